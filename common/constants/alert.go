@@ -0,0 +1,74 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package constants
+
+type AlertRuleType string
+
+// types of alert rule
+const (
+	AlertRuleBuiltIn AlertRuleType = "BuiltIn" // shipped with the platform, can only be enabled or disabled
+	AlertRuleCustom  AlertRuleType = "Custom"
+)
+
+type AlertRuleStatus string
+
+const (
+	AlertRuleEnabled  AlertRuleStatus = "Enabled"
+	AlertRuleDisabled AlertRuleStatus = "Disabled"
+)
+
+type AlertSeverity string
+
+// severity of alert, it is the same as the label 'level' of rules deployed by TiUP
+const (
+	AlertSeverityEmergency AlertSeverity = "emergency"
+	AlertSeverityCritical  AlertSeverity = "critical"
+	AlertSeverityWarning   AlertSeverity = "warning"
+)
+
+var AlertSeverityMap = map[string]AlertSeverity{
+	string(AlertSeverityEmergency): AlertSeverityEmergency,
+	string(AlertSeverityCritical):  AlertSeverityCritical,
+	string(AlertSeverityWarning):   AlertSeverityWarning,
+}
+
+type AlertState string
+
+// state of alert in history
+const (
+	AlertFiring   AlertState = "firing"
+	AlertResolved AlertState = "resolved"
+)
+
+type AlertSilenceStatus string
+
+const (
+	AlertSilenceActive  AlertSilenceStatus = "Active"
+	AlertSilenceExpired AlertSilenceStatus = "Expired"
+)
+
+const (
+	// AlertRuleFileName rule file of prometheus which contains enabled rules managed by the platform
+	AlertRuleFileName = "tiunimanager.rules.yml"
+	// AlertRuleGroupName rule group in the rule file
+	AlertRuleGroupName = "tiunimanager-alert-rules"
+	// AlertLabelCluster label added to every rule to identify the cluster
+	AlertLabelCluster = "cluster"
+	// AlertLabelName label of alert name
+	AlertLabelName = "alertname"
+	// AlertLabelSeverity label of alert severity
+	AlertLabelSeverity = "level"
+)
@@ -53,6 +53,9 @@ const (
 	ClusterMaintenanceSwitchoverRollback           ClusterMaintenanceStatus = "SwitchoverRollback"
 	ClusterMaintenanceModifyParameterAndRestarting ClusterMaintenanceStatus = "ModifyParameterRestarting"
 	ClusterMaintenanceTakeover                     ClusterMaintenanceStatus = "Takeover"
+	ClusterMaintenanceMigrating                    ClusterMaintenanceStatus = "Migrating"
+	ClusterMaintenanceRollingBack                  ClusterMaintenanceStatus = "RollingBack"
+	ClusterMaintenanceRecycled                     ClusterMaintenanceStatus = "Recycled" // kept while the cluster is in the recycle bin
 	ClusterMaintenanceNone                         ClusterMaintenanceStatus = ""
 )
 
@@ -62,11 +65,17 @@ const (
 	FlowBackupCluster                                   = "BackupCluster"
 	FlowRestoreNewCluster                               = "RestoreNewCluster"
 	FlowRestoreExistCluster                             = "RestoreExistCluster"
+	FlowVerifyBackup                                    = "VerifyBackup"
 	FlowModifyParameters                                = "ModifyParameters"
 	FlowExportData                                      = "ExportData"
 	FlowImportData                                      = "ImportData"
 	FlowRestartCluster                                  = "RestartCluster"
 	FlowStopCluster                                     = "StopCluster"
+	FlowStartInstance                                   = "StartInstance"
+	FlowStopInstance                                    = "StopInstance"
+	FlowRestartInstance                                 = "RestartInstance"
+	FlowReloadInstance                                  = "ReloadInstance"
+	FlowMigrateInstance                                 = "MigrateInstance"
 	FlowTakeoverCluster                                 = "TakeoverCluster"
 	FlowBuildLogConfig                                  = "BuildLogConfig"
 	FlowScaleOutCluster                                 = "ScaleOutCluster"
@@ -74,12 +83,56 @@ const (
 	FlowCloneCluster                                    = "CloneCluster"
 	FlowOnlineInPlaceUpgradeCluster                     = "OnlineInPlaceUpgradeCluster"
 	FlowOfflineInPlaceUpgradeCluster                    = "OfflineInPlaceUpgradeCluster"
+	FlowRollbackUpgradeCluster                          = "RollbackUpgradeCluster"
 	FlowMasterSlaveSwitchoverNormal                     = "SwitchoverNormal"
 	FlowMasterSlaveSwitchoverForce                      = "SwitchoverForce"
 	FlowMasterSlaveSwitchoverForceWithMasterUnavailable = "SwitchoverForceWithMasterUnavailable"
 	FlowMasterSlaveSwitchoverRollback                   = "SwitchoverRollback"
+	FlowRecycleCluster                                  = "RecycleCluster"
+	FlowApplyAlertRules                                 = "ApplyAlertRules"
+	FlowApplyAlertReceiver                              = "ApplyAlertReceiver"
 )
 
+type PendingOperationType string
+
+//Definition of disruptive operations which are restricted by the maintain window of cluster
+const (
+	PendingOperationRestartCluster      PendingOperationType = "RestartCluster"
+	PendingOperationUpgradeCluster      PendingOperationType = "UpgradeCluster"
+	PendingOperationUpdateParameters    PendingOperationType = "UpdateClusterParameters"
+	PendingOperationApplyParameterGroup PendingOperationType = "ApplyParameterGroup"
+	PendingOperationBackupCluster       PendingOperationType = "BackupCluster"
+	PendingOperationStopInstance        PendingOperationType = "StopInstance"
+	PendingOperationRestartInstance     PendingOperationType = "RestartInstance"
+	PendingOperationReloadInstance      PendingOperationType = "ReloadInstance"
+)
+
+type PendingOperationStatus string
+
+//Definition of pending operation status, operations wait until the maintain window opens
+const (
+	PendingOperationWaiting   PendingOperationStatus = "Waiting"
+	PendingOperationLaunching PendingOperationStatus = "Launching"
+	PendingOperationLaunched  PendingOperationStatus = "Launched"
+	PendingOperationFailed    PendingOperationStatus = "Failed"
+	PendingOperationCanceled  PendingOperationStatus = "Canceled"
+)
+
+type RecycledClusterStatus string
+
+//Definition of status of cluster in the recycle bin, resources of the cluster are held until it is purged
+const (
+	RecycledClusterRecycling RecycledClusterStatus = "Recycling" // the cluster is being stopped
+	RecycledClusterRecycled  RecycledClusterStatus = "Recycled"
+	RecycledClusterPurging   RecycledClusterStatus = "Purging" // the cluster is claimed to be purged
+	RecycledClusterRecovered RecycledClusterStatus = "Recovered"
+	RecycledClusterPurged    RecycledClusterStatus = "Purged"
+	RecycledClusterFailed    RecycledClusterStatus = "Failed"
+)
+
+// DefaultClusterRetentionHours default hours to keep a soft deleted cluster in the recycle bin
+const DefaultClusterRetentionHours = 72
+
 type ClusterInstanceRunningStatus string
 
 //Definition of cluster instance running status information
@@ -111,6 +164,24 @@ const (
 	ClusterBackupProcessing   ClusterBackupStatus = "Processing"
 	ClusterBackupFinished     ClusterBackupStatus = "Finished"
 	ClusterBackupFailed       ClusterBackupStatus = "Failed"
+	ClusterBackupPruneFailed  ClusterBackupStatus = "PruneFailed"
+)
+
+type BackupVerifyStatus string
+
+//Definition of backup verification status, empty means never verified
+const (
+	BackupVerifyProcessing BackupVerifyStatus = "Verifying"
+	BackupVerifyPassed     BackupVerifyStatus = "Passed"
+	BackupVerifyFailed     BackupVerifyStatus = "Failed"
+)
+
+type LogBackupStatus string
+
+//Definition of cluster log backup task status
+const (
+	LogBackupRunning LogBackupStatus = "Running"
+	LogBackupStopped LogBackupStatus = "Stopped"
 )
 
 type ClusterRelationType string
@@ -207,3 +278,21 @@ const (
 func DefaultResourceMode() string {
 	return ResourceModeSpecificZone
 }
+
+// TagSelectorOperator operator of a requirement in cluster tag selector
+type TagSelectorOperator string
+
+const (
+	TagOperatorEquals       TagSelectorOperator = "="
+	TagOperatorNotEquals    TagSelectorOperator = "!="
+	TagOperatorIn           TagSelectorOperator = "in"
+	TagOperatorNotIn        TagSelectorOperator = "notin"
+	TagOperatorExists       TagSelectorOperator = "exists"
+	TagOperatorDoesNotExist TagSelectorOperator = "!"
+)
+
+// TagKeyValueSeparator separate key and value of cluster tag, eg. team=dba
+const TagKeyValueSeparator = "="
+
+// TagMaxLength max length of key or value of cluster tag
+const TagMaxLength = 63
@@ -44,7 +44,9 @@ const (
 	MetricsClusterPreview               MetricsType = "cluster/preview"
 	MetricsClusterQuery                 MetricsType = "cluster/query"
 	MetricsClusterDetail                MetricsType = "cluster/detail"
+	MetricsClusterUpdateProfile         MetricsType = "cluster/update_profile"
 	MetricsClusterQueryMonitorAddress   MetricsType = "cluster/query_monitor_address"
+	MetricsClusterQueryMetrics          MetricsType = "cluster/query_metrics"
 	MetricsClusterQueryDashboardAddress MetricsType = "cluster/query_dashboard_address"
 	MetricsClusterQueryParameter        MetricsType = "cluster/query_parameter"
 	MetricsClusterModifyParameter       MetricsType = "cluster/modify_parameter"
@@ -53,6 +55,55 @@ const (
 	MetricsClusterUpgrade               MetricsType = "cluster/upgrade"
 	MetricsClusterUpgradePath           MetricsType = "cluster/upgrade_path"
 	MetricsClusterUpgradeDiff           MetricsType = "cluster/upgrade_diff"
+	MetricsClusterUpgradeCheck          MetricsType = "cluster/upgrade_check"
+	MetricsClusterUpgradeRollback       MetricsType = "cluster/upgrade_rollback"
+	MetricsUpgradeCampaignCreate        MetricsType = "upgrade_campaign/create"
+	MetricsUpgradeCampaignQuery         MetricsType = "upgrade_campaign/query"
+	MetricsUpgradeCampaignDetail        MetricsType = "upgrade_campaign/detail"
+	MetricsUpgradeCampaignCancel        MetricsType = "upgrade_campaign/cancel"
+	MetricsClusterPendingOperations     MetricsType = "cluster/pending_operations"
+	MetricsClusterCancelPendingOp       MetricsType = "cluster/cancel_pending_operation"
+	MetricsClusterAddTags               MetricsType = "cluster/add_tags"
+	MetricsClusterRemoveTags            MetricsType = "cluster/remove_tags"
+	MetricsClusterDeletionProtection    MetricsType = "cluster/deletion_protection"
+	MetricsRecycledClusterQuery         MetricsType = "recycled_cluster/query"
+	MetricsRecycledClusterRecover       MetricsType = "recycled_cluster/recover"
+	MetricsInstanceStart                MetricsType = "cluster/instance/start"
+	MetricsInstanceStop                 MetricsType = "cluster/instance/stop"
+	MetricsInstanceRestart              MetricsType = "cluster/instance/restart"
+	MetricsInstanceReload               MetricsType = "cluster/instance/reload"
+	MetricsInstanceMigrate              MetricsType = "cluster/instance/migrate"
+	MetricsAlertQueryFiring             MetricsType = "alert/query_firing"
+	MetricsAlertQueryHistory            MetricsType = "alert/query_history"
+	MetricsAlertRuleQuery               MetricsType = "alert/rule/query"
+	MetricsAlertRuleCreate              MetricsType = "alert/rule/create"
+	MetricsAlertRuleUpdate              MetricsType = "alert/rule/update"
+	MetricsAlertRuleSetEnabled          MetricsType = "alert/rule/set_enabled"
+	MetricsAlertRuleDelete              MetricsType = "alert/rule/delete"
+	MetricsAlertSilenceCreate           MetricsType = "alert/silence/create"
+	MetricsAlertSilenceQuery            MetricsType = "alert/silence/query"
+	MetricsAlertSilenceDelete           MetricsType = "alert/silence/delete"
+	MetricsAlertReceiverConfigure       MetricsType = "alert/receiver/configure"
+	MetricsAlertReceive                 MetricsType = "alert/receive"
+	MetricsNotificationChannelCreate    MetricsType = "notification/channel/create"
+	MetricsNotificationChannelUpdate    MetricsType = "notification/channel/update"
+	MetricsNotificationChannelDelete    MetricsType = "notification/channel/delete"
+	MetricsNotificationChannelQuery     MetricsType = "notification/channel/query"
+	MetricsNotificationChannelTest      MetricsType = "notification/channel/test"
+	MetricsNotificationRouteCreate      MetricsType = "notification/route/create"
+	MetricsNotificationRouteUpdate      MetricsType = "notification/route/update"
+	MetricsNotificationRouteDelete      MetricsType = "notification/route/delete"
+	MetricsNotificationRouteQuery       MetricsType = "notification/route/query"
+	MetricsNotificationDeliveryQuery    MetricsType = "notification/delivery/query"
+	MetricsPerformanceTopSQLQuery       MetricsType = "performance/top_sql/query"
+	MetricsPerformancePlanQuery         MetricsType = "performance/plan/query"
+	MetricsPerformanceSlowQueryQuery    MetricsType = "performance/slow_query/query"
+	MetricsPerformanceSnapshotCreate    MetricsType = "performance/snapshot/create"
+	MetricsPerformanceSnapshotQuery     MetricsType = "performance/snapshot/query"
+	MetricsPerformanceSnapshotDetail    MetricsType = "performance/snapshot/detail"
+	MetricsPerformanceSnapshotDelete    MetricsType = "performance/snapshot/delete"
+	MetricsPerformanceSnapshotCompare   MetricsType = "performance/snapshot/compare"
+	MetricsClusterHealthEventQuery      MetricsType = "cluster/health_event/query"
 
 	MetricsMetadataDeletePhysically MetricsType = "metadata/delete"
 
@@ -72,6 +123,12 @@ const (
 	MetricsBackupQuery          MetricsType = "backup/query"
 	MetricsBackupQueryStrategy  MetricsType = "backup/query_strategy"
 	MetricsBackupModifyStrategy MetricsType = "backup/modify_strategy"
+	MetricsBackupVerify         MetricsType = "backup/verify"
+	MetricsBackupPrune          MetricsType = "backup/prune"
+	MetricsLogBackupStart       MetricsType = "backup/log/start"
+	MetricsLogBackupStop        MetricsType = "backup/log/stop"
+	MetricsLogBackupQuery       MetricsType = "backup/log/query"
+	MetricsLogBackupTruncate    MetricsType = "backup/log/truncate"
 
 	// MetricsDataExport define data export & import metrics
 	MetricsDataExport             MetricsType = "data/export"
@@ -129,10 +186,13 @@ const (
 	MetricsTenantUpdateOnBoardingStatus MetricsType = "tenant/update_on_boarding_status"
 
 	// MetricsWorkFlowQuery define workflow metrics
-	MetricsWorkFlowQuery  MetricsType = "workflow/query"
-	MetricsWorkFlowDetail MetricsType = "workflow/detail"
-	MetricsWorkFlowStart  MetricsType = "workflow/start"
-	MetricsWorkFlowStop   MetricsType = "workflow/stop"
+	MetricsWorkFlowQuery   MetricsType = "workflow/query"
+	MetricsWorkFlowDetail  MetricsType = "workflow/detail"
+	MetricsWorkFlowStart   MetricsType = "workflow/start"
+	MetricsWorkFlowStop    MetricsType = "workflow/stop"
+	MetricsWorkFlowRetry   MetricsType = "workflow/retry"
+	MetricsWorkFlowResume  MetricsType = "workflow/resume"
+	MetricsWorkFlowAbandon MetricsType = "workflow/abandon"
 
 	// MetricsResourceQueryHierarchy define resource metrics
 	MetricsResourceQueryHierarchy           MetricsType = "resource/query_hierarchy"
@@ -147,6 +207,9 @@ const (
 	MetricsResourceCreateDisks              MetricsType = "resource/create_disks"
 	MetricsResourceDeleteDisks              MetricsType = "resource/delete_disks"
 	MetricsResourceUpdateDisk               MetricsType = "resource/update_disk"
+	MetricsResourceDrainHost                MetricsType = "resource/drain_host"
+	MetricsResourceQueryDrainHost           MetricsType = "resource/query_drain_host"
+	MetricsResourceReinstateHost            MetricsType = "resource/reinstate_host"
 
 	// MetricsProductUpdate define product metrics
 	MetricsProductUpdate         MetricsType = "product/update_products"
@@ -173,12 +236,57 @@ var EMMetrics = []MetricsType{
 	MetricsClusterPreview,
 	MetricsClusterQuery,
 	MetricsClusterDetail,
+	MetricsClusterUpdateProfile,
 	MetricsClusterQueryMonitorAddress,
+	MetricsClusterQueryMetrics,
 	MetricsClusterQueryDashboardAddress,
 	MetricsClusterQueryParameter,
 	MetricsClusterModifyParameter,
 	MetricsClusterInspectParameter,
 	MetricsClusterQueryLogParameter,
+	MetricsClusterPendingOperations,
+	MetricsClusterCancelPendingOp,
+	MetricsClusterAddTags,
+	MetricsClusterRemoveTags,
+	MetricsClusterDeletionProtection,
+	MetricsRecycledClusterQuery,
+	MetricsRecycledClusterRecover,
+	MetricsInstanceStart,
+	MetricsInstanceStop,
+	MetricsInstanceRestart,
+	MetricsInstanceReload,
+	MetricsInstanceMigrate,
+	MetricsAlertQueryFiring,
+	MetricsAlertQueryHistory,
+	MetricsAlertRuleQuery,
+	MetricsAlertRuleCreate,
+	MetricsAlertRuleUpdate,
+	MetricsAlertRuleSetEnabled,
+	MetricsAlertRuleDelete,
+	MetricsAlertSilenceCreate,
+	MetricsAlertSilenceQuery,
+	MetricsAlertSilenceDelete,
+	MetricsAlertReceiverConfigure,
+	MetricsAlertReceive,
+	MetricsNotificationChannelCreate,
+	MetricsNotificationChannelUpdate,
+	MetricsNotificationChannelDelete,
+	MetricsNotificationChannelQuery,
+	MetricsNotificationChannelTest,
+	MetricsNotificationRouteCreate,
+	MetricsNotificationRouteUpdate,
+	MetricsNotificationRouteDelete,
+	MetricsNotificationRouteQuery,
+	MetricsNotificationDeliveryQuery,
+	MetricsPerformanceTopSQLQuery,
+	MetricsPerformancePlanQuery,
+	MetricsPerformanceSlowQueryQuery,
+	MetricsPerformanceSnapshotCreate,
+	MetricsPerformanceSnapshotQuery,
+	MetricsPerformanceSnapshotDetail,
+	MetricsPerformanceSnapshotDelete,
+	MetricsPerformanceSnapshotCompare,
+	MetricsClusterHealthEventQuery,
 	MetricsMetadataDeletePhysically,
 	// MetricsBackupCreate define backup metrics
 	MetricsBackupCreate,
@@ -186,6 +294,12 @@ var EMMetrics = []MetricsType{
 	MetricsBackupQuery,
 	MetricsBackupQueryStrategy,
 	MetricsBackupModifyStrategy,
+	MetricsBackupVerify,
+	MetricsBackupPrune,
+	MetricsLogBackupStart,
+	MetricsLogBackupStop,
+	MetricsLogBackupQuery,
+	MetricsLogBackupTruncate,
 
 	// MetricsDataExport define data export & import metrics
 	MetricsDataExport,
@@ -234,6 +348,9 @@ var EMMetrics = []MetricsType{
 	MetricsResourceCreateDisks,
 	MetricsResourceDeleteDisks,
 	MetricsResourceUpdateDisk,
+	MetricsResourceDrainHost,
+	MetricsResourceQueryDrainHost,
+	MetricsResourceReinstateHost,
 
 	// define product metrics
 	MetricsProductUpdate,
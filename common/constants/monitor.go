@@ -0,0 +1,41 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package constants
+
+type ClusterMetricName string
+
+// named metrics of cluster which are queried from prometheus of the cluster
+const (
+	ClusterMetricQPS             ClusterMetricName = "qps"              // queries per second of TiDB by statement type
+	ClusterMetricLatencyP999     ClusterMetricName = "latency_p999"     // 99.9th percentile of TiDB query duration in seconds
+	ClusterMetricLatencyP99      ClusterMetricName = "latency_p99"      // 99th percentile of TiDB query duration in seconds
+	ClusterMetricLatencyP95      ClusterMetricName = "latency_p95"      // 95th percentile of TiDB query duration in seconds
+	ClusterMetricLatencyP80      ClusterMetricName = "latency_p80"      // 80th percentile of TiDB query duration in seconds
+	ClusterMetricStorageUsed     ClusterMetricName = "storage_used"     // used bytes of TiKV stores by instance
+	ClusterMetricStorageCapacity ClusterMetricName = "storage_capacity" // capacity bytes of TiKV stores by instance
+	ClusterMetricCPUByComponent  ClusterMetricName = "cpu_by_component" // cpu cores used by each component
+)
+
+const (
+	// MetricsDefaultRange time range of metrics query if start time is not specified
+	MetricsDefaultRange = 3600
+	// MetricsMinStep min step of metrics query in seconds, it is the scrape interval of prometheus deployed by TiUP
+	MetricsMinStep = 15
+	// MetricsDefaultPoints points of each series if step is not specified
+	MetricsDefaultPoints = 240
+	// MetricsMaxPoints max points of each series, which is limited by prometheus
+	MetricsMaxPoints = 11000
+)
@@ -0,0 +1,49 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package constants
+
+type NotificationChannelType string
+
+// types of notification channel
+const (
+	NotificationChannelWebhook NotificationChannelType = "Webhook" // post alerts in json to an http endpoint
+	NotificationChannelEmail   NotificationChannelType = "Email"   // send alerts by SMTP
+	NotificationChannelSlack   NotificationChannelType = "Slack"   // post alerts as text to a Slack-style incoming webhook
+)
+
+var NotificationChannelTypeMap = map[string]NotificationChannelType{
+	string(NotificationChannelWebhook): NotificationChannelWebhook,
+	string(NotificationChannelEmail):   NotificationChannelEmail,
+	string(NotificationChannelSlack):   NotificationChannelSlack,
+}
+
+type NotificationDeliveryStatus string
+
+const (
+	NotificationDeliveryPending NotificationDeliveryStatus = "Pending"
+	NotificationDeliverySending NotificationDeliveryStatus = "Sending"
+	NotificationDeliverySuccess NotificationDeliveryStatus = "Success"
+	NotificationDeliveryFailed  NotificationDeliveryStatus = "Failed"
+)
+
+const (
+	// AlertReceiverName receiver in alertmanager config of clusters which sends alerts to the platform
+	AlertReceiverName = "tiunimanager"
+	// AlertReceiverApiPath path of api which receives alerts from alertmanager, followed by cluster id
+	AlertReceiverApiPath = "/api/v1/alerts/receiver/"
+	// AlertManagerConfigFileName config file of alertmanager in deploy dir
+	AlertManagerConfigFileName = "alertmanager.yml"
+)
@@ -0,0 +1,46 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package constants
+
+type TopSQLOrderBy string
+
+// order of top SQL
+const (
+	TopSQLOrderByLatency     TopSQLOrderBy = "latency"      // total latency of all executions
+	TopSQLOrderByExecutions  TopSQLOrderBy = "executions"   // count of executions
+	TopSQLOrderByScannedKeys TopSQLOrderBy = "scanned_keys" // total keys scanned by all executions
+)
+
+type PerformanceSnapshotType string
+
+// type of performance snapshot
+const (
+	PerformanceSnapshotPeriodic PerformanceSnapshotType = "Periodic"
+	PerformanceSnapshotManual   PerformanceSnapshotType = "Manual"
+)
+
+const (
+	// TopSQLDefaultRange time range of top SQL and slow queries if start time is not specified
+	TopSQLDefaultRange = 1800
+	// TopSQLDefaultLimit count of top SQL if limit is not specified
+	TopSQLDefaultLimit = 20
+	// TopSQLMaxLimit max count of top SQL
+	TopSQLMaxLimit = 200
+	// PerformanceSnapshotInterval interval in seconds of periodic performance snapshots, each snapshot covers one interval
+	PerformanceSnapshotInterval = 3600
+	// DefaultPerformanceSnapshotRetentionDays default days to keep periodic performance snapshots
+	DefaultPerformanceSnapshotRetentionDays = 7
+)
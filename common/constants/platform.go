@@ -49,6 +49,14 @@ const (
 
 	ConfigKeyDefaultTiUPHome string = "default_tiup_home"
 	ConfigKeyDefaultEMHome   string = "em_tiup_home"
+
+	ConfigKeyClusterRetentionHours string = "config_cluster_retention_hours"
+
+	ConfigKeyAlertReceiverAddress string = "config_alert_receiver_address"
+
+	ConfigKeyPerformanceSnapshotRetentionDays string = "config_performance_snapshot_retention_days"
+
+	ConfigKeyHealthEventRetentionDays string = "config_health_event_retention_days"
 )
 
 type SystemState string
@@ -59,13 +59,35 @@ const (
 	RbacResourceWorkflow  RbacResource = "WORKFLOW"
 )
 
+// RbacInstanceScope Definition scope of resource instance permission, only cluster supports it now
+type RbacInstanceScope string
+
+var RbacInstanceScopeMap = map[string]RbacInstanceScope{
+	string(RbacInstanceScopeID):     RbacInstanceScopeID,
+	string(RbacInstanceScopeTag):    RbacInstanceScopeTag,
+	string(RbacInstanceScopeTenant): RbacInstanceScopeTenant,
+}
+
+const (
+	RbacInstanceScopeID     RbacInstanceScope = "id"
+	RbacInstanceScopeTag    RbacInstanceScope = "tag"
+	RbacInstanceScopeTenant RbacInstanceScope = "tenant"
+)
+
+// RbacInstanceSeparator separator of resource, instance scope and instance pattern in rbac policy object, eg. CLUSTER/tag/team=dba*
+const RbacInstanceSeparator = "/"
+
 // RbacRole Definition rbac role enum
 type RbacRole string
 
 var RbacRoleMap = map[string]RbacRole{
-	string(RbacRoleAdmin): RbacRoleAdmin,
+	string(RbacRoleAdmin):    RbacRoleAdmin,
+	string(RbacRoleOperator): RbacRoleOperator,
+	string(RbacRoleReadOnly): RbacRoleReadOnly,
 }
 
 const (
-	RbacRoleAdmin RbacRole = "RBAC_ROLE_ADMIN"
+	RbacRoleAdmin    RbacRole = "RBAC_ROLE_ADMIN"
+	RbacRoleOperator RbacRole = "RBAC_ROLE_OPERATOR"
+	RbacRoleReadOnly RbacRole = "RBAC_ROLE_READONLY"
 )
@@ -82,6 +82,10 @@ const (
 	HostFailed   HostStatus = "Failed"
 	HostDeleting HostStatus = "Deleting"
 	HostDeleted  HostStatus = "Deleted"
+	// HostDraining instances are being evacuated from the host, it is not allocatable
+	HostDraining HostStatus = "Draining"
+	// HostMaintenance the host is drained and can be maintained, reinstate it to bring it online
+	HostMaintenance HostStatus = "Maintenance"
 )
 
 func (s HostStatus) IsValidStatus() bool {
@@ -90,9 +94,22 @@ func (s HostStatus) IsValidStatus() bool {
 		s == HostInit ||
 		s == HostFailed ||
 		s == HostDeleting ||
-		s == HostDeleted)
+		s == HostDeleted ||
+		s == HostDraining ||
+		s == HostMaintenance)
 }
 
+type DrainInstanceStatus string
+
+//Definition of instance status while draining the host
+const (
+	DrainInstanceWaiting   DrainInstanceStatus = "Waiting"
+	DrainInstanceMigrating DrainInstanceStatus = "Migrating"
+	DrainInstanceMigrated  DrainInstanceStatus = "Migrated"
+	DrainInstanceFailed    DrainInstanceStatus = "Failed"
+	DrainInstanceSkipped   DrainInstanceStatus = "Skipped"
+)
+
 type HostLoadStatus string
 
 //Definition of host load status
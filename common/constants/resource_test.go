@@ -84,6 +84,8 @@ func Test_ValidStatus(t *testing.T) {
 		{"Test_Init", HostInit, want{true}},
 		{"Test_Failed", HostFailed, want{true}},
 		{"Test_Deleting", HostDeleting, want{true}},
+		{"Test_Draining", HostDraining, want{true}},
+		{"Test_Maintenance", HostMaintenance, want{true}},
 		{"Test_OtherStatus", HostWhatever, want{false}},
 	}
 	for _, tt := range tests {
@@ -34,3 +34,34 @@ const (
 	UpgradeWayOffline    UpgradeWayStr = "offline"
 	UpgradeWayOnline     UpgradeWayStr = "online"
 )
+
+type UpgradeCheckCategory string
+
+// categories of pre-upgrade checks
+const (
+	UpgradeCheckBinary   UpgradeCheckCategory = "Binary"
+	UpgradeCheckDuration UpgradeCheckCategory = "Duration"
+	UpgradeCheckConfig   UpgradeCheckCategory = "Config"
+	UpgradeCheckHealth   UpgradeCheckCategory = "Health"
+)
+
+type UpgradeCampaignStatus string
+
+// status of upgrade campaign
+const (
+	UpgradeCampaignRunning   UpgradeCampaignStatus = "Running"
+	UpgradeCampaignHalted    UpgradeCampaignStatus = "Halted" // stopped automatically as the failure rate exceeds the threshold
+	UpgradeCampaignCanceled  UpgradeCampaignStatus = "Canceled"
+	UpgradeCampaignCompleted UpgradeCampaignStatus = "Completed"
+)
+
+type UpgradeCampaignClusterStatus string
+
+// status of cluster in upgrade campaign
+const (
+	CampaignClusterWaiting   UpgradeCampaignClusterStatus = "Waiting" // waiting for its wave or maintain window
+	CampaignClusterUpgrading UpgradeCampaignClusterStatus = "Upgrading"
+	CampaignClusterSucceeded UpgradeCampaignClusterStatus = "Succeeded"
+	CampaignClusterFailed    UpgradeCampaignClusterStatus = "Failed"
+	CampaignClusterSkipped   UpgradeCampaignClusterStatus = "Skipped" // not upgraded as campaign is stopped, or cluster can not be upgraded
+)
@@ -55,6 +55,14 @@ const (
 	TIUNIMANAGER_TAKEOVER_SFTP_ERROR            EM_ERROR_CODE = 20110
 	TIUNIMANAGER_CLUSTER_GET_CLUSTER_PORT_ERROR EM_ERROR_CODE = 20113
 	TIUNIMANAGER_CLUSTER_MAINTENANCE_CONFLICT   EM_ERROR_CODE = 20114
+	TIUNIMANAGER_MAINTAIN_WINDOW_INVALID        EM_ERROR_CODE = 20115
+	TIUNIMANAGER_OUT_OF_MAINTAIN_WINDOW         EM_ERROR_CODE = 20116
+	TIUNIMANAGER_PENDING_OPERATION_NOT_FOUND    EM_ERROR_CODE = 20117
+	TIUNIMANAGER_PENDING_OPERATION_FAILED       EM_ERROR_CODE = 20118
+	TIUNIMANAGER_MIGRATE_INSTANCE_INVALID       EM_ERROR_CODE = 20119
+	TIUNIMANAGER_MIGRATE_INSTANCE_FAILED        EM_ERROR_CODE = 20120
+	TIUNIMANAGER_CLUSTER_DELETION_PROTECTED     EM_ERROR_CODE = 20121
+	TIUNIMANAGER_RECYCLED_CLUSTER_NOT_FOUND     EM_ERROR_CODE = 20122
 
 	// backup && restore
 	TIUNIMANAGER_BACKUP_SYSTEM_CONFIG_NOT_FOUND EM_ERROR_CODE = 20600
@@ -69,11 +77,21 @@ const (
 	TIUNIMANAGER_BACKUP_PATH_CREATE_FAILED      EM_ERROR_CODE = 20609
 	TIUNIMANAGER_BACKUP_RECORD_INVALID          EM_ERROR_CODE = 20610
 	TIUNIMANAGER_BACKUP_RECORD_CANCEL_FAILED    EM_ERROR_CODE = 20611
+	TIUNIMANAGER_LOG_BACKUP_START_FAILED        EM_ERROR_CODE = 20612
+	TIUNIMANAGER_LOG_BACKUP_STOP_FAILED         EM_ERROR_CODE = 20613
+	TIUNIMANAGER_LOG_BACKUP_QUERY_FAILED        EM_ERROR_CODE = 20614
+	TIUNIMANAGER_LOG_BACKUP_TRUNCATE_FAILED     EM_ERROR_CODE = 20615
+	TIUNIMANAGER_RESTORE_POINT_INVALID          EM_ERROR_CODE = 20616
+	TIUNIMANAGER_BACKUP_VERIFY_FAILED           EM_ERROR_CODE = 20617
 
 	// upgrade
-	TIUNIMANAGER_UPGRADE_QUERY_PATH_FAILED EM_ERROR_CODE = 21100
-	TIUNIMANAGER_UPGRADE_REGION_UNHEALTHY  EM_ERROR_CODE = 21104
-	TIUNIMANAGER_UPGRADE_VERSION_INCORRECT EM_ERROR_CODE = 21105
+	TIUNIMANAGER_UPGRADE_QUERY_PATH_FAILED  EM_ERROR_CODE = 21100
+	TIUNIMANAGER_UPGRADE_REGION_UNHEALTHY   EM_ERROR_CODE = 21104
+	TIUNIMANAGER_UPGRADE_VERSION_INCORRECT  EM_ERROR_CODE = 21105
+	TIUNIMANAGER_UPGRADE_PRECHECK_FAILED    EM_ERROR_CODE = 21106
+	TIUNIMANAGER_UPGRADE_ROLLBACK_REFUSED   EM_ERROR_CODE = 21107
+	TIUNIMANAGER_UPGRADE_ROLLBACK_FAILED    EM_ERROR_CODE = 21108
+	TIUNIMANAGER_UPGRADE_CAMPAIGN_NOT_FOUND EM_ERROR_CODE = 21109
 
 	// switchover
 	TIUNIMANAGER_MASTER_SLAVE_SWITCHOVER_NOT_FOUND               EM_ERROR_CODE = 21000
@@ -91,6 +109,11 @@ const (
 	TIUNIMANAGER_WORKFLOW_NODE_POLLING_TIME_OUT EM_ERROR_CODE = 40105
 	TIUNIMANAGER_WORKFLOW_STOP_FAILED           EM_ERROR_CODE = 40106
 	TIUNIMANAGER_WORKFLOW_CANCEL_FAILED         EM_ERROR_CODE = 40107
+	TIUNIMANAGER_WORKFLOW_RESUME_FAILED         EM_ERROR_CODE = 40108
+	TIUNIMANAGER_WORKFLOW_NODE_TIMEOUT          EM_ERROR_CODE = 40109
+	TIUNIMANAGER_WORKFLOW_OWNERSHIP_LOST        EM_ERROR_CODE = 40110
+	TIUNIMANAGER_WORKFLOW_ABANDON_FAILED        EM_ERROR_CODE = 40111
+	TIUNIMANAGER_WORKFLOW_OWNERSHIP_UNAVAILABLE EM_ERROR_CODE = 40112
 
 	// import && export
 	TIUNIMANAGER_TRANSPORT_SYSTEM_CONFIG_NOT_FOUND EM_ERROR_CODE = 60100
@@ -122,6 +145,7 @@ const (
 	TIUNIMANAGER_RBAC_ROLE_QUERY_FAILED        EM_ERROR_CODE = 70656
 	TIUNIMANAGER_RBAC_ROLE_BIND_FAILED         EM_ERROR_CODE = 70657
 	TIUNIMANAGER_RBAC_ROLE_UNBIND_FAILED       EM_ERROR_CODE = 70658
+	TIUNIMANAGER_RBAC_PERMISSION_DENIED        EM_ERROR_CODE = 70659
 
 	// dashboard && monitor
 	TIUNIMANAGER_DASHBOARD_NOT_FOUND EM_ERROR_CODE = 80100
@@ -172,6 +196,8 @@ const (
 	TIUNIMANAGER_RESOURCE_DISK_STILL_INUSED         EM_ERROR_CODE = 30144
 	TIUNIMANAGER_RESOURCE_DISK_ALREADY_EXIST        EM_ERROR_CODE = 30145
 	TIUNIMANAGER_RESOURCE_BAD_INSTANCE_EXIST        EM_ERROR_CODE = 30146
+	TIUNIMANAGER_RESOURCE_HOST_STATUS_CONFLICT      EM_ERROR_CODE = 30147
+	TIUNIMANAGER_RESOURCE_DRAIN_HOST_ERROR          EM_ERROR_CODE = 30148
 
 	TIUNIMANAGER_MONITOR_NOT_FOUND EM_ERROR_CODE = 614
 
@@ -249,6 +275,35 @@ const (
 	TIUNIMANAGER_LOG_QUERY_FAILED EM_ERROR_CODE = 80300
 	TIUNIMANAGER_LOG_TIME_AFTER   EM_ERROR_CODE = 80301
 
+	TIUNIMANAGER_ALERT_RULE_NOT_FOUND        EM_ERROR_CODE = 80400
+	TIUNIMANAGER_ALERT_RULE_INVALID          EM_ERROR_CODE = 80401
+	TIUNIMANAGER_ALERT_RULE_DUPLICATED       EM_ERROR_CODE = 80402
+	TIUNIMANAGER_ALERT_RULE_BUILTIN_READONLY EM_ERROR_CODE = 80403
+	TIUNIMANAGER_ALERT_MANAGER_UNAVAILABLE   EM_ERROR_CODE = 80404
+	TIUNIMANAGER_ALERT_SILENCE_NOT_FOUND     EM_ERROR_CODE = 80405
+	TIUNIMANAGER_ALERT_SILENCE_INVALID       EM_ERROR_CODE = 80406
+	TIUNIMANAGER_ALERT_RULE_NOT_LOADED       EM_ERROR_CODE = 80407
+
+	TIUNIMANAGER_NOTIFICATION_CHANNEL_NOT_FOUND EM_ERROR_CODE = 80500
+	TIUNIMANAGER_NOTIFICATION_CHANNEL_INVALID   EM_ERROR_CODE = 80501
+	TIUNIMANAGER_NOTIFICATION_CHANNEL_IN_USE    EM_ERROR_CODE = 80502
+	TIUNIMANAGER_NOTIFICATION_ROUTE_NOT_FOUND   EM_ERROR_CODE = 80503
+	TIUNIMANAGER_NOTIFICATION_ROUTE_INVALID     EM_ERROR_CODE = 80504
+	TIUNIMANAGER_NOTIFICATION_SEND_FAILED       EM_ERROR_CODE = 80505
+	TIUNIMANAGER_ALERT_RECEIVER_NOT_CONFIGURED  EM_ERROR_CODE = 80506
+	TIUNIMANAGER_ALERT_RECEIVER_UNAUTHORIZED    EM_ERROR_CODE = 80507
+
+	TIUNIMANAGER_METRICS_QUERY_INVALID  EM_ERROR_CODE = 80600
+	TIUNIMANAGER_METRICS_QUERY_FAILED   EM_ERROR_CODE = 80601
+	TIUNIMANAGER_PROMETHEUS_UNAVAILABLE EM_ERROR_CODE = 80602
+
+	TIUNIMANAGER_PERFORMANCE_QUERY_INVALID      EM_ERROR_CODE = 80700
+	TIUNIMANAGER_PERFORMANCE_QUERY_FAILED       EM_ERROR_CODE = 80701
+	TIUNIMANAGER_PERFORMANCE_SNAPSHOT_NOT_FOUND EM_ERROR_CODE = 80702
+	TIUNIMANAGER_PERFORMANCE_SNAPSHOT_EXISTED   EM_ERROR_CODE = 80703
+
+	TIUNIMANAGER_HEALTH_PROBE_FAILED EM_ERROR_CODE = 80800
+
 	QueryReportsScanRowError EM_ERROR_CODE = 90001
 	CheckReportNotExist      EM_ERROR_CODE = 90002
 )
@@ -297,6 +352,7 @@ var explanationContainer = map[EM_ERROR_CODE]ErrorCodeExplanation{
 	TIUNIMANAGER_RBAC_ROLE_DELETE_FAILED:       {"rbac role delete failed", 500},
 	TIUNIMANAGER_RBAC_ROLE_BIND_FAILED:         {"rbac role bind user failed", 500},
 	TIUNIMANAGER_RBAC_ROLE_UNBIND_FAILED:       {"rbac role unbind user failed", 500},
+	TIUNIMANAGER_RBAC_PERMISSION_DENIED:        {"rbac permission denied", 403},
 
 	TIUNIMANAGER_CLUSTER_SERVER_CALL_ERROR: {"call cluster-Server failed", 500},
 	TIUNIMANAGER_SYSTEM_MISSING_DATA:       {"missing system data", 500},
@@ -319,6 +375,14 @@ var explanationContainer = map[EM_ERROR_CODE]ErrorCodeExplanation{
 	TIUNIMANAGER_CLUSTER_RESOURCE_NOT_ENOUGH:  {"host resource is not enough", 500},
 	TIUNIMANAGER_CLUSTER_MAINTENANCE_CONFLICT: {"maintenance status conflict", 409},
 	TIUNIMANAGER_CLUSTER_METADATA_BROKEN:      {"cluster meta is incomplete", 400},
+	TIUNIMANAGER_MAINTAIN_WINDOW_INVALID:      {"invalid maintain window", 400},
+	TIUNIMANAGER_OUT_OF_MAINTAIN_WINDOW:       {"out of the maintain window of cluster", 409},
+	TIUNIMANAGER_PENDING_OPERATION_NOT_FOUND:  {"pending operation not found", 404},
+	TIUNIMANAGER_PENDING_OPERATION_FAILED:     {"pending operation failed", 500},
+	TIUNIMANAGER_MIGRATE_INSTANCE_INVALID:     {"instance can not be migrated", 400},
+	TIUNIMANAGER_MIGRATE_INSTANCE_FAILED:      {"migrate instance failed", 500},
+	TIUNIMANAGER_CLUSTER_DELETION_PROTECTED:   {"cluster is protected from deletion", 409},
+	TIUNIMANAGER_RECYCLED_CLUSTER_NOT_FOUND:   {"recycled cluster not found", 404},
 
 	// cluster management
 	TIUNIMANAGER_TAKEOVER_SSH_CONNECT_ERROR: {"ssh connect failed", 500},
@@ -334,6 +398,11 @@ var explanationContainer = map[EM_ERROR_CODE]ErrorCodeExplanation{
 	TIUNIMANAGER_WORKFLOW_START_FAILED:          {"workflow start failed", 500},
 	TIUNIMANAGER_WORKFLOW_DEFINE_NOT_FOUND:      {"workflow define not found", 404},
 	TIUNIMANAGER_WORKFLOW_NODE_POLLING_TIME_OUT: {"workflow node polling time out", 500},
+	TIUNIMANAGER_WORKFLOW_RESUME_FAILED:         {"workflow resume failed", 400},
+	TIUNIMANAGER_WORKFLOW_NODE_TIMEOUT:          {"workflow node execution time out", 500},
+	TIUNIMANAGER_WORKFLOW_OWNERSHIP_LOST:        {"workflow is owned by another replica", 409},
+	TIUNIMANAGER_WORKFLOW_ABANDON_FAILED:        {"workflow abandon failed", 400},
+	TIUNIMANAGER_WORKFLOW_OWNERSHIP_UNAVAILABLE: {"workflow ownership is unavailable", 503},
 
 	// import && export
 	TIUNIMANAGER_TRANSPORT_SYSTEM_CONFIG_NOT_FOUND: {"data transport system config not found", 404},
@@ -362,6 +431,18 @@ var explanationContainer = map[EM_ERROR_CODE]ErrorCodeExplanation{
 	TIUNIMANAGER_BACKUP_PATH_CREATE_FAILED:      {"backup filepath create failed", 500},
 	TIUNIMANAGER_BACKUP_RECORD_INVALID:          {"backup record invalid", 400},
 	TIUNIMANAGER_BACKUP_RECORD_CANCEL_FAILED:    {"cancel backup record failed", 500},
+	TIUNIMANAGER_LOG_BACKUP_START_FAILED:        {"start log backup failed", 500},
+	TIUNIMANAGER_LOG_BACKUP_STOP_FAILED:         {"stop log backup failed", 500},
+	TIUNIMANAGER_LOG_BACKUP_QUERY_FAILED:        {"query log backup failed", 500},
+	TIUNIMANAGER_LOG_BACKUP_TRUNCATE_FAILED:     {"truncate log backup failed", 500},
+	TIUNIMANAGER_RESTORE_POINT_INVALID:          {"restore point is not covered by backups", 400},
+	TIUNIMANAGER_BACKUP_VERIFY_FAILED:           {"verify backup failed", 500},
+
+	// upgrade
+	TIUNIMANAGER_UPGRADE_PRECHECK_FAILED:    {"upgrade pre-check failed", 409},
+	TIUNIMANAGER_UPGRADE_ROLLBACK_REFUSED:   {"upgrade can not be rolled back", 409},
+	TIUNIMANAGER_UPGRADE_ROLLBACK_FAILED:    {"roll back upgrade failed", 500},
+	TIUNIMANAGER_UPGRADE_CAMPAIGN_NOT_FOUND: {"upgrade campaign not found", 404},
 
 	// resource
 	TIUNIMANAGER_RESOURCE_HOST_NOT_FOUND:            {"host not found", 500},
@@ -404,6 +485,8 @@ var explanationContainer = map[EM_ERROR_CODE]ErrorCodeExplanation{
 	TIUNIMANAGER_RESOURCE_DISK_STILL_INUSED:         {"disk is still in used", 409},
 	TIUNIMANAGER_RESOURCE_DISK_ALREADY_EXIST:        {"disk is already existed", 409},
 	TIUNIMANAGER_RESOURCE_BAD_INSTANCE_EXIST:        {"already existed a instance with bad status", 500},
+	TIUNIMANAGER_RESOURCE_HOST_STATUS_CONFLICT:      {"host status does not allow the operation", 409},
+	TIUNIMANAGER_RESOURCE_DRAIN_HOST_ERROR:          {"drain host failed", 500},
 
 	// param group & cluster param
 	TIUNIMANAGER_DEFAULT_PARAM_GROUP_NOT_DEL:                 {"Not allow to deleted the default parameter group", 409},
@@ -449,6 +532,35 @@ var explanationContainer = map[EM_ERROR_CODE]ErrorCodeExplanation{
 	TIUNIMANAGER_LOG_QUERY_FAILED: {"Failed to query cluster log", 500},
 	TIUNIMANAGER_LOG_TIME_AFTER:   {"query log parameter startTime after endTime", 401},
 
+	TIUNIMANAGER_ALERT_RULE_NOT_FOUND:        {"alert rule not found", 404},
+	TIUNIMANAGER_ALERT_RULE_INVALID:          {"alert rule is invalid", 400},
+	TIUNIMANAGER_ALERT_RULE_DUPLICATED:       {"alert rule with the same name already exists", 409},
+	TIUNIMANAGER_ALERT_RULE_BUILTIN_READONLY: {"built-in alert rule can only be enabled or disabled", 409},
+	TIUNIMANAGER_ALERT_MANAGER_UNAVAILABLE:   {"alertmanager of cluster is unavailable", 500},
+	TIUNIMANAGER_ALERT_SILENCE_NOT_FOUND:     {"alert silence not found", 404},
+	TIUNIMANAGER_ALERT_SILENCE_INVALID:       {"alert silence is invalid", 400},
+	TIUNIMANAGER_ALERT_RULE_NOT_LOADED:       {"alert rules are not loaded by prometheus", 500},
+
+	TIUNIMANAGER_NOTIFICATION_CHANNEL_NOT_FOUND: {"notification channel not found", 404},
+	TIUNIMANAGER_NOTIFICATION_CHANNEL_INVALID:   {"notification channel is invalid", 400},
+	TIUNIMANAGER_NOTIFICATION_CHANNEL_IN_USE:    {"notification channel is used by notification routes", 409},
+	TIUNIMANAGER_NOTIFICATION_ROUTE_NOT_FOUND:   {"notification route not found", 404},
+	TIUNIMANAGER_NOTIFICATION_ROUTE_INVALID:     {"notification route is invalid", 400},
+	TIUNIMANAGER_NOTIFICATION_SEND_FAILED:       {"failed to send notification", 500},
+	TIUNIMANAGER_ALERT_RECEIVER_NOT_CONFIGURED:  {"alert receiver of the platform is not configured", 409},
+	TIUNIMANAGER_ALERT_RECEIVER_UNAUTHORIZED:    {"token of alert receiver is invalid", 401},
+
+	TIUNIMANAGER_METRICS_QUERY_INVALID:  {"metrics query is invalid", 400},
+	TIUNIMANAGER_METRICS_QUERY_FAILED:   {"failed to query metrics from prometheus", 500},
+	TIUNIMANAGER_PROMETHEUS_UNAVAILABLE: {"prometheus of cluster is unavailable", 500},
+
+	TIUNIMANAGER_PERFORMANCE_QUERY_INVALID:      {"performance query is invalid", 400},
+	TIUNIMANAGER_PERFORMANCE_QUERY_FAILED:       {"failed to query performance data from tidb", 500},
+	TIUNIMANAGER_PERFORMANCE_SNAPSHOT_NOT_FOUND: {"performance snapshot not found", 404},
+	TIUNIMANAGER_PERFORMANCE_SNAPSHOT_EXISTED:   {"performance snapshot of the time range already exists", 409},
+
+	TIUNIMANAGER_HEALTH_PROBE_FAILED: {"failed to probe health of cluster component", 500},
+
 	// scale out & scale in
 	TIUNIMANAGER_INSTANCE_NOT_FOUND:               {"Instance of cluster is not found", 404},
 	TIUNIMANAGER_CONNECT_TIDB_ERROR:               {"Failed to connect TiDB instances", 500},
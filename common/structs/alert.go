@@ -0,0 +1,84 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package structs
+
+import (
+	"time"
+)
+
+// AlertRuleInfo alert rule of cluster, enabled rules are deployed to prometheus of the cluster
+type AlertRuleInfo struct {
+	ID         string    `json:"id"`
+	ClusterID  string    `json:"clusterId"`
+	Name       string    `json:"name" example:"TiDB_server_is_down"`
+	Type       string    `json:"type" enums:"BuiltIn,Custom"`
+	Component  string    `json:"component" example:"TiDB"`
+	Expr       string    `json:"expr" example:"probe_success{group=\"tidb\"} == 0"`
+	For        string    `json:"for" example:"1m"`
+	Severity   string    `json:"severity" enums:"emergency,critical,warning"`
+	Summary    string    `json:"summary"`
+	Enabled    bool      `json:"enabled"`
+	CreateTime time.Time `json:"createTime"`
+	UpdateTime time.Time `json:"updateTime"`
+}
+
+// FiringAlertInfo alert which is firing in alertmanager of cluster
+type FiringAlertInfo struct {
+	Fingerprint string            `json:"fingerprint"`
+	Name        string            `json:"name" example:"TiDB_server_is_down"`
+	Severity    string            `json:"severity" example:"emergency"`
+	State       string            `json:"state" enums:"active,suppressed,unprocessed"`
+	Summary     string            `json:"summary"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	SilencedBy  []string          `json:"silencedBy"`
+	StartsAt    time.Time         `json:"startsAt"`
+}
+
+// AlertHistoryInfo alert which has been firing
+type AlertHistoryInfo struct {
+	ID          string            `json:"id"`
+	ClusterID   string            `json:"clusterId"`
+	Fingerprint string            `json:"fingerprint"`
+	Name        string            `json:"name"`
+	Severity    string            `json:"severity"`
+	State       string            `json:"state" enums:"firing,resolved"`
+	Summary     string            `json:"summary"`
+	Labels      map[string]string `json:"labels"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+}
+
+// AlertMatcher matcher of alert labels
+type AlertMatcher struct {
+	Name    string `json:"name" validate:"required" example:"alertname"`
+	Value   string `json:"value" validate:"required" example:"TiDB_server_is_down"`
+	IsRegex bool   `json:"isRegex"`
+}
+
+// AlertSilenceInfo silence of alerts which match all the matchers
+type AlertSilenceInfo struct {
+	ID         string         `json:"id"`
+	ClusterID  string         `json:"clusterId"`
+	SilenceID  string         `json:"silenceId"` // id of silence in alertmanager
+	Matchers   []AlertMatcher `json:"matchers"`
+	Comment    string         `json:"comment"`
+	CreatedBy  string         `json:"createdBy"`
+	Status     string         `json:"status" enums:"Active,Expired"`
+	StartsAt   time.Time      `json:"startsAt"`
+	EndsAt     time.Time      `json:"endsAt"`
+	CreateTime time.Time      `json:"createTime"`
+}
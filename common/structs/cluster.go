@@ -73,18 +73,20 @@ func (p ClusterResourceInfo) GetComponentCount(idType constants.EMProductCompone
 type CreateClusterParameter struct {
 	Name string `json:"clusterName" validate:"required,min=4,max=64"`
 	// todo delete?
-	DBUser           string        `json:"dbUser" validate:"max=32"` //The username and password for the newly created database cluster, default is the root user, which is not valid for Data Migration clusters
-	DBPassword       SensitiveText `json:"dbPassword" validate:"required,min=8,max=32"`
-	Type             string        `json:"clusterType" validate:"required,oneof=TiDB DM TiKV"`
-	Version          string        `json:"clusterVersion" validate:"required,startswith=v"`
-	Tags             []string      `json:"tags"`
-	TLS              bool          `json:"tls"`
-	Copies           int           `json:"copies"`                     //The number of copies of the newly created cluster data, consistent with the number of copies set in PD
-	Exclusive        bool          `json:"exclusive" form:"exclusive"` //Whether the newly created cluster is exclusive to physical resources, when exclusive, a host will only deploy instances of the same cluster, which may result in poor resource utilization
-	Vendor           string        `json:"vendor" form:"vendor"`
-	Region           string        `json:"region" form:"region" validate:"required,max=32"`                                       //The Region where the cluster is located
-	CpuArchitecture  string        `json:"cpuArchitecture" form:"cpuArchitecture" validate:"required,oneof=X86 X86_64 ARM ARM64"` //X86/X86_64/ARM
-	ParameterGroupID string        `json:"parameterGroupID" form:"parameterGroupID"`
+	DBUser             string        `json:"dbUser" validate:"max=32"` //The username and password for the newly created database cluster, default is the root user, which is not valid for Data Migration clusters
+	DBPassword         SensitiveText `json:"dbPassword" validate:"required,min=8,max=32"`
+	Type               string        `json:"clusterType" validate:"required,oneof=TiDB DM TiKV"`
+	Version            string        `json:"clusterVersion" validate:"required,startswith=v"`
+	Tags               []string      `json:"tags"`
+	TLS                bool          `json:"tls"`
+	Copies             int           `json:"copies"`                     //The number of copies of the newly created cluster data, consistent with the number of copies set in PD
+	Exclusive          bool          `json:"exclusive" form:"exclusive"` //Whether the newly created cluster is exclusive to physical resources, when exclusive, a host will only deploy instances of the same cluster, which may result in poor resource utilization
+	Vendor             string        `json:"vendor" form:"vendor"`
+	Region             string        `json:"region" form:"region" validate:"required,max=32"`                                       //The Region where the cluster is located
+	CpuArchitecture    string        `json:"cpuArchitecture" form:"cpuArchitecture" validate:"required,oneof=X86 X86_64 ARM ARM64"` //X86/X86_64/ARM
+	ParameterGroupID   string        `json:"parameterGroupID" form:"parameterGroupID"`
+	MaintainWindow     string        `json:"maintainWindow" validate:"max=256" example:"Saturday,Sunday 02:00-06:00"` //Disruptive operations are only allowed in the window, empty means no restriction. Time zone such as Asia/Shanghai may follow the time range, UTC if omitted
+	DeletionProtection bool          `json:"deletionProtection"`                                                      //The cluster can not be deleted until the protection is disabled
 }
 
 // ClusterRelations Cluster relations info
@@ -95,11 +97,12 @@ type ClusterRelations struct {
 
 // ClusterInfo Cluster details information
 type ClusterInfo struct {
-	ID      string `json:"clusterId"`
-	UserID  string `json:"userId"`
-	Name    string `json:"clusterName"`
-	Type    string `json:"clusterType"`
-	Version string `json:"clusterVersion"`
+	ID       string `json:"clusterId"`
+	UserID   string `json:"userId"`
+	TenantID string `json:"tenantId"`
+	Name     string `json:"clusterName"`
+	Type     string `json:"clusterType"`
+	Version  string `json:"clusterVersion"`
 	//DBUser                   string    `json:"dbUser"` //The username and password for the newly created database cluster, default is the root user, which is not valid for Data Migration clusters
 	Vendor                   string           `json:"vendor" form:"vendor"`
 	Tags                     []string         `json:"tags"`
@@ -114,6 +117,7 @@ type ClusterInfo struct {
 	GrafanaUrl               string           `json:"grafanaUrl" example:"http://127.0.0.1:3000"`
 	MaintainStatus           string           `json:"maintainStatus"`
 	MaintainWindow           string           `json:"maintainWindow"`
+	DeletionProtection       bool             `json:"deletionProtection"`
 	IntranetConnectAddresses []string         `json:"intranetConnectAddresses"`
 	ExtranetConnectAddresses []string         `json:"extranetConnectAddresses"`
 	Whitelist                []string         `json:"whitelist"`
@@ -154,9 +158,35 @@ type ClusterTopologyInfo struct {
 
 // BackupStrategy Timed or scheduled data backup strategy
 type BackupStrategy struct {
-	ClusterID  string `json:"clusterId"`
-	BackupDate string `json:"backupDate"`
-	Period     string `json:"period"`
+	ClusterID  string          `json:"clusterId"`
+	BackupDate string          `json:"backupDate"`
+	Period     string          `json:"period"`
+	Retention  BackupRetention `json:"retention"`
+	AutoVerify bool            `json:"autoVerify"` // verify the latest auto backup periodically
+}
+
+// BackupRetention Retention rules of auto backups, a backup is kept if any rule keeps it, 0 means the rule is disabled
+type BackupRetention struct {
+	KeepLast    int `json:"keepLast"`    // keep the latest N backups
+	KeepDays    int `json:"keepDays"`    // keep backups created in recent D days
+	KeepWeekly  int `json:"keepWeekly"`  // keep the latest backup of each recent W weeks
+	KeepMonthly int `json:"keepMonthly"` // keep the latest backup of each recent M months
+}
+
+// LogBackupTask Continuous log backup task of a cluster
+type LogBackupTask struct {
+	ID              string    `json:"id"`
+	ClusterID       string    `json:"clusterId"`
+	Status          string    `json:"status"`
+	StorageType     string    `json:"storageType"`
+	FilePath        string    `json:"filePath"`
+	StartTSO        string    `json:"startTso"`
+	CheckpointTSO   string    `json:"checkpointTso"`
+	TruncatedTSO    string    `json:"truncatedTso"`
+	RestorableStart time.Time `json:"restorableStart"` // earliest point in time can be restored
+	RestorableEnd   time.Time `json:"restorableEnd"`   // latest point in time can be restored
+	StartTime       time.Time `json:"startTime"`
+	StopTime        time.Time `json:"stopTime"`
 }
 
 // BackupRecord Single backup file details
@@ -168,13 +198,16 @@ type BackupRecord struct {
 	BackupMode   string    `json:"backupMode"`
 	FilePath     string    `json:"filePath"`
 	Size         float32   `json:"size"`
-	BackupTSO    string    `json:"backupTso"`
-	Status       string    `json:"status"`
-	StartTime    time.Time `json:"startTime"`
-	EndTime      time.Time `json:"endTime"`
-	CreateTime   time.Time `json:"createTime"`
-	UpdateTime   time.Time `json:"updateTime"`
-	DeleteTime   time.Time `json:"deleteTime"`
+	BackupTSO     string    `json:"backupTso"`
+	Status        string    `json:"status"`
+	StartTime     time.Time `json:"startTime"`
+	EndTime       time.Time `json:"endTime"`
+	CreateTime    time.Time `json:"createTime"`
+	UpdateTime    time.Time `json:"updateTime"`
+	DeleteTime    time.Time `json:"deleteTime"`
+	VerifyStatus  string    `json:"verifyStatus"`
+	VerifyMessage string    `json:"verifyMessage"`
+	VerifyTime    time.Time `json:"verifyTime"`
 }
 
 type ClusterLogItem struct {
@@ -217,6 +250,73 @@ type ClusterUpgradeVersionConfigItem struct {
 	Value        string `json:"value" validate:"required" example:"20"`
 }
 
+// UpgradeRollbackItem an instance which is rolled back to the version before upgrade
+type UpgradeRollbackItem struct {
+	InstanceID   string `json:"instanceId" example:"instance01"`
+	InstanceType string `json:"instanceType" example:"TiKV"`
+	Address      string `json:"address" example:"172.16.1.12:20160"`
+	FromVersion  string `json:"fromVersion" example:"v5.4.0"`
+	ToVersion    string `json:"toVersion" example:"v5.3.0"`
+}
+
+// UpgradeCampaignSelector select clusters of an upgrade campaign, empty field means no restriction
+type UpgradeCampaignSelector struct {
+	ClusterIDs []string `json:"clusterIds"`
+	Tags       []string `json:"tags"`     // cluster is selected if it has all of the tags
+	Versions   []string `json:"versions"` // cluster is selected if its version is one of them
+	Vendors    []string `json:"vendors"`
+	// TagSelector cluster is selected if its tags match the selector, eg. env=prod,team in (dba,ops)
+	TagSelector string `json:"tagSelector"`
+}
+
+// UpgradeCampaignInfo upgrade campaign with aggregate progress of its clusters
+type UpgradeCampaignInfo struct {
+	ID               string                  `json:"id"`
+	Name             string                  `json:"name"`
+	TargetVersion    string                  `json:"targetVersion" example:"v5.4.0"`
+	UpgradeWay       string                  `json:"upgradeWay" enums:"offline,online"`
+	Selector         UpgradeCampaignSelector `json:"selector"`
+	WaveSizes        []int                   `json:"waveSizes" example:"1,5,20"`
+	Concurrency      int                     `json:"concurrency" example:"2"`
+	FailureThreshold float64                 `json:"failureThreshold" example:"0.2"`
+	Status           string                  `json:"status" enums:"Running,Halted,Canceled,Completed"`
+	Message          string                  `json:"message"`
+	Waves            int                     `json:"waves"`
+	CurrentWave      int                     `json:"currentWave"`
+	Total            int                     `json:"total"`
+	Waiting          int                     `json:"waiting"`
+	Upgrading        int                     `json:"upgrading"`
+	Succeeded        int                     `json:"succeeded"`
+	Failed           int                     `json:"failed"`
+	Skipped          int                     `json:"skipped"`
+	OperatorID       string                  `json:"operatorId"`
+	CreateTime       time.Time               `json:"createTime"`
+	UpdateTime       time.Time               `json:"updateTime"`
+	EndTime          time.Time               `json:"endTime"`
+}
+
+// UpgradeCampaignClusterInfo a cluster upgraded by upgrade campaign
+type UpgradeCampaignClusterInfo struct {
+	ClusterID       string    `json:"clusterId"`
+	ClusterName     string    `json:"clusterName"`
+	OriginalVersion string    `json:"originalVersion" example:"v5.3.0"`
+	Wave            int       `json:"wave"`
+	Status          string    `json:"status" enums:"Waiting,Upgrading,Succeeded,Failed,Skipped"`
+	WorkFlowID      string    `json:"workFlowId"`
+	Message         string    `json:"message"`
+	StartTime       time.Time `json:"startTime"`
+	EndTime         time.Time `json:"endTime"`
+}
+
+// UpgradeCheckItem result of a pre-upgrade check, the upgrade is not allowed if any blocking item is not passed
+type UpgradeCheckItem struct {
+	Category string `json:"category" enums:"Binary,Duration,Config,Health" example:"Binary"`
+	Name     string `json:"name" example:"tikv"`
+	Passed   bool   `json:"passed" example:"true"`
+	Blocking bool   `json:"blocking" example:"true"`
+	Message  string `json:"message" example:"tikv v5.4.0 for linux/amd64 is available, checksum verified"`
+}
+
 type ClusterInstanceParameterValue struct {
 	ID    string `json:"instanceId"`
 	Value string `json:"value"`
@@ -261,3 +361,35 @@ type ResourceStockCheckResult struct {
 	ClusterResourceParameterComputeResource
 	Enough bool `json:"enough"`
 }
+
+// MaintainWindowOption How to deal with a disruptive operation submitted out of the maintain window of cluster
+type MaintainWindowOption struct {
+	IgnoreMaintainWindow bool `json:"ignoreMaintainWindow" form:"ignoreMaintainWindow"` // run at once even if out of the maintain window, for emergencies only
+	WaitMaintainWindow   bool `json:"waitMaintainWindow" form:"waitMaintainWindow"`     // queue the operation until the maintain window opens, otherwise it is rejected
+}
+
+// PendingOperationInfo Disruptive operation waiting for the maintain window of cluster
+type PendingOperationInfo struct {
+	ID            string    `json:"id"`
+	ClusterID     string    `json:"clusterId"`
+	OperationType string    `json:"operationType" enums:"RestartCluster,UpgradeCluster,UpdateClusterParameters,ApplyParameterGroup,BackupCluster"`
+	Status        string    `json:"status" enums:"Waiting,Launched,Failed,Canceled"`
+	WorkFlowID    string    `json:"workFlowId"`
+	Message       string    `json:"message"`
+	CreateTime    time.Time `json:"createTime"`
+	LaunchTime    time.Time `json:"launchTime"`
+}
+
+// RecycledClusterInfo Soft deleted cluster in the recycle bin, it is purged after ExpireTime
+type RecycledClusterInfo struct {
+	ID          string    `json:"id"`
+	ClusterID   string    `json:"clusterId"`
+	ClusterName string    `json:"clusterName"`
+	OperatorID  string    `json:"operatorId"`
+	Status      string    `json:"status" enums:"Recycling,Recycled,Recovered,Purged,Failed"`
+	BackupID    string    `json:"backupId"`
+	WorkFlowID  string    `json:"workFlowId"`
+	Message     string    `json:"message"`
+	CreateTime  time.Time `json:"createTime"`
+	ExpireTime  time.Time `json:"expireTime"`
+}
@@ -0,0 +1,32 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package structs
+
+import (
+	"time"
+)
+
+// ClusterHealthEventInfo status change of cluster or its instance found by health monitor, instance id is empty for cluster
+type ClusterHealthEventInfo struct {
+	ID             string    `json:"id"`
+	ClusterID      string    `json:"clusterId"`
+	InstanceID     string    `json:"instanceId"`
+	InstanceType   string    `json:"instanceType" example:"TiKV"`
+	PreviousStatus string    `json:"previousStatus" example:"Running"`
+	Status         string    `json:"status" example:"Failure"`
+	Reason         string    `json:"reason" example:"store 1 is Down"`
+	CreateTime     time.Time `json:"createTime"`
+}
@@ -0,0 +1,76 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package structs
+
+import (
+	"time"
+)
+
+// NotificationChannelConfig config of notification channel, URL is used by Webhook and Slack, SMTP fields are used by Email
+type NotificationChannelConfig struct {
+	URL          string        `json:"url,omitempty" example:"http://127.0.0.1:8080/alerts"`
+	SMTPHost     string        `json:"smtpHost,omitempty" example:"smtp.example.com"`
+	SMTPPort     int           `json:"smtpPort,omitempty" example:"25"`
+	SMTPUser     string        `json:"smtpUser,omitempty"`
+	SMTPPassword SensitiveText `json:"smtpPassword,omitempty"`
+	From         string        `json:"from,omitempty" example:"tiunimanager@example.com"`
+	To           []string      `json:"to,omitempty" example:"dba@example.com"`
+}
+
+// NotificationChannelInfo channel which notifications of alerts are sent to
+type NotificationChannelInfo struct {
+	ID         string                    `json:"id"`
+	Name       string                    `json:"name"`
+	Type       string                    `json:"type" enums:"Webhook,Email,Slack"`
+	Config     NotificationChannelConfig `json:"config"`
+	CreateTime time.Time                 `json:"createTime"`
+	UpdateTime time.Time                 `json:"updateTime"`
+}
+
+// NotificationRouteInfo route alerts to channels, empty severities, tag selector or tenant matches all
+type NotificationRouteInfo struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Severities  []string  `json:"severities" example:"emergency,critical"`
+	TagSelector string    `json:"tagSelector" example:"env=prod"`
+	TenantID    string    `json:"tenantId"`
+	ChannelIDs  []string  `json:"channelIds"`
+	CreateTime  time.Time `json:"createTime"`
+	UpdateTime  time.Time `json:"updateTime"`
+}
+
+// NotificationDeliveryInfo log of sending a notification to a channel
+type NotificationDeliveryInfo struct {
+	ID          string    `json:"id"`
+	ChannelID   string    `json:"channelId"`
+	ChannelName string    `json:"channelName"`
+	ClusterID   string    `json:"clusterId"`
+	Subject     string    `json:"subject"`
+	Test        bool      `json:"test"`
+	Status      string    `json:"status" enums:"Success,Failed"`
+	Message     string    `json:"message"`
+	CreateTime  time.Time `json:"createTime"`
+}
+
+// NotificationAlert alert sent by webhook of alertmanager
+type NotificationAlert struct {
+	Status      string            `json:"status" enums:"firing,resolved"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+	Fingerprint string            `json:"fingerprint"`
+}
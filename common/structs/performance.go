@@ -0,0 +1,89 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package structs
+
+import (
+	"time"
+)
+
+// StatementInfo statistics of a statement digest within a time range, latency is in milliseconds
+type StatementInfo struct {
+	Digest           string    `json:"digest"`
+	DigestText       string    `json:"digestText" example:"select * from t where id = ?"`
+	SchemaName       string    `json:"schemaName" example:"test"`
+	ExecCount        int64     `json:"execCount"`
+	SumLatency       float64   `json:"sumLatency"`
+	AvgLatency       float64   `json:"avgLatency"`
+	MaxLatency       float64   `json:"maxLatency"`
+	SumProcessedKeys int64     `json:"sumProcessedKeys"`
+	SumTotalKeys     int64     `json:"sumTotalKeys"`
+	AvgTotalKeys     int64     `json:"avgTotalKeys"`
+	FirstSeen        time.Time `json:"firstSeen"`
+	LastSeen         time.Time `json:"lastSeen"`
+}
+
+// StatementPlanInfo statistics of an execution plan of statement digest, latency is in milliseconds
+type StatementPlanInfo struct {
+	PlanDigest   string    `json:"planDigest"`
+	Plan         string    `json:"plan"`
+	SampleText   string    `json:"sampleText" example:"select * from t where id = 1"`
+	ExecCount    int64     `json:"execCount"`
+	SumLatency   float64   `json:"sumLatency"`
+	AvgLatency   float64   `json:"avgLatency"`
+	MaxLatency   float64   `json:"maxLatency"`
+	AvgTotalKeys int64     `json:"avgTotalKeys"`
+	FirstSeen    time.Time `json:"firstSeen"`
+	LastSeen     time.Time `json:"lastSeen"`
+}
+
+// SlowQueryInfo an entry of slow query log of cluster, query time is in milliseconds
+type SlowQueryInfo struct {
+	Time        time.Time `json:"time"`
+	Instance    string    `json:"instance" example:"172.16.6.252:10080"`
+	Digest      string    `json:"digest"`
+	Query       string    `json:"query" example:"select * from t where id = 1"`
+	DB          string    `json:"db" example:"test"`
+	User        string    `json:"user" example:"root"`
+	QueryTime   float64   `json:"queryTime"`
+	ProcessKeys int64     `json:"processKeys"`
+	TotalKeys   int64     `json:"totalKeys"`
+	Success     bool      `json:"success"`
+}
+
+// PerformanceSnapshotInfo top statements of cluster captured within a time range
+type PerformanceSnapshotInfo struct {
+	ID             string    `json:"id"`
+	ClusterID      string    `json:"clusterId"`
+	Name           string    `json:"name" example:"before upgrade"`
+	Type           string    `json:"type" enums:"Periodic,Manual"`
+	StartTime      time.Time `json:"startTime"`
+	EndTime        time.Time `json:"endTime"`
+	StatementCount int       `json:"statementCount"`
+	CreateTime     time.Time `json:"createTime"`
+}
+
+// StatementComparison a statement digest in base and target performance snapshots,
+// base or target is empty if the statement is not captured in the snapshot, and diffs are target minus base
+type StatementComparison struct {
+	Digest         string         `json:"digest"`
+	DigestText     string         `json:"digestText"`
+	SchemaName     string         `json:"schemaName"`
+	Base           *StatementInfo `json:"base"`
+	Target         *StatementInfo `json:"target"`
+	ExecCountDiff  int64          `json:"execCountDiff"`
+	SumLatencyDiff float64        `json:"sumLatencyDiff"`
+	AvgLatencyDiff float64        `json:"avgLatencyDiff"`
+}
@@ -15,15 +15,63 @@
 
 package structs
 
-import "github.com/pingcap/tiunimanager/common/constants"
+import (
+	"strings"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+)
 
 type RbacPermission struct {
 	Resource string `json:"resource"`
 	Action   string `json:"action"`
+	// Instance limit the permission to some instances of resource, empty means all instances.
+	// It is made up of scope and pattern, eg. id/cluster01, tag/team=dba, tenant/*, and pattern supports wildcards, "*" matches any characters including "/"
+	Instance string `json:"instance,omitempty"`
 }
 
 func (permission *RbacPermission) CheckInvalid() bool {
 	_, resourceExist := constants.RbacResourceMap[permission.Resource]
 	_, actionExist := constants.RbacActionMap[permission.Action]
-	return resourceExist && actionExist
+	return resourceExist && actionExist && permission.checkInstance()
+}
+
+func (permission *RbacPermission) checkInstance() bool {
+	if permission.Instance == "" {
+		return true
+	}
+	if permission.Resource != string(constants.RbacResourceCluster) {
+		return false
+	}
+	parts := strings.Split(permission.Instance, constants.RbacInstanceSeparator)
+	if len(parts) != 2 || parts[1] == "" {
+		return false
+	}
+	_, scopeExist := constants.RbacInstanceScopeMap[parts[0]]
+	return scopeExist
+}
+
+// GetObject
+// @Description: get object of rbac policy, which is resource itself or resource with instance
+// @Receiver permission
+// @return string
+func (permission *RbacPermission) GetObject() string {
+	if permission.Instance == "" {
+		return permission.Resource
+	}
+	return permission.Resource + constants.RbacInstanceSeparator + permission.Instance
+}
+
+// NewRbacPermissionFromObject
+// @Description: build permission from object and action of rbac policy
+// @Parameter object
+// @Parameter action
+// @return RbacPermission
+func NewRbacPermissionFromObject(object string, action string) RbacPermission {
+	permission := RbacPermission{Action: action}
+	parts := strings.SplitN(object, constants.RbacInstanceSeparator, 2)
+	permission.Resource = parts[0]
+	if len(parts) > 1 {
+		permission.Instance = parts[1]
+	}
+	return permission
 }
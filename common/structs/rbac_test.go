@@ -28,3 +28,26 @@ func TestRbacPermission_CheckInvalid(t *testing.T) {
 	}
 	assert.Equal(t, true, permission.CheckInvalid())
 }
+
+func TestRbacPermission_CheckInvalid_instance(t *testing.T) {
+	permission := &RbacPermission{
+		Resource: string(constants.RbacResourceCluster),
+		Action:   string(constants.RbacActionRead),
+		Instance: "tag/team=dba*",
+	}
+	assert.True(t, permission.CheckInvalid())
+	assert.Equal(t, "CLUSTER/tag/team=dba*", permission.GetObject())
+	assert.Equal(t, *permission, NewRbacPermissionFromObject(permission.GetObject(), permission.Action))
+
+	permission.Instance = "owner/dba"
+	assert.False(t, permission.CheckInvalid())
+	permission.Instance = "id/"
+	assert.False(t, permission.CheckInvalid())
+	permission.Instance = "id/a/b"
+	assert.False(t, permission.CheckInvalid())
+	permission.Resource = string(constants.RbacResourceResource)
+	permission.Instance = "id/host01"
+	assert.False(t, permission.CheckInvalid())
+
+	assert.Equal(t, RbacPermission{Resource: "CLUSTER", Action: "READ"}, NewRbacPermissionFromObject("CLUSTER", "READ"))
+}
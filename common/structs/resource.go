@@ -124,6 +124,16 @@ type HostInfo struct {
 	Disks              []DiskInfo          `json:"disks"`
 }
 
+// DrainInstanceProgress progress of evacuating a cluster instance from the host being drained
+type DrainInstanceProgress struct {
+	ClusterID  string `json:"clusterId"`
+	InstanceID string `json:"instanceId"`
+	Type       string `json:"type"`
+	Status     string `json:"status"`     // Waiting, Migrating, Migrated or Failed
+	WorkFlowID string `json:"workFlowId"` // workflow migrating the instance
+	Message    string `json:"message"`
+}
+
 func ParseCpu(specCode string) int {
 	cpu, err := strconv.Atoi(strings.Split(specCode, "C")[0])
 	if err != nil {
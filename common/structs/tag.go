@@ -0,0 +1,223 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package structs
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+)
+
+var tagPattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9_.\-/]*[A-Za-z0-9])?$`)
+
+// ClusterTag label of cluster, a tag without value is stored as key with empty value
+type ClusterTag struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// ParseClusterTag
+// @Description: parse tag from key=value or key
+// @Parameter tag
+// @return ClusterTag
+func ParseClusterTag(tag string) ClusterTag {
+	parts := strings.SplitN(tag, constants.TagKeyValueSeparator, 2)
+	result := ClusterTag{Key: strings.TrimSpace(parts[0])}
+	if len(parts) > 1 {
+		result.Value = strings.TrimSpace(parts[1])
+	}
+	return result
+}
+
+// String
+// @Description: format tag as key=value, or key if value is empty
+// @Receiver tag
+// @return string
+func (tag ClusterTag) String() string {
+	if tag.Value == "" {
+		return tag.Key
+	}
+	return tag.Key + constants.TagKeyValueSeparator + tag.Value
+}
+
+// Validate
+// @Description: key is required, key and value are made up of alphanumerics, '-', '_', '.' and '/'
+// @Receiver tag
+// @return error
+func (tag ClusterTag) Validate() error {
+	if len(tag.Key) == 0 || len(tag.Key) > constants.TagMaxLength || !tagPattern.MatchString(tag.Key) {
+		return fmt.Errorf("invalid tag key '%s'", tag.Key)
+	}
+	if len(tag.Value) > constants.TagMaxLength || (tag.Value != "" && !tagPattern.MatchString(tag.Value)) {
+		return fmt.Errorf("invalid value '%s' of tag '%s'", tag.Value, tag.Key)
+	}
+	return nil
+}
+
+// TagRequirement a requirement of tag selector, Values are used by =, !=, in and notin
+type TagRequirement struct {
+	Key      string                        `json:"key"`
+	Operator constants.TagSelectorOperator `json:"operator"`
+	Values   []string                      `json:"values"`
+}
+
+// TagSelector select clusters whose tags match all requirements
+type TagSelector []TagRequirement
+
+// ParseTagSelector
+// @Description: parse selector like "env=prod,team in (dba,ops),region!=east,backup,!legacy",
+// requirements are separated by commas out of parentheses
+// @Parameter selector
+// @return TagSelector
+// @return error
+func ParseTagSelector(selector string) (TagSelector, error) {
+	result := make(TagSelector, 0)
+	for _, expression := range splitTagSelector(selector) {
+		expression = strings.TrimSpace(expression)
+		if expression == "" {
+			continue
+		}
+		requirement, err := parseTagRequirement(expression)
+		if err != nil {
+			return nil, err
+		}
+		if err = (ClusterTag{Key: requirement.Key}).Validate(); err != nil {
+			return nil, err
+		}
+		for _, value := range requirement.Values {
+			if err = (ClusterTag{Key: requirement.Key, Value: value}).Validate(); err != nil {
+				return nil, err
+			}
+		}
+		result = append(result, requirement)
+	}
+	return result, nil
+}
+
+func splitTagSelector(selector string) []string {
+	expressions := make([]string, 0)
+	depth, start := 0, 0
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				expressions = append(expressions, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(expressions, selector[start:])
+}
+
+func parseTagRequirement(expression string) (TagRequirement, error) {
+	if strings.HasPrefix(expression, string(constants.TagOperatorDoesNotExist)) {
+		return TagRequirement{
+			Key:      strings.TrimSpace(expression[1:]),
+			Operator: constants.TagOperatorDoesNotExist,
+		}, nil
+	}
+	if i := strings.Index(expression, "!="); i > 0 {
+		return TagRequirement{
+			Key:      strings.TrimSpace(expression[:i]),
+			Operator: constants.TagOperatorNotEquals,
+			Values:   []string{strings.TrimSpace(expression[i+2:])},
+		}, nil
+	}
+	if i := strings.Index(expression, "="); i > 0 {
+		return TagRequirement{
+			Key:      strings.TrimSpace(expression[:i]),
+			Operator: constants.TagOperatorEquals,
+			Values:   []string{strings.TrimSpace(strings.TrimPrefix(expression[i+1:], "="))},
+		}, nil
+	}
+	fields := strings.Fields(expression)
+	if len(fields) == 1 {
+		return TagRequirement{Key: fields[0], Operator: constants.TagOperatorExists}, nil
+	}
+	rest := strings.TrimSpace(expression[len(fields[0]):])
+	operator, set := constants.TagSelectorOperator(""), ""
+	for _, op := range []constants.TagSelectorOperator{constants.TagOperatorNotIn, constants.TagOperatorIn} {
+		if strings.HasPrefix(rest, string(op)) {
+			operator, set = op, strings.TrimSpace(rest[len(op):])
+			break
+		}
+	}
+	if operator == "" {
+		return TagRequirement{}, fmt.Errorf("invalid operator in tag selector '%s'", expression)
+	}
+	if !strings.HasPrefix(set, "(") || !strings.HasSuffix(set, ")") {
+		return TagRequirement{}, fmt.Errorf("values of '%s' should be enclosed in parentheses", expression)
+	}
+	values := make([]string, 0)
+	for _, value := range strings.Split(set[1:len(set)-1], ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return TagRequirement{}, fmt.Errorf("empty values in tag selector '%s'", expression)
+	}
+	return TagRequirement{Key: fields[0], Operator: operator, Values: values}, nil
+}
+
+// Matches
+// @Description: whether tags match all requirements of selector
+// @Receiver selector
+// @Parameter tags, in the format of key=value or key
+// @return bool
+func (selector TagSelector) Matches(tags []string) bool {
+	tagMap := make(map[string]string)
+	for _, tag := range tags {
+		clusterTag := ParseClusterTag(tag)
+		tagMap[clusterTag.Key] = clusterTag.Value
+	}
+	for _, requirement := range selector {
+		if !requirement.matches(tagMap) {
+			return false
+		}
+	}
+	return true
+}
+
+func (requirement TagRequirement) matches(tagMap map[string]string) bool {
+	value, exist := tagMap[requirement.Key]
+	switch requirement.Operator {
+	case constants.TagOperatorExists:
+		return exist
+	case constants.TagOperatorDoesNotExist:
+		return !exist
+	case constants.TagOperatorEquals, constants.TagOperatorIn:
+		return exist && containsTagValue(requirement.Values, value)
+	case constants.TagOperatorNotEquals, constants.TagOperatorNotIn:
+		return !exist || !containsTagValue(requirement.Values, value)
+	}
+	return false
+}
+
+func containsTagValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
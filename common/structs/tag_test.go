@@ -0,0 +1,79 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package structs
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterTag(t *testing.T) {
+	tag := ParseClusterTag("team = dba")
+	assert.Equal(t, ClusterTag{Key: "team", Value: "dba"}, tag)
+	assert.Equal(t, "team=dba", tag.String())
+	assert.NoError(t, tag.Validate())
+
+	tag = ParseClusterTag("prod")
+	assert.Equal(t, ClusterTag{Key: "prod"}, tag)
+	assert.Equal(t, "prod", tag.String())
+	assert.NoError(t, tag.Validate())
+
+	assert.Error(t, ClusterTag{Value: "dba"}.Validate())
+	assert.Error(t, ClusterTag{Key: "team name"}.Validate())
+	assert.Error(t, ClusterTag{Key: "team", Value: "dba,ops"}.Validate())
+}
+
+func TestParseTagSelector(t *testing.T) {
+	selector, err := ParseTagSelector("env=prod, team in (dba, ops),region!=east,tier==1,backup,!legacy,app notin (a)")
+	assert.NoError(t, err)
+	assert.Equal(t, TagSelector{
+		{Key: "env", Operator: constants.TagOperatorEquals, Values: []string{"prod"}},
+		{Key: "team", Operator: constants.TagOperatorIn, Values: []string{"dba", "ops"}},
+		{Key: "region", Operator: constants.TagOperatorNotEquals, Values: []string{"east"}},
+		{Key: "tier", Operator: constants.TagOperatorEquals, Values: []string{"1"}},
+		{Key: "backup", Operator: constants.TagOperatorExists},
+		{Key: "legacy", Operator: constants.TagOperatorDoesNotExist},
+		{Key: "app", Operator: constants.TagOperatorNotIn, Values: []string{"a"}},
+	}, selector)
+
+	selector, err = ParseTagSelector("")
+	assert.NoError(t, err)
+	assert.Empty(t, selector)
+
+	for _, invalid := range []string{"team in dba", "team in ()", "team like (dba)", "=dba", "team=a b"} {
+		_, err = ParseTagSelector(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestTagSelector_Matches(t *testing.T) {
+	tags := []string{"env=prod", "team=dba", "backup"}
+	match := func(s string) bool {
+		selector, err := ParseTagSelector(s)
+		assert.NoError(t, err)
+		return selector.Matches(tags)
+	}
+	assert.True(t, match(""))
+	assert.True(t, match("env=prod,backup"))
+	assert.True(t, match("team in (ops,dba),region!=east,!legacy"))
+	assert.True(t, match("backup="))
+	assert.False(t, match("env=test"))
+	assert.False(t, match("team notin (dba)"))
+	assert.False(t, match("region"))
+	assert.False(t, match("!backup"))
+}
@@ -39,9 +39,18 @@ type WorkFlowInfo struct {
 type WorkFlowNodeInfo struct {
 	ID         string    `json:"id"`
 	Name       string    `json:"name"`
+	Branch     string    `json:"branch"`
 	Parameters string    `json:"parameters"`
 	Result     string    `json:"result"`
 	Status     string    `json:"status" enums:"Initializing,Processing,Finished,Error,Canceled"`
+	Attempts   int       `json:"attempts"`
+	Resumed    bool      `json:"resumed"`
 	StartTime  time.Time `json:"startTime"`
 	EndTime    time.Time `json:"endTime"`
 }
+
+type WorkFlowBranchInfo struct {
+	ParallelNode string   `json:"parallelNode"`
+	Name         string   `json:"name"`
+	NodeNames    []string `json:"nodeNames"`
+}
@@ -27,10 +27,19 @@
 
 package deployment
 
-import "syscall"
+import (
+	"os/exec"
+	"syscall"
+)
 
 func genSysProcAttr() *syscall.SysProcAttr {
 	return &syscall.SysProcAttr{
 		Pdeathsig: syscall.SIGTERM,
+		// tiup runs the component in a child process, they are killed together by the process group
+		Setpgid: true,
 	}
 }
+
+func killProcessGroup(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
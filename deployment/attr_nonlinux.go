@@ -27,8 +27,15 @@
 
 package deployment
 
-import "syscall"
+import (
+	"os/exec"
+	"syscall"
+)
 
 func genSysProcAttr() *syscall.SysProcAttr {
 	return &syscall.SysProcAttr{}
 }
+
+func killProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
@@ -30,7 +30,9 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -45,6 +47,18 @@ type Manager struct {
 	TiUPBinPath string
 }
 
+// cancelWaitTimeout time to wait for a killed operation to exit
+const cancelWaitTimeout = 30 * time.Second
+
+// runningOperation async operation being executed by this process, done is closed after its status is updated
+type runningOperation struct {
+	cmd  *exec.Cmd
+	done chan struct{}
+}
+
+// runningOperations operation id -> *runningOperation
+var runningOperations sync.Map
+
 // Deploy
 // @Description: wrapper of `tiup <component> deploy`, <component> can be 'cluster', 'dm'
 // @Receiver m
@@ -523,6 +537,70 @@ func (m *Manager) Lightning(ctx context.Context, home, workFlowID string, args [
 	return id, nil
 }
 
+// BR
+// @Description: wrapper of `tiup br:<version>`, for long running commands like `br restore point`
+// @Receiver m
+// @Parameter ctx
+// @Parameter version version of br, same as the cluster
+// @Parameter home
+// @Parameter workFlowID
+// @Parameter args
+// @Parameter timeout
+// @return ID, operation id to help check the status
+// @return err
+func (m *Manager) BR(ctx context.Context, version, home, workFlowID string, args []string, timeout int) (ID string, err error) {
+	logInFunc := framework.LogWithContext(ctx).WithField("workFlowID", workFlowID)
+
+	tiUPArgs := fmt.Sprintf("%s:%s %s", CMDBR, version, strings.Join(args, " "))
+	// args contain the storage address with its secret key, only the sub command is recorded
+	op := fmt.Sprintf("TIUP_HOME=%s %s %s:%s %s", home, m.TiUPBinPath, CMDBR, version, brSubCommand(args))
+	logInFunc.Infof("recv operation req: %s", op)
+
+	id, err := Create(home, Operation{
+		Type:       CMDBR,
+		Operation:  op,
+		WorkFlowID: workFlowID,
+		Status:     Init,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	m.startAsyncOperation(ctx, id, home, tiUPArgs, timeout)
+	return id, nil
+}
+
+// BRSync
+// @Description: wrapper of `tiup br:<version>`, for commands returning in a short time like `br log status`
+// @Receiver m
+// @Parameter ctx
+// @Parameter version version of br, same as the cluster
+// @Parameter home
+// @Parameter args
+// @Parameter timeout
+// @return result
+// @return err
+func (m *Manager) BRSync(ctx context.Context, version, home string, args []string, timeout int) (result string, err error) {
+	logInFunc := framework.LogWithContext(ctx)
+
+	tiUPArgs := fmt.Sprintf("%s:%s %s", CMDBR, version, strings.Join(args, " "))
+	logInFunc.Infof("recv operation req: TIUP_HOME=%s %s %s:%s %s", home, m.TiUPBinPath, CMDBR, version, brSubCommand(args))
+
+	return m.startSyncOperation(home, tiUPArgs, timeout, false)
+}
+
+// brSubCommand sub command of br without flags, e.g. `log start`
+func brSubCommand(args []string) string {
+	subCommand := make([]string, 0)
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			break
+		}
+		subCommand = append(subCommand, arg)
+	}
+	return strings.Join(subCommand, " ")
+}
+
 // Push
 // @Description: wrapper of `tiup cluster push`
 // @Receiver m
@@ -577,14 +655,10 @@ func (m *Manager) Push(ctx context.Context, componentType TiUPComponentType, clu
 // @return result, content of the file pulled from remotePath
 // @return err
 func (m *Manager) Pull(ctx context.Context, componentType TiUPComponentType, clusterID, remotePath, home string, args []string, timeout int) (result string, err error) {
-	logInFunc := framework.LogWithContext(ctx)
-
 	localPath := fmt.Sprintf("/tmp/%s", uuidutil.GenerateID())
-	//defer os.Remove(localPath)
-	tiUPArgs := fmt.Sprintf("%s %s %s %s %s %s %s %d %s", componentType, CMDPull, clusterID, remotePath, localPath, strings.Join(args, " "), FlagWaitTimeout, timeout, CMDYes)
-	logInFunc.Infof("recv operation req: TIUP_HOME=%s %s %s", home, m.TiUPBinPath, tiUPArgs)
+	defer os.Remove(localPath)
 
-	_, err = m.startSyncOperation(home, tiUPArgs, timeout, false)
+	err = m.PullFile(ctx, componentType, clusterID, remotePath, localPath, home, args, timeout)
 	if err != nil {
 		return
 	}
@@ -592,6 +666,28 @@ func (m *Manager) Pull(ctx context.Context, componentType TiUPComponentType, clu
 	return disk.ReadFileContent(localPath)
 }
 
+// PullFile
+// @Description: wrapper of `tiup cluster pull`, the file is kept in localPath
+// @Receiver m
+// @Parameter ctx
+// @Parameter componentType
+// @Parameter clusterID
+// @Parameter remotePath
+// @Parameter localPath
+// @Parameter home
+// @Parameter args
+// @Parameter timeout
+// @return err
+func (m *Manager) PullFile(ctx context.Context, componentType TiUPComponentType, clusterID, remotePath, localPath, home string, args []string, timeout int) (err error) {
+	logInFunc := framework.LogWithContext(ctx)
+
+	tiUPArgs := fmt.Sprintf("%s %s %s %s %s %s %s %d %s", componentType, CMDPull, clusterID, remotePath, localPath, strings.Join(args, " "), FlagWaitTimeout, timeout, CMDYes)
+	logInFunc.Infof("recv operation req: TIUP_HOME=%s %s %s", home, m.TiUPBinPath, tiUPArgs)
+
+	_, err = m.startSyncOperation(home, tiUPArgs, timeout, false)
+	return
+}
+
 // Ctl
 // @Description: wrapper of `tiup ctl:<TiDB version> <TiDB component>`
 // @Receiver m
@@ -646,6 +742,37 @@ func (m *Manager) Exec(ctx context.Context, componentType TiUPComponentType, clu
 	return id, nil
 }
 
+// ExecSync
+// @Description: wrapper of `tiup cluster exec --command`, the command is not split by spaces like other args
+// @Receiver m
+// @Parameter ctx
+// @Parameter componentType
+// @Parameter clusterID
+// @Parameter home
+// @Parameter args
+// @Parameter command
+// @Parameter timeout
+// @return result
+// @return err
+func (m *Manager) ExecSync(ctx context.Context, componentType TiUPComponentType, clusterID, home string, args []string, command string, timeout int) (result string, err error) {
+	logInFunc := framework.LogWithContext(ctx)
+
+	tiUPArgs := append([]string{string(componentType), CMDExec, clusterID}, args...)
+	tiUPArgs = append(tiUPArgs, FlagCommand, command, FlagWaitTimeout, strconv.Itoa(timeout), CMDYes)
+	logInFunc.Infof("recv operation req: TIUP_HOME=%s %s %s", home, m.TiUPBinPath, strings.Join(tiUPArgs, " "))
+
+	cmd, cancelFunc := genCommandWithArgs(home, m.TiUPBinPath, tiUPArgs, timeout)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	defer cancelFunc()
+
+	data, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%s.\ndetail info: %s\n%s", err.Error(), stderr.String(), string(data))
+	}
+	return string(data), nil
+}
+
 // CheckConfig
 // @Description: wrapper of `tiup cluster check topology.yml`
 // @Receiver m
@@ -731,6 +858,40 @@ func (m *Manager) Prune(ctx context.Context, componentType TiUPComponentType, cl
 	return id, nil
 }
 
+// Patch
+// @Description: wrapper of `tiup <component> patch`, replace binaries of the instances by the package
+// @Receiver m
+// @Parameter ctx
+// @Parameter componentType
+// @Parameter clusterID
+// @Parameter packagePath
+// @Parameter home
+// @Parameter workFlowID
+// @Parameter args
+// @Parameter timeout
+// @return ID, operation id to help check the status
+// @return err
+func (m *Manager) Patch(ctx context.Context, componentType TiUPComponentType, clusterID, packagePath, home, workFlowID string, args []string, timeout int) (ID string, err error) {
+	logInFunc := framework.LogWithContext(ctx).WithField("workFlowID", workFlowID)
+
+	tiUPArgs := fmt.Sprintf("%s %s %s %s %s %s %d %s", componentType, CMDPatch, clusterID, packagePath, strings.Join(args, " "), FlagWaitTimeout, timeout, CMDYes)
+	op := fmt.Sprintf("TIUP_HOME=%s %s %s", home, m.TiUPBinPath, tiUPArgs)
+	logInFunc.Infof("recv operation req: %s", op)
+
+	id, err := Create(home, Operation{
+		Type:       CMDPatch,
+		Operation:  op,
+		WorkFlowID: workFlowID,
+		Status:     Init,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	m.startAsyncOperation(ctx, id, home, tiUPArgs, timeout)
+	return id, nil
+}
+
 // GetStatus
 // @Description: get status for async operation
 // @Receiver m
@@ -743,6 +904,32 @@ func (m *Manager) GetStatus(ctx context.Context, ID string) (op Operation, err e
 	return Read(ID)
 }
 
+// Cancel
+// @Description: kill the process group of the async operation and wait for it to exit,
+// nothing is done if the operation is not executed by this process or has exited
+// @Receiver m
+// @Parameter ctx
+// @Parameter ID
+// @return err
+func (m *Manager) Cancel(ctx context.Context, ID string) (err error) {
+	value, ok := runningOperations.Load(ID)
+	if !ok {
+		return nil
+	}
+	running := value.(*runningOperation)
+	framework.LogWithContext(ctx).Infof("cancel operation %s, pid %d", ID, running.cmd.Process.Pid)
+	if err = killProcessGroup(running.cmd); err != nil {
+		return fmt.Errorf("kill operation %s failed, %s", ID, err.Error())
+	}
+
+	select {
+	case <-running.done:
+		return nil
+	case <-time.After(cancelWaitTimeout):
+		return fmt.Errorf("operation %s does not exit in %s after killed", ID, cancelWaitTimeout)
+	}
+}
+
 func (m *Manager) startAsyncOperation(ctx context.Context, id, home, tiUPArgs string, timeoutS int) {
 	go func() {
 		cmd, cancelFunc := genCommand(home, m.TiUPBinPath, tiUPArgs, timeoutS)
@@ -763,6 +950,12 @@ func (m *Manager) startAsyncOperation(ctx context.Context, id, home, tiUPArgs st
 			updateStatus(ctx, id, fmt.Sprintf("operation starts err: %+v. \ndetail info: %s", err, detailInfo), Error, t0)
 			return
 		}
+		running := &runningOperation{cmd: cmd, done: make(chan struct{})}
+		runningOperations.Store(id, running)
+		defer func() {
+			runningOperations.Delete(id)
+			close(running.done)
+		}()
 		updateStatus(ctx, id, "operation processing", Processing, time.Time{})
 
 		err := cmd.Wait()
@@ -812,12 +1005,16 @@ func (m *Manager) startSyncOperation(home, tiUPArgs string, timeoutS int, allInf
 }
 
 func genCommand(home, tiUPBinPath, tiUPArgs string, timeoutS int) (cmd *exec.Cmd, cancelFunc context.CancelFunc) {
+	return genCommandWithArgs(home, tiUPBinPath, strings.Fields(tiUPArgs), timeoutS)
+}
+
+func genCommandWithArgs(home, tiUPBinPath string, tiUPArgs []string, timeoutS int) (cmd *exec.Cmd, cancelFunc context.CancelFunc) {
 	if timeoutS != 0 {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeoutS)*time.Second)
 		cancelFunc = cancel
-		cmd = exec.CommandContext(ctx, tiUPBinPath, strings.Fields(tiUPArgs)...)
+		cmd = exec.CommandContext(ctx, tiUPBinPath, tiUPArgs...)
 	} else {
-		cmd = exec.Command(tiUPBinPath, strings.Fields(tiUPArgs)...)
+		cmd = exec.Command(tiUPBinPath, tiUPArgs...)
 		cancelFunc = func() {}
 	}
 	cmd.Env = os.Environ()
@@ -30,6 +30,7 @@ import (
 	"os"
 	"os/exec"
 	"testing"
+	"time"
 
 	asserts "github.com/stretchr/testify/assert"
 )
@@ -155,6 +156,36 @@ func TestManager_Lightning(t *testing.T) {
 	}
 }
 
+func TestManager_BR(t *testing.T) {
+	_, err := manager.BR(context.TODO(), TestVersion, testTiUPHome, TestWorkFlowID, []string{"restore", "point",
+		"--pd=127.0.0.1:2379",
+		"--storage=local:///tmp/log",
+		"--full-backup-storage=local:///tmp/full",
+		"--restored-ts=434267458936012801"}, 360)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestManager_BRSync(t *testing.T) {
+	_, err := manager.BRSync(context.TODO(), TestVersion, testTiUPHome, []string{"log", "status", "--pd=127.0.0.1:2379", "--json"}, 360)
+	if err == nil {
+		t.Error("nil err")
+	}
+}
+
+func Test_brSubCommand(t *testing.T) {
+	asserts.Equal(t, "log start", brSubCommand([]string{"log", "start", "--storage=s3://bucket/log?secret-access-key=sk"}))
+	asserts.Equal(t, "", brSubCommand([]string{"--version"}))
+}
+
+func TestManager_ExecSync(t *testing.T) {
+	_, err := manager.ExecSync(context.TODO(), TiUPComponentTypeCluster, TestClusterID, testTiUPHome, []string{"-N", "127.0.0.1"}, "ls -l /", 360)
+	if err == nil {
+		t.Error("nil err")
+	}
+}
+
 func TestManager_Push(t *testing.T) {
 	_, err := manager.Push(context.TODO(), TiUPComponentTypeCluster, TestClusterID, TestTiDBPushTopo, "/conf/input_tidb.yml", testTiUPHome, TestWorkFlowID, []string{"-N", "127.0.01"}, 360)
 	if err != nil {
@@ -169,6 +200,13 @@ func TestManager_Pull(t *testing.T) {
 	}
 }
 
+func TestManager_PullFile(t *testing.T) {
+	err := manager.PullFile(context.TODO(), TiUPComponentTypeCluster, TestClusterID, "/remote/path", "/local/path", testTiUPHome, []string{}, 360)
+	if err == nil {
+		t.Error("nil err")
+	}
+}
+
 func TestManager_Ctl(t *testing.T) {
 	_, err := manager.Ctl(context.TODO(), TiUPComponentTypeCluster, TestVersion, "tidb", testTiUPHome, []string{}, 360)
 	if err == nil {
@@ -226,6 +264,13 @@ func TestManager_Prune(t *testing.T) {
 	}
 }
 
+func TestManager_Patch(t *testing.T) {
+	_, err := manager.Patch(context.TODO(), TiUPComponentTypeCluster, TestClusterID, "/tmp/tikv-v5.0.0-linux-amd64.tar.gz", testTiUPHome, TestWorkFlowID, []string{"-N", "127.0.0.1:20160"}, 360)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
 func TestManager_GetStatus(t *testing.T) {
 	_, err := manager.GetStatus(context.TODO(), "")
 	if err == nil {
@@ -248,6 +293,41 @@ func TestManager_startAsyncOperation(t *testing.T) {
 	})
 }
 
+func TestManager_Cancel(t *testing.T) {
+	m := &Manager{
+		TiUPBinPath: "sleep",
+	}
+	t.Run("not running", func(t *testing.T) {
+		asserts.NoError(t, m.Cancel(context.TODO(), "unknown"))
+	})
+	t.Run("running", func(t *testing.T) {
+		id, err := Create(testTiUPHome, Operation{
+			Type:       CMDExec,
+			Operation:  "sleep 60",
+			WorkFlowID: TestWorkFlowID,
+			Status:     Init,
+		})
+		asserts.NoError(t, err)
+		m.startAsyncOperation(context.TODO(), id, testTiUPHome, "60", 0)
+		for i := 0; i < 100; i++ {
+			if _, ok := runningOperations.Load(id); ok {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		t0 := time.Now()
+		asserts.NoError(t, m.Cancel(context.TODO(), id))
+		asserts.True(t, time.Since(t0) < 10*time.Second)
+		op, err := m.GetStatus(context.TODO(), id)
+		asserts.NoError(t, err)
+		asserts.Equal(t, Error, op.Status)
+
+		// exited already
+		asserts.NoError(t, m.Cancel(context.TODO(), id))
+	})
+}
+
 func TestManager_sensitiveCmd(t *testing.T) {
 	t.Run("dumpling", func(t *testing.T) {
 		m := &Manager{
@@ -81,7 +81,10 @@ const (
 	CMDPull         = "pull"
 	CMDCheck        = "check"
 	CMDPrune        = "prune"
+	CMDPatch        = "patch"
+	CMDBR           = "br"
 	FlagWaitTimeout = "--wait-timeout"
+	FlagCommand     = "--command"
 )
 
 var M Interface
@@ -271,6 +274,27 @@ type Interface interface {
 	// @return ID
 	// @return err
 	Lightning(ctx context.Context, home, workFlowID string, args []string, timeout int) (ID string, err error)
+	// BR
+	// @Description: run br of the version asynchronously
+	// @param ctx
+	// @param version
+	// @param home
+	// @param workFlowID
+	// @param args[]
+	// @param timeout
+	// @return ID
+	// @return err
+	BR(ctx context.Context, version, home, workFlowID string, args []string, timeout int) (ID string, err error)
+	// BRSync
+	// @Description: run br of the version and wait for its output
+	// @param ctx
+	// @param version
+	// @param home
+	// @param args[]
+	// @param timeout
+	// @return result
+	// @return err
+	BRSync(ctx context.Context, version, home string, args []string, timeout int) (result string, err error)
 	// Push
 	// @Description:
 	// @param ctx
@@ -297,6 +321,18 @@ type Interface interface {
 	// @return result
 	// @return err
 	Pull(ctx context.Context, componentType TiUPComponentType, clusterID, remotePath, home string, args []string, timeout int) (result string, err error)
+	// PullFile
+	// @Description: pull the file to localPath instead of reading it into memory, the caller removes localPath
+	// @param ctx
+	// @param componentType
+	// @param clusterID
+	// @param remotePath
+	// @param localPath
+	// @param home
+	// @param args[]
+	// @param timeout
+	// @return err
+	PullFile(ctx context.Context, componentType TiUPComponentType, clusterID, remotePath, localPath, home string, args []string, timeout int) (err error)
 	// Ctl
 	// @Description:
 	// @param ctx
@@ -321,6 +357,18 @@ type Interface interface {
 	// @return result
 	// @return err
 	Exec(ctx context.Context, componentType TiUPComponentType, clusterID, home, workFlowID string, args []string, timeout int) (ID string, err error)
+	// ExecSync
+	// @Description: run the shell command on hosts of the cluster and wait for its output
+	// @param ctx
+	// @param componentType
+	// @param clusterID
+	// @param home
+	// @param args[] flags to select hosts, e.g. -N
+	// @param command shell command, it is passed as one argument even if it contains spaces
+	// @param timeout
+	// @return result
+	// @return err
+	ExecSync(ctx context.Context, componentType TiUPComponentType, clusterID, home string, args []string, command string, timeout int) (result string, err error)
 	// CheckConfig
 	// @Description:
 	// @param ctx
@@ -355,6 +403,19 @@ type Interface interface {
 	// @return ID
 	// @return err
 	Prune(ctx context.Context, componentType TiUPComponentType, clusterID, home, workFlowID string, args []string, timeout int) (ID string, err error)
+	// Patch
+	// @Description:
+	// @param ctx
+	// @param componentType
+	// @param clusterID
+	// @param packagePath
+	// @param home
+	// @param workFlowID
+	// @param args
+	// @param timeout
+	// @return ID
+	// @return err
+	Patch(ctx context.Context, componentType TiUPComponentType, clusterID, packagePath, home, workFlowID string, args []string, timeout int) (ID string, err error)
 	// GetStatus
 	// @Description:
 	// @param ctx
@@ -362,4 +423,10 @@ type Interface interface {
 	// @return resp
 	// @return err
 	GetStatus(ctx context.Context, operationID string) (op Operation, err error)
+	// Cancel
+	// @Description: kill the async operation executed by this process and wait for it to exit
+	// @param ctx
+	// @param operationID
+	// @return err
+	Cancel(ctx context.Context, operationID string) (err error)
 }
@@ -107,6 +107,76 @@ func (etcd *EtcdClientV3) Lease() clientv3.Lease {
 	return clientv3.NewLease(etcd.cli)
 }
 
+// GrantLease
+// @Description: grant a lease with ttl seconds
+// @Parameter ttl
+// @return clientv3.LeaseID
+// @return error
+func (etcd *EtcdClientV3) GrantLease(ttl int64) (clientv3.LeaseID, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), etcdTimeOut)
+	defer cancel()
+	resp, err := etcd.cli.Grant(ctx, ttl)
+	if err != nil {
+		return clientv3.NoLease, err
+	}
+	return resp.ID, nil
+}
+
+// KeepAlive
+// @Description: keep the lease alive until ctx is done, the returned channel is closed when the lease is lost
+// @Parameter ctx
+// @Parameter leaseID
+// @return <-chan *clientv3.LeaseKeepAliveResponse
+// @return error
+func (etcd *EtcdClientV3) KeepAlive(ctx context.Context, leaseID clientv3.LeaseID) (<-chan *clientv3.LeaseKeepAliveResponse, error) {
+	return etcd.cli.KeepAlive(ctx, leaseID)
+}
+
+// PutIfAbsent
+// @Description: put key with lease only if the key does not exist
+// @Parameter key
+// @Parameter value
+// @Parameter leaseID
+// @return current value of the key, equals to value if put succeed
+// @return create revision of the key, which increases every time the key is created again
+// @return error
+func (etcd *EtcdClientV3) PutIfAbsent(key, value string, leaseID clientv3.LeaseID) (string, int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), etcdTimeOut)
+	defer cancel()
+	resp, err := etcd.cli.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, value, clientv3.WithLease(leaseID))).
+		Else(clientv3.OpGet(key)).
+		Commit()
+	if err != nil {
+		return "", 0, err
+	}
+	if resp.Succeeded {
+		return value, resp.Header.Revision, nil
+	}
+	kvs := resp.Responses[0].GetResponseRange().Kvs
+	if len(kvs) == 0 {
+		// deleted between compare and get, let caller try again
+		return "", 0, nil
+	}
+	return string(kvs[0].Value), kvs[0].CreateRevision, nil
+}
+
+// DeleteIfValue
+// @Description: delete key only if its value equals to the given value
+// @Parameter key
+// @Parameter value
+// @return error
+func (etcd *EtcdClientV3) DeleteIfValue(key, value string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), etcdTimeOut)
+	defer cancel()
+	_, err := etcd.cli.Txn(ctx).
+		If(clientv3.Compare(clientv3.Value(key), "=", value)).
+		Then(clientv3.OpDelete(key)).
+		Commit()
+	return err
+}
+
 func InitEtcdClientV2(etcdAddress []string) *EtcdClientV2 {
 	// Determine whether to include 'http://'
 	for i, addr := range etcdAddress {
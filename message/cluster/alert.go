@@ -0,0 +1,164 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package cluster
+
+import (
+	"github.com/pingcap/tiunimanager/common/structs"
+)
+
+// QueryFiringAlertsReq Message for querying alerts which are firing in alertmanager of cluster
+type QueryFiringAlertsReq struct {
+	ClusterID string `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	Severity  string `json:"severity" form:"severity" enums:"emergency,critical,warning"`
+}
+
+// QueryFiringAlertsResp Reply message for querying firing alerts
+type QueryFiringAlertsResp struct {
+	Alerts []structs.FiringAlertInfo `json:"alerts"`
+}
+
+// QueryAlertHistoryReq Message for querying alert history of cluster
+type QueryAlertHistoryReq struct {
+	ClusterID string `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	Name      string `json:"name" form:"name" example:"TiDB_server_is_down"`
+	Severity  string `json:"severity" form:"severity" enums:"emergency,critical,warning"`
+	State     string `json:"state" form:"state" enums:"firing,resolved"`
+	StartTime int64  `json:"startTime" form:"startTime" example:"1630468800"`
+	EndTime   int64  `json:"endTime" form:"endTime" example:"1638331200"`
+	structs.PageRequest
+}
+
+// QueryAlertHistoryResp Reply message for querying alert history
+type QueryAlertHistoryResp struct {
+	Alerts []structs.AlertHistoryInfo `json:"alerts"`
+}
+
+// QueryAlertRulesReq Message for querying alert rules of cluster
+type QueryAlertRulesReq struct {
+	ClusterID string `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	Type      string `json:"type" form:"type" enums:"BuiltIn,Custom"`
+	structs.PageRequest
+}
+
+// QueryAlertRulesResp Reply message for querying alert rules
+type QueryAlertRulesResp struct {
+	Rules []structs.AlertRuleInfo `json:"rules"`
+}
+
+// CreateAlertRuleReq Message for adding a custom PromQL alert rule to cluster, the rule is enabled after creating
+type CreateAlertRuleReq struct {
+	ClusterID string `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	Name      string `json:"name" validate:"required,min=1,max=64" example:"TiDB_connection_too_many"`
+	Component string `json:"component" example:"TiDB"`
+	Expr      string `json:"expr" validate:"required" example:"sum(tidb_server_connections) by (instance) > 1000"`
+	For       string `json:"for" example:"5m"`
+	Severity  string `json:"severity" validate:"required" enums:"emergency,critical,warning"`
+	Summary   string `json:"summary" example:"too many connections of TiDB"`
+}
+
+// CreateAlertRuleResp Reply message for adding a custom alert rule
+type CreateAlertRuleResp struct {
+	structs.AsyncTaskWorkFlowInfo
+	Rule structs.AlertRuleInfo `json:"rule"`
+}
+
+// UpdateAlertRuleReq Message for modifying a custom alert rule
+type UpdateAlertRuleReq struct {
+	ClusterID string `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	RuleID    string `json:"ruleId" swaggerignore:"true" validate:"required"`
+	Expr      string `json:"expr" validate:"required" example:"sum(tidb_server_connections) by (instance) > 2000"`
+	For       string `json:"for" example:"5m"`
+	Severity  string `json:"severity" validate:"required" enums:"emergency,critical,warning"`
+	Summary   string `json:"summary"`
+}
+
+// UpdateAlertRuleResp Reply message for modifying a custom alert rule
+type UpdateAlertRuleResp struct {
+	structs.AsyncTaskWorkFlowInfo
+	Rule structs.AlertRuleInfo `json:"rule"`
+}
+
+// SetAlertRuleEnabledReq Message for enabling or disabling an alert rule, both built-in and custom rules are supported
+type SetAlertRuleEnabledReq struct {
+	ClusterID string `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	RuleID    string `json:"ruleId" swaggerignore:"true" validate:"required"`
+	Enabled   bool   `json:"enabled" example:"false"`
+}
+
+// SetAlertRuleEnabledResp Reply message for enabling or disabling an alert rule
+type SetAlertRuleEnabledResp struct {
+	structs.AsyncTaskWorkFlowInfo
+	Rule structs.AlertRuleInfo `json:"rule"`
+}
+
+// DeleteAlertRuleReq Message for deleting a custom alert rule
+type DeleteAlertRuleReq struct {
+	ClusterID string `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	RuleID    string `json:"ruleId" swaggerignore:"true" validate:"required"`
+}
+
+// DeleteAlertRuleResp Reply message for deleting a custom alert rule
+type DeleteAlertRuleResp struct {
+	structs.AsyncTaskWorkFlowInfo
+	RuleID string `json:"ruleId"`
+}
+
+// CreateAlertSilenceReq Message for silencing alerts of cluster which match all the matchers during a period
+type CreateAlertSilenceReq struct {
+	ClusterID string                 `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	Matchers  []structs.AlertMatcher `json:"matchers" validate:"required,min=1,dive"`
+	StartTime int64                  `json:"startTime" example:"1630468800"` // now if it is empty
+	EndTime   int64                  `json:"endTime" validate:"required" example:"1638331200"`
+	Comment   string                 `json:"comment" example:"upgrade tikv"`
+}
+
+// CreateAlertSilenceResp Reply message for silencing alerts
+type CreateAlertSilenceResp struct {
+	Silence structs.AlertSilenceInfo `json:"silence"`
+}
+
+// QueryAlertSilencesReq Message for querying alert silences of cluster
+type QueryAlertSilencesReq struct {
+	ClusterID string `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	Status    string `json:"status" form:"status" enums:"Active,Expired"`
+	structs.PageRequest
+}
+
+// QueryAlertSilencesResp Reply message for querying alert silences
+type QueryAlertSilencesResp struct {
+	Silences []structs.AlertSilenceInfo `json:"silences"`
+}
+
+// DeleteAlertSilenceReq Message for expiring an alert silence before its end time
+type DeleteAlertSilenceReq struct {
+	ClusterID string `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	SilenceID string `json:"silenceId" swaggerignore:"true" validate:"required"`
+}
+
+// DeleteAlertSilenceResp Reply message for expiring an alert silence
+type DeleteAlertSilenceResp struct {
+	SilenceID string `json:"silenceId"`
+}
+
+// ConfigureAlertReceiverReq Message for configuring alertmanager of cluster to send alerts to the platform
+type ConfigureAlertReceiverReq struct {
+	ClusterID string `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+}
+
+// ConfigureAlertReceiverResp Reply message for configuring alert receiver of cluster
+type ConfigureAlertReceiverResp struct {
+	structs.AsyncTaskWorkFlowInfo
+}
@@ -96,6 +96,78 @@ type GetBackupStrategyResp struct {
 	Strategy structs.BackupStrategy `json:"strategy"`
 }
 
+// PruneBackupRecordsReq Request to prune expired auto backups by retention rules of backup strategy
+type PruneBackupRecordsReq struct {
+	ClusterID string `json:"clusterId" swaggerignore:"true"`
+	DryRun    bool   `json:"dryRun"` // only report backups to be pruned
+}
+
+// PruneBackupRecordsResp prune expired backups reply message
+type PruneBackupRecordsResp struct {
+	PrunedRecords []*structs.BackupRecord `json:"prunedRecords"`
+	// records whose files are failed to be removed, they are kept and pruned again by the next run
+	FailedRecords []*structs.BackupRecord `json:"failedRecords"`
+}
+
+// VerifyBackupReq Request to verify integrity of a finished backup
+type VerifyBackupReq struct {
+	BackupID string `json:"backupId" swaggerignore:"true"`
+	// optional, restore the backup into a temporary cluster created by the parameters to make sure it is restorable,
+	// the cluster is destroyed when the verification finishes
+	TestRestoreCluster *TestRestoreClusterParameter `json:"testRestoreCluster"`
+}
+
+// TestRestoreClusterParameter parameters of the temporary cluster to test restore a backup
+type TestRestoreClusterParameter struct {
+	structs.CreateClusterParameter
+	ResourceParameter structs.ClusterResourceInfo `json:"resourceParameters"`
+}
+
+// VerifyBackupResp verify backup reply message
+type VerifyBackupResp struct {
+	structs.AsyncTaskWorkFlowInfo
+}
+
+// StartLogBackupReq Request to start continuous log backup of a cluster
+type StartLogBackupReq struct {
+	ClusterID string `json:"clusterId" swaggerignore:"true"`
+}
+
+// StartLogBackupResp start log backup reply message
+type StartLogBackupResp struct {
+	TaskID string `json:"taskId"`
+}
+
+// StopLogBackupReq Request to stop continuous log backup of a cluster
+type StopLogBackupReq struct {
+	ClusterID string `json:"clusterId" swaggerignore:"true"`
+}
+
+// StopLogBackupResp stop log backup reply message
+type StopLogBackupResp struct {
+}
+
+// QueryLogBackupReq Request to query log backup task of a cluster
+type QueryLogBackupReq struct {
+	ClusterID string `json:"clusterId" form:"clusterId" swaggerignore:"true"`
+}
+
+// QueryLogBackupResp query log backup task reply message
+type QueryLogBackupResp struct {
+	Task structs.LogBackupTask `json:"task"`
+}
+
+// TruncateLogBackupReq Request to truncate backed up logs before a point in time
+type TruncateLogBackupReq struct {
+	ClusterID string `json:"clusterId" swaggerignore:"true"`
+	UntilTime int64  `json:"untilTime"` // unix timestamp in seconds
+	UntilTSO  string `json:"untilTso"`  // takes precedence over untilTime
+}
+
+// TruncateLogBackupResp truncate log backup reply message
+type TruncateLogBackupResp struct {
+}
+
 // DeleteBackupStrategyReq Request to delete backup data strategy
 type DeleteBackupStrategyReq struct {
 	ClusterID string `json:"clusterId"`
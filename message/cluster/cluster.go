@@ -31,6 +31,7 @@ import (
 type CreateClusterReq struct {
 	structs.CreateClusterParameter
 	ResourceParameter structs.ClusterResourceInfo `json:"resourceParameters" form:"resourceParameters"`
+	DeferFailure      bool                        `json:"deferFailure" form:"deferFailure"` // keep allocated resources after failure until the workflow is resumed or abandoned
 }
 
 // CreateClusterResp Reply message for creating a new cluster
@@ -45,6 +46,8 @@ type DeleteClusterReq struct {
 	AutoBackup               bool   `json:"autoBackup" form:"autoBackup"`
 	KeepHistoryBackupRecords bool   `json:"keepHistoryBackupRecords" form:"keepHistoryBackupRecords"`
 	Force                    bool   `json:"force" form:"force"`
+	SoftDelete               bool   `json:"softDelete" form:"softDelete"`                                   // stop the cluster and keep it in the recycle bin, it can be recovered before purged
+	RetentionHours           int    `json:"retentionHours" form:"retentionHours" validate:"min=0,max=8760"` // hours to keep the soft deleted cluster, 0 means the system default
 }
 
 // DeleteClusterResp Reply message for delete a cluster
@@ -67,12 +70,14 @@ type StopClusterResp struct {
 // RestartClusterReq Message for restart a new cluster
 type RestartClusterReq struct {
 	ClusterID string `json:"clusterId" validate:"required,min=4,max=64"`
+	structs.MaintainWindowOption
 }
 
 // RestartClusterResp Reply message for restart a new cluster
 type RestartClusterResp struct {
 	structs.AsyncTaskWorkFlowInfo
-	ClusterID string `json:"clusterId"`
+	ClusterID          string `json:"clusterId"`
+	PendingOperationID string `json:"pendingOperationId,omitempty"` // restart is queued until the maintain window opens
 }
 
 // ScaleInClusterReq Message for delete an instance in the cluster
@@ -97,6 +102,7 @@ type PreviewScaleOutClusterReq struct {
 type ScaleOutClusterReq struct {
 	ClusterID string `json:"clusterId" form:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
 	structs.ClusterResourceInfo
+	DeferFailure bool `json:"deferFailure" form:"deferFailure"` // keep allocated resources after failure until the workflow is resumed or abandoned
 }
 
 // ScaleOutClusterResp Reply message for cluster expansion operation
@@ -105,11 +111,24 @@ type ScaleOutClusterResp struct {
 	ClusterID string `json:"clusterId"`
 }
 
+// RestorePoint Point in time to restore, data is restored from the nearest full backup before it and then the logs up to it
+type RestorePoint struct {
+	SourceClusterID string `json:"sourceClusterId" validate:"max=64"` // cluster whose backups are used, default the restored cluster
+	RestoreTime     int64  `json:"restoreTime"`                       // unix timestamp in seconds
+	RestoreTSO      string `json:"restoreTso"`                        // takes precedence over restoreTime
+}
+
+// IsSet whether a point in time is specified
+func (p RestorePoint) IsSet() bool {
+	return p.RestoreTSO != "" || p.RestoreTime > 0
+}
+
 //RestoreNewClusterReq Restore to a new cluster message using the backup file
 type RestoreNewClusterReq struct {
 	structs.CreateClusterParameter
-	BackupID          string                      `json:"backupId" validate:"required,min=8,max=64"`
+	BackupID          string                      `json:"backupId" validate:"max=64"` // required if restore point is not set
 	ResourceParameter structs.ClusterResourceInfo `json:"resourceParameters"`
+	RestorePoint
 }
 
 //RestoreNewClusterResp Restore to a new cluster using the backup file Reply Message
@@ -121,7 +140,8 @@ type RestoreNewClusterResp struct {
 //RestoreExistClusterReq Restore to exist cluster message using the backup file
 type RestoreExistClusterReq struct {
 	ClusterID string `json:"clusterID" validate:"required,min=4,max=64"`
-	BackupID  string `json:"backupID" validate:"required,min=8,max=64"`
+	BackupID  string `json:"backupID" validate:"max=64"` // required if restore point is not set
+	RestorePoint
 }
 
 //RestoreExistClusterResp Restore to exist cluster using the backup file Reply Message
@@ -135,6 +155,7 @@ type CloneClusterReq struct {
 	ResourceParameter structs.ClusterResourceInfo `json:"resourceParameters" form:"resourceParameters"`
 	CloneStrategy     string                      `json:"cloneStrategy" validate:"required"`                // specify clone strategy, include empty, snapshot and sync, default empty(option)
 	SourceClusterID   string                      `json:"sourceClusterId" validate:"required,min=4,max=64"` // specify source cluster id(require)
+	DeferFailure      bool                        `json:"deferFailure"`                                     // keep allocated resources after failure until the workflow is resumed or abandoned
 }
 
 // CloneClusterResp Reply message for clone a new cluster
@@ -203,6 +224,8 @@ type QueryClustersReq struct {
 	Type      string `json:"clusterType" form:"clusterType"`
 	Status    string `json:"clusterStatus" form:"clusterStatus"`
 	Tag       string `json:"clusterTag" form:"clusterTag"`
+	// TagSelector eg. env=prod,team in (dba,ops),region!=east,backup,!legacy
+	TagSelector string `json:"tagSelector" form:"tagSelector"`
 }
 
 // QueryClusterResp Query the cluster list to reply to messages
@@ -281,11 +304,13 @@ type UpdateClusterParametersReq struct {
 	Params    []structs.ClusterParameterSampleInfo `json:"params" validate:"required"`
 	Reboot    bool                                 `json:"reboot"`
 	Nodes     []string                             `json:"nodes" swaggerignore:"true"`
+	structs.MaintainWindowOption
 }
 
 type UpdateClusterParametersResp struct {
 	ClusterID string `json:"clusterId" example:"1"`
 	structs.AsyncTaskWorkFlowInfo
+	PendingOperationID string `json:"pendingOperationId,omitempty"`
 }
 
 type InspectParametersReq struct {
@@ -351,3 +376,118 @@ type ApiShowConfigReq struct {
 	Params       map[string]string
 	Headers      map[string]string
 }
+
+// QueryPendingOperationsReq Message for querying operations waiting for the maintain window of cluster
+type QueryPendingOperationsReq struct {
+	ClusterID string `json:"clusterId" form:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	Status    string `json:"status" form:"status" enums:"Waiting,Launched,Failed,Canceled"`
+}
+
+// QueryPendingOperationsResp Reply message for querying pending operations
+type QueryPendingOperationsResp struct {
+	Operations []structs.PendingOperationInfo `json:"operations"`
+}
+
+// CancelPendingOperationReq Message for canceling an operation waiting for the maintain window
+type CancelPendingOperationReq struct {
+	ClusterID   string `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	OperationID string `json:"operationId" swaggerignore:"true" validate:"required"`
+}
+
+// CancelPendingOperationResp Reply message for canceling a pending operation
+type CancelPendingOperationResp struct {
+}
+
+// AddClusterTagsReq Message for adding tags to cluster, tag with an existing key overwrites the value
+type AddClusterTagsReq struct {
+	ClusterID string   `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	Tags      []string `json:"tags" validate:"required,min=1" example:"env=prod,backup"`
+}
+
+// AddClusterTagsResp Reply message for adding tags to cluster
+type AddClusterTagsResp struct {
+	ClusterID string   `json:"clusterId"`
+	Tags      []string `json:"tags"`
+}
+
+// RemoveClusterTagsReq Message for removing tags of cluster by key
+type RemoveClusterTagsReq struct {
+	ClusterID string   `json:"clusterId" form:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	Keys      []string `json:"keys" form:"keys" validate:"required,min=1"`
+}
+
+// RemoveClusterTagsResp Reply message for removing tags of cluster
+type RemoveClusterTagsResp struct {
+	ClusterID string   `json:"clusterId"`
+	Tags      []string `json:"tags"`
+}
+
+// UpdateClusterProfileReq Message for updating profile of cluster, all fields are replaced
+type UpdateClusterProfileReq struct {
+	ClusterID      string   `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	Name           string   `json:"clusterName" validate:"required,min=4,max=64"`
+	Tags           []string `json:"tags" example:"env=prod,backup"`
+	Exclusive      bool     `json:"exclusive"` // only affects instances allocated later, eg. scaling out
+	MaintainWindow string   `json:"maintainWindow" validate:"max=256" example:"Saturday,Sunday 02:00-06:00 UTC"` // time zone is UTC if omitted
+}
+
+// UpdateClusterProfileResp Reply message for updating profile of cluster
+type UpdateClusterProfileResp struct {
+	Info structs.ClusterInfo `json:"info"`
+}
+
+// SetDeletionProtectionReq Message for enabling or disabling deletion protection of cluster
+type SetDeletionProtectionReq struct {
+	ClusterID string `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	Enabled   bool   `json:"enabled"`
+}
+
+// SetDeletionProtectionResp Reply message for setting deletion protection of cluster
+type SetDeletionProtectionResp struct {
+	ClusterID          string `json:"clusterId"`
+	DeletionProtection bool   `json:"deletionProtection"`
+}
+
+// QueryRecycledClustersReq Message for querying soft deleted clusters in the recycle bin
+type QueryRecycledClustersReq struct {
+	Status string `json:"status" form:"status" enums:"Recycling,Recycled,Recovered,Purged,Failed"`
+	structs.PageRequest
+}
+
+// QueryRecycledClustersResp Reply message for querying the recycle bin
+type QueryRecycledClustersResp struct {
+	Clusters []structs.RecycledClusterInfo `json:"clusters"`
+}
+
+// RecoverClusterReq Message for recovering a soft deleted cluster from the recycle bin
+type RecoverClusterReq struct {
+	ClusterID string `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+}
+
+// RecoverClusterResp Reply message for recovering a cluster, the cluster is started by the workflow
+type RecoverClusterResp struct {
+	structs.AsyncTaskWorkFlowInfo
+	ClusterID string `json:"clusterId"`
+}
+
+// QueryClusterMetricsReq Message to query metrics of a cluster from its prometheus, either a named metric or a raw PromQL is required
+type QueryClusterMetricsReq struct {
+	ClusterID string `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	Name      string `json:"name" form:"name" enums:"qps,latency_p999,latency_p99,latency_p95,latency_p80,storage_used,storage_capacity,cpu_by_component"`
+	Query     string `json:"query" form:"query" example:"sum(rate(tidb_server_query_total[1m]))"`
+	StartTime int64  `json:"startTime" form:"startTime" example:"1638331200"`
+	EndTime   int64  `json:"endTime" form:"endTime" example:"1638334800"`
+	Step      int64  `json:"step" form:"step" example:"15"`
+}
+
+// QueryClusterMetricsResp Reply message for querying metrics of a cluster, timestamps and step are in seconds
+type QueryClusterMetricsResp struct {
+	ClusterID string                 `json:"clusterId" example:"abc"`
+	Name      string                 `json:"name"`
+	Query     string                 `json:"query"`
+	Unit      string                 `json:"unit" example:"seconds"`
+	StartTime int64                  `json:"startTime" example:"1638331200"`
+	EndTime   int64                  `json:"endTime" example:"1638334800"`
+	Step      int64                  `json:"step" example:"15"`
+	Series    []structs.MetricSeries `json:"series"`
+}
@@ -0,0 +1,34 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package cluster
+
+import (
+	"github.com/pingcap/tiunimanager/common/structs"
+)
+
+// QueryClusterHealthEventsReq Message for querying status changes of cluster and its instances, time range is in seconds
+type QueryClusterHealthEventsReq struct {
+	ClusterID  string `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	InstanceID string `json:"instanceId" form:"instanceId"`
+	StartTime  int64  `json:"startTime" form:"startTime" example:"1630468800"`
+	EndTime    int64  `json:"endTime" form:"endTime" example:"1638331200"`
+	structs.PageRequest
+}
+
+// QueryClusterHealthEventsResp Reply message for querying health events of cluster
+type QueryClusterHealthEventsResp struct {
+	Events []structs.ClusterHealthEventInfo `json:"events"`
+}
@@ -0,0 +1,93 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package cluster
+
+import "github.com/pingcap/tiunimanager/common/structs"
+
+// StartInstanceReq Message for starting an instance of cluster
+type StartInstanceReq struct {
+	ClusterID  string `json:"clusterId" validate:"required,min=4,max=64"`
+	InstanceID string `json:"instanceId" validate:"required"`
+}
+
+// StartInstanceResp Reply message for starting an instance of cluster
+type StartInstanceResp struct {
+	structs.AsyncTaskWorkFlowInfo
+	ClusterID  string `json:"clusterId"`
+	InstanceID string `json:"instanceId"`
+}
+
+// StopInstanceReq Message for stopping an instance of cluster
+type StopInstanceReq struct {
+	ClusterID  string `json:"clusterId" validate:"required,min=4,max=64"`
+	InstanceID string `json:"instanceId" validate:"required"`
+	structs.MaintainWindowOption
+}
+
+// StopInstanceResp Reply message for stopping an instance of cluster
+type StopInstanceResp struct {
+	structs.AsyncTaskWorkFlowInfo
+	ClusterID          string `json:"clusterId"`
+	InstanceID         string `json:"instanceId"`
+	PendingOperationID string `json:"pendingOperationId,omitempty"` // stop is queued until the maintain window opens
+}
+
+// RestartInstanceReq Message for restarting an instance of cluster
+type RestartInstanceReq struct {
+	ClusterID  string `json:"clusterId" validate:"required,min=4,max=64"`
+	InstanceID string `json:"instanceId" validate:"required"`
+	structs.MaintainWindowOption
+}
+
+// RestartInstanceResp Reply message for restarting an instance of cluster
+type RestartInstanceResp struct {
+	structs.AsyncTaskWorkFlowInfo
+	ClusterID          string `json:"clusterId"`
+	InstanceID         string `json:"instanceId"`
+	PendingOperationID string `json:"pendingOperationId,omitempty"` // restart is queued until the maintain window opens
+}
+
+// ReloadInstanceReq Message for reloading config of an instance of cluster, the instance is restarted
+type ReloadInstanceReq struct {
+	ClusterID  string `json:"clusterId" validate:"required,min=4,max=64"`
+	InstanceID string `json:"instanceId" validate:"required"`
+	structs.MaintainWindowOption
+}
+
+// ReloadInstanceResp Reply message for reloading an instance of cluster
+type ReloadInstanceResp struct {
+	structs.AsyncTaskWorkFlowInfo
+	ClusterID          string `json:"clusterId"`
+	InstanceID         string `json:"instanceId"`
+	PendingOperationID string `json:"pendingOperationId,omitempty"` // reload is queued until the maintain window opens
+}
+
+// MigrateInstanceReq Message for migrating an instance of cluster to another host,
+// the target host is allocated in the zone of the instance if neither target host nor target zone is specified
+type MigrateInstanceReq struct {
+	ClusterID    string `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	InstanceID   string `json:"instanceId" swaggerignore:"true" validate:"required"`
+	TargetHostIP string `json:"targetHostIp" example:"172.16.1.10"`
+	TargetZone   string `json:"targetZone" example:"Region1,Zone1"`
+	DiskID       string `json:"diskId"` // disk of the target host, optional
+}
+
+// MigrateInstanceResp Reply message for migrating an instance of cluster
+type MigrateInstanceResp struct {
+	structs.AsyncTaskWorkFlowInfo
+	ClusterID  string `json:"clusterId"`
+	InstanceID string `json:"instanceId"`
+}
@@ -0,0 +1,137 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package cluster
+
+import (
+	"github.com/pingcap/tiunimanager/common/structs"
+)
+
+// QueryTopSQLReq Message for querying top SQL of cluster from statements summary history, time range is in seconds
+type QueryTopSQLReq struct {
+	ClusterID string `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	OrderBy   string `json:"orderBy" form:"orderBy" enums:"latency,executions,scanned_keys"`
+	StartTime int64  `json:"startTime" form:"startTime" example:"1638331200"`
+	EndTime   int64  `json:"endTime" form:"endTime" example:"1638333000"`
+	Limit     int    `json:"limit" form:"limit" example:"20"`
+}
+
+// QueryTopSQLResp Reply message for querying top SQL
+type QueryTopSQLResp struct {
+	ClusterID  string                  `json:"clusterId"`
+	OrderBy    string                  `json:"orderBy"`
+	StartTime  int64                   `json:"startTime"`
+	EndTime    int64                   `json:"endTime"`
+	Statements []structs.StatementInfo `json:"statements"`
+}
+
+// QueryStatementPlansReq Message for querying execution plans of a statement digest
+type QueryStatementPlansReq struct {
+	ClusterID string `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	Digest    string `json:"digest" swaggerignore:"true" validate:"required"`
+	StartTime int64  `json:"startTime" form:"startTime" example:"1638331200"`
+	EndTime   int64  `json:"endTime" form:"endTime" example:"1638333000"`
+}
+
+// QueryStatementPlansResp Reply message for querying execution plans of a statement digest
+type QueryStatementPlansResp struct {
+	ClusterID string                      `json:"clusterId"`
+	Digest    string                      `json:"digest"`
+	StartTime int64                       `json:"startTime"`
+	EndTime   int64                       `json:"endTime"`
+	Plans     []structs.StatementPlanInfo `json:"plans"`
+}
+
+// QuerySlowQueriesReq Message for querying slow query log of cluster, empty digest means all statements
+type QuerySlowQueriesReq struct {
+	ClusterID string `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	Digest    string `json:"digest" form:"digest"`
+	OrderBy   string `json:"orderBy" form:"orderBy" enums:"latency,scanned_keys"`
+	StartTime int64  `json:"startTime" form:"startTime" example:"1638331200"`
+	EndTime   int64  `json:"endTime" form:"endTime" example:"1638333000"`
+	Limit     int    `json:"limit" form:"limit" example:"20"`
+}
+
+// QuerySlowQueriesResp Reply message for querying slow query log
+type QuerySlowQueriesResp struct {
+	ClusterID   string                  `json:"clusterId"`
+	StartTime   int64                   `json:"startTime"`
+	EndTime     int64                   `json:"endTime"`
+	SlowQueries []structs.SlowQueryInfo `json:"slowQueries"`
+}
+
+// CreatePerformanceSnapshotReq Message for capturing top SQL of cluster within a time range as a snapshot
+type CreatePerformanceSnapshotReq struct {
+	ClusterID string `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	Name      string `json:"name" validate:"max=64" example:"before upgrade"`
+	StartTime int64  `json:"startTime" example:"1638331200"`
+	EndTime   int64  `json:"endTime" example:"1638333000"`
+}
+
+// CreatePerformanceSnapshotResp Reply message for creating a performance snapshot
+type CreatePerformanceSnapshotResp struct {
+	Snapshot structs.PerformanceSnapshotInfo `json:"snapshot"`
+}
+
+// QueryPerformanceSnapshotsReq Message for querying performance snapshots of cluster
+type QueryPerformanceSnapshotsReq struct {
+	ClusterID string `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	Type      string `json:"type" form:"type" enums:"Periodic,Manual"`
+	structs.PageRequest
+}
+
+// QueryPerformanceSnapshotsResp Reply message for querying performance snapshots
+type QueryPerformanceSnapshotsResp struct {
+	Snapshots []structs.PerformanceSnapshotInfo `json:"snapshots"`
+}
+
+// GetPerformanceSnapshotReq Message for getting a performance snapshot with its statements
+type GetPerformanceSnapshotReq struct {
+	ClusterID  string `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	SnapshotID string `json:"snapshotId" swaggerignore:"true" validate:"required"`
+}
+
+// GetPerformanceSnapshotResp Reply message for getting a performance snapshot
+type GetPerformanceSnapshotResp struct {
+	Snapshot   structs.PerformanceSnapshotInfo `json:"snapshot"`
+	Statements []structs.StatementInfo         `json:"statements"`
+}
+
+// DeletePerformanceSnapshotReq Message for deleting a performance snapshot
+type DeletePerformanceSnapshotReq struct {
+	ClusterID  string `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	SnapshotID string `json:"snapshotId" swaggerignore:"true" validate:"required"`
+}
+
+// DeletePerformanceSnapshotResp Reply message for deleting a performance snapshot
+type DeletePerformanceSnapshotResp struct {
+	SnapshotID string `json:"snapshotId"`
+}
+
+// ComparePerformanceSnapshotsReq Message for comparing statements of a target snapshot with a base snapshot,
+// for example snapshots before and after a change
+type ComparePerformanceSnapshotsReq struct {
+	ClusterID        string `json:"clusterId" swaggerignore:"true" validate:"required,min=4,max=64"`
+	TargetSnapshotID string `json:"targetSnapshotId" swaggerignore:"true" validate:"required"`
+	BaseSnapshotID   string `json:"baseSnapshotId" form:"baseSnapshotId" validate:"required"`
+}
+
+// ComparePerformanceSnapshotsResp Reply message for comparing performance snapshots,
+// statements are in order of absolute diff of total latency desc
+type ComparePerformanceSnapshotsResp struct {
+	Base       structs.PerformanceSnapshotInfo `json:"base"`
+	Target     structs.PerformanceSnapshotInfo `json:"target"`
+	Statements []structs.StatementComparison   `json:"statements"`
+}
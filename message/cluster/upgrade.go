@@ -47,6 +47,35 @@ type QueryUpgradeVersionDiffInfoResp struct {
 	ConfigDiffInfos []*structs.ProductUpgradeVersionConfigDiffItem `json:"configDiffInfos"`
 }
 
+// QueryUpgradeCheckReportReq Message for checking whether the cluster is ready to upgrade to target version
+type QueryUpgradeCheckReportReq struct {
+	ClusterID     string `json:"clusterId" swaggerignore:"true"`
+	TargetVersion string `form:"targetVersion" validate:"required" example:"v5.0.0"`
+	UpgradeWay    string `form:"upgradeWay" enums:"offline,online"`
+}
+
+// QueryUpgradeCheckReportResp Reply message for checking whether the cluster is ready to upgrade to target version
+type QueryUpgradeCheckReportResp struct {
+	ClusterID         string                     `json:"clusterId"`
+	TargetVersion     string                     `json:"targetVersion"`
+	Passed            bool                       `json:"passed"`
+	EstimatedDuration int64                      `json:"estimatedDuration" example:"600"` // in seconds
+	Items             []structs.UpgradeCheckItem `json:"items"`
+}
+
+// RollbackUpgradeReq Message for rolling back the latest upgrade of cluster
+type RollbackUpgradeReq struct {
+	ClusterID string `json:"clusterId" swaggerignore:"true"`
+}
+
+// RollbackUpgradeResp Reply message for rolling back the latest upgrade of cluster
+type RollbackUpgradeResp struct {
+	structs.AsyncTaskWorkFlowInfo
+	ClusterID string                        `json:"clusterId"`
+	Version   string                        `json:"version" example:"v5.3.0"`
+	Instances []structs.UpgradeRollbackItem `json:"instances"`
+}
+
 // UpgradeClusterReq Message for requesting upgrade
 type UpgradeClusterReq struct {
 	ClusterID     string `json:"clusterId" swaggerignore:"true"`
@@ -54,10 +83,61 @@ type UpgradeClusterReq struct {
 	UpgradeType   string `json:"upgradeType"  validate:"required" enums:"in-place,migration"`
 	UpgradeWay    string `json:"upgradeWay"  enums:"offline,online"`
 	Configs       []*structs.ClusterUpgradeVersionConfigItem
+	structs.MaintainWindowOption
 }
 
 // UpgradeClusterResp Reply message for requesting upgrade
 type UpgradeClusterResp struct {
 	structs.AsyncTaskWorkFlowInfo
-	ClusterID string `json:"clusterId"`
+	ClusterID          string `json:"clusterId"`
+	PendingOperationID string `json:"pendingOperationId,omitempty"` // offline upgrade is queued until the maintain window opens
+}
+
+// CreateUpgradeCampaignReq Message for upgrading selected clusters in ordered waves
+type CreateUpgradeCampaignReq struct {
+	Name             string                          `json:"name" example:"upgrade to v5.4.0"`
+	TargetVersion    string                          `json:"targetVersion" validate:"required" example:"v5.4.0"`
+	UpgradeWay       string                          `json:"upgradeWay" enums:"offline,online"`
+	Selector         structs.UpgradeCampaignSelector `json:"selector"`
+	WaveSizes        []int                           `json:"waveSizes" example:"1,5,20"` // cluster count of each wave, the last one is used by the rest waves
+	Concurrency      int                             `json:"concurrency" example:"2"`    // max count of clusters upgrading at the same time
+	FailureThreshold float64                         `json:"failureThreshold" example:"0.2"`
+}
+
+// CreateUpgradeCampaignResp Reply message for creating upgrade campaign
+type CreateUpgradeCampaignResp struct {
+	Campaign structs.UpgradeCampaignInfo          `json:"campaign"`
+	Clusters []structs.UpgradeCampaignClusterInfo `json:"clusters"`
+}
+
+// QueryUpgradeCampaignsReq Message for querying upgrade campaigns
+type QueryUpgradeCampaignsReq struct {
+	Status string `json:"status" form:"status" enums:"Running,Halted,Canceled,Completed"`
+	structs.PageRequest
+}
+
+// QueryUpgradeCampaignsResp Reply message for querying upgrade campaigns
+type QueryUpgradeCampaignsResp struct {
+	Campaigns []structs.UpgradeCampaignInfo `json:"campaigns"`
+}
+
+// GetUpgradeCampaignReq Message for getting upgrade campaign and its clusters
+type GetUpgradeCampaignReq struct {
+	CampaignID string `json:"campaignId" swaggerignore:"true"`
+}
+
+// GetUpgradeCampaignResp Reply message for getting upgrade campaign and its clusters
+type GetUpgradeCampaignResp struct {
+	Campaign structs.UpgradeCampaignInfo          `json:"campaign"`
+	Clusters []structs.UpgradeCampaignClusterInfo `json:"clusters"`
+}
+
+// CancelUpgradeCampaignReq Message for canceling upgrade campaign, clusters being upgraded are not affected
+type CancelUpgradeCampaignReq struct {
+	CampaignID string `json:"campaignId" swaggerignore:"true"`
+}
+
+// CancelUpgradeCampaignResp Reply message for canceling upgrade campaign
+type CancelUpgradeCampaignResp struct {
+	CampaignID string `json:"campaignId"`
 }
@@ -0,0 +1,155 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package message
+
+import (
+	"github.com/pingcap/tiunimanager/common/structs"
+)
+
+// CreateNotificationChannelReq create notification channel request
+type CreateNotificationChannelReq struct {
+	Name   string                            `json:"name" validate:"required,min=1,max=64" example:"dba-webhook"`
+	Type   string                            `json:"type" validate:"required" enums:"Webhook,Email,Slack"`
+	Config structs.NotificationChannelConfig `json:"config"`
+}
+
+// CreateNotificationChannelResp create notification channel response
+type CreateNotificationChannelResp struct {
+	Channel structs.NotificationChannelInfo `json:"channel"`
+}
+
+// UpdateNotificationChannelReq update name and config of notification channel, type is immutable
+type UpdateNotificationChannelReq struct {
+	ChannelID string                            `json:"channelId" swaggerignore:"true" validate:"required"`
+	Name      string                            `json:"name" validate:"required,min=1,max=64" example:"dba-webhook"`
+	Config    structs.NotificationChannelConfig `json:"config"`
+}
+
+// UpdateNotificationChannelResp update notification channel response
+type UpdateNotificationChannelResp struct {
+	Channel structs.NotificationChannelInfo `json:"channel"`
+}
+
+// DeleteNotificationChannelReq delete notification channel request, channels used by routes can not be deleted
+type DeleteNotificationChannelReq struct {
+	ChannelID string `json:"channelId" swaggerignore:"true" validate:"required"`
+}
+
+// DeleteNotificationChannelResp delete notification channel response
+type DeleteNotificationChannelResp struct {
+	ChannelID string `json:"channelId"`
+}
+
+// QueryNotificationChannelsReq query notification channels request
+type QueryNotificationChannelsReq struct {
+	Type string `json:"type" form:"type" enums:"Webhook,Email,Slack"`
+	structs.PageRequest
+}
+
+// QueryNotificationChannelsResp query notification channels response
+type QueryNotificationChannelsResp struct {
+	Channels []structs.NotificationChannelInfo `json:"channels"`
+}
+
+// TestNotificationChannelReq send a test notification to channel
+type TestNotificationChannelReq struct {
+	ChannelID string `json:"channelId" swaggerignore:"true" validate:"required"`
+}
+
+// TestNotificationChannelResp test notification channel response
+type TestNotificationChannelResp struct {
+	Delivery structs.NotificationDeliveryInfo `json:"delivery"`
+}
+
+// CreateNotificationRouteReq create notification route request
+type CreateNotificationRouteReq struct {
+	Name        string   `json:"name" validate:"required,min=1,max=64" example:"prod-critical"`
+	Severities  []string `json:"severities" example:"emergency,critical"`
+	TagSelector string   `json:"tagSelector" example:"env=prod"`
+	TenantID    string   `json:"tenantId"`
+	ChannelIDs  []string `json:"channelIds" validate:"required,min=1"`
+}
+
+// CreateNotificationRouteResp create notification route response
+type CreateNotificationRouteResp struct {
+	Route structs.NotificationRouteInfo `json:"route"`
+}
+
+// UpdateNotificationRouteReq update notification route request
+type UpdateNotificationRouteReq struct {
+	RouteID     string   `json:"routeId" swaggerignore:"true" validate:"required"`
+	Name        string   `json:"name" validate:"required,min=1,max=64" example:"prod-critical"`
+	Severities  []string `json:"severities" example:"emergency,critical"`
+	TagSelector string   `json:"tagSelector" example:"env=prod"`
+	TenantID    string   `json:"tenantId"`
+	ChannelIDs  []string `json:"channelIds" validate:"required,min=1"`
+}
+
+// UpdateNotificationRouteResp update notification route response
+type UpdateNotificationRouteResp struct {
+	Route structs.NotificationRouteInfo `json:"route"`
+}
+
+// DeleteNotificationRouteReq delete notification route request
+type DeleteNotificationRouteReq struct {
+	RouteID string `json:"routeId" swaggerignore:"true" validate:"required"`
+}
+
+// DeleteNotificationRouteResp delete notification route response
+type DeleteNotificationRouteResp struct {
+	RouteID string `json:"routeId"`
+}
+
+// QueryNotificationRoutesReq query notification routes request
+type QueryNotificationRoutesReq struct {
+	structs.PageRequest
+}
+
+// QueryNotificationRoutesResp query notification routes response
+type QueryNotificationRoutesResp struct {
+	Routes []structs.NotificationRouteInfo `json:"routes"`
+}
+
+// QueryNotificationDeliveriesReq query delivery logs of notifications
+type QueryNotificationDeliveriesReq struct {
+	ChannelID string `json:"channelId" form:"channelId"`
+	ClusterID string `json:"clusterId" form:"clusterId"`
+	Status    string `json:"status" form:"status" enums:"Success,Failed"`
+	structs.PageRequest
+}
+
+// QueryNotificationDeliveriesResp query delivery logs of notifications response
+type QueryNotificationDeliveriesResp struct {
+	Deliveries []structs.NotificationDeliveryInfo `json:"deliveries"`
+}
+
+// ReceiveAlertsReq alerts sent by webhook receiver of cluster alertmanager, in the format of alertmanager webhook
+type ReceiveAlertsReq struct {
+	ClusterID         string                      `json:"clusterId" swaggerignore:"true" validate:"required"`
+	Token             structs.SensitiveText       `json:"token" swaggerignore:"true"`
+	Status            string                      `json:"status" enums:"firing,resolved"`
+	Receiver          string                      `json:"receiver"`
+	GroupLabels       map[string]string           `json:"groupLabels"`
+	CommonLabels      map[string]string           `json:"commonLabels"`
+	CommonAnnotations map[string]string           `json:"commonAnnotations"`
+	ExternalURL       string                      `json:"externalURL"`
+	Alerts            []structs.NotificationAlert `json:"alerts"`
+}
+
+// ReceiveAlertsResp receive alerts response
+type ReceiveAlertsResp struct {
+	Deliveries []structs.NotificationDeliveryInfo `json:"deliveries"`
+}
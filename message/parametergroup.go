@@ -104,12 +104,14 @@ type ApplyParameterGroupReq struct {
 	ClusterID    string   `json:"clusterId" example:"123" validate:"required,min=4,max=64"`
 	Reboot       bool     `json:"reboot"`
 	Nodes        []string `json:"nodes" swaggerignore:"true"`
+	structs.MaintainWindowOption
 }
 
 type ApplyParameterGroupResp struct {
 	ClusterID    string `json:"clusterId" example:"123"`
 	ParamGroupID string `json:"paramGroupId" example:"123"`
 	structs.AsyncTaskWorkFlowInfo
+	PendingOperationID string `json:"pendingOperationId,omitempty"`
 }
 
 type ParameterGroupInfo struct {
@@ -20,12 +20,28 @@ import "github.com/pingcap/tiunimanager/common/structs"
 type CheckPermissionForUserReq struct {
 	UserID      string                   `json:"userId"`
 	Permissions []structs.RbacPermission `json:"permissions"`
+	// ClusterIDs clusters operated by the request, cluster permission granted on all of them is accepted
+	ClusterIDs []string `json:"clusterIds,omitempty"`
+	// AnyInstance cluster permission granted on any cluster is accepted, only for requests which filter clusters by themselves
+	AnyInstance bool `json:"anyInstance,omitempty"`
 }
 
 type CheckPermissionForUserResp struct {
 	Result bool `json:"result"`
 }
 
+// CheckPermissionForInstancesReq check whether user has permission of any one of the instances
+type CheckPermissionForInstancesReq struct {
+	UserID    string   `json:"userId"`
+	Resource  string   `json:"resource"`
+	Action    string   `json:"action"`
+	Instances []string `json:"instances"` // candidate instances of resource, eg. id/cluster01, tag/team=dba, tenant/tenant01
+}
+
+type CheckPermissionForInstancesResp struct {
+	Result bool `json:"result"`
+}
+
 type CreateRoleReq struct {
 	Role string `json:"role"`
 }
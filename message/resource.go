@@ -82,6 +82,33 @@ type UpdateHostStatusReq struct {
 type UpdateHostStatusResp struct {
 }
 
+type DrainHostReq struct {
+	HostID string `json:"hostId" swaggerignore:"true"`
+}
+
+type DrainHostResp struct {
+	structs.AsyncTaskWorkFlowInfo
+}
+
+type QueryDrainHostReq struct {
+	HostID string `json:"hostId" swaggerignore:"true"`
+}
+
+type QueryDrainHostResp struct {
+	HostID     string                          `json:"hostId"`
+	HostStatus string                          `json:"hostStatus"`
+	WorkFlowID string                          `json:"workFlowId"`
+	Status     string                          `json:"status"` // status of the drain workflow
+	Instances  []structs.DrainInstanceProgress `json:"instances"`
+}
+
+type ReinstateHostReq struct {
+	HostID string `json:"hostId" swaggerignore:"true"`
+}
+
+type ReinstateHostResp struct {
+}
+
 type GetHierarchyReq struct {
 	structs.HostFilter
 	Level int `json:"Level"` // [1:Region, 2:Zone, 3:Rack, 4:Host]
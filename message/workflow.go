@@ -32,9 +32,10 @@ type QueryWorkFlowDetailReq struct {
 }
 
 type QueryWorkFlowDetailResp struct {
-	Info      *structs.WorkFlowInfo       `json:"info"`
-	NodeInfo  []*structs.WorkFlowNodeInfo `json:"nodes"`
-	NodeNames []string                    `json:"nodeNames"`
+	Info      *structs.WorkFlowInfo         `json:"info"`
+	NodeInfo  []*structs.WorkFlowNodeInfo   `json:"nodes"`
+	NodeNames []string                      `json:"nodeNames"`
+	Branches  []*structs.WorkFlowBranchInfo `json:"branches"`
 }
 
 type QueryWorkFlowsReq struct {
@@ -62,3 +63,25 @@ type StopWorkFlowReq struct {
 
 type StopWorkFlowResp struct {
 }
+
+type RetryWorkFlowReq struct {
+	WorkFlowID string `json:"workFlowId"`
+}
+
+type RetryWorkFlowResp struct {
+}
+
+type ResumeWorkFlowReq struct {
+	WorkFlowID string `json:"workFlowId"`
+	NodeName   string `json:"nodeName"`
+}
+
+type ResumeWorkFlowResp struct {
+}
+
+type AbandonWorkFlowReq struct {
+	WorkFlowID string `json:"workFlowId"`
+}
+
+type AbandonWorkFlowResp struct {
+}
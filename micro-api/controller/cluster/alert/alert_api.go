@@ -16,3 +16,313 @@
  ******************************************************************************/
 
 package alert
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/tiunimanager/common/client"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-api/controller"
+)
+
+const paramNameOfClusterId = "clusterId"
+
+// QueryFiringAlerts query alerts which are firing in cluster
+// @Summary query firing alerts of cluster
+// @Description query alerts which are firing in alertmanager of cluster
+// @Tags cluster alert
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param queryReq query cluster.QueryFiringAlertsReq false "query request"
+// @Success 200 {object} controller.CommonResult{data=cluster.QueryFiringAlertsResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/alerts [get]
+func QueryFiringAlerts(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromQuery(c,
+		&cluster.QueryFiringAlertsReq{},
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.QueryFiringAlertsReq).ClusterID = c.Param(paramNameOfClusterId)
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.QueryFiringAlerts, &cluster.QueryFiringAlertsResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// QueryAlertHistory query alert history of cluster
+// @Summary query alert history of cluster
+// @Description query alerts which have been firing in cluster, startTime and endTime are unix timestamps of alert starting
+// @Tags cluster alert
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param queryReq query cluster.QueryAlertHistoryReq false "query request"
+// @Success 200 {object} controller.ResultWithPage{data=cluster.QueryAlertHistoryResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/alerts/history [get]
+func QueryAlertHistory(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromQuery(c,
+		&cluster.QueryAlertHistoryReq{},
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.QueryAlertHistoryReq).ClusterID = c.Param(paramNameOfClusterId)
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.QueryAlertHistory, &cluster.QueryAlertHistoryResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// QueryAlertRules query alert rules of cluster
+// @Summary query alert rules of cluster
+// @Description query built-in and custom alert rules of cluster
+// @Tags cluster alert
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param queryReq query cluster.QueryAlertRulesReq false "query request"
+// @Success 200 {object} controller.ResultWithPage{data=cluster.QueryAlertRulesResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/alert-rules [get]
+func QueryAlertRules(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromQuery(c,
+		&cluster.QueryAlertRulesReq{},
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.QueryAlertRulesReq).ClusterID = c.Param(paramNameOfClusterId)
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.QueryAlertRules, &cluster.QueryAlertRulesResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// CreateAlertRule add a custom alert rule to cluster
+// @Summary add a custom alert rule to cluster
+// @Description add a custom PromQL alert rule, enabled rules are pushed to prometheus of the cluster asynchronously
+// @Tags cluster alert
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param createReq body cluster.CreateAlertRuleReq true "create alert rule request"
+// @Success 200 {object} controller.CommonResult{data=cluster.CreateAlertRuleResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/alert-rules [post]
+func CreateAlertRule(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromBody(c,
+		&cluster.CreateAlertRuleReq{},
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.CreateAlertRuleReq).ClusterID = c.Param(paramNameOfClusterId)
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.CreateAlertRule, &cluster.CreateAlertRuleResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// UpdateAlertRule modify a custom alert rule
+// @Summary modify a custom alert rule
+// @Description modify expression, duration, severity and summary of a custom alert rule, built-in rules can only be enabled or disabled
+// @Tags cluster alert
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param ruleId path string true "alert rule id"
+// @Param updateReq body cluster.UpdateAlertRuleReq true "update alert rule request"
+// @Success 200 {object} controller.CommonResult{data=cluster.UpdateAlertRuleResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/alert-rules/{ruleId} [put]
+func UpdateAlertRule(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromBody(c,
+		&cluster.UpdateAlertRuleReq{},
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.UpdateAlertRuleReq).ClusterID = c.Param(paramNameOfClusterId)
+			req.(*cluster.UpdateAlertRuleReq).RuleID = c.Param("ruleId")
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.UpdateAlertRule, &cluster.UpdateAlertRuleResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// SetAlertRuleEnabled enable or disable an alert rule
+// @Summary enable or disable an alert rule
+// @Description enable or disable a built-in or custom alert rule
+// @Tags cluster alert
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param ruleId path string true "alert rule id"
+// @Param setReq body cluster.SetAlertRuleEnabledReq true "set alert rule enabled request"
+// @Success 200 {object} controller.CommonResult{data=cluster.SetAlertRuleEnabledResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/alert-rules/{ruleId}/status [put]
+func SetAlertRuleEnabled(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromBody(c,
+		&cluster.SetAlertRuleEnabledReq{},
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.SetAlertRuleEnabledReq).ClusterID = c.Param(paramNameOfClusterId)
+			req.(*cluster.SetAlertRuleEnabledReq).RuleID = c.Param("ruleId")
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.SetAlertRuleEnabled, &cluster.SetAlertRuleEnabledResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// DeleteAlertRule delete a custom alert rule
+// @Summary delete a custom alert rule
+// @Description delete a custom alert rule, built-in rules can only be disabled
+// @Tags cluster alert
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param ruleId path string true "alert rule id"
+// @Success 200 {object} controller.CommonResult{data=cluster.DeleteAlertRuleResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/alert-rules/{ruleId} [delete]
+func DeleteAlertRule(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestWithBuiltReq(c, &cluster.DeleteAlertRuleReq{
+		ClusterID: c.Param(paramNameOfClusterId),
+		RuleID:    c.Param("ruleId"),
+	}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.DeleteAlertRule, &cluster.DeleteAlertRuleResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// CreateAlertSilence silence alerts of cluster
+// @Summary silence alerts of cluster
+// @Description silence alerts which match all the matchers until end time
+// @Tags cluster alert
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param createReq body cluster.CreateAlertSilenceReq true "create alert silence request"
+// @Success 200 {object} controller.CommonResult{data=cluster.CreateAlertSilenceResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/alert-silences [post]
+func CreateAlertSilence(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromBody(c,
+		&cluster.CreateAlertSilenceReq{},
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.CreateAlertSilenceReq).ClusterID = c.Param(paramNameOfClusterId)
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.CreateAlertSilence, &cluster.CreateAlertSilenceResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// QueryAlertSilences query alert silences of cluster
+// @Summary query alert silences of cluster
+// @Description query active and expired alert silences of cluster
+// @Tags cluster alert
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param queryReq query cluster.QueryAlertSilencesReq false "query request"
+// @Success 200 {object} controller.ResultWithPage{data=cluster.QueryAlertSilencesResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/alert-silences [get]
+func QueryAlertSilences(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromQuery(c,
+		&cluster.QueryAlertSilencesReq{},
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.QueryAlertSilencesReq).ClusterID = c.Param(paramNameOfClusterId)
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.QueryAlertSilences, &cluster.QueryAlertSilencesResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// DeleteAlertSilence expire an alert silence
+// @Summary expire an alert silence
+// @Description expire an alert silence before its end time
+// @Tags cluster alert
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param silenceId path string true "alert silence id"
+// @Success 200 {object} controller.CommonResult{data=cluster.DeleteAlertSilenceResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/alert-silences/{silenceId} [delete]
+func DeleteAlertSilence(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestWithBuiltReq(c, &cluster.DeleteAlertSilenceReq{
+		ClusterID: c.Param(paramNameOfClusterId),
+		SilenceID: c.Param("silenceId"),
+	}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.DeleteAlertSilence, &cluster.DeleteAlertSilenceResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// ConfigureAlertReceiver configure alertmanager of cluster to send alerts to the platform
+// @Summary configure alert receiver of cluster
+// @Description configure alertmanager of cluster to send alerts to the receiver of the platform, which fans out them to notification channels
+// @Tags cluster alert
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Success 200 {object} controller.CommonResult{data=cluster.ConfigureAlertReceiverResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/alert-receiver [post]
+func ConfigureAlertReceiver(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestWithBuiltReq(c, &cluster.ConfigureAlertReceiverReq{
+		ClusterID: c.Param(paramNameOfClusterId),
+	}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.ConfigureAlertReceiver, &cluster.ConfigureAlertReceiverResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
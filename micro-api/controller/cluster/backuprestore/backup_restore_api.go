@@ -65,6 +65,62 @@ func CancelBackup(c *gin.Context) {
 	}
 }
 
+// VerifyBackup verify integrity of a backup
+// @Summary verify integrity of a backup
+// @Description check backup meta, checksum and size of backup files, and optionally restore the backup into a throwaway cluster
+// @Tags cluster backup
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param backupId path string true "backup record id"
+// @Param verifyReq body cluster.VerifyBackupReq true "verify backup request"
+// @Success 200 {object} controller.CommonResult{data=cluster.VerifyBackupResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /backups/{backupId}/verify [post]
+func VerifyBackup(c *gin.Context) {
+	var req cluster.VerifyBackupReq
+
+	if requestBody, ok := controller.HandleJsonRequestFromBody(c,
+		&req,
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.VerifyBackupReq).BackupID = c.Param("backupId")
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.VerifyBackup, &cluster.VerifyBackupResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// PruneBackupRecords prune expired auto backups of a cluster
+// @Summary prune expired auto backups of a cluster
+// @Description delete auto backups kept by none of the retention rules of backup strategy, the restore base of log backup is always kept
+// @Tags cluster backup
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "clusterId"
+// @Param pruneReq body cluster.PruneBackupRecordsReq true "prune backup records request"
+// @Success 200 {object} controller.CommonResult{data=cluster.PruneBackupRecordsResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/backups/prune [post]
+func PruneBackupRecords(c *gin.Context) {
+	req := cluster.PruneBackupRecordsReq{
+		ClusterID: c.Param("clusterId"),
+	}
+
+	if requestBody, ok := controller.HandleJsonRequestFromBody(c, &req); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.PruneBackupRecords, &cluster.PruneBackupRecordsResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
 // GetBackupStrategy show the backup strategy of a cluster
 // @Summary show the backup strategy of a cluster
 // @Description show the backup strategy of a cluster
@@ -181,3 +237,98 @@ func Restore(c *gin.Context) {
 			controller.DefaultTimeout)
 	}
 }
+
+// StartLogBackup start continuous log backup of a cluster
+// @Summary start continuous log backup of a cluster
+// @Description start continuous log backup of a cluster, which is required by point-in-time recovery
+// @Tags cluster backup
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "clusterId"
+// @Success 200 {object} controller.CommonResult{data=cluster.StartLogBackupResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/log-backup/start [post]
+func StartLogBackup(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestWithBuiltReq(c, &cluster.StartLogBackupReq{
+		ClusterID: c.Param("clusterId"),
+	}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.StartLogBackup, &cluster.StartLogBackupResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// StopLogBackup stop continuous log backup of a cluster
+// @Summary stop continuous log backup of a cluster
+// @Description stop continuous log backup of a cluster, backed up logs are still kept
+// @Tags cluster backup
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "clusterId"
+// @Success 200 {object} controller.CommonResult{data=cluster.StopLogBackupResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/log-backup/stop [post]
+func StopLogBackup(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestWithBuiltReq(c, &cluster.StopLogBackupReq{
+		ClusterID: c.Param("clusterId"),
+	}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.StopLogBackup, &cluster.StopLogBackupResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// QueryLogBackup show the log backup task of a cluster
+// @Summary show the log backup task of a cluster
+// @Description show the log backup task and restorable time range of a cluster
+// @Tags cluster backup
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "clusterId"
+// @Success 200 {object} controller.CommonResult{data=cluster.QueryLogBackupResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/log-backup [get]
+func QueryLogBackup(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestWithBuiltReq(c, &cluster.QueryLogBackupReq{
+		ClusterID: c.Param("clusterId"),
+	}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.QueryLogBackup, &cluster.QueryLogBackupResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// TruncateLogBackup truncate backed up logs of a cluster
+// @Summary truncate backed up logs of a cluster
+// @Description truncate backed up logs before a point in time, which can not be restored to any more
+// @Tags cluster backup
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "clusterId"
+// @Param truncateReq body cluster.TruncateLogBackupReq true "truncate log backup request"
+// @Success 200 {object} controller.CommonResult{data=cluster.TruncateLogBackupResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/log-backup/truncate [post]
+func TruncateLogBackup(c *gin.Context) {
+	req := cluster.TruncateLogBackupReq{
+		ClusterID: c.Param("clusterId"),
+	}
+
+	if requestBody, ok := controller.HandleJsonRequestFromBody(c, &req); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.TruncateLogBackup, &cluster.TruncateLogBackupResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
@@ -0,0 +1,54 @@
+/******************************************************************************
+ * Copyright (c)  2021 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ *                                                                            *
+ ******************************************************************************/
+
+package health
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/tiunimanager/common/client"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-api/controller"
+)
+
+const paramNameOfClusterId = "clusterId"
+
+// QueryClusterHealthEvents query health events of cluster
+// @Summary query health events of cluster
+// @Description query status changes of cluster and its instances found by health monitor
+// @Tags cluster health
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param queryReq query cluster.QueryClusterHealthEventsReq false "query request"
+// @Success 200 {object} controller.ResultWithPage{data=cluster.QueryClusterHealthEventsResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/health-events [get]
+func QueryClusterHealthEvents(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromQuery(c,
+		&cluster.QueryClusterHealthEventsReq{},
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.QueryClusterHealthEventsReq).ClusterID = c.Param(paramNameOfClusterId)
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.QueryClusterHealthEvents, &cluster.QueryClusterHealthEventsResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
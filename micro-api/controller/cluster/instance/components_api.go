@@ -1,4 +1,3 @@
-
 /******************************************************************************
  * Copyright (c)  2021 PingCAP                                                *
  * Licensed under the Apache License, Version 2.0 (the "License");            *
@@ -16,3 +15,163 @@
  ******************************************************************************/
 
 package instance
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/tiunimanager/common/client"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-api/controller"
+)
+
+const (
+	paramNameOfClusterId  = "clusterId"
+	paramNameOfInstanceId = "instanceId"
+)
+
+// Start start an instance of cluster
+// @Summary start an instance of cluster
+// @Description start an instance of cluster, other instances are not touched
+// @Tags cluster instance
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param instanceId path string true "instance id"
+// @Success 200 {object} controller.CommonResult{data=cluster.StartInstanceResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/instances/{instanceId}/start [post]
+func Start(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestWithBuiltReq(c, &cluster.StartInstanceReq{
+		ClusterID:  c.Param(paramNameOfClusterId),
+		InstanceID: c.Param(paramNameOfInstanceId),
+	}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.StartInstance, &cluster.StartInstanceResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// Stop stop an instance of cluster
+// @Summary stop an instance of cluster
+// @Description stop an instance of cluster, other instances are not touched
+// @Tags cluster instance
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param instanceId path string true "instance id"
+// @Param ignoreMaintainWindow query bool false "stop at once even if out of the maintain window"
+// @Param waitMaintainWindow query bool false "queue the stop until the maintain window opens"
+// @Success 200 {object} controller.CommonResult{data=cluster.StopInstanceResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/instances/{instanceId}/stop [post]
+func Stop(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromQuery(c,
+		&cluster.StopInstanceReq{},
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.StopInstanceReq).ClusterID = c.Param(paramNameOfClusterId)
+			req.(*cluster.StopInstanceReq).InstanceID = c.Param(paramNameOfInstanceId)
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.StopInstance, &cluster.StopInstanceResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// Restart restart an instance of cluster
+// @Summary restart an instance of cluster
+// @Description restart an instance of cluster, other instances are not touched
+// @Tags cluster instance
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param instanceId path string true "instance id"
+// @Param ignoreMaintainWindow query bool false "restart at once even if out of the maintain window"
+// @Param waitMaintainWindow query bool false "queue the restart until the maintain window opens"
+// @Success 200 {object} controller.CommonResult{data=cluster.RestartInstanceResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/instances/{instanceId}/restart [post]
+func Restart(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromQuery(c,
+		&cluster.RestartInstanceReq{},
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.RestartInstanceReq).ClusterID = c.Param(paramNameOfClusterId)
+			req.(*cluster.RestartInstanceReq).InstanceID = c.Param(paramNameOfInstanceId)
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.RestartInstance, &cluster.RestartInstanceResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// Reload reload an instance of cluster
+// @Summary reload an instance of cluster
+// @Description reload config of an instance of cluster and restart it, other instances are not touched
+// @Tags cluster instance
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param instanceId path string true "instance id"
+// @Param ignoreMaintainWindow query bool false "reload at once even if out of the maintain window"
+// @Param waitMaintainWindow query bool false "queue the reload until the maintain window opens"
+// @Success 200 {object} controller.CommonResult{data=cluster.ReloadInstanceResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/instances/{instanceId}/reload [post]
+func Reload(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromQuery(c,
+		&cluster.ReloadInstanceReq{},
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.ReloadInstanceReq).ClusterID = c.Param(paramNameOfClusterId)
+			req.(*cluster.ReloadInstanceReq).InstanceID = c.Param(paramNameOfInstanceId)
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.ReloadInstance, &cluster.ReloadInstanceResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// Migrate migrate an instance of cluster to another host
+// @Summary migrate an instance of cluster to another host
+// @Description scale out a replacement on the target host, then scale in the instance and recycle its resource
+// @Tags cluster instance
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param instanceId path string true "instance id"
+// @Param migrateReq body cluster.MigrateInstanceReq true "migrate request"
+// @Success 200 {object} controller.CommonResult{data=cluster.MigrateInstanceResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/instances/{instanceId}/migrate [post]
+func Migrate(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromBody(c,
+		&cluster.MigrateInstanceReq{},
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.MigrateInstanceReq).ClusterID = c.Param(paramNameOfClusterId)
+			req.(*cluster.MigrateInstanceReq).InstanceID = c.Param(paramNameOfInstanceId)
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.MigrateInstance, &cluster.MigrateInstanceResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
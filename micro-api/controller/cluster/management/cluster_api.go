@@ -90,9 +90,37 @@ func Query(c *gin.Context) {
 	}
 }
 
+// UpdateProfile update profile of cluster
+// @Summary update profile of cluster
+// @Description update name, tags, exclusive flag and maintain window of cluster, all fields are replaced
+// @Tags cluster
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param profileReq body cluster.UpdateClusterProfileReq true "update profile request"
+// @Success 200 {object} controller.CommonResult{data=cluster.UpdateClusterProfileResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId} [put]
+func UpdateProfile(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromBody(c,
+		&cluster.UpdateClusterProfileReq{},
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.UpdateClusterProfileReq).ClusterID = c.Param("clusterId")
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.UpdateClusterProfile, &cluster.UpdateClusterProfileResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
 // Delete delete cluster
 // @Summary delete cluster
-// @Description delete cluster
+// @Description delete cluster, or keep it in the recycle bin if softDelete is true, a protected cluster can not be deleted
 // @Tags cluster
 // @Accept json
 // @Produce json
@@ -150,15 +178,21 @@ func DeleteMetaDataPhysically(c *gin.Context) {
 // @Produce application/json
 // @Security ApiKeyAuth
 // @Param clusterId path string true "cluster id"
+// @Param ignoreMaintainWindow query bool false "restart at once even if out of the maintain window"
+// @Param waitMaintainWindow query bool false "queue the restart until the maintain window opens"
 // @Success 200 {object} controller.CommonResult{data=cluster.RestartClusterResp}
 // @Failure 401 {object} controller.CommonResult
 // @Failure 403 {object} controller.CommonResult
 // @Failure 500 {object} controller.CommonResult
 // @Router /clusters/{clusterId}/restart [post]
 func Restart(c *gin.Context) {
-	if requestBody, ok := controller.HandleJsonRequestWithBuiltReq(c, &cluster.RestartClusterReq{
-		ClusterID: c.Param("clusterId"),
-	}); ok {
+	if requestBody, ok := controller.HandleJsonRequestFromQuery(c,
+		&cluster.RestartClusterReq{},
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.RestartClusterReq).ClusterID = c.Param("clusterId")
+			return nil
+		}); ok {
 		controller.InvokeRpcMethod(c, client.ClusterClient.RestartCluster, &cluster.RestartClusterResp{},
 			requestBody,
 			controller.DefaultTimeout)
@@ -378,3 +412,185 @@ func Clone(c *gin.Context) {
 			&cluster.CloneClusterResp{}, body, controller.DefaultTimeout)
 	}
 }
+
+// QueryPendingOperations query operations waiting for the maintain window
+// @Summary query operations of a cluster waiting for the maintain window
+// @Description query operations of a cluster waiting for the maintain window
+// @Tags cluster
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param queryReq query cluster.QueryPendingOperationsReq false "query request"
+// @Success 200 {object} controller.CommonResult{data=cluster.QueryPendingOperationsResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/pending-operations [get]
+func QueryPendingOperations(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromQuery(c,
+		&cluster.QueryPendingOperationsReq{},
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.QueryPendingOperationsReq).ClusterID = c.Param("clusterId")
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.QueryPendingOperations, &cluster.QueryPendingOperationsResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// CancelPendingOperation cancel an operation waiting for the maintain window
+// @Summary cancel an operation waiting for the maintain window
+// @Description cancel an operation waiting for the maintain window
+// @Tags cluster
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param operationId path string true "pending operation id"
+// @Success 200 {object} controller.CommonResult{data=cluster.CancelPendingOperationResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/pending-operations/{operationId} [delete]
+func CancelPendingOperation(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestWithBuiltReq(c, &cluster.CancelPendingOperationReq{
+		ClusterID:   c.Param("clusterId"),
+		OperationID: c.Param("operationId"),
+	}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.CancelPendingOperation, &cluster.CancelPendingOperationResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// AddTags add tags to cluster
+// @Summary add tags to cluster
+// @Description add tags in the format of key=value or key, value of an existing key is overwritten
+// @Tags cluster
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param addTagsReq body cluster.AddClusterTagsReq true "add tags request"
+// @Success 200 {object} controller.CommonResult{data=cluster.AddClusterTagsResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/tags [post]
+func AddTags(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromBody(c,
+		&cluster.AddClusterTagsReq{},
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.AddClusterTagsReq).ClusterID = c.Param("clusterId")
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.AddClusterTags, &cluster.AddClusterTagsResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// RemoveTags remove tags of cluster
+// @Summary remove tags of cluster
+// @Description remove tags of cluster by key
+// @Tags cluster
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param removeTagsReq query cluster.RemoveClusterTagsReq true "remove tags request"
+// @Success 200 {object} controller.CommonResult{data=cluster.RemoveClusterTagsResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/tags [delete]
+func RemoveTags(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromQuery(c,
+		&cluster.RemoveClusterTagsReq{},
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.RemoveClusterTagsReq).ClusterID = c.Param("clusterId")
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.RemoveClusterTags, &cluster.RemoveClusterTagsResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// SetDeletionProtection enable or disable deletion protection of cluster
+// @Summary enable or disable deletion protection of cluster
+// @Description a protected cluster can not be deleted even if forced, until the protection is disabled
+// @Tags cluster
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param protectionReq body cluster.SetDeletionProtectionReq true "deletion protection request"
+// @Success 200 {object} controller.CommonResult{data=cluster.SetDeletionProtectionResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/deletion-protection [put]
+func SetDeletionProtection(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromBody(c,
+		&cluster.SetDeletionProtectionReq{},
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.SetDeletionProtectionReq).ClusterID = c.Param("clusterId")
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.SetDeletionProtection, &cluster.SetDeletionProtectionResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// QueryRecycled query soft deleted clusters
+// @Summary query soft deleted clusters
+// @Description query clusters in the recycle bin, they are purged when the retention expires
+// @Tags cluster
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param recycledQuery query cluster.QueryRecycledClustersReq false "recycled cluster query"
+// @Success 200 {object} controller.ResultWithPage{data=cluster.QueryRecycledClustersResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /recycled-clusters/ [get]
+func QueryRecycled(c *gin.Context) {
+	var req cluster.QueryRecycledClustersReq
+	if requestBody, ok := controller.HandleJsonRequestFromQuery(c, &req); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.QueryRecycledClusters, &cluster.QueryRecycledClustersResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// Recover recover soft deleted cluster
+// @Summary recover soft deleted cluster
+// @Description take the cluster out of the recycle bin and start it
+// @Tags cluster
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Success 200 {object} controller.CommonResult{data=cluster.RecoverClusterResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /recycled-clusters/{clusterId}/recover [post]
+func Recover(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestWithBuiltReq(c, &cluster.RecoverClusterReq{
+		ClusterID: c.Param("clusterId"),
+	}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.RecoverCluster, &cluster.RecoverClusterResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
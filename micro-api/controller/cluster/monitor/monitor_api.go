@@ -16,3 +16,40 @@
  ******************************************************************************/
 
 package monitor
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/tiunimanager/common/client"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-api/controller"
+)
+
+const paramNameOfClusterId = "clusterId"
+
+// QueryClusterMetrics query metrics of cluster
+// @Summary query metrics of cluster
+// @Description query a named metric or a raw PromQL over a time range from prometheus of cluster, startTime, endTime and step are in seconds
+// @Tags cluster monitor
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param queryReq query cluster.QueryClusterMetricsReq false "query request"
+// @Success 200 {object} controller.CommonResult{data=cluster.QueryClusterMetricsResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/metrics [get]
+func QueryClusterMetrics(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromQuery(c,
+		&cluster.QueryClusterMetricsReq{},
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.QueryClusterMetricsReq).ClusterID = c.Param(paramNameOfClusterId)
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.QueryClusterMetrics, &cluster.QueryClusterMetricsResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
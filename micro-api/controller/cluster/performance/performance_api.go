@@ -16,3 +16,235 @@
  ******************************************************************************/
 
 package performance
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/tiunimanager/common/client"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-api/controller"
+)
+
+const paramNameOfClusterId = "clusterId"
+const paramNameOfSnapshotId = "snapshotId"
+
+// QueryTopSQL query top SQL of cluster
+// @Summary query top SQL of cluster
+// @Description query top SQL of cluster in order of latency, executions or scanned keys from statements summary history, startTime and endTime are unix timestamps
+// @Tags cluster performance
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param queryReq query cluster.QueryTopSQLReq false "query request"
+// @Success 200 {object} controller.CommonResult{data=cluster.QueryTopSQLResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/top-sql [get]
+func QueryTopSQL(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromQuery(c,
+		&cluster.QueryTopSQLReq{},
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.QueryTopSQLReq).ClusterID = c.Param(paramNameOfClusterId)
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.QueryTopSQL, &cluster.QueryTopSQLResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// QueryStatementPlans query execution plans of a statement
+// @Summary query execution plans of a statement
+// @Description query execution plans of a statement digest in order of latency from statements summary history
+// @Tags cluster performance
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param digest path string true "statement digest"
+// @Param queryReq query cluster.QueryStatementPlansReq false "query request"
+// @Success 200 {object} controller.CommonResult{data=cluster.QueryStatementPlansResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/top-sql/{digest}/plans [get]
+func QueryStatementPlans(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromQuery(c,
+		&cluster.QueryStatementPlansReq{},
+		// append id and digest in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.QueryStatementPlansReq).ClusterID = c.Param(paramNameOfClusterId)
+			req.(*cluster.QueryStatementPlansReq).Digest = c.Param("digest")
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.QueryStatementPlans, &cluster.QueryStatementPlansResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// QuerySlowQueries query slow queries of cluster
+// @Summary query slow queries of cluster
+// @Description query slow query log of cluster in order of query time or scanned keys, startTime and endTime are unix timestamps
+// @Tags cluster performance
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param queryReq query cluster.QuerySlowQueriesReq false "query request"
+// @Success 200 {object} controller.CommonResult{data=cluster.QuerySlowQueriesResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/slow-queries [get]
+func QuerySlowQueries(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromQuery(c,
+		&cluster.QuerySlowQueriesReq{},
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.QuerySlowQueriesReq).ClusterID = c.Param(paramNameOfClusterId)
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.QuerySlowQueries, &cluster.QuerySlowQueriesResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// CreatePerformanceSnapshot capture top SQL of cluster as a snapshot
+// @Summary create performance snapshot of cluster
+// @Description capture top SQL of cluster within a time range as a snapshot, which can be compared with other snapshots later
+// @Tags cluster performance
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param createReq body cluster.CreatePerformanceSnapshotReq true "create performance snapshot request"
+// @Success 200 {object} controller.CommonResult{data=cluster.CreatePerformanceSnapshotResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/performance-snapshots [post]
+func CreatePerformanceSnapshot(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromBody(c,
+		&cluster.CreatePerformanceSnapshotReq{},
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.CreatePerformanceSnapshotReq).ClusterID = c.Param(paramNameOfClusterId)
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.CreatePerformanceSnapshot, &cluster.CreatePerformanceSnapshotResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// QueryPerformanceSnapshots query performance snapshots of cluster
+// @Summary query performance snapshots of cluster
+// @Description query periodic and manual performance snapshots of cluster
+// @Tags cluster performance
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param queryReq query cluster.QueryPerformanceSnapshotsReq false "query request"
+// @Success 200 {object} controller.ResultWithPage{data=cluster.QueryPerformanceSnapshotsResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/performance-snapshots [get]
+func QueryPerformanceSnapshots(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromQuery(c,
+		&cluster.QueryPerformanceSnapshotsReq{},
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.QueryPerformanceSnapshotsReq).ClusterID = c.Param(paramNameOfClusterId)
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.QueryPerformanceSnapshots, &cluster.QueryPerformanceSnapshotsResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// GetPerformanceSnapshot get performance snapshot of cluster
+// @Summary get performance snapshot of cluster
+// @Description get performance snapshot with its statements in order of latency
+// @Tags cluster performance
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param snapshotId path string true "performance snapshot id"
+// @Success 200 {object} controller.CommonResult{data=cluster.GetPerformanceSnapshotResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/performance-snapshots/{snapshotId} [get]
+func GetPerformanceSnapshot(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestWithBuiltReq(c, &cluster.GetPerformanceSnapshotReq{
+		ClusterID:  c.Param(paramNameOfClusterId),
+		SnapshotID: c.Param(paramNameOfSnapshotId),
+	}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.GetPerformanceSnapshot, &cluster.GetPerformanceSnapshotResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// DeletePerformanceSnapshot delete performance snapshot of cluster
+// @Summary delete performance snapshot of cluster
+// @Description delete performance snapshot of cluster
+// @Tags cluster performance
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param snapshotId path string true "performance snapshot id"
+// @Success 200 {object} controller.CommonResult{data=cluster.DeletePerformanceSnapshotResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/performance-snapshots/{snapshotId} [delete]
+func DeletePerformanceSnapshot(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestWithBuiltReq(c, &cluster.DeletePerformanceSnapshotReq{
+		ClusterID:  c.Param(paramNameOfClusterId),
+		SnapshotID: c.Param(paramNameOfSnapshotId),
+	}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.DeletePerformanceSnapshot, &cluster.DeletePerformanceSnapshotResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// ComparePerformanceSnapshots compare performance snapshot with a base snapshot
+// @Summary compare performance snapshots of cluster
+// @Description compare statements of the snapshot with a base snapshot, for example snapshots before and after a change
+// @Tags cluster performance
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "cluster id"
+// @Param snapshotId path string true "target performance snapshot id"
+// @Param compareReq query cluster.ComparePerformanceSnapshotsReq true "compare request"
+// @Success 200 {object} controller.CommonResult{data=cluster.ComparePerformanceSnapshotsResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/performance-snapshots/{snapshotId}/compare [get]
+func ComparePerformanceSnapshots(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromQuery(c,
+		&cluster.ComparePerformanceSnapshotsReq{},
+		// append id of cluster and target snapshot in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.ComparePerformanceSnapshotsReq).ClusterID = c.Param(paramNameOfClusterId)
+			req.(*cluster.ComparePerformanceSnapshotsReq).TargetSnapshotID = c.Param(paramNameOfSnapshotId)
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.ComparePerformanceSnapshots, &cluster.ComparePerformanceSnapshotsResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
@@ -0,0 +1,114 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package upgrade
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/tiunimanager/common/client"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-api/controller"
+)
+
+const ParamCampaignID = "campaignId"
+
+// CreateCampaign create an upgrade campaign
+// @Summary create an upgrade campaign
+// @Description upgrade clusters selected by ids, tags, versions or vendors to the target version in ordered waves, within maintain window of each cluster
+// @Tags upgrade campaign
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param campaignReq body cluster.CreateUpgradeCampaignReq true "upgrade campaign request"
+// @Success 200 {object} controller.CommonResult{data=cluster.CreateUpgradeCampaignResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /upgrade-campaigns/ [post]
+func CreateCampaign(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromBody(c, &cluster.CreateUpgradeCampaignReq{}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.CreateUpgradeCampaign, &cluster.CreateUpgradeCampaignResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// QueryCampaigns query upgrade campaigns
+// @Summary query upgrade campaigns
+// @Description query upgrade campaigns with aggregate progress
+// @Tags upgrade campaign
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param campaignQuery query cluster.QueryUpgradeCampaignsReq false "upgrade campaign query"
+// @Success 200 {object} controller.ResultWithPage{data=cluster.QueryUpgradeCampaignsResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /upgrade-campaigns/ [get]
+func QueryCampaigns(c *gin.Context) {
+	var req cluster.QueryUpgradeCampaignsReq
+	if requestBody, ok := controller.HandleJsonRequestFromQuery(c, &req); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.QueryUpgradeCampaigns, &cluster.QueryUpgradeCampaignsResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// GetCampaign get upgrade campaign
+// @Summary get upgrade campaign
+// @Description get upgrade campaign and progress of its clusters
+// @Tags upgrade campaign
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param campaignId path string true "campaignId"
+// @Success 200 {object} controller.CommonResult{data=cluster.GetUpgradeCampaignResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /upgrade-campaigns/{campaignId} [get]
+func GetCampaign(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestWithBuiltReq(c, &cluster.GetUpgradeCampaignReq{
+		CampaignID: c.Param(ParamCampaignID),
+	}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.GetUpgradeCampaign, &cluster.GetUpgradeCampaignResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// CancelCampaign cancel upgrade campaign
+// @Summary cancel upgrade campaign
+// @Description cancel a running upgrade campaign, waiting clusters are skipped and upgrading ones are not affected
+// @Tags upgrade campaign
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param campaignId path string true "campaignId"
+// @Success 200 {object} controller.CommonResult{data=cluster.CancelUpgradeCampaignResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /upgrade-campaigns/{campaignId}/cancel [post]
+func CancelCampaign(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestWithBuiltReq(c, &cluster.CancelUpgradeCampaignReq{
+		CampaignID: c.Param(ParamCampaignID),
+	}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.CancelUpgradeCampaign, &cluster.CancelUpgradeCampaignResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
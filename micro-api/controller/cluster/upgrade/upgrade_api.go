@@ -84,6 +84,35 @@ func QueryUpgradeVersionDiffInfo(c *gin.Context) {
 	}
 }
 
+// QueryUpgradeCheckReport check whether the cluster is ready to upgrade to target version
+// @Summary check whether the cluster is ready to upgrade to target version
+// @Description check binaries of target version, estimated duration, changed or deprecated configs and health of cluster before upgrade
+// @Tags cluster upgrade
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "clusterId"
+// @Param upgradeCheckQuery query cluster.QueryUpgradeCheckReportReq true "upgrade check query"
+// @Success 200 {object} controller.CommonResult{data=cluster.QueryUpgradeCheckReportResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/upgrade/check [get]
+func QueryUpgradeCheckReport(c *gin.Context) {
+	var req cluster.QueryUpgradeCheckReportReq
+	if requestBody, ok := controller.HandleJsonRequestFromQuery(c,
+		&req,
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*cluster.QueryUpgradeCheckReportReq).ClusterID = c.Param(ParamClusterID)
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.QueryUpgradeCheckReport, &cluster.QueryUpgradeCheckReportResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
 // Upgrade a cluster
 // @Summary request for upgrade TiDB cluster
 // @Description request for upgrade TiDB cluster
@@ -111,3 +140,26 @@ func Upgrade(c *gin.Context) {
 			&cluster.UpgradeClusterResp{}, body, controller.DefaultTimeout)
 	}
 }
+
+// RollbackUpgrade roll back the latest upgrade of a cluster
+// @Summary roll back the latest upgrade of a cluster
+// @Description reinstall the versions before upgrade on instances which have been upgraded, it is refused if any instance can not be rolled back
+// @Tags cluster upgrade
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param clusterId path string true "clusterId"
+// @Success 200 {object} controller.CommonResult{data=cluster.RollbackUpgradeResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /clusters/{clusterId}/upgrade/rollback [post]
+func RollbackUpgrade(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestWithBuiltReq(c, &cluster.RollbackUpgradeReq{
+		ClusterID: c.Param(ParamClusterID),
+	}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.RollbackUpgrade, &cluster.RollbackUpgradeResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
@@ -0,0 +1,290 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package notification
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/tiunimanager/common/client"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/message"
+	"github.com/pingcap/tiunimanager/micro-api/controller"
+)
+
+const (
+	paramNameOfChannelId = "channelId"
+	paramNameOfRouteId   = "routeId"
+	paramNameOfClusterId = "clusterId"
+)
+
+// CreateNotificationChannel create a notification channel
+// @Summary create a notification channel
+// @Description create a webhook, email or slack channel which notifications of alerts are sent to
+// @Tags platform notification
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param createReq body message.CreateNotificationChannelReq true "create request"
+// @Success 200 {object} controller.CommonResult{data=message.CreateNotificationChannelResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /notifications/channels [post]
+func CreateNotificationChannel(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromBody(c, &message.CreateNotificationChannelReq{}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.CreateNotificationChannel, &message.CreateNotificationChannelResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// UpdateNotificationChannel update a notification channel
+// @Summary update a notification channel
+// @Description update name and config of notification channel, SMTP password is kept if it is empty
+// @Tags platform notification
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param channelId path string true "notification channel id"
+// @Param updateReq body message.UpdateNotificationChannelReq true "update request"
+// @Success 200 {object} controller.CommonResult{data=message.UpdateNotificationChannelResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /notifications/channels/{channelId} [put]
+func UpdateNotificationChannel(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromBody(c,
+		&message.UpdateNotificationChannelReq{},
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*message.UpdateNotificationChannelReq).ChannelID = c.Param(paramNameOfChannelId)
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.UpdateNotificationChannel, &message.UpdateNotificationChannelResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// DeleteNotificationChannel delete a notification channel
+// @Summary delete a notification channel
+// @Description delete a notification channel which is not used by any route
+// @Tags platform notification
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param channelId path string true "notification channel id"
+// @Success 200 {object} controller.CommonResult{data=message.DeleteNotificationChannelResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /notifications/channels/{channelId} [delete]
+func DeleteNotificationChannel(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestWithBuiltReq(c, &message.DeleteNotificationChannelReq{
+		ChannelID: c.Param(paramNameOfChannelId),
+	}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.DeleteNotificationChannel, &message.DeleteNotificationChannelResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// QueryNotificationChannels query notification channels
+// @Summary query notification channels
+// @Description query notification channels, SMTP passwords are not returned
+// @Tags platform notification
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param queryReq query message.QueryNotificationChannelsReq false "query request"
+// @Success 200 {object} controller.ResultWithPage{data=message.QueryNotificationChannelsResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /notifications/channels [get]
+func QueryNotificationChannels(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromQuery(c, &message.QueryNotificationChannelsReq{}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.QueryNotificationChannels, &message.QueryNotificationChannelsResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// TestNotificationChannel send a test notification
+// @Summary send a test notification
+// @Description send a test notification to channel, the result is recorded in delivery logs
+// @Tags platform notification
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param channelId path string true "notification channel id"
+// @Success 200 {object} controller.CommonResult{data=message.TestNotificationChannelResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /notifications/channels/{channelId}/test [post]
+func TestNotificationChannel(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestWithBuiltReq(c, &message.TestNotificationChannelReq{
+		ChannelID: c.Param(paramNameOfChannelId),
+	}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.TestNotificationChannel, &message.TestNotificationChannelResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// CreateNotificationRoute create a notification route
+// @Summary create a notification route
+// @Description create a route which sends alerts matching severities, cluster tag selector and tenant to channels, empty conditions match all
+// @Tags platform notification
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param createReq body message.CreateNotificationRouteReq true "create request"
+// @Success 200 {object} controller.CommonResult{data=message.CreateNotificationRouteResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /notifications/routes [post]
+func CreateNotificationRoute(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromBody(c, &message.CreateNotificationRouteReq{}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.CreateNotificationRoute, &message.CreateNotificationRouteResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// UpdateNotificationRoute update a notification route
+// @Summary update a notification route
+// @Description update conditions and channels of notification route
+// @Tags platform notification
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param routeId path string true "notification route id"
+// @Param updateReq body message.UpdateNotificationRouteReq true "update request"
+// @Success 200 {object} controller.CommonResult{data=message.UpdateNotificationRouteResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /notifications/routes/{routeId} [put]
+func UpdateNotificationRoute(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromBody(c,
+		&message.UpdateNotificationRouteReq{},
+		// append id in path to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*message.UpdateNotificationRouteReq).RouteID = c.Param(paramNameOfRouteId)
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.UpdateNotificationRoute, &message.UpdateNotificationRouteResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// DeleteNotificationRoute delete a notification route
+// @Summary delete a notification route
+// @Description delete a notification route
+// @Tags platform notification
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param routeId path string true "notification route id"
+// @Success 200 {object} controller.CommonResult{data=message.DeleteNotificationRouteResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /notifications/routes/{routeId} [delete]
+func DeleteNotificationRoute(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestWithBuiltReq(c, &message.DeleteNotificationRouteReq{
+		RouteID: c.Param(paramNameOfRouteId),
+	}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.DeleteNotificationRoute, &message.DeleteNotificationRouteResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// QueryNotificationRoutes query notification routes
+// @Summary query notification routes
+// @Description query notification routes
+// @Tags platform notification
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param queryReq query message.QueryNotificationRoutesReq false "query request"
+// @Success 200 {object} controller.ResultWithPage{data=message.QueryNotificationRoutesResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /notifications/routes [get]
+func QueryNotificationRoutes(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromQuery(c, &message.QueryNotificationRoutesReq{}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.QueryNotificationRoutes, &message.QueryNotificationRoutesResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// QueryNotificationDeliveries query notification delivery logs
+// @Summary query notification delivery logs
+// @Description query delivery logs of notifications, including test notifications
+// @Tags platform notification
+// @Accept application/json
+// @Produce application/json
+// @Security ApiKeyAuth
+// @Param queryReq query message.QueryNotificationDeliveriesReq false "query request"
+// @Success 200 {object} controller.ResultWithPage{data=message.QueryNotificationDeliveriesResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /notifications/deliveries [get]
+func QueryNotificationDeliveries(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromQuery(c, &message.QueryNotificationDeliveriesReq{}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.QueryNotificationDeliveries, &message.QueryNotificationDeliveriesResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// ReceiveAlerts receive alerts from alertmanager
+// @Summary receive alerts from alertmanager
+// @Description receive alerts sent by webhook of cluster alertmanager, authenticated by bearer token of the cluster, deliveries are queued and sent asynchronously
+// @Tags platform notification
+// @Accept application/json
+// @Produce application/json
+// @Param clusterId path string true "cluster id"
+// @Param alerts body message.ReceiveAlertsReq true "alerts in the format of alertmanager webhook"
+// @Success 200 {object} controller.CommonResult{data=message.ReceiveAlertsResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /alerts/receiver/{clusterId} [post]
+func ReceiveAlerts(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromBody(c,
+		&message.ReceiveAlertsReq{},
+		// append id in path and token in header to request
+		func(c *gin.Context, req interface{}) error {
+			req.(*message.ReceiveAlertsReq).ClusterID = c.Param(paramNameOfClusterId)
+			req.(*message.ReceiveAlertsReq).Token = structs.SensitiveText(strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "))
+			return nil
+		}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.ReceiveAlerts, &message.ReceiveAlertsResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
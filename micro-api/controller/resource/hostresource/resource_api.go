@@ -370,7 +370,7 @@ func UpdateHostStatus(c *gin.Context) {
 		}
 
 		if !constants.HostStatus(req.Status).IsValidStatus() {
-			errmsg := fmt.Sprintf("input status %s is invalid, [Online,Offline,Deleted,Init,Failed]", req.Status)
+			errmsg := fmt.Sprintf("input status %s is invalid, [Online,Offline,Deleted,Init,Failed,Deleting,Draining,Maintenance]", req.Status)
 			setGinContextForInvalidParam(c, errmsg)
 			return
 		}
@@ -381,6 +381,66 @@ func UpdateHostStatus(c *gin.Context) {
 	}
 }
 
+// DrainHost godoc
+// @Summary Drain a host
+// @Description stop allocating resources on the host, evacuate all cluster instances from it, then put it into maintenance
+// @Tags resource
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param hostId path string true "host id"
+// @Success 200 {object} controller.CommonResult{data=message.DrainHostResp}
+// @Router /resources/hosts/{hostId}/drain [post]
+func DrainHost(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestWithBuiltReq(c, &message.DrainHostReq{
+		HostID: c.Param("hostId"),
+	}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.DrainHost, &message.DrainHostResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// QueryDrainHost godoc
+// @Summary Query drain progress of a host
+// @Description query progress of the latest drain of the host, including status of each instance on it
+// @Tags resource
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param hostId path string true "host id"
+// @Success 200 {object} controller.CommonResult{data=message.QueryDrainHostResp}
+// @Router /resources/hosts/{hostId}/drain [get]
+func QueryDrainHost(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestWithBuiltReq(c, &message.QueryDrainHostReq{
+		HostID: c.Param("hostId"),
+	}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.QueryDrainHost, &message.QueryDrainHostResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// ReinstateHost godoc
+// @Summary Reinstate a host
+// @Description bring a drained host in maintenance online again
+// @Tags resource
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param hostId path string true "host id"
+// @Success 200 {object} controller.CommonResult{data=message.ReinstateHostResp}
+// @Router /resources/hosts/{hostId}/reinstate [post]
+func ReinstateHost(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestWithBuiltReq(c, &message.ReinstateHostReq{
+		HostID: c.Param("hostId"),
+	}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.ReinstateHost, &message.ReinstateHostResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
 // UpdateHost godoc
 // @Summary Update host info
 // @Description update host information
@@ -110,3 +110,66 @@ func Stop(c *gin.Context) {
 			controller.DefaultTimeout)
 	}
 }
+
+// Retry
+// @Summary retry failed workflow from the failed node
+// @Description retry failed workflow from the failed node
+// @Tags retry workflow
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param retryReq body message.RetryWorkFlowReq true "retry workflow"
+// @Success 200 {object} controller.CommonResult{data=message.RetryWorkFlowResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /workflow/retry [post]
+func Retry(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromBody(c, &message.RetryWorkFlowReq{}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.RetryFlow, &message.RetryWorkFlowResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// Resume
+// @Summary resume failed workflow from the specified node
+// @Description resume failed workflow from the specified node
+// @Tags resume workflow
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param resumeReq body message.ResumeWorkFlowReq true "resume workflow"
+// @Success 200 {object} controller.CommonResult{data=message.ResumeWorkFlowResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /workflow/resume [post]
+func Resume(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromBody(c, &message.ResumeWorkFlowReq{}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.ResumeFlow, &message.ResumeWorkFlowResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
+
+// Abandon
+// @Summary abandon failed workflow and execute its deferred failure handling
+// @Description abandon failed workflow and execute its deferred failure handling
+// @Tags abandon workflow
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param abandonReq body message.AbandonWorkFlowReq true "abandon workflow"
+// @Success 200 {object} controller.CommonResult{data=message.AbandonWorkFlowResp}
+// @Failure 401 {object} controller.CommonResult
+// @Failure 403 {object} controller.CommonResult
+// @Failure 500 {object} controller.CommonResult
+// @Router /workflow/abandon [post]
+func Abandon(c *gin.Context) {
+	if requestBody, ok := controller.HandleJsonRequestFromBody(c, &message.AbandonWorkFlowReq{}); ok {
+		controller.InvokeRpcMethod(c, client.ClusterClient.AbandonFlow, &message.AbandonWorkFlowResp{},
+			requestBody,
+			controller.DefaultTimeout)
+	}
+}
@@ -0,0 +1,233 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package interceptor
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/tiunimanager/common/client"
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message"
+	"github.com/pingcap/tiunimanager/micro-api/controller"
+	"github.com/pingcap/tiunimanager/proto/clusterservices"
+)
+
+// methodActionMap default rbac action of http method
+var methodActionMap = map[string]constants.RbacAction{
+	http.MethodGet:    constants.RbacActionRead,
+	http.MethodPost:   constants.RbacActionCreate,
+	http.MethodPut:    constants.RbacActionUpdate,
+	http.MethodDelete: constants.RbacActionDelete,
+}
+
+// routePermission overrides the resource or action of a route, empty field means using the default one
+type routePermission struct {
+	resource constants.RbacResource
+	action   constants.RbacAction
+	// anyInstance cluster permission granted on any cluster is enough, the route filters clusters by itself
+	anyInstance bool
+}
+
+// routePermissionMap key: method + full path of route
+// POST routes which do not create anything, and routes whose resource differs from their group
+var routePermissionMap = map[string]routePermission{
+	"POST /api/v1/config/update": {action: constants.RbacActionUpdate},
+
+	"POST /api/v1/users/:userId/update_profile":                {action: constants.RbacActionUpdate},
+	"POST /api/v1/users/:userId/password":                      {action: constants.RbacActionUpdate},
+	"POST /api/v1/tenants/:tenantId/update_profile":            {action: constants.RbacActionUpdate},
+	"POST /api/v1/tenants/:tenantId/update_on_boarding_status": {action: constants.RbacActionUpdate},
+	"POST /api/v1/rbac/role/bind":                              {action: constants.RbacActionUpdate},
+	"POST /api/v1/rbac/permission/add":                         {action: constants.RbacActionUpdate},
+	"POST /api/v1/rbac/permission/check":                       {action: constants.RbacActionRead},
+
+	"GET /api/v1/clusters/":                              {anyInstance: true},
+	"POST /api/v1/clusters/preview":                      {action: constants.RbacActionRead},
+	"POST /api/v1/clusters/:clusterId/restart":           {action: constants.RbacActionUpdate},
+	"POST /api/v1/clusters/:clusterId/stop":              {action: constants.RbacActionUpdate},
+	"POST /api/v1/clusters/:clusterId/preview-scale-out": {action: constants.RbacActionRead},
+	"POST /api/v1/clusters/:clusterId/scale-out":         {action: constants.RbacActionUpdate},
+	"POST /api/v1/clusters/:clusterId/scale-in":          {action: constants.RbacActionUpdate},
+	"POST /api/v1/clusters/switchover":                   {action: constants.RbacActionUpdate},
+	"POST /api/v1/clusters/:clusterId/upgrade":           {action: constants.RbacActionUpdate},
+	"GET /api/v1/clusters/:clusterId/params":             {resource: constants.RbacResourceParameter},
+	"PUT /api/v1/clusters/:clusterId/params":             {resource: constants.RbacResourceParameter},
+	"POST /api/v1/clusters/:clusterId/params/inspect":    {resource: constants.RbacResourceParameter, action: constants.RbacActionRead},
+	"POST /api/v1/backups/cancel":                        {action: constants.RbacActionUpdate},
+	"POST /api/v1/changefeeds/:changeFeedTaskId/pause":   {action: constants.RbacActionUpdate},
+	"POST /api/v1/changefeeds/:changeFeedTaskId/resume":  {action: constants.RbacActionUpdate},
+	"POST /api/v1/changefeeds/:changeFeedTaskId/update":  {action: constants.RbacActionUpdate},
+	"POST /api/v1/workflow/start":                        {action: constants.RbacActionUpdate},
+	"POST /api/v1/workflow/stop":                         {action: constants.RbacActionUpdate},
+	"POST /api/v1/workflow/retry":                        {action: constants.RbacActionUpdate},
+	"POST /api/v1/workflow/resume":                       {action: constants.RbacActionUpdate},
+	"POST /api/v1/workflow/abandon":                      {action: constants.RbacActionUpdate},
+	"POST /api/v1/param-groups/:paramGroupId/apply":      {action: constants.RbacActionUpdate},
+	"POST /api/v1/products/":                             {action: constants.RbacActionUpdate},
+	"POST /api/v1/vendors/":                              {action: constants.RbacActionUpdate},
+
+	"POST /api/v1/clusters/:clusterId/log-backup/start":    {action: constants.RbacActionUpdate},
+	"POST /api/v1/clusters/:clusterId/log-backup/stop":     {action: constants.RbacActionUpdate},
+	"POST /api/v1/clusters/:clusterId/log-backup/truncate": {action: constants.RbacActionDelete},
+	"POST /api/v1/backups/:backupId/verify":                {action: constants.RbacActionUpdate},
+	"POST /api/v1/clusters/:clusterId/backups/prune":       {action: constants.RbacActionDelete},
+
+	"DELETE /api/v1/clusters/:clusterId/pending-operations/:operationId":   {action: constants.RbacActionUpdate},
+	"POST /api/v1/clusters/:clusterId/tags":                                {action: constants.RbacActionUpdate},
+	"DELETE /api/v1/clusters/:clusterId/tags":                              {action: constants.RbacActionUpdate},
+	"POST /api/v1/clusters/:clusterId/instances/:instanceId/start":         {action: constants.RbacActionUpdate},
+	"POST /api/v1/clusters/:clusterId/instances/:instanceId/stop":          {action: constants.RbacActionUpdate},
+	"POST /api/v1/clusters/:clusterId/instances/:instanceId/restart":       {action: constants.RbacActionUpdate},
+	"POST /api/v1/clusters/:clusterId/instances/:instanceId/reload":        {action: constants.RbacActionUpdate},
+	"POST /api/v1/clusters/:clusterId/instances/:instanceId/migrate":       {action: constants.RbacActionUpdate},
+	"POST /api/v1/clusters/:clusterId/upgrade/rollback":                    {action: constants.RbacActionUpdate},
+	"POST /api/v1/resources/hosts/:hostId/drain":                           {action: constants.RbacActionUpdate},
+	"POST /api/v1/resources/hosts/:hostId/reinstate":                       {action: constants.RbacActionUpdate},
+	"POST /api/v1/upgrade-campaigns/":                                      {action: constants.RbacActionUpdate},
+	"POST /api/v1/upgrade-campaigns/:campaignId/cancel":                    {action: constants.RbacActionUpdate},
+	"POST /api/v1/recycled-clusters/:clusterId/recover":                    {action: constants.RbacActionUpdate},
+	"POST /api/v1/clusters/:clusterId/alert-rules":                         {action: constants.RbacActionUpdate},
+	"DELETE /api/v1/clusters/:clusterId/alert-rules/:ruleId":               {action: constants.RbacActionUpdate},
+	"POST /api/v1/clusters/:clusterId/alert-silences":                      {action: constants.RbacActionUpdate},
+	"DELETE /api/v1/clusters/:clusterId/alert-silences/:silenceId":         {action: constants.RbacActionUpdate},
+	"POST /api/v1/clusters/:clusterId/alert-receiver":                      {action: constants.RbacActionUpdate},
+	"POST /api/v1/notifications/channels/:channelId/test":                  {action: constants.RbacActionUpdate},
+	"POST /api/v1/clusters/:clusterId/performance-snapshots":               {action: constants.RbacActionUpdate},
+	"DELETE /api/v1/clusters/:clusterId/performance-snapshots/:snapshotId": {action: constants.RbacActionUpdate},
+}
+
+// getRoutePermission
+// @Description: get rbac permission required by the route, resource of the group is used by default,
+// and action is decided by the http method
+// @Parameter resource
+// @Parameter method
+// @Parameter fullPath
+// @return structs.RbacPermission
+// @return routePermission override of the route
+// @return bool false if the method is not supported
+func getRoutePermission(resource constants.RbacResource, method string, fullPath string) (structs.RbacPermission, routePermission, bool) {
+	action, ok := methodActionMap[method]
+	if !ok {
+		return structs.RbacPermission{}, routePermission{}, false
+	}
+	override, exist := routePermissionMap[method+" "+fullPath]
+	if exist {
+		if override.resource != "" {
+			resource = override.resource
+		}
+		if override.action != "" {
+			action = override.action
+		}
+	}
+	return structs.RbacPermission{Resource: string(resource), Action: string(action)}, override, true
+}
+
+// getRouteClusterIDs
+// @Description: get cluster operated by the route from path or query, cluster in request body is checked by cluster-server
+// @Parameter c
+// @return []string
+func getRouteClusterIDs(c *gin.Context) []string {
+	if clusterID := c.Param("clusterId"); clusterID != "" {
+		return []string{clusterID}
+	}
+	if clusterID := c.Query("clusterId"); clusterID != "" {
+		return []string{clusterID}
+	}
+	return nil
+}
+
+// isSelfService users are always allowed to read and update their own profile
+func isSelfService(c *gin.Context, userID string) bool {
+	if c.Request.Method == http.MethodDelete {
+		return false
+	}
+	return userID != "" && c.Param("userId") == userID
+}
+
+// abortWithError
+// @Description: abort the request with the error in common result, so that the caller always gets a json body
+// @Parameter c
+// @Parameter err
+func abortWithError(c *gin.Context, err errors.EMError) {
+	c.Error(err)
+	c.JSON(err.GetCode().GetHttpCode(), controller.Fail(int(err.GetCode()), err.Error()))
+	c.Abort()
+}
+
+// RBAC
+// @Description: check whether the visitor has permission of the route, it must be used after VerifyIdentity
+// @Parameter resource default rbac resource of the route group
+// @return gin.HandlerFunc
+func RBAC(resource constants.RbacResource) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString(framework.TiUniManager_X_USER_ID_KEY)
+		if isSelfService(c, userID) {
+			c.Next()
+			return
+		}
+
+		permission, override, ok := getRoutePermission(resource, c.Request.Method, c.FullPath())
+		if !ok {
+			abortWithError(c, errors.NewErrorf(errors.TIUNIMANAGER_RBAC_PERMISSION_DENIED, "method %s is not allowed", c.Request.Method))
+			return
+		}
+
+		req := message.CheckPermissionForUserReq{
+			UserID:      userID,
+			Permissions: []structs.RbacPermission{permission},
+			ClusterIDs:  getRouteClusterIDs(c),
+			AnyInstance: override.anyInstance,
+		}
+		body, err := json.Marshal(req)
+		if err != nil {
+			framework.LogWithContext(c).Errorf("marshal request error: %s", err.Error())
+			abortWithError(c, errors.WrapError(errors.TIUNIMANAGER_MARSHAL_ERROR, "", err))
+			return
+		}
+
+		rpcResp, err := client.ClusterClient.CheckPermissionForUser(framework.NewMicroCtxFromGinCtx(c), &clusterservices.RpcRequest{Request: string(body)}, controller.DefaultTimeout)
+		if err != nil {
+			framework.LogWithContext(c).Errorf("check permission for user %s error: %s", userID, err.Error())
+			abortWithError(c, errors.WrapError(errors.TIUNIMANAGER_RBAC_PERMISSION_CHECK_FAILED, "", err))
+		} else if rpcResp.Code != int32(errors.TIUNIMANAGER_SUCCESS) {
+			framework.LogWithContext(c).Error(rpcResp.Message)
+			code := errors.EM_ERROR_CODE(rpcResp.Code)
+			msg := rpcResp.Message
+			c.JSON(code.GetHttpCode(), controller.Fail(int(code), msg))
+			c.Abort()
+		} else {
+			var result message.CheckPermissionForUserResp
+			err = json.Unmarshal([]byte(rpcResp.Response), &result)
+			if err != nil {
+				framework.LogWithContext(c).Errorf("unmarshal check permission rpc response error: %s", err.Error())
+				abortWithError(c, errors.WrapError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, "", err))
+				return
+			}
+			if !result.Result {
+				denied := errors.NewErrorf(errors.TIUNIMANAGER_RBAC_PERMISSION_DENIED, "user %s has no permission %s of %s", userID, permission.Action, permission.Resource)
+				framework.LogWithContext(c).Warn(denied.Error())
+				abortWithError(c, denied)
+				return
+			}
+			c.Next()
+		}
+	}
+}
@@ -20,15 +20,21 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/pingcap/tiunimanager/common/constants"
 	"github.com/pingcap/tiunimanager/metrics"
+	alertApi "github.com/pingcap/tiunimanager/micro-api/controller/cluster/alert"
 	"github.com/pingcap/tiunimanager/micro-api/controller/cluster/backuprestore"
 	"github.com/pingcap/tiunimanager/micro-api/controller/cluster/changefeed"
+	healthApi "github.com/pingcap/tiunimanager/micro-api/controller/cluster/health"
+	instanceApi "github.com/pingcap/tiunimanager/micro-api/controller/cluster/instance"
 	logApi "github.com/pingcap/tiunimanager/micro-api/controller/cluster/log"
 	clusterApi "github.com/pingcap/tiunimanager/micro-api/controller/cluster/management"
+	monitorApi "github.com/pingcap/tiunimanager/micro-api/controller/cluster/monitor"
 	parameterApi "github.com/pingcap/tiunimanager/micro-api/controller/cluster/parameter"
+	performanceApi "github.com/pingcap/tiunimanager/micro-api/controller/cluster/performance"
 	switchoverApi "github.com/pingcap/tiunimanager/micro-api/controller/cluster/switchover"
 	"github.com/pingcap/tiunimanager/micro-api/controller/cluster/upgrade"
 	configApi "github.com/pingcap/tiunimanager/micro-api/controller/platform/config"
 	platformdignose "github.com/pingcap/tiunimanager/micro-api/controller/platform/dignose"
+	notificationApi "github.com/pingcap/tiunimanager/micro-api/controller/platform/notification"
 	"github.com/pingcap/tiunimanager/micro-api/controller/platform/system"
 
 	"github.com/pingcap/tiunimanager/micro-api/controller/datatransfer/importexport"
@@ -76,6 +82,7 @@ func Route(g *gin.Engine) {
 		{
 			platform.Use(interceptor.VerifyIdentity)
 			platform.Use(interceptor.AuditLog)
+			platform.Use(interceptor.RBAC(constants.RbacResourceSystem))
 			platform.POST("/check", metrics.HandleMetrics(constants.MetricsPlatformCheck), platformApi.Check)
 			platform.POST("/check/:clusterId", metrics.HandleMetrics(constants.MetricsClusterCheck), platformApi.CheckCluster)
 			platform.GET("/report/:checkId", metrics.HandleMetrics(constants.MetricsGetCheckReport), platformApi.GetCheckReport)
@@ -87,14 +94,39 @@ func Route(g *gin.Engine) {
 		{
 			config.Use(interceptor.VerifyIdentity)
 			config.Use(interceptor.AuditLog)
+			config.Use(interceptor.RBAC(constants.RbacResourceSystem))
 			config.POST("/update", metrics.HandleMetrics(constants.MetricsSystemConfigUpdate), configApi.UpdateSystemConfig)
 			config.GET("/", metrics.HandleMetrics(constants.MetricsSystemConfigGet), configApi.GetSystemConfig)
 		}
 
+		notification := apiV1.Group("/notifications")
+		{
+			notification.Use(interceptor.VerifyIdentity)
+			notification.Use(interceptor.AuditLog)
+			notification.Use(interceptor.RBAC(constants.RbacResourceSystem))
+			notification.GET("/channels", metrics.HandleMetrics(constants.MetricsNotificationChannelQuery), notificationApi.QueryNotificationChannels)
+			notification.POST("/channels", metrics.HandleMetrics(constants.MetricsNotificationChannelCreate), notificationApi.CreateNotificationChannel)
+			notification.PUT("/channels/:channelId", metrics.HandleMetrics(constants.MetricsNotificationChannelUpdate), notificationApi.UpdateNotificationChannel)
+			notification.DELETE("/channels/:channelId", metrics.HandleMetrics(constants.MetricsNotificationChannelDelete), notificationApi.DeleteNotificationChannel)
+			notification.POST("/channels/:channelId/test", metrics.HandleMetrics(constants.MetricsNotificationChannelTest), notificationApi.TestNotificationChannel)
+			notification.GET("/routes", metrics.HandleMetrics(constants.MetricsNotificationRouteQuery), notificationApi.QueryNotificationRoutes)
+			notification.POST("/routes", metrics.HandleMetrics(constants.MetricsNotificationRouteCreate), notificationApi.CreateNotificationRoute)
+			notification.PUT("/routes/:routeId", metrics.HandleMetrics(constants.MetricsNotificationRouteUpdate), notificationApi.UpdateNotificationRoute)
+			notification.DELETE("/routes/:routeId", metrics.HandleMetrics(constants.MetricsNotificationRouteDelete), notificationApi.DeleteNotificationRoute)
+			notification.GET("/deliveries", metrics.HandleMetrics(constants.MetricsNotificationDeliveryQuery), notificationApi.QueryNotificationDeliveries)
+		}
+
+		// alertmanager of each cluster authenticates by the bearer token of its own receiver, which is generated for the cluster
+		alertReceiver := apiV1.Group("/alerts")
+		{
+			alertReceiver.POST("/receiver/:clusterId", metrics.HandleMetrics(constants.MetricsAlertReceive), notificationApi.ReceiveAlerts)
+		}
+
 		user := apiV1.Group("/users")
 		{
 			user.Use(interceptor.VerifyIdentityForUserModule)
 			user.Use(interceptor.AuditLog)
+			user.Use(interceptor.RBAC(constants.RbacResourceUser))
 			user.POST("/", metrics.HandleMetrics(constants.MetricsUserCreate), userApi.CreateUser)
 			user.DELETE("/:userId", metrics.HandleMetrics(constants.MetricsUserDelete), userApi.DeleteUser)
 			user.POST("/:userId/update_profile", metrics.HandleMetrics(constants.MetricsUserUpdateProfile), userApi.UpdateUserProfile)
@@ -107,6 +139,7 @@ func Route(g *gin.Engine) {
 		{
 			tenant.Use(interceptor.VerifyIdentity)
 			tenant.Use(interceptor.AuditLog)
+			tenant.Use(interceptor.RBAC(constants.RbacResourceUser))
 			tenant.POST("/", metrics.HandleMetrics(constants.MetricsTenantCreate), userApi.CreateTenant)
 			tenant.DELETE("/:tenantId", metrics.HandleMetrics(constants.MetricsTenantDelete), userApi.DeleteTenant)
 			tenant.POST("/:tenantId/update_profile", metrics.HandleMetrics(constants.MetricsTenantUpdateProfile), userApi.UpdateTenantProfile)
@@ -119,6 +152,7 @@ func Route(g *gin.Engine) {
 		{
 			rbac.Use(interceptor.VerifyIdentity)
 			rbac.Use(interceptor.AuditLog)
+			rbac.Use(interceptor.RBAC(constants.RbacResourceUser))
 			rbac.POST("/role/", metrics.HandleMetrics(constants.MetricsRbacCreateRole), rbacApi.CreateRbacRole)
 			rbac.GET("/role/", metrics.HandleMetrics(constants.MetricsRbacQueryRole), rbacApi.QueryRbacRoles)
 			rbac.POST("/role/bind", metrics.HandleMetrics(constants.MetricsRbacBindRolesForUser), rbacApi.BindRolesForUser)
@@ -135,7 +169,9 @@ func Route(g *gin.Engine) {
 			cluster.Use(interceptor.SystemRunning)
 			cluster.Use(interceptor.VerifyIdentity)
 			cluster.Use(interceptor.AuditLog)
+			cluster.Use(interceptor.RBAC(constants.RbacResourceCluster))
 			cluster.GET("/:clusterId", metrics.HandleMetrics(constants.MetricsClusterDetail), clusterApi.Detail)
+			cluster.PUT("/:clusterId", metrics.HandleMetrics(constants.MetricsClusterUpdateProfile), clusterApi.UpdateProfile)
 			cluster.POST("/", metrics.HandleMetrics(constants.MetricsClusterCreate), clusterApi.Create)
 			cluster.POST("/takeover", metrics.HandleMetrics(constants.MetricsClusterTakeover), clusterApi.Takeover)
 			cluster.POST("/preview", metrics.HandleMetrics(constants.MetricsClusterPreview), clusterApi.Preview)
@@ -144,12 +180,49 @@ func Route(g *gin.Engine) {
 			cluster.DELETE("/:clusterId", metrics.HandleMetrics(constants.MetricsClusterDelete), clusterApi.Delete)
 			cluster.POST("/:clusterId/restart", metrics.HandleMetrics(constants.MetricsClusterRestart), clusterApi.Restart)
 			cluster.POST("/:clusterId/stop", metrics.HandleMetrics(constants.MetricsClusterStop), clusterApi.Stop)
+			cluster.GET("/:clusterId/pending-operations", metrics.HandleMetrics(constants.MetricsClusterPendingOperations), clusterApi.QueryPendingOperations)
+			cluster.DELETE("/:clusterId/pending-operations/:operationId", metrics.HandleMetrics(constants.MetricsClusterCancelPendingOp), clusterApi.CancelPendingOperation)
+			cluster.POST("/:clusterId/tags", metrics.HandleMetrics(constants.MetricsClusterAddTags), clusterApi.AddTags)
+			cluster.DELETE("/:clusterId/tags", metrics.HandleMetrics(constants.MetricsClusterRemoveTags), clusterApi.RemoveTags)
+			cluster.PUT("/:clusterId/deletion-protection", metrics.HandleMetrics(constants.MetricsClusterDeletionProtection), clusterApi.SetDeletionProtection)
+
+			// Instance
+			cluster.POST("/:clusterId/instances/:instanceId/start", metrics.HandleMetrics(constants.MetricsInstanceStart), instanceApi.Start)
+			cluster.POST("/:clusterId/instances/:instanceId/stop", metrics.HandleMetrics(constants.MetricsInstanceStop), instanceApi.Stop)
+			cluster.POST("/:clusterId/instances/:instanceId/restart", metrics.HandleMetrics(constants.MetricsInstanceRestart), instanceApi.Restart)
+			cluster.POST("/:clusterId/instances/:instanceId/reload", metrics.HandleMetrics(constants.MetricsInstanceReload), instanceApi.Reload)
+			cluster.POST("/:clusterId/instances/:instanceId/migrate", metrics.HandleMetrics(constants.MetricsInstanceMigrate), instanceApi.Migrate)
 			cluster.POST("/restore", metrics.HandleMetrics(constants.MetricsClusterRestore), backuprestore.Restore)
 			cluster.GET("/:clusterId/dashboard", metrics.HandleMetrics(constants.MetricsClusterQueryDashboardAddress), clusterApi.GetDashboardInfo)
 			cluster.GET("/:clusterId/monitor", metrics.HandleMetrics(constants.MetricsClusterQueryMonitorAddress), clusterApi.GetMonitorInfo)
+			cluster.GET("/:clusterId/metrics", metrics.HandleMetrics(constants.MetricsClusterQueryMetrics), monitorApi.QueryClusterMetrics)
+			cluster.GET("/:clusterId/health-events", metrics.HandleMetrics(constants.MetricsClusterHealthEventQuery), healthApi.QueryClusterHealthEvents)
 
 			cluster.GET("/:clusterId/log", metrics.HandleMetrics(constants.MetricsClusterQueryLogParameter), logApi.QueryClusterLog)
 
+			// Alert
+			cluster.GET("/:clusterId/alerts", metrics.HandleMetrics(constants.MetricsAlertQueryFiring), alertApi.QueryFiringAlerts)
+			cluster.GET("/:clusterId/alerts/history", metrics.HandleMetrics(constants.MetricsAlertQueryHistory), alertApi.QueryAlertHistory)
+			cluster.GET("/:clusterId/alert-rules", metrics.HandleMetrics(constants.MetricsAlertRuleQuery), alertApi.QueryAlertRules)
+			cluster.POST("/:clusterId/alert-rules", metrics.HandleMetrics(constants.MetricsAlertRuleCreate), alertApi.CreateAlertRule)
+			cluster.PUT("/:clusterId/alert-rules/:ruleId", metrics.HandleMetrics(constants.MetricsAlertRuleUpdate), alertApi.UpdateAlertRule)
+			cluster.PUT("/:clusterId/alert-rules/:ruleId/status", metrics.HandleMetrics(constants.MetricsAlertRuleSetEnabled), alertApi.SetAlertRuleEnabled)
+			cluster.DELETE("/:clusterId/alert-rules/:ruleId", metrics.HandleMetrics(constants.MetricsAlertRuleDelete), alertApi.DeleteAlertRule)
+			cluster.GET("/:clusterId/alert-silences", metrics.HandleMetrics(constants.MetricsAlertSilenceQuery), alertApi.QueryAlertSilences)
+			cluster.POST("/:clusterId/alert-silences", metrics.HandleMetrics(constants.MetricsAlertSilenceCreate), alertApi.CreateAlertSilence)
+			cluster.DELETE("/:clusterId/alert-silences/:silenceId", metrics.HandleMetrics(constants.MetricsAlertSilenceDelete), alertApi.DeleteAlertSilence)
+			cluster.POST("/:clusterId/alert-receiver", metrics.HandleMetrics(constants.MetricsAlertReceiverConfigure), alertApi.ConfigureAlertReceiver)
+
+			// Performance
+			cluster.GET("/:clusterId/top-sql", metrics.HandleMetrics(constants.MetricsPerformanceTopSQLQuery), performanceApi.QueryTopSQL)
+			cluster.GET("/:clusterId/top-sql/:digest/plans", metrics.HandleMetrics(constants.MetricsPerformancePlanQuery), performanceApi.QueryStatementPlans)
+			cluster.GET("/:clusterId/slow-queries", metrics.HandleMetrics(constants.MetricsPerformanceSlowQueryQuery), performanceApi.QuerySlowQueries)
+			cluster.POST("/:clusterId/performance-snapshots", metrics.HandleMetrics(constants.MetricsPerformanceSnapshotCreate), performanceApi.CreatePerformanceSnapshot)
+			cluster.GET("/:clusterId/performance-snapshots", metrics.HandleMetrics(constants.MetricsPerformanceSnapshotQuery), performanceApi.QueryPerformanceSnapshots)
+			cluster.GET("/:clusterId/performance-snapshots/:snapshotId", metrics.HandleMetrics(constants.MetricsPerformanceSnapshotDetail), performanceApi.GetPerformanceSnapshot)
+			cluster.DELETE("/:clusterId/performance-snapshots/:snapshotId", metrics.HandleMetrics(constants.MetricsPerformanceSnapshotDelete), performanceApi.DeletePerformanceSnapshot)
+			cluster.GET("/:clusterId/performance-snapshots/:snapshotId/compare", metrics.HandleMetrics(constants.MetricsPerformanceSnapshotCompare), performanceApi.ComparePerformanceSnapshots)
+
 			// Scale cluster
 			cluster.POST("/:clusterId/preview-scale-out", metrics.HandleMetrics(constants.MetricsClusterPreviewScaleOut), clusterApi.ScaleOutPreview)
 			cluster.POST("/:clusterId/scale-out", metrics.HandleMetrics(constants.MetricsClusterScaleOut), clusterApi.ScaleOut)
@@ -170,6 +243,13 @@ func Route(g *gin.Engine) {
 			cluster.GET("/:clusterId/strategy", metrics.HandleMetrics(constants.MetricsBackupQueryStrategy), backuprestore.GetBackupStrategy)
 			cluster.PUT("/:clusterId/strategy", metrics.HandleMetrics(constants.MetricsBackupModifyStrategy), backuprestore.SaveBackupStrategy)
 
+			// Log backup
+			cluster.POST("/:clusterId/log-backup/start", metrics.HandleMetrics(constants.MetricsLogBackupStart), backuprestore.StartLogBackup)
+			cluster.POST("/:clusterId/log-backup/stop", metrics.HandleMetrics(constants.MetricsLogBackupStop), backuprestore.StopLogBackup)
+			cluster.GET("/:clusterId/log-backup", metrics.HandleMetrics(constants.MetricsLogBackupQuery), backuprestore.QueryLogBackup)
+			cluster.POST("/:clusterId/log-backup/truncate", metrics.HandleMetrics(constants.MetricsLogBackupTruncate), backuprestore.TruncateLogBackup)
+			cluster.POST("/:clusterId/backups/prune", metrics.HandleMetrics(constants.MetricsBackupPrune), backuprestore.PruneBackupRecords)
+
 			//Import and Export
 			cluster.POST("/import", metrics.HandleMetrics(constants.MetricsDataImport), importexport.ImportData)
 			cluster.POST("/export", metrics.HandleMetrics(constants.MetricsDataExport), importexport.ExportData)
@@ -179,14 +259,39 @@ func Route(g *gin.Engine) {
 			//Upgrade
 			cluster.GET("/:clusterId/upgrade/path", metrics.HandleMetrics(constants.MetricsClusterUpgradePath), upgrade.QueryUpgradePaths)
 			cluster.GET("/:clusterId/upgrade/diff", metrics.HandleMetrics(constants.MetricsClusterUpgradeDiff), upgrade.QueryUpgradeVersionDiffInfo)
+			cluster.GET("/:clusterId/upgrade/check", metrics.HandleMetrics(constants.MetricsClusterUpgradeCheck), upgrade.QueryUpgradeCheckReport)
 			cluster.POST("/:clusterId/upgrade", metrics.HandleMetrics(constants.MetricsClusterUpgrade), upgrade.Upgrade)
+			cluster.POST("/:clusterId/upgrade/rollback", metrics.HandleMetrics(constants.MetricsClusterUpgradeRollback), upgrade.RollbackUpgrade)
+		}
+
+		upgradeCampaign := apiV1.Group("/upgrade-campaigns")
+		{
+			upgradeCampaign.Use(interceptor.SystemRunning)
+			upgradeCampaign.Use(interceptor.VerifyIdentity)
+			upgradeCampaign.Use(interceptor.AuditLog)
+			upgradeCampaign.Use(interceptor.RBAC(constants.RbacResourceCluster))
+			upgradeCampaign.POST("/", metrics.HandleMetrics(constants.MetricsUpgradeCampaignCreate), upgrade.CreateCampaign)
+			upgradeCampaign.GET("/", metrics.HandleMetrics(constants.MetricsUpgradeCampaignQuery), upgrade.QueryCampaigns)
+			upgradeCampaign.GET("/:campaignId", metrics.HandleMetrics(constants.MetricsUpgradeCampaignDetail), upgrade.GetCampaign)
+			upgradeCampaign.POST("/:campaignId/cancel", metrics.HandleMetrics(constants.MetricsUpgradeCampaignCancel), upgrade.CancelCampaign)
+		}
+
+		recycledCluster := apiV1.Group("/recycled-clusters")
+		{
+			recycledCluster.Use(interceptor.SystemRunning)
+			recycledCluster.Use(interceptor.VerifyIdentity)
+			recycledCluster.Use(interceptor.AuditLog)
+			recycledCluster.Use(interceptor.RBAC(constants.RbacResourceCluster))
+			recycledCluster.GET("/", metrics.HandleMetrics(constants.MetricsRecycledClusterQuery), clusterApi.QueryRecycled)
+			recycledCluster.POST("/:clusterId/recover", metrics.HandleMetrics(constants.MetricsRecycledClusterRecover), clusterApi.Recover)
 		}
 
 		metadata := apiV1.Group("/metadata")
 		{
-			cluster.Use(interceptor.SystemRunning)
-			cluster.Use(interceptor.VerifyIdentity)
-			cluster.Use(interceptor.AuditLog)
+			metadata.Use(interceptor.SystemRunning)
+			metadata.Use(interceptor.VerifyIdentity)
+			metadata.Use(interceptor.AuditLog)
+			metadata.Use(interceptor.RBAC(constants.RbacResourceCluster))
 			metadata.DELETE("/:clusterId", metrics.HandleMetrics(constants.MetricsMetadataDeletePhysically), clusterApi.DeleteMetaDataPhysically)
 		}
 
@@ -195,11 +300,13 @@ func Route(g *gin.Engine) {
 			backup.Use(interceptor.SystemRunning)
 			backup.Use(interceptor.VerifyIdentity)
 			backup.Use(interceptor.AuditLog)
+			backup.Use(interceptor.RBAC(constants.RbacResourceCluster))
 
 			backup.POST("/", metrics.HandleMetrics(constants.MetricsBackupCreate), backuprestore.Backup)
 			backup.POST("/cancel", metrics.HandleMetrics(constants.MetricsBackupCancel), backuprestore.CancelBackup)
 			backup.GET("/", metrics.HandleMetrics(constants.MetricsBackupQuery), backuprestore.QueryBackupRecords)
 			backup.DELETE("/:backupId", metrics.HandleMetrics(constants.MetricsBackupDelete), backuprestore.DeleteBackup)
+			backup.POST("/:backupId/verify", metrics.HandleMetrics(constants.MetricsBackupVerify), backuprestore.VerifyBackup)
 		}
 
 		changeFeeds := apiV1.Group("/changefeeds")
@@ -207,6 +314,7 @@ func Route(g *gin.Engine) {
 			changeFeeds.Use(interceptor.SystemRunning)
 			changeFeeds.Use(interceptor.VerifyIdentity)
 			changeFeeds.Use(interceptor.AuditLog)
+			changeFeeds.Use(interceptor.RBAC(constants.RbacResourceCDC))
 
 			changeFeeds.POST("/", metrics.HandleMetrics(constants.MetricsCDCTaskCreate), changefeed.Create)
 			changeFeeds.POST("/:changeFeedTaskId/pause", metrics.HandleMetrics(constants.MetricsCDCTaskPause), changefeed.Pause)
@@ -224,10 +332,14 @@ func Route(g *gin.Engine) {
 			flowworks.Use(interceptor.SystemRunning)
 			flowworks.Use(interceptor.VerifyIdentity)
 			flowworks.Use(interceptor.AuditLog)
+			flowworks.Use(interceptor.RBAC(constants.RbacResourceWorkflow))
 			flowworks.GET("/", metrics.HandleMetrics(constants.MetricsWorkFlowQuery), flowtaskApi.Query)
 			flowworks.GET("/:workFlowId", metrics.HandleMetrics(constants.MetricsWorkFlowDetail), flowtaskApi.Detail)
 			flowworks.POST("/start", metrics.HandleMetrics(constants.MetricsWorkFlowStart), flowtaskApi.Start)
 			flowworks.POST("/stop", metrics.HandleMetrics(constants.MetricsWorkFlowStop), flowtaskApi.Stop)
+			flowworks.POST("/retry", metrics.HandleMetrics(constants.MetricsWorkFlowRetry), flowtaskApi.Retry)
+			flowworks.POST("/resume", metrics.HandleMetrics(constants.MetricsWorkFlowResume), flowtaskApi.Resume)
+			flowworks.POST("/abandon", metrics.HandleMetrics(constants.MetricsWorkFlowAbandon), flowtaskApi.Abandon)
 		}
 
 		host := apiV1.Group("/resources")
@@ -235,6 +347,7 @@ func Route(g *gin.Engine) {
 			host.Use(interceptor.SystemRunning)
 			host.Use(interceptor.VerifyIdentity)
 			host.Use(interceptor.AuditLog)
+			host.Use(interceptor.RBAC(constants.RbacResourceResource))
 			host.POST("hosts", metrics.HandleMetrics(constants.MetricsResourceImportHosts), resourceApi.ImportHosts)
 			host.GET("hosts", metrics.HandleMetrics(constants.MetricsResourceQueryHosts), resourceApi.QueryHosts)
 			host.DELETE("hosts", metrics.HandleMetrics(constants.MetricsResourceDeleteHosts), resourceApi.RemoveHosts)
@@ -243,6 +356,9 @@ func Route(g *gin.Engine) {
 			host.GET("stocks", metrics.HandleMetrics(constants.MetricsResourceQueryStocks), warehouseApi.GetStocks)
 			host.PUT("host-reserved", metrics.HandleMetrics(constants.MetricsResourceReservedHost), resourceApi.UpdateHostReserved)
 			host.PUT("host-status", metrics.HandleMetrics(constants.MetricsResourceModifyHostStatus), resourceApi.UpdateHostStatus)
+			host.POST("hosts/:hostId/drain", metrics.HandleMetrics(constants.MetricsResourceDrainHost), resourceApi.DrainHost)
+			host.GET("hosts/:hostId/drain", metrics.HandleMetrics(constants.MetricsResourceQueryDrainHost), resourceApi.QueryDrainHost)
+			host.POST("hosts/:hostId/reinstate", metrics.HandleMetrics(constants.MetricsResourceReinstateHost), resourceApi.ReinstateHost)
 			host.PUT("host", metrics.HandleMetrics(constants.MetricsResourceUpdateHost), resourceApi.UpdateHost)
 			host.POST("disks", metrics.HandleMetrics(constants.MetricsResourceCreateDisks), resourceApi.CreateDisks)
 			host.DELETE("disks", metrics.HandleMetrics(constants.MetricsResourceDeleteDisks), resourceApi.RemoveDisks)
@@ -254,6 +370,7 @@ func Route(g *gin.Engine) {
 			paramGroups.Use(interceptor.SystemRunning)
 			paramGroups.Use(interceptor.VerifyIdentity)
 			paramGroups.Use(interceptor.AuditLog)
+			paramGroups.Use(interceptor.RBAC(constants.RbacResourceParameter))
 			paramGroups.GET("/", metrics.HandleMetrics(constants.MetricsParameterGroupQuery), parametergroup.Query)
 			paramGroups.GET("/:paramGroupId", metrics.HandleMetrics(constants.MetricsParameterGroupDetail), parametergroup.Detail)
 			paramGroups.POST("/", metrics.HandleMetrics(constants.MetricsParameterGroupCreate), parametergroup.Create)
@@ -268,6 +385,7 @@ func Route(g *gin.Engine) {
 			productGroup.Use(interceptor.SystemRunning)
 			productGroup.Use(interceptor.VerifyIdentity)
 			productGroup.Use(interceptor.AuditLog)
+			productGroup.Use(interceptor.RBAC(constants.RbacResourceProduct))
 			productGroup.POST("/", metrics.HandleMetrics(constants.MetricsProductUpdate), product.UpdateProducts)
 			productGroup.GET("/", metrics.HandleMetrics(constants.MetricsProductQuery), product.QueryProducts)
 			productGroup.GET("/available", metrics.HandleMetrics(constants.MetricsProductQueryAvailable), product.QueryAvailableProducts)
@@ -279,6 +397,7 @@ func Route(g *gin.Engine) {
 			vendorGroup.Use(interceptor.SystemRunning)
 			vendorGroup.Use(interceptor.VerifyIdentity)
 			vendorGroup.Use(interceptor.AuditLog)
+			vendorGroup.Use(interceptor.RBAC(constants.RbacResourceProduct))
 			vendorGroup.POST("/", metrics.HandleMetrics(constants.MetricsVendorUpdate), product.UpdateVendors)
 			vendorGroup.GET("/", metrics.HandleMetrics(constants.MetricsVendorQuery), product.QueryVendors)
 			vendorGroup.GET("/available", metrics.HandleMetrics(constants.MetricsVendorQueryAvailable), product.QueryAvailableVendors)
@@ -289,6 +408,7 @@ func Route(g *gin.Engine) {
 			specGroup.Use(interceptor.SystemRunning)
 			specGroup.Use(interceptor.VerifyIdentity)
 			specGroup.Use(interceptor.AuditLog)
+			specGroup.Use(interceptor.RBAC(constants.RbacResourceProduct))
 		}
 
 		systemGroup := apiV1.Group("/system")
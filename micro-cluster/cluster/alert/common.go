@@ -0,0 +1,159 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package alert
+
+import (
+	"github.com/pingcap/tiunimanager/common/constants"
+)
+
+const (
+	contextClusterMeta = "ClusterMeta"
+	contextReceiverURL = "ReceiverURL"
+)
+
+const historyJobSpec = "0 */1 * * * *" // every minute
+
+const annotationSummary = "summary"
+
+// receiverTokenBytes length of random bytes of alert receiver token
+const receiverTokenBytes = 32
+
+const (
+	prometheusConfigFileName = "prometheus.yml"
+	prometheusRuleFilesKey   = "rule_files"
+)
+
+// ruleFile rule file of prometheus
+type ruleFile struct {
+	Groups []ruleGroup `yaml:"groups"`
+}
+
+type ruleGroup struct {
+	Name  string           `yaml:"name"`
+	Rules []prometheusRule `yaml:"rules"`
+}
+
+type prometheusRule struct {
+	Alert       string            `yaml:"alert"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// alertManagerConfig config file of alertmanager, all alerts are sent to the receiver of the platform
+type alertManagerConfig struct {
+	Route     alertManagerRoute      `yaml:"route"`
+	Receivers []alertManagerReceiver `yaml:"receivers"`
+}
+
+type alertManagerRoute struct {
+	Receiver       string   `yaml:"receiver"`
+	GroupBy        []string `yaml:"group_by"`
+	GroupWait      string   `yaml:"group_wait"`
+	GroupInterval  string   `yaml:"group_interval"`
+	RepeatInterval string   `yaml:"repeat_interval"`
+}
+
+type alertManagerReceiver struct {
+	Name           string          `yaml:"name"`
+	WebhookConfigs []webhookConfig `yaml:"webhook_configs"`
+}
+
+type webhookConfig struct {
+	URL          string     `yaml:"url"`
+	SendResolved bool       `yaml:"send_resolved"`
+	HTTPConfig   httpConfig `yaml:"http_config"`
+}
+
+type httpConfig struct {
+	BearerToken string `yaml:"bearer_token"`
+}
+
+// builtInRule rule shipped with the platform, rules of a cluster are initialized with them
+type builtInRule struct {
+	Name      string
+	Component constants.EMProductComponentIDType
+	Expr      string
+	Duration  string
+	Severity  constants.AlertSeverity
+	Summary   string
+}
+
+var builtInRules = []builtInRule{
+	{
+		Name:      "TiDB_server_is_down",
+		Component: constants.ComponentIDTiDB,
+		Expr:      `probe_success{group="tidb"} == 0`,
+		Duration:  "1m",
+		Severity:  constants.AlertSeverityEmergency,
+		Summary:   "TiDB server is down",
+	},
+	{
+		Name:      "TiKV_server_is_down",
+		Component: constants.ComponentIDTiKV,
+		Expr:      `probe_success{group="tikv"} == 0`,
+		Duration:  "1m",
+		Severity:  constants.AlertSeverityEmergency,
+		Summary:   "TiKV server is down",
+	},
+	{
+		Name:      "PD_cluster_down_store_nums",
+		Component: constants.ComponentIDPD,
+		Expr:      `(sum(pd_cluster_status{type="store_down_count"}) by (instance) > 0) and (sum(etcd_server_is_leader) by (instance) > 0)`,
+		Severity:  constants.AlertSeverityEmergency,
+		Summary:   "some TiKV stores are down",
+	},
+	{
+		Name:      "PD_cluster_low_space",
+		Component: constants.ComponentIDPD,
+		Expr:      `(sum(pd_cluster_status{type="store_low_space_count"}) by (instance) > 0) and (sum(etcd_server_is_leader) by (instance) > 0)`,
+		Duration:  "1m",
+		Severity:  constants.AlertSeverityCritical,
+		Summary:   "some TiKV stores are low on space",
+	},
+	{
+		Name:      "TiDB_server_panic_total",
+		Component: constants.ComponentIDTiDB,
+		Expr:      `increase(tidb_server_panic_total[10m]) > 0`,
+		Severity:  constants.AlertSeverityCritical,
+		Summary:   "TiDB server panicked",
+	},
+	{
+		Name:      "TiDB_query_duration",
+		Component: constants.ComponentIDTiDB,
+		Expr:      `histogram_quantile(0.99, sum(rate(tidb_server_handle_query_duration_seconds_bucket[1m])) by (le, instance)) > 1`,
+		Duration:  "5m",
+		Severity:  constants.AlertSeverityWarning,
+		Summary:   "99th percentile of query duration is longer than 1s",
+	},
+	{
+		Name:      "TiKV_space_used_more_than_80%",
+		Component: constants.ComponentIDTiKV,
+		Expr:      `sum(tikv_store_size_bytes{type="used"}) by (instance) / sum(tikv_store_size_bytes{type="capacity"}) by (instance) > 0.8`,
+		Duration:  "1m",
+		Severity:  constants.AlertSeverityWarning,
+		Summary:   "TiKV store space is used more than 80%",
+	},
+	{
+		Name:      "TiKV_memory_used_too_fast",
+		Component: constants.ComponentIDTiKV,
+		Expr:      `process_resident_memory_bytes{job=~"tikv",instance=~".*"} - (process_resident_memory_bytes{job=~"tikv",instance=~".*"} offset 5m) > 5*1024*1024*1024`,
+		Duration:  "5m",
+		Severity:  constants.AlertSeverityWarning,
+		Summary:   "TiKV memory grows more than 5GiB in 5 minutes",
+	},
+}
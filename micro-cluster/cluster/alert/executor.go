@@ -0,0 +1,401 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package alert
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/deployment"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/alert"
+	workflowModel "github.com/pingcap/tiunimanager/models/workflow"
+	"github.com/pingcap/tiunimanager/util/api/alertmanager"
+	"github.com/pingcap/tiunimanager/util/api/prometheus"
+	workflow "github.com/pingcap/tiunimanager/workflow2"
+	"gopkg.in/yaml.v2"
+)
+
+// pushAlertRules
+// @Description: render enabled rules of cluster into a rule file and push it to the deploy dir of prometheus
+func pushAlertRules(node *workflowModel.WorkFlowNode, ctx *workflow.FlowContext) error {
+	framework.LogWithContext(ctx).Info("begin push alert rules executor method")
+	defer framework.LogWithContext(ctx).Info("end push alert rules executor method")
+
+	var clusterMeta meta.ClusterMeta
+	err := ctx.GetData(contextClusterMeta, &clusterMeta)
+	if err != nil {
+		return err
+	}
+
+	instances := clusterMeta.Instances[string(constants.ComponentIDPrometheus)]
+	if len(instances) == 0 {
+		errMsg := fmt.Sprintf("prometheus of cluster %s not found", clusterMeta.Cluster.ID)
+		framework.LogWithContext(ctx).Error(errMsg)
+		return errors.NewError(errors.TIUNIMANAGER_ALERT_MANAGER_UNAVAILABLE, errMsg)
+	}
+	instance := instances[0]
+
+	rules, _, err := models.GetAlertReaderWriter().QueryRules(ctx, clusterMeta.Cluster.ID, "", constants.AlertRuleEnabled, 0, -1)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query enabled alert rules of cluster %s failed, err = %s", clusterMeta.Cluster.ID, err.Error())
+		return err
+	}
+
+	content, err := buildRuleFile(clusterMeta.Cluster.ID, rules)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("build alert rule file of cluster %s failed, err = %s", clusterMeta.Cluster.ID, err.Error())
+		return err
+	}
+
+	node.Record(fmt.Sprintf("push %d enabled alert rules to prometheus %s", len(rules), instance.HostIP[0]))
+	remotePath := fmt.Sprintf("%s/conf/%s", instance.GetDeployDir(), constants.AlertRuleFileName)
+	pushID, err := deployment.M.Push(ctx, deployment.TiUPComponentTypeCluster, clusterMeta.Cluster.ID, content,
+		remotePath, framework.GetTiupHomePathForTidb(), node.ParentID, []string{"-N", instance.HostIP[0]}, 0)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("push alert rules of cluster %s failed, err = %s", clusterMeta.Cluster.ID, err.Error())
+		return err
+	}
+	framework.LogWithContext(ctx).Infof("got pushID: %s", pushID)
+	node.OperationID = pushID
+	return nil
+}
+
+// registerRuleFile
+// @Description: prometheus deployed by tiup only loads rule files listed in rule_files of its config,
+// append the rule file of the platform to it if absent. The config is patched again when rules are applied next time
+// if tiup regenerates it, for example scaling out prometheus
+func registerRuleFile(node *workflowModel.WorkFlowNode, ctx *workflow.FlowContext) error {
+	framework.LogWithContext(ctx).Info("begin register rule file executor method")
+	defer framework.LogWithContext(ctx).Info("end register rule file executor method")
+
+	var clusterMeta meta.ClusterMeta
+	err := ctx.GetData(contextClusterMeta, &clusterMeta)
+	if err != nil {
+		return err
+	}
+	instances := clusterMeta.Instances[string(constants.ComponentIDPrometheus)]
+	if len(instances) == 0 {
+		errMsg := fmt.Sprintf("prometheus of cluster %s not found", clusterMeta.Cluster.ID)
+		framework.LogWithContext(ctx).Error(errMsg)
+		return errors.NewError(errors.TIUNIMANAGER_PROMETHEUS_UNAVAILABLE, errMsg)
+	}
+	instance := instances[0]
+
+	remotePath := fmt.Sprintf("%s/conf/%s", instance.GetDeployDir(), prometheusConfigFileName)
+	config, err := deployment.M.Pull(ctx, deployment.TiUPComponentTypeCluster, clusterMeta.Cluster.ID, remotePath,
+		framework.GetTiupHomePathForTidb(), []string{"-N", instance.HostIP[0]}, 0)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("pull config of prometheus of cluster %s failed, err = %s", clusterMeta.Cluster.ID, err.Error())
+		return err
+	}
+	patched, changed, err := appendRuleFile(config, constants.AlertRuleFileName)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("patch config of prometheus of cluster %s failed, err = %s", clusterMeta.Cluster.ID, err.Error())
+		return err
+	}
+	if !changed {
+		// nothing to push, skip polling
+		node.Success(fmt.Sprintf("rule file %s has been registered in %s", constants.AlertRuleFileName, remotePath))
+		return nil
+	}
+
+	node.Record(fmt.Sprintf("register rule file %s in %s", constants.AlertRuleFileName, remotePath))
+	pushID, err := deployment.M.Push(ctx, deployment.TiUPComponentTypeCluster, clusterMeta.Cluster.ID, patched,
+		remotePath, framework.GetTiupHomePathForTidb(), node.ParentID, []string{"-N", instance.HostIP[0]}, 0)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("push config of prometheus of cluster %s failed, err = %s", clusterMeta.Cluster.ID, err.Error())
+		return err
+	}
+	framework.LogWithContext(ctx).Infof("got pushID: %s", pushID)
+	node.OperationID = pushID
+	return nil
+}
+
+// reloadPrometheus
+// @Description: reload prometheus by its api to make the rule file take effect,
+// reloading by tiup would regenerate the config and drop the registered rule file
+func reloadPrometheus(node *workflowModel.WorkFlowNode, ctx *workflow.FlowContext) error {
+	framework.LogWithContext(ctx).Info("begin reload prometheus executor method")
+	defer framework.LogWithContext(ctx).Info("end reload prometheus executor method")
+
+	var clusterMeta meta.ClusterMeta
+	err := ctx.GetData(contextClusterMeta, &clusterMeta)
+	if err != nil {
+		return err
+	}
+	address, err := getPrometheusAddress(&clusterMeta)
+	if err != nil {
+		return err
+	}
+
+	node.Record(fmt.Sprintf("reload prometheus %s", address))
+	if err = prometheus.PrometheusService.Reload(ctx, address); err != nil {
+		framework.LogWithContext(ctx).Errorf("reload prometheus of cluster %s failed, err = %s", clusterMeta.Cluster.ID, err.Error())
+		return err
+	}
+	return nil
+}
+
+// checkAlertRules
+// @Description: make sure that all enabled rules of cluster are loaded by prometheus after reloading
+func checkAlertRules(node *workflowModel.WorkFlowNode, ctx *workflow.FlowContext) error {
+	framework.LogWithContext(ctx).Info("begin check alert rules executor method")
+	defer framework.LogWithContext(ctx).Info("end check alert rules executor method")
+
+	var clusterMeta meta.ClusterMeta
+	err := ctx.GetData(contextClusterMeta, &clusterMeta)
+	if err != nil {
+		return err
+	}
+	address, err := getPrometheusAddress(&clusterMeta)
+	if err != nil {
+		return err
+	}
+
+	rules, _, err := models.GetAlertReaderWriter().QueryRules(ctx, clusterMeta.Cluster.ID, "", constants.AlertRuleEnabled, 0, -1)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query enabled alert rules of cluster %s failed, err = %s", clusterMeta.Cluster.ID, err.Error())
+		return err
+	}
+	groups, err := prometheus.PrometheusService.QueryRuleGroups(ctx, address)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query rule groups of cluster %s failed, err = %s", clusterMeta.Cluster.ID, err.Error())
+		return err
+	}
+	if err = matchLoadedRules(groups, rules); err != nil {
+		framework.LogWithContext(ctx).Errorf("check alert rules of cluster %s failed, err = %s", clusterMeta.Cluster.ID, err.Error())
+		return err
+	}
+	node.Record(fmt.Sprintf("%d enabled alert rules are loaded by prometheus %s", len(rules), address))
+	return nil
+}
+
+// appendRuleFile
+// @Description: append rule file to rule_files of prometheus config, other fields are kept in order
+// @Parameter config content of prometheus.yml
+// @Parameter ruleFile
+// @return patched
+// @return changed false if the rule file has been listed
+// @return err
+func appendRuleFile(config string, ruleFile string) (patched string, changed bool, err error) {
+	content := yaml.MapSlice{}
+	if err = yaml.Unmarshal([]byte(config), &content); err != nil {
+		return "", false, errors.WrapError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, "unmarshal config of prometheus failed", err)
+	}
+
+	found := false
+	for i, item := range content {
+		if item.Key != prometheusRuleFilesKey {
+			continue
+		}
+		found = true
+		files, _ := item.Value.([]interface{})
+		for _, file := range files {
+			if file == ruleFile {
+				return config, false, nil
+			}
+		}
+		content[i].Value = append(files, ruleFile)
+	}
+	if !found {
+		content = append(content, yaml.MapItem{Key: prometheusRuleFilesKey, Value: []interface{}{ruleFile}})
+	}
+
+	bs, err := yaml.Marshal(content)
+	if err != nil {
+		return "", false, errors.WrapError(errors.TIUNIMANAGER_MARSHAL_ERROR, "marshal config of prometheus failed", err)
+	}
+	return string(bs), true, nil
+}
+
+// matchLoadedRules
+// @Description: the rule group of the platform is required to be loaded from its rule file with all enabled rules
+// @Parameter groups rule groups loaded by prometheus
+// @Parameter rules enabled rules of cluster
+// @return error
+func matchLoadedRules(groups []prometheus.RuleGroup, rules []*alert.AlertRule) error {
+	for _, group := range groups {
+		if group.Name != constants.AlertRuleGroupName || !strings.HasSuffix(group.File, constants.AlertRuleFileName) {
+			continue
+		}
+		loaded := make(map[string]bool, len(group.Alerts))
+		for _, name := range group.Alerts {
+			loaded[name] = true
+		}
+		for _, rule := range rules {
+			if !loaded[rule.Name] {
+				return errors.NewErrorf(errors.TIUNIMANAGER_ALERT_RULE_NOT_LOADED, "alert rule %s is not loaded by prometheus", rule.Name)
+			}
+		}
+		return nil
+	}
+	return errors.NewErrorf(errors.TIUNIMANAGER_ALERT_RULE_NOT_LOADED, "rule file %s is not loaded by prometheus", constants.AlertRuleFileName)
+}
+
+// pushAlertManagerConfig
+// @Description: render config of alertmanager which sends all alerts to the receiver of the platform, and push it to the deploy dir of alertmanager
+func pushAlertManagerConfig(node *workflowModel.WorkFlowNode, ctx *workflow.FlowContext) error {
+	framework.LogWithContext(ctx).Info("begin push alertmanager config executor method")
+	defer framework.LogWithContext(ctx).Info("end push alertmanager config executor method")
+
+	var clusterMeta meta.ClusterMeta
+	err := ctx.GetData(contextClusterMeta, &clusterMeta)
+	if err != nil {
+		return err
+	}
+	var receiverURL string
+	if err = ctx.GetData(contextReceiverURL, &receiverURL); err != nil {
+		return err
+	}
+
+	instances := clusterMeta.Instances[string(constants.ComponentIDAlertManger)]
+	if len(instances) == 0 {
+		errMsg := fmt.Sprintf("alertmanager of cluster %s not found", clusterMeta.Cluster.ID)
+		framework.LogWithContext(ctx).Error(errMsg)
+		return errors.NewError(errors.TIUNIMANAGER_ALERT_MANAGER_UNAVAILABLE, errMsg)
+	}
+	alertManager := instances[0]
+
+	// token is read when the flow is running, so that it is not saved in flow context
+	token, err := getAlertReceiverToken(ctx, clusterMeta.Cluster.ID)
+	if err != nil {
+		return err
+	}
+	content, err := buildAlertManagerConfig(receiverURL, token)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("build alertmanager config of cluster %s failed, err = %s", clusterMeta.Cluster.ID, err.Error())
+		return err
+	}
+
+	node.Record(fmt.Sprintf("push config with receiver %s to alertmanager %s", receiverURL, alertManager.HostIP[0]))
+	remotePath := fmt.Sprintf("%s/conf/%s", alertManager.GetDeployDir(), constants.AlertManagerConfigFileName)
+	pushID, err := deployment.M.Push(ctx, deployment.TiUPComponentTypeCluster, clusterMeta.Cluster.ID, content,
+		remotePath, framework.GetTiupHomePathForTidb(), node.ParentID, []string{"-N", alertManager.HostIP[0]}, 0)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("push alertmanager config of cluster %s failed, err = %s", clusterMeta.Cluster.ID, err.Error())
+		return err
+	}
+	framework.LogWithContext(ctx).Infof("got pushID: %s", pushID)
+	node.OperationID = pushID
+	return nil
+}
+
+// reloadAlertManager
+// @Description: reload alertmanager by its api, reloading by tiup would overwrite the pushed config
+func reloadAlertManager(node *workflowModel.WorkFlowNode, ctx *workflow.FlowContext) error {
+	framework.LogWithContext(ctx).Info("begin reload alertmanager executor method")
+	defer framework.LogWithContext(ctx).Info("end reload alertmanager executor method")
+
+	var clusterMeta meta.ClusterMeta
+	err := ctx.GetData(contextClusterMeta, &clusterMeta)
+	if err != nil {
+		return err
+	}
+	address, err := getAlertManagerAddress(&clusterMeta)
+	if err != nil {
+		return err
+	}
+
+	node.Record(fmt.Sprintf("reload alertmanager %s", address))
+	if err = alertmanager.AlertManagerService.Reload(ctx, address); err != nil {
+		framework.LogWithContext(ctx).Errorf("reload alertmanager of cluster %s failed, err = %s", clusterMeta.Cluster.ID, err.Error())
+		return err
+	}
+	return nil
+}
+
+// buildRuleFile
+// @Description: build prometheus rule file, every rule is labeled with cluster id and severity
+// @Parameter clusterID
+// @Parameter rules
+// @return string
+// @return error
+func buildRuleFile(clusterID string, rules []*alert.AlertRule) (string, error) {
+	group := ruleGroup{
+		Name:  constants.AlertRuleGroupName,
+		Rules: make([]prometheusRule, 0),
+	}
+	for _, rule := range rules {
+		promRule := prometheusRule{
+			Alert: rule.Name,
+			Expr:  rule.Expr,
+			For:   rule.Duration,
+			Labels: map[string]string{
+				constants.AlertLabelSeverity: rule.Severity,
+				constants.AlertLabelCluster:  clusterID,
+			},
+		}
+		if len(rule.Summary) > 0 {
+			promRule.Annotations = map[string]string{annotationSummary: rule.Summary}
+		}
+		group.Rules = append(group.Rules, promRule)
+	}
+
+	bs, err := yaml.Marshal(ruleFile{Groups: []ruleGroup{group}})
+	if err != nil {
+		return "", err
+	}
+	return string(bs), nil
+}
+
+// buildAlertManagerConfig
+// @Description: build alertmanager config file, alerts are grouped by name and sent to receiver url with bearer token
+// @Parameter receiverURL
+// @Parameter token
+// @return string
+// @return error
+func buildAlertManagerConfig(receiverURL string, token string) (string, error) {
+	config := alertManagerConfig{
+		Route: alertManagerRoute{
+			Receiver:       constants.AlertReceiverName,
+			GroupBy:        []string{constants.AlertLabelName, constants.AlertLabelCluster},
+			GroupWait:      "30s",
+			GroupInterval:  "5m",
+			RepeatInterval: "3h",
+		},
+		Receivers: []alertManagerReceiver{
+			{
+				Name: constants.AlertReceiverName,
+				WebhookConfigs: []webhookConfig{
+					{
+						URL:          receiverURL,
+						SendResolved: true,
+						HTTPConfig:   httpConfig{BearerToken: token},
+					},
+				},
+			},
+		},
+	}
+	bs, err := yaml.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return string(bs), nil
+}
+
+// defaultEnd
+// @Description: default end
+func defaultEnd(node *workflowModel.WorkFlowNode, ctx *workflow.FlowContext) error {
+	framework.LogWithContext(ctx).Info("begin default end executor method")
+	defer framework.LogWithContext(ctx).Info("end default end executor method")
+
+	return nil
+}
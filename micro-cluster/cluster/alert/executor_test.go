@@ -0,0 +1,301 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package alert
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert"
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/deployment"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/alert"
+	workflowModel "github.com/pingcap/tiunimanager/models/workflow"
+	mock_deployment "github.com/pingcap/tiunimanager/test/mockdeployment"
+	"github.com/pingcap/tiunimanager/test/mockutilalertmanager"
+	"github.com/pingcap/tiunimanager/test/mockutilprometheus"
+	"github.com/pingcap/tiunimanager/util/api/alertmanager"
+	"github.com/pingcap/tiunimanager/util/api/prometheus"
+	workflow "github.com/pingcap/tiunimanager/workflow2"
+	"gopkg.in/yaml.v2"
+)
+
+func TestExecutor_pushAlertRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDeployment := mock_deployment.NewMockInterface(ctrl)
+	deployment.M = mockDeployment
+
+	t.Run("normal", func(t *testing.T) {
+		clusterMeta := mockClusterMeta("push-cluster")
+		assert.NoError(t, ensureBuiltInRules(context.TODO(), clusterMeta))
+
+		mockDeployment.EXPECT().Push(gomock.Any(), deployment.TiUPComponentTypeCluster, "push-cluster", gomock.Any(),
+			"/mnt/sda/push-cluster/prometheus-deploy/conf/"+constants.AlertRuleFileName, gomock.Any(), "flow01", []string{"-N", "127.0.0.1"}, 0).
+			DoAndReturn(func(ctx context.Context, componentType deployment.TiUPComponentType, clusterID, collectorYaml, remotePath, home, workFlowID string, args []string, timeout int) (string, error) {
+				assert.Contains(t, collectorYaml, "TiDB_server_is_down")
+				return "push01", nil
+			})
+
+		ctx := &workflow.FlowContext{
+			Context:  context.TODO(),
+			FlowData: make(map[string]string),
+		}
+		ctx.SetData(contextClusterMeta, clusterMeta)
+		node := &workflowModel.WorkFlowNode{ParentID: "flow01"}
+		err := pushAlertRules(node, ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "push01", node.OperationID)
+	})
+
+	t.Run("without prometheus", func(t *testing.T) {
+		clusterMeta := mockClusterMeta("push-cluster")
+		delete(clusterMeta.Instances, string(constants.ComponentIDPrometheus))
+
+		ctx := &workflow.FlowContext{
+			Context:  context.TODO(),
+			FlowData: make(map[string]string),
+		}
+		ctx.SetData(contextClusterMeta, clusterMeta)
+		err := pushAlertRules(&workflowModel.WorkFlowNode{}, ctx)
+		assert.Error(t, err)
+	})
+}
+
+func TestExecutor_registerRuleFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDeployment := mock_deployment.NewMockInterface(ctrl)
+	deployment.M = mockDeployment
+	remotePath := "/mnt/sda/register-cluster/prometheus-deploy/conf/prometheus.yml"
+
+	t.Run("normal", func(t *testing.T) {
+		mockDeployment.EXPECT().Pull(gomock.Any(), deployment.TiUPComponentTypeCluster, "register-cluster", remotePath, gomock.Any(), []string{"-N", "127.0.0.1"}, 0).
+			Return("global:\n  scrape_interval: 15s\nrule_files:\n- node.rules.yml\n", nil)
+		mockDeployment.EXPECT().Push(gomock.Any(), deployment.TiUPComponentTypeCluster, "register-cluster", gomock.Any(),
+			remotePath, gomock.Any(), "flow01", []string{"-N", "127.0.0.1"}, 0).
+			DoAndReturn(func(ctx context.Context, componentType deployment.TiUPComponentType, clusterID, collectorYaml, remotePath, home, workFlowID string, args []string, timeout int) (string, error) {
+				assert.Contains(t, collectorYaml, "- "+constants.AlertRuleFileName)
+				return "push01", nil
+			})
+
+		ctx := &workflow.FlowContext{
+			Context:  context.TODO(),
+			FlowData: make(map[string]string),
+		}
+		ctx.SetData(contextClusterMeta, mockClusterMeta("register-cluster"))
+		node := &workflowModel.WorkFlowNode{ParentID: "flow01"}
+		assert.NoError(t, registerRuleFile(node, ctx))
+		assert.Equal(t, "push01", node.OperationID)
+	})
+	t.Run("registered", func(t *testing.T) {
+		mockDeployment.EXPECT().Pull(gomock.Any(), deployment.TiUPComponentTypeCluster, "register-cluster", remotePath, gomock.Any(), []string{"-N", "127.0.0.1"}, 0).
+			Return("rule_files:\n- node.rules.yml\n- "+constants.AlertRuleFileName+"\n", nil)
+
+		ctx := &workflow.FlowContext{
+			Context:  context.TODO(),
+			FlowData: make(map[string]string),
+		}
+		ctx.SetData(contextClusterMeta, mockClusterMeta("register-cluster"))
+		node := &workflowModel.WorkFlowNode{ParentID: "flow01"}
+		assert.NoError(t, registerRuleFile(node, ctx))
+		assert.Empty(t, node.OperationID)
+		assert.Equal(t, constants.WorkFlowStatusFinished, node.Status)
+	})
+}
+
+func TestExecutor_appendRuleFile(t *testing.T) {
+	t.Run("append", func(t *testing.T) {
+		patched, changed, err := appendRuleFile("global:\n  scrape_interval: 15s\nrule_files:\n- node.rules.yml\nscrape_configs: []\n", "a.rules.yml")
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, "global:\n  scrape_interval: 15s\nrule_files:\n- node.rules.yml\n- a.rules.yml\nscrape_configs: []\n", patched)
+	})
+	t.Run("absent", func(t *testing.T) {
+		patched, changed, err := appendRuleFile("global:\n  scrape_interval: 15s\n", "a.rules.yml")
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, "global:\n  scrape_interval: 15s\nrule_files:\n- a.rules.yml\n", patched)
+	})
+	t.Run("registered", func(t *testing.T) {
+		_, changed, err := appendRuleFile("rule_files:\n- a.rules.yml\n", "a.rules.yml")
+		assert.NoError(t, err)
+		assert.False(t, changed)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := appendRuleFile("rule_files: [", "a.rules.yml")
+		assert.Error(t, err)
+	})
+}
+
+func TestExecutor_reloadPrometheus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mockutilprometheus.NewMockQueryService(ctrl)
+	prometheus.PrometheusService = service
+	service.EXPECT().Reload(gomock.Any(), "127.0.0.1:9090").Return(nil)
+
+	ctx := &workflow.FlowContext{
+		Context:  context.TODO(),
+		FlowData: make(map[string]string),
+	}
+	ctx.SetData(contextClusterMeta, mockClusterMeta("reload-cluster"))
+	assert.NoError(t, reloadPrometheus(&workflowModel.WorkFlowNode{ParentID: "flow01"}, ctx))
+}
+
+func TestExecutor_checkAlertRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mockutilprometheus.NewMockQueryService(ctrl)
+	prometheus.PrometheusService = service
+
+	clusterMeta := mockClusterMeta("check-cluster")
+	assert.NoError(t, ensureBuiltInRules(context.TODO(), clusterMeta))
+	loaded := make([]string, 0, len(builtInRules))
+	for _, rule := range builtInRules {
+		loaded = append(loaded, rule.Name)
+	}
+	ctx := &workflow.FlowContext{
+		Context:  context.TODO(),
+		FlowData: make(map[string]string),
+	}
+	ctx.SetData(contextClusterMeta, clusterMeta)
+
+	t.Run("loaded", func(t *testing.T) {
+		service.EXPECT().QueryRuleGroups(gomock.Any(), "127.0.0.1:9090").Return([]prometheus.RuleGroup{
+			{Name: "alert.rules", File: "/deploy/conf/node.rules.yml", Alerts: []string{"NODE_disk_used_more_than_80%"}},
+			{Name: constants.AlertRuleGroupName, File: "/deploy/conf/" + constants.AlertRuleFileName, Alerts: loaded},
+		}, nil)
+		assert.NoError(t, checkAlertRules(&workflowModel.WorkFlowNode{}, ctx))
+	})
+	t.Run("rule file not loaded", func(t *testing.T) {
+		service.EXPECT().QueryRuleGroups(gomock.Any(), "127.0.0.1:9090").Return([]prometheus.RuleGroup{
+			{Name: "alert.rules", File: "/deploy/conf/node.rules.yml"},
+		}, nil)
+		err := checkAlertRules(&workflowModel.WorkFlowNode{}, ctx)
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_ALERT_RULE_NOT_LOADED, err.(errors.EMError).GetCode())
+	})
+	t.Run("rule not loaded", func(t *testing.T) {
+		service.EXPECT().QueryRuleGroups(gomock.Any(), "127.0.0.1:9090").Return([]prometheus.RuleGroup{
+			{Name: constants.AlertRuleGroupName, File: "/deploy/conf/" + constants.AlertRuleFileName, Alerts: loaded[1:]},
+		}, nil)
+		err := checkAlertRules(&workflowModel.WorkFlowNode{}, ctx)
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_ALERT_RULE_NOT_LOADED, err.(errors.EMError).GetCode())
+	})
+}
+
+func TestExecutor_pushAlertManagerConfig(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDeployment := mock_deployment.NewMockInterface(ctrl)
+	deployment.M = mockDeployment
+	assert.NoError(t, models.GetAlertReaderWriter().SaveReceiver(context.TODO(), &alert.AlertReceiver{ClusterID: "receiver-cluster", Token: "token"}))
+
+	mockDeployment.EXPECT().Push(gomock.Any(), deployment.TiUPComponentTypeCluster, "receiver-cluster", gomock.Any(),
+		gomock.Any(), gomock.Any(), "flow01", []string{"-N", "127.0.0.1"}, 0).
+		DoAndReturn(func(ctx context.Context, componentType deployment.TiUPComponentType, clusterID, collectorYaml, remotePath, home, workFlowID string, args []string, timeout int) (string, error) {
+			assert.True(t, strings.HasSuffix(remotePath, "/conf/"+constants.AlertManagerConfigFileName))
+			assert.Contains(t, collectorYaml, "http://127.0.0.1:4100/api/v1/alerts/receiver/receiver-cluster")
+			assert.Contains(t, collectorYaml, "bearer_token: token")
+			return "push01", nil
+		})
+
+	ctx := &workflow.FlowContext{
+		Context:  context.TODO(),
+		FlowData: make(map[string]string),
+	}
+	ctx.SetData(contextClusterMeta, mockClusterMeta("receiver-cluster"))
+	ctx.SetData(contextReceiverURL, "http://127.0.0.1:4100/api/v1/alerts/receiver/receiver-cluster")
+	node := &workflowModel.WorkFlowNode{ParentID: "flow01"}
+	err := pushAlertManagerConfig(node, ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "push01", node.OperationID)
+
+	t.Run("without alertmanager", func(t *testing.T) {
+		clusterMeta := mockClusterMeta("receiver-cluster")
+		delete(clusterMeta.Instances, string(constants.ComponentIDAlertManger))
+		ctx.SetData(contextClusterMeta, clusterMeta)
+		err := pushAlertManagerConfig(&workflowModel.WorkFlowNode{}, ctx)
+		assert.Error(t, err)
+	})
+}
+
+func TestExecutor_reloadAlertManager(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mockutilalertmanager.NewMockAlertService(ctrl)
+	alertmanager.AlertManagerService = service
+	service.EXPECT().Reload(gomock.Any(), "127.0.0.1:9093").Return(nil)
+
+	ctx := &workflow.FlowContext{
+		Context:  context.TODO(),
+		FlowData: make(map[string]string),
+	}
+	ctx.SetData(contextClusterMeta, mockClusterMeta("reload-cluster"))
+	err := reloadAlertManager(&workflowModel.WorkFlowNode{ParentID: "flow01"}, ctx)
+	assert.NoError(t, err)
+}
+
+func TestExecutor_buildAlertManagerConfig(t *testing.T) {
+	content, err := buildAlertManagerConfig("http://127.0.0.1:4100/api/v1/alerts/receiver/cluster01", "token")
+	assert.NoError(t, err)
+
+	amConfig := alertManagerConfig{}
+	assert.NoError(t, yaml.Unmarshal([]byte(content), &amConfig))
+	assert.Equal(t, constants.AlertReceiverName, amConfig.Route.Receiver)
+	assert.Equal(t, 1, len(amConfig.Receivers))
+	assert.Equal(t, constants.AlertReceiverName, amConfig.Receivers[0].Name)
+	assert.Equal(t, "http://127.0.0.1:4100/api/v1/alerts/receiver/cluster01", amConfig.Receivers[0].WebhookConfigs[0].URL)
+	assert.True(t, amConfig.Receivers[0].WebhookConfigs[0].SendResolved)
+	assert.Equal(t, "token", amConfig.Receivers[0].WebhookConfigs[0].HTTPConfig.BearerToken)
+}
+
+func TestExecutor_buildRuleFile(t *testing.T) {
+	t.Run("normal", func(t *testing.T) {
+		content, err := buildRuleFile("cluster01", []*alert.AlertRule{
+			{Name: "rule1", Expr: "up == 0", Duration: "1m", Severity: "critical", Summary: "down"},
+			{Name: "rule2", Expr: "up == 2", Severity: "warning"},
+		})
+		assert.NoError(t, err)
+
+		file := ruleFile{}
+		assert.NoError(t, yaml.Unmarshal([]byte(content), &file))
+		assert.Equal(t, 1, len(file.Groups))
+		assert.Equal(t, constants.AlertRuleGroupName, file.Groups[0].Name)
+		assert.Equal(t, 2, len(file.Groups[0].Rules))
+		assert.Equal(t, "cluster01", file.Groups[0].Rules[0].Labels[constants.AlertLabelCluster])
+		assert.Equal(t, "critical", file.Groups[0].Rules[0].Labels[constants.AlertLabelSeverity])
+		assert.Equal(t, "down", file.Groups[0].Rules[0].Annotations[annotationSummary])
+		assert.False(t, strings.Contains(content, "for: \"\""))
+	})
+	t.Run("empty", func(t *testing.T) {
+		content, err := buildRuleFile("cluster01", []*alert.AlertRule{})
+		assert.NoError(t, err)
+		assert.Contains(t, content, constants.AlertRuleGroupName)
+	})
+}
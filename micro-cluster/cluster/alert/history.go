@@ -0,0 +1,143 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/alert"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	"github.com/pingcap/tiunimanager/util/api/alertmanager"
+	"github.com/robfig/cron"
+)
+
+var historySchedulerOnce sync.Once
+
+type historyHandler struct{}
+
+// StartAlertHistoryScheduler
+// @Description: start the scheduler which records alerts of running clusters into alert history
+func StartAlertHistoryScheduler() {
+	historySchedulerOnce.Do(func() {
+		jobCron := cron.New()
+		if err := jobCron.AddJob(historyJobSpec, &historyHandler{}); err != nil {
+			framework.Log().Fatalf("add alert history cron job failed, %s", err.Error())
+			return
+		}
+		go func() {
+			time.Sleep(5 * time.Second) //wait db client ready
+			jobCron.Start()
+		}()
+	})
+}
+
+func (h *historyHandler) Run() {
+	results, _, err := models.GetClusterReaderWriter().QueryMetas(context.TODO(), management.Filters{
+		StatusFilters: []constants.ClusterRunningStatus{constants.ClusterRunning},
+	}, structs.PageRequest{Page: 1, PageSize: math.MaxInt32})
+	if err != nil {
+		framework.Log().Errorf("query running clusters failed, %s", err.Error())
+		return
+	}
+
+	for _, result := range results {
+		address := findAlertManagerAddress(result.Instances)
+		if len(address) == 0 {
+			continue
+		}
+		if err = syncAlertHistory(context.TODO(), result.Cluster, address); err != nil {
+			framework.Log().Errorf("sync alert history of cluster %s failed, %s", result.Cluster.ID, err.Error())
+		}
+	}
+}
+
+// syncAlertHistory
+// @Description: record new alerts of alertmanager as firing, and mark recorded alerts which disappear as resolved
+// @Parameter ctx
+// @Parameter clusterInfo
+// @Parameter address
+// @return error
+func syncAlertHistory(ctx context.Context, clusterInfo *management.Cluster, address string) error {
+	alerts, err := alertmanager.AlertManagerService.QueryAlerts(ctx, address)
+	if err != nil {
+		return err
+	}
+	rw := models.GetAlertReaderWriter()
+	firing, err := rw.QueryFiringHistory(ctx, clusterInfo.ID)
+	if err != nil {
+		return err
+	}
+
+	recorded := make(map[string]*alert.AlertHistory)
+	for _, history := range firing {
+		recorded[history.Fingerprint] = history
+	}
+	current := make(map[string]bool)
+	for _, a := range alerts {
+		current[a.Fingerprint] = true
+		if _, ok := recorded[a.Fingerprint]; ok {
+			continue
+		}
+		labels, err := json.Marshal(a.Labels)
+		if err != nil {
+			return err
+		}
+		history := &alert.AlertHistory{
+			ClusterID:   clusterInfo.ID,
+			Fingerprint: a.Fingerprint,
+			Name:        a.Labels[constants.AlertLabelName],
+			Severity:    a.Labels[constants.AlertLabelSeverity],
+			Summary:     a.Annotations[annotationSummary],
+			Labels:      string(labels),
+			StartsAt:    a.StartsAt,
+		}
+		history.TenantId = clusterInfo.TenantId
+		if _, err = rw.CreateHistory(ctx, history); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	for fingerprint, history := range recorded {
+		if current[fingerprint] {
+			continue
+		}
+		if err = rw.ResolveHistory(ctx, history.ID, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func findAlertManagerAddress(instances []*management.ClusterInstance) string {
+	for _, instance := range instances {
+		if instance.Type == string(constants.ComponentIDAlertManger) &&
+			instance.Status == string(constants.ClusterInstanceRunning) &&
+			len(instance.HostIP) > 0 && len(instance.Ports) > 0 {
+			return fmt.Sprintf("%s:%d", instance.HostIP[0], instance.Ports[0])
+		}
+	}
+	return ""
+}
@@ -0,0 +1,90 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package alert
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert"
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/test/mockmodels/mockclustermanagement"
+	"github.com/pingcap/tiunimanager/test/mockutilalertmanager"
+	"github.com/pingcap/tiunimanager/util/api/alertmanager"
+)
+
+func TestSyncAlertHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+	models.SetClusterReaderWriter(clusterRW)
+	mockGetMeta(clusterRW)
+	alertService := mockutilalertmanager.NewMockAlertService(ctrl)
+	alertmanager.AlertManagerService = alertService
+
+	clusterInfo := mockCluster("history-sync")
+	down := alertmanager.Alert{
+		Fingerprint: "f1",
+		Labels:      map[string]string{constants.AlertLabelName: "TiDB_server_is_down", constants.AlertLabelSeverity: "emergency"},
+		Annotations: map[string]string{annotationSummary: "TiDB server is down"},
+		StartsAt:    time.Now().Add(-time.Minute),
+	}
+	slow := alertmanager.Alert{
+		Fingerprint: "f2",
+		Labels:      map[string]string{constants.AlertLabelName: "TiDB_query_duration", constants.AlertLabelSeverity: "warning"},
+		StartsAt:    time.Now(),
+	}
+
+	gomock.InOrder(
+		alertService.EXPECT().QueryAlerts(gomock.Any(), "127.0.0.1:9093").Return([]alertmanager.Alert{down}, nil),
+		alertService.EXPECT().QueryAlerts(gomock.Any(), "127.0.0.1:9093").Return([]alertmanager.Alert{down, slow}, nil),
+		alertService.EXPECT().QueryAlerts(gomock.Any(), "127.0.0.1:9093").Return([]alertmanager.Alert{slow}, nil),
+	)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, syncAlertHistory(context.TODO(), clusterInfo, "127.0.0.1:9093"))
+	}
+
+	resp, total, err := mockManager.QueryAlertHistory(context.TODO(), cluster.QueryAlertHistoryReq{
+		ClusterID:   "history-sync",
+		PageRequest: structs.PageRequest{Page: 1, PageSize: 10},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, "TiDB_query_duration", resp.Alerts[0].Name)
+	assert.Equal(t, string(constants.AlertFiring), resp.Alerts[0].State)
+	assert.Equal(t, "TiDB_server_is_down", resp.Alerts[1].Name)
+	assert.Equal(t, string(constants.AlertResolved), resp.Alerts[1].State)
+	assert.Equal(t, "emergency", resp.Alerts[1].Labels[constants.AlertLabelSeverity])
+
+	_, total, err = mockManager.QueryAlertHistory(context.TODO(), cluster.QueryAlertHistoryReq{
+		ClusterID:   "history-sync",
+		State:       string(constants.AlertFiring),
+		PageRequest: structs.PageRequest{Page: 1, PageSize: 10},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+}
+
+func TestFindAlertManagerAddress(t *testing.T) {
+	assert.Equal(t, "127.0.0.1:9093", findAlertManagerAddress(mockClusterInstances("address")))
+	assert.Equal(t, "", findAlertManagerAddress(mockClusterInstances("address")[:1]))
+}
@@ -0,0 +1,100 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package alert
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	"github.com/pingcap/tiunimanager/models/common"
+	"github.com/pingcap/tiunimanager/test/mockmodels/mockclustermanagement"
+)
+
+var mockManager = NewManager()
+
+func TestMain(m *testing.M) {
+	var testFilePath string
+	framework.InitBaseFrameworkForUt(framework.ClusterService,
+		func(d *framework.BaseFramework) error {
+			testFilePath = d.GetDataDir()
+			os.MkdirAll(testFilePath, 0755)
+			models.MockDB()
+			return models.Open(d)
+		},
+	)
+	code := m.Run()
+	os.RemoveAll(testFilePath)
+
+	os.Exit(code)
+}
+
+func mockCluster(clusterID string) *management.Cluster {
+	return &management.Cluster{
+		Entity:  common.Entity{ID: clusterID, TenantId: "1", Status: string(constants.ClusterRunning)},
+		Name:    "testCluster",
+		Type:    "TiDB",
+		Version: "v5.2.2",
+	}
+}
+
+func mockClusterInstances(clusterID string) []*management.ClusterInstance {
+	return []*management.ClusterInstance{
+		{
+			Entity:    common.Entity{ID: clusterID + "-prometheus", TenantId: "1", Status: string(constants.ClusterInstanceRunning)},
+			Type:      string(constants.ComponentIDPrometheus),
+			Version:   "v5.2.2",
+			ClusterID: clusterID,
+			HostID:    "host1",
+			HostIP:    []string{"127.0.0.1"},
+			Ports:     []int32{9090},
+			DiskPath:  "/mnt/sda",
+		},
+		{
+			Entity:    common.Entity{ID: clusterID + "-alertmanager", TenantId: "1", Status: string(constants.ClusterInstanceRunning)},
+			Type:      string(constants.ComponentIDAlertManger),
+			Version:   "v5.2.2",
+			ClusterID: clusterID,
+			HostID:    "host1",
+			HostIP:    []string{"127.0.0.1"},
+			Ports:     []int32{9093, 9094},
+			DiskPath:  "/mnt/sda",
+		},
+	}
+}
+
+func mockClusterMeta(clusterID string) *meta.ClusterMeta {
+	return &meta.ClusterMeta{
+		Cluster: mockCluster(clusterID),
+		Instances: map[string][]*management.ClusterInstance{
+			string(constants.ComponentIDPrometheus):  mockClusterInstances(clusterID)[:1],
+			string(constants.ComponentIDAlertManger): mockClusterInstances(clusterID)[1:],
+		},
+	}
+}
+
+func mockGetMeta(clusterRW *mockclustermanagement.MockReaderWriter) {
+	clusterRW.EXPECT().GetMeta(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, clusterID string) (*management.Cluster, []*management.ClusterInstance, []*management.DBUser, error) {
+			return mockCluster(clusterID), mockClusterInstances(clusterID), []*management.DBUser{}, nil
+		}).AnyTimes()
+}
@@ -0,0 +1,735 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package alert
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/micro-cluster/user/rbac"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/alert"
+	dbCommon "github.com/pingcap/tiunimanager/models/common"
+	"github.com/pingcap/tiunimanager/util/api/alertmanager"
+	workflow "github.com/pingcap/tiunimanager/workflow2"
+)
+
+type Manager struct{}
+
+var manager *Manager
+var once sync.Once
+
+func NewManager() *Manager {
+	once.Do(func() {
+		if manager == nil {
+			workflowManager := workflow.GetWorkFlowService()
+			workflowManager.RegisterWorkFlow(context.TODO(), constants.FlowApplyAlertRules, &applyAlertRulesDefine)
+			workflowManager.RegisterWorkFlow(context.TODO(), constants.FlowApplyAlertReceiver, &applyAlertReceiverDefine)
+
+			manager = &Manager{}
+		}
+	})
+	return manager
+}
+
+var applyAlertRulesDefine = workflow.WorkFlowDefine{
+	FlowName: constants.FlowApplyAlertRules,
+	TaskNodes: map[string]*workflow.NodeDefine{
+		"start":        {"pushAlertRules", "pushDone", "fail", workflow.PollingNode, pushAlertRules, nil},
+		"pushDone":     {"registerRuleFile", "registerDone", "fail", workflow.PollingNode, registerRuleFile, nil},
+		"registerDone": {"reloadPrometheus", "reloadDone", "fail", workflow.SyncFuncNode, reloadPrometheus, nil},
+		"reloadDone":   {"checkAlertRules", "checkDone", "fail", workflow.SyncFuncNode, checkAlertRules, nil},
+		"checkDone":    {"end", "", "", workflow.SyncFuncNode, defaultEnd, nil},
+		"fail":         {"fail", "", "", workflow.SyncFuncNode, defaultEnd, nil},
+	},
+}
+
+var applyAlertReceiverDefine = workflow.WorkFlowDefine{
+	FlowName: constants.FlowApplyAlertReceiver,
+	TaskNodes: map[string]*workflow.NodeDefine{
+		"start":      {"pushAlertManagerConfig", "pushDone", "fail", workflow.PollingNode, pushAlertManagerConfig, nil},
+		"pushDone":   {"reloadAlertManager", "reloadDone", "fail", workflow.SyncFuncNode, reloadAlertManager, nil},
+		"reloadDone": {"end", "", "", workflow.SyncFuncNode, defaultEnd, nil},
+		"fail":       {"fail", "", "", workflow.SyncFuncNode, defaultEnd, nil},
+	},
+}
+
+// durationPattern duration format of prometheus rule
+var durationPattern = regexp.MustCompile(`^[0-9]+(ms|s|m|h|d|w|y)$`)
+
+// QueryFiringAlerts
+// @Description: query alerts which are firing in alertmanager of cluster
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (m *Manager) QueryFiringAlerts(ctx context.Context, req cluster.QueryFiringAlertsReq) (resp cluster.QueryFiringAlertsResp, err error) {
+	clusterMeta, err := getClusterMeta(ctx, req.ClusterID, constants.RbacActionRead)
+	if err != nil {
+		return
+	}
+	address, err := getAlertManagerAddress(clusterMeta)
+	if err != nil {
+		return
+	}
+
+	alerts, err := alertmanager.AlertManagerService.QueryAlerts(ctx, address)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query alerts of cluster %s failed, err = %s", req.ClusterID, err.Error())
+		return
+	}
+	resp.Alerts = make([]structs.FiringAlertInfo, 0, len(alerts))
+	for _, a := range alerts {
+		if len(req.Severity) > 0 && a.Labels[constants.AlertLabelSeverity] != req.Severity {
+			continue
+		}
+		resp.Alerts = append(resp.Alerts, structs.FiringAlertInfo{
+			Fingerprint: a.Fingerprint,
+			Name:        a.Labels[constants.AlertLabelName],
+			Severity:    a.Labels[constants.AlertLabelSeverity],
+			State:       a.Status.State,
+			Summary:     a.Annotations[annotationSummary],
+			Labels:      a.Labels,
+			Annotations: a.Annotations,
+			SilencedBy:  a.Status.SilencedBy,
+			StartsAt:    a.StartsAt,
+		})
+	}
+	return
+}
+
+// QueryAlertHistory
+// @Description: query alerts which have been firing in cluster
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return total
+// @return err
+func (m *Manager) QueryAlertHistory(ctx context.Context, req cluster.QueryAlertHistoryReq) (resp cluster.QueryAlertHistoryResp, total int, err error) {
+	var startTime, endTime time.Time
+	if req.StartTime > 0 {
+		startTime = time.Unix(req.StartTime, 0)
+	}
+	if req.EndTime > 0 {
+		endTime = time.Unix(req.EndTime, 0)
+	}
+	if req.StartTime > 0 && req.EndTime > 0 && startTime.After(endTime) {
+		err = errors.NewErrorf(errors.TIUNIMANAGER_PARAMETER_INVALID, "start time %d is after end time %d", req.StartTime, req.EndTime)
+		return
+	}
+	if _, err = getClusterMeta(ctx, req.ClusterID, constants.RbacActionRead); err != nil {
+		return
+	}
+
+	histories, count, err := models.GetAlertReaderWriter().QueryHistory(ctx, req.ClusterID, req.Name, req.Severity,
+		constants.AlertState(req.State), startTime, endTime, req.GetOffset(), req.PageSize)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query alert history of cluster %s failed, err = %s", req.ClusterID, err.Error())
+		return
+	}
+	resp.Alerts = make([]structs.AlertHistoryInfo, 0, len(histories))
+	for _, history := range histories {
+		labels := make(map[string]string)
+		if len(history.Labels) > 0 {
+			if err = json.Unmarshal([]byte(history.Labels), &labels); err != nil {
+				framework.LogWithContext(ctx).Errorf("unmarshal labels of alert history %s failed, err = %s", history.ID, err.Error())
+				err = errors.WrapError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, "", err)
+				return
+			}
+		}
+		resp.Alerts = append(resp.Alerts, structs.AlertHistoryInfo{
+			ID:          history.ID,
+			ClusterID:   history.ClusterID,
+			Fingerprint: history.Fingerprint,
+			Name:        history.Name,
+			Severity:    history.Severity,
+			State:       history.Status,
+			Summary:     history.Summary,
+			Labels:      labels,
+			StartsAt:    history.StartsAt,
+			EndsAt:      history.EndsAt,
+		})
+	}
+	total = int(count)
+	return
+}
+
+// QueryAlertRules
+// @Description: query alert rules of cluster, built-in rules are initialized if absent
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return total
+// @return err
+func (m *Manager) QueryAlertRules(ctx context.Context, req cluster.QueryAlertRulesReq) (resp cluster.QueryAlertRulesResp, total int, err error) {
+	clusterMeta, err := getClusterMeta(ctx, req.ClusterID, constants.RbacActionRead)
+	if err != nil {
+		return
+	}
+	if err = ensureBuiltInRules(ctx, clusterMeta); err != nil {
+		framework.LogWithContext(ctx).Errorf("init built-in alert rules of cluster %s failed, err = %s", req.ClusterID, err.Error())
+		return
+	}
+
+	rules, count, err := models.GetAlertReaderWriter().QueryRules(ctx, req.ClusterID, constants.AlertRuleType(req.Type), "", req.GetOffset(), req.PageSize)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query alert rules of cluster %s failed, err = %s", req.ClusterID, err.Error())
+		return
+	}
+	resp.Rules = make([]structs.AlertRuleInfo, 0, len(rules))
+	for _, rule := range rules {
+		resp.Rules = append(resp.Rules, convertRuleInfo(rule))
+	}
+	total = int(count)
+	return
+}
+
+// CreateAlertRule
+// @Description: add a custom alert rule to cluster and apply enabled rules to prometheus
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (m *Manager) CreateAlertRule(ctx context.Context, req cluster.CreateAlertRuleReq) (resp cluster.CreateAlertRuleResp, err error) {
+	if err = validateRule(req.Severity, req.For); err != nil {
+		return
+	}
+	clusterMeta, err := getClusterMeta(ctx, req.ClusterID, constants.RbacActionUpdate)
+	if err != nil {
+		return
+	}
+	if err = ensureBuiltInRules(ctx, clusterMeta); err != nil {
+		framework.LogWithContext(ctx).Errorf("init built-in alert rules of cluster %s failed, err = %s", req.ClusterID, err.Error())
+		return
+	}
+
+	rule := &alert.AlertRule{
+		ClusterID: req.ClusterID,
+		Name:      req.Name,
+		Type:      string(constants.AlertRuleCustom),
+		Component: req.Component,
+		Expr:      req.Expr,
+		Duration:  req.For,
+		Severity:  req.Severity,
+		Summary:   req.Summary,
+	}
+	rule.TenantId = clusterMeta.Cluster.TenantId
+	if err = models.GetAlertReaderWriter().CreateRules(ctx, []*alert.AlertRule{rule}); err != nil {
+		framework.LogWithContext(ctx).Errorf("create alert rule %s of cluster %s failed, err = %s", req.Name, req.ClusterID, err.Error())
+		return
+	}
+
+	resp.Rule = convertRuleInfo(rule)
+	resp.WorkFlowID, err = m.applyAlertRules(ctx, clusterMeta)
+	return
+}
+
+// UpdateAlertRule
+// @Description: modify a custom alert rule and apply enabled rules to prometheus
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (m *Manager) UpdateAlertRule(ctx context.Context, req cluster.UpdateAlertRuleReq) (resp cluster.UpdateAlertRuleResp, err error) {
+	if err = validateRule(req.Severity, req.For); err != nil {
+		return
+	}
+	clusterMeta, err := getClusterMeta(ctx, req.ClusterID, constants.RbacActionUpdate)
+	if err != nil {
+		return
+	}
+	rw := models.GetAlertReaderWriter()
+	rule, err := rw.GetRule(ctx, req.ClusterID, req.RuleID)
+	if err != nil {
+		return
+	}
+	if rule.Type == string(constants.AlertRuleBuiltIn) {
+		err = errors.NewErrorf(errors.TIUNIMANAGER_ALERT_RULE_BUILTIN_READONLY, "alert rule %s is built-in", rule.Name)
+		return
+	}
+
+	rule.Expr = req.Expr
+	rule.Duration = req.For
+	rule.Severity = req.Severity
+	rule.Summary = req.Summary
+	if err = rw.UpdateRule(ctx, rule); err != nil {
+		framework.LogWithContext(ctx).Errorf("update alert rule %s of cluster %s failed, err = %s", req.RuleID, req.ClusterID, err.Error())
+		return
+	}
+
+	resp.Rule = convertRuleInfo(rule)
+	resp.WorkFlowID, err = m.applyAlertRules(ctx, clusterMeta)
+	return
+}
+
+// SetAlertRuleEnabled
+// @Description: enable or disable an alert rule and apply enabled rules to prometheus
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (m *Manager) SetAlertRuleEnabled(ctx context.Context, req cluster.SetAlertRuleEnabledReq) (resp cluster.SetAlertRuleEnabledResp, err error) {
+	clusterMeta, err := getClusterMeta(ctx, req.ClusterID, constants.RbacActionUpdate)
+	if err != nil {
+		return
+	}
+	rw := models.GetAlertReaderWriter()
+	rule, err := rw.GetRule(ctx, req.ClusterID, req.RuleID)
+	if err != nil {
+		return
+	}
+
+	rule.Status = string(constants.AlertRuleDisabled)
+	if req.Enabled {
+		rule.Status = string(constants.AlertRuleEnabled)
+	}
+	if err = rw.UpdateRule(ctx, rule); err != nil {
+		framework.LogWithContext(ctx).Errorf("update status of alert rule %s of cluster %s failed, err = %s", req.RuleID, req.ClusterID, err.Error())
+		return
+	}
+
+	resp.Rule = convertRuleInfo(rule)
+	resp.WorkFlowID, err = m.applyAlertRules(ctx, clusterMeta)
+	return
+}
+
+// DeleteAlertRule
+// @Description: delete a custom alert rule and apply enabled rules to prometheus
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (m *Manager) DeleteAlertRule(ctx context.Context, req cluster.DeleteAlertRuleReq) (resp cluster.DeleteAlertRuleResp, err error) {
+	clusterMeta, err := getClusterMeta(ctx, req.ClusterID, constants.RbacActionUpdate)
+	if err != nil {
+		return
+	}
+	rw := models.GetAlertReaderWriter()
+	rule, err := rw.GetRule(ctx, req.ClusterID, req.RuleID)
+	if err != nil {
+		return
+	}
+	if rule.Type == string(constants.AlertRuleBuiltIn) {
+		err = errors.NewErrorf(errors.TIUNIMANAGER_ALERT_RULE_BUILTIN_READONLY, "alert rule %s is built-in", rule.Name)
+		return
+	}
+	if err = rw.DeleteRule(ctx, req.ClusterID, req.RuleID); err != nil {
+		framework.LogWithContext(ctx).Errorf("delete alert rule %s of cluster %s failed, err = %s", req.RuleID, req.ClusterID, err.Error())
+		return
+	}
+
+	resp.RuleID = req.RuleID
+	resp.WorkFlowID, err = m.applyAlertRules(ctx, clusterMeta)
+	return
+}
+
+// CreateAlertSilence
+// @Description: silence alerts of cluster in alertmanager during a period
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (m *Manager) CreateAlertSilence(ctx context.Context, req cluster.CreateAlertSilenceReq) (resp cluster.CreateAlertSilenceResp, err error) {
+	now := time.Now()
+	startsAt := now
+	if req.StartTime > 0 {
+		startsAt = time.Unix(req.StartTime, 0)
+	}
+	endsAt := time.Unix(req.EndTime, 0)
+	if !endsAt.After(startsAt) || !endsAt.After(now) {
+		err = errors.NewErrorf(errors.TIUNIMANAGER_ALERT_SILENCE_INVALID, "end time %d should be after both start time and now", req.EndTime)
+		return
+	}
+
+	clusterMeta, err := getClusterMeta(ctx, req.ClusterID, constants.RbacActionUpdate)
+	if err != nil {
+		return
+	}
+	address, err := getAlertManagerAddress(clusterMeta)
+	if err != nil {
+		return
+	}
+
+	operator := framework.GetUserIDFromContext(ctx)
+	matchers := make([]alertmanager.Matcher, 0, len(req.Matchers))
+	for _, matcher := range req.Matchers {
+		matchers = append(matchers, alertmanager.Matcher{Name: matcher.Name, Value: matcher.Value, IsRegex: matcher.IsRegex})
+	}
+	silenceID, err := alertmanager.AlertManagerService.CreateSilence(ctx, address, alertmanager.Silence{
+		Matchers:  matchers,
+		StartsAt:  startsAt,
+		EndsAt:    endsAt,
+		CreatedBy: operator,
+		Comment:   req.Comment,
+	})
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("create silence of cluster %s failed, err = %s", req.ClusterID, err.Error())
+		return
+	}
+
+	matchersJson, err := json.Marshal(req.Matchers)
+	if err != nil {
+		err = errors.WrapError(errors.TIUNIMANAGER_MARSHAL_ERROR, "", err)
+		return
+	}
+	silence := &alert.AlertSilence{
+		ClusterID: req.ClusterID,
+		SilenceID: silenceID,
+		Matchers:  string(matchersJson),
+		Comment:   req.Comment,
+		CreatedBy: operator,
+		StartsAt:  startsAt,
+		EndsAt:    endsAt,
+	}
+	silence.TenantId = clusterMeta.Cluster.TenantId
+	if silence, err = models.GetAlertReaderWriter().CreateSilence(ctx, silence); err != nil {
+		framework.LogWithContext(ctx).Errorf("save silence %s of cluster %s failed, err = %s", silenceID, req.ClusterID, err.Error())
+		return
+	}
+	resp.Silence, err = convertSilenceInfo(silence)
+	return
+}
+
+// QueryAlertSilences
+// @Description: query alert silences of cluster, silences which have passed their end time are marked as expired
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return total
+// @return err
+func (m *Manager) QueryAlertSilences(ctx context.Context, req cluster.QueryAlertSilencesReq) (resp cluster.QueryAlertSilencesResp, total int, err error) {
+	if _, err = getClusterMeta(ctx, req.ClusterID, constants.RbacActionRead); err != nil {
+		return
+	}
+	rw := models.GetAlertReaderWriter()
+	active, _, err := rw.QuerySilences(ctx, req.ClusterID, constants.AlertSilenceActive, 0, -1)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query active silences of cluster %s failed, err = %s", req.ClusterID, err.Error())
+		return
+	}
+	now := time.Now()
+	for _, silence := range active {
+		if silence.EndsAt.Before(now) {
+			if err = rw.UpdateSilenceStatus(ctx, silence.ID, constants.AlertSilenceExpired); err != nil {
+				return
+			}
+		}
+	}
+
+	silences, count, err := rw.QuerySilences(ctx, req.ClusterID, constants.AlertSilenceStatus(req.Status), req.GetOffset(), req.PageSize)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query silences of cluster %s failed, err = %s", req.ClusterID, err.Error())
+		return
+	}
+	resp.Silences = make([]structs.AlertSilenceInfo, 0, len(silences))
+	for _, silence := range silences {
+		info, convertErr := convertSilenceInfo(silence)
+		if convertErr != nil {
+			err = convertErr
+			return
+		}
+		resp.Silences = append(resp.Silences, info)
+	}
+	total = int(count)
+	return
+}
+
+// DeleteAlertSilence
+// @Description: expire an alert silence before its end time
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (m *Manager) DeleteAlertSilence(ctx context.Context, req cluster.DeleteAlertSilenceReq) (resp cluster.DeleteAlertSilenceResp, err error) {
+	clusterMeta, err := getClusterMeta(ctx, req.ClusterID, constants.RbacActionUpdate)
+	if err != nil {
+		return
+	}
+	rw := models.GetAlertReaderWriter()
+	silence, err := rw.GetSilence(ctx, req.ClusterID, req.SilenceID)
+	if err != nil {
+		return
+	}
+
+	if silence.Status == string(constants.AlertSilenceActive) && silence.EndsAt.After(time.Now()) {
+		address, addressErr := getAlertManagerAddress(clusterMeta)
+		if addressErr != nil {
+			err = addressErr
+			return
+		}
+		if err = alertmanager.AlertManagerService.DeleteSilence(ctx, address, silence.SilenceID); err != nil {
+			framework.LogWithContext(ctx).Errorf("delete silence %s of cluster %s failed, err = %s", silence.SilenceID, req.ClusterID, err.Error())
+			return
+		}
+	}
+
+	if err = rw.UpdateSilenceStatus(ctx, silence.ID, constants.AlertSilenceExpired); err != nil {
+		return
+	}
+	resp.SilenceID = req.SilenceID
+	return
+}
+
+// ConfigureAlertReceiver
+// @Description: configure alertmanager of cluster to send alerts to the receiver of the platform, which fans out them to notification channels
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (m *Manager) ConfigureAlertReceiver(ctx context.Context, req cluster.ConfigureAlertReceiverReq) (resp cluster.ConfigureAlertReceiverResp, err error) {
+	clusterMeta, err := getClusterMeta(ctx, req.ClusterID, constants.RbacActionUpdate)
+	if err != nil {
+		return
+	}
+	if _, err = getAlertManagerAddress(clusterMeta); err != nil {
+		return
+	}
+	if err = ensureAlertReceiverToken(ctx, clusterMeta); err != nil {
+		framework.LogWithContext(ctx).Errorf("init alert receiver token of cluster %s failed, err = %s", req.ClusterID, err.Error())
+		return
+	}
+	receiverURL := getAlertReceiverAddress(ctx) + constants.AlertReceiverApiPath + req.ClusterID
+
+	flowID, err := workflow.GetWorkFlowService().CreateWorkFlow(ctx, req.ClusterID, workflow.BizTypeCluster, applyAlertReceiverDefine.FlowName)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("create flow %s failed, clusterID = %s, error = %s", applyAlertReceiverDefine.FlowName, req.ClusterID, err.Error())
+		return
+	}
+	workflow.GetWorkFlowService().InitContext(ctx, flowID, contextClusterMeta, clusterMeta)
+	workflow.GetWorkFlowService().InitContext(ctx, flowID, contextReceiverURL, receiverURL)
+	if err = workflow.GetWorkFlowService().Start(ctx, flowID); err != nil {
+		framework.LogWithContext(ctx).Errorf("start flow %s failed, clusterID = %s, error = %s", applyAlertReceiverDefine.FlowName, req.ClusterID, err.Error())
+		return
+	}
+	framework.LogWithContext(ctx).Infof("create flow %s succeed, clusterID = %s, receiver = %s", applyAlertReceiverDefine.FlowName, req.ClusterID, receiverURL)
+	resp.WorkFlowID = flowID
+	return
+}
+
+// applyAlertRules
+// @Description: start a workflow to push enabled rules of cluster to prometheus and reload it
+// @Receiver m
+// @Parameter ctx
+// @Parameter clusterMeta
+// @return flowID
+// @return err
+func (m *Manager) applyAlertRules(ctx context.Context, clusterMeta *meta.ClusterMeta) (flowID string, err error) {
+	flowID, err = workflow.GetWorkFlowService().CreateWorkFlow(ctx, clusterMeta.Cluster.ID, workflow.BizTypeCluster, applyAlertRulesDefine.FlowName)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("create flow %s failed, clusterID = %s, error = %s", applyAlertRulesDefine.FlowName, clusterMeta.Cluster.ID, err.Error())
+		return "", err
+	}
+	workflow.GetWorkFlowService().InitContext(ctx, flowID, contextClusterMeta, clusterMeta)
+	if err = workflow.GetWorkFlowService().Start(ctx, flowID); err != nil {
+		framework.LogWithContext(ctx).Errorf("start flow %s failed, clusterID = %s, error = %s", applyAlertRulesDefine.FlowName, clusterMeta.Cluster.ID, err.Error())
+		return flowID, err
+	}
+	framework.LogWithContext(ctx).Infof("create flow %s succeed, clusterID = %s", applyAlertRulesDefine.FlowName, clusterMeta.Cluster.ID)
+	return flowID, nil
+}
+
+// getClusterMeta
+// @Description: load meta of cluster and deny the request if current user has no permission of action on it
+// @Parameter ctx
+// @Parameter clusterID
+// @Parameter action
+// @return *meta.ClusterMeta
+// @return error
+func getClusterMeta(ctx context.Context, clusterID string, action constants.RbacAction) (*meta.ClusterMeta, error) {
+	clusterMeta, err := meta.Get(ctx, clusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("load cluster %s meta from db error: %s", clusterID, err.Error())
+		return nil, err
+	}
+	cluster := clusterMeta.Cluster
+	if err = rbac.CheckClusterPermission(ctx, cluster.ID, cluster.Tags, cluster.TenantId, action); err != nil {
+		return nil, err
+	}
+	return clusterMeta, nil
+}
+
+// validateRule
+// @Description: severity is required to be one of AlertSeverityMap, duration is optional
+func validateRule(severity string, duration string) error {
+	if _, ok := constants.AlertSeverityMap[severity]; !ok {
+		return errors.NewErrorf(errors.TIUNIMANAGER_ALERT_RULE_INVALID, "severity %s is invalid", severity)
+	}
+	if len(duration) > 0 && !durationPattern.MatchString(duration) {
+		return errors.NewErrorf(errors.TIUNIMANAGER_ALERT_RULE_INVALID, "duration %s is invalid", duration)
+	}
+	return nil
+}
+
+// getAlertManagerAddress
+// @Description: get address of the running alertmanager of cluster
+func getAlertManagerAddress(clusterMeta *meta.ClusterMeta) (string, error) {
+	addresses := clusterMeta.GetAlertManagerAddresses()
+	if len(addresses) == 0 {
+		return "", errors.NewErrorf(errors.TIUNIMANAGER_ALERT_MANAGER_UNAVAILABLE, "no running alertmanager in cluster %s", clusterMeta.Cluster.ID)
+	}
+	return fmt.Sprintf("%s:%d", addresses[0].IP, addresses[0].Port), nil
+}
+
+// getPrometheusAddress
+// @Description: get address of the running prometheus of cluster
+func getPrometheusAddress(clusterMeta *meta.ClusterMeta) (string, error) {
+	addresses := clusterMeta.GetMonitorAddresses()
+	if len(addresses) == 0 {
+		return "", errors.NewErrorf(errors.TIUNIMANAGER_PROMETHEUS_UNAVAILABLE, "no running prometheus in cluster %s", clusterMeta.Cluster.ID)
+	}
+	return fmt.Sprintf("%s:%d", addresses[0].IP, addresses[0].Port), nil
+}
+
+// ensureAlertReceiverToken
+// @Description: every cluster has its own token to authenticate its alertmanager to the receiver of the platform,
+// the token is generated when the receiver is configured for the first time and kept afterwards
+// @Parameter ctx
+// @Parameter clusterMeta
+// @return error
+func ensureAlertReceiverToken(ctx context.Context, clusterMeta *meta.ClusterMeta) error {
+	rw := models.GetAlertReaderWriter()
+	if _, err := rw.GetReceiver(ctx, clusterMeta.Cluster.ID); err == nil {
+		return nil
+	}
+	b := make([]byte, receiverTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return errors.WrapError(errors.TIUNIMANAGER_UNRECOGNIZED_ERROR, "generate alert receiver token failed", err)
+	}
+	return rw.SaveReceiver(ctx, &alert.AlertReceiver{
+		ClusterID: clusterMeta.Cluster.ID,
+		TenantId:  clusterMeta.Cluster.TenantId,
+		Token:     dbCommon.Password(hex.EncodeToString(b)),
+	})
+}
+
+// getAlertReceiverToken
+// @Description: alertmanager of cluster authenticates to the receiver of the platform with the token of cluster
+func getAlertReceiverToken(ctx context.Context, clusterID string) (string, error) {
+	receiver, err := models.GetAlertReaderWriter().GetReceiver(ctx, clusterID)
+	if err != nil {
+		return "", err
+	}
+	return string(receiver.Token), nil
+}
+
+// getAlertReceiverAddress
+// @Description: address of openapi which is reachable by alertmanager, default to the address of this host
+func getAlertReceiverAddress(ctx context.Context) string {
+	config, err := models.GetConfigReaderWriter().GetConfig(ctx, constants.ConfigKeyAlertReceiverAddress)
+	if err == nil && len(config.ConfigValue) > 0 {
+		return strings.TrimSuffix(config.ConfigValue, "/")
+	}
+	scheme := "http"
+	if framework.Current.GetClientArgs().EnableHttps {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s:%d", scheme, framework.Current.GetClientArgs().Host, constants.DefaultMicroApiPort)
+}
+
+// ensureBuiltInRules
+// @Description: rules of cluster are initialized with built-in rules when they are accessed for the first time
+// @Parameter ctx
+// @Parameter clusterMeta
+// @return error
+func ensureBuiltInRules(ctx context.Context, clusterMeta *meta.ClusterMeta) error {
+	rw := models.GetAlertReaderWriter()
+	_, total, err := rw.QueryRules(ctx, clusterMeta.Cluster.ID, constants.AlertRuleBuiltIn, "", 0, 1)
+	if err != nil {
+		return err
+	}
+	if total > 0 {
+		return nil
+	}
+
+	rules := make([]*alert.AlertRule, 0, len(builtInRules))
+	for _, r := range builtInRules {
+		rule := &alert.AlertRule{
+			ClusterID: clusterMeta.Cluster.ID,
+			Name:      r.Name,
+			Type:      string(constants.AlertRuleBuiltIn),
+			Component: string(r.Component),
+			Expr:      r.Expr,
+			Duration:  r.Duration,
+			Severity:  string(r.Severity),
+			Summary:   r.Summary,
+		}
+		rule.TenantId = clusterMeta.Cluster.TenantId
+		rules = append(rules, rule)
+	}
+	return rw.CreateRules(ctx, rules)
+}
+
+func convertRuleInfo(rule *alert.AlertRule) structs.AlertRuleInfo {
+	return structs.AlertRuleInfo{
+		ID:         rule.ID,
+		ClusterID:  rule.ClusterID,
+		Name:       rule.Name,
+		Type:       rule.Type,
+		Component:  rule.Component,
+		Expr:       rule.Expr,
+		For:        rule.Duration,
+		Severity:   rule.Severity,
+		Summary:    rule.Summary,
+		Enabled:    rule.Status == string(constants.AlertRuleEnabled),
+		CreateTime: rule.CreatedAt,
+		UpdateTime: rule.UpdatedAt,
+	}
+}
+
+func convertSilenceInfo(silence *alert.AlertSilence) (structs.AlertSilenceInfo, error) {
+	matchers := make([]structs.AlertMatcher, 0)
+	if len(silence.Matchers) > 0 {
+		if err := json.Unmarshal([]byte(silence.Matchers), &matchers); err != nil {
+			return structs.AlertSilenceInfo{}, errors.WrapError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, "", err)
+		}
+	}
+	return structs.AlertSilenceInfo{
+		ID:         silence.ID,
+		ClusterID:  silence.ClusterID,
+		SilenceID:  silence.SilenceID,
+		Matchers:   matchers,
+		Comment:    silence.Comment,
+		CreatedBy:  silence.CreatedBy,
+		Status:     silence.Status,
+		StartsAt:   silence.StartsAt,
+		EndsAt:     silence.EndsAt,
+		CreateTime: silence.CreatedAt,
+	}, nil
+}
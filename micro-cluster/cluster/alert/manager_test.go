@@ -0,0 +1,423 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package alert
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-cluster/user/rbac"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/platform/config"
+	"github.com/pingcap/tiunimanager/test/mockmodels/mockclustermanagement"
+	"github.com/pingcap/tiunimanager/test/mockmodels/mockconfig"
+	"github.com/pingcap/tiunimanager/test/mockutilalertmanager"
+	mock_workflow_service "github.com/pingcap/tiunimanager/test/mockworkflow"
+	"github.com/pingcap/tiunimanager/util/api/alertmanager"
+	workflow "github.com/pingcap/tiunimanager/workflow2"
+)
+
+func mockWorkFlow(workflowService *mock_workflow_service.MockWorkFlowService) {
+	workflowService.EXPECT().CreateWorkFlow(gomock.Any(), gomock.Any(), gomock.Any(), constants.FlowApplyAlertRules).Return("flow01", nil).AnyTimes()
+	workflowService.EXPECT().InitContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	workflowService.EXPECT().Start(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+}
+
+func TestManager_QueryAlertRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+	models.SetClusterReaderWriter(clusterRW)
+	mockGetMeta(clusterRW)
+
+	resp, total, err := mockManager.QueryAlertRules(context.TODO(), cluster.QueryAlertRulesReq{
+		ClusterID:   "query-rules",
+		PageRequest: structs.PageRequest{Page: 1, PageSize: 100},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, len(builtInRules), total)
+	for _, rule := range resp.Rules {
+		assert.True(t, rule.Enabled)
+		assert.Equal(t, string(constants.AlertRuleBuiltIn), rule.Type)
+	}
+
+	// built-in rules are initialized only once
+	_, total, err = mockManager.QueryAlertRules(context.TODO(), cluster.QueryAlertRulesReq{
+		ClusterID:   "query-rules",
+		Type:        string(constants.AlertRuleBuiltIn),
+		PageRequest: structs.PageRequest{Page: 1, PageSize: 100},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, len(builtInRules), total)
+}
+
+func TestManager_CustomAlertRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+	models.SetClusterReaderWriter(clusterRW)
+	mockGetMeta(clusterRW)
+	workflowService := mock_workflow_service.NewMockWorkFlowService(ctrl)
+	workflow.MockWorkFlowService(workflowService)
+	defer workflow.MockWorkFlowService(workflow.NewWorkFlowManager())
+	mockWorkFlow(workflowService)
+
+	createReq := cluster.CreateAlertRuleReq{
+		ClusterID: "custom-rules",
+		Name:      "TiDB_connection_too_many",
+		Component: "TiDB",
+		Expr:      "sum(tidb_server_connections) by (instance) > 1000",
+		For:       "5m",
+		Severity:  string(constants.AlertSeverityWarning),
+		Summary:   "too many connections",
+	}
+
+	t.Run("invalid severity", func(t *testing.T) {
+		req := createReq
+		req.Severity = "fatal"
+		_, err := mockManager.CreateAlertRule(context.TODO(), req)
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_ALERT_RULE_INVALID, err.(errors.EMError).GetCode())
+	})
+	t.Run("invalid duration", func(t *testing.T) {
+		req := createReq
+		req.For = "5 minutes"
+		_, err := mockManager.CreateAlertRule(context.TODO(), req)
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_ALERT_RULE_INVALID, err.(errors.EMError).GetCode())
+	})
+
+	created, err := mockManager.CreateAlertRule(context.TODO(), createReq)
+	assert.NoError(t, err)
+	assert.Equal(t, "flow01", created.WorkFlowID)
+	assert.NotEmpty(t, created.Rule.ID)
+	assert.True(t, created.Rule.Enabled)
+	assert.Equal(t, string(constants.AlertRuleCustom), created.Rule.Type)
+
+	t.Run("duplicated", func(t *testing.T) {
+		_, err := mockManager.CreateAlertRule(context.TODO(), createReq)
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_ALERT_RULE_DUPLICATED, err.(errors.EMError).GetCode())
+	})
+
+	t.Run("update", func(t *testing.T) {
+		resp, err := mockManager.UpdateAlertRule(context.TODO(), cluster.UpdateAlertRuleReq{
+			ClusterID: "custom-rules",
+			RuleID:    created.Rule.ID,
+			Expr:      "sum(tidb_server_connections) by (instance) > 2000",
+			Severity:  string(constants.AlertSeverityCritical),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "flow01", resp.WorkFlowID)
+
+		rule, err := models.GetAlertReaderWriter().GetRule(context.TODO(), "custom-rules", created.Rule.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, string(constants.AlertSeverityCritical), rule.Severity)
+		assert.Equal(t, "", rule.Duration)
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		resp, err := mockManager.SetAlertRuleEnabled(context.TODO(), cluster.SetAlertRuleEnabledReq{
+			ClusterID: "custom-rules",
+			RuleID:    created.Rule.ID,
+			Enabled:   false,
+		})
+		assert.NoError(t, err)
+		assert.False(t, resp.Rule.Enabled)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		resp, err := mockManager.DeleteAlertRule(context.TODO(), cluster.DeleteAlertRuleReq{
+			ClusterID: "custom-rules",
+			RuleID:    created.Rule.ID,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, created.Rule.ID, resp.RuleID)
+
+		_, err = models.GetAlertReaderWriter().GetRule(context.TODO(), "custom-rules", created.Rule.ID)
+		assert.Error(t, err)
+	})
+}
+
+func TestManager_BuiltInAlertRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+	models.SetClusterReaderWriter(clusterRW)
+	mockGetMeta(clusterRW)
+	workflowService := mock_workflow_service.NewMockWorkFlowService(ctrl)
+	workflow.MockWorkFlowService(workflowService)
+	defer workflow.MockWorkFlowService(workflow.NewWorkFlowManager())
+	mockWorkFlow(workflowService)
+
+	rules, _, err := mockManager.QueryAlertRules(context.TODO(), cluster.QueryAlertRulesReq{
+		ClusterID:   "builtin-rules",
+		PageRequest: structs.PageRequest{Page: 1, PageSize: 1},
+	})
+	assert.NoError(t, err)
+	ruleID := rules.Rules[0].ID
+
+	t.Run("update", func(t *testing.T) {
+		_, err := mockManager.UpdateAlertRule(context.TODO(), cluster.UpdateAlertRuleReq{
+			ClusterID: "builtin-rules",
+			RuleID:    ruleID,
+			Expr:      "up == 0",
+			Severity:  string(constants.AlertSeverityWarning),
+		})
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_ALERT_RULE_BUILTIN_READONLY, err.(errors.EMError).GetCode())
+	})
+	t.Run("delete", func(t *testing.T) {
+		_, err := mockManager.DeleteAlertRule(context.TODO(), cluster.DeleteAlertRuleReq{
+			ClusterID: "builtin-rules",
+			RuleID:    ruleID,
+		})
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_ALERT_RULE_BUILTIN_READONLY, err.(errors.EMError).GetCode())
+	})
+	t.Run("disable and enable", func(t *testing.T) {
+		resp, err := mockManager.SetAlertRuleEnabled(context.TODO(), cluster.SetAlertRuleEnabledReq{
+			ClusterID: "builtin-rules",
+			RuleID:    ruleID,
+			Enabled:   false,
+		})
+		assert.NoError(t, err)
+		assert.False(t, resp.Rule.Enabled)
+
+		resp, err = mockManager.SetAlertRuleEnabled(context.TODO(), cluster.SetAlertRuleEnabledReq{
+			ClusterID: "builtin-rules",
+			RuleID:    ruleID,
+			Enabled:   true,
+		})
+		assert.NoError(t, err)
+		assert.True(t, resp.Rule.Enabled)
+	})
+	t.Run("not found", func(t *testing.T) {
+		_, err := mockManager.SetAlertRuleEnabled(context.TODO(), cluster.SetAlertRuleEnabledReq{
+			ClusterID: "builtin-rules",
+			RuleID:    "unknown",
+			Enabled:   true,
+		})
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_ALERT_RULE_NOT_FOUND, err.(errors.EMError).GetCode())
+	})
+}
+
+func TestManager_QueryFiringAlerts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+	models.SetClusterReaderWriter(clusterRW)
+	mockGetMeta(clusterRW)
+	alertService := mockutilalertmanager.NewMockAlertService(ctrl)
+	alertmanager.AlertManagerService = alertService
+
+	alertService.EXPECT().QueryAlerts(gomock.Any(), "127.0.0.1:9093").Return([]alertmanager.Alert{
+		{
+			Fingerprint: "f1",
+			Labels:      map[string]string{constants.AlertLabelName: "TiDB_server_is_down", constants.AlertLabelSeverity: "emergency"},
+			Annotations: map[string]string{annotationSummary: "TiDB server is down"},
+			Status:      alertmanager.AlertStatus{State: "active"},
+		},
+		{
+			Fingerprint: "f2",
+			Labels:      map[string]string{constants.AlertLabelName: "TiDB_query_duration", constants.AlertLabelSeverity: "warning"},
+			Status:      alertmanager.AlertStatus{State: "suppressed", SilencedBy: []string{"s1"}},
+		},
+	}, nil).Times(2)
+
+	resp, err := mockManager.QueryFiringAlerts(context.TODO(), cluster.QueryFiringAlertsReq{ClusterID: "firing-alerts"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(resp.Alerts))
+	assert.Equal(t, "TiDB server is down", resp.Alerts[0].Summary)
+
+	resp, err = mockManager.QueryFiringAlerts(context.TODO(), cluster.QueryFiringAlertsReq{ClusterID: "firing-alerts", Severity: "warning"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(resp.Alerts))
+	assert.Equal(t, "TiDB_query_duration", resp.Alerts[0].Name)
+	assert.Equal(t, []string{"s1"}, resp.Alerts[0].SilencedBy)
+}
+
+func TestManager_AlertSilence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+	models.SetClusterReaderWriter(clusterRW)
+	mockGetMeta(clusterRW)
+	alertService := mockutilalertmanager.NewMockAlertService(ctrl)
+	alertmanager.AlertManagerService = alertService
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := mockManager.CreateAlertSilence(context.TODO(), cluster.CreateAlertSilenceReq{
+			ClusterID: "silence-cluster",
+			Matchers:  []structs.AlertMatcher{{Name: constants.AlertLabelName, Value: "TiDB_server_is_down"}},
+			StartTime: time.Now().Add(time.Hour).Unix(),
+			EndTime:   time.Now().Unix(),
+		})
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_ALERT_SILENCE_INVALID, err.(errors.EMError).GetCode())
+	})
+
+	alertService.EXPECT().CreateSilence(gomock.Any(), "127.0.0.1:9093", gomock.Any()).Return("am-silence-01", nil)
+	created, err := mockManager.CreateAlertSilence(context.TODO(), cluster.CreateAlertSilenceReq{
+		ClusterID: "silence-cluster",
+		Matchers:  []structs.AlertMatcher{{Name: constants.AlertLabelName, Value: "TiDB_server_is_down"}},
+		EndTime:   time.Now().Add(time.Hour).Unix(),
+		Comment:   "upgrade",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "am-silence-01", created.Silence.SilenceID)
+	assert.Equal(t, string(constants.AlertSilenceActive), created.Silence.Status)
+
+	resp, total, err := mockManager.QueryAlertSilences(context.TODO(), cluster.QueryAlertSilencesReq{
+		ClusterID:   "silence-cluster",
+		Status:      string(constants.AlertSilenceActive),
+		PageRequest: structs.PageRequest{Page: 1, PageSize: 10},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "TiDB_server_is_down", resp.Silences[0].Matchers[0].Value)
+
+	alertService.EXPECT().DeleteSilence(gomock.Any(), "127.0.0.1:9093", "am-silence-01").Return(nil)
+	_, err = mockManager.DeleteAlertSilence(context.TODO(), cluster.DeleteAlertSilenceReq{
+		ClusterID: "silence-cluster",
+		SilenceID: created.Silence.ID,
+	})
+	assert.NoError(t, err)
+
+	_, total, err = mockManager.QueryAlertSilences(context.TODO(), cluster.QueryAlertSilencesReq{
+		ClusterID:   "silence-cluster",
+		Status:      string(constants.AlertSilenceExpired),
+		PageRequest: structs.PageRequest{Page: 1, PageSize: 10},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+}
+
+func TestManager_QueryAlertHistory(t *testing.T) {
+	t.Run("invalid time", func(t *testing.T) {
+		_, _, err := mockManager.QueryAlertHistory(context.TODO(), cluster.QueryAlertHistoryReq{
+			ClusterID: "history-cluster",
+			StartTime: 1638331200,
+			EndTime:   1630468800,
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestManager_ConfigureAlertReceiver(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+	models.SetClusterReaderWriter(clusterRW)
+	mockGetMeta(clusterRW)
+	workflowService := mock_workflow_service.NewMockWorkFlowService(ctrl)
+	workflow.MockWorkFlowService(workflowService)
+	defer workflow.MockWorkFlowService(workflow.NewWorkFlowManager())
+
+	configRW := mockconfig.NewMockReaderWriter(ctrl)
+	configRW.EXPECT().GetConfig(gomock.Any(), constants.ConfigKeyAlertReceiverAddress).Return(&config.SystemConfig{ConfigValue: "https://em.example.com/"}, nil).AnyTimes()
+	models.SetConfigReaderWriter(configRW)
+
+	workflowService.EXPECT().CreateWorkFlow(gomock.Any(), "configure-receiver", gomock.Any(), constants.FlowApplyAlertReceiver).Return("flow01", nil)
+	workflowService.EXPECT().InitContext(gomock.Any(), "flow01", contextClusterMeta, gomock.Any()).Return(nil)
+	workflowService.EXPECT().InitContext(gomock.Any(), "flow01", contextReceiverURL, "https://em.example.com/api/v1/alerts/receiver/configure-receiver").Return(nil)
+	workflowService.EXPECT().Start(gomock.Any(), "flow01").Return(nil)
+
+	resp, err := mockManager.ConfigureAlertReceiver(context.TODO(), cluster.ConfigureAlertReceiverReq{ClusterID: "configure-receiver"})
+	assert.NoError(t, err)
+	assert.Equal(t, "flow01", resp.WorkFlowID)
+
+	// every cluster has its own token, which is kept when the receiver is configured again
+	token, err := getAlertReceiverToken(context.TODO(), "configure-receiver")
+	assert.NoError(t, err)
+	assert.Equal(t, receiverTokenBytes*2, len(token))
+	assert.NoError(t, ensureAlertReceiverToken(context.TODO(), mockClusterMeta("configure-receiver")))
+	kept, err := getAlertReceiverToken(context.TODO(), "configure-receiver")
+	assert.NoError(t, err)
+	assert.Equal(t, token, kept)
+
+	_, err = getAlertReceiverToken(context.TODO(), "another-cluster")
+	assert.Error(t, err)
+	assert.Equal(t, errors.TIUNIMANAGER_ALERT_RECEIVER_NOT_CONFIGURED, err.(errors.EMError).GetCode())
+}
+
+type fakeRBACService struct {
+	rbac.RBACService
+	allowed map[string]bool
+}
+
+func (f *fakeRBACService) CheckPermissionForInstances(ctx context.Context, request message.CheckPermissionForInstancesReq) (message.CheckPermissionForInstancesResp, error) {
+	for _, instance := range request.Instances {
+		if f.allowed[instance] {
+			return message.CheckPermissionForInstancesResp{Result: true}, nil
+		}
+	}
+	return message.CheckPermissionForInstancesResp{Result: false}, nil
+}
+
+func TestManager_ClusterPermission(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+	models.SetClusterReaderWriter(clusterRW)
+	mockGetMeta(clusterRW)
+	rbac.MockRBACService(&fakeRBACService{allowed: map[string]bool{"id/allowed-cluster": true}})
+	defer rbac.MockRBACService(nil)
+
+	ginCtx := &gin.Context{}
+	ginCtx.Set(framework.TiUniManager_X_USER_ID_KEY, "dba")
+	ctx := framework.NewMicroCtxFromGinCtx(ginCtx)
+
+	t.Run("allowed", func(t *testing.T) {
+		_, _, err := mockManager.QueryAlertRules(ctx, cluster.QueryAlertRulesReq{
+			ClusterID:   "allowed-cluster",
+			PageRequest: structs.PageRequest{Page: 1, PageSize: 10},
+		})
+		assert.NoError(t, err)
+	})
+	t.Run("denied", func(t *testing.T) {
+		_, _, err := mockManager.QueryAlertRules(ctx, cluster.QueryAlertRulesReq{ClusterID: "denied-cluster"})
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_RBAC_PERMISSION_DENIED, err.(errors.EMError).GetCode())
+
+		_, _, err = mockManager.QueryAlertHistory(ctx, cluster.QueryAlertHistoryReq{ClusterID: "denied-cluster"})
+		assert.Error(t, err)
+		_, _, err = mockManager.QueryAlertSilences(ctx, cluster.QueryAlertSilencesReq{ClusterID: "denied-cluster"})
+		assert.Error(t, err)
+		_, err = mockManager.DeleteAlertRule(ctx, cluster.DeleteAlertRuleReq{ClusterID: "denied-cluster", RuleID: "rule01"})
+		assert.Error(t, err)
+		_, err = mockManager.DeleteAlertSilence(ctx, cluster.DeleteAlertSilenceReq{ClusterID: "denied-cluster", SilenceID: "silence01"})
+		assert.Error(t, err)
+	})
+}
@@ -19,8 +19,10 @@ package backuprestore
 import (
 	"context"
 	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/structs"
 	"github.com/pingcap/tiunimanager/library/framework"
 	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/maintainwindow"
 	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
 	"github.com/pingcap/tiunimanager/models"
 	"github.com/pingcap/tiunimanager/models/cluster/backuprestore"
@@ -28,6 +30,9 @@ import (
 	"time"
 )
 
+const autoPruneJobSpec = "0 30 * * * *"  // every half past hour, apart from auto backup
+const autoVerifyJobSpec = "0 45 * * * *" // after auto backup and prune
+
 type autoBackupManager struct {
 	JobCron *cron.Cron
 	JobSpec string
@@ -36,6 +41,12 @@ type autoBackupManager struct {
 type autoBackupHandler struct {
 }
 
+type autoPruneHandler struct {
+}
+
+type autoVerifyHandler struct {
+}
+
 func NewAutoBackupManager() *autoBackupManager {
 	mgr := &autoBackupManager{
 		JobCron: cron.New(),
@@ -46,6 +57,16 @@ func NewAutoBackupManager() *autoBackupManager {
 		framework.Log().Fatalf("add auto backup cron job failed, %s", err.Error())
 		return nil
 	}
+	err = mgr.JobCron.AddJob(autoPruneJobSpec, &autoPruneHandler{})
+	if err != nil {
+		framework.Log().Fatalf("add auto prune backup cron job failed, %s", err.Error())
+		return nil
+	}
+	err = mgr.JobCron.AddJob(autoVerifyJobSpec, &autoVerifyHandler{})
+	if err != nil {
+		framework.Log().Fatalf("add auto verify backup cron job failed, %s", err.Error())
+		return nil
+	}
 	go mgr.start()
 
 	return mgr
@@ -90,12 +111,123 @@ func (auto *autoBackupHandler) doBackup(strategy *backuprestore.BackupStrategy)
 	}
 
 	ctx := framework.NewMicroContextWithKeyValuePairs(context.Background(), map[string]string{framework.TiUniManager_X_TENANT_ID_KEY: meta.Cluster.TenantId})
-	_, err = GetBRService().BackupCluster(ctx, cluster.BackupClusterDataReq{
+	request := cluster.BackupClusterDataReq{
 		ClusterID:  strategy.ClusterID,
 		BackupMode: string(constants.BackupModeAuto),
-	}, true)
+	}
+	// auto backup out of the maintain window waits for the window
+	pendingOperationID, err := maintainwindow.Admit(ctx, meta, constants.PendingOperationBackupCluster, structs.MaintainWindowOption{WaitMaintainWindow: true}, request)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("check maintain window of cluster %s failed, %s", strategy.ClusterID, err.Error())
+		return
+	}
+	if pendingOperationID != "" {
+		framework.LogWithContext(ctx).Infof("auto backup of cluster %s is queued as %s", strategy.ClusterID, pendingOperationID)
+		return
+	}
+	_, err = GetBRService().BackupCluster(ctx, request, true)
 	if err != nil {
 		framework.LogWithContext(context.Background()).Errorf("do backup for cluster %s failed, %s", strategy.ClusterID, err.Error())
 		return
 	}
 }
+
+func (auto *autoPruneHandler) Run() {
+	framework.Log().Infof("begin AutoPruneHandler Run")
+	defer framework.Log().Infof("end AutoPruneHandler Run")
+
+	// every replica runs the job, the same scheduled time makes each cluster pruned by only one of them
+	runTime := time.Now().Truncate(time.Hour)
+	strategies, err := models.GetBRReaderWriter().QueryBackupStrategy(context.TODO(), "", 0)
+	if err != nil {
+		framework.Log().Errorf("query backup strategies failed, %s", err.Error())
+		return
+	}
+
+	for _, strategy := range strategies {
+		if strategy.RetentionEnabled() {
+			auto.doPrune(strategy, runTime)
+		}
+	}
+}
+
+func (auto *autoPruneHandler) doPrune(strategy *backuprestore.BackupStrategy, runTime time.Time) {
+	ctx := framework.NewMicroContextWithKeyValuePairs(context.Background(), map[string]string{framework.TiUniManager_X_TENANT_ID_KEY: strategy.TenantId})
+	claimed, err := models.GetBRReaderWriter().ClaimAutoPrune(ctx, strategy.ClusterID, runTime)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("claim auto prune of cluster %s failed, %s", strategy.ClusterID, err.Error())
+		return
+	}
+	if !claimed {
+		framework.LogWithContext(ctx).Infof("auto prune of cluster %s at %s is claimed by another replica", strategy.ClusterID, runTime.Format(time.RFC3339))
+		return
+	}
+	resp, err := GetBRService().PruneBackupRecords(ctx, cluster.PruneBackupRecordsReq{
+		ClusterID: strategy.ClusterID,
+	})
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("prune backups of cluster %s failed, %s", strategy.ClusterID, err.Error())
+		return
+	}
+	for _, record := range resp.PrunedRecords {
+		framework.LogWithContext(ctx).Infof("pruned expired backup %s of cluster %s, created at %s, file path %s",
+			record.ID, record.ClusterID, record.StartTime.Format(time.RFC3339), record.FilePath)
+	}
+	for _, record := range resp.FailedRecords {
+		framework.LogWithContext(ctx).Warnf("prune expired backup %s of cluster %s failed, file path %s",
+			record.ID, record.ClusterID, record.FilePath)
+	}
+}
+
+func (auto *autoVerifyHandler) Run() {
+	framework.Log().Infof("begin AutoVerifyHandler Run")
+	defer framework.Log().Infof("end AutoVerifyHandler Run")
+
+	// every replica runs the job, the same scheduled time makes each cluster verified by only one of them
+	runTime := time.Now().Truncate(time.Hour)
+	strategies, err := models.GetBRReaderWriter().QueryBackupStrategy(context.TODO(), "", 0)
+	if err != nil {
+		framework.Log().Errorf("query backup strategies failed, %s", err.Error())
+		return
+	}
+
+	for _, strategy := range strategies {
+		if strategy.AutoVerify {
+			auto.doVerify(strategy, runTime)
+		}
+	}
+}
+
+// doVerify verify the latest finished auto backup of the cluster, if it has not been verified yet
+func (auto *autoVerifyHandler) doVerify(strategy *backuprestore.BackupStrategy, runTime time.Time) {
+	ctx := framework.NewMicroContextWithKeyValuePairs(context.Background(), map[string]string{framework.TiUniManager_X_TENANT_ID_KEY: strategy.TenantId})
+	claimed, err := models.GetBRReaderWriter().ClaimAutoVerify(ctx, strategy.ClusterID, runTime)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("claim auto verify of cluster %s failed, %s", strategy.ClusterID, err.Error())
+		return
+	}
+	if !claimed {
+		framework.LogWithContext(ctx).Infof("auto verify of cluster %s at %s is claimed by another replica", strategy.ClusterID, runTime.Format(time.RFC3339))
+		return
+	}
+	records, _, err := models.GetBRReaderWriter().QueryBackupRecords(ctx, strategy.ClusterID, "", string(constants.BackupModeAuto), 0, 0, 1, defaultPageSize)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query auto backup records of cluster %s failed, %s", strategy.ClusterID, err.Error())
+		return
+	}
+	for _, record := range records {
+		if string(constants.ClusterBackupFinished) != record.Status {
+			continue
+		}
+		if record.VerifyStatus != "" {
+			return
+		}
+		resp, err := GetBRService().VerifyBackup(ctx, cluster.VerifyBackupReq{BackupID: record.ID})
+		if err != nil {
+			framework.LogWithContext(ctx).Errorf("verify backup %s of cluster %s failed, %s", record.ID, strategy.ClusterID, err.Error())
+			return
+		}
+		framework.LogWithContext(ctx).Infof("start verify backup %s of cluster %s, workflow %s", record.ID, strategy.ClusterID, resp.WorkFlowID)
+		return
+	}
+}
@@ -17,6 +17,7 @@ package backuprestore
 
 import (
 	"github.com/golang/mock/gomock"
+	"github.com/pingcap/tiunimanager/common/constants"
 	"github.com/pingcap/tiunimanager/message/cluster"
 	"github.com/pingcap/tiunimanager/models"
 	"github.com/pingcap/tiunimanager/models/cluster/backuprestore"
@@ -27,6 +28,7 @@ import (
 	"github.com/pingcap/tiunimanager/test/mockmodels/mockclustermanagement"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestNewAutoBackupManager(t *testing.T) {
@@ -76,3 +78,68 @@ func Test_AutoBackup_doBackup(t *testing.T) {
 	handler := &autoBackupHandler{}
 	handler.doBackup(strategy)
 }
+
+func Test_AutoPrune_doPrune(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	runTime := time.Now().Truncate(time.Hour)
+	strategy := &backuprestore.BackupStrategy{
+		ClusterID: "cls-xxxx",
+		KeepLast:  1,
+	}
+	brRW := mockbr.NewMockReaderWriter(ctrl)
+	models.SetBRReaderWriter(brRW)
+	mockBRService := mock_br_service.NewMockBRService(ctrl)
+	MockBRService(mockBRService)
+	defer MockBRService(NewBRManager())
+
+	t.Run("claimed", func(t *testing.T) {
+		brRW.EXPECT().ClaimAutoPrune(gomock.Any(), "cls-xxxx", runTime).Return(true, nil).Times(1)
+		mockBRService.EXPECT().PruneBackupRecords(gomock.Any(), cluster.PruneBackupRecordsReq{ClusterID: "cls-xxxx"}).
+			Return(cluster.PruneBackupRecordsResp{}, nil).Times(1)
+		handler := &autoPruneHandler{}
+		handler.doPrune(strategy, runTime)
+	})
+	t.Run("claimed by another replica", func(t *testing.T) {
+		brRW.EXPECT().ClaimAutoPrune(gomock.Any(), "cls-xxxx", runTime).Return(false, nil).Times(1)
+		mockBRService.EXPECT().PruneBackupRecords(gomock.Any(), gomock.Any()).Times(0)
+		handler := &autoPruneHandler{}
+		handler.doPrune(strategy, runTime)
+	})
+}
+
+func Test_AutoVerify_doVerify(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	runTime := time.Now().Truncate(time.Hour)
+	strategy := &backuprestore.BackupStrategy{
+		ClusterID:  "cls-xxxx",
+		AutoVerify: true,
+	}
+	brRW := mockbr.NewMockReaderWriter(ctrl)
+	models.SetBRReaderWriter(brRW)
+	mockBRService := mock_br_service.NewMockBRService(ctrl)
+	MockBRService(mockBRService)
+	defer MockBRService(NewBRManager())
+
+	t.Run("claimed", func(t *testing.T) {
+		brRW.EXPECT().ClaimAutoVerify(gomock.Any(), "cls-xxxx", runTime).Return(true, nil).Times(1)
+		brRW.EXPECT().QueryBackupRecords(gomock.Any(), "cls-xxxx", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), 1, gomock.Any()).
+			Return([]*backuprestore.BackupRecord{
+				{Entity: common.Entity{ID: "backup-0", Status: string(constants.ClusterBackupFinished)}},
+			}, int64(1), nil).Times(1)
+		mockBRService.EXPECT().VerifyBackup(gomock.Any(), cluster.VerifyBackupReq{BackupID: "backup-0"}).
+			Return(cluster.VerifyBackupResp{}, nil).Times(1)
+		handler := &autoVerifyHandler{}
+		handler.doVerify(strategy, runTime)
+	})
+	t.Run("claimed by another replica", func(t *testing.T) {
+		brRW.EXPECT().ClaimAutoVerify(gomock.Any(), "cls-xxxx", runTime).Return(false, nil).Times(1)
+		brRW.EXPECT().QueryBackupRecords(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockBRService.EXPECT().VerifyBackup(gomock.Any(), gomock.Any()).Times(0)
+		handler := &autoVerifyHandler{}
+		handler.doVerify(strategy, runTime)
+	})
+}
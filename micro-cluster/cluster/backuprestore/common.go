@@ -20,6 +20,12 @@ const (
 	contextBackupRecordKey            string = "backupRecord"
 	contextMaintenanceStatusChangeKey string = "maintenanceStatusChange"
 	contextBRInfoKey                  string = "brInfo"
+	contextLogBackupTaskKey           string = "logBackupTask"
+	contextRestoreTSKey               string = "restoreTS"
+	contextTestRestoreParameterKey    string = "testRestoreParameter"
+	contextTestRestoreClusterKey      string = "testRestoreCluster"
+	contextTestRestoreFlowKey         string = "testRestoreFlow"
+	contextVerifyFailureKey           string = "verifyFailure"
 )
 
 const (
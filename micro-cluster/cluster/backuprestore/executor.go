@@ -19,6 +19,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/deployment"
 	"github.com/pingcap/tiunimanager/library/framework"
 	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
 	"github.com/pingcap/tiunimanager/models"
@@ -38,6 +39,9 @@ const (
 	StorageTypeS3    StorageType = "s3"
 )
 
+// restorePointTimeout restore to a point in time may last for hours, the br operation is not limited
+const restorePointTimeout = 0
+
 func backupCluster(node *wfModel.WorkFlowNode, ctx *workflow.FlowContext) error {
 	framework.LogWithContext(ctx).Info("begin backupCluster")
 	defer framework.LogWithContext(ctx).Info("end backupCluster")
@@ -164,6 +168,8 @@ func restoreFromSrcCluster(node *wfModel.WorkFlowNode, ctx *workflow.FlowContext
 
 	var record backuprestore.BackupRecord
 	var meta meta.ClusterMeta
+	var logBackupTask backuprestore.LogBackupTask
+	var restoreTS uint64
 	err := ctx.GetData(contextBackupRecordKey, &record)
 	if err != nil {
 		return err
@@ -172,6 +178,14 @@ func restoreFromSrcCluster(node *wfModel.WorkFlowNode, ctx *workflow.FlowContext
 	if err != nil {
 		return err
 	}
+	err = ctx.GetData(contextLogBackupTaskKey, &logBackupTask)
+	if err != nil {
+		return err
+	}
+	err = ctx.GetData(contextRestoreTSKey, &restoreTS)
+	if err != nil {
+		return err
+	}
 
 	tidbServers := meta.GetClusterConnectAddresses()
 	if len(tidbServers) == 0 {
@@ -226,6 +240,11 @@ func restoreFromSrcCluster(node *wfModel.WorkFlowNode, ctx *workflow.FlowContext
 	if concurrencyConfig != nil && concurrencyConfig.ConfigValue != "" {
 		restoreSQLReq.Concurrency = concurrencyConfig.ConfigValue
 	}
+
+	if restoreTS > 0 {
+		return restorePoint(node, ctx, &meta, &record, &logBackupTask, restoreTS, restoreSQLReq)
+	}
+
 	framework.LogWithContext(ctx).Infof("begin do backup sql, request[%+v]", restoreSQLReq)
 	_, err = sql.ExecRestoreSQL(ctx, restoreSQLReq, node.ID)
 	if err != nil {
@@ -234,6 +253,56 @@ func restoreFromSrcCluster(node *wfModel.WorkFlowNode, ctx *workflow.FlowContext
 	}
 
 	node.Record(fmt.Sprintf("update backup record %s of cluster %s ", record.ID, meta.Cluster.ID))
+	// restore by sql has been done, the polling node does not wait for any operation
+	node.Success()
+	return nil
+}
+
+// restorePoint
+// @Description: restore the full backup and then replay logs up to restoreTS by `br restore point`,
+// it may last for hours, so it is run asynchronously and the node waits for the operation
+// @Parameter node
+// @Parameter ctx
+// @Parameter clusterMeta restored cluster
+// @Parameter record full backup
+// @Parameter logBackupTask
+// @Parameter restoreTS
+// @Parameter restoreSQLReq rate limit and concurrency of restore
+// @return error
+func restorePoint(node *wfModel.WorkFlowNode, ctx *workflow.FlowContext, clusterMeta *meta.ClusterMeta, record *backuprestore.BackupRecord,
+	logBackupTask *backuprestore.LogBackupTask, restoreTS uint64, restoreSQLReq sql.RestoreSQLReq) error {
+	target, err := getBRCluster(clusterMeta)
+	if err != nil {
+		framework.LogWithContext(ctx).Error(err.Error())
+		return err
+	}
+	logStorageAddress, err := getLogBackupStorageAddress(ctx, logBackupTask)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("get log backup storage address failed, %s", err.Error())
+		return err
+	}
+	fullStorageAddress, err := getBRStorageAddress(ctx, record.StorageType, record.FilePath)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("get full backup storage address failed, %s", err.Error())
+		return err
+	}
+
+	args := []string{"restore", "point", "--pd=" + target.pdAddress, "--storage=" + logStorageAddress,
+		"--full-backup-storage=" + fullStorageAddress, "--restored-ts=" + strconv.FormatUint(restoreTS, 10)}
+	if restoreSQLReq.RateLimitM != "" {
+		args = append(args, "--ratelimit="+restoreSQLReq.RateLimitM)
+	}
+	if restoreSQLReq.Concurrency != "" {
+		args = append(args, "--concurrency="+restoreSQLReq.Concurrency)
+	}
+	framework.LogWithContext(ctx).Infof("begin restore point, restored ts %d", restoreTS)
+	operationID, err := deployment.M.BR(ctx, target.version, framework.GetTiupHomePathForTidb(), node.ParentID, args, restorePointTimeout)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("call br restore point failed, %s", err.Error())
+		return err
+	}
+	node.OperationID = operationID
+	node.Record(fmt.Sprintf("restore cluster %s to %d by backup record %s and log backup task %s ", clusterMeta.Cluster.ID, restoreTS, record.ID, logBackupTask.ID))
 	return nil
 }
 
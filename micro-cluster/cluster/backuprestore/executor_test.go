@@ -19,6 +19,7 @@ import (
 	"context"
 	"github.com/golang/mock/gomock"
 	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/deployment"
 	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
 	"github.com/pingcap/tiunimanager/models"
 	"github.com/pingcap/tiunimanager/models/cluster/backuprestore"
@@ -26,6 +27,7 @@ import (
 	"github.com/pingcap/tiunimanager/models/common"
 	"github.com/pingcap/tiunimanager/models/platform/config"
 	workflowModel "github.com/pingcap/tiunimanager/models/workflow"
+	mock_deployment "github.com/pingcap/tiunimanager/test/mockdeployment"
 	"github.com/pingcap/tiunimanager/test/mockmodels/mockbr"
 	"github.com/pingcap/tiunimanager/test/mockmodels/mockconfig"
 	"github.com/pingcap/tiunimanager/test/mockmodels/mockmanagement"
@@ -170,6 +172,62 @@ func TestExecutor_restoreFromSrcCluster(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestExecutor_restoreFromSrcCluster_point(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	confingRW := mockconfig.NewMockReaderWriter(ctrl)
+	confingRW.EXPECT().GetConfig(gomock.Any(), gomock.Any()).Return(&config.SystemConfig{ConfigValue: ""}, nil).AnyTimes()
+	models.SetConfigReaderWriter(confingRW)
+
+	mockDeployment := mock_deployment.NewMockInterface(ctrl)
+	deployment.M = mockDeployment
+	mockDeployment.EXPECT().BR(gomock.Any(), "v6.2.0", gomock.Any(), "flow-test", []string{"restore", "point",
+		"--pd=127.0.0.2:2379", "--storage=local:///nfs/log", "--full-backup-storage=local:///nfs/full",
+		"--restored-ts=432072460356829185"}, restorePointTimeout).Return("operation-test", nil).Times(1)
+
+	flowContext := workflow.NewFlowContext(context.TODO(), make(map[string]string))
+	flowContext.SetData(contextBackupRecordKey, &backuprestore.BackupRecord{
+		Entity:      common.Entity{ID: "record-test"},
+		StorageType: string(constants.StorageTypeNFS),
+		FilePath:    "/nfs/full",
+	})
+	flowContext.SetData(contextLogBackupTaskKey, &backuprestore.LogBackupTask{
+		Entity:      common.Entity{ID: "task-test"},
+		StorageType: string(constants.StorageTypeNFS),
+		FilePath:    "/nfs/log",
+	})
+	flowContext.SetData(contextRestoreTSKey, uint64(432072460356829185))
+	flowContext.SetData(contextClusterMetaKey, &meta.ClusterMeta{
+		Cluster: &management.Cluster{
+			Entity:  common.Entity{ID: "cls-test"},
+			Version: "v6.2.0",
+		},
+		Instances: map[string][]*management.ClusterInstance{
+			"TiDB": {
+				{Entity: common.Entity{Status: string(constants.ClusterInstanceRunning)}, Type: "TiDB", HostIP: []string{"127.0.0.1"}, Ports: []int32{4000}},
+			},
+			"PD": {
+				{Entity: common.Entity{Status: string(constants.ClusterInstanceRunning)}, Type: "PD", HostIP: []string{"127.0.0.2"}, Ports: []int32{2379}},
+			},
+		},
+		DBUsers: map[string]*management.DBUser{
+			string(constants.DBUserBackupRestore): {
+				ClusterID: "cls-test",
+				Name:      constants.DBUserName[constants.DBUserBackupRestore],
+				Password:  common.PasswordInExpired{Val: "12345678", UpdateTime: time.Now()},
+				RoleType:  string(constants.DBUserBackupRestore),
+			},
+		},
+	})
+	node := &workflowModel.WorkFlowNode{ParentID: "flow-test"}
+	err := restoreFromSrcCluster(node, flowContext)
+	assert.NoError(t, err)
+	// the polling node waits for the br operation
+	assert.Equal(t, "operation-test", node.OperationID)
+	assert.NotEqual(t, constants.WorkFlowStatusFinished, node.Status)
+}
+
 func TestExecutor_backupFail(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
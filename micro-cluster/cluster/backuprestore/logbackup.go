@@ -0,0 +1,549 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package backuprestore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/deployment"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/backuprestore"
+	dbModel "github.com/pingcap/tiunimanager/models/common"
+)
+
+// physicalShiftBits bits of logical part in tso
+const physicalShiftBits = 18
+
+// timeToTSO convert a point in time to tso with zero logical part
+func timeToTSO(t time.Time) uint64 {
+	return uint64(t.UnixNano()/int64(time.Millisecond)) << physicalShiftBits
+}
+
+// tsoToTime physical time of tso
+func tsoToTime(tso uint64) time.Time {
+	return time.Unix(0, int64(tso>>physicalShiftBits)*int64(time.Millisecond))
+}
+
+// parseRestoreTSO
+// @Description: get tso from tso string or unix timestamp, tso string takes precedence
+// @Parameter tso
+// @Parameter unixTime
+// @return uint64
+// @return error
+func parseRestoreTSO(tso string, unixTime int64) (uint64, error) {
+	if tso != "" {
+		ts, err := strconv.ParseUint(tso, 10, 64)
+		if err != nil || ts == 0 {
+			return 0, fmt.Errorf("invalid tso %s", tso)
+		}
+		return ts, nil
+	}
+	if unixTime <= 0 {
+		return 0, fmt.Errorf("invalid unix time %d", unixTime)
+	}
+	return timeToTSO(time.Unix(unixTime, 0)), nil
+}
+
+const (
+	// logBackupTaskName name of the log backup task in the cluster, a cluster has one log backup task at most
+	logBackupTaskName = "tiunimanager"
+	// logBackupTimeout timeout in seconds of br log commands
+	logBackupTimeout = 600
+)
+
+// brCluster the cluster operated by br, br of the same version as the cluster is used
+type brCluster struct {
+	version   string
+	pdAddress string
+}
+
+// getBRCluster version and pd address of the cluster for br commands
+func getBRCluster(clusterMeta *meta.ClusterMeta) (brCluster, error) {
+	pdServers := clusterMeta.GetPDClientAddresses()
+	if len(pdServers) == 0 {
+		return brCluster{}, fmt.Errorf("get pd servers address from cluster %s meta result empty", clusterMeta.Cluster.ID)
+	}
+	return brCluster{
+		version:   clusterMeta.Cluster.Version,
+		pdAddress: net.JoinHostPort(pdServers[0].IP, strconv.Itoa(pdServers[0].Port)),
+	}, nil
+}
+
+// getBRStorageAddress
+// @Description: storage address used by br command, it is built as an escaped url, so it is passed to br as a single argument
+// @Parameter ctx
+// @Parameter storageType
+// @Parameter filePath
+// @return string
+// @return error
+func getBRStorageAddress(ctx context.Context, storageType string, filePath string) (string, error) {
+	brStorageType, err := convertBrStorageType(storageType)
+	if err != nil {
+		return "", err
+	}
+	if brStorageType != StorageTypeS3 {
+		return (&url.URL{Scheme: string(brStorageType), Path: filePath}).String(), nil
+	}
+
+	configRW := models.GetConfigReaderWriter()
+	query := url.Values{}
+	for param, key := range map[string]string{
+		"endpoint":          constants.ConfigKeyBackupS3Endpoint,
+		"access-key":        constants.ConfigKeyBackupS3AccessKey,
+		"secret-access-key": constants.ConfigKeyBackupS3SecretAccessKey,
+	} {
+		config, err := configRW.GetConfig(ctx, key)
+		if err != nil {
+			framework.LogWithContext(ctx).Errorf("get conifg %s failed: %s", key, err.Error())
+			return "", err
+		}
+		query.Set(param, config.ConfigValue)
+	}
+	query.Set("force-path-style", "true")
+	s3Addr := strings.SplitN(filePath, "/", 2)
+	if len(s3Addr) != 2 {
+		return "", fmt.Errorf("invalid s3 backup path %s", filePath)
+	}
+	return (&url.URL{Scheme: string(brStorageType), Host: s3Addr[0], Path: "/" + s3Addr[1], RawQuery: query.Encode()}).String(), nil
+}
+
+// getLogBackupStorageAddress storage address of log backup task used by br
+func getLogBackupStorageAddress(ctx context.Context, task *backuprestore.LogBackupTask) (string, error) {
+	return getBRStorageAddress(ctx, task.StorageType, task.FilePath)
+}
+
+// logBackupStatus status of a log backup task printed by `br log status --json`
+type logBackupStatus struct {
+	Name         string `json:"name"`
+	StartTS      uint64 `json:"start_ts"`
+	CheckpointTS uint64 `json:"checkpoint"`
+}
+
+// parseLogBackupStatus
+// @Description: find status of the task from output of `br log status --json`, which is a json array of tasks
+// @Parameter output
+// @Parameter taskName
+// @return logBackupStatus
+// @return error
+func parseLogBackupStatus(output string, taskName string) (logBackupStatus, error) {
+	begin := strings.Index(output, "[")
+	end := strings.LastIndex(output, "]")
+	if begin < 0 || end < begin {
+		return logBackupStatus{}, fmt.Errorf("no log backup task in output of br, %s", output)
+	}
+	tasks := make([]logBackupStatus, 0)
+	if err := json.Unmarshal([]byte(output[begin:end+1]), &tasks); err != nil {
+		return logBackupStatus{}, fmt.Errorf("unmarshal log backup status failed, %s", err.Error())
+	}
+	for _, task := range tasks {
+		if task.Name == taskName {
+			return task, nil
+		}
+	}
+	return logBackupStatus{}, fmt.Errorf("log backup task %s is not found", taskName)
+}
+
+func startLogBackup(ctx context.Context, target brCluster, storageAddress string) error {
+	_, err := deployment.M.BRSync(ctx, target.version, framework.GetTiupHomePathForTidb(), []string{"log", "start",
+		"--task-name=" + logBackupTaskName, "--pd=" + target.pdAddress, "--storage=" + storageAddress}, logBackupTimeout)
+	return err
+}
+
+func stopLogBackup(ctx context.Context, target brCluster) error {
+	_, err := deployment.M.BRSync(ctx, target.version, framework.GetTiupHomePathForTidb(), []string{"log", "stop",
+		"--task-name=" + logBackupTaskName, "--pd=" + target.pdAddress}, logBackupTimeout)
+	return err
+}
+
+func showLogBackupStatus(ctx context.Context, target brCluster) (logBackupStatus, error) {
+	output, err := deployment.M.BRSync(ctx, target.version, framework.GetTiupHomePathForTidb(), []string{"log", "status",
+		"--task-name=" + logBackupTaskName, "--pd=" + target.pdAddress, "--json"}, logBackupTimeout)
+	if err != nil {
+		return logBackupStatus{}, err
+	}
+	return parseLogBackupStatus(output, logBackupTaskName)
+}
+
+func truncateLogBackup(ctx context.Context, target brCluster, storageAddress string, untilTS uint64) error {
+	_, err := deployment.M.BRSync(ctx, target.version, framework.GetTiupHomePathForTidb(), []string{"log", "truncate",
+		"--storage=" + storageAddress, "--until=" + strconv.FormatUint(untilTS, 10), "--yes"}, logBackupTimeout)
+	return err
+}
+
+func (mgr *BRManager) StartLogBackup(ctx context.Context, request cluster.StartLogBackupReq) (resp cluster.StartLogBackupResp, err error) {
+	framework.LogWithContext(ctx).Infof("Begin StartLogBackup, request: %+v", request)
+	defer framework.LogWithContext(ctx).Infof("End StartLogBackup")
+
+	brRW := models.GetBRReaderWriter()
+	existTask, err := brRW.GetLogBackupTask(ctx, request.ClusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("get log backup task of cluster %s failed, %s", request.ClusterID, err.Error())
+		return resp, errors.WrapError(errors.TIUNIMANAGER_LOG_BACKUP_QUERY_FAILED, fmt.Sprintf("get log backup task of cluster %s failed, %s", request.ClusterID, err.Error()), err)
+	}
+	if existTask.ID != "" && existTask.Status == string(constants.LogBackupRunning) {
+		return resp, errors.NewErrorf(errors.TIUNIMANAGER_LOG_BACKUP_START_FAILED, "log backup task %s of cluster %s is already running", existTask.ID, request.ClusterID)
+	}
+
+	if err = mgr.backupClusterPreCheck(ctx, cluster.BackupClusterDataReq{ClusterID: request.ClusterID, BackupMode: string(constants.BackupModeManual)}); err != nil {
+		framework.LogWithContext(ctx).Errorf("start log backup precheck failed: %s", err.Error())
+		return resp, errors.WrapError(errors.TIUNIMANAGER_PARAMETER_INVALID, fmt.Sprintf("start log backup precheck failed: %s", err.Error()), err)
+	}
+	configRW := models.GetConfigReaderWriter()
+	storageTypeConfig, err := configRW.GetConfig(ctx, constants.ConfigKeyBackupStorageType)
+	if err != nil {
+		return resp, errors.WrapError(errors.TIUNIMANAGER_BACKUP_SYSTEM_CONFIG_INVAILD, fmt.Sprintf("get conifg %s failed: %s", constants.ConfigKeyBackupStorageType, err.Error()), err)
+	}
+	storagePathConfig, err := configRW.GetConfig(ctx, constants.ConfigKeyBackupStoragePath)
+	if err != nil {
+		return resp, errors.WrapError(errors.TIUNIMANAGER_BACKUP_SYSTEM_CONFIG_INVAILD, fmt.Sprintf("get conifg %s failed: %s", constants.ConfigKeyBackupStoragePath, err.Error()), err)
+	}
+
+	clusterMeta, err := meta.Get(ctx, request.ClusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("load cluster meta %s failed, %s", request.ClusterID, err.Error())
+		return resp, errors.WrapError(errors.TIUNIMANAGER_CLUSTER_NOT_FOUND, fmt.Sprintf("load cluster meta %s failed, %s", request.ClusterID, err.Error()), err)
+	}
+	brClusterParam, err := getBRCluster(clusterMeta)
+	if err != nil {
+		framework.LogWithContext(ctx).Error(err.Error())
+		return resp, errors.WrapError(errors.TIUNIMANAGER_CLUSTER_NOT_FOUND, err.Error(), err)
+	}
+
+	now := time.Now()
+	task := &backuprestore.LogBackupTask{
+		Entity: dbModel.Entity{
+			TenantId: clusterMeta.Cluster.TenantId,
+			Status:   string(constants.LogBackupRunning),
+		},
+		ClusterID:   request.ClusterID,
+		StorageType: storageTypeConfig.ConfigValue,
+		FilePath:    mgr.getBackupPath(storagePathConfig.ConfigValue, request.ClusterID, now, "log"),
+		StartTime:   now,
+	}
+	storageAddress, err := getLogBackupStorageAddress(ctx, task)
+	if err != nil {
+		return resp, errors.WrapError(errors.TIUNIMANAGER_BACKUP_SYSTEM_CONFIG_INVAILD, err.Error(), err)
+	}
+	// leave start ts to br, which takes the current tso from pd of the cluster
+	if err = startLogBackup(ctx, brClusterParam, storageAddress); err != nil {
+		framework.LogWithContext(ctx).Errorf("start log backup of cluster %s failed, %s", request.ClusterID, err.Error())
+		return resp, errors.WrapError(errors.TIUNIMANAGER_LOG_BACKUP_START_FAILED, fmt.Sprintf("start log backup of cluster %s failed, %s", request.ClusterID, err.Error()), err)
+	}
+	status, err := showLogBackupStatus(ctx, brClusterParam)
+	if err != nil || status.StartTS == 0 {
+		if err == nil {
+			err = fmt.Errorf("start ts is empty")
+		}
+		framework.LogWithContext(ctx).Errorf("get start ts of log backup of cluster %s failed, %s", request.ClusterID, err.Error())
+		if stopErr := stopLogBackup(ctx, brClusterParam); stopErr != nil {
+			framework.LogWithContext(ctx).Warnf("stop log backup of cluster %s failed, %s", request.ClusterID, stopErr.Error())
+		}
+		return resp, errors.WrapError(errors.TIUNIMANAGER_LOG_BACKUP_START_FAILED, fmt.Sprintf("get start ts of log backup of cluster %s failed, %s", request.ClusterID, err.Error()), err)
+	}
+	task.StartTS = status.StartTS
+	task.CheckpointTS = status.StartTS
+	if status.CheckpointTS > task.CheckpointTS {
+		task.CheckpointTS = status.CheckpointTS
+	}
+
+	taskCreate, err := brRW.CreateLogBackupTask(ctx, task)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("save log backup task of cluster %s failed, %s", request.ClusterID, err.Error())
+		if stopErr := stopLogBackup(ctx, brClusterParam); stopErr != nil {
+			framework.LogWithContext(ctx).Warnf("stop log backup of cluster %s failed, %s", request.ClusterID, stopErr.Error())
+		}
+		return resp, errors.WrapError(errors.TIUNIMANAGER_LOG_BACKUP_START_FAILED, fmt.Sprintf("save log backup task of cluster %s failed, %s", request.ClusterID, err.Error()), err)
+	}
+
+	resp.TaskID = taskCreate.ID
+	return resp, nil
+}
+
+func (mgr *BRManager) StopLogBackup(ctx context.Context, request cluster.StopLogBackupReq) (resp cluster.StopLogBackupResp, err error) {
+	framework.LogWithContext(ctx).Infof("Begin StopLogBackup, request: %+v", request)
+	defer framework.LogWithContext(ctx).Infof("End StopLogBackup")
+
+	task, err := mgr.getRunningLogBackupTask(ctx, request.ClusterID)
+	if err != nil {
+		return resp, err
+	}
+	clusterMeta, err := meta.Get(ctx, request.ClusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("load cluster meta %s failed, %s", request.ClusterID, err.Error())
+		return resp, errors.WrapError(errors.TIUNIMANAGER_CLUSTER_NOT_FOUND, fmt.Sprintf("load cluster meta %s failed, %s", request.ClusterID, err.Error()), err)
+	}
+	brClusterParam, err := getBRCluster(clusterMeta)
+	if err != nil {
+		framework.LogWithContext(ctx).Error(err.Error())
+		return resp, errors.WrapError(errors.TIUNIMANAGER_CLUSTER_NOT_FOUND, err.Error(), err)
+	}
+
+	// keep the final checkpoint, logs after it can not be used any more
+	mgr.refreshLogBackupCheckpoint(ctx, task, brClusterParam)
+	if err = stopLogBackup(ctx, brClusterParam); err != nil {
+		framework.LogWithContext(ctx).Errorf("stop log backup of cluster %s failed, %s", request.ClusterID, err.Error())
+		return resp, errors.WrapError(errors.TIUNIMANAGER_LOG_BACKUP_STOP_FAILED, fmt.Sprintf("stop log backup of cluster %s failed, %s", request.ClusterID, err.Error()), err)
+	}
+
+	task.Status = string(constants.LogBackupStopped)
+	task.StopTime = time.Now()
+	if err = models.GetBRReaderWriter().UpdateLogBackupTask(ctx, task); err != nil {
+		framework.LogWithContext(ctx).Errorf("update log backup task %s failed, %s", task.ID, err.Error())
+		return resp, errors.WrapError(errors.TIUNIMANAGER_LOG_BACKUP_STOP_FAILED, fmt.Sprintf("update log backup task %s failed, %s", task.ID, err.Error()), err)
+	}
+	return resp, nil
+}
+
+func (mgr *BRManager) QueryLogBackup(ctx context.Context, request cluster.QueryLogBackupReq) (resp cluster.QueryLogBackupResp, err error) {
+	framework.LogWithContext(ctx).Infof("Begin QueryLogBackup, request: %+v", request)
+	defer framework.LogWithContext(ctx).Infof("End QueryLogBackup")
+
+	task, err := mgr.getLogBackupTask(ctx, request.ClusterID)
+	if err != nil {
+		return resp, err
+	}
+	if task.ID == "" {
+		return resp, errors.NewErrorf(errors.TIUNIMANAGER_LOG_BACKUP_QUERY_FAILED, "cluster %s has no log backup task", request.ClusterID)
+	}
+
+	resp.Task = convertLogBackupTask(task)
+	return resp, nil
+}
+
+func (mgr *BRManager) TruncateLogBackup(ctx context.Context, request cluster.TruncateLogBackupReq) (resp cluster.TruncateLogBackupResp, err error) {
+	framework.LogWithContext(ctx).Infof("Begin TruncateLogBackup, request: %+v", request)
+	defer framework.LogWithContext(ctx).Infof("End TruncateLogBackup")
+
+	untilTS, err := parseRestoreTSO(request.UntilTSO, request.UntilTime)
+	if err != nil {
+		return resp, errors.WrapError(errors.TIUNIMANAGER_PARAMETER_INVALID, fmt.Sprintf("invalid truncate point, %s", err.Error()), err)
+	}
+	task, err := mgr.getLogBackupTask(ctx, request.ClusterID)
+	if err != nil {
+		return resp, err
+	}
+	if task.ID == "" {
+		return resp, errors.NewErrorf(errors.TIUNIMANAGER_LOG_BACKUP_QUERY_FAILED, "cluster %s has no log backup task", request.ClusterID)
+	}
+	if untilTS <= task.CoveredFrom() {
+		framework.LogWithContext(ctx).Infof("logs of cluster %s before %d have been truncated, skip", request.ClusterID, untilTS)
+		return resp, nil
+	}
+	if untilTS > task.CheckpointTS {
+		return resp, errors.NewErrorf(errors.TIUNIMANAGER_PARAMETER_INVALID, "truncate point %d is after checkpoint %d of log backup", untilTS, task.CheckpointTS)
+	}
+
+	clusterMeta, err := meta.Get(ctx, request.ClusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("load cluster meta %s failed, %s", request.ClusterID, err.Error())
+		return resp, errors.WrapError(errors.TIUNIMANAGER_CLUSTER_NOT_FOUND, fmt.Sprintf("load cluster meta %s failed, %s", request.ClusterID, err.Error()), err)
+	}
+	brClusterParam, err := getBRCluster(clusterMeta)
+	if err != nil {
+		framework.LogWithContext(ctx).Error(err.Error())
+		return resp, errors.WrapError(errors.TIUNIMANAGER_CLUSTER_NOT_FOUND, err.Error(), err)
+	}
+	storageAddress, err := getLogBackupStorageAddress(ctx, task)
+	if err != nil {
+		return resp, errors.WrapError(errors.TIUNIMANAGER_BACKUP_SYSTEM_CONFIG_INVAILD, err.Error(), err)
+	}
+	if err = truncateLogBackup(ctx, brClusterParam, storageAddress, untilTS); err != nil {
+		framework.LogWithContext(ctx).Errorf("truncate log backup of cluster %s failed, %s", request.ClusterID, err.Error())
+		return resp, errors.WrapError(errors.TIUNIMANAGER_LOG_BACKUP_TRUNCATE_FAILED, fmt.Sprintf("truncate log backup of cluster %s failed, %s", request.ClusterID, err.Error()), err)
+	}
+
+	task.TruncatedTS = untilTS
+	if err = models.GetBRReaderWriter().UpdateLogBackupTask(ctx, task); err != nil {
+		framework.LogWithContext(ctx).Errorf("update log backup task %s failed, %s", task.ID, err.Error())
+		return resp, errors.WrapError(errors.TIUNIMANAGER_LOG_BACKUP_TRUNCATE_FAILED, fmt.Sprintf("update log backup task %s failed, %s", task.ID, err.Error()), err)
+	}
+	return resp, nil
+}
+
+// getLogBackupTask
+// @Description: get the latest log backup task of cluster, checkpoint of running task is refreshed
+// @Parameter ctx
+// @Parameter clusterID
+// @return *backuprestore.LogBackupTask empty task if not found
+// @return error
+func (mgr *BRManager) getLogBackupTask(ctx context.Context, clusterID string) (*backuprestore.LogBackupTask, error) {
+	task, err := models.GetBRReaderWriter().GetLogBackupTask(ctx, clusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("get log backup task of cluster %s failed, %s", clusterID, err.Error())
+		return nil, errors.WrapError(errors.TIUNIMANAGER_LOG_BACKUP_QUERY_FAILED, fmt.Sprintf("get log backup task of cluster %s failed, %s", clusterID, err.Error()), err)
+	}
+	if task.ID == "" || task.Status != string(constants.LogBackupRunning) {
+		return task, nil
+	}
+
+	clusterMeta, err := meta.Get(ctx, clusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Warnf("load cluster meta %s failed, %s", clusterID, err.Error())
+		return task, nil
+	}
+	brClusterParam, err := getBRCluster(clusterMeta)
+	if err != nil {
+		framework.LogWithContext(ctx).Warn(err.Error())
+		return task, nil
+	}
+	if mgr.refreshLogBackupCheckpoint(ctx, task, brClusterParam) {
+		if err = models.GetBRReaderWriter().UpdateLogBackupTask(ctx, task); err != nil {
+			framework.LogWithContext(ctx).Warnf("update log backup task %s failed, %s", task.ID, err.Error())
+		}
+	}
+	return task, nil
+}
+
+func (mgr *BRManager) getRunningLogBackupTask(ctx context.Context, clusterID string) (*backuprestore.LogBackupTask, error) {
+	task, err := models.GetBRReaderWriter().GetLogBackupTask(ctx, clusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("get log backup task of cluster %s failed, %s", clusterID, err.Error())
+		return nil, errors.WrapError(errors.TIUNIMANAGER_LOG_BACKUP_QUERY_FAILED, fmt.Sprintf("get log backup task of cluster %s failed, %s", clusterID, err.Error()), err)
+	}
+	if task.ID == "" || task.Status != string(constants.LogBackupRunning) {
+		return nil, errors.NewErrorf(errors.TIUNIMANAGER_LOG_BACKUP_QUERY_FAILED, "cluster %s has no running log backup task", clusterID)
+	}
+	return task, nil
+}
+
+// refreshLogBackupCheckpoint update checkpoint of task by the status of log backup in cluster, return whether it is changed
+func (mgr *BRManager) refreshLogBackupCheckpoint(ctx context.Context, task *backuprestore.LogBackupTask, target brCluster) bool {
+	status, err := showLogBackupStatus(ctx, target)
+	if err != nil {
+		framework.LogWithContext(ctx).Warnf("show log backup status of cluster %s failed, %s", task.ClusterID, err.Error())
+		return false
+	}
+	if status.CheckpointTS <= task.CheckpointTS {
+		return false
+	}
+	task.CheckpointTS = status.CheckpointTS
+	return true
+}
+
+// resolveRestorePoint
+// @Description: find the full backup and log backup task to restore the cluster to the point in time.
+// The full backup must be taken between the earliest kept log and the restore point, the latest one is used if not specified
+// @Parameter ctx
+// @Parameter clusterID restored cluster
+// @Parameter backupID specified full backup, optional
+// @Parameter point
+// @return *backuprestore.BackupRecord
+// @return *backuprestore.LogBackupTask
+// @return uint64 restore tso
+// @return error
+func (mgr *BRManager) resolveRestorePoint(ctx context.Context, clusterID string, backupID string, point cluster.RestorePoint) (*backuprestore.BackupRecord, *backuprestore.LogBackupTask, uint64, error) {
+	restoreTS, err := parseRestoreTSO(point.RestoreTSO, point.RestoreTime)
+	if err != nil {
+		return nil, nil, 0, errors.WrapError(errors.TIUNIMANAGER_PARAMETER_INVALID, fmt.Sprintf("invalid restore point, %s", err.Error()), err)
+	}
+
+	brRW := models.GetBRReaderWriter()
+	var specified *backuprestore.BackupRecord
+	sourceClusterID := point.SourceClusterID
+	if backupID != "" {
+		specified, err = brRW.GetBackupRecord(ctx, backupID)
+		if err != nil {
+			framework.LogWithContext(ctx).Errorf("get backup record %s failed, %s", backupID, err.Error())
+			return nil, nil, 0, errors.WrapError(errors.TIUNIMANAGER_BACKUP_RECORD_QUERY_FAILED, fmt.Sprintf("get backup record %s failed, %s", backupID, err.Error()), err)
+		}
+		sourceClusterID = specified.ClusterID
+	}
+	if sourceClusterID == "" {
+		sourceClusterID = clusterID
+	}
+
+	task, err := mgr.getLogBackupTask(ctx, sourceClusterID)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if task.ID == "" {
+		return nil, nil, 0, errors.NewErrorf(errors.TIUNIMANAGER_RESTORE_POINT_INVALID, "cluster %s has no log backup task", sourceClusterID)
+	}
+	if restoreTS < task.CoveredFrom() || restoreTS > task.CheckpointTS {
+		return nil, nil, 0, errors.NewErrorf(errors.TIUNIMANAGER_RESTORE_POINT_INVALID, "restore point %d is out of log backup range [%d, %d] of cluster %s",
+			restoreTS, task.CoveredFrom(), task.CheckpointTS, sourceClusterID)
+	}
+
+	var candidates []*backuprestore.BackupRecord
+	if specified != nil {
+		candidates = []*backuprestore.BackupRecord{specified}
+	} else {
+		for page, pageSize := 1, defaultPageSize; ; page++ {
+			records, _, err := brRW.QueryBackupRecords(ctx, sourceClusterID, "", "", 0, 0, page, pageSize)
+			if err != nil {
+				framework.LogWithContext(ctx).Errorf("query backup records of cluster %s failed, %s", sourceClusterID, err.Error())
+				return nil, nil, 0, errors.WrapError(errors.TIUNIMANAGER_BACKUP_RECORD_QUERY_FAILED, fmt.Sprintf("query backup records of cluster %s failed, %s", sourceClusterID, err.Error()), err)
+			}
+			if len(records) == 0 {
+				break
+			}
+			candidates = append(candidates, records...)
+		}
+	}
+	record := selectRestoreBackupRecord(candidates, task.CoveredFrom(), restoreTS)
+	if record == nil {
+		return nil, nil, 0, errors.NewErrorf(errors.TIUNIMANAGER_RESTORE_POINT_INVALID, "no full backup of cluster %s between %d and restore point %d",
+			sourceClusterID, task.CoveredFrom(), restoreTS)
+	}
+	return record, task, restoreTS, nil
+}
+
+// selectRestoreBackupRecord the latest finished full backup whose tso is in [from, restoreTS], nil if not found
+func selectRestoreBackupRecord(records []*backuprestore.BackupRecord, from uint64, restoreTS uint64) *backuprestore.BackupRecord {
+	var selected *backuprestore.BackupRecord
+	for _, record := range records {
+		if record.Status != string(constants.ClusterBackupFinished) || record.BackupType != string(constants.BackupTypeFull) {
+			continue
+		}
+		if record.BackupTso < from || record.BackupTso > restoreTS {
+			continue
+		}
+		if selected == nil || record.BackupTso > selected.BackupTso {
+			selected = record
+		}
+	}
+	return selected
+}
+
+func convertLogBackupTask(task *backuprestore.LogBackupTask) structs.LogBackupTask {
+	return structs.LogBackupTask{
+		ID:              task.ID,
+		ClusterID:       task.ClusterID,
+		Status:          task.Status,
+		StorageType:     task.StorageType,
+		FilePath:        task.FilePath,
+		StartTSO:        strconv.FormatUint(task.StartTS, 10),
+		CheckpointTSO:   strconv.FormatUint(task.CheckpointTS, 10),
+		TruncatedTSO:    strconv.FormatUint(task.TruncatedTS, 10),
+		RestorableStart: tsoToTime(task.CoveredFrom()),
+		RestorableEnd:   tsoToTime(task.CheckpointTS),
+		StartTime:       task.StartTime,
+		StopTime:        task.StopTime,
+	}
+}
@@ -0,0 +1,164 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package backuprestore
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/deployment"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/backuprestore"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	"github.com/pingcap/tiunimanager/models/common"
+	"github.com/pingcap/tiunimanager/models/platform/config"
+	mock_deployment "github.com/pingcap/tiunimanager/test/mockdeployment"
+	"github.com/pingcap/tiunimanager/test/mockmodels/mockconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_tsoConvert(t *testing.T) {
+	now := time.Unix(1648688400, 123000000)
+	assert.Equal(t, now, tsoToTime(timeToTSO(now)))
+	assert.Equal(t, uint64(1648688400123)<<18, timeToTSO(now))
+}
+
+func Test_parseRestoreTSO(t *testing.T) {
+	ts, err := parseRestoreTSO("432072460356829185", 1648688400)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(432072460356829185), ts)
+
+	ts, err = parseRestoreTSO("", 1648688400)
+	assert.NoError(t, err)
+	assert.Equal(t, timeToTSO(time.Unix(1648688400, 0)), ts)
+
+	_, err = parseRestoreTSO("abc", 0)
+	assert.Error(t, err)
+	_, err = parseRestoreTSO("", 0)
+	assert.Error(t, err)
+}
+
+func Test_selectRestoreBackupRecord(t *testing.T) {
+	newRecord := func(id string, status constants.ClusterBackupStatus, backupType constants.BackupType, tso uint64) *backuprestore.BackupRecord {
+		return &backuprestore.BackupRecord{
+			Entity:     common.Entity{ID: id, Status: string(status)},
+			BackupType: string(backupType),
+			BackupTso:  tso,
+		}
+	}
+	records := []*backuprestore.BackupRecord{
+		newRecord("before-log", constants.ClusterBackupFinished, constants.BackupTypeFull, 50),
+		newRecord("first", constants.ClusterBackupFinished, constants.BackupTypeFull, 120),
+		newRecord("second", constants.ClusterBackupFinished, constants.BackupTypeFull, 200),
+		newRecord("failed", constants.ClusterBackupFailed, constants.BackupTypeFull, 250),
+		newRecord("after-point", constants.ClusterBackupFinished, constants.BackupTypeFull, 400),
+	}
+
+	assert.Equal(t, "second", selectRestoreBackupRecord(records, 100, 300).ID)
+	assert.Equal(t, "first", selectRestoreBackupRecord(records, 100, 150).ID)
+	assert.Nil(t, selectRestoreBackupRecord(records, 100, 110))
+	assert.Nil(t, selectRestoreBackupRecord(nil, 100, 300))
+}
+
+func Test_parseLogBackupStatus(t *testing.T) {
+	output := `Detail BR log in /tmp/br.log
+[{"name":"other","start_ts":1,"checkpoint":2},{"name":"tiunimanager","start_ts":432072460356829185,"checkpoint":432072460356829190,"storage":"local:///tmp/log"}]`
+	status, err := parseLogBackupStatus(output, logBackupTaskName)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(432072460356829185), status.StartTS)
+	assert.Equal(t, uint64(432072460356829190), status.CheckpointTS)
+
+	_, err = parseLogBackupStatus(`[{"name":"other","start_ts":1,"checkpoint":2}]`, logBackupTaskName)
+	assert.Error(t, err)
+	_, err = parseLogBackupStatus("no task", logBackupTaskName)
+	assert.Error(t, err)
+}
+
+func Test_getBRStorageAddress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	configRW := mockconfig.NewMockReaderWriter(ctrl)
+	configRW.EXPECT().GetConfig(gomock.Any(), constants.ConfigKeyBackupS3Endpoint).Return(&config.SystemConfig{ConfigValue: "http://minio:9000"}, nil).AnyTimes()
+	configRW.EXPECT().GetConfig(gomock.Any(), constants.ConfigKeyBackupS3AccessKey).Return(&config.SystemConfig{ConfigValue: "ak"}, nil).AnyTimes()
+	configRW.EXPECT().GetConfig(gomock.Any(), constants.ConfigKeyBackupS3SecretAccessKey).Return(&config.SystemConfig{ConfigValue: "s k&x"}, nil).AnyTimes()
+	models.SetConfigReaderWriter(configRW)
+
+	t.Run("nfs", func(t *testing.T) {
+		address, err := getBRStorageAddress(context.TODO(), string(constants.StorageTypeNFS), "/nfs/backup dir/cls-test")
+		assert.NoError(t, err)
+		assert.Equal(t, "local:///nfs/backup%20dir/cls-test", address)
+	})
+	t.Run("s3", func(t *testing.T) {
+		address, err := getBRStorageAddress(context.TODO(), string(constants.StorageTypeS3), "bucket/backup/cls-test")
+		assert.NoError(t, err)
+		assert.NotContains(t, address, " ")
+		parsed, err := url.Parse(address)
+		assert.NoError(t, err)
+		assert.Equal(t, "bucket", parsed.Host)
+		assert.Equal(t, "/backup/cls-test", parsed.Path)
+		assert.Equal(t, "s k&x", parsed.Query().Get("secret-access-key"))
+		assert.Equal(t, "http://minio:9000", parsed.Query().Get("endpoint"))
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := getBRStorageAddress(context.TODO(), "unknown", "/nfs/backup")
+		assert.Error(t, err)
+	})
+}
+
+func Test_logBackupCommands(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDeployment := mock_deployment.NewMockInterface(ctrl)
+	deployment.M = mockDeployment
+
+	target, err := getBRCluster(&meta.ClusterMeta{
+		Cluster: &management.Cluster{Entity: common.Entity{ID: "cls-test"}, Version: "v6.2.0"},
+		Instances: map[string][]*management.ClusterInstance{
+			string(constants.ComponentIDPD): {
+				{Entity: common.Entity{Status: string(constants.ClusterInstanceRunning)}, HostIP: []string{"127.0.0.1"}, Ports: []int32{2379}},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	mockDeployment.EXPECT().BRSync(gomock.Any(), "v6.2.0", gomock.Any(), []string{"log", "start",
+		"--task-name=tiunimanager", "--pd=127.0.0.1:2379", "--storage=local:///nfs/log"}, logBackupTimeout).Return("", nil)
+	assert.NoError(t, startLogBackup(context.TODO(), target, "local:///nfs/log"))
+
+	mockDeployment.EXPECT().BRSync(gomock.Any(), "v6.2.0", gomock.Any(), []string{"log", "status",
+		"--task-name=tiunimanager", "--pd=127.0.0.1:2379", "--json"}, logBackupTimeout).Return(`[{"name":"tiunimanager","start_ts":10,"checkpoint":20}]`, nil)
+	status, err := showLogBackupStatus(context.TODO(), target)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(20), status.CheckpointTS)
+
+	mockDeployment.EXPECT().BRSync(gomock.Any(), "v6.2.0", gomock.Any(), []string{"log", "truncate",
+		"--storage=local:///nfs/log", "--until=15", "--yes"}, logBackupTimeout).Return("", nil)
+	assert.NoError(t, truncateLogBackup(context.TODO(), target, "local:///nfs/log", 15))
+
+	mockDeployment.EXPECT().BRSync(gomock.Any(), "v6.2.0", gomock.Any(), []string{"log", "stop",
+		"--task-name=tiunimanager", "--pd=127.0.0.1:2379"}, logBackupTimeout).Return("", nil)
+	assert.NoError(t, stopLogBackup(context.TODO(), target))
+
+	_, err = getBRCluster(&meta.ClusterMeta{Cluster: &management.Cluster{Entity: common.Entity{ID: "cls-test"}}})
+	assert.Error(t, err)
+}
@@ -17,8 +17,8 @@ package backuprestore
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"github.com/labstack/gommon/bytes"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/pingcap/tiunimanager/common/constants"
@@ -26,12 +26,14 @@ import (
 	"github.com/pingcap/tiunimanager/common/structs"
 	"github.com/pingcap/tiunimanager/library/framework"
 	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/maintainwindow"
 	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
 	"github.com/pingcap/tiunimanager/models"
 	"github.com/pingcap/tiunimanager/models/cluster/backuprestore"
 	dbModel "github.com/pingcap/tiunimanager/models/common"
 	utilsql "github.com/pingcap/tiunimanager/util/api/tidb/sql"
 	workflow "github.com/pingcap/tiunimanager/workflow2"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -65,24 +67,44 @@ func NewBRManager() *BRManager {
 	flowManager.RegisterWorkFlow(context.TODO(), constants.FlowBackupCluster, &workflow.WorkFlowDefine{
 		FlowName: constants.FlowBackupCluster,
 		TaskNodes: map[string]*workflow.NodeDefine{
-			"start":            {"backup", "backupDone", "fail", workflow.SyncFuncNode, backupCluster},
-			"backupDone":       {"updateBackupRecord", "updateRecordDone", "fail", workflow.SyncFuncNode, updateBackupRecord},
-			"updateRecordDone": {"end", "", "", workflow.SyncFuncNode, defaultEnd},
-			"fail":             {"fail", "", "", workflow.SyncFuncNode, backupFail},
+			"start":            {"backup", "backupDone", "fail", workflow.SyncFuncNode, backupCluster, nil},
+			"backupDone":       {"updateBackupRecord", "updateRecordDone", "fail", workflow.SyncFuncNode, updateBackupRecord, nil},
+			"updateRecordDone": {"end", "", "", workflow.SyncFuncNode, defaultEnd, nil},
+			"fail":             {"fail", "", "", workflow.SyncFuncNode, backupFail, nil},
 		},
 	})
 	flowManager.RegisterWorkFlow(context.TODO(), constants.FlowRestoreExistCluster, &workflow.WorkFlowDefine{
 		FlowName: constants.FlowRestoreExistCluster,
 		TaskNodes: map[string]*workflow.NodeDefine{
-			"start":       {"restoreFromSrcCluster", "restoreDone", "fail", workflow.SyncFuncNode, restoreFromSrcCluster},
-			"restoreDone": {"end", "", "", workflow.SyncFuncNode, defaultEnd},
-			"fail":        {"fail", "", "", workflow.SyncFuncNode, restoreFail},
+			"start":       {"restoreFromSrcCluster", "restoreDone", "fail", workflow.PollingNode, restoreFromSrcCluster, nil},
+			"restoreDone": {"end", "", "", workflow.SyncFuncNode, defaultEnd, nil},
+			"fail":        {"fail", "", "", workflow.SyncFuncNode, restoreFail, nil},
+		},
+	})
+
+	flowManager.RegisterWorkFlow(context.TODO(), constants.FlowVerifyBackup, &workflow.WorkFlowDefine{
+		FlowName: constants.FlowVerifyBackup,
+		TaskNodes: map[string]*workflow.NodeDefine{
+			"start":           {"verifyBackupFiles", "filesVerified", "fail", workflow.SyncFuncNode, verifyBackupFiles, nil},
+			"filesVerified":   {"testRestore", "testRestoreDone", "fail", workflow.SyncFuncNode, testRestoreBackup, nil},
+			"testRestoreDone": {"destroyTestRestoreCluster", "destroyDone", "fail", workflow.SyncFuncNode, destroyTestRestoreCluster, nil},
+			"destroyDone":     {"verifyPassed", "", "", workflow.SyncFuncNode, verifyPassed, nil},
+			"fail":            {"fail", "", "", workflow.SyncFuncNode, verifyFail, nil},
 		},
 	})
 
 	mgr := &BRManager{
 		autoBackupMgr: NewAutoBackupManager(),
 	}
+	// auto backup out of the maintain window is queued, and launched when the window opens
+	maintainwindow.RegisterOperation(constants.PendingOperationBackupCluster, func(ctx context.Context, request string) (string, error) {
+		req := cluster.BackupClusterDataReq{}
+		if err := json.Unmarshal([]byte(request), &req); err != nil {
+			return "", errors.WrapError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, "unmarshal backup cluster request failed", err)
+		}
+		resp, err := mgr.BackupCluster(ctx, req, true)
+		return resp.WorkFlowID, err
+	})
 
 	return mgr
 }
@@ -187,11 +209,23 @@ func (mgr *BRManager) RestoreExistCluster(ctx context.Context, request cluster.R
 		return resp, errors.WrapError(errors.TIUNIMANAGER_CLUSTER_NOT_FOUND, fmt.Sprintf("load cluster meta %s failed, %s", request.ClusterID, err.Error()), err)
 	}
 
-	brRW := models.GetBRReaderWriter()
-	record, err := brRW.GetBackupRecord(ctx, request.BackupID)
-	if err != nil {
-		framework.LogWithContext(ctx).Errorf("get backup record %s failed, %s", request.BackupID, err.Error())
-		return resp, errors.WrapError(errors.TIUNIMANAGER_BACKUP_RECORD_QUERY_FAILED, fmt.Sprintf("get backup record %s failed, %s", request.BackupID, err.Error()), err)
+	var record *backuprestore.BackupRecord
+	logBackupTask := &backuprestore.LogBackupTask{}
+	var restoreTS uint64
+	if request.RestorePoint.IsSet() {
+		record, logBackupTask, restoreTS, err = mgr.resolveRestorePoint(ctx, request.ClusterID, request.BackupID, request.RestorePoint)
+		if err != nil {
+			framework.LogWithContext(ctx).Errorf("resolve restore point %+v failed, %s", request.RestorePoint, err.Error())
+			return resp, err
+		}
+		framework.LogWithContext(ctx).Infof("restore cluster %s to %d by backup record %s and log backup task %s", request.ClusterID, restoreTS, record.ID, logBackupTask.ID)
+	} else {
+		brRW := models.GetBRReaderWriter()
+		record, err = brRW.GetBackupRecord(ctx, request.BackupID)
+		if err != nil {
+			framework.LogWithContext(ctx).Errorf("get backup record %s failed, %s", request.BackupID, err.Error())
+			return resp, errors.WrapError(errors.TIUNIMANAGER_BACKUP_RECORD_QUERY_FAILED, fmt.Sprintf("get backup record %s failed, %s", request.BackupID, err.Error()), err)
+		}
 	}
 
 	if maintenanceStatusChange {
@@ -216,6 +250,8 @@ func (mgr *BRManager) RestoreExistCluster(ctx context.Context, request cluster.R
 	}
 
 	flowManager.InitContext(ctx, flowId, contextBackupRecordKey, record)
+	flowManager.InitContext(ctx, flowId, contextLogBackupTaskKey, logBackupTask)
+	flowManager.InitContext(ctx, flowId, contextRestoreTSKey, restoreTS)
 	flowManager.InitContext(ctx, flowId, contextClusterMetaKey, meta)
 	flowManager.InitContext(ctx, flowId, contextMaintenanceStatusChangeKey, maintenanceStatusChange)
 	if err = flowManager.Start(ctx, flowId); err != nil {
@@ -306,22 +342,7 @@ func (mgr *BRManager) QueryClusterBackupRecords(ctx context.Context, request clu
 		BackupRecords: make([]*structs.BackupRecord, len(records)),
 	}
 	for index, record := range records {
-		response.BackupRecords[index] = &structs.BackupRecord{
-			ID:           record.ID,
-			ClusterID:    record.ClusterID,
-			BackupType:   record.BackupType,
-			BackupMethod: record.BackupMethod,
-			BackupMode:   record.BackupMode,
-			FilePath:     record.FilePath,
-			Size:         float32(record.Size) / bytes.MB, //Byte to MByte,
-			BackupTSO:    strconv.FormatUint(record.BackupTso, 10),
-			Status:       record.Status,
-			StartTime:    record.StartTime,
-			EndTime:      record.EndTime,
-			CreateTime:   record.CreatedAt,
-			UpdateTime:   record.UpdatedAt,
-			DeleteTime:   record.DeletedAt.Time,
-		}
+		response.BackupRecords[index] = convertBackupRecord(record)
 	}
 
 	return response, structs.Page{Page: request.Page, PageSize: request.PageSize, Total: int(total)}, nil
@@ -400,6 +421,13 @@ func (mgr *BRManager) GetBackupStrategy(ctx context.Context, request cluster.Get
 		ClusterID:  request.ClusterID,
 		BackupDate: strategy.BackupDate,
 		Period:     fmt.Sprintf("%d:00-%d:00", strategy.StartHour, strategy.EndHour),
+		Retention: structs.BackupRetention{
+			KeepLast:    strategy.KeepLast,
+			KeepDays:    strategy.KeepDays,
+			KeepWeekly:  strategy.KeepWeekly,
+			KeepMonthly: strategy.KeepMonthly,
+		},
+		AutoVerify: strategy.AutoVerify,
 	}
 	return resp, nil
 }
@@ -430,10 +458,15 @@ func (mgr *BRManager) SaveBackupStrategy(ctx context.Context, request cluster.Sa
 		Entity: dbModel.Entity{
 			TenantId: meta.Cluster.TenantId,
 		},
-		ClusterID:  request.ClusterID,
-		BackupDate: request.Strategy.BackupDate,
-		StartHour:  uint32(startHour),
-		EndHour:    uint32(endHour),
+		ClusterID:   request.ClusterID,
+		BackupDate:  request.Strategy.BackupDate,
+		StartHour:   uint32(startHour),
+		EndHour:     uint32(endHour),
+		KeepLast:    request.Strategy.Retention.KeepLast,
+		KeepDays:    request.Strategy.Retention.KeepDays,
+		KeepWeekly:  request.Strategy.Retention.KeepWeekly,
+		KeepMonthly: request.Strategy.Retention.KeepMonthly,
+		AutoVerify:  request.Strategy.AutoVerify,
 	})
 	if err != nil {
 		framework.LogWithContext(ctx).Errorf("save backup strategy %+v failed %s", strategy, err.Error())
@@ -535,6 +568,11 @@ func (mgr *BRManager) saveBackupStrategyPreCheck(ctx context.Context, request cl
 		}
 	}
 
+	retention := request.Strategy.Retention
+	if retention.KeepLast < 0 || retention.KeepDays < 0 || retention.KeepWeekly < 0 || retention.KeepMonthly < 0 {
+		return fmt.Errorf("invalid param retention, %+v", retention)
+	}
+
 	return nil
 }
 
@@ -550,50 +588,115 @@ func (mgr *BRManager) checkFilePathExists(path string) bool {
 	return true
 }
 
+// removeBackupFiles
+// @Description: remove files of the backup in background, s3 configs are checked before it returns
+// @Receiver mgr
+// @Parameter ctx
+// @Parameter record
+// @return error
 func (mgr *BRManager) removeBackupFiles(ctx context.Context, record *backuprestore.BackupRecord) error {
 	if string(constants.StorageTypeS3) == record.StorageType {
-		configRW := models.GetConfigReaderWriter()
-		endpointCfg, err := configRW.GetConfig(ctx, constants.ConfigKeyBackupS3Endpoint)
-		if err != nil || endpointCfg.ConfigValue == "" {
-			return fmt.Errorf("get and check conifg %s failed", constants.ConfigKeyBackupS3Endpoint)
+		if _, err := newBackupS3Client(ctx); err != nil {
+			return err
 		}
-		akCfg, err := configRW.GetConfig(ctx, constants.ConfigKeyBackupS3AccessKey)
-		if err != nil || akCfg.ConfigValue == "" {
-			return fmt.Errorf("get and check conifg %s failed", constants.ConfigKeyBackupS3AccessKey)
+	}
+	go func() {
+		if err := deleteBackupFiles(ctx, record); err != nil {
+			framework.LogWithContext(ctx).Warnf("remove backup files of recordId %s failed, %s", record.ID, err.Error())
 		}
-		skCfg, err := configRW.GetConfig(ctx, constants.ConfigKeyBackupS3SecretAccessKey)
-		if err != nil || skCfg.ConfigValue == "" {
-			return fmt.Errorf("get and check conifg %s failed", constants.ConfigKeyBackupS3SecretAccessKey)
+	}()
+	return nil
+}
+
+// newBackupS3Client
+// @Description: create client of the s3 storage which backups are stored in
+// @Parameter ctx
+// @return *minio.Client
+// @return error
+func newBackupS3Client(ctx context.Context) (*minio.Client, error) {
+	configRW := models.GetConfigReaderWriter()
+	endpointCfg, err := configRW.GetConfig(ctx, constants.ConfigKeyBackupS3Endpoint)
+	if err != nil || endpointCfg.ConfigValue == "" {
+		return nil, fmt.Errorf("get and check conifg %s failed", constants.ConfigKeyBackupS3Endpoint)
+	}
+	akCfg, err := configRW.GetConfig(ctx, constants.ConfigKeyBackupS3AccessKey)
+	if err != nil || akCfg.ConfigValue == "" {
+		return nil, fmt.Errorf("get and check conifg %s failed", constants.ConfigKeyBackupS3AccessKey)
+	}
+	skCfg, err := configRW.GetConfig(ctx, constants.ConfigKeyBackupS3SecretAccessKey)
+	if err != nil || skCfg.ConfigValue == "" {
+		return nil, fmt.Errorf("get and check conifg %s failed", constants.ConfigKeyBackupS3SecretAccessKey)
+	}
+	endpoint, secure, err := parseS3Endpoint(endpointCfg.ConfigValue)
+	if err != nil {
+		return nil, err
+	}
+	s3Client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(akCfg.ConfigValue, skCfg.ConfigValue, ""),
+		Secure: secure,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create s3 client failed, %s", err.Error())
+	}
+	return s3Client, nil
+}
+
+// parseS3Endpoint
+// @Description: split the endpoint config into host and scheme, endpoint without scheme is accessed by http
+// @Parameter endpoint
+// @return host
+// @return secure true if the scheme is https
+// @return err
+func parseS3Endpoint(endpoint string) (host string, secure bool, err error) {
+	if !strings.Contains(endpoint, "://") {
+		return endpoint, false, nil
+	}
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid s3 endpoint %s, %s", endpoint, err.Error())
+	}
+	if endpointURL.Host == "" {
+		return "", false, fmt.Errorf("invalid s3 endpoint %s, host is empty", endpoint)
+	}
+	switch endpointURL.Scheme {
+	case "http":
+		return endpointURL.Host, false, nil
+	case "https":
+		return endpointURL.Host, true, nil
+	default:
+		return "", false, fmt.Errorf("invalid s3 endpoint %s, scheme %s is not supported", endpoint, endpointURL.Scheme)
+	}
+}
+
+// deleteBackupFiles
+// @Description: remove files of the backup, it returns after all files are tried
+// @Parameter ctx
+// @Parameter record
+// @return error the last error if any file is not removed
+func deleteBackupFiles(ctx context.Context, record *backuprestore.BackupRecord) error {
+	if string(constants.StorageTypeS3) != record.StorageType {
+		return os.RemoveAll(record.FilePath)
+	}
+
+	s3Client, err := newBackupS3Client(ctx)
+	if err != nil {
+		return err
+	}
+	s3Addr := strings.SplitN(record.FilePath, "/", 2)
+	if len(s3Addr) != 2 {
+		return fmt.Errorf("invalid s3 backup path %s", record.FilePath)
+	}
+	framework.LogWithContext(ctx).Infof("begin remove bucket:%s object:%s", s3Addr[0], s3Addr[1])
+	var removeErr error
+	objectChan := s3Client.ListObjects(ctx, s3Addr[0], minio.ListObjectsOptions{Recursive: true, Prefix: s3Addr[1]})
+	for object := range objectChan {
+		if object.Err != nil {
+			return fmt.Errorf("list objects of %s failed, %s", record.FilePath, object.Err.Error())
+		}
+		if err = s3Client.RemoveObject(context.TODO(), s3Addr[0], object.Key, minio.RemoveObjectOptions{ForceDelete: true}); err != nil {
+			framework.LogWithContext(ctx).Warnf("remove object %s of %s failed: %v", object.Key, record.FilePath, err)
+			removeErr = err
 		}
-		go func() {
-			endpoint := strings.TrimPrefix(endpointCfg.ConfigValue, "http://")
-			s3Client, err := minio.New(endpoint, &minio.Options{
-				Creds:  credentials.NewStaticV4(akCfg.ConfigValue, skCfg.ConfigValue, ""),
-				Secure: false,
-			})
-			if err != nil {
-				framework.LogWithContext(ctx).Warnf("create s3 client failed: %s", err.Error())
-				return
-			}
-			s3Addr := strings.SplitN(record.FilePath, "/", 2)
-			if len(s3Addr) != 2 {
-				return
-			}
-			framework.LogWithContext(ctx).Infof("begin remove bucket:%s object:%s", s3Addr[0], s3Addr[1])
-			objectChan := s3Client.ListObjects(ctx, s3Addr[0], minio.ListObjectsOptions{Recursive: true, Prefix: s3Addr[1]})
-			for object := range objectChan {
-				if err = s3Client.RemoveObject(context.TODO(), s3Addr[0], object.Key, minio.RemoveObjectOptions{ForceDelete: true}); err != nil {
-					framework.LogWithContext(ctx).Warnf("remove bucket %s failed: %v", record.FilePath, err)
-				}
-			}
-		}()
-	} else {
-		go func() {
-			if err := os.RemoveAll(record.FilePath); err != nil {
-				framework.LogWithContext(ctx).Warnf("remove backup filePath %s, result %v", record.FilePath, err)
-				return
-			}
-		}()
 	}
-	return nil
+	return removeErr
 }
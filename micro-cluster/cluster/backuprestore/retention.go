@@ -0,0 +1,255 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package backuprestore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/labstack/gommon/bytes"
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/backuprestore"
+	log "github.com/sirupsen/logrus"
+)
+
+func (mgr *BRManager) PruneBackupRecords(ctx context.Context, request cluster.PruneBackupRecordsReq) (resp cluster.PruneBackupRecordsResp, err error) {
+	framework.LogWithContext(ctx).Infof("Begin PruneBackupRecords, request: %+v", request)
+	defer framework.LogWithContext(ctx).Infof("End PruneBackupRecords")
+
+	resp.PrunedRecords = make([]*structs.BackupRecord, 0)
+	resp.FailedRecords = make([]*structs.BackupRecord, 0)
+	brRW := models.GetBRReaderWriter()
+	strategy, err := brRW.GetBackupStrategy(ctx, request.ClusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("get backup strategy of cluster %s failed %s", request.ClusterID, err.Error())
+		return resp, errors.WrapError(errors.TIUNIMANAGER_BACKUP_STRATEGY_QUERY_FAILED, fmt.Sprintf("get backup strategy of cluster %s failed %s", request.ClusterID, err.Error()), err)
+	}
+	if strategy.ID == "" || !strategy.RetentionEnabled() {
+		framework.LogWithContext(ctx).Infof("cluster %s has no backup retention rule, skip prune", request.ClusterID)
+		return resp, nil
+	}
+
+	candidates := make([]*backuprestore.BackupRecord, 0)
+	// backups failed to be pruned by the last run are expired already, prune them again
+	retried := make([]*backuprestore.BackupRecord, 0)
+	for page, pageSize := 1, defaultPageSize; ; page++ {
+		records, _, err := brRW.QueryBackupRecords(ctx, request.ClusterID, "", string(constants.BackupModeAuto), 0, 0, page, pageSize)
+		if err != nil {
+			framework.LogWithContext(ctx).Errorf("query auto backup records of cluster %s failed, %s", request.ClusterID, err.Error())
+			return resp, errors.WrapError(errors.TIUNIMANAGER_BACKUP_RECORD_QUERY_FAILED, fmt.Sprintf("query cluster %s backup records failed %s", request.ClusterID, err.Error()), err)
+		}
+		if len(records) == 0 {
+			break
+		}
+		for _, record := range records {
+			// processing and failed backups are not counted by retention rules
+			if string(constants.ClusterBackupFinished) == record.Status {
+				candidates = append(candidates, record)
+			}
+			if string(constants.ClusterBackupPruneFailed) == record.Status {
+				retried = append(retried, record)
+			}
+		}
+	}
+
+	// logs of the log backup task are useless without the full backup as restore base
+	task, err := brRW.GetLogBackupTask(ctx, request.ClusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("get log backup task of cluster %s failed, %s", request.ClusterID, err.Error())
+		return resp, errors.WrapError(errors.TIUNIMANAGER_LOG_BACKUP_QUERY_FAILED, fmt.Sprintf("get log backup task of cluster %s failed, %s", request.ClusterID, err.Error()), err)
+	}
+	expired := selectExpiredBackupRecords(candidates, strategy, time.Now())
+	if base := selectPITRBaseBackupRecord(candidates, task); base != nil {
+		framework.LogWithContext(ctx).Infof("keep backup %s of cluster %s as the restore base of log backup task %s", base.ID, request.ClusterID, task.ID)
+		expired = excludeBackupRecord(expired, base)
+	}
+	expired = append(retried, expired...)
+
+	if request.DryRun {
+		for _, record := range expired {
+			resp.PrunedRecords = append(resp.PrunedRecords, convertBackupRecord(record))
+		}
+		framework.LogWithContext(ctx).Infof("%d expired backups of cluster %s to be pruned", len(resp.PrunedRecords), request.ClusterID)
+		return resp, nil
+	}
+
+	// results are recorded in audit log even if pruning is interrupted
+	defer func() {
+		mgr.auditPruneBackupRecords(ctx, request.ClusterID, resp)
+	}()
+	for _, record := range expired {
+		if err = deleteBackupFiles(ctx, record); err != nil {
+			// keep the record to prune its files again by the next run
+			framework.LogWithContext(ctx).Warnf("remove backup files of recordId %s failed, %s", record.ID, err.Error())
+			if err = brRW.UpdateBackupRecord(ctx, record.ID, string(constants.ClusterBackupPruneFailed), 0, 0, time.Time{}); err != nil {
+				framework.LogWithContext(ctx).Errorf("update status of backup record %s failed, %s", record.ID, err.Error())
+				return resp, errors.WrapError(errors.TIUNIMANAGER_BACKUP_FILE_DELETE_FAILED, fmt.Sprintf("update status of backup record %s failed, %s", record.ID, err.Error()), err)
+			}
+			record.Status = string(constants.ClusterBackupPruneFailed)
+			resp.FailedRecords = append(resp.FailedRecords, convertBackupRecord(record))
+			continue
+		}
+		if err = brRW.DeleteBackupRecord(ctx, record.ID); err != nil {
+			framework.LogWithContext(ctx).Errorf("delete backup record %s failed, %s", record.ID, err.Error())
+			return resp, errors.WrapError(errors.TIUNIMANAGER_BACKUP_RECORD_DELETE_FAILED, fmt.Sprintf("delete backup record %s failed, %s", record.ID, err.Error()), err)
+		}
+		resp.PrunedRecords = append(resp.PrunedRecords, convertBackupRecord(record))
+	}
+	framework.LogWithContext(ctx).Infof("prune %d expired backups of cluster %s, %d failed", len(resp.PrunedRecords), request.ClusterID, len(resp.FailedRecords))
+
+	return resp, nil
+}
+
+// auditPruneBackupRecords
+// @Description: record backups pruned and failed to be pruned in audit log
+// @Receiver mgr
+// @Parameter ctx
+// @Parameter clusterID
+// @Parameter resp
+func (mgr *BRManager) auditPruneBackupRecords(ctx context.Context, clusterID string, resp cluster.PruneBackupRecordsResp) {
+	if len(resp.PrunedRecords) == 0 && len(resp.FailedRecords) == 0 {
+		return
+	}
+	pruned := make([]string, 0, len(resp.PrunedRecords))
+	for _, record := range resp.PrunedRecords {
+		pruned = append(pruned, record.ID)
+	}
+	failed := make([]string, 0, len(resp.FailedRecords))
+	for _, record := range resp.FailedRecords {
+		failed = append(failed, record.ID)
+	}
+	framework.LogForkFile(constants.LogFileAudit).WithFields(log.Fields{
+		"operatorID":         framework.GetUserIDFromContext(ctx),
+		"operatorFinishTime": time.Now(),
+		"event":              "PruneBackupRecords",
+		"operation":          clusterID,
+		"pruned":             pruned,
+		"failed":             failed,
+	}).Info()
+}
+
+// selectExpiredBackupRecords
+// @Description: select backups kept by none of the retention rules, it works like GFS rotation,
+// the latest backup of a week or a month is kept as the weekly or monthly copy
+// @Parameter records
+// @Parameter strategy
+// @Parameter now
+// @return []*backuprestore.BackupRecord
+func selectExpiredBackupRecords(records []*backuprestore.BackupRecord, strategy *backuprestore.BackupStrategy, now time.Time) []*backuprestore.BackupRecord {
+	expired := make([]*backuprestore.BackupRecord, 0)
+	if !strategy.RetentionEnabled() {
+		return expired
+	}
+
+	sorted := make([]*backuprestore.BackupRecord, len(records))
+	copy(sorted, records)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].StartTime.After(sorted[j].StartTime)
+	})
+
+	weeks := make(map[string]bool)
+	months := make(map[string]bool)
+	for index, record := range sorted {
+		kept := false
+		if index < strategy.KeepLast {
+			kept = true
+		}
+		if strategy.KeepDays > 0 && record.StartTime.After(now.AddDate(0, 0, -strategy.KeepDays)) {
+			kept = true
+		}
+		year, week := record.StartTime.ISOWeek()
+		weekKey := fmt.Sprintf("%d-%d", year, week)
+		if !weeks[weekKey] && len(weeks) < strategy.KeepWeekly {
+			weeks[weekKey] = true
+			kept = true
+		}
+		monthKey := record.StartTime.Format("2006-01")
+		if !months[monthKey] && len(months) < strategy.KeepMonthly {
+			months[monthKey] = true
+			kept = true
+		}
+		if !kept {
+			expired = append(expired, record)
+		}
+	}
+	return expired
+}
+
+// selectPITRBaseBackupRecord
+// @Description: select the full backup which restore points of the log backup task are based on.
+// Restore point is restored from the latest full backup after the earliest kept log and before it,
+// so the earliest one keeps all restore points restorable, even if later full backups are pruned
+// @Parameter records
+// @Parameter task log backup task of the cluster, empty if not found
+// @return *backuprestore.BackupRecord nil if there is no log backup task or no full backup in its range
+func selectPITRBaseBackupRecord(records []*backuprestore.BackupRecord, task *backuprestore.LogBackupTask) *backuprestore.BackupRecord {
+	if task == nil || task.ID == "" {
+		return nil
+	}
+	var base *backuprestore.BackupRecord
+	for _, record := range records {
+		if record.Status != string(constants.ClusterBackupFinished) || record.BackupType != string(constants.BackupTypeFull) {
+			continue
+		}
+		if record.BackupTso < task.CoveredFrom() {
+			continue
+		}
+		if base == nil || record.BackupTso < base.BackupTso {
+			base = record
+		}
+	}
+	return base
+}
+
+func excludeBackupRecord(records []*backuprestore.BackupRecord, excluded *backuprestore.BackupRecord) []*backuprestore.BackupRecord {
+	result := make([]*backuprestore.BackupRecord, 0, len(records))
+	for _, record := range records {
+		if record.ID != excluded.ID {
+			result = append(result, record)
+		}
+	}
+	return result
+}
+
+func convertBackupRecord(record *backuprestore.BackupRecord) *structs.BackupRecord {
+	return &structs.BackupRecord{
+		ID:            record.ID,
+		ClusterID:     record.ClusterID,
+		BackupType:    record.BackupType,
+		BackupMethod:  record.BackupMethod,
+		BackupMode:    record.BackupMode,
+		FilePath:      record.FilePath,
+		Size:          float32(record.Size) / bytes.MB, //Byte to MByte,
+		BackupTSO:     strconv.FormatUint(record.BackupTso, 10),
+		Status:        record.Status,
+		StartTime:     record.StartTime,
+		EndTime:       record.EndTime,
+		CreateTime:    record.CreatedAt,
+		UpdateTime:    record.UpdatedAt,
+		DeleteTime:    record.DeletedAt.Time,
+		VerifyStatus:  record.VerifyStatus,
+		VerifyMessage: record.VerifyMessage,
+		VerifyTime:    record.VerifyTime,
+	}
+}
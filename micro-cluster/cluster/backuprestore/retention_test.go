@@ -0,0 +1,210 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package backuprestore
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/backuprestore"
+	"github.com/pingcap/tiunimanager/models/common"
+	"github.com/pingcap/tiunimanager/test/mockmodels/mockbr"
+	"github.com/pingcap/tiunimanager/test/mockmodels/mockconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+// dailyBackupRecords one backup every day before now, the newest first
+func dailyBackupRecords(now time.Time, days int) []*backuprestore.BackupRecord {
+	records := make([]*backuprestore.BackupRecord, 0)
+	for i := 0; i < days; i++ {
+		records = append(records, &backuprestore.BackupRecord{
+			Entity: common.Entity{
+				ID:     fmt.Sprintf("backup-%d", i),
+				Status: string(constants.ClusterBackupFinished),
+			},
+			BackupMode: string(constants.BackupModeAuto),
+			StartTime:  now.AddDate(0, 0, -i),
+		})
+	}
+	return records
+}
+
+func Test_selectExpiredBackupRecords(t *testing.T) {
+	now := time.Date(2022, 3, 31, 1, 0, 0, 0, time.Local)
+	records := dailyBackupRecords(now, 90)
+
+	t.Run("disabled", func(t *testing.T) {
+		expired := selectExpiredBackupRecords(records, &backuprestore.BackupStrategy{}, now)
+		assert.Empty(t, expired)
+	})
+	t.Run("keep last", func(t *testing.T) {
+		expired := selectExpiredBackupRecords(records, &backuprestore.BackupStrategy{KeepLast: 7}, now)
+		assert.Equal(t, 83, len(expired))
+		assert.Equal(t, "backup-7", expired[0].ID)
+	})
+	t.Run("keep days", func(t *testing.T) {
+		expired := selectExpiredBackupRecords(records, &backuprestore.BackupStrategy{KeepDays: 30}, now)
+		assert.Equal(t, 60, len(expired))
+	})
+	t.Run("gfs", func(t *testing.T) {
+		expired := selectExpiredBackupRecords(records, &backuprestore.BackupStrategy{KeepLast: 7, KeepWeekly: 4, KeepMonthly: 3}, now)
+		kept := make(map[string]bool)
+		for _, record := range records {
+			kept[record.ID] = true
+		}
+		for _, record := range expired {
+			delete(kept, record.ID)
+		}
+		// monthly copies of March, February and January
+		assert.True(t, kept["backup-0"])
+		assert.True(t, kept["backup-31"])
+		assert.True(t, kept["backup-59"])
+		// weekly copies, weeks end on Sunday
+		assert.True(t, kept["backup-11"])
+		assert.True(t, kept["backup-18"])
+		assert.False(t, kept["backup-10"])
+		assert.False(t, kept["backup-60"])
+		assert.Equal(t, 7+2+2, len(kept))
+	})
+}
+
+func TestBRManager_PruneBackupRecords(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	brRW := mockbr.NewMockReaderWriter(ctrl)
+	models.SetBRReaderWriter(brRW)
+	brRW.EXPECT().GetBackupStrategy(gomock.Any(), gomock.Any()).Return(&backuprestore.BackupStrategy{
+		Entity:   common.Entity{ID: "strategy-id"},
+		KeepLast: 2,
+	}, nil)
+	brRW.EXPECT().QueryBackupRecords(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), 1, gomock.Any()).Return(dailyBackupRecords(now, 5), int64(5), nil)
+	brRW.EXPECT().QueryBackupRecords(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), 2, gomock.Any()).Return(nil, int64(5), nil)
+	brRW.EXPECT().GetLogBackupTask(gomock.Any(), gomock.Any()).Return(&backuprestore.LogBackupTask{}, nil)
+
+	mgr := &BRManager{}
+	resp, err := mgr.PruneBackupRecords(context.TODO(), cluster.PruneBackupRecordsReq{ClusterID: "cls-xxxx", DryRun: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(resp.PrunedRecords))
+}
+
+func TestBRManager_PruneBackupRecords_keepPITRBase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	records := dailyBackupRecords(now, 5)
+	for _, record := range records {
+		record.BackupType = string(constants.BackupTypeFull)
+		record.BackupTso = timeToTSO(record.StartTime)
+	}
+	brRW := mockbr.NewMockReaderWriter(ctrl)
+	models.SetBRReaderWriter(brRW)
+	brRW.EXPECT().GetBackupStrategy(gomock.Any(), gomock.Any()).Return(&backuprestore.BackupStrategy{
+		Entity:   common.Entity{ID: "strategy-id"},
+		KeepLast: 1,
+	}, nil)
+	brRW.EXPECT().QueryBackupRecords(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), 1, gomock.Any()).Return(records, int64(5), nil)
+	brRW.EXPECT().QueryBackupRecords(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), 2, gomock.Any()).Return(nil, int64(5), nil)
+	// logs are kept since two and a half days ago, backup-2 is the restore base
+	brRW.EXPECT().GetLogBackupTask(gomock.Any(), gomock.Any()).Return(&backuprestore.LogBackupTask{
+		Entity:       common.Entity{ID: "task-id", Status: string(constants.LogBackupRunning)},
+		StartTS:      timeToTSO(now.AddDate(0, 0, -4)),
+		TruncatedTS:  timeToTSO(now.Add(-60 * time.Hour)),
+		CheckpointTS: timeToTSO(now),
+	}, nil)
+
+	mgr := &BRManager{}
+	resp, err := mgr.PruneBackupRecords(context.TODO(), cluster.PruneBackupRecordsReq{ClusterID: "cls-xxxx", DryRun: true})
+	assert.NoError(t, err)
+	pruned := make([]string, 0)
+	for _, record := range resp.PrunedRecords {
+		pruned = append(pruned, record.ID)
+	}
+	assert.Equal(t, []string{"backup-1", "backup-3", "backup-4"}, pruned)
+}
+
+func TestBRManager_PruneBackupRecords_failed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	records := dailyBackupRecords(now, 3)
+	for _, record := range records {
+		record.StorageType = string(constants.StorageTypeNFS)
+		record.FilePath = filepath.Join(t.TempDir(), record.ID)
+	}
+	// files of backup-2 are failed to be removed, it is kept to prune again
+	records[2].StorageType = string(constants.StorageTypeS3)
+	records[2].FilePath = "bucket/backup-2"
+	// backup-3 failed to be pruned by the last run
+	records = append(records, &backuprestore.BackupRecord{
+		Entity:      common.Entity{ID: "backup-3", Status: string(constants.ClusterBackupPruneFailed)},
+		StorageType: string(constants.StorageTypeNFS),
+		FilePath:    filepath.Join(t.TempDir(), "backup-3"),
+		BackupMode:  string(constants.BackupModeAuto),
+		StartTime:   now.AddDate(0, 0, -3),
+	})
+
+	configRW := mockconfig.NewMockReaderWriter(ctrl)
+	configRW.EXPECT().GetConfig(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("not found")).AnyTimes()
+	models.SetConfigReaderWriter(configRW)
+	brRW := mockbr.NewMockReaderWriter(ctrl)
+	models.SetBRReaderWriter(brRW)
+	brRW.EXPECT().GetBackupStrategy(gomock.Any(), gomock.Any()).Return(&backuprestore.BackupStrategy{
+		Entity:   common.Entity{ID: "strategy-id"},
+		KeepLast: 1,
+	}, nil)
+	brRW.EXPECT().QueryBackupRecords(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), 1, gomock.Any()).Return(records, int64(4), nil)
+	brRW.EXPECT().QueryBackupRecords(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), 2, gomock.Any()).Return(nil, int64(4), nil)
+	brRW.EXPECT().GetLogBackupTask(gomock.Any(), gomock.Any()).Return(&backuprestore.LogBackupTask{}, nil)
+	brRW.EXPECT().DeleteBackupRecord(gomock.Any(), "backup-3").Return(nil).Times(1)
+	brRW.EXPECT().DeleteBackupRecord(gomock.Any(), "backup-1").Return(nil).Times(1)
+	brRW.EXPECT().UpdateBackupRecord(gomock.Any(), "backup-2", string(constants.ClusterBackupPruneFailed), uint64(0), uint64(0), time.Time{}).Return(nil).Times(1)
+	brRW.EXPECT().DeleteBackupRecord(gomock.Any(), "backup-2").Times(0)
+
+	mgr := &BRManager{}
+	resp, err := mgr.PruneBackupRecords(context.TODO(), cluster.PruneBackupRecordsReq{ClusterID: "cls-xxxx"})
+	assert.NoError(t, err)
+	pruned := make([]string, 0)
+	for _, record := range resp.PrunedRecords {
+		pruned = append(pruned, record.ID)
+	}
+	assert.ElementsMatch(t, []string{"backup-1", "backup-3"}, pruned)
+	assert.Equal(t, 1, len(resp.FailedRecords))
+	assert.Equal(t, "backup-2", resp.FailedRecords[0].ID)
+	assert.Equal(t, string(constants.ClusterBackupPruneFailed), resp.FailedRecords[0].Status)
+}
+
+func Test_selectPITRBaseBackupRecord(t *testing.T) {
+	records := []*backuprestore.BackupRecord{
+		{Entity: common.Entity{ID: "full-1", Status: string(constants.ClusterBackupFinished)}, BackupType: string(constants.BackupTypeFull), BackupTso: 100},
+		{Entity: common.Entity{ID: "full-2", Status: string(constants.ClusterBackupFinished)}, BackupType: string(constants.BackupTypeFull), BackupTso: 200},
+		{Entity: common.Entity{ID: "failed", Status: string(constants.ClusterBackupFailed)}, BackupType: string(constants.BackupTypeFull), BackupTso: 150},
+	}
+	assert.Nil(t, selectPITRBaseBackupRecord(records, &backuprestore.LogBackupTask{}))
+	assert.Equal(t, "full-1", selectPITRBaseBackupRecord(records, &backuprestore.LogBackupTask{Entity: common.Entity{ID: "task"}, StartTS: 50}).ID)
+	assert.Equal(t, "full-2", selectPITRBaseBackupRecord(records, &backuprestore.LogBackupTask{Entity: common.Entity{ID: "task"}, StartTS: 50, TruncatedTS: 120}).ID)
+	assert.Nil(t, selectPITRBaseBackupRecord(records, &backuprestore.LogBackupTask{Entity: common.Entity{ID: "task"}, StartTS: 300}))
+}
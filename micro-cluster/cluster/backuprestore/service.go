@@ -33,7 +33,7 @@ type BRService interface {
 	BackupCluster(ctx context.Context, request cluster.BackupClusterDataReq, maintenanceStatusChange bool) (resp cluster.BackupClusterDataResp, backupErr error)
 
 	// RestoreExistCluster
-	// @Description: restore exist cluster by backup record
+	// @Description: restore exist cluster by backup record, or to a point in time by full backup and logs
 	// @Receiver m
 	// @Parameter ctx
 	// @Parameter request
@@ -70,6 +70,60 @@ type BRService interface {
 	// @Return error
 	DeleteBackupRecords(ctx context.Context, request cluster.DeleteBackupDataReq) (resp cluster.DeleteBackupDataResp, err error)
 
+	// PruneBackupRecords
+	// @Description: prune expired auto backups by retention rules of backup strategy
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter request
+	// @Return cluster.PruneBackupRecordsResp
+	// @Return error
+	PruneBackupRecords(ctx context.Context, request cluster.PruneBackupRecordsReq) (resp cluster.PruneBackupRecordsResp, err error)
+
+	// StartLogBackup
+	// @Description: start continuous log backup of cluster, which is required by point-in-time recovery
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter request
+	// @Return cluster.StartLogBackupResp
+	// @Return error
+	StartLogBackup(ctx context.Context, request cluster.StartLogBackupReq) (resp cluster.StartLogBackupResp, err error)
+
+	// StopLogBackup
+	// @Description: stop continuous log backup of cluster, backed up logs are still kept
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter request
+	// @Return cluster.StopLogBackupResp
+	// @Return error
+	StopLogBackup(ctx context.Context, request cluster.StopLogBackupReq) (resp cluster.StopLogBackupResp, err error)
+
+	// QueryLogBackup
+	// @Description: query log backup task and restorable time range of cluster
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter request
+	// @Return cluster.QueryLogBackupResp
+	// @Return error
+	QueryLogBackup(ctx context.Context, request cluster.QueryLogBackupReq) (resp cluster.QueryLogBackupResp, err error)
+
+	// TruncateLogBackup
+	// @Description: truncate backed up logs before a point in time
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter request
+	// @Return cluster.TruncateLogBackupResp
+	// @Return error
+	TruncateLogBackup(ctx context.Context, request cluster.TruncateLogBackupReq) (resp cluster.TruncateLogBackupResp, err error)
+
+	// VerifyBackup
+	// @Description: verify integrity of a finished backup, and optionally restore it into a throwaway cluster
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter request
+	// @Return cluster.VerifyBackupResp
+	// @Return error
+	VerifyBackup(ctx context.Context, request cluster.VerifyBackupReq) (resp cluster.VerifyBackupResp, err error)
+
 	// SaveBackupStrategy
 	// @Description: save backup strategy of cluster
 	// @Receiver m
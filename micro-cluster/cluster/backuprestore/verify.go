@@ -0,0 +1,610 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package backuprestore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	backup "github.com/pingcap/kvproto/pkg/brpb"
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/deployment"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/backuprestore"
+	wfModel "github.com/pingcap/tiunimanager/models/workflow"
+	"github.com/pingcap/tiunimanager/util/uuidutil"
+	workflow "github.com/pingcap/tiunimanager/workflow2"
+)
+
+const (
+	backupMetaFileName = "backupmeta"
+
+	testRestoreCheckInterval  = 10 * time.Second
+	testRestoreTimeout        = 24 * time.Hour
+	testRestoreDestroyTimeout = 2 * time.Hour
+
+	// seconds to pull a file of backup from nfs
+	nfsPullTimeout = 600
+	// seconds to checksum all files of a backup on nfs
+	nfsChecksumTimeout = 7200
+)
+
+// nfsChecksumLine output line of the checksum command for nfs, size, sha256 and relative path of a file
+var nfsChecksumLine = regexp.MustCompile(`^(\d+) ([0-9a-f]{64})  \./(.+)$`)
+
+// TestRestoreClusterExecutor create and destroy the temporary cluster to test restore a backup
+type TestRestoreClusterExecutor interface {
+	// CreateTestRestoreCluster restore the backup into a new cluster, return id of the cluster and the workflow
+	CreateTestRestoreCluster(ctx context.Context, request cluster.RestoreNewClusterReq) (clusterID string, workflowID string, err error)
+	// DestroyTestRestoreCluster delete the cluster and free its resources, return id of the workflow
+	DestroyTestRestoreCluster(ctx context.Context, clusterID string) (workflowID string, err error)
+}
+
+var testRestoreClusterExecutor TestRestoreClusterExecutor
+var testRestoreClusterExecutorLock sync.RWMutex
+
+// RegisterTestRestoreClusterExecutor
+// @Description: register executor of the temporary cluster to test restore a backup, it is implemented by cluster management
+// @Parameter executor
+func RegisterTestRestoreClusterExecutor(executor TestRestoreClusterExecutor) {
+	testRestoreClusterExecutorLock.Lock()
+	defer testRestoreClusterExecutorLock.Unlock()
+	testRestoreClusterExecutor = executor
+}
+
+func getTestRestoreClusterExecutor() TestRestoreClusterExecutor {
+	testRestoreClusterExecutorLock.RLock()
+	defer testRestoreClusterExecutorLock.RUnlock()
+	return testRestoreClusterExecutor
+}
+
+// backupStorage read only access to files of a backup
+type backupStorage interface {
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+}
+
+// fileChecksum sha256 and size of a file of backup
+type fileChecksum struct {
+	sha256 []byte
+	size   uint64
+}
+
+// backupChecksummer storage which checksums all files of a backup in one pass where they are stored,
+// instead of being read one by one
+type backupChecksummer interface {
+	ChecksumAll(ctx context.Context) (map[string]fileChecksum, error)
+}
+
+// nfsBackupStorage files of backup on nfs are written by tidb and tikv of the cluster,
+// the nfs is mounted on hosts of the cluster instead of the manager, so they are pulled from a host of the cluster by tiup
+type nfsBackupStorage struct {
+	clusterID string
+	host      string
+	root      string
+}
+
+func (s *nfsBackupStorage) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	// data files may be large, they are pulled to a local file and read as a stream
+	localPath := filepath.Join(os.TempDir(), uuidutil.GenerateID())
+	err := deployment.M.PullFile(ctx, deployment.TiUPComponentTypeCluster, s.clusterID, filepath.Join(s.root, name), localPath,
+		framework.GetTiupHomePathForTidb(), []string{"-N", s.host}, nfsPullTimeout)
+	if err != nil {
+		os.Remove(localPath)
+		return nil, err
+	}
+	file, err := os.Open(localPath)
+	if err != nil {
+		os.Remove(localPath)
+		return nil, err
+	}
+	return &pulledFile{file}, nil
+}
+
+// ChecksumAll
+// @Description: checksum all files under the backup directory by one command on the host
+// @Receiver s
+// @Parameter ctx
+// @return map[string]fileChecksum checksums by path relative to the backup directory
+// @return error
+func (s *nfsBackupStorage) ChecksumAll(ctx context.Context) (map[string]fileChecksum, error) {
+	command := fmt.Sprintf("cd %s && find . -type f -printf '%%s ' -exec sha256sum {} ';'", shellQuote(s.root))
+	output, err := deployment.M.ExecSync(ctx, deployment.TiUPComponentTypeCluster, s.clusterID,
+		framework.GetTiupHomePathForTidb(), []string{"-N", s.host}, command, nfsChecksumTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return parseNFSChecksums(output)
+}
+
+// parseNFSChecksums
+// @Description: parse checksums from output of `tiup cluster exec`, lines other than checksums are ignored
+// @Parameter output
+// @return map[string]fileChecksum
+// @return error
+func parseNFSChecksums(output string) (map[string]fileChecksum, error) {
+	checksums := make(map[string]fileChecksum)
+	for _, line := range strings.Split(output, "\n") {
+		matches := nfsChecksumLine.FindStringSubmatch(strings.TrimSpace(line))
+		if matches == nil {
+			continue
+		}
+		size, err := strconv.ParseUint(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid size of file %s, %s", matches[3], err.Error())
+		}
+		sum, err := hex.DecodeString(matches[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid sha256 of file %s, %s", matches[3], err.Error())
+		}
+		checksums[matches[3]] = fileChecksum{sha256: sum, size: size}
+	}
+	return checksums, nil
+}
+
+// shellQuote quote the string as one word of shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// pulledFile local copy of a file of backup, it is removed when closed
+type pulledFile struct {
+	*os.File
+}
+
+func (f *pulledFile) Close() error {
+	err := f.File.Close()
+	os.Remove(f.Name())
+	return err
+}
+
+type s3BackupStorage struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+func (s *s3BackupStorage) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	object, err := s.client.GetObject(ctx, s.bucket, path.Join(s.prefix, name), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	// GetObject is lazy, make sure the object exists
+	if _, err = object.Stat(); err != nil {
+		object.Close()
+		return nil, err
+	}
+	return object, nil
+}
+
+func newBackupStorage(ctx context.Context, record *backuprestore.BackupRecord) (backupStorage, error) {
+	switch constants.StorageType(record.StorageType) {
+	case constants.StorageTypeNFS:
+		clusterMeta, err := meta.Get(ctx, record.ClusterID)
+		if err != nil {
+			return nil, fmt.Errorf("load cluster meta %s failed, %s", record.ClusterID, err.Error())
+		}
+		tidbServers := clusterMeta.GetClusterConnectAddresses()
+		if len(tidbServers) == 0 {
+			return nil, fmt.Errorf("get tidb servers address from cluster %s meta result empty", record.ClusterID)
+		}
+		return &nfsBackupStorage{clusterID: record.ClusterID, host: tidbServers[0].IP, root: record.FilePath}, nil
+	case constants.StorageTypeS3:
+		s3Addr := strings.SplitN(record.FilePath, "/", 2)
+		if len(s3Addr) != 2 {
+			return nil, fmt.Errorf("invalid s3 backup path %s", record.FilePath)
+		}
+		s3Client, err := newBackupS3Client(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &s3BackupStorage{client: s3Client, bucket: s3Addr[0], prefix: s3Addr[1]}, nil
+	default:
+		return nil, fmt.Errorf("verifying backup on storage %s is not supported", record.StorageType)
+	}
+}
+
+func readBackupFile(ctx context.Context, storage backupStorage, name string) ([]byte, error) {
+	reader, err := storage.Open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
+
+// checksumBackupFile
+// @Description: compute sha256 and size of a backup file by streaming it
+// @Parameter ctx
+// @Parameter storage
+// @Parameter name
+// @return []byte
+// @return uint64
+// @return error
+func checksumBackupFile(ctx context.Context, storage backupStorage, name string) ([]byte, uint64, error) {
+	reader, err := storage.Open(ctx, name)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer reader.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, reader)
+	if err != nil {
+		return nil, 0, err
+	}
+	return hash.Sum(nil), uint64(size), nil
+}
+
+// lookupChecksum checksum of the file in checksums computed in advance, the file is read if they are not computed
+func lookupChecksum(ctx context.Context, storage backupStorage, checksums map[string]fileChecksum, name string) ([]byte, uint64, error) {
+	if checksums == nil {
+		return checksumBackupFile(ctx, storage, name)
+	}
+	checksum, ok := checksums[name]
+	if !ok {
+		return nil, 0, fmt.Errorf("file %s is not found", name)
+	}
+	return checksum.sha256, checksum.size, nil
+}
+
+// collectBackupDataFiles
+// @Description: collect data files recorded in backupmeta, meta files of backupmeta v2 are read and checked recursively
+// @Parameter ctx
+// @Parameter storage
+// @Parameter backupMeta
+// @return []*backup.File
+// @return error
+func collectBackupDataFiles(ctx context.Context, storage backupStorage, backupMeta *backup.BackupMeta) ([]*backup.File, error) {
+	files := make([]*backup.File, 0, len(backupMeta.Files))
+	files = append(files, backupMeta.Files...)
+	if backupMeta.FileIndex == nil {
+		return files, nil
+	}
+
+	pending := []*backup.MetaFile{backupMeta.FileIndex}
+	for len(pending) > 0 {
+		metaFile := pending[0]
+		pending = pending[1:]
+		files = append(files, metaFile.DataFiles...)
+		for _, child := range metaFile.MetaFiles {
+			data, err := readBackupFile(ctx, storage, child.Name)
+			if err != nil {
+				return nil, fmt.Errorf("read meta file %s failed, %s", child.Name, err.Error())
+			}
+			if sum := sha256.Sum256(data); len(child.Sha256) > 0 && !bytes.Equal(sum[:], child.Sha256) {
+				return nil, fmt.Errorf("checksum of meta file %s mismatch", child.Name)
+			}
+			childMeta := &backup.MetaFile{}
+			if err = childMeta.Unmarshal(data); err != nil {
+				return nil, fmt.Errorf("unmarshal meta file %s failed, %s", child.Name, err.Error())
+			}
+			pending = append(pending, childMeta)
+		}
+	}
+	return files, nil
+}
+
+// checkBackupIntegrity
+// @Description: validate backupmeta of the record, then checksum every data file and compare the total size with the record
+// @Parameter ctx
+// @Parameter storage
+// @Parameter record
+// @return error
+func checkBackupIntegrity(ctx context.Context, storage backupStorage, record *backuprestore.BackupRecord) error {
+	data, err := readBackupFile(ctx, storage, backupMetaFileName)
+	if err != nil {
+		return fmt.Errorf("read %s failed, %s", backupMetaFileName, err.Error())
+	}
+	backupMeta := &backup.BackupMeta{}
+	if err = backupMeta.Unmarshal(data); err != nil {
+		return fmt.Errorf("unmarshal %s failed, %s", backupMetaFileName, err.Error())
+	}
+	if record.BackupTso > 0 && backupMeta.EndVersion != record.BackupTso {
+		return fmt.Errorf("backup ts %d in %s is not the same as record %d", backupMeta.EndVersion, backupMetaFileName, record.BackupTso)
+	}
+
+	files, err := collectBackupDataFiles(ctx, storage, backupMeta)
+	if err != nil {
+		return err
+	}
+	var checksums map[string]fileChecksum
+	if checksummer, ok := storage.(backupChecksummer); ok {
+		if checksums, err = checksummer.ChecksumAll(ctx); err != nil {
+			return fmt.Errorf("checksum backup files failed, %s", err.Error())
+		}
+	}
+	var totalSize uint64
+	checked := make(map[string]bool)
+	for _, file := range files {
+		// files of different column families may be listed more than once
+		if checked[file.Name] {
+			continue
+		}
+		checked[file.Name] = true
+
+		sum, size, err := lookupChecksum(ctx, storage, checksums, file.Name)
+		if err != nil {
+			return fmt.Errorf("read data file %s failed, %s", file.Name, err.Error())
+		}
+		if len(file.Sha256) > 0 && !bytes.Equal(sum, file.Sha256) {
+			return fmt.Errorf("checksum of data file %s mismatch", file.Name)
+		}
+		if file.Size_ > 0 && file.Size_ != size {
+			return fmt.Errorf("size of data file %s is %d, expected %d", file.Name, size, file.Size_)
+		}
+		totalSize += size
+	}
+	if record.Size > 0 && totalSize != record.Size {
+		return fmt.Errorf("total size of %d data files is %d, expected %d", len(checked), totalSize, record.Size)
+	}
+	framework.LogWithContext(ctx).Infof("backup %s has %d data files, total size %d", record.ID, len(checked), totalSize)
+	return nil
+}
+
+func (mgr *BRManager) VerifyBackup(ctx context.Context, request cluster.VerifyBackupReq) (resp cluster.VerifyBackupResp, verifyErr error) {
+	framework.LogWithContext(ctx).Infof("Begin VerifyBackup, request: %+v", request)
+	defer framework.LogWithContext(ctx).Infof("End VerifyBackup")
+
+	brRW := models.GetBRReaderWriter()
+	record, err := brRW.GetBackupRecord(ctx, request.BackupID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("get backup record %s failed, %s", request.BackupID, err.Error())
+		return resp, errors.WrapError(errors.TIUNIMANAGER_BACKUP_RECORD_QUERY_FAILED, fmt.Sprintf("get backup record %s failed, %s", request.BackupID, err.Error()), err)
+	}
+	if string(constants.ClusterBackupFinished) != record.Status {
+		return resp, errors.NewErrorf(errors.TIUNIMANAGER_BACKUP_RECORD_INVALID, "backup record %s status %s is not finished", record.ID, record.Status)
+	}
+	if string(constants.BackupVerifyProcessing) == record.VerifyStatus {
+		return resp, errors.NewErrorf(errors.TIUNIMANAGER_BACKUP_VERIFY_FAILED, "backup record %s is being verified", record.ID)
+	}
+	if request.TestRestoreCluster != nil && getTestRestoreClusterExecutor() == nil {
+		return resp, errors.NewError(errors.TIUNIMANAGER_BACKUP_VERIFY_FAILED, "test restore cluster is not supported")
+	}
+
+	if err = brRW.UpdateBackupVerification(ctx, record.ID, string(constants.BackupVerifyProcessing), "", time.Now()); err != nil {
+		framework.LogWithContext(ctx).Errorf("update verify status of backup record %s failed, %s", record.ID, err.Error())
+		return resp, errors.WrapError(errors.TIUNIMANAGER_BACKUP_VERIFY_FAILED, fmt.Sprintf("update verify status of backup record %s failed, %s", record.ID, err.Error()), err)
+	}
+	defer func() {
+		if verifyErr != nil {
+			if updateErr := brRW.UpdateBackupVerification(ctx, record.ID, record.VerifyStatus, record.VerifyMessage, record.VerifyTime); updateErr != nil {
+				framework.LogWithContext(ctx).Warnf("reset verify status of backup record %s failed, %s", record.ID, updateErr.Error())
+			}
+		}
+	}()
+
+	flowManager := workflow.GetWorkFlowService()
+	flowId, err := flowManager.CreateWorkFlow(ctx, record.ClusterID, workflow.BizTypeCluster, constants.FlowVerifyBackup)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("create %s workflow failed, %s", constants.FlowVerifyBackup, err.Error())
+		return resp, errors.WrapError(errors.TIUNIMANAGER_WORKFLOW_CREATE_FAILED, fmt.Sprintf("create %s workflow failed, %s", constants.FlowVerifyBackup, err.Error()), err)
+	}
+
+	flowManager.InitContext(ctx, flowId, contextBackupRecordKey, record)
+	flowManager.InitContext(ctx, flowId, contextTestRestoreParameterKey, request.TestRestoreCluster)
+	flowManager.InitContext(ctx, flowId, contextTestRestoreClusterKey, "")
+	if err = flowManager.Start(ctx, flowId); err != nil {
+		framework.LogWithContext(ctx).Errorf("async start %s workflow failed, %s", constants.FlowVerifyBackup, err.Error())
+		return resp, errors.WrapError(errors.TIUNIMANAGER_WORKFLOW_START_FAILED, fmt.Sprintf("async start %s workflow failed, %s", constants.FlowVerifyBackup, err.Error()), err)
+	}
+
+	resp.WorkFlowID = flowId
+	return resp, nil
+}
+
+func verifyBackupFiles(node *wfModel.WorkFlowNode, ctx *workflow.FlowContext) error {
+	framework.LogWithContext(ctx).Info("begin verifyBackupFiles")
+	defer framework.LogWithContext(ctx).Info("end verifyBackupFiles")
+
+	var record backuprestore.BackupRecord
+	if err := ctx.GetData(contextBackupRecordKey, &record); err != nil {
+		return err
+	}
+
+	storage, err := newBackupStorage(ctx, &record)
+	if err != nil {
+		return recordVerifyFailure(ctx, err)
+	}
+	if err = checkBackupIntegrity(ctx, storage, &record); err != nil {
+		framework.LogWithContext(ctx).Errorf("check integrity of backup %s failed, %s", record.ID, err.Error())
+		return recordVerifyFailure(ctx, err)
+	}
+
+	node.Record(fmt.Sprintf("backup files of %s are complete", record.FilePath))
+	return nil
+}
+
+func testRestoreBackup(node *wfModel.WorkFlowNode, ctx *workflow.FlowContext) error {
+	framework.LogWithContext(ctx).Info("begin testRestoreBackup")
+	defer framework.LogWithContext(ctx).Info("end testRestoreBackup")
+
+	var record backuprestore.BackupRecord
+	var parameter *cluster.TestRestoreClusterParameter
+	if err := ctx.GetData(contextBackupRecordKey, &record); err != nil {
+		return err
+	}
+	if err := ctx.GetData(contextTestRestoreParameterKey, &parameter); err != nil {
+		return err
+	}
+	if parameter == nil {
+		node.Record("skip test restore")
+		return nil
+	}
+	executor := getTestRestoreClusterExecutor()
+	if executor == nil {
+		return recordVerifyFailure(ctx, fmt.Errorf("test restore cluster is not supported"))
+	}
+
+	createParameter := parameter.CreateClusterParameter
+	// the temporary cluster is destroyed as soon as the verification finishes
+	createParameter.DeletionProtection = false
+	createParameter.MaintainWindow = ""
+	testClusterID, restoreFlowID, err := executor.CreateTestRestoreCluster(ctx, cluster.RestoreNewClusterReq{
+		CreateClusterParameter: createParameter,
+		BackupID:               record.ID,
+		ResourceParameter:      parameter.ResourceParameter,
+	})
+	if testClusterID != "" {
+		if setErr := ctx.SetData(contextTestRestoreClusterKey, testClusterID); setErr != nil {
+			return setErr
+		}
+	}
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("create cluster to test restore backup %s failed, %s", record.ID, err.Error())
+		return recordVerifyFailure(ctx, fmt.Errorf("create cluster to test restore failed, %s", err.Error()))
+	}
+	if err = ctx.SetData(contextTestRestoreFlowKey, restoreFlowID); err != nil {
+		return err
+	}
+	if err = meta.WaitWorkflow(ctx, restoreFlowID, testRestoreCheckInterval, testRestoreTimeout); err != nil {
+		framework.LogWithContext(ctx).Errorf("wait test restore workflow %s failed, %s", restoreFlowID, err.Error())
+		return recordVerifyFailure(ctx, fmt.Errorf("test restore workflow %s failed, %s", restoreFlowID, err.Error()))
+	}
+
+	node.Record(fmt.Sprintf("test restore backup %s into temporary cluster %s ", record.ID, testClusterID))
+	return nil
+}
+
+// destroyTestRestoreCluster
+// @Description: destroy the temporary cluster of test restore and wait until it is done
+// @Parameter node
+// @Parameter ctx
+// @return error
+func destroyTestRestoreCluster(node *wfModel.WorkFlowNode, ctx *workflow.FlowContext) error {
+	framework.LogWithContext(ctx).Info("begin destroyTestRestoreCluster")
+	defer framework.LogWithContext(ctx).Info("end destroyTestRestoreCluster")
+
+	flowID, err := startDestroyTestRestoreCluster(ctx)
+	if err != nil {
+		return recordVerifyFailure(ctx, err)
+	}
+	if flowID == "" {
+		return nil
+	}
+	if err = meta.WaitWorkflow(ctx, flowID, testRestoreCheckInterval, testRestoreDestroyTimeout); err != nil {
+		framework.LogWithContext(ctx).Errorf("wait destroying test restore cluster workflow %s failed, %s", flowID, err.Error())
+		return recordVerifyFailure(ctx, fmt.Errorf("destroy test restore cluster workflow %s failed, %s", flowID, err.Error()))
+	}
+	node.Record("temporary cluster of test restore is destroyed")
+	return nil
+}
+
+// startDestroyTestRestoreCluster start to destroy the temporary cluster of test restore if it has been created, return id of the workflow
+func startDestroyTestRestoreCluster(ctx *workflow.FlowContext) (string, error) {
+	var testClusterID string
+	if err := ctx.GetData(contextTestRestoreClusterKey, &testClusterID); err != nil {
+		return "", err
+	}
+	if testClusterID == "" {
+		return "", nil
+	}
+	executor := getTestRestoreClusterExecutor()
+	if executor == nil {
+		return "", fmt.Errorf("test restore cluster is not supported")
+	}
+	flowID, err := executor.DestroyTestRestoreCluster(ctx, testClusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("destroy test restore cluster %s failed, %s", testClusterID, err.Error())
+		return "", fmt.Errorf("destroy test restore cluster %s failed, %s", testClusterID, err.Error())
+	}
+	// destroyed only once, even if the verification fails later
+	if err = ctx.SetData(contextTestRestoreClusterKey, ""); err != nil {
+		return "", err
+	}
+	return flowID, nil
+}
+
+func verifyPassed(node *wfModel.WorkFlowNode, ctx *workflow.FlowContext) error {
+	framework.LogWithContext(ctx).Info("begin verifyPassed")
+	defer framework.LogWithContext(ctx).Info("end verifyPassed")
+
+	var record backuprestore.BackupRecord
+	var parameter *cluster.TestRestoreClusterParameter
+	if err := ctx.GetData(contextBackupRecordKey, &record); err != nil {
+		return err
+	}
+	if err := ctx.GetData(contextTestRestoreParameterKey, &parameter); err != nil {
+		return err
+	}
+
+	message := "backup files are complete"
+	if parameter != nil {
+		message = fmt.Sprintf("%s, restored into a temporary cluster", message)
+	}
+	err := models.GetBRReaderWriter().UpdateBackupVerification(ctx, record.ID, string(constants.BackupVerifyPassed), message, time.Now())
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("update verify status of backup record %s failed, %s", record.ID, err.Error())
+		return err
+	}
+	return nil
+}
+
+func verifyFail(node *wfModel.WorkFlowNode, ctx *workflow.FlowContext) error {
+	framework.LogWithContext(ctx).Info("begin verifyFail")
+	defer framework.LogWithContext(ctx).Info("end verifyFail")
+
+	var record backuprestore.BackupRecord
+	var message string
+	if err := ctx.GetData(contextBackupRecordKey, &record); err != nil {
+		return err
+	}
+	if err := ctx.GetData(contextVerifyFailureKey, &message); err != nil {
+		return err
+	}
+	if message == "" {
+		message = "verify backup failed"
+	}
+
+	// the temporary cluster is never kept, even if the test restore fails
+	if flowID, err := startDestroyTestRestoreCluster(ctx); err != nil {
+		message = fmt.Sprintf("%s, %s", message, err.Error())
+	} else if flowID != "" {
+		node.Record(fmt.Sprintf("destroying temporary cluster of test restore by workflow %s", flowID))
+	}
+
+	err := models.GetBRReaderWriter().UpdateBackupVerification(ctx, record.ID, string(constants.BackupVerifyFailed), message, time.Now())
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("update verify status of backup record %s failed, %s", record.ID, err.Error())
+		return err
+	}
+	return nil
+}
+
+// recordVerifyFailure keep the reason in flow context, it will be saved on the backup record by the fail node
+func recordVerifyFailure(ctx *workflow.FlowContext, cause error) error {
+	if err := ctx.SetData(contextVerifyFailureKey, cause.Error()); err != nil {
+		framework.LogWithContext(ctx).Warnf("save verify failure %s failed, %s", cause.Error(), err.Error())
+	}
+	return cause
+}
@@ -0,0 +1,174 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package backuprestore
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	backup "github.com/pingcap/kvproto/pkg/brpb"
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/deployment"
+	"github.com/pingcap/tiunimanager/models/cluster/backuprestore"
+	mock_deployment "github.com/pingcap/tiunimanager/test/mockdeployment"
+	"github.com/stretchr/testify/assert"
+)
+
+// localBackupStorage files of backup in a local directory
+type localBackupStorage struct {
+	root string
+}
+
+func (s *localBackupStorage) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(s.root, name))
+}
+
+// writeBackupFiles write data files and backupmeta like BR does, return the total size of data files
+func writeBackupFiles(t *testing.T, dir string, endVersion uint64, contents map[string]string) uint64 {
+	backupMeta := &backup.BackupMeta{EndVersion: endVersion}
+	var total uint64
+	for name, content := range contents {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+		sum := sha256.Sum256([]byte(content))
+		backupMeta.Files = append(backupMeta.Files, &backup.File{Name: name, Sha256: sum[:], Size_: uint64(len(content))})
+		total += uint64(len(content))
+	}
+	data, err := backupMeta.Marshal()
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, backupMetaFileName), data, 0644))
+	return total
+}
+
+func Test_checkBackupIntegrity(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backup-verify")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	size := writeBackupFiles(t, dir, 100, map[string]string{
+		"1_write.sst":   "write cf data",
+		"1_default.sst": "default cf data",
+	})
+	storage := &localBackupStorage{root: dir}
+	record := &backuprestore.BackupRecord{FilePath: dir, Size: size, BackupTso: 100}
+
+	t.Run("normal", func(t *testing.T) {
+		assert.NoError(t, checkBackupIntegrity(context.TODO(), storage, record))
+	})
+	t.Run("tso mismatch", func(t *testing.T) {
+		assert.Error(t, checkBackupIntegrity(context.TODO(), storage, &backuprestore.BackupRecord{Size: size, BackupTso: 101}))
+	})
+	t.Run("size mismatch", func(t *testing.T) {
+		assert.Error(t, checkBackupIntegrity(context.TODO(), storage, &backuprestore.BackupRecord{Size: size + 1, BackupTso: 100}))
+	})
+	t.Run("corrupted", func(t *testing.T) {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "1_write.sst"), []byte("write cf dat!"), 0644))
+		assert.Error(t, checkBackupIntegrity(context.TODO(), storage, record))
+	})
+	t.Run("missing", func(t *testing.T) {
+		assert.NoError(t, os.Remove(filepath.Join(dir, "1_default.sst")))
+		assert.Error(t, checkBackupIntegrity(context.TODO(), storage, record))
+	})
+	t.Run("no backupmeta", func(t *testing.T) {
+		assert.Error(t, checkBackupIntegrity(context.TODO(), &localBackupStorage{root: filepath.Join(dir, "none")}, record))
+	})
+}
+
+func Test_nfsBackupStorage_Open(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDeployment := mock_deployment.NewMockInterface(ctrl)
+	deployment.M = mockDeployment
+
+	storage := &nfsBackupStorage{clusterID: "cluster01", host: "127.0.0.1", root: "/nfs/backup/cluster01"}
+	t.Run("normal", func(t *testing.T) {
+		var pulled string
+		mockDeployment.EXPECT().PullFile(gomock.Any(), deployment.TiUPComponentTypeCluster, "cluster01", "/nfs/backup/cluster01/backupmeta",
+			gomock.Any(), gomock.Any(), []string{"-N", "127.0.0.1"}, nfsPullTimeout).
+			DoAndReturn(func(ctx context.Context, componentType deployment.TiUPComponentType, clusterID, remotePath, localPath, home string, args []string, timeout int) error {
+				pulled = localPath
+				return ioutil.WriteFile(localPath, []byte("content"), 0644)
+			})
+		data, err := readBackupFile(context.TODO(), storage, backupMetaFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, "content", string(data))
+		// the local copy is removed after reading
+		_, err = os.Stat(pulled)
+		assert.True(t, os.IsNotExist(err))
+	})
+	t.Run("pull failed", func(t *testing.T) {
+		mockDeployment.EXPECT().PullFile(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(fmt.Errorf("no such file"))
+		_, err := readBackupFile(context.TODO(), storage, backupMetaFileName)
+		assert.Error(t, err)
+	})
+}
+
+func Test_nfsBackupStorage_ChecksumAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockDeployment := mock_deployment.NewMockInterface(ctrl)
+	deployment.M = mockDeployment
+
+	storage := &nfsBackupStorage{clusterID: "cluster01", host: "127.0.0.1", root: "/nfs/backup/cluster01"}
+	writeSum := sha256.Sum256([]byte("write cf data"))
+	t.Run("normal", func(t *testing.T) {
+		output := fmt.Sprintf("Run command on 127.0.0.1(sudo:false): cd ...\nOutputs of cd ... on 127.0.0.1:\nstdout:\n13 %x  ./1_write.sst\n\n", writeSum)
+		mockDeployment.EXPECT().ExecSync(gomock.Any(), deployment.TiUPComponentTypeCluster, "cluster01", gomock.Any(),
+			[]string{"-N", "127.0.0.1"}, "cd '/nfs/backup/cluster01' && find . -type f -printf '%s ' -exec sha256sum {} ';'", nfsChecksumTimeout).
+			Return(output, nil)
+		checksums, err := storage.ChecksumAll(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]fileChecksum{"1_write.sst": {sha256: writeSum[:], size: 13}}, checksums)
+	})
+	t.Run("exec failed", func(t *testing.T) {
+		mockDeployment.EXPECT().ExecSync(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("", fmt.Errorf("host is unreachable"))
+		_, err := storage.ChecksumAll(context.TODO())
+		assert.Error(t, err)
+	})
+}
+
+func Test_newBackupStorage_unsupported(t *testing.T) {
+	_, err := newBackupStorage(context.TODO(), &backuprestore.BackupRecord{StorageType: string(constants.StorageTypeLocal), FilePath: "/tmp/backup"})
+	assert.Error(t, err)
+}
+
+func Test_parseS3Endpoint(t *testing.T) {
+	host, secure, err := parseS3Endpoint("https://s3.example.com:9000")
+	assert.NoError(t, err)
+	assert.Equal(t, "s3.example.com:9000", host)
+	assert.True(t, secure)
+
+	host, secure, err = parseS3Endpoint("http://127.0.0.1:9000/")
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:9000", host)
+	assert.False(t, secure)
+
+	host, secure, err = parseS3Endpoint("127.0.0.1:9000")
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:9000", host)
+	assert.False(t, secure)
+
+	_, _, err = parseS3Endpoint("ftp://127.0.0.1:9000")
+	assert.Error(t, err)
+}
@@ -0,0 +1,82 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package health
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/health"
+)
+
+const (
+	monitorJobSpec = "*/30 * * * * *" // every 30 seconds
+	cleanJobSpec   = "0 30 0 * * *"   // every day 00:30:00
+)
+
+// monitoredClusterStatus clusters in these status are probed, stopped and initializing clusters are ignored
+var monitoredClusterStatus = []constants.ClusterRunningStatus{
+	constants.ClusterRunning,
+	constants.ClusterRecovering,
+	constants.ClusterFailure,
+}
+
+// monitoredInstanceStatus instances in these status are probed
+var monitoredInstanceStatus = map[constants.ClusterInstanceRunningStatus]bool{
+	constants.ClusterInstanceRunning:    true,
+	constants.ClusterInstanceRecovering: true,
+	constants.ClusterInstanceFailure:    true,
+}
+
+// statusSeverity a status change to a higher severity only happens after consecutive unhealthy probes,
+// clusters and instances share the same names of status
+var statusSeverity = map[string]int{
+	string(constants.ClusterRunning):    0,
+	string(constants.ClusterRecovering): 1,
+	string(constants.ClusterFailure):    2,
+}
+
+// getEventRetentionDays days to keep health events, it is configured by system config
+func getEventRetentionDays(ctx context.Context) int {
+	config, err := models.GetConfigReaderWriter().GetConfig(ctx, constants.ConfigKeyHealthEventRetentionDays)
+	if err != nil || config.ConfigValue == "" {
+		framework.LogWithContext(ctx).Warnf("get config %s failed, use default %d days", constants.ConfigKeyHealthEventRetentionDays, constants.DefaultHealthEventRetentionDays)
+		return constants.DefaultHealthEventRetentionDays
+	}
+	days, err := strconv.Atoi(config.ConfigValue)
+	if err != nil || days <= 0 {
+		framework.LogWithContext(ctx).Warnf("invalid config %s = %s, use default %d days", constants.ConfigKeyHealthEventRetentionDays, config.ConfigValue, constants.DefaultHealthEventRetentionDays)
+		return constants.DefaultHealthEventRetentionDays
+	}
+	return days
+}
+
+func convertEventInfo(event *health.HealthEvent) structs.ClusterHealthEventInfo {
+	return structs.ClusterHealthEventInfo{
+		ID:             event.ID,
+		ClusterID:      event.ClusterID,
+		InstanceID:     event.InstanceID,
+		InstanceType:   event.InstanceType,
+		PreviousStatus: event.PreviousStatus,
+		Status:         event.Status,
+		Reason:         event.Reason,
+		CreateTime:     event.CreatedAt,
+	}
+}
@@ -0,0 +1,65 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package health
+
+import (
+	"os"
+	"testing"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	"github.com/pingcap/tiunimanager/models/common"
+)
+
+var mockManager = NewManager()
+
+func TestMain(m *testing.M) {
+	var testFilePath string
+	framework.InitBaseFrameworkForUt(framework.ClusterService,
+		func(d *framework.BaseFramework) error {
+			testFilePath = d.GetDataDir()
+			os.MkdirAll(testFilePath, 0755)
+			models.MockDB()
+			return models.Open(d)
+		},
+	)
+	code := m.Run()
+	os.RemoveAll(testFilePath)
+
+	os.Exit(code)
+}
+
+// mockInstances pd, tikv and tidb on 127.0.0.1, tiflash on 127.0.0.2
+func mockInstances(clusterID string) []*management.ClusterInstance {
+	newInstance := func(id string, componentType constants.EMProductComponentIDType, ip string, ports []int32) *management.ClusterInstance {
+		return &management.ClusterInstance{
+			Entity:    common.Entity{ID: id, TenantId: "1", Status: string(constants.ClusterInstanceRunning)},
+			Type:      string(componentType),
+			Version:   "v5.2.2",
+			ClusterID: clusterID,
+			HostIP:    []string{ip},
+			Ports:     ports,
+		}
+	}
+	return []*management.ClusterInstance{
+		newInstance("pd1", constants.ComponentIDPD, "127.0.0.1", []int32{2379, 2380}),
+		newInstance("tikv1", constants.ComponentIDTiKV, "127.0.0.1", []int32{20160, 20180}),
+		newInstance("tidb1", constants.ComponentIDTiDB, "127.0.0.1", []int32{4000, 10080}),
+		newInstance("tiflash1", constants.ComponentIDTiFlash, "127.0.0.2", []int32{9000, 8123, 3930, 20170, 20292, 8234}),
+	}
+}
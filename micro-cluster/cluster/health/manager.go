@@ -0,0 +1,62 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package health
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/models"
+)
+
+type Manager struct{}
+
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// QueryClusterHealthEvents
+// @Description: query status changes of cluster and its instances found by health monitor
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return total
+// @return err
+func (m *Manager) QueryClusterHealthEvents(ctx context.Context, req cluster.QueryClusterHealthEventsReq) (resp cluster.QueryClusterHealthEventsResp, total int, err error) {
+	var startTime, endTime time.Time
+	if req.StartTime > 0 {
+		startTime = time.Unix(req.StartTime, 0)
+	}
+	if req.EndTime > 0 {
+		endTime = time.Unix(req.EndTime, 0)
+	}
+	events, count, err := models.GetHealthReaderWriter().QueryEvents(ctx, req.ClusterID, req.InstanceID,
+		startTime, endTime, req.GetOffset(), req.PageSize)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query health events of cluster %s failed, err = %s", req.ClusterID, err.Error())
+		return
+	}
+	resp.Events = make([]structs.ClusterHealthEventInfo, 0, len(events))
+	for _, event := range events {
+		resp.Events = append(resp.Events, convertEventInfo(event))
+	}
+	total = int(count)
+	return
+}
@@ -0,0 +1,75 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/health"
+	"github.com/pingcap/tiunimanager/models/common"
+	"github.com/pingcap/tiunimanager/test/mockmodels/mockhealth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_QueryClusterHealthEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	healthRW := mockhealth.NewMockReaderWriter(ctrl)
+	models.SetHealthReaderWriter(healthRW)
+
+	t.Run("normal", func(t *testing.T) {
+		healthRW.EXPECT().QueryEvents(gomock.Any(), "cluster1", "tidb1", time.Unix(1630468800, 0), time.Time{}, 10, 10).
+			Return([]*health.HealthEvent{
+				{
+					Entity:         common.Entity{ID: "event1", Status: string(constants.ClusterInstanceFailure)},
+					ClusterID:      "cluster1",
+					InstanceID:     "tidb1",
+					InstanceType:   string(constants.ComponentIDTiDB),
+					PreviousStatus: string(constants.ClusterInstanceRunning),
+					Reason:         "status of tidb 127.0.0.1:10080 is unreachable",
+				},
+			}, int64(11), nil)
+
+		resp, total, err := mockManager.QueryClusterHealthEvents(context.TODO(), cluster.QueryClusterHealthEventsReq{
+			ClusterID:   "cluster1",
+			InstanceID:  "tidb1",
+			StartTime:   1630468800,
+			PageRequest: structs.PageRequest{Page: 2, PageSize: 10},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 11, total)
+		assert.Len(t, resp.Events, 1)
+		assert.Equal(t, "event1", resp.Events[0].ID)
+		assert.Equal(t, string(constants.ClusterInstanceFailure), resp.Events[0].Status)
+		assert.Equal(t, string(constants.ClusterInstanceRunning), resp.Events[0].PreviousStatus)
+	})
+	t.Run("failed", func(t *testing.T) {
+		healthRW.EXPECT().QueryEvents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, int64(0), errors.Error(errors.TIUNIMANAGER_PARAMETER_INVALID))
+
+		_, _, err := mockManager.QueryClusterHealthEvents(context.TODO(), cluster.QueryClusterHealthEventsReq{})
+		assert.Error(t, err)
+	})
+}
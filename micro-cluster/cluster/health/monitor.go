@@ -0,0 +1,206 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package health
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/health"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	dbCommon "github.com/pingcap/tiunimanager/models/common"
+	"github.com/robfig/cron"
+)
+
+var monitorOnce sync.Once
+
+type monitorHandler struct {
+	// 1 if a round of probes is running, a round is skipped if the previous one has not finished
+	running int32
+	// cluster or instance id -> count of consecutive unhealthy probes
+	failures map[string]int
+	// ids of clusters and instances probed in the current round
+	probed map[string]bool
+}
+
+type cleanHandler struct{}
+
+func newMonitorHandler() *monitorHandler {
+	return &monitorHandler{
+		failures: make(map[string]int),
+		probed:   make(map[string]bool),
+	}
+}
+
+// StartHealthMonitor
+// @Description: start the scheduler which probes health of clusters every 30 seconds to maintain status of clusters and instances,
+// and removes expired health events every day
+func StartHealthMonitor() {
+	monitorOnce.Do(func() {
+		jobCron := cron.New()
+		if err := jobCron.AddJob(monitorJobSpec, newMonitorHandler()); err != nil {
+			framework.Log().Fatalf("add health monitor cron job failed, %s", err.Error())
+			return
+		}
+		if err := jobCron.AddJob(cleanJobSpec, &cleanHandler{}); err != nil {
+			framework.Log().Fatalf("add health event clean cron job failed, %s", err.Error())
+			return
+		}
+		go func() {
+			time.Sleep(5 * time.Second) //wait db client ready
+			jobCron.Start()
+		}()
+	})
+}
+
+func (h *monitorHandler) Run() {
+	if !atomic.CompareAndSwapInt32(&h.running, 0, 1) {
+		framework.Log().Warnf("previous round of health probes has not finished, skip")
+		return
+	}
+	defer atomic.StoreInt32(&h.running, 0)
+
+	ctx := context.TODO()
+	results, _, err := models.GetClusterReaderWriter().QueryMetas(ctx, management.Filters{
+		StatusFilters: monitoredClusterStatus,
+	}, structs.PageRequest{Page: 1, PageSize: math.MaxInt32})
+	if err != nil {
+		framework.Log().Errorf("query clusters failed, %s", err.Error())
+		return
+	}
+
+	h.probed = make(map[string]bool)
+	for _, result := range results {
+		// status of cluster is maintained by workflow during maintenance
+		if result.Cluster.MaintenanceStatus != constants.ClusterMaintenanceNone {
+			continue
+		}
+		if err = h.checkCluster(ctx, result.Cluster, result.Instances); err != nil {
+			framework.Log().Errorf("check health of cluster %s failed, %s", result.Cluster.ID, err.Error())
+		}
+	}
+	for id := range h.failures {
+		if !h.probed[id] {
+			delete(h.failures, id)
+		}
+	}
+}
+
+// checkCluster
+// @Description: probe the cluster, then update status of the cluster and its instances and record the changes as health events
+// @Parameter ctx
+// @Parameter clusterInfo
+// @Parameter instances
+// @return error
+func (h *monitorHandler) checkCluster(ctx context.Context, clusterInfo *management.Cluster, instances []*management.ClusterInstance) error {
+	report := probeCluster(ctx, instances)
+	rw := models.GetClusterReaderWriter()
+	events := make([]*health.HealthEvent, 0)
+
+	for _, instance := range instances {
+		result, ok := report.instances[instance.ID]
+		if !ok {
+			continue
+		}
+		target, reason := constants.ClusterInstanceRunning, "instance is healthy"
+		if !result.healthy {
+			target, reason = constants.ClusterInstanceFailure, result.reason
+		}
+		if !h.shouldChange(instance.ID, instance.Status, string(target)) {
+			continue
+		}
+		// status is updated by only one of replicas, and is kept if the cluster is under maintenance since it is queried
+		updated, err := rw.CompareAndUpdateInstanceStatus(ctx, instance.ID, instance.Status, target)
+		if err != nil {
+			framework.LogWithContext(ctx).Errorf("update status of instance %s failed, %s", instance.ID, err.Error())
+			continue
+		}
+		if !updated {
+			continue
+		}
+		events = append(events, &health.HealthEvent{
+			Entity:         dbCommon.Entity{TenantId: clusterInfo.TenantId, Status: string(target)},
+			ClusterID:      clusterInfo.ID,
+			InstanceID:     instance.ID,
+			InstanceType:   instance.Type,
+			PreviousStatus: instance.Status,
+			Reason:         reason,
+		})
+	}
+
+	target, reason := report.clusterStatus()
+	if h.shouldChange(clusterInfo.ID, clusterInfo.Status, string(target)) {
+		updated, err := rw.CompareAndUpdateStatus(ctx, clusterInfo.ID, clusterInfo.Status, target)
+		if err != nil {
+			return err
+		}
+		if updated {
+			events = append(events, &health.HealthEvent{
+				Entity:         dbCommon.Entity{TenantId: clusterInfo.TenantId, Status: string(target)},
+				ClusterID:      clusterInfo.ID,
+				PreviousStatus: clusterInfo.Status,
+				Reason:         reason,
+			})
+		}
+	}
+
+	for _, event := range events {
+		framework.LogWithContext(ctx).Infof("status of cluster %s instance %s changes from %s to %s, %s",
+			event.ClusterID, event.InstanceID, event.PreviousStatus, event.Status, event.Reason)
+	}
+	return models.GetHealthReaderWriter().CreateEvents(ctx, events)
+}
+
+// shouldChange
+// @Description: count consecutive unhealthy probes of the cluster or instance,
+// a change to a higher severity happens only if the count reaches HealthCheckFailureThreshold
+// @Parameter id
+// @Parameter current
+// @Parameter target
+// @return bool
+func (h *monitorHandler) shouldChange(id string, current string, target string) bool {
+	h.probed[id] = true
+	if target == string(constants.ClusterRunning) {
+		delete(h.failures, id)
+	} else {
+		h.failures[id]++
+	}
+	if current == target {
+		return false
+	}
+	if statusSeverity[target] > statusSeverity[current] {
+		return h.failures[id] >= constants.HealthCheckFailureThreshold
+	}
+	return true
+}
+
+func (h *cleanHandler) Run() {
+	ctx := context.TODO()
+	retention := getEventRetentionDays(ctx)
+	deleted, err := models.GetHealthReaderWriter().DeleteEventsBefore(ctx, time.Now().AddDate(0, 0, -retention))
+	if err != nil {
+		framework.Log().Errorf("delete expired health events failed, %s", err.Error())
+		return
+	}
+	framework.Log().Infof("%d expired health events are deleted", deleted)
+}
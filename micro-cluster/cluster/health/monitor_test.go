@@ -0,0 +1,208 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/health"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	"github.com/pingcap/tiunimanager/models/common"
+	"github.com/pingcap/tiunimanager/models/platform/config"
+	"github.com/pingcap/tiunimanager/test/mockmodels/mockclustermanagement"
+	"github.com/pingcap/tiunimanager/test/mockmodels/mockconfig"
+	"github.com/pingcap/tiunimanager/test/mockmodels/mockhealth"
+	"github.com/pingcap/tiunimanager/test/mockutilhealth"
+	healthApi "github.com/pingcap/tiunimanager/util/api/health"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonitorHandler_shouldChange(t *testing.T) {
+	h := newMonitorHandler()
+	running, recovering, failure := string(constants.ClusterRunning), string(constants.ClusterRecovering), string(constants.ClusterFailure)
+
+	assert.False(t, h.shouldChange("c1", running, running))
+	assert.False(t, h.shouldChange("c1", running, failure))
+	assert.True(t, h.shouldChange("c1", running, failure))
+	assert.False(t, h.shouldChange("c1", failure, failure))
+	assert.True(t, h.shouldChange("c1", failure, recovering))
+	assert.True(t, h.shouldChange("c1", recovering, running))
+	assert.Equal(t, 0, h.failures["c1"])
+
+	assert.False(t, h.shouldChange("c2", running, recovering))
+	assert.False(t, h.shouldChange("c2", running, running))
+	assert.False(t, h.shouldChange("c2", running, recovering))
+	assert.True(t, h.probed["c2"])
+}
+
+func TestMonitorHandler_checkCluster(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mockutilhealth.NewMockHealthService(ctrl)
+	healthApi.ProbeService = service
+	clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+	models.SetClusterReaderWriter(clusterRW)
+	healthRW := mockhealth.NewMockReaderWriter(ctrl)
+	models.SetHealthReaderWriter(healthRW)
+
+	clusterInfo := &management.Cluster{Entity: common.Entity{ID: "cluster1", TenantId: "1", Status: string(constants.ClusterRunning)}}
+	instances := mockInstances("cluster1")
+	mockHealthyPD(service)
+	service.EXPECT().QueryStores(gomock.Any(), gomock.Any()).Return([]healthApi.Store{
+		{ID: 1, Address: "127.0.0.1:20160", StateName: healthApi.StoreStateUp},
+	}, nil).AnyTimes()
+	service.EXPECT().QueryRegionCount(gomock.Any(), gomock.Any(), gomock.Any()).Return(0, nil).AnyTimes()
+	service.EXPECT().QueryTiDBStatus(gomock.Any(), "127.0.0.1:10080").Return(nil, errors.Error(errors.TIUNIMANAGER_HEALTH_PROBE_FAILED)).Times(2)
+
+	h := newMonitorHandler()
+	t.Run("first failure", func(t *testing.T) {
+		healthRW.EXPECT().CreateEvents(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, events []*health.HealthEvent) error {
+				assert.Empty(t, events)
+				return nil
+			})
+		assert.NoError(t, h.checkCluster(context.TODO(), clusterInfo, instances))
+	})
+	t.Run("consecutive failure", func(t *testing.T) {
+		mockHealthyPD(service)
+		clusterRW.EXPECT().CompareAndUpdateInstanceStatus(gomock.Any(), "tidb1", string(constants.ClusterInstanceRunning), constants.ClusterInstanceFailure).Return(true, nil)
+		clusterRW.EXPECT().CompareAndUpdateStatus(gomock.Any(), "cluster1", string(constants.ClusterRunning), constants.ClusterFailure).Return(true, nil)
+		healthRW.EXPECT().CreateEvents(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, events []*health.HealthEvent) error {
+				assert.Len(t, events, 2)
+				assert.Equal(t, "tidb1", events[0].InstanceID)
+				assert.Equal(t, string(constants.ClusterInstanceFailure), events[0].Status)
+				assert.Equal(t, string(constants.ClusterInstanceRunning), events[0].PreviousStatus)
+				assert.Empty(t, events[1].InstanceID)
+				assert.Equal(t, string(constants.ClusterFailure), events[1].Status)
+				assert.Contains(t, events[1].Reason, "no tidb server is available")
+				assert.Equal(t, "1", events[1].TenantId)
+				return nil
+			})
+		assert.NoError(t, h.checkCluster(context.TODO(), clusterInfo, instances))
+	})
+	t.Run("recovered", func(t *testing.T) {
+		clusterInfo.Status = string(constants.ClusterFailure)
+		instances[2].Status = string(constants.ClusterInstanceFailure)
+		mockHealthyPD(service)
+		service.EXPECT().QueryTiDBStatus(gomock.Any(), "127.0.0.1:10080").Return(&healthApi.TiDBStatus{}, nil)
+		clusterRW.EXPECT().CompareAndUpdateInstanceStatus(gomock.Any(), "tidb1", string(constants.ClusterInstanceFailure), constants.ClusterInstanceRunning).Return(true, nil)
+		clusterRW.EXPECT().CompareAndUpdateStatus(gomock.Any(), "cluster1", string(constants.ClusterFailure), constants.ClusterRunning).Return(true, nil)
+		healthRW.EXPECT().CreateEvents(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, events []*health.HealthEvent) error {
+				assert.Len(t, events, 2)
+				assert.Equal(t, string(constants.ClusterRunning), events[1].Status)
+				return nil
+			})
+		assert.NoError(t, h.checkCluster(context.TODO(), clusterInfo, instances))
+		assert.Empty(t, h.failures)
+	})
+	t.Run("updated by another replica", func(t *testing.T) {
+		mockHealthyPD(service)
+		service.EXPECT().QueryTiDBStatus(gomock.Any(), "127.0.0.1:10080").Return(&healthApi.TiDBStatus{}, nil)
+		clusterRW.EXPECT().CompareAndUpdateInstanceStatus(gomock.Any(), "tidb1", string(constants.ClusterInstanceFailure), constants.ClusterInstanceRunning).Return(false, nil)
+		clusterRW.EXPECT().CompareAndUpdateStatus(gomock.Any(), "cluster1", string(constants.ClusterFailure), constants.ClusterRunning).Return(false, nil)
+		healthRW.EXPECT().CreateEvents(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, events []*health.HealthEvent) error {
+				// events are recorded only by the replica which updates the status
+				assert.Empty(t, events)
+				return nil
+			})
+		assert.NoError(t, h.checkCluster(context.TODO(), clusterInfo, instances))
+	})
+}
+
+func TestMonitorHandler_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mockutilhealth.NewMockHealthService(ctrl)
+	healthApi.ProbeService = service
+	clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+	models.SetClusterReaderWriter(clusterRW)
+	healthRW := mockhealth.NewMockReaderWriter(ctrl)
+	models.SetHealthReaderWriter(healthRW)
+
+	clusterRW.EXPECT().QueryMetas(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, filters management.Filters, pageReq structs.PageRequest) ([]*management.Result, structs.Page, error) {
+			assert.Equal(t, monitoredClusterStatus, filters.StatusFilters)
+			return []*management.Result{
+				{
+					Cluster:   &management.Cluster{Entity: common.Entity{ID: "cluster1", TenantId: "1", Status: string(constants.ClusterRunning)}},
+					Instances: mockInstances("cluster1")[:1],
+				},
+				{
+					Cluster: &management.Cluster{
+						Entity:            common.Entity{ID: "cluster2", TenantId: "1", Status: string(constants.ClusterRunning)},
+						MaintenanceStatus: constants.ClusterMaintenanceUpgrading,
+					},
+					Instances: mockInstances("cluster2"),
+				},
+			}, structs.Page{}, nil
+		})
+	mockHealthyPD(service)
+	service.EXPECT().QueryStores(gomock.Any(), gomock.Any()).Return([]healthApi.Store{}, nil)
+	service.EXPECT().QueryRegionCount(gomock.Any(), gomock.Any(), gomock.Any()).Return(0, nil).Times(2)
+	healthRW.EXPECT().CreateEvents(gomock.Any(), gomock.Any()).Return(nil)
+
+	h := newMonitorHandler()
+	h.failures["removedCluster"] = 1
+	h.Run()
+	assert.NotContains(t, h.failures, "removedCluster")
+	assert.True(t, h.probed["cluster1"])
+	assert.False(t, h.probed["cluster2"])
+}
+
+func TestCleanHandler_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	healthRW := mockhealth.NewMockReaderWriter(ctrl)
+	models.SetHealthReaderWriter(healthRW)
+	configRW := mockconfig.NewMockReaderWriter(ctrl)
+	models.SetConfigReaderWriter(configRW)
+
+	configRW.EXPECT().GetConfig(gomock.Any(), constants.ConfigKeyHealthEventRetentionDays).Return(&config.SystemConfig{ConfigValue: "7"}, nil)
+	healthRW.EXPECT().DeleteEventsBefore(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, before time.Time) (int64, error) {
+			assert.WithinDuration(t, time.Now().AddDate(0, 0, -7), before, time.Minute)
+			return 1, nil
+		})
+	handler := &cleanHandler{}
+	handler.Run()
+}
+
+func Test_getEventRetentionDays(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	configRW := mockconfig.NewMockReaderWriter(ctrl)
+	models.SetConfigReaderWriter(configRW)
+
+	configRW.EXPECT().GetConfig(gomock.Any(), constants.ConfigKeyHealthEventRetentionDays).Return(&config.SystemConfig{ConfigValue: "abc"}, nil)
+	assert.Equal(t, constants.DefaultHealthEventRetentionDays, getEventRetentionDays(context.TODO()))
+
+	configRW.EXPECT().GetConfig(gomock.Any(), constants.ConfigKeyHealthEventRetentionDays).Return(nil, errors.Error(errors.TIUNIMANAGER_SYSTEM_MISSING_CONFIG))
+	assert.Equal(t, constants.DefaultHealthEventRetentionDays, getEventRetentionDays(context.TODO()))
+}
@@ -0,0 +1,229 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	healthApi "github.com/pingcap/tiunimanager/util/api/health"
+)
+
+// instanceHealth result of probing an instance, reason is empty if it is healthy
+type instanceHealth struct {
+	healthy bool
+	reason  string
+}
+
+// probeReport result of probing a cluster, instances which are not probed are absent
+type probeReport struct {
+	instances map[string]instanceHealth
+	// reasons why the cluster can not provide service
+	unavailable []string
+	// reasons why the cluster provides service but is not fully healthy
+	degraded []string
+}
+
+func (r *probeReport) setHealthy(instance *management.ClusterInstance) {
+	r.instances[instance.ID] = instanceHealth{healthy: true}
+}
+
+func (r *probeReport) setUnhealthy(instance *management.ClusterInstance, reason string) {
+	r.instances[instance.ID] = instanceHealth{healthy: false, reason: reason}
+	r.degraded = append(r.degraded, reason)
+}
+
+// clusterStatus
+// @Description: cluster is failure if it is unavailable, and recovering if it is degraded
+// @return constants.ClusterRunningStatus
+// @return string reason
+func (r *probeReport) clusterStatus() (constants.ClusterRunningStatus, string) {
+	if len(r.unavailable) > 0 {
+		return constants.ClusterFailure, strings.Join(append(r.unavailable, r.degraded...), "; ")
+	}
+	if len(r.degraded) > 0 {
+		return constants.ClusterRecovering, strings.Join(r.degraded, "; ")
+	}
+	return constants.ClusterRunning, "all probes are healthy"
+}
+
+// probeCluster
+// @Description: probe health of pd members, stores and regions by pd api, and tidb servers by their status api
+// @Parameter ctx
+// @Parameter instances
+// @return *probeReport
+func probeCluster(ctx context.Context, instances []*management.ClusterInstance) *probeReport {
+	report := &probeReport{
+		instances:   make(map[string]instanceHealth),
+		unavailable: make([]string, 0),
+		degraded:    make([]string, 0),
+	}
+	grouped := make(map[string][]*management.ClusterInstance)
+	for _, instance := range instances {
+		if monitoredInstanceStatus[constants.ClusterInstanceRunningStatus(instance.Status)] &&
+			len(instance.HostIP) > 0 && len(instance.Ports) > 0 {
+			grouped[instance.Type] = append(grouped[instance.Type], instance)
+		}
+	}
+
+	if pdAddress, ok := probePD(ctx, grouped[string(constants.ComponentIDPD)], report); ok {
+		probeStores(ctx, pdAddress, grouped[string(constants.ComponentIDTiKV)], grouped[string(constants.ComponentIDTiFlash)], report)
+		probeRegions(ctx, pdAddress, report)
+	}
+	probeTiDB(ctx, grouped[string(constants.ComponentIDTiDB)], report)
+	return report
+}
+
+// probePD
+// @Description: query health of pd members from the first reachable pd, quorum of pd members should be healthy
+// @return string address of the reachable pd
+// @return bool false if no pd is reachable
+func probePD(ctx context.Context, pds []*management.ClusterInstance, report *probeReport) (string, bool) {
+	if len(pds) == 0 {
+		report.unavailable = append(report.unavailable, "no pd instance is running")
+		return "", false
+	}
+	for _, pd := range pds {
+		pdAddress := address(pd, 0)
+		members, err := healthApi.ProbeService.QueryPDHealth(ctx, pdAddress)
+		if err != nil {
+			continue
+		}
+		memberHealth := make(map[string]bool)
+		healthyCount := 0
+		for _, member := range members {
+			if member.Health {
+				healthyCount++
+			}
+			for _, url := range member.ClientUrls {
+				memberHealth[strings.TrimPrefix(strings.TrimPrefix(url, "http://"), "https://")] = member.Health
+			}
+		}
+		for _, instance := range pds {
+			if memberHealth[address(instance, 0)] {
+				report.setHealthy(instance)
+			} else {
+				report.setUnhealthy(instance, fmt.Sprintf("pd %s is unhealthy", address(instance, 0)))
+			}
+		}
+		if healthyCount*2 <= len(members) {
+			report.unavailable = append(report.unavailable, fmt.Sprintf("only %d of %d pd members are healthy", healthyCount, len(members)))
+		}
+		return pdAddress, true
+	}
+
+	for _, instance := range pds {
+		report.setUnhealthy(instance, fmt.Sprintf("pd %s is unreachable", address(instance, 0)))
+	}
+	report.unavailable = append(report.unavailable, "all pd members are unreachable")
+	return "", false
+}
+
+// probeStores
+// @Description: tikv and tiflash instances are healthy if their stores are up, at least one tikv store should be up
+func probeStores(ctx context.Context, pdAddress string, tikvs []*management.ClusterInstance, tiflashs []*management.ClusterInstance, report *probeReport) {
+	stores, err := healthApi.ProbeService.QueryStores(ctx, pdAddress)
+	if err != nil {
+		report.degraded = append(report.degraded, fmt.Sprintf("query stores failed, %s", err.Error()))
+		return
+	}
+	storeMap := make(map[string]healthApi.Store)
+	for _, store := range stores {
+		storeMap[store.Address] = store
+	}
+
+	upCount := 0
+	storeInstances := make([]*management.ClusterInstance, 0, len(tikvs)+len(tiflashs))
+	storeInstances = append(append(storeInstances, tikvs...), tiflashs...)
+	for _, instance := range storeInstances {
+		store, found := findStore(instance, storeMap)
+		if !found {
+			// the store has not registered in pd yet
+			continue
+		}
+		switch store.StateName {
+		case healthApi.StoreStateUp:
+			report.setHealthy(instance)
+			if instance.Type == string(constants.ComponentIDTiKV) {
+				upCount++
+			}
+		case healthApi.StoreStateDisconnected, healthApi.StoreStateDown:
+			report.setUnhealthy(instance, fmt.Sprintf("store %d of %s %s is %s", store.ID, instance.Type, store.Address, store.StateName))
+		default:
+			// offline and tombstone stores are being removed from the cluster
+		}
+	}
+	if len(tikvs) > 0 && upCount == 0 {
+		report.unavailable = append(report.unavailable, "no tikv store is up")
+	}
+}
+
+// probeRegions
+// @Description: regions are healthy if none of them are missing peers or have pending peers, as what platform check does
+func probeRegions(ctx context.Context, pdAddress string, report *probeReport) {
+	for _, state := range []string{healthApi.RegionStateMissPeer, healthApi.RegionStatePendingPeer} {
+		count, err := healthApi.ProbeService.QueryRegionCount(ctx, pdAddress, state)
+		if err != nil {
+			report.degraded = append(report.degraded, fmt.Sprintf("query %s regions failed, %s", state, err.Error()))
+			continue
+		}
+		if count > 0 {
+			report.degraded = append(report.degraded, fmt.Sprintf("%d regions are %s", count, state))
+		}
+	}
+}
+
+// probeTiDB
+// @Description: tidb instances are healthy if their status api responds, at least one tidb server should be healthy
+func probeTiDB(ctx context.Context, tidbs []*management.ClusterInstance, report *probeReport) {
+	healthyCount := 0
+	for _, instance := range tidbs {
+		statusAddress := address(instance, 1)
+		if len(statusAddress) == 0 {
+			continue
+		}
+		if _, err := healthApi.ProbeService.QueryTiDBStatus(ctx, statusAddress); err != nil {
+			report.setUnhealthy(instance, fmt.Sprintf("status of tidb %s is unreachable", statusAddress))
+			continue
+		}
+		report.setHealthy(instance)
+		healthyCount++
+	}
+	if len(tidbs) > 0 && healthyCount == 0 {
+		report.unavailable = append(report.unavailable, "no tidb server is available")
+	}
+}
+
+// address ip:port of instance, port is specified by index of ports
+func address(instance *management.ClusterInstance, portIndex int) string {
+	if len(instance.HostIP) == 0 || len(instance.Ports) <= portIndex {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", instance.HostIP[0], instance.Ports[portIndex])
+}
+
+// findStore store whose address is one of the ports of instance
+func findStore(instance *management.ClusterInstance, stores map[string]healthApi.Store) (healthApi.Store, bool) {
+	for index := range instance.Ports {
+		if store, ok := stores[address(instance, index)]; ok {
+			return store, true
+		}
+	}
+	return healthApi.Store{}, false
+}
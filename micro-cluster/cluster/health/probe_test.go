@@ -0,0 +1,137 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package health
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/test/mockutilhealth"
+	healthApi "github.com/pingcap/tiunimanager/util/api/health"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockHealthyPD(service *mockutilhealth.MockHealthService) {
+	service.EXPECT().QueryPDHealth(gomock.Any(), "127.0.0.1:2379").Return([]healthApi.PDMemberHealth{
+		{Name: "pd1", ClientUrls: []string{"http://127.0.0.1:2379"}, Health: true},
+	}, nil)
+}
+
+func Test_probeCluster(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mockutilhealth.NewMockHealthService(ctrl)
+	healthApi.ProbeService = service
+
+	t.Run("healthy", func(t *testing.T) {
+		mockHealthyPD(service)
+		service.EXPECT().QueryStores(gomock.Any(), "127.0.0.1:2379").Return([]healthApi.Store{
+			{ID: 1, Address: "127.0.0.1:20160", StateName: healthApi.StoreStateUp},
+			{ID: 2, Address: "127.0.0.2:3930", StateName: healthApi.StoreStateUp},
+		}, nil)
+		service.EXPECT().QueryRegionCount(gomock.Any(), "127.0.0.1:2379", gomock.Any()).Return(0, nil).Times(2)
+		service.EXPECT().QueryTiDBStatus(gomock.Any(), "127.0.0.1:10080").Return(&healthApi.TiDBStatus{}, nil)
+
+		report := probeCluster(context.TODO(), mockInstances("cluster1"))
+		assert.Len(t, report.instances, 4)
+		for _, result := range report.instances {
+			assert.True(t, result.healthy)
+		}
+		status, _ := report.clusterStatus()
+		assert.Equal(t, constants.ClusterRunning, status)
+	})
+	t.Run("degraded", func(t *testing.T) {
+		mockHealthyPD(service)
+		service.EXPECT().QueryStores(gomock.Any(), "127.0.0.1:2379").Return([]healthApi.Store{
+			{ID: 1, Address: "127.0.0.1:20160", StateName: healthApi.StoreStateUp},
+			{ID: 2, Address: "127.0.0.2:3930", StateName: healthApi.StoreStateDown},
+		}, nil)
+		service.EXPECT().QueryRegionCount(gomock.Any(), "127.0.0.1:2379", healthApi.RegionStateMissPeer).Return(3, nil)
+		service.EXPECT().QueryRegionCount(gomock.Any(), "127.0.0.1:2379", healthApi.RegionStatePendingPeer).Return(0, nil)
+		service.EXPECT().QueryTiDBStatus(gomock.Any(), "127.0.0.1:10080").Return(&healthApi.TiDBStatus{}, nil)
+
+		report := probeCluster(context.TODO(), mockInstances("cluster1"))
+		assert.False(t, report.instances["tiflash1"].healthy)
+		assert.Contains(t, report.instances["tiflash1"].reason, "Down")
+		assert.True(t, report.instances["tikv1"].healthy)
+		status, reason := report.clusterStatus()
+		assert.Equal(t, constants.ClusterRecovering, status)
+		assert.Contains(t, reason, "3 regions are miss-peer")
+	})
+	t.Run("pd unreachable", func(t *testing.T) {
+		service.EXPECT().QueryPDHealth(gomock.Any(), "127.0.0.1:2379").Return(nil, errors.Error(errors.TIUNIMANAGER_HEALTH_PROBE_FAILED))
+		service.EXPECT().QueryTiDBStatus(gomock.Any(), "127.0.0.1:10080").Return(&healthApi.TiDBStatus{}, nil)
+
+		report := probeCluster(context.TODO(), mockInstances("cluster1"))
+		assert.False(t, report.instances["pd1"].healthy)
+		_, probed := report.instances["tikv1"]
+		assert.False(t, probed)
+		status, reason := report.clusterStatus()
+		assert.Equal(t, constants.ClusterFailure, status)
+		assert.Contains(t, reason, "all pd members are unreachable")
+	})
+	t.Run("tidb and tikv unavailable", func(t *testing.T) {
+		mockHealthyPD(service)
+		service.EXPECT().QueryStores(gomock.Any(), "127.0.0.1:2379").Return([]healthApi.Store{
+			{ID: 1, Address: "127.0.0.1:20160", StateName: healthApi.StoreStateDisconnected},
+		}, nil)
+		service.EXPECT().QueryRegionCount(gomock.Any(), "127.0.0.1:2379", gomock.Any()).Return(0, nil).Times(2)
+		service.EXPECT().QueryTiDBStatus(gomock.Any(), "127.0.0.1:10080").Return(nil, errors.Error(errors.TIUNIMANAGER_HEALTH_PROBE_FAILED))
+
+		report := probeCluster(context.TODO(), mockInstances("cluster1"))
+		assert.False(t, report.instances["tikv1"].healthy)
+		assert.False(t, report.instances["tidb1"].healthy)
+		_, probed := report.instances["tiflash1"]
+		assert.False(t, probed)
+		status, reason := report.clusterStatus()
+		assert.Equal(t, constants.ClusterFailure, status)
+		assert.Contains(t, reason, "no tikv store is up")
+		assert.Contains(t, reason, "no tidb server is available")
+	})
+	t.Run("stopped instances", func(t *testing.T) {
+		instances := mockInstances("cluster1")
+		for _, instance := range instances {
+			instance.Status = string(constants.ClusterInstanceStopped)
+		}
+		report := probeCluster(context.TODO(), instances)
+		assert.Empty(t, report.instances)
+		status, _ := report.clusterStatus()
+		assert.Equal(t, constants.ClusterFailure, status)
+	})
+}
+
+func Test_probePD_QuorumLost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mockutilhealth.NewMockHealthService(ctrl)
+	healthApi.ProbeService = service
+	service.EXPECT().QueryPDHealth(gomock.Any(), "127.0.0.1:2379").Return([]healthApi.PDMemberHealth{
+		{Name: "pd1", ClientUrls: []string{"http://127.0.0.1:2379"}, Health: true},
+		{Name: "pd2", ClientUrls: []string{"http://127.0.0.2:2379"}, Health: false},
+	}, nil)
+
+	report := &probeReport{instances: make(map[string]instanceHealth)}
+	address, ok := probePD(context.TODO(), mockInstances("cluster1")[:1], report)
+	assert.True(t, ok)
+	assert.Equal(t, "127.0.0.1:2379", address)
+	assert.True(t, report.instances["pd1"].healthy)
+	assert.Equal(t, []string{"only 1 of 2 pd members are healthy"}, report.unavailable)
+}
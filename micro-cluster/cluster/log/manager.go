@@ -71,9 +71,9 @@ func NewManager() *Manager {
 var buildLogConfigDefine = workflow.WorkFlowDefine{
 	FlowName: constants.FlowBuildLogConfig,
 	TaskNodes: map[string]*workflow.NodeDefine{
-		"start":   {"collect", "success", "fail", workflow.SyncFuncNode, collectorClusterLogConfig},
-		"success": {"end", "", "", workflow.SyncFuncNode, defaultEnd},
-		"fail":    {"fail", "", "", workflow.SyncFuncNode, defaultEnd},
+		"start":   {"collect", "success", "fail", workflow.SyncFuncNode, collectorClusterLogConfig, nil},
+		"success": {"end", "", "", workflow.SyncFuncNode, defaultEnd, nil},
+		"fail":    {"fail", "", "", workflow.SyncFuncNode, defaultEnd, nil},
 	},
 }
 
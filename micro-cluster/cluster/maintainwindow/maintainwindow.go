@@ -0,0 +1,122 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+// Package maintainwindow restricts disruptive operations to the maintain window of cluster.
+// Operations submitted out of the window are rejected, or queued and launched by the scheduler when the window opens.
+package maintainwindow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	dbCommon "github.com/pingcap/tiunimanager/models/common"
+)
+
+// OperationExecutor launch a queued operation by its request in json, return id of the workflow
+type OperationExecutor func(ctx context.Context, request string) (workflowID string, err error)
+
+var executors = make(map[constants.PendingOperationType]OperationExecutor)
+var executorsLock sync.RWMutex
+
+// RegisterOperation
+// @Description: register executor of a disruptive operation, it is used to launch the queued operation
+// @Parameter operationType
+// @Parameter executor
+func RegisterOperation(operationType constants.PendingOperationType, executor OperationExecutor) {
+	executorsLock.Lock()
+	defer executorsLock.Unlock()
+	executors[operationType] = executor
+}
+
+func getExecutor(operationType constants.PendingOperationType) OperationExecutor {
+	executorsLock.RLock()
+	defer executorsLock.RUnlock()
+	return executors[operationType]
+}
+
+// Admit
+// @Description: check maintain window of cluster before a disruptive operation.
+// The operation runs at once if it is in the window or the window is ignored,
+// otherwise it is rejected, or queued if option.WaitMaintainWindow is true
+// @Parameter ctx
+// @Parameter clusterMeta
+// @Parameter operationType
+// @Parameter option
+// @Parameter request request of the operation, it is saved for launching later
+// @return pendingOperationID not empty if the operation is queued
+// @return err
+func Admit(ctx context.Context, clusterMeta *meta.ClusterMeta, operationType constants.PendingOperationType,
+	option structs.MaintainWindowOption, request interface{}) (pendingOperationID string, err error) {
+	now := time.Now()
+	inWindow, window, err := clusterMeta.InMaintainWindow(now)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("parse maintain window of cluster %s failed, %s", clusterMeta.Cluster.ID, err.Error())
+		return "", err
+	}
+	if inWindow {
+		return "", nil
+	}
+	if option.IgnoreMaintainWindow {
+		framework.LogWithContext(ctx).Warnf("%s of cluster %s ignores maintain window %s",
+			operationType, clusterMeta.Cluster.ID, clusterMeta.Cluster.MaintainWindow)
+		return "", nil
+	}
+	nextOpen := window.NextOpen(now)
+	if !option.WaitMaintainWindow {
+		return "", errors.NewErrorf(errors.TIUNIMANAGER_OUT_OF_MAINTAIN_WINDOW,
+			"%s of cluster %s is out of maintain window %s, next window opens at %s",
+			operationType, clusterMeta.Cluster.ID, clusterMeta.Cluster.MaintainWindow, nextOpen.Format(time.RFC3339))
+	}
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		return "", errors.WrapError(errors.TIUNIMANAGER_MARSHAL_ERROR, fmt.Sprintf("marshal request of %s failed", operationType), err)
+	}
+	rw := models.GetClusterReaderWriter()
+	waiting, err := rw.QueryPendingOperations(ctx, clusterMeta.Cluster.ID, constants.PendingOperationWaiting)
+	if err != nil {
+		return "", err
+	}
+	for _, operation := range waiting {
+		// the same operation has been queued
+		if operation.OperationType == string(operationType) && operation.Request == string(data) {
+			return operation.ID, nil
+		}
+	}
+	operation, err := rw.CreatePendingOperation(ctx, &management.PendingOperation{
+		Entity:        dbCommon.Entity{TenantId: clusterMeta.Cluster.TenantId},
+		ClusterID:     clusterMeta.Cluster.ID,
+		OperationType: string(operationType),
+		OperatorID:    framework.GetUserIDFromContext(ctx),
+		Request:       string(data),
+	})
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("queue %s of cluster %s failed, %s", operationType, clusterMeta.Cluster.ID, err.Error())
+		return "", err
+	}
+	framework.LogWithContext(ctx).Infof("%s of cluster %s is queued as %s, it will be launched at %s",
+		operationType, clusterMeta.Cluster.ID, operation.ID, nextOpen.Format(time.RFC3339))
+	return operation.ID, nil
+}
@@ -0,0 +1,137 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package maintainwindow
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	"github.com/robfig/cron"
+)
+
+const launchJobSpec = "0 * * * * *" // every minute
+
+var schedulerOnce sync.Once
+
+type launchHandler struct {
+}
+
+// StartScheduler
+// @Description: start the scheduler which launches queued operations when the maintain window of cluster opens
+func StartScheduler() {
+	schedulerOnce.Do(func() {
+		jobCron := cron.New()
+		if err := jobCron.AddJob(launchJobSpec, &launchHandler{}); err != nil {
+			framework.Log().Fatalf("add launch pending operations cron job failed, %s", err.Error())
+			return
+		}
+		go func() {
+			time.Sleep(5 * time.Second) //wait db client ready
+			jobCron.Start()
+		}()
+	})
+}
+
+func (h *launchHandler) Run() {
+	operations, err := models.GetClusterReaderWriter().QueryPendingOperations(context.TODO(), "", constants.PendingOperationWaiting)
+	if err != nil {
+		framework.Log().Errorf("query waiting operations failed, %s", err.Error())
+		return
+	}
+
+	// operations of a cluster are launched one by one in order of submission
+	handled := make(map[string]bool)
+	for _, operation := range operations {
+		if handled[operation.ClusterID] {
+			continue
+		}
+		handled[operation.ClusterID] = true
+		launch(operation)
+	}
+}
+
+// launch
+// @Description: launch the operation if maintain window of its cluster is open and the cluster is not under maintenance
+// @Parameter operation
+func launch(operation *management.PendingOperation) {
+	ctx := framework.NewMicroContextWithKeyValuePairs(context.Background(), map[string]string{
+		framework.TiUniManager_X_TENANT_ID_KEY: operation.TenantId,
+		framework.TiUniManager_X_USER_ID_KEY:   operation.OperatorID,
+	})
+	rw := models.GetClusterReaderWriter()
+
+	clusterMeta, err := meta.Get(ctx, operation.ClusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Warnf("load cluster %s meta failed, cancel pending operation %s, %s", operation.ClusterID, operation.ID, err.Error())
+		if err = rw.UpdatePendingOperation(ctx, operation.ID, constants.PendingOperationCanceled, "", fmt.Sprintf("load cluster meta failed, %s", err.Error())); err != nil {
+			framework.LogWithContext(ctx).Errorf("update pending operation %s failed, %s", operation.ID, err.Error())
+		}
+		return
+	}
+	inWindow, _, err := clusterMeta.InMaintainWindow(time.Now())
+	if err != nil {
+		if err = rw.UpdatePendingOperation(ctx, operation.ID, constants.PendingOperationFailed, "", err.Error()); err != nil {
+			framework.LogWithContext(ctx).Errorf("update pending operation %s failed, %s", operation.ID, err.Error())
+		}
+		return
+	}
+	if !inWindow {
+		return
+	}
+	if clusterMeta.Cluster.MaintenanceStatus != constants.ClusterMaintenanceNone {
+		framework.LogWithContext(ctx).Infof("cluster %s is under maintenance %s, pending operation %s will be launched later",
+			operation.ClusterID, clusterMeta.Cluster.MaintenanceStatus, operation.ID)
+		return
+	}
+
+	executor := getExecutor(constants.PendingOperationType(operation.OperationType))
+	if executor == nil {
+		framework.LogWithContext(ctx).Errorf("no executor of operation type %s", operation.OperationType)
+		if err = rw.UpdatePendingOperation(ctx, operation.ID, constants.PendingOperationFailed, "", fmt.Sprintf("unknown operation type %s", operation.OperationType)); err != nil {
+			framework.LogWithContext(ctx).Errorf("update pending operation %s failed, %s", operation.ID, err.Error())
+		}
+		return
+	}
+
+	// every replica runs the scheduler, the operation is launched by the one claims it
+	claimed, err := rw.ClaimPendingOperation(ctx, operation.ID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("claim pending operation %s failed, %s", operation.ID, err.Error())
+		return
+	}
+	if !claimed {
+		framework.LogWithContext(ctx).Infof("pending operation %s has been claimed by another replica or canceled", operation.ID)
+		return
+	}
+
+	framework.LogWithContext(ctx).Infof("launch pending operation %s, %s of cluster %s", operation.ID, operation.OperationType, operation.ClusterID)
+	status, message := constants.PendingOperationLaunched, ""
+	workflowID, err := executor(ctx, operation.Request)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("launch pending operation %s failed, %s", operation.ID, err.Error())
+		status, message = constants.PendingOperationFailed, err.Error()
+	}
+	if err = rw.UpdatePendingOperation(ctx, operation.ID, status, workflowID, message); err != nil {
+		framework.LogWithContext(ctx).Errorf("update pending operation %s failed, %s", operation.ID, err.Error())
+	}
+}
@@ -0,0 +1,693 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package management
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/micro-cluster/platform/product"
+	"github.com/pingcap/tiunimanager/micro-cluster/user/rbac"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	"github.com/pingcap/tiunimanager/models/cluster/upgrade"
+	dbCommon "github.com/pingcap/tiunimanager/models/common"
+	"github.com/robfig/cron"
+)
+
+const campaignJobSpec = "*/30 * * * * *" // every 30 seconds
+
+var campaignSchedulerOnce sync.Once
+
+type campaignHandler struct {
+	manager *Manager
+}
+
+// StartUpgradeCampaignScheduler
+// @Description: start the scheduler which follows upgrading clusters and launches the next ones of running campaigns
+// @Receiver p
+func (p *Manager) StartUpgradeCampaignScheduler() {
+	campaignSchedulerOnce.Do(func() {
+		jobCron := cron.New()
+		if err := jobCron.AddJob(campaignJobSpec, &campaignHandler{manager: p}); err != nil {
+			framework.Log().Fatalf("add upgrade campaign cron job failed, %s", err.Error())
+			return
+		}
+		go func() {
+			time.Sleep(5 * time.Second) //wait db client ready
+			jobCron.Start()
+		}()
+	})
+}
+
+func (h *campaignHandler) Run() {
+	for _, status := range []constants.UpgradeCampaignStatus{constants.UpgradeCampaignRunning, constants.UpgradeCampaignHalted, constants.UpgradeCampaignCanceled} {
+		campaigns, _, err := models.GetUpgradeReaderWriter().QueryCampaigns(context.TODO(), "", status, 0, -1)
+		if err != nil {
+			framework.Log().Errorf("query %s upgrade campaigns failed, %s", status, err.Error())
+			continue
+		}
+		for _, campaign := range campaigns {
+			// clusters of stopped campaigns are followed until all of them are finished
+			if !campaign.EndTime.IsZero() {
+				continue
+			}
+			h.manager.advanceCampaign(campaign.ID, campaign.TenantId, campaign.OperatorID)
+		}
+	}
+}
+
+// CreateUpgradeCampaign
+// @Description: select clusters and upgrade them to the target version in ordered waves
+// @Receiver p
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (p *Manager) CreateUpgradeCampaign(ctx context.Context, req cluster.CreateUpgradeCampaignReq) (resp cluster.CreateUpgradeCampaignResp, err error) {
+	if req.UpgradeWay == "" {
+		req.UpgradeWay = string(constants.UpgradeWayOffline)
+	}
+	if req.Concurrency <= 0 {
+		req.Concurrency = 1
+	}
+	if err = validateCampaignReq(req); err != nil {
+		return
+	}
+
+	candidates, err := selectCampaignClusters(ctx, req.Selector)
+	if err != nil {
+		return
+	}
+	products, err := product.NewManager().QueryProducts(ctx, message.QueryProductsInfoReq{
+		ProductIDs: []string{"TiDB"},
+	})
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("failed to query products for TiDB: %s", err.Error())
+		return
+	}
+
+	clusters := make([]*upgrade.UpgradeCampaignCluster, 0)
+	skipped := make([]*upgrade.UpgradeCampaignCluster, 0)
+	for _, candidate := range candidates {
+		if candidate.Version == req.TargetVersion {
+			continue
+		}
+		campaignCluster := &upgrade.UpgradeCampaignCluster{
+			ClusterID:       candidate.ID,
+			ClusterName:     candidate.Name,
+			OriginalVersion: candidate.Version,
+			Status:          string(constants.CampaignClusterWaiting),
+		}
+		if !upgradable(ctx, products, candidate, req.TargetVersion) {
+			campaignCluster.Status = string(constants.CampaignClusterSkipped)
+			campaignCluster.Message = fmt.Sprintf("no in-place upgrade path from %s to %s", candidate.Version, req.TargetVersion)
+			skipped = append(skipped, campaignCluster)
+			continue
+		}
+		clusters = append(clusters, campaignCluster)
+	}
+	if len(clusters) == 0 {
+		err = errors.NewErrorf(errors.TIUNIMANAGER_PARAMETER_INVALID, "no cluster can be upgraded to %s by the selector", req.TargetVersion)
+		return
+	}
+
+	waves := planCampaignWaves(len(clusters), req.WaveSizes)
+	for i, campaignCluster := range clusters {
+		campaignCluster.Wave = waves[i]
+		campaignCluster.Sequence = i
+	}
+	for _, campaignCluster := range skipped {
+		campaignCluster.Wave = waves[len(waves)-1]
+		campaignCluster.Sequence = len(clusters)
+	}
+	clusters = append(clusters, skipped...)
+
+	selector, err := json.Marshal(req.Selector)
+	if err != nil {
+		err = errors.WrapError(errors.TIUNIMANAGER_MARSHAL_ERROR, "marshal campaign selector failed", err)
+		return
+	}
+	waveSizes, err := json.Marshal(req.WaveSizes)
+	if err != nil {
+		err = errors.WrapError(errors.TIUNIMANAGER_MARSHAL_ERROR, "marshal campaign wave sizes failed", err)
+		return
+	}
+	campaign := &upgrade.UpgradeCampaign{
+		Entity: dbCommon.Entity{
+			TenantId: framework.GetTenantIDFromContext(ctx),
+			Status:   string(constants.UpgradeCampaignRunning),
+		},
+		Name:             req.Name,
+		TargetVersion:    req.TargetVersion,
+		UpgradeWay:       req.UpgradeWay,
+		Selector:         string(selector),
+		WaveSizes:        string(waveSizes),
+		Concurrency:      req.Concurrency,
+		FailureThreshold: req.FailureThreshold,
+		OperatorID:       framework.GetUserIDFromContext(ctx),
+		Waves:            waves[len(waves)-1] + 1,
+	}
+	summarizeCampaign(campaign, clusters)
+	campaign, err = models.GetUpgradeReaderWriter().CreateCampaign(ctx, campaign, clusters)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("create upgrade campaign to %s failed, %s", req.TargetVersion, err.Error())
+		return
+	}
+	framework.LogWithContext(ctx).Infof("upgrade campaign %s is created, %d clusters are upgraded to %s in %d waves",
+		campaign.ID, len(clusters)-len(skipped), req.TargetVersion, campaign.Waves)
+
+	resp.Campaign = convertCampaignInfo(campaign)
+	resp.Clusters = convertCampaignClusters(clusters)
+	return
+}
+
+func validateCampaignReq(req cluster.CreateUpgradeCampaignReq) error {
+	if req.TargetVersion == "" {
+		return errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "target version is required")
+	}
+	if req.UpgradeWay != string(constants.UpgradeWayOffline) && req.UpgradeWay != string(constants.UpgradeWayOnline) {
+		return errors.NewErrorf(errors.TIUNIMANAGER_PARAMETER_INVALID, "upgrade way %s is invalid", req.UpgradeWay)
+	}
+	for _, size := range req.WaveSizes {
+		if size <= 0 {
+			return errors.NewErrorf(errors.TIUNIMANAGER_PARAMETER_INVALID, "wave size %d is invalid", size)
+		}
+	}
+	if req.FailureThreshold < 0 || req.FailureThreshold > 1 {
+		return errors.NewErrorf(errors.TIUNIMANAGER_PARAMETER_INVALID, "failure threshold %v is not in [0, 1]", req.FailureThreshold)
+	}
+	return nil
+}
+
+// selectCampaignClusters
+// @Description: select clusters of current tenant which current user has permission to upgrade,
+// tag selector is applied by the query of clusters. Clusters are ordered as cluster ids in selector if specified, otherwise by name
+// @Parameter ctx
+// @Parameter selector
+// @return []*management.Cluster
+// @return error
+func selectCampaignClusters(ctx context.Context, selector structs.UpgradeCampaignSelector) ([]*management.Cluster, error) {
+	tagSelector, err := structs.ParseTagSelector(selector.TagSelector)
+	if err != nil {
+		return nil, errors.WrapError(errors.TIUNIMANAGER_PARAMETER_INVALID, "invalid tag selector", err)
+	}
+	results, _, err := models.GetClusterReaderWriter().QueryMetas(ctx, management.Filters{
+		TenantId:    framework.GetTenantIDFromContext(ctx),
+		ClusterIDs:  selector.ClusterIDs,
+		TagSelector: tagSelector,
+	}, structs.PageRequest{Page: 1, PageSize: math.MaxInt32})
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query clusters of upgrade campaign failed, %s", err.Error())
+		return nil, err
+	}
+
+	selected := make([]*management.Cluster, 0)
+	for _, result := range results {
+		if !matchCampaignSelector(result.Cluster, selector) {
+			continue
+		}
+		allowed, err := rbac.HasClusterPermission(ctx, constants.RbacActionUpdate,
+			rbac.ClusterPermissionInstances(result.Cluster.ID, result.Cluster.Tags, result.Cluster.TenantId))
+		if err != nil {
+			framework.LogWithContext(ctx).Errorf("check permission of cluster %s failed, %s", result.Cluster.ID, err.Error())
+			return nil, err
+		}
+		if allowed {
+			selected = append(selected, result.Cluster)
+		}
+	}
+
+	order := make(map[string]int)
+	for i, id := range selector.ClusterIDs {
+		order[id] = i
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		if len(order) > 0 {
+			return order[selected[i].ID] < order[selected[j].ID]
+		}
+		return selected[i].Name < selected[j].Name
+	})
+	return selected, nil
+}
+
+// matchCampaignSelector
+// @Description: cluster is selected if it has all tags, and its version and vendor are in the selector
+func matchCampaignSelector(clusterInfo *management.Cluster, selector structs.UpgradeCampaignSelector) bool {
+	tags := make(map[string]bool)
+	for _, tag := range clusterInfo.Tags {
+		tags[tag] = true
+	}
+	for _, tag := range selector.Tags {
+		if !tags[tag] {
+			return false
+		}
+	}
+	return (len(selector.Versions) == 0 || meta.Contain(selector.Versions, clusterInfo.Version)) &&
+		(len(selector.Vendors) == 0 || meta.Contain(selector.Vendors, clusterInfo.Vendor))
+}
+
+// upgradable
+// @Description: check whether the target version is in the in-place upgrade paths of cluster
+func upgradable(ctx context.Context, products message.QueryProductsInfoResp, clusterInfo *management.Cluster, targetVersion string) bool {
+	for _, path := range generatePaths(ctx, products, clusterInfo.Version, string(clusterInfo.CpuArchitecture)) {
+		if path.UpgradeType == string(constants.UpgradeTypeInPlace) && meta.Contain(path.Versions, targetVersion) {
+			return true
+		}
+	}
+	return false
+}
+
+// planCampaignWaves
+// @Description: assign clusters to waves by wave sizes, the last size is used by the rest waves,
+// all clusters are in one wave if sizes are not specified
+// @Parameter count count of clusters
+// @Parameter sizes
+// @return []int index of wave of each cluster
+func planCampaignWaves(count int, sizes []int) []int {
+	waves := make([]int, count)
+	if len(sizes) == 0 {
+		return waves
+	}
+	wave, filled := 0, 0
+	for i := range waves {
+		size := sizes[len(sizes)-1]
+		if wave < len(sizes) {
+			size = sizes[wave]
+		}
+		if filled == size {
+			wave++
+			filled = 0
+		}
+		waves[i] = wave
+		filled++
+	}
+	return waves
+}
+
+// exceedFailureThreshold
+// @Description: failure rate is the ratio of failed clusters in finished clusters
+func exceedFailureThreshold(succeeded int, failed int, threshold float64) bool {
+	if failed == 0 {
+		return false
+	}
+	return float64(failed)/float64(succeeded+failed) > threshold
+}
+
+// summarizeCampaign
+// @Description: update aggregate progress of campaign by its clusters
+func summarizeCampaign(campaign *upgrade.UpgradeCampaign, clusters []*upgrade.UpgradeCampaignCluster) {
+	campaign.Total = len(clusters)
+	campaign.Upgrading, campaign.Succeeded, campaign.Failed, campaign.Skipped = 0, 0, 0, 0
+	for _, c := range clusters {
+		switch constants.UpgradeCampaignClusterStatus(c.Status) {
+		case constants.CampaignClusterUpgrading:
+			campaign.Upgrading++
+		case constants.CampaignClusterSucceeded:
+			campaign.Succeeded++
+		case constants.CampaignClusterFailed:
+			campaign.Failed++
+		case constants.CampaignClusterSkipped:
+			campaign.Skipped++
+		}
+	}
+}
+
+// currentCampaignWave
+// @Description: the first wave which has clusters waiting or upgrading, the next wave starts after all clusters of the current one are finished
+// @return int -1 if all clusters are finished
+func currentCampaignWave(clusters []*upgrade.UpgradeCampaignCluster) int {
+	wave := -1
+	for _, c := range clusters {
+		if c.Status != string(constants.CampaignClusterWaiting) && c.Status != string(constants.CampaignClusterUpgrading) {
+			continue
+		}
+		if wave < 0 || c.Wave < wave {
+			wave = c.Wave
+		}
+	}
+	return wave
+}
+
+// skipWaitingClusters
+// @Description: skip clusters not launched when the campaign is stopped
+func skipWaitingClusters(clusters []*upgrade.UpgradeCampaignCluster, reason string) {
+	for _, c := range clusters {
+		if c.Status == string(constants.CampaignClusterWaiting) {
+			c.Status = string(constants.CampaignClusterSkipped)
+			c.Message = reason
+		}
+	}
+}
+
+// advanceCampaign
+// @Description: follow workflows of upgrading clusters, then halt the campaign if the failure rate exceeds the threshold,
+// or launch waiting clusters of the current wave whose maintain window is open
+// @Receiver p
+// @Parameter campaignID
+// @Parameter tenantID
+// @Parameter operatorID clusters are upgraded by the user who creates the campaign
+func (p *Manager) advanceCampaign(campaignID string, tenantID string, operatorID string) {
+	ctx := framework.NewMicroContextWithKeyValuePairs(context.Background(), map[string]string{
+		framework.TiUniManager_X_TENANT_ID_KEY: tenantID,
+		framework.TiUniManager_X_USER_ID_KEY:   operatorID,
+	})
+	rw := models.GetUpgradeReaderWriter()
+	campaign, clusters, err := rw.GetCampaign(ctx, campaignID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("get upgrade campaign %s failed, %s", campaignID, err.Error())
+		return
+	}
+	// every write is conditional on the status read here,
+	// so that a concurrent cancel or another replica is never reverted
+	campaignStatus := campaign.Status
+	origins := make(map[string]upgrade.UpgradeCampaignCluster, len(clusters))
+	expectedStatus := make(map[string]string, len(clusters))
+	for _, c := range clusters {
+		origins[c.ID] = *c
+		expectedStatus[c.ID] = c.Status
+	}
+
+	for _, c := range clusters {
+		if c.Status == string(constants.CampaignClusterUpgrading) {
+			followCampaignCluster(ctx, c)
+		}
+	}
+	summarizeCampaign(campaign, clusters)
+
+	if campaign.Status == string(constants.UpgradeCampaignRunning) {
+		if exceedFailureThreshold(campaign.Succeeded, campaign.Failed, campaign.FailureThreshold) {
+			campaign.Status = string(constants.UpgradeCampaignHalted)
+			campaign.Message = fmt.Sprintf("failure rate %d/%d exceeds threshold %v",
+				campaign.Failed, campaign.Succeeded+campaign.Failed, campaign.FailureThreshold)
+			skipWaitingClusters(clusters, "campaign is halted")
+			framework.LogWithContext(ctx).Warnf("upgrade campaign %s is halted, %s", campaign.ID, campaign.Message)
+		} else if wave := currentCampaignWave(clusters); wave < 0 {
+			campaign.Status = string(constants.UpgradeCampaignCompleted)
+		} else {
+			campaign.CurrentWave = wave
+			for _, c := range clusters {
+				if campaign.Upgrading >= campaign.Concurrency {
+					break
+				}
+				if c.Wave == wave && c.Status == string(constants.CampaignClusterWaiting) {
+					if p.launchCampaignCluster(ctx, campaign, c) {
+						expectedStatus[c.ID] = string(constants.CampaignClusterUpgrading)
+					}
+					summarizeCampaign(campaign, clusters)
+				}
+			}
+		}
+	}
+
+	if campaign.Status != string(constants.UpgradeCampaignRunning) && campaign.Upgrading == 0 {
+		campaign.EndTime = time.Now()
+	}
+	for _, c := range clusters {
+		if *c == origins[c.ID] && expectedStatus[c.ID] == c.Status {
+			continue
+		}
+		if updated, err := rw.UpdateCampaignCluster(ctx, c, expectedStatus[c.ID]); err != nil {
+			framework.LogWithContext(ctx).Errorf("update cluster %s of upgrade campaign %s failed, %s", c.ClusterID, campaign.ID, err.Error())
+		} else if !updated {
+			framework.LogWithContext(ctx).Infof("cluster %s of upgrade campaign %s has been changed by others", c.ClusterID, campaign.ID)
+		}
+	}
+	if updated, err := rw.UpdateCampaign(ctx, campaign, campaignStatus); err != nil {
+		framework.LogWithContext(ctx).Errorf("update upgrade campaign %s failed, %s", campaign.ID, err.Error())
+	} else if !updated {
+		framework.LogWithContext(ctx).Infof("upgrade campaign %s has been changed by others, it will be advanced next time", campaign.ID)
+	}
+}
+
+// followCampaignCluster
+// @Description: update status of upgrading cluster by its workflow
+func followCampaignCluster(ctx context.Context, c *upgrade.UpgradeCampaignCluster) {
+	flow, err := models.GetWorkFlowReaderWriter().GetWorkFlow(ctx, c.WorkFlowID)
+	if err != nil {
+		framework.LogWithContext(ctx).Warnf("get workflow %s of cluster %s failed, %s", c.WorkFlowID, c.ClusterID, err.Error())
+		return
+	}
+	if !flow.Finished() && !flow.Stopped() {
+		return
+	}
+	c.EndTime = time.Now()
+	if flow.Status == constants.WorkFlowStatusFinished {
+		c.Status = string(constants.CampaignClusterSucceeded)
+		c.Message = ""
+	} else {
+		c.Status = string(constants.CampaignClusterFailed)
+		c.Message = fmt.Sprintf("upgrade workflow %s is %s", flow.ID, flow.Status)
+	}
+}
+
+// launchCampaignCluster
+// @Description: upgrade the cluster if its maintain window is open and it is not under maintenance, otherwise it keeps waiting.
+// The cluster is claimed before upgrading, so that it is launched by only one replica
+// @return claimed true if the cluster is claimed, its status in db is upgrading now
+func (p *Manager) launchCampaignCluster(ctx context.Context, campaign *upgrade.UpgradeCampaign, c *upgrade.UpgradeCampaignCluster) (claimed bool) {
+	clusterMeta, err := meta.Get(ctx, c.ClusterID)
+	if err != nil {
+		c.Status = string(constants.CampaignClusterSkipped)
+		c.Message = fmt.Sprintf("load cluster meta failed, %s", err.Error())
+		return
+	}
+	now := time.Now()
+	inWindow, window, err := clusterMeta.InMaintainWindow(now)
+	if err != nil {
+		c.Status = string(constants.CampaignClusterFailed)
+		c.Message = err.Error()
+		return
+	}
+	if !inWindow {
+		c.Message = fmt.Sprintf("waiting for maintain window %s, next window opens at %s",
+			clusterMeta.Cluster.MaintainWindow, window.NextOpen(now).Format(time.RFC3339))
+		return
+	}
+	if clusterMeta.Cluster.MaintenanceStatus != constants.ClusterMaintenanceNone {
+		c.Message = fmt.Sprintf("waiting for maintenance %s of cluster", clusterMeta.Cluster.MaintenanceStatus)
+		return
+	}
+
+	claimed, err = models.GetUpgradeReaderWriter().ClaimCampaignCluster(ctx, c.ID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("claim cluster %s of campaign %s failed, %s", c.ClusterID, campaign.ID, err.Error())
+		return
+	}
+	if !claimed {
+		// launched or skipped by others, it is written by them
+		framework.LogWithContext(ctx).Infof("cluster %s of campaign %s has been claimed by others", c.ClusterID, campaign.ID)
+		c.Status = string(constants.CampaignClusterUpgrading)
+		return
+	}
+
+	resp, err := p.InPlaceUpgradeCluster(ctx, cluster.UpgradeClusterReq{
+		ClusterID:     c.ClusterID,
+		TargetVersion: campaign.TargetVersion,
+		UpgradeType:   string(constants.UpgradeTypeInPlace),
+		UpgradeWay:    campaign.UpgradeWay,
+		// the window has been checked above
+		MaintainWindowOption: structs.MaintainWindowOption{IgnoreMaintainWindow: true},
+	})
+	if err != nil {
+		if finalErr, ok := err.(errors.EMError); ok && finalErr.GetCode() == errors.TIUNIMANAGER_CLUSTER_MAINTENANCE_CONFLICT {
+			// cluster is taken by another maintenance after checking, give it back to waiting
+			framework.LogWithContext(ctx).Infof("cluster %s of campaign %s keeps waiting, %s", c.ClusterID, campaign.ID, err.Error())
+			c.Status = string(constants.CampaignClusterWaiting)
+			c.Message = fmt.Sprintf("waiting for maintenance of cluster, %s", err.Error())
+			return
+		}
+		framework.LogWithContext(ctx).Errorf("upgrade cluster %s of campaign %s failed, %s", c.ClusterID, campaign.ID, err.Error())
+		c.StartTime = now
+		c.Status = string(constants.CampaignClusterFailed)
+		c.Message = err.Error()
+		c.EndTime = now
+		return
+	}
+	framework.LogWithContext(ctx).Infof("upgrade cluster %s to %s in wave %d of campaign %s, workflow %s",
+		c.ClusterID, campaign.TargetVersion, c.Wave, campaign.ID, resp.WorkFlowID)
+	c.StartTime = now
+	c.Status = string(constants.CampaignClusterUpgrading)
+	c.WorkFlowID = resp.WorkFlowID
+	c.Message = ""
+}
+
+// QueryUpgradeCampaigns
+// @Description: query upgrade campaigns of current tenant
+// @Receiver p
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return total
+// @return err
+func (p *Manager) QueryUpgradeCampaigns(ctx context.Context, req cluster.QueryUpgradeCampaignsReq) (resp cluster.QueryUpgradeCampaignsResp, total int, err error) {
+	campaigns, count, err := models.GetUpgradeReaderWriter().QueryCampaigns(ctx, framework.GetTenantIDFromContext(ctx),
+		constants.UpgradeCampaignStatus(req.Status), req.GetOffset(), req.PageSize)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query upgrade campaigns failed, %s", err.Error())
+		return
+	}
+	resp.Campaigns = make([]structs.UpgradeCampaignInfo, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		resp.Campaigns = append(resp.Campaigns, convertCampaignInfo(campaign))
+	}
+	total = int(count)
+	return
+}
+
+// GetUpgradeCampaign
+// @Description: get upgrade campaign and its clusters
+// @Receiver p
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (p *Manager) GetUpgradeCampaign(ctx context.Context, req cluster.GetUpgradeCampaignReq) (resp cluster.GetUpgradeCampaignResp, err error) {
+	campaign, clusters, err := getTenantCampaign(ctx, req.CampaignID)
+	if err != nil {
+		return
+	}
+	resp.Campaign = convertCampaignInfo(campaign)
+	resp.Clusters = convertCampaignClusters(clusters)
+	return
+}
+
+// CancelUpgradeCampaign
+// @Description: cancel a running campaign, waiting clusters are skipped and upgrading ones are not affected
+// @Receiver p
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (p *Manager) CancelUpgradeCampaign(ctx context.Context, req cluster.CancelUpgradeCampaignReq) (resp cluster.CancelUpgradeCampaignResp, err error) {
+	campaign, clusters, err := getTenantCampaign(ctx, req.CampaignID)
+	if err != nil {
+		return
+	}
+	if campaign.Status != string(constants.UpgradeCampaignRunning) {
+		err = errors.NewErrorf(errors.TIUNIMANAGER_TASK_CONFLICT, "upgrade campaign %s is %s, only running campaign can be canceled", campaign.ID, campaign.Status)
+		return
+	}
+
+	waiting := make([]*upgrade.UpgradeCampaignCluster, 0)
+	for _, c := range clusters {
+		if c.Status == string(constants.CampaignClusterWaiting) {
+			waiting = append(waiting, c)
+		}
+	}
+	campaign.Status = string(constants.UpgradeCampaignCanceled)
+	campaign.Message = fmt.Sprintf("canceled by %s", framework.GetUserIDFromContext(ctx))
+	skipWaitingClusters(clusters, "campaign is canceled")
+	summarizeCampaign(campaign, clusters)
+	if campaign.Upgrading == 0 {
+		campaign.EndTime = time.Now()
+	}
+
+	rw := models.GetUpgradeReaderWriter()
+	updated, err := rw.UpdateCampaign(ctx, campaign, string(constants.UpgradeCampaignRunning))
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("cancel upgrade campaign %s failed, %s", campaign.ID, err.Error())
+		return
+	}
+	if !updated {
+		err = errors.NewErrorf(errors.TIUNIMANAGER_TASK_CONFLICT, "upgrade campaign %s has been changed by others, please retry", campaign.ID)
+		return
+	}
+	// a cluster claimed by the scheduler in the meantime keeps upgrading, the progress is corrected when the campaign is advanced
+	for _, c := range waiting {
+		if _, err = rw.UpdateCampaignCluster(ctx, c, string(constants.CampaignClusterWaiting)); err != nil {
+			framework.LogWithContext(ctx).Errorf("skip cluster %s of upgrade campaign %s failed, %s", c.ClusterID, campaign.ID, err.Error())
+			return
+		}
+	}
+	resp.CampaignID = campaign.ID
+	return
+}
+
+func getTenantCampaign(ctx context.Context, campaignID string) (*upgrade.UpgradeCampaign, []*upgrade.UpgradeCampaignCluster, error) {
+	campaign, clusters, err := models.GetUpgradeReaderWriter().GetCampaign(ctx, campaignID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("get upgrade campaign %s failed, %s", campaignID, err.Error())
+		return nil, nil, err
+	}
+	if campaign.TenantId != framework.GetTenantIDFromContext(ctx) {
+		return nil, nil, errors.NewErrorf(errors.TIUNIMANAGER_UPGRADE_CAMPAIGN_NOT_FOUND, "upgrade campaign %s not found", campaignID)
+	}
+	return campaign, clusters, nil
+}
+
+func convertCampaignInfo(campaign *upgrade.UpgradeCampaign) structs.UpgradeCampaignInfo {
+	info := structs.UpgradeCampaignInfo{
+		ID:               campaign.ID,
+		Name:             campaign.Name,
+		TargetVersion:    campaign.TargetVersion,
+		UpgradeWay:       campaign.UpgradeWay,
+		WaveSizes:        make([]int, 0),
+		Concurrency:      campaign.Concurrency,
+		FailureThreshold: campaign.FailureThreshold,
+		Status:           campaign.Status,
+		Message:          campaign.Message,
+		Waves:            campaign.Waves,
+		CurrentWave:      campaign.CurrentWave,
+		Total:            campaign.Total,
+		Waiting:          campaign.Total - campaign.Upgrading - campaign.Succeeded - campaign.Failed - campaign.Skipped,
+		Upgrading:        campaign.Upgrading,
+		Succeeded:        campaign.Succeeded,
+		Failed:           campaign.Failed,
+		Skipped:          campaign.Skipped,
+		OperatorID:       campaign.OperatorID,
+		CreateTime:       campaign.CreatedAt,
+		UpdateTime:       campaign.UpdatedAt,
+		EndTime:          campaign.EndTime,
+	}
+	if campaign.Selector != "" {
+		json.Unmarshal([]byte(campaign.Selector), &info.Selector)
+	}
+	if campaign.WaveSizes != "" {
+		json.Unmarshal([]byte(campaign.WaveSizes), &info.WaveSizes)
+	}
+	return info
+}
+
+func convertCampaignClusters(clusters []*upgrade.UpgradeCampaignCluster) []structs.UpgradeCampaignClusterInfo {
+	infos := make([]structs.UpgradeCampaignClusterInfo, 0, len(clusters))
+	for _, c := range clusters {
+		infos = append(infos, structs.UpgradeCampaignClusterInfo{
+			ClusterID:       c.ClusterID,
+			ClusterName:     c.ClusterName,
+			OriginalVersion: c.OriginalVersion,
+			Wave:            c.Wave,
+			Status:          c.Status,
+			WorkFlowID:      c.WorkFlowID,
+			Message:         c.Message,
+			StartTime:       c.StartTime,
+			EndTime:         c.EndTime,
+		})
+	}
+	return infos
+}
@@ -0,0 +1,104 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package management
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	"github.com/pingcap/tiunimanager/models/cluster/upgrade"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_planCampaignWaves(t *testing.T) {
+	assert.Equal(t, []int{0, 0, 0}, planCampaignWaves(3, nil))
+	assert.Equal(t, []int{0, 1, 1, 2, 2, 3}, planCampaignWaves(6, []int{1, 2}))
+	assert.Equal(t, []int{0, 1, 1, 1, 2}, planCampaignWaves(5, []int{1, 3, 5}))
+	assert.Equal(t, []int{}, planCampaignWaves(0, []int{1}))
+}
+
+func Test_exceedFailureThreshold(t *testing.T) {
+	assert.False(t, exceedFailureThreshold(0, 0, 0))
+	assert.False(t, exceedFailureThreshold(4, 1, 0.2))
+	assert.True(t, exceedFailureThreshold(3, 1, 0.2))
+	assert.True(t, exceedFailureThreshold(10, 1, 0))
+}
+
+func Test_matchCampaignSelector(t *testing.T) {
+	clusterInfo := &management.Cluster{
+		Version: "v5.3.0",
+		Vendor:  "Local",
+		Tags:    []string{"prod", "east"},
+	}
+	assert.True(t, matchCampaignSelector(clusterInfo, structs.UpgradeCampaignSelector{}))
+	assert.True(t, matchCampaignSelector(clusterInfo, structs.UpgradeCampaignSelector{
+		Tags:     []string{"prod"},
+		Versions: []string{"v5.2.0", "v5.3.0"},
+		Vendors:  []string{"Local"},
+	}))
+	assert.False(t, matchCampaignSelector(clusterInfo, structs.UpgradeCampaignSelector{Tags: []string{"prod", "west"}}))
+	assert.False(t, matchCampaignSelector(clusterInfo, structs.UpgradeCampaignSelector{Versions: []string{"v5.2.0"}}))
+	assert.False(t, matchCampaignSelector(clusterInfo, structs.UpgradeCampaignSelector{Vendors: []string{"AWS"}}))
+}
+
+func Test_validateCampaignReq(t *testing.T) {
+	req := cluster.CreateUpgradeCampaignReq{
+		TargetVersion:    "v5.4.0",
+		UpgradeWay:       string(constants.UpgradeWayOnline),
+		WaveSizes:        []int{1, 5},
+		FailureThreshold: 0.2,
+	}
+	assert.NoError(t, validateCampaignReq(req))
+
+	invalid := req
+	invalid.UpgradeWay = "unknown"
+	assert.Error(t, validateCampaignReq(invalid))
+	invalid = req
+	invalid.WaveSizes = []int{1, 0}
+	assert.Error(t, validateCampaignReq(invalid))
+	invalid = req
+	invalid.FailureThreshold = 1.5
+	assert.Error(t, validateCampaignReq(invalid))
+}
+
+func Test_campaignProgress(t *testing.T) {
+	clusters := []*upgrade.UpgradeCampaignCluster{
+		{ClusterID: "c1", Wave: 0, Status: string(constants.CampaignClusterSucceeded)},
+		{ClusterID: "c2", Wave: 1, Status: string(constants.CampaignClusterUpgrading)},
+		{ClusterID: "c3", Wave: 1, Status: string(constants.CampaignClusterFailed)},
+		{ClusterID: "c4", Wave: 2, Status: string(constants.CampaignClusterWaiting)},
+		{ClusterID: "c5", Wave: 2, Status: string(constants.CampaignClusterSkipped)},
+	}
+	campaign := &upgrade.UpgradeCampaign{}
+	summarizeCampaign(campaign, clusters)
+	assert.Equal(t, 5, campaign.Total)
+	assert.Equal(t, 1, campaign.Upgrading)
+	assert.Equal(t, 1, campaign.Succeeded)
+	assert.Equal(t, 1, campaign.Failed)
+	assert.Equal(t, 1, campaign.Skipped)
+	assert.Equal(t, 1, convertCampaignInfo(campaign).Waiting)
+	assert.Equal(t, 1, currentCampaignWave(clusters))
+
+	skipWaitingClusters(clusters, "campaign is halted")
+	assert.Equal(t, string(constants.CampaignClusterSkipped), clusters[3].Status)
+	assert.Equal(t, string(constants.CampaignClusterUpgrading), clusters[1].Status)
+
+	clusters[1].Status = string(constants.CampaignClusterSucceeded)
+	assert.Equal(t, -1, currentCampaignWave(clusters))
+}
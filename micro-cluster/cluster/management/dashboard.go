@@ -53,6 +53,9 @@ func GetDashboardInfo(ctx context.Context, request cluster.GetDashboardInfoReq)
 		framework.LogWithContext(ctx).Errorf(errMsg)
 		return resp, errors.WrapError(errors.TIUNIMANAGER_CLUSTER_NOT_FOUND, errMsg, err)
 	}
+	if err = checkClusterPermission(ctx, meta, constants.RbacActionRead); err != nil {
+		return resp, err
+	}
 
 	tidbUserInfo, err := meta.GetDBUserNamePassword(ctx, constants.Root)
 	if err != nil {
@@ -1637,7 +1637,11 @@ func initGrafanaAccount(node *workflowModel.WorkFlowNode, context *workflow.Flow
 		return err
 	}
 
-	if len(meta.Grafanas) <= 0 || len(meta.Grafanas[0].Username) == 0 {
+	if len(meta.Grafanas) <= 0 {
+		node.Record(fmt.Sprintf("cluster %s has no grafana, no need to init", clusterMeta.Cluster.ID))
+		return nil
+	}
+	if len(meta.Grafanas[0].Username) == 0 {
 		framework.LogWithContext(context.Context).Errorf("not found grafana config")
 		return errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "not found grafana config")
 	}
@@ -2225,14 +2229,20 @@ func initDatabaseData(node *workflowModel.WorkFlowNode, context *workflow.FlowCo
 	if err != nil {
 		return err
 	}
+	var restorePoint cluster.RestorePoint
+	err = context.GetData(ContextRestorePoint, &restorePoint)
+	if err != nil {
+		return err
+	}
 
-	if backupID != "" {
-		node.Record(fmt.Sprintf("recover data from backup record %s for cluster %s", backupID, clusterMeta.Cluster.ID))
+	if backupID != "" || restorePoint.IsSet() {
+		node.Record(fmt.Sprintf("recover data from backup record %s, restore point %+v for cluster %s", backupID, restorePoint, clusterMeta.Cluster.ID))
 
 		restoreResponse, err := backuprestore.GetBRService().RestoreExistCluster(context.Context,
 			cluster.RestoreExistClusterReq{
-				ClusterID: clusterMeta.Cluster.ID,
-				BackupID:  backupID,
+				ClusterID:    clusterMeta.Cluster.ID,
+				BackupID:     backupID,
+				RestorePoint: restorePoint,
 			}, false)
 		if err != nil {
 			framework.LogWithContext(context.Context).Errorf("do restore for cluster %s by backup id %s error: %s", clusterMeta.Cluster.ID, backupID, err.Error())
@@ -2308,20 +2318,27 @@ func checkRegionHealth(node *workflowModel.WorkFlowNode, context *workflow.FlowC
 
 	framework.LogWithContext(context.Context).Infof(
 		"check cluster %s, version %s health", cluster.ID, cluster.Version)
+	if err = checkRegionsHealthy(context.Context, cluster.ID); err != nil {
+		return err
+	}
+
+	node.Record("check all regions are healthy")
+	return nil
+}
+
+func checkRegionsHealthy(ctx context.Context, clusterID string) error {
 	tiupHomeForTidb := framework.GetTiupHomePathForTidb()
-	result, err := deployment.M.CheckCluster(context.Context, deployment.TiUPComponentTypeCluster, cluster.ID,
+	result, err := deployment.M.CheckCluster(ctx, deployment.TiUPComponentTypeCluster, clusterID,
 		tiupHomeForTidb, []string{"--cluster"}, meta.DefaultTiupTimeOut)
 	if err != nil {
-		framework.LogWithContext(context.Context).Errorf(
-			"check cluster %s health error: %s", clusterMeta.Cluster.ID, err.Error())
+		framework.LogWithContext(ctx).Errorf(
+			"check cluster %s health error: %s", clusterID, err.Error())
 		return err
 	}
 
 	if !strings.Contains(result, "All regions are healthy") {
-		return errors.NewErrorf(errors.TIUNIMANAGER_UPGRADE_REGION_UNHEALTHY, "check cluster %s health result: %s", clusterMeta.Cluster.ID, result)
+		return errors.NewErrorf(errors.TIUNIMANAGER_UPGRADE_REGION_UNHEALTHY, "check cluster %s health result: %s", clusterID, result)
 	}
-
-	node.Record("check all regions are healthy")
 	return nil
 }
 
@@ -2416,20 +2433,20 @@ func checkUpgradeVersion(node *workflowModel.WorkFlowNode, context *workflow.Flo
 }
 
 func checkUpgradeMD5(node *workflowModel.WorkFlowNode, context *workflow.FlowContext) error {
-	return nil
+	return executeUpgradeCheck(node, context, checkUpgradeBinaries)
 }
 
 func checkUpgradeTime(node *workflowModel.WorkFlowNode, context *workflow.FlowContext) error {
-	return nil
+	return executeUpgradeCheck(node, context, checkUpgradeDuration)
 }
 
 func checkUpgradeConfig(node *workflowModel.WorkFlowNode, context *workflow.FlowContext) error {
-	return nil
+	return executeUpgradeCheck(node, context, checkUpgradeConfigs)
 }
 
-//func checkSystemHealth(node *workflowModel.WorkFlowNode, context *workflow.FlowContext) error {
-//	return nil
-//}
+func checkSystemHealth(node *workflowModel.WorkFlowNode, context *workflow.FlowContext) error {
+	return executeUpgradeCheck(node, context, checkUpgradeHealth)
+}
 
 func revertConfigAfterFailure(node *workflowModel.WorkFlowNode, context *workflow.FlowContext) error {
 	var clusterMeta meta.ClusterMeta
@@ -1752,7 +1752,7 @@ func TestInitGrafanaAccount(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
-	t.Run("not found grafana", func(t *testing.T) {
+	t.Run("no grafana", func(t *testing.T) {
 		flowContext := workflow.NewFlowContext(context.TODO(), make(map[string]string))
 		flowContext.SetData(ContextClusterMeta, clusterMeta)
 		metadata := &spec.Specification{}
@@ -1760,7 +1760,7 @@ func TestInitGrafanaAccount(t *testing.T) {
 		assert.NoError(t, err)
 		flowContext.SetData(ContextTopology, string(bytes))
 		err = initGrafanaAccount(&workflowModel.WorkFlowNode{}, flowContext)
-		assert.Error(t, err)
+		assert.NoError(t, err)
 	})
 
 	t.Run("create DBUser error", func(t *testing.T) {
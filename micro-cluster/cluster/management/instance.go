@@ -0,0 +1,281 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package management
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/deployment"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/maintainwindow"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	workflowModel "github.com/pingcap/tiunimanager/models/workflow"
+	workflow "github.com/pingcap/tiunimanager/workflow2"
+)
+
+var startInstanceFlow = workflow.WorkFlowDefine{
+	FlowName: constants.FlowStartInstance,
+	TaskNodes: map[string]*workflow.NodeDefine{
+		"start":     {"startInstance", "startDone", "fail", workflow.PollingNode, startInstance, nil},
+		"startDone": {"end", "", "fail", workflow.SyncFuncNode, workflow.CompositeExecutor(setInstanceRunning, persistCluster, endMaintenance), nil},
+		"fail":      {"fail", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(setInstanceFailure, persistCluster, endMaintenance), nil},
+	},
+}
+
+var stopInstanceFlow = workflow.WorkFlowDefine{
+	FlowName: constants.FlowStopInstance,
+	TaskNodes: map[string]*workflow.NodeDefine{
+		"start":    {"stopInstance", "stopDone", "fail", workflow.PollingNode, stopInstance, nil},
+		"stopDone": {"end", "", "fail", workflow.SyncFuncNode, workflow.CompositeExecutor(setInstanceStopped, persistCluster, endMaintenance), nil},
+		"fail":     {"fail", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(setInstanceFailure, persistCluster, endMaintenance), nil},
+	},
+}
+
+var restartInstanceFlow = workflow.WorkFlowDefine{
+	FlowName: constants.FlowRestartInstance,
+	TaskNodes: map[string]*workflow.NodeDefine{
+		"start":       {"restartInstance", "restartDone", "fail", workflow.PollingNode, restartInstance, nil},
+		"restartDone": {"end", "", "fail", workflow.SyncFuncNode, workflow.CompositeExecutor(setInstanceRunning, persistCluster, endMaintenance), nil},
+		"fail":        {"fail", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(setInstanceFailure, persistCluster, endMaintenance), nil},
+	},
+}
+
+var reloadInstanceFlow = workflow.WorkFlowDefine{
+	FlowName: constants.FlowReloadInstance,
+	TaskNodes: map[string]*workflow.NodeDefine{
+		"start":      {"reloadInstance", "reloadDone", "fail", workflow.PollingNode, reloadInstance, nil},
+		"reloadDone": {"end", "", "fail", workflow.SyncFuncNode, workflow.CompositeExecutor(setInstanceRunning, persistCluster, endMaintenance), nil},
+		"fail":       {"fail", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(setInstanceFailure, persistCluster, endMaintenance), nil},
+	},
+}
+
+// StartInstance
+// @Description: start an instance of cluster
+// @Receiver p
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (p *Manager) StartInstance(ctx context.Context, req cluster.StartInstanceReq) (resp cluster.StartInstanceResp, err error) {
+	resp.ClusterID, resp.InstanceID = req.ClusterID, req.InstanceID
+	// starting an instance recovers the cluster, it is not restricted by the maintain window
+	resp.WorkFlowID, _, err = asyncInstanceMaintenance(ctx, req.ClusterID, req.InstanceID, constants.ClusterMaintenanceRestarting, startInstanceFlow.FlowName,
+		"", structs.MaintainWindowOption{}, req)
+	return
+}
+
+// StopInstance
+// @Description: stop an instance of cluster
+// @Receiver p
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (p *Manager) StopInstance(ctx context.Context, req cluster.StopInstanceReq) (resp cluster.StopInstanceResp, err error) {
+	resp.ClusterID, resp.InstanceID = req.ClusterID, req.InstanceID
+	resp.WorkFlowID, resp.PendingOperationID, err = asyncInstanceMaintenance(ctx, req.ClusterID, req.InstanceID, constants.ClusterMaintenanceStopping, stopInstanceFlow.FlowName,
+		constants.PendingOperationStopInstance, req.MaintainWindowOption, req)
+	return
+}
+
+// RestartInstance
+// @Description: restart an instance of cluster
+// @Receiver p
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (p *Manager) RestartInstance(ctx context.Context, req cluster.RestartInstanceReq) (resp cluster.RestartInstanceResp, err error) {
+	resp.ClusterID, resp.InstanceID = req.ClusterID, req.InstanceID
+	resp.WorkFlowID, resp.PendingOperationID, err = asyncInstanceMaintenance(ctx, req.ClusterID, req.InstanceID, constants.ClusterMaintenanceRestarting, restartInstanceFlow.FlowName,
+		constants.PendingOperationRestartInstance, req.MaintainWindowOption, req)
+	return
+}
+
+// ReloadInstance
+// @Description: reload config of an instance of cluster, the instance is restarted
+// @Receiver p
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (p *Manager) ReloadInstance(ctx context.Context, req cluster.ReloadInstanceReq) (resp cluster.ReloadInstanceResp, err error) {
+	resp.ClusterID, resp.InstanceID = req.ClusterID, req.InstanceID
+	resp.WorkFlowID, resp.PendingOperationID, err = asyncInstanceMaintenance(ctx, req.ClusterID, req.InstanceID, constants.ClusterMaintenanceRestarting, reloadInstanceFlow.FlowName,
+		constants.PendingOperationReloadInstance, req.MaintainWindowOption, req)
+	return
+}
+
+// asyncInstanceMaintenance
+// @Description: check the instance and start the workflow operating it, the cluster is under maintenance until the workflow ends.
+// A disruptive operation is admitted by the maintain window of cluster first, it may be rejected or queued
+// @Parameter ctx
+// @Parameter clusterID
+// @Parameter instanceID
+// @Parameter status
+// @Parameter flowName
+// @Parameter operationType empty if the operation is not restricted by the maintain window
+// @Parameter option
+// @Parameter request
+// @return flowID
+// @return pendingOperationID not empty if the operation is queued
+// @return err
+func asyncInstanceMaintenance(ctx context.Context, clusterID string, instanceID string,
+	status constants.ClusterMaintenanceStatus, flowName string,
+	operationType constants.PendingOperationType, option structs.MaintainWindowOption, request interface{}) (flowID string, pendingOperationID string, err error) {
+	clusterMeta, err := meta.Get(ctx, clusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("load cluster %s meta from db error: %s", clusterID, err.Error())
+		return
+	}
+	if err = checkClusterPermission(ctx, clusterMeta, constants.RbacActionUpdate); err != nil {
+		return
+	}
+	instance, err := clusterMeta.GetInstance(ctx, instanceID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("get instance %s of cluster %s error: %s", instanceID, clusterID, err.Error())
+		return
+	}
+	if len(instance.HostIP) == 0 || len(instance.Ports) == 0 {
+		err = errors.NewErrorf(errors.TIUNIMANAGER_PARAMETER_INVALID, "address of instance %s is unknown", instanceID)
+		return
+	}
+	if operationType != "" {
+		pendingOperationID, err = maintainwindow.Admit(ctx, clusterMeta, operationType, option, request)
+		if err != nil || pendingOperationID != "" {
+			return
+		}
+	}
+
+	data := map[string]interface{}{
+		ContextClusterMeta: clusterMeta,
+		ContextInstanceID:  instanceID,
+	}
+	flowID, err = asyncMaintenance(ctx, clusterMeta, status, flowName, data)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("cluster %s async maintenance error: %s", clusterID, err.Error())
+	}
+	return
+}
+
+// instanceNodeFlags
+// @Description: tiup flags which restrict the operation to the instance
+// @Parameter instance
+// @return []string
+func instanceNodeFlags(instance *management.ClusterInstance) []string {
+	role := strings.ToLower(instance.Type)
+	if instance.Type == string(constants.ComponentIDAlertManger) {
+		role = "alertmanager"
+	}
+	return []string{
+		"-N", strings.Join([]string{instance.HostIP[0], strconv.Itoa(int(instance.Ports[0]))}, ":"),
+		"-R", role,
+	}
+}
+
+type instanceOperation func(ctx context.Context, componentType deployment.TiUPComponentType, clusterID, home, workFlowID string, args []string, timeout int) (string, error)
+
+// operateInstance
+// @Description: execute tiup command on the instance in context
+func operateInstance(node *workflowModel.WorkFlowNode, context *workflow.FlowContext, name string, operation instanceOperation) error {
+	var clusterMeta meta.ClusterMeta
+	err := context.GetData(ContextClusterMeta, &clusterMeta)
+	if err != nil {
+		return err
+	}
+	var instanceID string
+	err = context.GetData(ContextInstanceID, &instanceID)
+	if err != nil {
+		return err
+	}
+	instance, err := clusterMeta.GetInstance(context, instanceID)
+	if err != nil {
+		return err
+	}
+
+	flags := instanceNodeFlags(instance)
+	framework.LogWithContext(context).Infof("%s instance %s of cluster %s, flags %v", name, instanceID, clusterMeta.Cluster.ID, flags)
+	operationID, err := operation(context.Context, deployment.TiUPComponentTypeCluster, clusterMeta.Cluster.ID,
+		framework.GetTiupHomePathForTidb(), node.ParentID, flags, meta.DefaultTiupTimeOut)
+	if err != nil {
+		framework.LogWithContext(context).Errorf("%s instance %s of cluster %s error: %s", name, instanceID, clusterMeta.Cluster.ID, err.Error())
+		return err
+	}
+	framework.LogWithContext(context).Infof("get %s instance %s operation id: %s", name, instanceID, operationID)
+
+	node.Record(fmt.Sprintf("%s instance %s %s of cluster %s ", name, instance.Type, flags[1], clusterMeta.Cluster.ID))
+	node.OperationID = operationID
+	return nil
+}
+
+func startInstance(node *workflowModel.WorkFlowNode, context *workflow.FlowContext) error {
+	return operateInstance(node, context, "start", deployment.M.Start)
+}
+
+func stopInstance(node *workflowModel.WorkFlowNode, context *workflow.FlowContext) error {
+	return operateInstance(node, context, "stop", deployment.M.Stop)
+}
+
+func restartInstance(node *workflowModel.WorkFlowNode, context *workflow.FlowContext) error {
+	return operateInstance(node, context, "restart", deployment.M.Restart)
+}
+
+func reloadInstance(node *workflowModel.WorkFlowNode, context *workflow.FlowContext) error {
+	return operateInstance(node, context, "reload", deployment.M.Reload)
+}
+
+// setInstanceStatus
+// @Description: set running status of the instance in context, it is persisted by persistCluster
+func setInstanceStatus(node *workflowModel.WorkFlowNode, context *workflow.FlowContext, status constants.ClusterInstanceRunningStatus) error {
+	var clusterMeta meta.ClusterMeta
+	err := context.GetData(ContextClusterMeta, &clusterMeta)
+	if err != nil {
+		return err
+	}
+	var instanceID string
+	err = context.GetData(ContextInstanceID, &instanceID)
+	if err != nil {
+		return err
+	}
+	instance, err := clusterMeta.GetInstance(context, instanceID)
+	if err != nil {
+		return err
+	}
+	instance.Status = string(status)
+	context.SetData(ContextClusterMeta, &clusterMeta)
+	node.Record(fmt.Sprintf("set instance %s status into %s ", instanceID, status))
+	return nil
+}
+
+func setInstanceRunning(node *workflowModel.WorkFlowNode, context *workflow.FlowContext) error {
+	return setInstanceStatus(node, context, constants.ClusterInstanceRunning)
+}
+
+func setInstanceStopped(node *workflowModel.WorkFlowNode, context *workflow.FlowContext) error {
+	return setInstanceStatus(node, context, constants.ClusterInstanceStopped)
+}
+
+func setInstanceFailure(node *workflowModel.WorkFlowNode, context *workflow.FlowContext) error {
+	return setInstanceStatus(node, context, constants.ClusterInstanceFailure)
+}
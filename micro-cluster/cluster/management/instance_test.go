@@ -0,0 +1,147 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package management
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/deployment"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	"github.com/pingcap/tiunimanager/models/common"
+	workflowModel "github.com/pingcap/tiunimanager/models/workflow"
+	mock_deployment "github.com/pingcap/tiunimanager/test/mockdeployment"
+	"github.com/pingcap/tiunimanager/test/mockmodels/mockclustermanagement"
+	workflow "github.com/pingcap/tiunimanager/workflow2"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockInstanceFlowContext() *workflow.FlowContext {
+	flowContext := workflow.NewFlowContext(context.TODO(), make(map[string]string))
+	flowContext.SetData(ContextClusterMeta, &meta.ClusterMeta{
+		Cluster: &management.Cluster{
+			Entity:  common.Entity{ID: "testCluster"},
+			Version: "v5.0.0",
+		},
+		Instances: map[string][]*management.ClusterInstance{
+			"TiKV": {
+				{
+					Entity: common.Entity{ID: "instance01", Status: string(constants.ClusterInstanceRunning)},
+					Type:   "TiKV",
+					HostIP: []string{"127.0.0.1"},
+					Ports:  []int32{20160},
+				},
+			},
+		},
+	})
+	flowContext.SetData(ContextInstanceID, "instance01")
+	return flowContext
+}
+
+func Test_instanceNodeFlags(t *testing.T) {
+	assert.Equal(t, []string{"-N", "127.0.0.1:4000", "-R", "tidb"}, instanceNodeFlags(&management.ClusterInstance{
+		Type:   string(constants.ComponentIDTiDB),
+		HostIP: []string{"127.0.0.1"},
+		Ports:  []int32{4000, 10080},
+	}))
+	assert.Equal(t, []string{"-N", "127.0.0.1:9093", "-R", "alertmanager"}, instanceNodeFlags(&management.ClusterInstance{
+		Type:   string(constants.ComponentIDAlertManger),
+		HostIP: []string{"127.0.0.1"},
+		Ports:  []int32{9093},
+	}))
+}
+
+func TestRestartInstance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("normal", func(t *testing.T) {
+		mockTiupManager := mock_deployment.NewMockInterface(ctrl)
+		mockTiupManager.EXPECT().Restart(gomock.Any(), gomock.Any(), "testCluster",
+			gomock.Any(), gomock.Any(), []string{"-N", "127.0.0.1:20160", "-R", "tikv"}, gomock.Any()).Return("task01", nil)
+		deployment.M = mockTiupManager
+
+		node := &workflowModel.WorkFlowNode{}
+		err := restartInstance(node, mockInstanceFlowContext())
+		assert.NoError(t, err)
+		assert.Equal(t, "task01", node.OperationID)
+	})
+	t.Run("restart fail", func(t *testing.T) {
+		mockTiupManager := mock_deployment.NewMockInterface(ctrl)
+		mockTiupManager.EXPECT().Restart(gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return("", fmt.Errorf("fail"))
+		deployment.M = mockTiupManager
+
+		err := restartInstance(&workflowModel.WorkFlowNode{}, mockInstanceFlowContext())
+		assert.Error(t, err)
+	})
+	t.Run("instance not found", func(t *testing.T) {
+		flowContext := mockInstanceFlowContext()
+		flowContext.SetData(ContextInstanceID, "instance02")
+		err := restartInstance(&workflowModel.WorkFlowNode{}, flowContext)
+		assert.Error(t, err)
+	})
+}
+
+func TestStopInstance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTiupManager := mock_deployment.NewMockInterface(ctrl)
+	mockTiupManager.EXPECT().Stop(gomock.Any(), gomock.Any(), "testCluster",
+		gomock.Any(), gomock.Any(), []string{"-N", "127.0.0.1:20160", "-R", "tikv"}, gomock.Any()).Return("task01", nil)
+	deployment.M = mockTiupManager
+
+	err := stopInstance(&workflowModel.WorkFlowNode{}, mockInstanceFlowContext())
+	assert.NoError(t, err)
+}
+
+func TestStopInstanceFlow_fail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+	models.SetClusterReaderWriter(clusterRW)
+	clusterRW.EXPECT().UpdateMeta(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	clusterRW.EXPECT().ClearMaintenanceStatus(gomock.Any(), "testCluster", gomock.Any()).Return(nil)
+
+	flowContext := mockInstanceFlowContext()
+	err := stopInstanceFlow.TaskNodes["fail"].Executor(&workflowModel.WorkFlowNode{}, flowContext)
+	assert.NoError(t, err)
+
+	var clusterMeta meta.ClusterMeta
+	assert.NoError(t, flowContext.GetData(ContextClusterMeta, &clusterMeta))
+	instance, err := clusterMeta.GetInstance(context.TODO(), "instance01")
+	assert.NoError(t, err)
+	assert.Equal(t, string(constants.ClusterInstanceFailure), instance.Status)
+}
+
+func TestSetInstanceStatus(t *testing.T) {
+	flowContext := mockInstanceFlowContext()
+	err := setInstanceStopped(&workflowModel.WorkFlowNode{}, flowContext)
+	assert.NoError(t, err)
+
+	var clusterMeta meta.ClusterMeta
+	assert.NoError(t, flowContext.GetData(ContextClusterMeta, &clusterMeta))
+	instance, err := clusterMeta.GetInstance(context.TODO(), "instance01")
+	assert.NoError(t, err)
+	assert.Equal(t, string(constants.ClusterInstanceStopped), instance.Status)
+}
@@ -41,6 +41,7 @@ import (
 	"github.com/pingcap/tiunimanager/library/framework"
 	"github.com/pingcap/tiunimanager/message/cluster"
 	"github.com/pingcap/tiunimanager/micro-cluster/cluster/backuprestore"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/maintainwindow"
 	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
 	"github.com/pingcap/tiunimanager/micro-cluster/resourcemanager/resourcepool"
 	workflow "github.com/pingcap/tiunimanager/workflow2"
@@ -57,6 +58,7 @@ const (
 	ContextSourceClusterMaintenanceStatus = "SourceClusterMaintenanceStatus"
 	ContextCloneStrategy                  = "CloneStrategy"
 	ContextBackupID                       = "BackupID"
+	ContextRestorePoint                   = "RestorePoint"
 	ContextOriginalParamGroupId           = "OriginalParamGroupId"
 	ContextOriginalVersion                = "OriginalVersion"
 	ContextUpgradeVersion                 = "UpgradeVersion"
@@ -70,6 +72,33 @@ const (
 	ContextTakeoverRequest                = "TakeoverRequest"
 	ContextGCLifeTime                     = "GCLifeTime"
 	ContextInstanceTypes                  = "InstanceTypes"
+	ContextRollbackItems                  = "RollbackItems"
+	ContextRecycledClusterID              = "RecycledClusterID"
+)
+
+var (
+	// tiupRetryPolicy retry polling tiup operations failed by transient errors, such as losing the operation status,
+	// the operation itself is submitted only once, see workflow.NodePolicy
+	tiupRetryPolicy = &workflow.NodePolicy{
+		MaxAttempts: 3,
+		Backoff:     10 * time.Second,
+		MaxBackoff:  time.Minute,
+		Retryable:   workflow.RetryableErrorCodes(errors.TIUNIMANAGER_TASK_FAILED),
+	}
+	// connectRetryPolicy retry idempotent nodes which request TiDB or PD just started, such as PD http api timeout
+	connectRetryPolicy = &workflow.NodePolicy{
+		MaxAttempts: 5,
+		Backoff:     5 * time.Second,
+		MaxBackoff:  30 * time.Second,
+	}
+	// scaleOutTimeoutPolicy fail the scaling out node which is stuck, longer than timeout of the tiup command
+	scaleOutTimeoutPolicy = &workflow.NodePolicy{
+		Timeout: 30 * time.Minute,
+	}
+	// upgradeTimeoutPolicy fail the upgrading node which is stuck, longer than timeout of the tiup command
+	upgradeTimeoutPolicy = &workflow.NodePolicy{
+		Timeout: 2 * time.Hour,
+	}
 )
 
 type Manager struct{}
@@ -83,27 +112,66 @@ func NewClusterManager() *Manager {
 	workflowManager.RegisterWorkFlow(context.TODO(), constants.FlowDeleteCluster, &deleteClusterFlow)
 	workflowManager.RegisterWorkFlow(context.TODO(), constants.FlowRestartCluster, &restartClusterFlow)
 	workflowManager.RegisterWorkFlow(context.TODO(), constants.FlowStopCluster, &stopClusterFlow)
+	workflowManager.RegisterWorkFlow(context.TODO(), constants.FlowRecycleCluster, &recycleClusterFlow)
 	workflowManager.RegisterWorkFlow(context.TODO(), constants.FlowOnlineInPlaceUpgradeCluster, &onlineInPlaceUpgradeClusterFlow)
 	workflowManager.RegisterWorkFlow(context.TODO(), constants.FlowOfflineInPlaceUpgradeCluster, &offlineInPlaceUpgradeClusterFlow)
+	workflowManager.RegisterWorkFlow(context.TODO(), constants.FlowRollbackUpgradeCluster, &rollbackUpgradeClusterFlow)
 	workflowManager.RegisterWorkFlow(context.TODO(), constants.FlowCloneCluster, &cloneDefine)
 	workflowManager.RegisterWorkFlow(context.TODO(), constants.FlowTakeoverCluster, &takeoverClusterFlow)
+	workflowManager.RegisterWorkFlow(context.TODO(), constants.FlowStartInstance, &startInstanceFlow)
+	workflowManager.RegisterWorkFlow(context.TODO(), constants.FlowStopInstance, &stopInstanceFlow)
+	workflowManager.RegisterWorkFlow(context.TODO(), constants.FlowRestartInstance, &restartInstanceFlow)
+	workflowManager.RegisterWorkFlow(context.TODO(), constants.FlowReloadInstance, &reloadInstanceFlow)
+	workflowManager.RegisterWorkFlow(context.TODO(), constants.FlowMigrateInstance, &migrateInstanceFlow)
+
+	manager := &Manager{}
+	manager.registerPendingOperations()
+	backuprestore.RegisterTestRestoreClusterExecutor(manager)
+	return manager
+}
 
-	return &Manager{}
+// CreateTestRestoreCluster
+// @Description: restore a backup into a new temporary cluster to verify the backup
+// @Receiver p
+// @Parameter ctx
+// @Parameter req
+// @return clusterID
+// @return workflowID
+// @return err
+func (p *Manager) CreateTestRestoreCluster(ctx context.Context, req cluster.RestoreNewClusterReq) (clusterID string, workflowID string, err error) {
+	resp, err := p.RestoreNewCluster(ctx, req)
+	return resp.ClusterID, resp.WorkFlowID, err
+}
+
+// DestroyTestRestoreCluster
+// @Description: delete the temporary cluster of verifying a backup without backup
+// @Receiver p
+// @Parameter ctx
+// @Parameter clusterID
+// @return workflowID
+// @return err
+func (p *Manager) DestroyTestRestoreCluster(ctx context.Context, clusterID string) (workflowID string, err error) {
+	resp, err := p.DeleteCluster(ctx, cluster.DeleteClusterReq{
+		ClusterID: clusterID,
+		Force:     true,
+	})
+	return resp.WorkFlowID, err
 }
 
 var scaleOutDefine = workflow.WorkFlowDefine{
-	FlowName: constants.FlowScaleOutCluster,
+	FlowName:     constants.FlowScaleOutCluster,
+	DeferFailure: true,
 	TaskNodes: map[string]*workflow.NodeDefine{
-		"start":            {"prepareResource", "resourceDone", "fail", workflow.SyncFuncNode, prepareResource},
-		"resourceDone":     {"buildConfig", "configDone", "fail", workflow.SyncFuncNode, buildConfig},
-		"configDone":       {"scaleOutCluster", "scaleOutDone", "fail", workflow.PollingNode, scaleOutCluster},
-		"scaleOutDone":     {"syncTopology", "syncTopologyDone", "fail", workflow.SyncFuncNode, syncTopology},
-		"syncTopologyDone": {"getTypes", "getTypesDone", "fail", workflow.SyncFuncNode, getFirstScaleOutTypes},
-		"getTypesDone":     {"setClusterOnline", "onlineDone", "fail", workflow.SyncFuncNode, setClusterOnline},
-		"onlineDone":       {"updateClusterParameters", "updateDone", "failAfterScale", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, updateClusterParameters)},
-		"updateDone":       {"end", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, endMaintenance, asyncBuildLog)},
-		"fail":             {"fail", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(revertResourceAfterFailure, endMaintenance)},
-		"failAfterScale":   {"failAfterScale", "", "", workflow.SyncFuncNode, endMaintenance},
+		"start":            {"prepareResource", "resourceDone", "fail", workflow.SyncFuncNode, prepareResource, nil},
+		"resourceDone":     {"buildConfig", "configDone", "fail", workflow.SyncFuncNode, buildConfig, nil},
+		"configDone":       {"scaleOutCluster", "scaleOutDone", "fail", workflow.PollingNode, scaleOutCluster, scaleOutTimeoutPolicy},
+		"scaleOutDone":     {"syncTopology", "syncTopologyDone", "fail", workflow.SyncFuncNode, syncTopology, nil},
+		"syncTopologyDone": {"getTypes", "getTypesDone", "fail", workflow.SyncFuncNode, getFirstScaleOutTypes, nil},
+		"getTypesDone":     {"setClusterOnline", "onlineDone", "fail", workflow.SyncFuncNode, setClusterOnline, nil},
+		"onlineDone":       {"updateClusterParameters", "updateDone", "failAfterScale", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, updateClusterParameters), connectRetryPolicy},
+		"updateDone":       {"end", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, endMaintenance, asyncBuildLog), nil},
+		"fail":             {"fail", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(revertResourceAfterFailure, endMaintenance), nil},
+		"failAfterScale":   {"failAfterScale", "", "", workflow.SyncFuncNode, endMaintenance, nil},
 	},
 }
 
@@ -120,6 +188,9 @@ func (p *Manager) ScaleOut(ctx context.Context, request cluster.ScaleOutClusterR
 			"load cluster %s meta from db error: %s", request.ClusterID, err.Error())
 		return
 	}
+	if err = checkClusterPermission(ctx, clusterMeta, constants.RbacActionUpdate); err != nil {
+		return
+	}
 
 	// When scale out TiFlash, Judge whether enable-placement-rules is true
 	err = meta.ScaleOutPreCheck(ctx, clusterMeta, request.InstanceResource)
@@ -138,7 +209,8 @@ func (p *Manager) ScaleOut(ctx context.Context, request cluster.ScaleOutClusterR
 
 	// Update cluster maintenance status and async start workflow
 	data := map[string]interface{}{
-		ContextClusterMeta: clusterMeta,
+		ContextClusterMeta:           clusterMeta,
+		workflow.ContextDeferFailure: request.DeferFailure,
 	}
 	flowID, err := asyncMaintenance(ctx, clusterMeta, constants.ClusterMaintenanceScaleOut, scaleOutDefine.FlowName, data)
 	if err != nil {
@@ -156,11 +228,11 @@ func (p *Manager) ScaleOut(ctx context.Context, request cluster.ScaleOutClusterR
 var scaleInDefine = workflow.WorkFlowDefine{
 	FlowName: constants.FlowScaleInCluster,
 	TaskNodes: map[string]*workflow.NodeDefine{
-		"start":       {"scaleInCluster", "scaleInDone", "fail", workflow.PollingNode, scaleInCluster},
-		"scaleInDone": {"checkInstanceStatus", "checkDone", "fail", workflow.SyncFuncNode, checkInstanceStatus},
-		"checkDone":   {"freeInstanceResource", "freeDone", "fail", workflow.SyncFuncNode, freeInstanceResource},
-		"freeDone":    {"end", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, endMaintenance)},
-		"fail":        {"fail", "", "", workflow.SyncFuncNode, endMaintenance},
+		"start":       {"scaleInCluster", "scaleInDone", "fail", workflow.PollingNode, scaleInCluster, nil},
+		"scaleInDone": {"checkInstanceStatus", "checkDone", "fail", workflow.SyncFuncNode, checkInstanceStatus, nil},
+		"checkDone":   {"freeInstanceResource", "freeDone", "fail", workflow.SyncFuncNode, freeInstanceResource, nil},
+		"freeDone":    {"end", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, endMaintenance), nil},
+		"fail":        {"fail", "", "", workflow.SyncFuncNode, endMaintenance, nil},
 	},
 }
 
@@ -177,6 +249,9 @@ func (p *Manager) ScaleIn(ctx context.Context, request cluster.ScaleInClusterReq
 			"load cluster %s meta from db error: %s", request.ClusterID, err.Error())
 		return
 	}
+	if err = checkClusterPermission(ctx, clusterMeta, constants.RbacActionUpdate); err != nil {
+		return
+	}
 
 	// Judge whether the instance exists
 	instance, err := clusterMeta.GetInstance(ctx, request.InstanceID)
@@ -214,31 +289,32 @@ func (p *Manager) ScaleIn(ctx context.Context, request cluster.ScaleInClusterReq
 }
 
 var cloneDefine = workflow.WorkFlowDefine{
-	FlowName: constants.FlowCloneCluster,
+	FlowName:     constants.FlowCloneCluster,
+	DeferFailure: true,
 	TaskNodes: map[string]*workflow.NodeDefine{
-		"start":                   {"prepareResource", "resourceDone", "fail", workflow.SyncFuncNode, prepareResource},
-		"resourceDone":            {"modifySourceClusterGCTime", "modifyGCTimeDone", "fail", workflow.SyncFuncNode, modifySourceClusterGCTime},
-		"modifyGCTimeDone":        {"backupSourceCluster", "backupDone", "fail", workflow.SyncFuncNode, backupSourceCluster},
-		"backupDone":              {"waitBackup", "waitBackupDone", "fail", workflow.SyncFuncNode, waitWorkFlow},
-		"waitBackupDone":          {"buildConfig", "configDone", "fail", workflow.SyncFuncNode, buildConfig},
-		"configDone":              {"deployCluster", "deployDone", "fail", workflow.PollingNode, deployCluster},
-		"deployDone":              {"syncConnectionKey", "syncConnectionKeyDone", "failAfterDeploy", workflow.SyncFuncNode, syncConnectionKey},
-		"syncConnectionKeyDone":   {"syncTopology", "syncTopologyDone", "failAfterDeploy", workflow.SyncFuncNode, syncTopology},
-		"syncTopologyDone":        {"startCluster", "startDone", "fail", workflow.PollingNode, startCluster},
-		"startDone":               {"setClusterOnline", "onlineDone", "failAfterDeploy", workflow.SyncFuncNode, setClusterOnline},
-		"onlineDone":              {"initRootAccount", "initRootAccountDone", "failAfterDeploy", workflow.SyncFuncNode, initRootAccount},
-		"initRootAccountDone":     {"initAccount", "initAccountDone", "failAfterDeploy", workflow.SyncFuncNode, initDatabaseAccount},
-		"initAccountDone":         {"applyParameterGroup", "applyParameterGroupDone", "failAfterDeploy", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, applyParameterGroup)},
-		"applyParameterGroupDone": {"syncBackupStrategy", "syncBackupStrategyDone", "failAfterDeploy", workflow.SyncFuncNode, syncBackupStrategy},
-		"syncBackupStrategyDone":  {"syncParameters", "syncParametersDone", "failAfterDeploy", workflow.SyncFuncNode, syncParameters},
-		"syncParametersDone":      {"waitSyncParam", "waitSyncParamDone", "failAfterDeploy", workflow.SyncFuncNode, waitWorkFlow},
-		"waitSyncParamDone":       {"adjustParameters", "initParametersDone", "failAfterDeploy", workflow.SyncFuncNode, adjustParameters},
-		"initParametersDone":      {"restoreCluster", "restoreClusterDone", "failAfterDeploy", workflow.SyncFuncNode, restoreCluster},
-		"restoreClusterDone":      {"waitRestore", "waitRestoreDone", "failAfterDeploy", workflow.SyncFuncNode, waitWorkFlow},
-		"waitRestoreDone":         {"syncIncrData", "syncIncrDataDone", "failAfterDeploy", workflow.SyncFuncNode, syncIncrData},
-		"syncIncrDataDone":        {"end", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(recoverSourceClusterGCTime, persistCluster, endMaintenance, asyncBuildLog)},
-		"fail":                    {"fail", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(recoverSourceClusterGCTime, setClusterFailure, revertResourceAfterFailure, endMaintenance)},
-		"failAfterDeploy":         {"failAfterDeploy", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(recoverSourceClusterGCTime, setClusterFailure, endMaintenance)},
+		"start":                   {"prepareResource", "resourceDone", "fail", workflow.SyncFuncNode, prepareResource, nil},
+		"resourceDone":            {"modifySourceClusterGCTime", "modifyGCTimeDone", "fail", workflow.SyncFuncNode, modifySourceClusterGCTime, nil},
+		"modifyGCTimeDone":        {"backupSourceCluster", "backupDone", "fail", workflow.SyncFuncNode, backupSourceCluster, nil},
+		"backupDone":              {"waitBackup", "waitBackupDone", "fail", workflow.SyncFuncNode, waitWorkFlow, nil},
+		"waitBackupDone":          {"buildConfig", "configDone", "fail", workflow.SyncFuncNode, buildConfig, nil},
+		"configDone":              {"deployCluster", "deployDone", "fail", workflow.PollingNode, deployCluster, nil},
+		"deployDone":              {"syncConnectionKey", "syncConnectionKeyDone", "failAfterDeploy", workflow.SyncFuncNode, syncConnectionKey, nil},
+		"syncConnectionKeyDone":   {"syncTopology", "syncTopologyDone", "failAfterDeploy", workflow.SyncFuncNode, syncTopology, nil},
+		"syncTopologyDone":        {"startCluster", "startDone", "fail", workflow.PollingNode, startCluster, nil},
+		"startDone":               {"setClusterOnline", "onlineDone", "failAfterDeploy", workflow.SyncFuncNode, setClusterOnline, nil},
+		"onlineDone":              {"initRootAccount", "initRootAccountDone", "failAfterDeploy", workflow.SyncFuncNode, initRootAccount, nil},
+		"initRootAccountDone":     {"initAccount", "initAccountDone", "failAfterDeploy", workflow.SyncFuncNode, initDatabaseAccount, nil},
+		"initAccountDone":         {"applyParameterGroup", "applyParameterGroupDone", "failAfterDeploy", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, applyParameterGroup), nil},
+		"applyParameterGroupDone": {"syncBackupStrategy", "syncBackupStrategyDone", "failAfterDeploy", workflow.SyncFuncNode, syncBackupStrategy, nil},
+		"syncBackupStrategyDone":  {"syncParameters", "syncParametersDone", "failAfterDeploy", workflow.SyncFuncNode, syncParameters, nil},
+		"syncParametersDone":      {"waitSyncParam", "waitSyncParamDone", "failAfterDeploy", workflow.SyncFuncNode, waitWorkFlow, nil},
+		"waitSyncParamDone":       {"adjustParameters", "initParametersDone", "failAfterDeploy", workflow.SyncFuncNode, adjustParameters, nil},
+		"initParametersDone":      {"restoreCluster", "restoreClusterDone", "failAfterDeploy", workflow.SyncFuncNode, restoreCluster, nil},
+		"restoreClusterDone":      {"waitRestore", "waitRestoreDone", "failAfterDeploy", workflow.SyncFuncNode, waitWorkFlow, nil},
+		"waitRestoreDone":         {"syncIncrData", "syncIncrDataDone", "failAfterDeploy", workflow.SyncFuncNode, syncIncrData, nil},
+		"syncIncrDataDone":        {"end", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(recoverSourceClusterGCTime, persistCluster, endMaintenance, asyncBuildLog), nil},
+		"fail":                    {"fail", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(recoverSourceClusterGCTime, setClusterFailure, revertResourceAfterFailure, endMaintenance), nil},
+		"failAfterDeploy":         {"failAfterDeploy", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(recoverSourceClusterGCTime, setClusterFailure, endMaintenance), nil},
 	},
 }
 
@@ -255,6 +331,9 @@ func (p *Manager) Clone(ctx context.Context, request cluster.CloneClusterReq) (r
 			"load source cluster %s meta from db error: %s", request.SourceClusterID, err.Error())
 		return
 	}
+	if err = checkClusterPermission(ctx, sourceClusterMeta, constants.RbacActionRead); err != nil {
+		return
+	}
 
 	// Clone source cluster meta to get cluster topology
 	clusterMeta, err := sourceClusterMeta.CloneMeta(ctx, request.CreateClusterParameter,
@@ -271,6 +350,7 @@ func (p *Manager) Clone(ctx context.Context, request cluster.CloneClusterReq) (r
 		ContextSourceClusterMeta:              sourceClusterMeta,
 		ContextCloneStrategy:                  request.CloneStrategy,
 		ContextSourceClusterMaintenanceStatus: constants.ClusterMaintenanceBeingCloned,
+		workflow.ContextDeferFailure:          request.DeferFailure,
 	}
 	flowID, err := asyncMaintenance(ctx, clusterMeta, constants.ClusterMaintenanceCloning, cloneDefine.FlowName, data)
 	if err != nil {
@@ -286,25 +366,36 @@ func (p *Manager) Clone(ctx context.Context, request cluster.CloneClusterReq) (r
 }
 
 var createClusterFlow = workflow.WorkFlowDefine{
-	FlowName: constants.FlowCreateCluster,
+	FlowName:     constants.FlowCreateCluster,
+	DeferFailure: true,
 	TaskNodes: map[string]*workflow.NodeDefine{
-		"start":                   {"prepareResource", "resourceDone", "fail", workflow.SyncFuncNode, prepareResource},
-		"resourceDone":            {"buildConfig", "configDone", "fail", workflow.SyncFuncNode, buildConfig},
-		"configDone":              {"deployCluster", "deployDone", "fail", workflow.PollingNode, deployCluster},
-		"deployDone":              {"syncConnectionKey", "syncConnectionKeyDone", "failAfterDeploy", workflow.SyncFuncNode, syncConnectionKey},
-		"syncConnectionKeyDone":   {"syncTopology", "syncTopologyDone", "failAfterDeploy", workflow.SyncFuncNode, syncTopology},
-		"syncTopologyDone":        {"startupCluster", "startupDone", "failAfterDeploy", workflow.PollingNode, startCluster},
-		"startupDone":             {"setClusterOnline", "onlineDone", "failAfterDeploy", workflow.SyncFuncNode, setClusterOnline},
-		"onlineDone":              {"initRootAccount", "initRootAccountDone", "failAfterDeploy", workflow.SyncFuncNode, initRootAccount},
-		"initRootAccountDone":     {"initDatabaseAccount", "initDatabaseAccountDone", "failAfterDeploy", workflow.SyncFuncNode, initDatabaseAccount},
-		"initDatabaseAccountDone": {"applyParameterGroup", "applyParameterGroupDone", "failAfterDeploy", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, applyParameterGroup)},
-		"applyParameterGroupDone": {"adjustParameters", "initParametersDone", "failAfterDeploy", workflow.SyncFuncNode, adjustParameters},
-		"initParametersDone":      {"testConnectivity", "testConnectivityDone", "failAfterDeploy", workflow.SyncFuncNode, testConnectivity},
-		"testConnectivityDone":    {"initDatabaseData", "initDataDone", "failAfterDeploy", workflow.SyncFuncNode, initDatabaseData},
-		"initDataDone":            {"waitInitDatabaseData", "success", "failAfterDeploy", workflow.SyncFuncNode, waitInitDatabaseData},
-		"success":                 {"end", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, endMaintenance, asyncBuildLog)},
-		"fail":                    {"fail", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(setClusterFailure, revertResourceAfterFailure, endMaintenance)},
-		"failAfterDeploy":         {"failAfterDeploy", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(setClusterFailure, endMaintenance)},
+		"start":                   {"prepareResource", "resourceDone", "fail", workflow.SyncFuncNode, prepareResource, nil},
+		"resourceDone":            {"buildConfig", "configDone", "fail", workflow.SyncFuncNode, buildConfig, nil},
+		"configDone":              {"deployCluster", "deployDone", "fail", workflow.PollingNode, deployCluster, nil},
+		"deployDone":              {"syncConnectionKey", "syncConnectionKeyDone", "failAfterDeploy", workflow.SyncFuncNode, syncConnectionKey, nil},
+		"syncConnectionKeyDone":   {"syncTopology", "syncTopologyDone", "failAfterDeploy", workflow.SyncFuncNode, syncTopology, nil},
+		"syncTopologyDone":        {"startupCluster", "startupDone", "failAfterDeploy", workflow.PollingNode, startCluster, tiupRetryPolicy},
+		"startupDone":             {"setClusterOnline", "onlineDone", "failAfterDeploy", workflow.SyncFuncNode, setClusterOnline, nil},
+		"onlineDone":              {"initRootAccount", "initRootAccountDone", "failAfterDeploy", workflow.SyncFuncNode, initRootAccount, nil},
+		"initRootAccountDone":     {"initAccounts", "initAccountsDone", "failAfterDeploy", workflow.ParallelNode, nil, nil},
+		"initAccountsDone":        {"applyParameterGroup", "applyParameterGroupDone", "failAfterDeploy", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, applyParameterGroup), nil},
+		"applyParameterGroupDone": {"adjustParameters", "initParametersDone", "failAfterDeploy", workflow.SyncFuncNode, adjustParameters, connectRetryPolicy},
+		"initParametersDone":      {"testConnectivity", "testConnectivityDone", "failAfterDeploy", workflow.SyncFuncNode, testConnectivity, connectRetryPolicy},
+		"testConnectivityDone":    {"initDatabaseData", "initDataDone", "failAfterDeploy", workflow.SyncFuncNode, initDatabaseData, nil},
+		"initDataDone":            {"waitInitDatabaseData", "success", "failAfterDeploy", workflow.SyncFuncNode, waitInitDatabaseData, nil},
+		"success":                 {"end", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, endMaintenance, asyncBuildLog), nil},
+		"fail":                    {"fail", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(setClusterFailure, revertResourceAfterFailure, endMaintenance), nil},
+		"failAfterDeploy":         {"failAfterDeploy", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(setClusterFailure, endMaintenance), nil},
+	},
+	Branches: map[string][]*workflow.BranchDefine{
+		"initAccounts": {
+			{Name: "database", TaskNodes: map[string]*workflow.NodeDefine{
+				"start": {"initDatabaseAccount", "", "", workflow.SyncFuncNode, initDatabaseAccount, nil},
+			}},
+			{Name: "grafana", TaskNodes: map[string]*workflow.NodeDefine{
+				"start": {"initGrafanaAccount", "", "", workflow.SyncFuncNode, initGrafanaAccount, nil},
+			}},
+		},
 	},
 }
 
@@ -335,7 +426,8 @@ func (p *Manager) CreateCluster(ctx context.Context, req cluster.CreateClusterRe
 	}
 
 	data := map[string]interface{}{
-		ContextClusterMeta: meta,
+		ContextClusterMeta:           meta,
+		workflow.ContextDeferFailure: req.DeferFailure,
 	}
 	flowID, err := asyncMaintenance(ctx, meta, constants.ClusterMaintenanceCreating, createClusterFlow.FlowName, data)
 	if err != nil {
@@ -350,7 +442,7 @@ func (p *Manager) CreateCluster(ctx context.Context, req cluster.CreateClusterRe
 }
 
 // RestoreNewCluster
-// @Description: restore a new cluster by backup record
+// @Description: restore a new cluster by backup record, or to a point in time by full backup and logs
 // @Receiver m
 // @Parameter ctx
 // @Parameter request
@@ -378,8 +470,9 @@ func (p *Manager) RestoreNewCluster(ctx context.Context, req cluster.RestoreNewC
 	}
 
 	data := map[string]interface{}{
-		ContextClusterMeta: meta,
-		ContextBackupID:    req.BackupID,
+		ContextClusterMeta:  meta,
+		ContextBackupID:     req.BackupID,
+		ContextRestorePoint: req.RestorePoint,
 	}
 	flowID, err := asyncMaintenance(ctx, meta, constants.ClusterMaintenanceCreating, createClusterFlow.FlowName, data)
 	if err != nil {
@@ -493,6 +586,9 @@ func (p *Manager) PreviewScaleOutCluster(ctx context.Context, req cluster.ScaleO
 	if err != nil {
 		return
 	}
+	if err = checkClusterPermission(ctx, clusterMeta, constants.RbacActionRead); err != nil {
+		return
+	}
 
 	// todo validate
 	resp = cluster.PreviewClusterResp{
@@ -517,10 +613,10 @@ func (p *Manager) PreviewScaleOutCluster(ctx context.Context, req cluster.ScaleO
 var stopClusterFlow = workflow.WorkFlowDefine{
 	FlowName: constants.FlowStopCluster,
 	TaskNodes: map[string]*workflow.NodeDefine{
-		"start":       {"clusterStop", "stopDone", "fail", workflow.PollingNode, stopCluster},
-		"stopDone":    {"setClusterOffline", "offlineDone", "fail", workflow.SyncFuncNode, setClusterOffline},
-		"offlineDone": {"end", "", "fail", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, endMaintenance)},
-		"fail":        {"fail", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(setClusterFailure, endMaintenance)},
+		"start":       {"clusterStop", "stopDone", "fail", workflow.PollingNode, stopCluster, nil},
+		"stopDone":    {"setClusterOffline", "offlineDone", "fail", workflow.SyncFuncNode, setClusterOffline, nil},
+		"offlineDone": {"end", "", "fail", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, endMaintenance), nil},
+		"fail":        {"fail", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(setClusterFailure, endMaintenance), nil},
 	},
 }
 
@@ -531,6 +627,9 @@ func (p *Manager) StopCluster(ctx context.Context, req cluster.StopClusterReq) (
 			"load cluster %s meta from db error: %s", req.ClusterID, err.Error())
 		return
 	}
+	if err = checkClusterPermission(ctx, meta, constants.RbacActionUpdate); err != nil {
+		return
+	}
 
 	data := map[string]interface{}{
 		ContextClusterMeta: meta,
@@ -550,14 +649,14 @@ func (p *Manager) StopCluster(ctx context.Context, req cluster.StopClusterReq) (
 var deleteClusterFlow = workflow.WorkFlowDefine{
 	FlowName: constants.FlowDeleteCluster,
 	TaskNodes: map[string]*workflow.NodeDefine{
-		"start":              {"backupBeforeDelete", "backupDone", "revert", workflow.SyncFuncNode, backupBeforeDelete},
-		"backupDone":         {"destroyCluster", "destroyClusterDone", "fail", workflow.PollingNode, destroyCluster},
-		"destroyClusterDone": {"freedClusterResource", "freedResourceDone", "fail", workflow.SyncFuncNode, freedClusterResource},
-		"freedResourceDone":  {"clearBackupData", "clearBackupDone", "fail", workflow.SyncFuncNode, clearBackupData},
-		"clearBackupDone":    {"clearCDCLinks", "clearLinkDone", "fail", workflow.SyncFuncNode, clearCDCLinks},
-		"clearLinkDone":      {"end", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(deleteCluster)},
-		"fail":               {"fail", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(setClusterFailure, endMaintenance)},
-		"revert":             {"revert", "", "", workflow.SyncFuncNode, endMaintenance},
+		"start":              {"backupBeforeDelete", "backupDone", "revert", workflow.SyncFuncNode, backupBeforeDelete, nil},
+		"backupDone":         {"destroyCluster", "destroyClusterDone", "fail", workflow.PollingNode, destroyCluster, nil},
+		"destroyClusterDone": {"freedClusterResource", "freedResourceDone", "fail", workflow.SyncFuncNode, freedClusterResource, nil},
+		"freedResourceDone":  {"clearBackupData", "clearBackupDone", "fail", workflow.SyncFuncNode, clearBackupData, nil},
+		"clearBackupDone":    {"clearCDCLinks", "clearLinkDone", "fail", workflow.SyncFuncNode, clearCDCLinks, nil},
+		"clearLinkDone":      {"end", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(deleteCluster), nil},
+		"fail":               {"fail", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(setClusterFailure, endMaintenance), nil},
+		"revert":             {"revert", "", "", workflow.SyncFuncNode, endMaintenance, nil},
 	},
 }
 
@@ -568,14 +667,29 @@ func (p *Manager) DeleteCluster(ctx context.Context, req cluster.DeleteClusterRe
 			"load cluster %s meta from db error: %s", req.ClusterID, err.Error())
 		return
 	}
+	if err = checkClusterPermission(ctx, meta, constants.RbacActionDelete); err != nil {
+		return
+	}
 
 	resp.ClusterID = meta.Cluster.ID
 
+	if meta.Cluster.DeletionProtection {
+		err = errors.NewErrorf(errors.TIUNIMANAGER_CLUSTER_DELETION_PROTECTED,
+			"cluster %s is protected from deletion, disable the protection first", meta.Cluster.ID)
+		return
+	}
+	// the cluster in the recycle bin is purged at once
+	if meta.Cluster.MaintenanceStatus == constants.ClusterMaintenanceRecycled {
+		return p.purgeRecycledCluster(ctx, meta)
+	}
 	if len(meta.Cluster.MaintenanceStatus) > 0 && !req.Force {
 		msg := fmt.Sprintf("cluster maintenance status is '%s'", string(meta.Cluster.MaintenanceStatus))
 		err = errors.NewError(errors.TIUNIMANAGER_CLUSTER_MAINTENANCE_CONFLICT, msg)
 		return
 	}
+	if req.SoftDelete {
+		return p.recycleCluster(ctx, meta, req)
+	}
 
 	data := map[string]interface{}{
 		ContextClusterMeta:   meta,
@@ -596,20 +710,20 @@ func (p *Manager) DeleteCluster(ctx context.Context, req cluster.DeleteClusterRe
 var startClusterFlow = workflow.WorkFlowDefine{
 	FlowName: constants.FlowRestartCluster,
 	TaskNodes: map[string]*workflow.NodeDefine{
-		"start":      {"startCluster", "startDone", "fail", workflow.PollingNode, startCluster},
-		"startDone":  {"setClusterOnline", "onlineDone", "fail", workflow.SyncFuncNode, setClusterOnline},
-		"onlineDone": {"end", "", "fail", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, endMaintenance)},
-		"fail":       {"fail", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(setClusterFailure, endMaintenance)},
+		"start":      {"startCluster", "startDone", "fail", workflow.PollingNode, startCluster, nil},
+		"startDone":  {"setClusterOnline", "onlineDone", "fail", workflow.SyncFuncNode, setClusterOnline, nil},
+		"onlineDone": {"end", "", "fail", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, endMaintenance), nil},
+		"fail":       {"fail", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(setClusterFailure, endMaintenance), nil},
 	},
 }
 
 var restartClusterFlow = workflow.WorkFlowDefine{
 	FlowName: constants.FlowRestartCluster,
 	TaskNodes: map[string]*workflow.NodeDefine{
-		"start":      {"restartCluster", "startDone", "fail", workflow.PollingNode, restartCluster},
-		"startDone":  {"setClusterOnline", "onlineDone", "fail", workflow.SyncFuncNode, setClusterOnline},
-		"onlineDone": {"end", "", "fail", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, endMaintenance, asyncBuildLog)},
-		"fail":       {"fail", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(setClusterFailure, endMaintenance)},
+		"start":      {"restartCluster", "startDone", "fail", workflow.PollingNode, restartCluster, nil},
+		"startDone":  {"setClusterOnline", "onlineDone", "fail", workflow.SyncFuncNode, setClusterOnline, nil},
+		"onlineDone": {"end", "", "fail", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, endMaintenance, asyncBuildLog), nil},
+		"fail":       {"fail", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(setClusterFailure, endMaintenance), nil},
 	},
 }
 
@@ -620,6 +734,15 @@ func (p *Manager) RestartCluster(ctx context.Context, req cluster.RestartCluster
 			"load cluster %s meta from db error: %s", req.ClusterID, err.Error())
 		return
 	}
+	if err = checkClusterPermission(ctx, meta, constants.RbacActionUpdate); err != nil {
+		return
+	}
+	pendingOperationID, err := maintainwindow.Admit(ctx, meta, constants.PendingOperationRestartCluster, req.MaintainWindowOption, req)
+	if err != nil || pendingOperationID != "" {
+		resp.ClusterID = meta.Cluster.ID
+		resp.PendingOperationID = pendingOperationID
+		return
+	}
 
 	data := map[string]interface{}{
 		ContextClusterMeta: meta,
@@ -646,17 +769,17 @@ func (p *Manager) RestartCluster(ctx context.Context, req cluster.RestartCluster
 var takeoverClusterFlow = workflow.WorkFlowDefine{
 	FlowName: constants.FlowTakeoverCluster,
 	TaskNodes: map[string]*workflow.NodeDefine{
-		"start":                   {"fetchTopologyFile", "fetched", "revert", workflow.SyncFuncNode, fetchTopologyFile},
-		"fetched":                 {"rebuildTopologyFromConfig", "built", "revert", workflow.SyncFuncNode, rebuildTopologyFromConfig},
-		"built":                   {"testConnectivity", "testConnectivityPassed", "revert", workflow.SyncFuncNode, testConnectivity},
-		"testConnectivityPassed":  {"validateHostsStatus", "hostReady", "revert", workflow.SyncFuncNode, validateHostsStatus},
-		"hostReady":               {"takeoverResource", "resourceDone", "revert", workflow.SyncFuncNode, takeoverResource},
-		"resourceDone":            {"rebuildTiupSpaceForCluster", "workingSpaceDone", "revertWithResource", workflow.SyncFuncNode, rebuildTiupSpaceForCluster},
-		"workingSpaceDone":        {"applyParameterGroup", "applyParameterGroupDone", "revertWithResource", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, applyParameterGroupForTakeover)},
-		"applyParameterGroupDone": {"initDatabaseAccount", "success", "revertWithResource", workflow.SyncFuncNode, initDatabaseAccount},
-		"success":                 {"end", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, endMaintenance, asyncBuildLog)},
-		"revert":                  {"revert", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(takeoverRevertMeta)},
-		"revertWithResource":      {"revertWithResource", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(revertResourceAfterFailure, takeoverRevertMeta)},
+		"start":                   {"fetchTopologyFile", "fetched", "revert", workflow.SyncFuncNode, fetchTopologyFile, nil},
+		"fetched":                 {"rebuildTopologyFromConfig", "built", "revert", workflow.SyncFuncNode, rebuildTopologyFromConfig, nil},
+		"built":                   {"testConnectivity", "testConnectivityPassed", "revert", workflow.SyncFuncNode, testConnectivity, nil},
+		"testConnectivityPassed":  {"validateHostsStatus", "hostReady", "revert", workflow.SyncFuncNode, validateHostsStatus, nil},
+		"hostReady":               {"takeoverResource", "resourceDone", "revert", workflow.SyncFuncNode, takeoverResource, nil},
+		"resourceDone":            {"rebuildTiupSpaceForCluster", "workingSpaceDone", "revertWithResource", workflow.SyncFuncNode, rebuildTiupSpaceForCluster, nil},
+		"workingSpaceDone":        {"applyParameterGroup", "applyParameterGroupDone", "revertWithResource", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, applyParameterGroupForTakeover), nil},
+		"applyParameterGroupDone": {"initDatabaseAccount", "success", "revertWithResource", workflow.SyncFuncNode, initDatabaseAccount, nil},
+		"success":                 {"end", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, endMaintenance, asyncBuildLog), nil},
+		"revert":                  {"revert", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(takeoverRevertMeta), nil},
+		"revertWithResource":      {"revertWithResource", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(revertResourceAfterFailure, takeoverRevertMeta), nil},
 	},
 }
 
@@ -802,7 +925,7 @@ func (p *Manager) DeleteMetadataPhysically(ctx context.Context, req cluster.Dele
 }
 
 func (p *Manager) QueryCluster(ctx context.Context, req cluster.QueryClustersReq) (resp cluster.QueryClusterResp, total int, err error) {
-	return meta.Query(ctx, req)
+	return queryClusterWithPermission(ctx, req)
 }
 
 func (p *Manager) DetailCluster(ctx context.Context, req cluster.QueryClusterDetailReq) (resp cluster.QueryClusterDetailResp, err error) {
@@ -812,6 +935,9 @@ func (p *Manager) DetailCluster(ctx context.Context, req cluster.QueryClusterDet
 			"load cluster %s meta from db error: %s", req.ClusterID, err.Error())
 		return
 	}
+	if err = checkClusterPermission(ctx, meta, constants.RbacActionRead); err != nil {
+		return
+	}
 
 	resp.Info = meta.DisplayClusterInfo(ctx)
 	resp.ClusterTopologyInfo, resp.ClusterResourceInfo = meta.DisplayInstanceInfo(ctx)
@@ -831,6 +957,9 @@ func (p *Manager) GetMonitorInfo(ctx context.Context, req cluster.QueryMonitorIn
 			"load cluster %s meta from db error: %s", req.ClusterID, err.Error())
 		return resp, err
 	}
+	if err = checkClusterPermission(ctx, clusterMeta, constants.RbacActionRead); err != nil {
+		return resp, err
+	}
 
 	alertServers := clusterMeta.GetAlertManagerAddresses()
 	grafanaServers := clusterMeta.GetGrafanaAddresses()
@@ -865,6 +994,15 @@ func (p *Manager) GetMonitorInfo(ctx context.Context, req cluster.QueryMonitorIn
 }
 
 func (p *Manager) restoreNewClusterPreCheck(ctx context.Context, req cluster.RestoreNewClusterReq) error {
+	if req.RestorePoint.IsSet() {
+		// the restore point is resolved when restoring, here only check the source of backups
+		if req.BackupID == "" && req.SourceClusterID == "" {
+			return errors.NewErrorf(errors.TIUNIMANAGER_PARAMETER_INVALID, "restore new cluster to a point in time requires backupId or sourceClusterId")
+		}
+		if req.BackupID == "" {
+			return nil
+		}
+	}
 	if req.BackupID == "" {
 		return errors.NewErrorf(errors.TIUNIMANAGER_PARAMETER_INVALID, "restore new cluster input backupId empty")
 	}
@@ -893,50 +1031,54 @@ func (p *Manager) restoreNewClusterPreCheck(ctx context.Context, req cluster.Res
 var onlineInPlaceUpgradeClusterFlow = workflow.WorkFlowDefine{
 	FlowName: constants.FlowOnlineInPlaceUpgradeCluster,
 	TaskNodes: map[string]*workflow.NodeDefine{
-		"start":                   {"initialize", "initializeDone", "fail", workflow.SyncFuncNode, initializeUpgrade},
-		"initializeDone":          {"selectTargetVersion", "selectTargetVersionDone", "fail", workflow.SyncFuncNode, selectTargetUpgradeVersion},
-		"selectTargetVersionDone": {"mergeConfig", "mergeConfigDone", "fail", workflow.SyncFuncNode, mergeUpgradeConfig},
-		"mergeConfigDone":         {"checkRegionHealth", "checkRegionHealthDone", "fail", workflow.SyncFuncNode, checkRegionHealth},
-		"checkRegionHealthDone":   {"upgradeCluster", "upgradeDone", "fail", workflow.PollingNode, upgradeCluster},
-		"upgradeDone":             {"checkVersion", "checkVersionDone", "failAfterUpgrade", workflow.SyncFuncNode, checkUpgradeVersion},
-		"checkVersionDone":        {"checkMD5", "checkMD5Done", "failAfterUpgrade", workflow.SyncFuncNode, checkUpgradeMD5},
-		"checkMD5Done":            {"checkUpgradeTime", "checkUpgradeTimeDone", "failAfterUpgrade", workflow.SyncFuncNode, checkUpgradeTime},
-		"checkUpgradeTimeDone":    {"checkConfig", "checkConfigDone", "failAfterUpgrade", workflow.SyncFuncNode, checkUpgradeConfig},
-		"checkConfigDone":         {"checkSystemHealth", "checkSystemHealthDone", "failAfterUpgrade", workflow.SyncFuncNode, checkRegionHealth},
-		"checkSystemHealthDone":   {"initDatabaseAccount", "initDatabaseAccountDone", "failAfterUpgrade", workflow.SyncFuncNode, initDatabaseAccount},
-		"initDatabaseAccountDone": {"applyParameterGroup", "applyParameterGroupDone", "failAfterUpgrade", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, applyParameterGroup)},
-		"applyParameterGroupDone": {"adjustParameters", "adjustParametersDone", "failAfterUpgrade", workflow.SyncFuncNode, adjustParametersAfterUpgrade},
-		"adjustParametersDone":    {"syncTopology", "success", "failAfterUpgrade", workflow.SyncFuncNode, syncTopology},
-		"success":                 {"end", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, endMaintenance)},
-		"fail":                    {"fail", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(revertConfigAfterFailure, endMaintenance)},
-		"failAfterUpgrade":        {"failAfterUpgrade", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(setClusterFailure, endMaintenance)},
+		"start":                   {"initialize", "initializeDone", "fail", workflow.SyncFuncNode, initializeUpgrade, nil},
+		"initializeDone":          {"selectTargetVersion", "selectTargetVersionDone", "fail", workflow.SyncFuncNode, selectTargetUpgradeVersion, nil},
+		"selectTargetVersionDone": {"mergeConfig", "mergeConfigDone", "fail", workflow.SyncFuncNode, mergeUpgradeConfig, nil},
+		"mergeConfigDone":         {"checkMD5", "checkMD5Done", "fail", workflow.SyncFuncNode, checkUpgradeMD5, nil},
+		"checkMD5Done":            {"checkUpgradeTime", "checkUpgradeTimeDone", "fail", workflow.SyncFuncNode, checkUpgradeTime, nil},
+		"checkUpgradeTimeDone":    {"checkConfig", "checkConfigDone", "fail", workflow.SyncFuncNode, checkUpgradeConfig, nil},
+		"checkConfigDone":         {"checkSystemHealth", "checkSystemHealthDone", "fail", workflow.SyncFuncNode, checkSystemHealth, nil},
+		"checkSystemHealthDone":   {"snapshotTopology", "snapshotDone", "fail", workflow.SyncFuncNode, snapshotUpgradeTopology, nil},
+		"snapshotDone":            {"upgradeCluster", "upgradeDone", "failDuringUpgrade", workflow.PollingNode, upgradeCluster, upgradeTimeoutPolicy},
+		"upgradeDone":             {"checkVersion", "checkVersionDone", "failAfterUpgrade", workflow.SyncFuncNode, checkUpgradeVersion, nil},
+		"checkVersionDone":        {"checkRegionHealth", "checkRegionHealthDone", "failAfterUpgrade", workflow.SyncFuncNode, checkRegionHealth, nil},
+		"checkRegionHealthDone":   {"initDatabaseAccount", "initDatabaseAccountDone", "failAfterUpgrade", workflow.SyncFuncNode, initDatabaseAccount, nil},
+		"initDatabaseAccountDone": {"applyParameterGroup", "applyParameterGroupDone", "failAfterUpgrade", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, applyParameterGroup), nil},
+		"applyParameterGroupDone": {"adjustParameters", "adjustParametersDone", "failAfterUpgrade", workflow.SyncFuncNode, adjustParametersAfterUpgrade, nil},
+		"adjustParametersDone":    {"syncTopology", "success", "failAfterUpgrade", workflow.SyncFuncNode, syncTopology, nil},
+		"success":                 {"end", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, endMaintenance), nil},
+		"fail":                    {"fail", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(revertConfigAfterFailure, endMaintenance), nil},
+		"failDuringUpgrade":       {"failDuringUpgrade", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(revertConfigAfterFailure, setClusterFailure, endMaintenance), nil},
+		"failAfterUpgrade":        {"failAfterUpgrade", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(setClusterFailure, endMaintenance), nil},
 	},
 }
 
 var offlineInPlaceUpgradeClusterFlow = workflow.WorkFlowDefine{
 	FlowName: constants.FlowOfflineInPlaceUpgradeCluster,
 	TaskNodes: map[string]*workflow.NodeDefine{
-		"start":                   {"initialize", "initializeDone", "fail", workflow.SyncFuncNode, initializeUpgrade},
-		"initializeDone":          {"selectTargetVersion", "selectTargetVersionDone", "fail", workflow.SyncFuncNode, selectTargetUpgradeVersion},
-		"selectTargetVersionDone": {"mergeConfig", "mergeConfigDone", "fail", workflow.SyncFuncNode, mergeUpgradeConfig},
-		"mergeConfigDone":         {"checkRegionHealth", "checkRegionHealthDone", "fail", workflow.SyncFuncNode, checkRegionHealth},
-		"checkRegionHealthDone":   {"stopCluster", "stopClusterDone", "fail", workflow.PollingNode, stopCluster},
-		"stopClusterDone":         {"setClusterOffline", "offlineDone", "fail", workflow.SyncFuncNode, setClusterOffline},
-		"offlineDone":             {"upgradeCluster", "upgradeDone", "fail", workflow.PollingNode, upgradeCluster},
-		"upgradeDone":             {"startCluster", "startClusterDone", "fail", workflow.SyncFuncNode, startCluster},
-		"startClusterDone":        {"setClusterOnline", "onlineDone", "failAfterUpgrade", workflow.SyncFuncNode, setClusterOnline},
-		"onlineDone":              {"checkVersion", "checkVersionDone", "failAfterUpgrade", workflow.SyncFuncNode, checkUpgradeVersion},
-		"checkVersionDone":        {"checkMD5", "checkMD5Done", "failAfterUpgrade", workflow.SyncFuncNode, checkUpgradeMD5},
-		"checkMD5Done":            {"checkUpgradeTime", "checkUpgradeTimeDone", "failAfterUpgrade", workflow.SyncFuncNode, checkUpgradeTime},
-		"checkUpgradeTimeDone":    {"checkConfig", "checkConfigDone", "failAfterUpgrade", workflow.SyncFuncNode, checkUpgradeConfig},
-		"checkConfigDone":         {"checkSystemHealth", "checkSystemHealthDone", "failAfterUpgrade", workflow.SyncFuncNode, checkRegionHealth},
-		"checkSystemHealthDone":   {"initDatabaseAccount", "initDatabaseAccountDone", "failAfterUpgrade", workflow.SyncFuncNode, initDatabaseAccount},
-		"initDatabaseAccountDone": {"applyParameterGroup", "applyParameterGroupDone", "failAfterUpgrade", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, applyParameterGroup)},
-		"applyParameterGroupDone": {"adjustParameters", "adjustParametersDone", "failAfterUpgrade", workflow.SyncFuncNode, adjustParametersAfterUpgrade},
-		"adjustParametersDone":    {"syncTopology", "success", "failAfterUpgrade", workflow.SyncFuncNode, syncTopology},
-		"success":                 {"end", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, endMaintenance)},
-		"fail":                    {"fail", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(revertConfigAfterFailure, endMaintenance)},
-		"failAfterUpgrade":        {"failAfterUpgrade", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(setClusterFailure, endMaintenance)},
+		"start":                   {"initialize", "initializeDone", "fail", workflow.SyncFuncNode, initializeUpgrade, nil},
+		"initializeDone":          {"selectTargetVersion", "selectTargetVersionDone", "fail", workflow.SyncFuncNode, selectTargetUpgradeVersion, nil},
+		"selectTargetVersionDone": {"mergeConfig", "mergeConfigDone", "fail", workflow.SyncFuncNode, mergeUpgradeConfig, nil},
+		"mergeConfigDone":         {"checkMD5", "checkMD5Done", "fail", workflow.SyncFuncNode, checkUpgradeMD5, nil},
+		"checkMD5Done":            {"checkUpgradeTime", "checkUpgradeTimeDone", "fail", workflow.SyncFuncNode, checkUpgradeTime, nil},
+		"checkUpgradeTimeDone":    {"checkConfig", "checkConfigDone", "fail", workflow.SyncFuncNode, checkUpgradeConfig, nil},
+		"checkConfigDone":         {"checkSystemHealth", "checkSystemHealthDone", "fail", workflow.SyncFuncNode, checkSystemHealth, nil},
+		"checkSystemHealthDone":   {"snapshotTopology", "snapshotDone", "fail", workflow.SyncFuncNode, snapshotUpgradeTopology, nil},
+		"snapshotDone":            {"stopCluster", "stopClusterDone", "fail", workflow.PollingNode, stopCluster, nil},
+		"stopClusterDone":         {"setClusterOffline", "offlineDone", "fail", workflow.SyncFuncNode, setClusterOffline, nil},
+		"offlineDone":             {"upgradeCluster", "upgradeDone", "failDuringUpgrade", workflow.PollingNode, upgradeCluster, upgradeTimeoutPolicy},
+		"upgradeDone":             {"startCluster", "startClusterDone", "failDuringUpgrade", workflow.SyncFuncNode, startCluster, nil},
+		"startClusterDone":        {"setClusterOnline", "onlineDone", "failAfterUpgrade", workflow.SyncFuncNode, setClusterOnline, nil},
+		"onlineDone":              {"checkVersion", "checkVersionDone", "failAfterUpgrade", workflow.SyncFuncNode, checkUpgradeVersion, nil},
+		"checkVersionDone":        {"checkRegionHealth", "checkRegionHealthDone", "failAfterUpgrade", workflow.SyncFuncNode, checkRegionHealth, nil},
+		"checkRegionHealthDone":   {"initDatabaseAccount", "initDatabaseAccountDone", "failAfterUpgrade", workflow.SyncFuncNode, initDatabaseAccount, nil},
+		"initDatabaseAccountDone": {"applyParameterGroup", "applyParameterGroupDone", "failAfterUpgrade", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, applyParameterGroup), nil},
+		"applyParameterGroupDone": {"adjustParameters", "adjustParametersDone", "failAfterUpgrade", workflow.SyncFuncNode, adjustParametersAfterUpgrade, nil},
+		"adjustParametersDone":    {"syncTopology", "success", "failAfterUpgrade", workflow.SyncFuncNode, syncTopology, nil},
+		"success":                 {"end", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, endMaintenance), nil},
+		"fail":                    {"fail", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(revertConfigAfterFailure, endMaintenance), nil},
+		"failDuringUpgrade":       {"failDuringUpgrade", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(revertConfigAfterFailure, setClusterFailure, endMaintenance), nil},
+		"failAfterUpgrade":        {"failAfterUpgrade", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(setClusterFailure, endMaintenance), nil},
 	},
 }
 
@@ -955,6 +1097,9 @@ func (p *Manager) QueryProductUpdatePath(ctx context.Context, clusterID string)
 			"load cluster %s meta from db error: %s", clusterID, err.Error())
 		return
 	}
+	if err = checkClusterPermission(ctx, clusterMeta, constants.RbacActionRead); err != nil {
+		return
+	}
 
 	version := clusterMeta.Cluster.Version
 	framework.LogWithContext(ctx).Infof("query update path for cluster %s, version %s, using minor version %s",
@@ -1013,12 +1158,34 @@ func (p *Manager) QueryUpgradeVersionDiffInfo(ctx context.Context, clusterID str
 			"load cluster %s meta from db error: %s", clusterID, err.Error())
 		return
 	}
+	if err = checkClusterPermission(ctx, clusterMeta, constants.RbacActionRead); err != nil {
+		return
+	}
 
-	runningInstanceTypes := make([]string, 0)
-	for instanceType := range clusterMeta.Instances {
-		runningInstanceTypes = append(runningInstanceTypes, instanceType)
+	clusterParams, groupParams, err := queryUpgradeParameters(ctx, clusterMeta, version)
+	if err != nil {
+		return
 	}
 
+	configDiffInfos := compareConfigDifference(ctx, clusterParams, groupParams, getRunningInstanceTypes(clusterMeta))
+	sort.Slice(configDiffInfos, func(i, j int) bool {
+		return configDiffInfos[i].InstanceType < configDiffInfos[j].InstanceType
+	})
+	resp.ConfigDiffInfos = configDiffInfos
+
+	return
+}
+
+// queryUpgradeParameters
+// @Description: query parameters of cluster and parameters of the default parameter group of target version
+// @Parameter ctx
+// @Parameter clusterMeta
+// @Parameter version
+// @return clusterParams
+// @return groupParams
+// @return err
+func queryUpgradeParameters(ctx context.Context, clusterMeta *meta.ClusterMeta, version string) (clusterParams []structs.ClusterParameterInfo, groupParams []structs.ParameterGroupParameterInfo, err error) {
+	clusterID := clusterMeta.Cluster.ID
 	framework.LogWithContext(ctx).Infof("query config difference between cluster %s version %s and parametergroup of %s", clusterID, clusterMeta.Cluster.Version, version)
 	paramResp, _, err := parameter.NewManager().QueryClusterParameters(ctx, cluster.QueryClusterParametersReq{
 		ClusterID: clusterID,
@@ -1049,13 +1216,15 @@ func (p *Manager) QueryUpgradeVersionDiffInfo(ctx context.Context, clusterID str
 	}
 	framework.LogWithContext(ctx).Debugf("query paramgroup for version %s result: %v", version, groups)
 
-	configDiffInfos := compareConfigDifference(ctx, paramResp.Params, groups[0].Params, runningInstanceTypes)
-	sort.Slice(configDiffInfos, func(i, j int) bool {
-		return configDiffInfos[i].InstanceType < configDiffInfos[j].InstanceType
-	})
-	resp.ConfigDiffInfos = configDiffInfos
+	return paramResp.Params, groups[0].Params, nil
+}
 
-	return
+func getRunningInstanceTypes(clusterMeta *meta.ClusterMeta) []string {
+	runningInstanceTypes := make([]string, 0)
+	for instanceType := range clusterMeta.Instances {
+		runningInstanceTypes = append(runningInstanceTypes, instanceType)
+	}
+	return runningInstanceTypes
 }
 
 func getMinorVersion(version string) string {
@@ -1127,6 +1296,19 @@ func (p *Manager) InPlaceUpgradeCluster(ctx context.Context, req cluster.Upgrade
 			"load cluster %s meta from db error: %s", req.ClusterID, err.Error())
 		return
 	}
+	if err = checkClusterPermission(ctx, clusterMeta, constants.RbacActionUpdate); err != nil {
+		return
+	}
+	// online upgrade keeps the cluster available, only offline upgrade is restricted by the maintain window
+	if req.UpgradeWay != string(constants.UpgradeWayOnline) {
+		pendingOperationID, admitErr := maintainwindow.Admit(ctx, clusterMeta, constants.PendingOperationUpgradeCluster, req.MaintainWindowOption, req)
+		if admitErr != nil || pendingOperationID != "" {
+			resp.ClusterID = clusterMeta.Cluster.ID
+			resp.PendingOperationID = pendingOperationID
+			err = admitErr
+			return
+		}
+	}
 
 	data := map[string]interface{}{
 		ContextClusterMeta:          clusterMeta,
@@ -47,6 +47,7 @@ import (
 	mock_product "github.com/pingcap/tiunimanager/test/mockmodels"
 	"github.com/pingcap/tiunimanager/test/mockmodels/mockclustermanagement"
 	"github.com/pingcap/tiunimanager/test/mockmodels/mockresource"
+	"github.com/pingcap/tiunimanager/test/mockmodels/mockworkflow"
 	mock_workflow_service "github.com/pingcap/tiunimanager/test/mockworkflow"
 	workflow "github.com/pingcap/tiunimanager/workflow2"
 	"github.com/pkg/sftp"
@@ -62,9 +63,9 @@ func getEmptyFlow(name string) *workflow.WorkFlowDefine {
 	return &workflow.WorkFlowDefine{
 		FlowName: name,
 		TaskNodes: map[string]*workflow.NodeDefine{
-			"start": {"start", "done", "fail", workflow.SyncFuncNode, emptyNode},
-			"done":  {"end", "", "", workflow.SyncFuncNode, emptyNode},
-			"fail":  {"end", "", "", workflow.SyncFuncNode, emptyNode},
+			"start": {"start", "done", "fail", workflow.SyncFuncNode, emptyNode, nil},
+			"done":  {"end", "", "", workflow.SyncFuncNode, emptyNode, nil},
+			"fail":  {"end", "", "", workflow.SyncFuncNode, emptyNode, nil},
 		},
 	}
 }
@@ -1282,6 +1283,19 @@ func TestNewClusterManager(t *testing.T) {
 
 }
 
+func TestFlowNodePolicy(t *testing.T) {
+	assert.Equal(t, tiupRetryPolicy, createClusterFlow.TaskNodes["syncTopologyDone"].Policy)
+	assert.Equal(t, connectRetryPolicy, createClusterFlow.TaskNodes["initParametersDone"].Policy)
+	assert.Equal(t, connectRetryPolicy, scaleOutDefine.TaskNodes["onlineDone"].Policy)
+	assert.Nil(t, createClusterFlow.TaskNodes["configDone"].Policy)
+	assert.Equal(t, scaleOutTimeoutPolicy, scaleOutDefine.TaskNodes["configDone"].Policy)
+	assert.Equal(t, upgradeTimeoutPolicy, onlineInPlaceUpgradeClusterFlow.TaskNodes["snapshotDone"].Policy)
+	assert.Equal(t, upgradeTimeoutPolicy, offlineInPlaceUpgradeClusterFlow.TaskNodes["offlineDone"].Policy)
+
+	assert.True(t, tiupRetryPolicy.Retryable(em_errors.Error(em_errors.TIUNIMANAGER_TASK_FAILED)))
+	assert.False(t, tiupRetryPolicy.Retryable(em_errors.Error(em_errors.TIUNIMANAGER_PARAMETER_INVALID)))
+}
+
 func TestPreviewCluster(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -2247,3 +2261,154 @@ func TestManager_DeleteMetadataPhysically(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestCreateClusterFlow_resume(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	workflow.GetWorkFlowService().RegisterWorkFlow(context.TODO(), constants.FlowCreateCluster, &createClusterFlow)
+	defer workflow.GetWorkFlowService().RegisterWorkFlow(context.TODO(), constants.FlowCreateCluster, getEmptyFlow(constants.FlowCreateCluster))
+
+	// failure handling of the flow updates the cluster, no call is expected before the flow is abandoned
+	clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+	models.SetClusterReaderWriter(clusterRW)
+
+	flowContext := workflow.NewFlowContext(context.TODO(), make(map[string]string))
+	flowContext.SetData(ContextClusterMeta, &meta.ClusterMeta{
+		Cluster: &management.Cluster{
+			Entity:  common.Entity{ID: "testCluster"},
+			Version: "v5.0.0",
+		},
+	})
+	flowContext.SetData(ContextTopology, "test topology")
+	flowContext.SetData(workflow.ContextDeferFailure, true)
+	now := time.Now()
+	flow := &wfModel.WorkFlow{
+		Entity:  common.Entity{ID: "createFlow", Status: constants.WorkFlowStatusProcessing},
+		Name:    constants.FlowCreateCluster,
+		BizID:   "testCluster",
+		Context: flowContext.GetContextString(),
+	}
+	nodes := []*wfModel.WorkFlowNode{
+		{Entity: common.Entity{ID: "node1", CreatedAt: now, Status: constants.WorkFlowStatusFinished}, Name: "prepareResource", ParentID: flow.ID},
+		{Entity: common.Entity{ID: "node2", CreatedAt: now.Add(time.Millisecond), Status: constants.WorkFlowStatusFinished}, Name: "buildConfig", ParentID: flow.ID},
+	}
+
+	flowRW := mockworkflow.NewMockReaderWriter(ctrl)
+	models.SetWorkFlowReaderWriter(flowRW)
+	flowRW.EXPECT().QueryWorkFlows(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, int64(0), nil).AnyTimes()
+	flowRW.EXPECT().QueryDetailWorkFlow(gomock.Any(), flow.ID).DoAndReturn(func(ctx context.Context, flowId string) (*wfModel.WorkFlow, []*wfModel.WorkFlowNode, error) {
+		return flow, nodes, nil
+	}).AnyTimes()
+	flowRW.EXPECT().GetWorkFlow(gomock.Any(), flow.ID).Return(flow, nil).AnyTimes()
+	flowRW.EXPECT().UpdateWorkFlowDetail(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	flowRW.EXPECT().CreateWorkFlowNode(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, node *wfModel.WorkFlowNode) (*wfModel.WorkFlowNode, error) {
+		node.ID = fmt.Sprintf("node%d", len(nodes)+1)
+		node.CreatedAt = now.Add(time.Duration(len(nodes)) * time.Millisecond)
+		nodes = append(nodes, node)
+		return node, nil
+	}).AnyTimes()
+	flowRW.EXPECT().UpdateWorkFlow(gomock.Any(), flow.ID, gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, flowId string, status string, flowContext string) error {
+		flow.Status = status
+		return nil
+	}).AnyTimes()
+	flowRW.EXPECT().ResumeWorkFlow(gomock.Any(), flow.ID, gomock.Any()).DoAndReturn(func(ctx context.Context, flowId string, node *wfModel.WorkFlowNode) error {
+		flow.Status = constants.WorkFlowStatusProcessing
+		node.ID = fmt.Sprintf("node%d", len(nodes)+1)
+		node.CreatedAt = now.Add(time.Duration(len(nodes)) * time.Millisecond)
+		nodes = append(nodes, node)
+		return nil
+	}).AnyTimes()
+
+	tiupManager := mock_deployment.NewMockInterface(ctrl)
+	deployment.M = tiupManager
+
+	// deploy fails
+	tiupManager.EXPECT().Deploy(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any(), gomock.Any(), gomock.Any()).Return("", errors.New("deploy failed")).Times(1)
+	flowMeta, err := workflow.NewWorkFlowMeta(context.TODO(), flow.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "deployCluster", flowMeta.CurrentNode.Name)
+	flowMeta.Execute()
+	assert.Equal(t, constants.WorkFlowStatusError, flow.Status)
+	assert.Equal(t, 3, len(nodes))
+	assert.Equal(t, constants.WorkFlowStatusError, nodes[2].Status)
+
+	// resume from deployCluster, allocated resources and config are kept
+	err = workflow.GetWorkFlowService().Retry(context.TODO(), flow.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, constants.WorkFlowStatusProcessing, flow.Status)
+	assert.Equal(t, 4, len(nodes))
+	assert.True(t, nodes[3].Resumed)
+
+	tiupManager.EXPECT().Deploy(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any(), gomock.Any(), gomock.Any()).Return("operation01", nil).Times(1)
+	tiupManager.EXPECT().GetStatus(gomock.Any(), "operation01").Return(deployment.Operation{Status: deployment.Finished}, nil).Times(1)
+	flowMeta, err = workflow.NewWorkFlowMeta(context.TODO(), flow.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "deployCluster", flowMeta.CurrentNode.Name)
+	flowMeta.Execute()
+	assert.Equal(t, constants.WorkFlowStatusProcessing, flow.Status)
+	assert.Equal(t, constants.WorkFlowStatusFinished, nodes[3].Status)
+
+	flowMeta, err = workflow.NewWorkFlowMeta(context.TODO(), flow.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "syncConnectionKey", flowMeta.CurrentNode.Name)
+}
+
+func TestCreateClusterFlow_startupRetry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	workflow.GetWorkFlowService().RegisterWorkFlow(context.TODO(), constants.FlowCreateCluster, &createClusterFlow)
+	defer workflow.GetWorkFlowService().RegisterWorkFlow(context.TODO(), constants.FlowCreateCluster, getEmptyFlow(constants.FlowCreateCluster))
+
+	flowContext := workflow.NewFlowContext(context.TODO(), make(map[string]string))
+	flowContext.SetData(ContextClusterMeta, &meta.ClusterMeta{
+		Cluster: &management.Cluster{
+			Entity:  common.Entity{ID: "testCluster"},
+			Version: "v5.0.0",
+		},
+	})
+	now := time.Now()
+	flow := &wfModel.WorkFlow{
+		Entity:  common.Entity{ID: "createFlow", Status: constants.WorkFlowStatusProcessing},
+		Name:    constants.FlowCreateCluster,
+		BizID:   "testCluster",
+		Context: flowContext.GetContextString(),
+	}
+	nodes := make([]*wfModel.WorkFlowNode, 0)
+	for i, name := range []string{"prepareResource", "buildConfig", "deployCluster", "syncConnectionKey", "syncTopology"} {
+		nodes = append(nodes, &wfModel.WorkFlowNode{
+			Entity: common.Entity{ID: fmt.Sprintf("node%d", i+1), CreatedAt: now.Add(time.Duration(i) * time.Millisecond), Status: constants.WorkFlowStatusFinished},
+			Name:   name, ParentID: flow.ID,
+		})
+	}
+
+	flowRW := mockworkflow.NewMockReaderWriter(ctrl)
+	models.SetWorkFlowReaderWriter(flowRW)
+	flowRW.EXPECT().QueryWorkFlows(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, int64(0), nil).AnyTimes()
+	flowRW.EXPECT().QueryDetailWorkFlow(gomock.Any(), flow.ID).Return(flow, nodes, nil).AnyTimes()
+	flowRW.EXPECT().GetWorkFlow(gomock.Any(), flow.ID).Return(flow, nil).AnyTimes()
+	flowRW.EXPECT().UpdateWorkFlowDetail(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	flowRW.EXPECT().CreateWorkFlowNode(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, node *wfModel.WorkFlowNode) (*wfModel.WorkFlowNode, error) {
+		return node, nil
+	}).AnyTimes()
+
+	// status of the start operation is lost once, the operation is polled again instead of starting the cluster twice
+	tiupManager := mock_deployment.NewMockInterface(ctrl)
+	deployment.M = tiupManager
+	tiupManager.EXPECT().Start(gomock.Any(), deployment.TiUPComponentTypeCluster, "testCluster", gomock.Any(), flow.ID,
+		gomock.Any(), gomock.Any()).Return("operation01", nil).Times(1)
+	gomock.InOrder(
+		tiupManager.EXPECT().GetStatus(gomock.Any(), "operation01").Return(deployment.Operation{}, errors.New("status lost")).Times(1),
+		tiupManager.EXPECT().GetStatus(gomock.Any(), "operation01").Return(deployment.Operation{Status: deployment.Finished}, nil).Times(1),
+	)
+
+	flowMeta, err := workflow.NewWorkFlowMeta(context.TODO(), flow.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "startupCluster", flowMeta.CurrentNode.Name)
+	flowMeta.Execute()
+	assert.Equal(t, constants.WorkFlowStatusFinished, flowMeta.CurrentNode.Status)
+	assert.Equal(t, 2, flowMeta.CurrentNode.Attempts)
+}
@@ -62,6 +62,7 @@ type StoreInfo struct {
 		ID        int    `json:"id"`
 		Address   string `json:"address"`
 		StateName string `json:"state_name"`
+		Version   string `json:"version"`
 	} `json:"store"`
 	Status struct {
 		RegionCount int `json:"region_count"`
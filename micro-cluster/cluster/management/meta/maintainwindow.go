@@ -0,0 +1,205 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package meta
+
+import (
+	"strings"
+	"time"
+	// time zones of maintain window do not depend on the zoneinfo of the server
+	_ "time/tzdata"
+
+	"github.com/pingcap/tiunimanager/common/errors"
+)
+
+// MaintainWindowRange weekdays and a time range of the day, the range crosses midnight if End is not after Start
+type MaintainWindowRange struct {
+	Weekdays map[time.Weekday]bool
+	Start    time.Duration // offset from midnight
+	End      time.Duration
+	Location *time.Location // time zone of weekdays and the time range, UTC if nil
+}
+
+func (r MaintainWindowRange) location() *time.Location {
+	if r.Location == nil {
+		return time.UTC
+	}
+	return r.Location
+}
+
+// MaintainWindow ranges of time in which disruptive operations are allowed, empty window means no restriction
+type MaintainWindow []MaintainWindowRange
+
+// ParseMaintainWindow
+// @Description: parse maintain window like "Saturday,Sunday 02:00-06:00 Asia/Shanghai;Wednesday 23:00-01:00",
+// "*" means every day, time zone is an IANA name following the time range, it is UTC if omitted.
+// The window is evaluated in its own time zone, no matter which time zone the server is in
+// @Parameter window
+// @return MaintainWindow
+// @return error
+func ParseMaintainWindow(window string) (MaintainWindow, error) {
+	result := make(MaintainWindow, 0)
+	for _, item := range strings.Split(window, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		fields := strings.Fields(item)
+		if len(fields) != 2 && len(fields) != 3 {
+			return nil, errors.NewErrorf(errors.TIUNIMANAGER_MAINTAIN_WINDOW_INVALID, "invalid maintain window %s, expected format is weekdays, time range and optional time zone, eg. Sunday 02:00-04:00 UTC", item)
+		}
+		weekdays, err := parseWeekdays(fields[0])
+		if err != nil {
+			return nil, err
+		}
+		times := strings.Split(fields[1], "-")
+		if len(times) != 2 {
+			return nil, errors.NewErrorf(errors.TIUNIMANAGER_MAINTAIN_WINDOW_INVALID, "invalid time range %s", fields[1])
+		}
+		start, err := parseClock(times[0])
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseClock(times[1])
+		if err != nil {
+			return nil, err
+		}
+		if start == end {
+			return nil, errors.NewErrorf(errors.TIUNIMANAGER_MAINTAIN_WINDOW_INVALID, "empty time range %s", fields[1])
+		}
+		location := time.UTC
+		if len(fields) == 3 {
+			if location, err = parseLocation(fields[2]); err != nil {
+				return nil, err
+			}
+		}
+		result = append(result, MaintainWindowRange{Weekdays: weekdays, Start: start, End: end, Location: location})
+	}
+	return result, nil
+}
+
+func parseWeekdays(days string) (map[time.Weekday]bool, error) {
+	weekdays := make(map[time.Weekday]bool)
+	if days == "*" {
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			weekdays[day] = true
+		}
+		return weekdays, nil
+	}
+	for _, name := range strings.Split(days, ",") {
+		found := false
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if strings.EqualFold(name, day.String()) || strings.EqualFold(name, day.String()[:3]) {
+				weekdays[day] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.NewErrorf(errors.TIUNIMANAGER_MAINTAIN_WINDOW_INVALID, "invalid weekday %s", name)
+		}
+	}
+	return weekdays, nil
+}
+
+func parseClock(clock string) (time.Duration, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, errors.NewErrorf(errors.TIUNIMANAGER_MAINTAIN_WINDOW_INVALID, "invalid time %s, expected format is HH:MM", clock)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func parseLocation(name string) (*time.Location, error) {
+	// local time zone of the server is not allowed, it differs between servers
+	if name == "Local" {
+		return nil, errors.NewErrorf(errors.TIUNIMANAGER_MAINTAIN_WINDOW_INVALID, "time zone of maintain window should be explicit, not %s", name)
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errors.NewErrorf(errors.TIUNIMANAGER_MAINTAIN_WINDOW_INVALID, "invalid time zone %s", name)
+	}
+	return location, nil
+}
+
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// Contains
+// @Description: whether t is in the maintain window, empty window contains any time
+// @Receiver w
+// @Parameter t
+// @return bool
+func (w MaintainWindow) Contains(t time.Time) bool {
+	if len(w) == 0 {
+		return true
+	}
+	for _, r := range w {
+		if r.contains(t.In(r.location())) {
+			return true
+		}
+	}
+	return false
+}
+
+// contains whether t in the time zone of the range is in the range
+func (r MaintainWindowRange) contains(t time.Time) bool {
+	today := midnight(t)
+	offset := t.Sub(today)
+	yesterday := today.AddDate(0, 0, -1).Weekday()
+	if r.Start < r.End {
+		return r.Weekdays[t.Weekday()] && offset >= r.Start && offset < r.End
+	}
+	return (r.Weekdays[t.Weekday()] && offset >= r.Start) || (r.Weekdays[yesterday] && offset < r.End)
+}
+
+// NextOpen
+// @Description: the earliest time after t when the maintain window opens, it is in the time zone of the opening range
+// @Receiver w
+// @Parameter t
+// @return time.Time
+func (w MaintainWindow) NextOpen(t time.Time) time.Time {
+	if w.Contains(t) {
+		return t
+	}
+	next := time.Time{}
+	for _, r := range w {
+		today := midnight(t.In(r.location()))
+		for days := 0; days <= 7; days++ {
+			day := today.AddDate(0, 0, days)
+			open := day.Add(r.Start)
+			if r.Weekdays[day.Weekday()] && open.After(t) && (next.IsZero() || open.Before(next)) {
+				next = open
+			}
+		}
+	}
+	return next
+}
+
+// InMaintainWindow
+// @Description: whether t is in the maintain window of cluster
+// @Receiver p
+// @Parameter t
+// @return bool
+// @return MaintainWindow
+// @return error
+func (p *ClusterMeta) InMaintainWindow(t time.Time) (bool, MaintainWindow, error) {
+	window, err := ParseMaintainWindow(p.Cluster.MaintainWindow)
+	if err != nil {
+		return false, nil, err
+	}
+	return window.Contains(t), window, nil
+}
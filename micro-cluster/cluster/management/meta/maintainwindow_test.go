@@ -0,0 +1,130 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package meta
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMaintainWindow(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		window, err := ParseMaintainWindow("")
+		assert.NoError(t, err)
+		assert.Empty(t, window)
+	})
+	t.Run("normal", func(t *testing.T) {
+		window, err := ParseMaintainWindow("Saturday,sun 02:00-06:00; Wed 23:00-01:00")
+		assert.NoError(t, err)
+		assert.Len(t, window, 2)
+		assert.True(t, window[0].Weekdays[time.Saturday])
+		assert.True(t, window[0].Weekdays[time.Sunday])
+		assert.False(t, window[0].Weekdays[time.Monday])
+		assert.Equal(t, 2*time.Hour, window[0].Start)
+		assert.Equal(t, 6*time.Hour, window[0].End)
+		assert.True(t, window[1].Weekdays[time.Wednesday])
+		assert.Equal(t, 23*time.Hour, window[1].Start)
+		assert.Equal(t, time.Hour, window[1].End)
+		assert.Equal(t, time.UTC, window[1].Location)
+	})
+	t.Run("time zone", func(t *testing.T) {
+		window, err := ParseMaintainWindow("Sunday 02:00-06:00 Asia/Shanghai")
+		assert.NoError(t, err)
+		assert.Equal(t, "Asia/Shanghai", window[0].Location.String())
+	})
+	t.Run("every day", func(t *testing.T) {
+		window, err := ParseMaintainWindow("* 00:30-01:30")
+		assert.NoError(t, err)
+		assert.Len(t, window[0].Weekdays, 7)
+		assert.Equal(t, 30*time.Minute, window[0].Start)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for _, s := range []string{"02:00-06:00", "Someday 02:00-06:00", "Sunday 02:00", "Sunday 2am-6am", "Sunday 02:00-02:00", "Sunday 25:00-06:00", "Sunday 02:00-06:00 Mars/Olympus", "Sunday 02:00-06:00 Local"} {
+			_, err := ParseMaintainWindow(s)
+			assert.Error(t, err, s)
+		}
+	})
+}
+
+func TestMaintainWindow_Contains(t *testing.T) {
+	window, err := ParseMaintainWindow("Saturday 02:00-06:00;Wednesday 23:00-01:00")
+	assert.NoError(t, err)
+
+	// 2022-01-01 is Saturday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2022, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+	assert.True(t, window.Contains(at(1, 2, 0)))
+	assert.True(t, window.Contains(at(1, 5, 59)))
+	assert.False(t, window.Contains(at(1, 6, 0)))
+	assert.False(t, window.Contains(at(1, 1, 59)))
+	assert.False(t, window.Contains(at(2, 3, 0)))
+	// crosses midnight from Wednesday to Thursday
+	assert.True(t, window.Contains(at(5, 23, 30)))
+	assert.True(t, window.Contains(at(6, 0, 30)))
+	assert.False(t, window.Contains(at(6, 1, 0)))
+	assert.False(t, window.Contains(at(5, 0, 30)))
+
+	assert.True(t, MaintainWindow{}.Contains(at(3, 12, 0)))
+}
+
+func TestMaintainWindow_Contains_timeZone(t *testing.T) {
+	window, err := ParseMaintainWindow("Wednesday 23:00-01:00 Asia/Shanghai")
+	assert.NoError(t, err)
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	// 2022-01-05 is Wednesday, Asia/Shanghai is UTC+8
+	assert.True(t, window.Contains(time.Date(2022, 1, 5, 15, 30, 0, 0, time.UTC)))
+	// Thursday 00:30 in Asia/Shanghai, it is still Wednesday in UTC and New York
+	assert.True(t, window.Contains(time.Date(2022, 1, 5, 16, 30, 0, 0, time.UTC)))
+	assert.True(t, window.Contains(time.Date(2022, 1, 5, 11, 30, 0, 0, newYork)))
+	assert.False(t, window.Contains(time.Date(2022, 1, 5, 17, 0, 0, 0, time.UTC)))
+	// Wednesday 23:30 in UTC is Thursday 07:30 in Asia/Shanghai
+	assert.False(t, window.Contains(time.Date(2022, 1, 5, 23, 30, 0, 0, time.UTC)))
+
+	assert.True(t, window.NextOpen(time.Date(2022, 1, 5, 12, 0, 0, 0, newYork)).Equal(time.Date(2022, 1, 12, 15, 0, 0, 0, time.UTC)))
+}
+
+func TestMaintainWindow_NextOpen(t *testing.T) {
+	window, err := ParseMaintainWindow("Saturday 02:00-06:00;Wednesday 23:00-01:00")
+	assert.NoError(t, err)
+
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2022, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+	assert.Equal(t, at(1, 3, 0), window.NextOpen(at(1, 3, 0)))
+	assert.Equal(t, at(5, 23, 0), window.NextOpen(at(1, 6, 0)))
+	assert.Equal(t, at(8, 2, 0), window.NextOpen(at(6, 1, 0)))
+	assert.Equal(t, at(8, 2, 0), window.NextOpen(at(1, 12, 0).AddDate(0, 0, 6)))
+}
+
+func TestClusterMeta_InMaintainWindow(t *testing.T) {
+	meta := &ClusterMeta{Cluster: &management.Cluster{MaintainWindow: "Saturday 02:00-06:00"}}
+	in, _, err := meta.InMaintainWindow(time.Date(2022, 1, 1, 3, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.True(t, in)
+	in, _, err = meta.InMaintainWindow(time.Date(2022, 1, 2, 3, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.False(t, in)
+
+	meta.Cluster.MaintainWindow = "Saturday"
+	_, _, err = meta.InMaintainWindow(time.Now())
+	assert.Error(t, err)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/tiunimanager/message/cluster"
 
 	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
 	"github.com/pingcap/tiunimanager/common/structs"
 	"github.com/pingcap/tiunimanager/library/framework"
 	"github.com/pingcap/tiunimanager/models"
@@ -109,6 +110,13 @@ func Query(ctx context.Context, req cluster.QueryClustersReq) (resp cluster.Quer
 	if len(req.Status) > 0 {
 		filters.StatusFilters = []constants.ClusterRunningStatus{constants.ClusterRunningStatus(req.Status)}
 	}
+	if len(req.TagSelector) > 0 {
+		filters.TagSelector, err = structs.ParseTagSelector(req.TagSelector)
+		if err != nil {
+			err = errors.WrapError(errors.TIUNIMANAGER_PARAMETER_INVALID, "invalid tag selector", err)
+			return
+		}
+	}
 
 	result, page, err := models.GetClusterReaderWriter().QueryMetas(ctx, filters, req.PageRequest)
 	if err != nil {
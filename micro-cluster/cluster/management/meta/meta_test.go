@@ -622,6 +622,51 @@ func TestClusterMeta_IsComponentRequired(t *testing.T) {
 	})
 }
 
+func TestClusterMeta_AddMigrationInstance(t *testing.T) {
+	source := &management.ClusterInstance{
+		Entity:       common.Entity{ID: "instance01", Status: string(constants.ClusterInstanceRunning)},
+		Type:         "TiKV",
+		CpuCores:     4,
+		Memory:       8,
+		Zone:         "zone1",
+		DiskType:     "SSD",
+		DiskCapacity: 100,
+		DiskID:       "disk01",
+		HostIP:       []string{"127.0.0.1"},
+	}
+	meta := &ClusterMeta{
+		Cluster: &management.Cluster{
+			Entity:  common.Entity{ID: "111", TenantId: "tenant"},
+			Version: "v5.0.0",
+		},
+		Instances: map[string][]*management.ClusterInstance{
+			"TiKV": {source},
+		},
+	}
+	t.Run("same host", func(t *testing.T) {
+		_, err := meta.AddMigrationInstance(context.TODO(), source, "127.0.0.1", "", "")
+		assert.Error(t, err)
+		assert.Len(t, meta.Instances["TiKV"], 1)
+	})
+	t.Run("target host", func(t *testing.T) {
+		instance, err := meta.AddMigrationInstance(context.TODO(), source, "127.0.0.2", "", "disk02")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"127.0.0.2"}, instance.HostIP)
+		assert.Equal(t, "zone1", instance.Zone)
+		assert.Equal(t, "disk02", instance.DiskID)
+		assert.Equal(t, int8(4), instance.CpuCores)
+		assert.Equal(t, string(constants.ClusterInstanceInitializing), instance.Status)
+		assert.Len(t, meta.Instances["TiKV"], 2)
+	})
+	t.Run("target zone", func(t *testing.T) {
+		instance, err := meta.AddMigrationInstance(context.TODO(), source, "", "region1,zone2", "")
+		assert.NoError(t, err)
+		assert.Empty(t, instance.HostIP)
+		assert.Equal(t, "zone2", instance.Zone)
+		assert.Empty(t, instance.DiskID)
+	})
+}
+
 func TestClusterMeta_DeleteInstance(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
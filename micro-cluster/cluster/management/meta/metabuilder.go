@@ -151,20 +151,21 @@ func (p *ClusterMeta) BuildCluster(ctx context.Context, param structs.CreateClus
 			TenantId: framework.GetTenantIDFromContext(ctx),
 			Status:   string(constants.ClusterInitializing),
 		},
-		Name:              param.Name,
-		Type:              param.Type,
-		Version:           param.Version,
-		TLS:               param.TLS,
-		Tags:              param.Tags,
-		OwnerId:           framework.GetUserIDFromContext(ctx),
-		ParameterGroupID:  param.ParameterGroupID,
-		Copies:            param.Copies,
-		Exclusive:         param.Exclusive,
-		Region:            param.Region,
-		Vendor:            param.Vendor,
-		CpuArchitecture:   constants.ArchType(param.CpuArchitecture),
-		MaintenanceStatus: constants.ClusterMaintenanceNone,
-		MaintainWindow:    "",
+		Name:               param.Name,
+		Type:               param.Type,
+		Version:            param.Version,
+		TLS:                param.TLS,
+		Tags:               param.Tags,
+		OwnerId:            framework.GetUserIDFromContext(ctx),
+		ParameterGroupID:   param.ParameterGroupID,
+		Copies:             param.Copies,
+		Exclusive:          param.Exclusive,
+		Region:             param.Region,
+		Vendor:             param.Vendor,
+		CpuArchitecture:    constants.ArchType(param.CpuArchitecture),
+		MaintenanceStatus:  constants.ClusterMaintenanceNone,
+		MaintainWindow:     param.MaintainWindow,
+		DeletionProtection: param.DeletionProtection,
 	}
 	got, err := models.GetClusterReaderWriter().Create(ctx, p.Cluster)
 	if err == nil {
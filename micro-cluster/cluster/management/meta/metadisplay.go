@@ -325,24 +325,26 @@ func (p *ClusterMeta) GetRevision() string {
 func (p *ClusterMeta) DisplayClusterInfo(ctx context.Context) structs.ClusterInfo {
 	cluster := p.Cluster
 	clusterInfo := &structs.ClusterInfo{
-		ID:              cluster.ID,
-		UserID:          cluster.OwnerId,
-		Name:            cluster.Name,
-		Type:            cluster.Type,
-		Version:         cluster.Version,
-		Tags:            cluster.Tags,
-		TLS:             cluster.TLS,
-		Vendor:          cluster.Vendor,
-		Region:          cluster.Region,
-		Status:          cluster.Status,
-		Copies:          cluster.Copies,
-		Exclusive:       cluster.Exclusive,
-		CpuArchitecture: string(cluster.CpuArchitecture),
-		MaintainStatus:  string(cluster.MaintenanceStatus),
-		Whitelist:       []string{},
-		MaintainWindow:  cluster.MaintainWindow,
-		CreateTime:      cluster.CreatedAt,
-		UpdateTime:      cluster.UpdatedAt,
+		ID:                 cluster.ID,
+		UserID:             cluster.OwnerId,
+		TenantID:           cluster.TenantId,
+		Name:               cluster.Name,
+		Type:               cluster.Type,
+		Version:            cluster.Version,
+		Tags:               cluster.Tags,
+		TLS:                cluster.TLS,
+		Vendor:             cluster.Vendor,
+		Region:             cluster.Region,
+		Status:             cluster.Status,
+		Copies:             cluster.Copies,
+		Exclusive:          cluster.Exclusive,
+		CpuArchitecture:    string(cluster.CpuArchitecture),
+		MaintainStatus:     string(cluster.MaintenanceStatus),
+		Whitelist:          []string{},
+		MaintainWindow:     cluster.MaintainWindow,
+		DeletionProtection: cluster.DeletionProtection,
+		CreateTime:         cluster.CreatedAt,
+		UpdateTime:         cluster.UpdatedAt,
 	}
 	// todo: display users?
 	// component address
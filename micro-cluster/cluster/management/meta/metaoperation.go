@@ -82,6 +82,53 @@ func (p *ClusterMeta) AddInstances(ctx context.Context, computes []structs.Clust
 	return nil
 }
 
+// AddMigrationInstance
+// @Description add an instance into cluster topology to replace the source instance, it has the same type and spec as the source
+// @Parameter	source instance to be replaced
+// @Parameter	targetHostIP host of the new instance, empty means allocating in the zone
+// @Parameter	targetZone zone code of the new instance, empty means the zone of source instance
+// @Parameter	diskID disk of the new instance, optional
+// @Return		the new instance
+// @Return		error
+func (p *ClusterMeta) AddMigrationInstance(ctx context.Context, source *management.ClusterInstance,
+	targetHostIP string, targetZone string, diskID string) (*management.ClusterInstance, error) {
+	if p.Cluster == nil {
+		return nil, errors.NewError(errors.TIUNIMANAGER_UNRECOGNIZED_ERROR, "cluster is nil!")
+	}
+	if len(source.HostIP) > 0 && targetHostIP == source.HostIP[0] {
+		return nil, errors.NewErrorf(errors.TIUNIMANAGER_MIGRATE_INSTANCE_INVALID,
+			"target host %s is the host of instance %s", targetHostIP, source.ID)
+	}
+
+	zone := source.Zone
+	if len(targetZone) > 0 {
+		zone = structs.GetDomainNameFromCode(targetZone)
+	}
+	ips := make([]string, 0)
+	if len(targetHostIP) > 0 {
+		ips = append(ips, targetHostIP)
+	}
+	instance := &management.ClusterInstance{
+		Entity: dbCommon.Entity{
+			TenantId: p.Cluster.TenantId,
+			Status:   string(constants.ClusterInstanceInitializing),
+		},
+		Type:         source.Type,
+		Version:      p.Cluster.Version,
+		ClusterID:    p.Cluster.ID,
+		CpuCores:     source.CpuCores,
+		Memory:       source.Memory,
+		Zone:         zone,
+		DiskType:     source.DiskType,
+		DiskCapacity: source.DiskCapacity,
+		DiskID:       diskID,
+		HostIP:       ips,
+	}
+	p.Instances[source.Type] = append(p.Instances[source.Type], instance)
+	framework.LogWithContext(ctx).Infof("add instance into cluster %s topology to replace instance %s", p.Cluster.ID, source.ID)
+	return instance, nil
+}
+
 func (p *ClusterMeta) AddDefaultInstances(ctx context.Context) error {
 	if string(constants.EMProductIDTiDB) == p.Cluster.Type {
 		for _, t := range constants.ParasiteComponentIDs {
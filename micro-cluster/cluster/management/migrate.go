@@ -0,0 +1,377 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package management
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/deployment"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	workflowModel "github.com/pingcap/tiunimanager/models/workflow"
+	workflow "github.com/pingcap/tiunimanager/workflow2"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+)
+
+const waitStoreUpTimeout = 10 * time.Minute
+
+// waitStoreUpPolicy fail the node waiting for the store of replacement to be up after waitStoreUpTimeout
+var waitStoreUpPolicy = &workflow.NodePolicy{
+	Timeout: waitStoreUpTimeout,
+}
+
+// migrateInstanceFlow scale out a replacement of the instance, then scale in the instance.
+// Regions of TiKV or TiFlash are moved off the old store by PD before it is pruned.
+// If it fails before the replacement is persisted, the replacement is scaled in and its resource is recycled,
+// otherwise the replacement is kept as a member of the cluster
+var migrateInstanceFlow = workflow.WorkFlowDefine{
+	FlowName: constants.FlowMigrateInstance,
+	TaskNodes: map[string]*workflow.NodeDefine{
+		"start":                {"prepareResource", "resourceDone", "fail", workflow.SyncFuncNode, prepareResource, nil},
+		"resourceDone":         {"checkMigrationTarget", "targetChecked", "fail", workflow.SyncFuncNode, checkMigrationTarget, nil},
+		"targetChecked":        {"buildConfig", "configDone", "fail", workflow.SyncFuncNode, buildConfig, nil},
+		"configDone":           {"scaleOutCluster", "scaleOutDone", "fail", workflow.PollingNode, scaleOutCluster, nil},
+		"scaleOutDone":         {"syncTopology", "syncTopologyDone", "failAfterScaleOut", workflow.SyncFuncNode, syncTopology, nil},
+		"syncTopologyDone":     {"waitReplacementReady", "replacementReady", "failAfterScaleOut", workflow.SyncFuncNode, waitReplacementReady, waitStoreUpPolicy},
+		"replacementReady":     {"setClusterOnline", "replacementPersisted", "failAfterScaleOut", workflow.SyncFuncNode, workflow.CompositeExecutor(setClusterOnline, persistCluster), nil},
+		"replacementPersisted": {"scaleInCluster", "scaleInDone", "failAfterReplaced", workflow.PollingNode, scaleInCluster, nil},
+		"scaleInDone":          {"checkInstanceStatus", "checkDone", "failAfterReplaced", workflow.SyncFuncNode, checkInstanceStatus, nil},
+		"checkDone":            {"freeInstanceResource", "freeDone", "failAfterReplaced", workflow.SyncFuncNode, freeInstanceResource, nil},
+		"freeDone":             {"end", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, endMaintenance, asyncBuildLog), nil},
+		"fail":                 {"fail", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(revertResourceAfterFailure, endMaintenance), nil},
+		"failAfterScaleOut":    {"failAfterScaleOut", "replacementScaledIn", "failAfterRevert", workflow.PollingNode, scaleInReplacement, nil},
+		"replacementScaledIn":  {"removeReplacement", "failAfterRevert", "failAfterRevert", workflow.SyncFuncNode, workflow.CompositeExecutor(revertResourceAfterFailure, removeReplacement), nil},
+		"failAfterRevert":      {"failAfterRevert", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, endMaintenance), nil},
+		"failAfterReplaced":    {"failAfterReplaced", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, endMaintenance), nil},
+	},
+}
+
+// MigrateInstance
+// @Description: migrate an instance of cluster to another host
+// @Receiver p
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (p *Manager) MigrateInstance(ctx context.Context, req cluster.MigrateInstanceReq) (resp cluster.MigrateInstanceResp, err error) {
+	clusterMeta, err := meta.Get(ctx, req.ClusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("load cluster %s meta from db error: %s", req.ClusterID, err.Error())
+		return
+	}
+	if err = checkClusterPermission(ctx, clusterMeta, constants.RbacActionUpdate); err != nil {
+		return
+	}
+	flowID, err := p.migrateInstance(ctx, clusterMeta, req)
+	if err != nil {
+		return
+	}
+
+	resp.ClusterID = req.ClusterID
+	resp.InstanceID = req.InstanceID
+	resp.WorkFlowID = flowID
+	return
+}
+
+func (p *Manager) migrateInstance(ctx context.Context, clusterMeta *meta.ClusterMeta, req cluster.MigrateInstanceReq) (flowID string, err error) {
+	if clusterMeta.Cluster.Status != string(constants.ClusterRunning) {
+		err = errors.NewErrorf(errors.TIUNIMANAGER_MIGRATE_INSTANCE_INVALID, "cluster %s is %s, only instance of running cluster can be migrated",
+			req.ClusterID, clusterMeta.Cluster.Status)
+		return
+	}
+
+	instance, err := clusterMeta.GetInstance(ctx, req.InstanceID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("cluster %s has no instance %s", req.ClusterID, req.InstanceID)
+		return
+	}
+	if meta.Contain(constants.ParasiteComponentIDs, constants.EMProductComponentIDType(instance.Type)) {
+		err = errors.NewErrorf(errors.TIUNIMANAGER_MIGRATE_INSTANCE_INVALID, "instance %s of %s can not be migrated", instance.ID, instance.Type)
+		return
+	}
+	if len(clusterMeta.GetInstanceByStatus(ctx, constants.ClusterInstanceInitializing)) > 0 {
+		err = errors.NewErrorf(errors.TIUNIMANAGER_MIGRATE_INSTANCE_INVALID, "cluster %s has instances being initialized", req.ClusterID)
+		return
+	}
+
+	// Add the replacement into cluster topology, resource is allocated in workflow
+	if _, err = clusterMeta.AddMigrationInstance(ctx, instance, req.TargetHostIP, req.TargetZone, req.DiskID); err != nil {
+		framework.LogWithContext(ctx).Errorf("add replacement of instance %s error: %s", req.InstanceID, err.Error())
+		return
+	}
+
+	data := map[string]interface{}{
+		ContextClusterMeta: clusterMeta,
+		ContextInstanceID:  req.InstanceID,
+	}
+	flowID, err = asyncMaintenance(ctx, clusterMeta, constants.ClusterMaintenanceMigrating, migrateInstanceFlow.FlowName, data)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("cluster %s async maintenance error: %s", req.ClusterID, err.Error())
+	}
+	return
+}
+
+// EvacuateInstance
+// @Description: migrate the instance to another host allocated by resource manager, it is called when draining a host
+// @Receiver p
+// @Parameter ctx
+// @Parameter clusterID
+// @Parameter instanceID
+// @return workflowID
+// @return err
+func (p *Manager) EvacuateInstance(ctx context.Context, clusterID string, instanceID string) (workflowID string, err error) {
+	clusterMeta, err := meta.Get(ctx, clusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("load cluster %s meta from db error: %s", clusterID, err.Error())
+		return
+	}
+	return p.migrateInstance(ctx, clusterMeta, cluster.MigrateInstanceReq{ClusterID: clusterID, InstanceID: instanceID})
+}
+
+// EvictLeaders
+// @Description: evict region leaders from TiKV instances of the cluster on the host, it is called when draining a host
+// @Receiver p
+// @Parameter ctx
+// @Parameter clusterID
+// @Parameter hostID
+// @return storeIDs stores whose leaders are evicted, including those evicted before failing
+// @return err
+func (p *Manager) EvictLeaders(ctx context.Context, clusterID string, hostID string) (storeIDs []int, err error) {
+	clusterMeta, err := meta.Get(ctx, clusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("load cluster %s meta from db error: %s", clusterID, err.Error())
+		return nil, err
+	}
+	addresses := make(map[string]bool)
+	for _, instance := range clusterMeta.Instances[string(constants.ComponentIDTiKV)] {
+		if instance.HostID == hostID {
+			addresses[storeAddress(instance)] = true
+		}
+	}
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+
+	storeInfos, err := queryStores(ctx, clusterMeta)
+	if err != nil {
+		return nil, err
+	}
+	storeIDs = make([]int, 0, len(addresses))
+	for _, info := range storeInfos.Stores {
+		if !addresses[info.Store.Address] {
+			continue
+		}
+		_, err = pdCtl(ctx, clusterMeta, "scheduler", "add", "evict-leader-scheduler", strconv.Itoa(info.Store.ID))
+		if err != nil {
+			framework.LogWithContext(ctx).Errorf("evict leaders of store %d in cluster %s error: %s", info.Store.ID, clusterID, err.Error())
+			return storeIDs, err
+		}
+		storeIDs = append(storeIDs, info.Store.ID)
+		framework.LogWithContext(ctx).Infof("evict %d leaders of store %d in cluster %s", info.Status.LeaderCount, info.Store.ID, clusterID)
+	}
+	return storeIDs, nil
+}
+
+// RemoveLeaderEviction
+// @Description: remove the evict leader scheduler of the stores added by EvictLeaders, it is called when draining a host ends
+// @Receiver p
+// @Parameter ctx
+// @Parameter clusterID
+// @Parameter storeIDs
+// @return error
+func (p *Manager) RemoveLeaderEviction(ctx context.Context, clusterID string, storeIDs []int) error {
+	clusterMeta, err := meta.Get(ctx, clusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("load cluster %s meta from db error: %s", clusterID, err.Error())
+		return err
+	}
+	for _, storeID := range storeIDs {
+		_, err = pdCtl(ctx, clusterMeta, "scheduler", "remove", fmt.Sprintf("evict-leader-scheduler-%d", storeID))
+		if err != nil {
+			framework.LogWithContext(ctx).Errorf("remove leader eviction of store %d in cluster %s error: %s", storeID, clusterID, err.Error())
+			return err
+		}
+		framework.LogWithContext(ctx).Infof("remove leader eviction of store %d in cluster %s", storeID, clusterID)
+	}
+	return nil
+}
+
+// getMigrationInstances
+// @Description: get the instance being migrated and its replacement
+func getMigrationInstances(context *workflow.FlowContext) (*meta.ClusterMeta, *management.ClusterInstance, *management.ClusterInstance, error) {
+	clusterMeta := &meta.ClusterMeta{}
+	err := context.GetData(ContextClusterMeta, clusterMeta)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var instanceID string
+	err = context.GetData(ContextInstanceID, &instanceID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	source, err := clusterMeta.GetInstance(context, instanceID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	for _, instance := range clusterMeta.GetInstanceByStatus(context, constants.ClusterInstanceInitializing) {
+		if instance.Type == source.Type {
+			return clusterMeta, source, instance, nil
+		}
+	}
+	return nil, nil, nil, errors.NewErrorf(errors.TIUNIMANAGER_MIGRATE_INSTANCE_FAILED, "replacement of instance %s not found", instanceID)
+}
+
+// checkMigrationTarget
+// @Description: the replacement must not be allocated on the host of the instance being migrated
+func checkMigrationTarget(node *workflowModel.WorkFlowNode, context *workflow.FlowContext) error {
+	clusterMeta, source, replacement, err := getMigrationInstances(context)
+	if err != nil {
+		return err
+	}
+	if len(replacement.HostIP) == 0 {
+		return errors.NewErrorf(errors.TIUNIMANAGER_MIGRATE_INSTANCE_FAILED, "no host allocated for replacement of instance %s", source.ID)
+	}
+	if len(source.HostIP) > 0 && replacement.HostIP[0] == source.HostIP[0] {
+		return errors.NewErrorf(errors.TIUNIMANAGER_MIGRATE_INSTANCE_FAILED,
+			"host %s allocated for replacement is the host of instance %s, specify another target host or zone", replacement.HostIP[0], source.ID)
+	}
+	node.Record(fmt.Sprintf("migrate %s instance %s of cluster %s from host %s to host %s ",
+		source.Type, source.ID, clusterMeta.Cluster.ID, strings.Join(source.HostIP, ","), replacement.HostIP[0]))
+	return nil
+}
+
+// storeAddress
+// @Description: address of TiKV or TiFlash store registered in PD, empty for other components
+func storeAddress(instance *management.ClusterInstance) string {
+	switch instance.Type {
+	case string(constants.ComponentIDTiKV):
+		return strings.Join([]string{instance.HostIP[0], strconv.Itoa(int(instance.Ports[0]))}, ":")
+	case string(constants.ComponentIDTiFlash):
+		return strings.Join([]string{instance.HostIP[0], strconv.Itoa(int(instance.Ports[2]))}, ":") // specify server port
+	default:
+		return ""
+	}
+}
+
+// waitReplacementReady
+// @Description: if the replacement is TiKV or TiFlash, wait until its store is up, then regions can be moved onto it
+func waitReplacementReady(node *workflowModel.WorkFlowNode, context *workflow.FlowContext) error {
+	clusterMeta, _, replacement, err := getMigrationInstances(context)
+	if err != nil {
+		return err
+	}
+	address := storeAddress(replacement)
+	if address == "" {
+		return nil
+	}
+
+	ticker := time.NewTicker(meta.CheckInstanceStatusInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-context.Done():
+			// the node is past its deadline, or the workflow is canceled
+			return errors.NewErrorf(errors.TIUNIMANAGER_CHECK_INSTANCE_TIUNIMANAGEROUT_ERROR, "wait store %s up canceled, %s", address, context.Err())
+		case <-ticker.C:
+		}
+		storeInfos, err := queryStores(context, clusterMeta, "--state", "Up")
+		if err != nil {
+			return err
+		}
+		for _, info := range storeInfos.Stores {
+			if info.Store.Address == address && info.Store.StateName == string(meta.StoreUp) {
+				node.Record(fmt.Sprintf("store %d of replacement %s is up ", info.Store.ID, address))
+				return nil
+			}
+		}
+	}
+}
+
+// scaleInReplacement
+// @Description: scale in the replacement after the migration fails. It is removed by force, because its store may be not up,
+// and the instance being migrated is still a member of the cluster, regions on the replacement are replicated again by PD
+func scaleInReplacement(node *workflowModel.WorkFlowNode, context *workflow.FlowContext) error {
+	clusterMeta, _, replacement, err := getMigrationInstances(context)
+	if err != nil {
+		return err
+	}
+	nodeID := strings.Join([]string{replacement.HostIP[0], strconv.Itoa(int(replacement.Ports[0]))}, ":")
+	operationID, err := deployment.M.ScaleIn(context.Context, deployment.TiUPComponentTypeCluster, clusterMeta.Cluster.ID,
+		nodeID, framework.GetTiupHomePathForTidb(), node.ParentID, []string{"--force"}, meta.DefaultTiupTimeOut)
+	if err != nil {
+		framework.LogWithContext(context.Context).Errorf(
+			"cluster %s scale in replacement %s error: %s", clusterMeta.Cluster.ID, nodeID, err.Error())
+		return err
+	}
+	node.Record(fmt.Sprintf("scale in replacement %s of cluster %s ", nodeID, clusterMeta.Cluster.ID))
+	node.OperationID = operationID
+	return nil
+}
+
+// removeReplacement
+// @Description: remove the replacement scaled in from cluster topology, then it will not be persisted
+func removeReplacement(node *workflowModel.WorkFlowNode, context *workflow.FlowContext) error {
+	clusterMeta, _, replacement, err := getMigrationInstances(context)
+	if err != nil {
+		return err
+	}
+	instances := clusterMeta.Instances[replacement.Type]
+	for index, instance := range instances {
+		if instance == replacement {
+			clusterMeta.Instances[replacement.Type] = append(instances[:index], instances[index+1:]...)
+			break
+		}
+	}
+	context.SetData(ContextClusterMeta, clusterMeta)
+	node.Record(fmt.Sprintf("remove replacement %s from cluster %s ", strings.Join(replacement.HostIP, ","), clusterMeta.Cluster.ID))
+	return nil
+}
+
+// pdCtl
+// @Description: run pd-ctl command on the cluster
+func pdCtl(ctx context.Context, clusterMeta *meta.ClusterMeta, args ...string) (string, error) {
+	pdAddress := clusterMeta.GetPDClientAddresses()
+	if len(pdAddress) <= 0 {
+		return "", errors.NewError(errors.TIUNIMANAGER_PD_NOT_FOUND_ERROR, "cluster not found pd instance")
+	}
+	pdID := strings.Join([]string{pdAddress[0].IP, strconv.Itoa(pdAddress[0].Port)}, ":")
+	return deployment.M.Ctl(ctx, deployment.TiUPComponentTypeCtrl, clusterMeta.Cluster.Version, spec.ComponentPD,
+		framework.GetTiupHomePathForTidb(), append([]string{"-u", pdID}, args...), meta.DefaultTiupTimeOut)
+}
+
+// queryStores
+// @Description: query TiKV and TiFlash stores registered in PD
+func queryStores(ctx context.Context, clusterMeta *meta.ClusterMeta, args ...string) (*meta.StoreInfos, error) {
+	output, err := pdCtl(ctx, clusterMeta, append([]string{"store"}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	storeInfos := &meta.StoreInfos{}
+	if err = json.Unmarshal([]byte(output), storeInfos); err != nil {
+		return nil, errors.WrapError(errors.TIUNIMANAGER_UNMARSHAL_ERROR,
+			fmt.Sprintf("parse TiKV or TiFlash store status error: %s", err.Error()), err)
+	}
+	return storeInfos, nil
+}
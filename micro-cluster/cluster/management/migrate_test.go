@@ -0,0 +1,150 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package management
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/deployment"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	"github.com/pingcap/tiunimanager/models/common"
+	workflowModel "github.com/pingcap/tiunimanager/models/workflow"
+	mock_deployment "github.com/pingcap/tiunimanager/test/mockdeployment"
+	workflow "github.com/pingcap/tiunimanager/workflow2"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockMigrationFlowContext(replacementHost string) *workflow.FlowContext {
+	flowContext := workflow.NewFlowContext(context.TODO(), make(map[string]string))
+	flowContext.SetData(ContextClusterMeta, &meta.ClusterMeta{
+		Cluster: &management.Cluster{
+			Entity:  common.Entity{ID: "testCluster"},
+			Version: "v5.0.0",
+		},
+		Instances: map[string][]*management.ClusterInstance{
+			"TiKV": {
+				{
+					Entity: common.Entity{ID: "instance01", Status: string(constants.ClusterInstanceRunning)},
+					Type:   "TiKV",
+					HostIP: []string{"127.0.0.1"},
+					Ports:  []int32{20160, 20180},
+				},
+				{
+					Entity: common.Entity{Status: string(constants.ClusterInstanceInitializing)},
+					Type:   "TiKV",
+					HostIP: []string{replacementHost},
+					Ports:  []int32{20160, 20180},
+				},
+			},
+		},
+	})
+	flowContext.SetData(ContextInstanceID, "instance01")
+	return flowContext
+}
+
+func TestCheckMigrationTarget(t *testing.T) {
+	t.Run("normal", func(t *testing.T) {
+		err := checkMigrationTarget(&workflowModel.WorkFlowNode{}, mockMigrationFlowContext("127.0.0.2"))
+		assert.NoError(t, err)
+	})
+	t.Run("same host", func(t *testing.T) {
+		err := checkMigrationTarget(&workflowModel.WorkFlowNode{}, mockMigrationFlowContext("127.0.0.1"))
+		assert.Error(t, err)
+	})
+	t.Run("no replacement", func(t *testing.T) {
+		flowContext := mockMigrationFlowContext("127.0.0.2")
+		flowContext.SetData(ContextInstanceID, "instance02")
+		err := checkMigrationTarget(&workflowModel.WorkFlowNode{}, flowContext)
+		assert.Error(t, err)
+	})
+}
+
+func TestStoreAddress(t *testing.T) {
+	assert.Equal(t, "127.0.0.1:20160", storeAddress(&management.ClusterInstance{
+		Type: string(constants.ComponentIDTiKV), HostIP: []string{"127.0.0.1"}, Ports: []int32{20160, 20180},
+	}))
+	assert.Equal(t, "127.0.0.1:3930", storeAddress(&management.ClusterInstance{
+		Type: string(constants.ComponentIDTiFlash), HostIP: []string{"127.0.0.1"}, Ports: []int32{9000, 8123, 3930},
+	}))
+	assert.Empty(t, storeAddress(&management.ClusterInstance{
+		Type: string(constants.ComponentIDTiDB), HostIP: []string{"127.0.0.1"}, Ports: []int32{4000},
+	}))
+}
+
+func TestWaitReplacementReady_NotStore(t *testing.T) {
+	flowContext := workflow.NewFlowContext(context.TODO(), make(map[string]string))
+	flowContext.SetData(ContextClusterMeta, &meta.ClusterMeta{
+		Cluster: &management.Cluster{Entity: common.Entity{ID: "testCluster"}},
+		Instances: map[string][]*management.ClusterInstance{
+			"TiDB": {
+				{Entity: common.Entity{ID: "instance01", Status: string(constants.ClusterInstanceRunning)}, Type: "TiDB", HostIP: []string{"127.0.0.1"}, Ports: []int32{4000}},
+				{Entity: common.Entity{Status: string(constants.ClusterInstanceInitializing)}, Type: "TiDB", HostIP: []string{"127.0.0.2"}, Ports: []int32{4000}},
+			},
+		},
+	})
+	flowContext.SetData(ContextInstanceID, "instance01")
+	assert.NoError(t, waitReplacementReady(&workflowModel.WorkFlowNode{}, flowContext))
+}
+
+func TestWaitReplacementReady_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	flowContext := mockMigrationFlowContext("127.0.0.2")
+	flowContext.Context = ctx
+	assert.Error(t, waitReplacementReady(&workflowModel.WorkFlowNode{}, flowContext))
+}
+
+func TestScaleInReplacement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("normal", func(t *testing.T) {
+		mockTiupManager := mock_deployment.NewMockInterface(ctrl)
+		mockTiupManager.EXPECT().ScaleIn(gomock.Any(), deployment.TiUPComponentTypeCluster, "testCluster", "127.0.0.2:20160",
+			gomock.Any(), gomock.Any(), []string{"--force"}, gomock.Any()).Return("task01", nil)
+		deployment.M = mockTiupManager
+
+		node := &workflowModel.WorkFlowNode{}
+		assert.NoError(t, scaleInReplacement(node, mockMigrationFlowContext("127.0.0.2")))
+		assert.Equal(t, "task01", node.OperationID)
+	})
+	t.Run("scale in fail", func(t *testing.T) {
+		mockTiupManager := mock_deployment.NewMockInterface(ctrl)
+		mockTiupManager.EXPECT().ScaleIn(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return("", fmt.Errorf("fail"))
+		deployment.M = mockTiupManager
+
+		assert.Error(t, scaleInReplacement(&workflowModel.WorkFlowNode{}, mockMigrationFlowContext("127.0.0.2")))
+	})
+}
+
+func TestRemoveReplacement(t *testing.T) {
+	flowContext := mockMigrationFlowContext("127.0.0.2")
+	assert.NoError(t, removeReplacement(&workflowModel.WorkFlowNode{}, flowContext))
+
+	var clusterMeta meta.ClusterMeta
+	assert.NoError(t, flowContext.GetData(ContextClusterMeta, &clusterMeta))
+	assert.Len(t, clusterMeta.Instances["TiKV"], 1)
+	assert.Equal(t, "instance01", clusterMeta.Instances["TiKV"][0].ID)
+	// the replacement is not picked up again
+	_, _, _, err := getMigrationInstances(flowContext)
+	assert.Error(t, err)
+}
@@ -0,0 +1,169 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package management
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/maintainwindow"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/models"
+)
+
+// registerPendingOperations
+// @Description: register executors of cluster operations which may be queued until the maintain window opens
+// @Receiver p
+func (p *Manager) registerPendingOperations() {
+	maintainwindow.RegisterOperation(constants.PendingOperationRestartCluster, func(ctx context.Context, request string) (string, error) {
+		req := cluster.RestartClusterReq{}
+		if err := json.Unmarshal([]byte(request), &req); err != nil {
+			return "", errors.WrapError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, "unmarshal restart cluster request failed", err)
+		}
+		// the window has been checked by the scheduler
+		req.IgnoreMaintainWindow = true
+		resp, err := p.RestartCluster(ctx, req)
+		return resp.WorkFlowID, err
+	})
+	maintainwindow.RegisterOperation(constants.PendingOperationUpgradeCluster, func(ctx context.Context, request string) (string, error) {
+		req := cluster.UpgradeClusterReq{}
+		if err := json.Unmarshal([]byte(request), &req); err != nil {
+			return "", errors.WrapError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, "unmarshal upgrade cluster request failed", err)
+		}
+		req.IgnoreMaintainWindow = true
+		resp, err := p.InPlaceUpgradeCluster(ctx, req)
+		return resp.WorkFlowID, err
+	})
+	maintainwindow.RegisterOperation(constants.PendingOperationStopInstance, func(ctx context.Context, request string) (string, error) {
+		req := cluster.StopInstanceReq{}
+		if err := json.Unmarshal([]byte(request), &req); err != nil {
+			return "", errors.WrapError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, "unmarshal stop instance request failed", err)
+		}
+		req.IgnoreMaintainWindow = true
+		resp, err := p.StopInstance(ctx, req)
+		return resp.WorkFlowID, err
+	})
+	maintainwindow.RegisterOperation(constants.PendingOperationRestartInstance, func(ctx context.Context, request string) (string, error) {
+		req := cluster.RestartInstanceReq{}
+		if err := json.Unmarshal([]byte(request), &req); err != nil {
+			return "", errors.WrapError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, "unmarshal restart instance request failed", err)
+		}
+		req.IgnoreMaintainWindow = true
+		resp, err := p.RestartInstance(ctx, req)
+		return resp.WorkFlowID, err
+	})
+	maintainwindow.RegisterOperation(constants.PendingOperationReloadInstance, func(ctx context.Context, request string) (string, error) {
+		req := cluster.ReloadInstanceReq{}
+		if err := json.Unmarshal([]byte(request), &req); err != nil {
+			return "", errors.WrapError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, "unmarshal reload instance request failed", err)
+		}
+		req.IgnoreMaintainWindow = true
+		resp, err := p.ReloadInstance(ctx, req)
+		return resp.WorkFlowID, err
+	})
+}
+
+// QueryPendingOperations
+// @Description: query operations of cluster queued for the maintain window
+// @Receiver p
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (p *Manager) QueryPendingOperations(ctx context.Context, req cluster.QueryPendingOperationsReq) (resp cluster.QueryPendingOperationsResp, err error) {
+	clusterMeta, err := meta.Get(ctx, req.ClusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("load cluster %s meta from db error: %s", req.ClusterID, err.Error())
+		return
+	}
+	if err = checkClusterPermission(ctx, clusterMeta, constants.RbacActionRead); err != nil {
+		return
+	}
+
+	operations, err := models.GetClusterReaderWriter().QueryPendingOperations(ctx, req.ClusterID, constants.PendingOperationStatus(req.Status))
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query pending operations of cluster %s failed, %s", req.ClusterID, err.Error())
+		return
+	}
+	resp.Operations = make([]structs.PendingOperationInfo, 0, len(operations))
+	for _, operation := range operations {
+		resp.Operations = append(resp.Operations, structs.PendingOperationInfo{
+			ID:            operation.ID,
+			ClusterID:     operation.ClusterID,
+			OperationType: operation.OperationType,
+			Status:        operation.Status,
+			WorkFlowID:    operation.WorkFlowID,
+			Message:       operation.Message,
+			CreateTime:    operation.CreatedAt,
+			LaunchTime:    operation.LaunchTime,
+		})
+	}
+	return
+}
+
+// CancelPendingOperation
+// @Description: cancel an operation which is still waiting for the maintain window
+// @Receiver p
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (p *Manager) CancelPendingOperation(ctx context.Context, req cluster.CancelPendingOperationReq) (resp cluster.CancelPendingOperationResp, err error) {
+	clusterMeta, err := meta.Get(ctx, req.ClusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("load cluster %s meta from db error: %s", req.ClusterID, err.Error())
+		return
+	}
+	if err = checkClusterPermission(ctx, clusterMeta, constants.RbacActionUpdate); err != nil {
+		return
+	}
+
+	rw := models.GetClusterReaderWriter()
+	operation, err := rw.GetPendingOperation(ctx, req.OperationID)
+	if err != nil {
+		return
+	}
+	if operation.ClusterID != req.ClusterID {
+		err = errors.NewErrorf(errors.TIUNIMANAGER_PENDING_OPERATION_NOT_FOUND, "pending operation %s not found in cluster %s", req.OperationID, req.ClusterID)
+		return
+	}
+	if operation.Status != string(constants.PendingOperationWaiting) {
+		err = errors.NewErrorf(errors.TIUNIMANAGER_TASK_CONFLICT, "pending operation %s is %s, only waiting operation can be canceled", req.OperationID, operation.Status)
+		return
+	}
+	// the scheduler may be launching it at the same time
+	claimed, err := rw.ClaimPendingOperation(ctx, req.OperationID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("claim pending operation %s failed, %s", req.OperationID, err.Error())
+		return
+	}
+	if !claimed {
+		err = errors.NewErrorf(errors.TIUNIMANAGER_TASK_CONFLICT, "pending operation %s is being launched, only waiting operation can be canceled", req.OperationID)
+		return
+	}
+	err = rw.UpdatePendingOperation(ctx, req.OperationID, constants.PendingOperationCanceled, "",
+		fmt.Sprintf("canceled by %s", framework.GetUserIDFromContext(ctx)))
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("cancel pending operation %s failed, %s", req.OperationID, err.Error())
+	}
+	return
+}
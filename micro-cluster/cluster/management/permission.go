@@ -0,0 +1,95 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package management
+
+import (
+	"context"
+	"math"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/micro-cluster/user/rbac"
+)
+
+// checkClusterPermission
+// @Description: deny the request if current user has no permission of action on the cluster
+// @Parameter ctx
+// @Parameter clusterMeta
+// @Parameter action
+// @return error
+func checkClusterPermission(ctx context.Context, clusterMeta *meta.ClusterMeta, action constants.RbacAction) error {
+	cluster := clusterMeta.Cluster
+	return rbac.CheckClusterPermission(ctx, cluster.ID, cluster.Tags, cluster.TenantId, action)
+}
+
+// queryClusterWithPermission
+// @Description: query clusters and filter out those current user has no permission to read.
+// If user can read all clusters, query by page directly, otherwise query all clusters and page them after filtering
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return total
+// @return err
+func queryClusterWithPermission(ctx context.Context, req cluster.QueryClustersReq) (resp cluster.QueryClusterResp, total int, err error) {
+	readAll, err := rbac.HasClusterPermission(ctx, constants.RbacActionRead, nil)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("check permission of all clusters failed, %s", err.Error())
+		return
+	}
+	if readAll {
+		return meta.Query(ctx, req)
+	}
+
+	page := req.PageRequest
+	req.PageRequest = structs.PageRequest{Page: 1, PageSize: math.MaxInt32}
+	all, _, err := meta.Query(ctx, req)
+	if err != nil {
+		return
+	}
+
+	allowed := make([]structs.ClusterInfo, 0)
+	for _, info := range all.Clusters {
+		ok, checkErr := rbac.HasClusterPermission(ctx, constants.RbacActionRead, rbac.ClusterPermissionInstances(info.ID, info.Tags, info.TenantID))
+		if checkErr != nil {
+			err = checkErr
+			framework.LogWithContext(ctx).Errorf("check permission of cluster %s failed, %s", info.ID, err.Error())
+			return
+		}
+		if ok {
+			allowed = append(allowed, info)
+		}
+	}
+
+	total = len(allowed)
+	resp.Clusters = make([]structs.ClusterInfo, 0)
+	if page.PageSize <= 0 {
+		resp.Clusters = allowed
+		return
+	}
+	start := page.GetOffset()
+	if start >= total {
+		return
+	}
+	end := start + page.PageSize
+	if end > total {
+		end = total
+	}
+	resp.Clusters = allowed[start:end]
+	return
+}
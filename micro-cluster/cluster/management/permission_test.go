@@ -0,0 +1,72 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package management
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/micro-cluster/user/rbac"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	"github.com/pingcap/tiunimanager/models/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRBACService struct {
+	rbac.RBACService
+	allowed map[string]bool
+}
+
+func (f *fakeRBACService) CheckPermissionForInstances(ctx context.Context, request message.CheckPermissionForInstancesReq) (message.CheckPermissionForInstancesResp, error) {
+	for _, instance := range request.Instances {
+		if f.allowed[instance] {
+			return message.CheckPermissionForInstancesResp{Result: true}, nil
+		}
+	}
+	return message.CheckPermissionForInstancesResp{Result: false}, nil
+}
+
+func Test_checkClusterPermission(t *testing.T) {
+	rbac.MockRBACService(&fakeRBACService{allowed: map[string]bool{"tag/team=dba": true}})
+	defer rbac.MockRBACService(nil)
+
+	clusterMeta := &meta.ClusterMeta{
+		Cluster: &management.Cluster{
+			Entity: common.Entity{ID: "cluster01", TenantId: "tenant01"},
+			Tags:   []string{"team=dba"},
+		},
+	}
+
+	t.Run("internal", func(t *testing.T) {
+		assert.NoError(t, checkClusterPermission(context.TODO(), clusterMeta, constants.RbacActionDelete))
+	})
+	t.Run("allowed", func(t *testing.T) {
+		ctx := &gin.Context{}
+		ctx.Set(framework.TiUniManager_X_USER_ID_KEY, "dba")
+		assert.NoError(t, checkClusterPermission(framework.NewMicroCtxFromGinCtx(ctx), clusterMeta, constants.RbacActionRead))
+	})
+	t.Run("denied", func(t *testing.T) {
+		ctx := &gin.Context{}
+		ctx.Set(framework.TiUniManager_X_USER_ID_KEY, "dba")
+		clusterMeta.Cluster.Tags = []string{"team=dev"}
+		assert.Error(t, checkClusterPermission(framework.NewMicroCtxFromGinCtx(ctx), clusterMeta, constants.RbacActionRead))
+	})
+}
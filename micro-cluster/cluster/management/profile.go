@@ -0,0 +1,137 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package management
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/models"
+	log "github.com/sirupsen/logrus"
+)
+
+// UpdateClusterProfile
+// @Description: update name, tags, exclusive flag and maintain window of cluster, the change is recorded in audit log
+// @Receiver p
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (p *Manager) UpdateClusterProfile(ctx context.Context, req cluster.UpdateClusterProfileReq) (resp cluster.UpdateClusterProfileResp, err error) {
+	clusterMeta, err := meta.Get(ctx, req.ClusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("load cluster %s meta from db error: %s", req.ClusterID, err.Error())
+		return
+	}
+	if err = checkClusterPermission(ctx, clusterMeta, constants.RbacActionUpdate); err != nil {
+		return
+	}
+
+	if _, windowErr := meta.ParseMaintainWindow(req.MaintainWindow); windowErr != nil {
+		err = errors.WrapError(errors.TIUNIMANAGER_PARAMETER_INVALID, "invalid maintain window", windowErr)
+		return
+	}
+	tags, removedKeys, err := diffClusterTags(clusterMeta.Cluster.Tags, req.Tags)
+	if err != nil {
+		return
+	}
+
+	original := *clusterMeta.Cluster
+	rw := models.GetClusterReaderWriter()
+	// profile and tags are saved together, the audit log is written after the transaction is committed
+	err = models.Transaction(ctx, func(transactionCtx context.Context) error {
+		if updateErr := rw.UpdateClusterProfile(transactionCtx, req.ClusterID, req.Name, req.Exclusive, req.MaintainWindow); updateErr != nil {
+			return updateErr
+		}
+		_, updateErr := rw.UpdateClusterTags(transactionCtx, req.ClusterID, tags, removedKeys)
+		return updateErr
+	})
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("update profile of cluster %s failed, %s", req.ClusterID, err.Error())
+		return
+	}
+
+	// refresh meta to display the cluster as it is saved
+	clusterMeta, err = meta.Get(ctx, req.ClusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("load cluster %s meta from db error: %s", req.ClusterID, err.Error())
+		return
+	}
+	framework.LogForkFile(constants.LogFileAudit).WithFields(log.Fields{
+		"operatorID":         framework.GetUserIDFromContext(ctx),
+		"operatorFinishTime": time.Now(),
+		"event":              "UpdateClusterProfile",
+		"operation":          req.ClusterID,
+		"original": map[string]interface{}{
+			"name":           original.Name,
+			"tags":           original.Tags,
+			"exclusive":      original.Exclusive,
+			"maintainWindow": original.MaintainWindow,
+		},
+		"updated": map[string]interface{}{
+			"name":           clusterMeta.Cluster.Name,
+			"tags":           clusterMeta.Cluster.Tags,
+			"exclusive":      clusterMeta.Cluster.Exclusive,
+			"maintainWindow": clusterMeta.Cluster.MaintainWindow,
+		},
+	}).Info()
+
+	resp.Info = clusterMeta.DisplayClusterInfo(ctx)
+	return
+}
+
+// diffClusterTags
+// @Description: get tags to be saved and keys to be removed, so that the cluster has exactly the expected tags.
+// Tag of takeover is kept as it is, and can not be added
+// @Parameter current
+// @Parameter expected
+// @return []structs.ClusterTag
+// @return []string
+// @return error
+func diffClusterTags(current []string, expected []string) ([]structs.ClusterTag, []string, error) {
+	tags, err := parseClusterTags(expected)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	added := make([]structs.ClusterTag, 0, len(tags))
+	keys := map[string]bool{meta.TagTakeover: true}
+	for _, tag := range tags {
+		if tag.Key == meta.TagTakeover {
+			if !meta.Contain(current, meta.TagTakeover) {
+				return nil, nil, validateClusterTagKey(tag.Key)
+			}
+			continue
+		}
+		keys[tag.Key] = true
+		added = append(added, tag)
+	}
+
+	removedKeys := make([]string, 0)
+	for _, tag := range current {
+		if key := structs.ParseClusterTag(tag).Key; key != "" && !keys[key] {
+			removedKeys = append(removedKeys, key)
+			keys[key] = true
+		}
+	}
+	return added, removedKeys, nil
+}
@@ -0,0 +1,97 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package management
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	"github.com/pingcap/tiunimanager/test/mockmodels/mockclustermanagement"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_diffClusterTags(t *testing.T) {
+	t.Run("normal", func(t *testing.T) {
+		added, removedKeys, err := diffClusterTags([]string{"env=test", "backup", "legacy"}, []string{"env=prod", "team=dba", "backup"})
+		assert.NoError(t, err)
+		assert.Equal(t, []structs.ClusterTag{{Key: "env", Value: "prod"}, {Key: "team", Value: "dba"}, {Key: "backup"}}, added)
+		assert.Equal(t, []string{"legacy"}, removedKeys)
+	})
+	t.Run("clear", func(t *testing.T) {
+		added, removedKeys, err := diffClusterTags([]string{"env=test", "backup"}, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, added)
+		assert.Equal(t, []string{"env", "backup"}, removedKeys)
+	})
+	t.Run("takeover", func(t *testing.T) {
+		added, removedKeys, err := diffClusterTags([]string{meta.TagTakeover, "env=test"}, []string{"env=prod"})
+		assert.NoError(t, err)
+		assert.Equal(t, []structs.ClusterTag{{Key: "env", Value: "prod"}}, added)
+		assert.Empty(t, removedKeys)
+
+		added, _, err = diffClusterTags([]string{meta.TagTakeover}, []string{meta.TagTakeover, "env=prod"})
+		assert.NoError(t, err)
+		assert.Equal(t, []structs.ClusterTag{{Key: "env", Value: "prod"}}, added)
+
+		_, _, err = diffClusterTags([]string{"env=test"}, []string{meta.TagTakeover})
+		assert.Error(t, err)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := diffClusterTags([]string{}, []string{"env=a b"})
+		assert.Error(t, err)
+	})
+}
+
+func TestManager_UpdateClusterProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	manager := Manager{}
+	req := cluster.UpdateClusterProfileReq{ClusterID: "111", Name: "renamed", Tags: []string{"env=prod"}}
+	t.Run("normal", func(t *testing.T) {
+		clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+		models.SetClusterReaderWriter(clusterRW)
+		clusterRW.EXPECT().GetMeta(gomock.Any(), "111").Return(mockRecycleCluster(constants.ClusterMaintenanceNone, false),
+			[]*management.ClusterInstance{}, make([]*management.DBUser, 0), nil).Times(2)
+		clusterRW.EXPECT().UpdateClusterProfile(gomock.Any(), "111", "renamed", false, "").Return(nil)
+		clusterRW.EXPECT().UpdateClusterTags(gomock.Any(), "111", []structs.ClusterTag{{Key: "env", Value: "prod"}}, []string{}).
+			Return([]string{"env=prod"}, nil)
+
+		_, err := manager.UpdateClusterProfile(context.TODO(), req)
+		assert.NoError(t, err)
+	})
+	t.Run("update tags failed", func(t *testing.T) {
+		clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+		models.SetClusterReaderWriter(clusterRW)
+		clusterRW.EXPECT().GetMeta(gomock.Any(), "111").Return(mockRecycleCluster(constants.ClusterMaintenanceNone, false),
+			[]*management.ClusterInstance{}, make([]*management.DBUser, 0), nil).Times(1)
+		clusterRW.EXPECT().UpdateClusterProfile(gomock.Any(), "111", "renamed", false, "").Return(nil)
+		clusterRW.EXPECT().UpdateClusterTags(gomock.Any(), "111", gomock.Any(), gomock.Any()).
+			Return(nil, errors.Error(errors.TIUNIMANAGER_SQL_ERROR))
+
+		_, err := manager.UpdateClusterProfile(context.TODO(), req)
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_SQL_ERROR, err.(errors.EMError).GetCode())
+	})
+}
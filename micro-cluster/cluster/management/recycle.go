@@ -0,0 +1,413 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package management
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	dbCommon "github.com/pingcap/tiunimanager/models/common"
+	workflowModel "github.com/pingcap/tiunimanager/models/workflow"
+	workflow "github.com/pingcap/tiunimanager/workflow2"
+	"github.com/robfig/cron"
+	log "github.com/sirupsen/logrus"
+)
+
+const purgeJobSpec = "0 * * * * *" // every minute
+
+var recycleSchedulerOnce sync.Once
+
+type purgeHandler struct {
+	manager *Manager
+}
+
+// recycleClusterFlow stop the cluster and keep it in the recycle bin,
+// maintenance status Recycled is kept until the cluster is recovered or purged
+var recycleClusterFlow = workflow.WorkFlowDefine{
+	FlowName: constants.FlowRecycleCluster,
+	TaskNodes: map[string]*workflow.NodeDefine{
+		"start":       {"backupBeforeDelete", "backupDone", "revert", workflow.SyncFuncNode, backupBeforeDelete, nil},
+		"backupDone":  {"clusterStop", "stopDone", "fail", workflow.PollingNode, stopCluster, nil},
+		"stopDone":    {"setClusterOffline", "offlineDone", "fail", workflow.SyncFuncNode, setClusterOffline, nil},
+		"offlineDone": {"end", "", "fail", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, recycleClusterDone), nil},
+		"fail":        {"fail", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(setClusterFailure, recycleClusterFailed, endMaintenance), nil},
+		"revert":      {"revert", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(recycleClusterFailed, endMaintenance), nil},
+	},
+}
+
+// StartClusterRecycleScheduler
+// @Description: start the scheduler which purges clusters expired in the recycle bin
+// @Receiver p
+func (p *Manager) StartClusterRecycleScheduler() {
+	recycleSchedulerOnce.Do(func() {
+		jobCron := cron.New()
+		if err := jobCron.AddJob(purgeJobSpec, &purgeHandler{manager: p}); err != nil {
+			framework.Log().Fatalf("add purge recycled clusters cron job failed, %s", err.Error())
+			return
+		}
+		go func() {
+			time.Sleep(5 * time.Second) //wait db client ready
+			jobCron.Start()
+		}()
+	})
+}
+
+func (h *purgeHandler) Run() {
+	recycled, _, err := models.GetClusterReaderWriter().QueryRecycledClusters(context.TODO(), "", constants.RecycledClusterRecycled, 0, -1)
+	if err != nil {
+		framework.Log().Errorf("query recycled clusters failed, %s", err.Error())
+		return
+	}
+	now := time.Now()
+	for _, record := range recycled {
+		if record.ExpireTime.After(now) {
+			continue
+		}
+		ctx := framework.NewMicroContextWithKeyValuePairs(context.Background(), map[string]string{
+			framework.TiUniManager_X_TENANT_ID_KEY: record.TenantId,
+			framework.TiUniManager_X_USER_ID_KEY:   record.OperatorID,
+		})
+		clusterMeta, err := meta.Get(ctx, record.ClusterID)
+		if err != nil {
+			framework.LogWithContext(ctx).Errorf("load cluster %s meta failed, %s", record.ClusterID, err.Error())
+			continue
+		}
+		if _, err = h.manager.purgeRecycledCluster(ctx, clusterMeta); err != nil {
+			framework.LogWithContext(ctx).Errorf("purge expired cluster %s failed, %s", record.ClusterID, err.Error())
+		}
+	}
+}
+
+// SetDeletionProtection
+// @Description: enable or disable deletion protection, a protected cluster can not be deleted even if forced
+// @Receiver p
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (p *Manager) SetDeletionProtection(ctx context.Context, req cluster.SetDeletionProtectionReq) (resp cluster.SetDeletionProtectionResp, err error) {
+	clusterMeta, err := meta.Get(ctx, req.ClusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("load cluster %s meta from db error: %s", req.ClusterID, err.Error())
+		return
+	}
+	if err = checkClusterPermission(ctx, clusterMeta, constants.RbacActionUpdate); err != nil {
+		return
+	}
+
+	if err = models.GetClusterReaderWriter().SetDeletionProtection(ctx, req.ClusterID, req.Enabled); err != nil {
+		framework.LogWithContext(ctx).Errorf("set deletion protection of cluster %s failed, %s", req.ClusterID, err.Error())
+		return
+	}
+	framework.LogForkFile(constants.LogFileAudit).WithFields(log.Fields{
+		"operatorID":         framework.GetUserIDFromContext(ctx),
+		"operatorFinishTime": time.Now(),
+		"event":              "SetDeletionProtection",
+		"operation":          req.ClusterID,
+		"original":           clusterMeta.Cluster.DeletionProtection,
+		"updated":            req.Enabled,
+	}).Info()
+
+	resp.ClusterID = req.ClusterID
+	resp.DeletionProtection = req.Enabled
+	return
+}
+
+// QueryRecycledClusters
+// @Description: query soft deleted clusters of current tenant
+// @Receiver p
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return total
+// @return err
+func (p *Manager) QueryRecycledClusters(ctx context.Context, req cluster.QueryRecycledClustersReq) (resp cluster.QueryRecycledClustersResp, total int, err error) {
+	recycled, count, err := models.GetClusterReaderWriter().QueryRecycledClusters(ctx, framework.GetTenantIDFromContext(ctx),
+		constants.RecycledClusterStatus(req.Status), req.GetOffset(), req.PageSize)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query recycled clusters failed, %s", err.Error())
+		return
+	}
+	resp.Clusters = make([]structs.RecycledClusterInfo, 0, len(recycled))
+	for _, record := range recycled {
+		resp.Clusters = append(resp.Clusters, structs.RecycledClusterInfo{
+			ID:          record.ID,
+			ClusterID:   record.ClusterID,
+			ClusterName: record.ClusterName,
+			OperatorID:  record.OperatorID,
+			Status:      record.Status,
+			BackupID:    record.BackupID,
+			WorkFlowID:  record.WorkFlowID,
+			Message:     record.Message,
+			CreateTime:  record.CreatedAt,
+			ExpireTime:  record.ExpireTime,
+		})
+	}
+	total = int(count)
+	return
+}
+
+// RecoverCluster
+// @Description: take the cluster out of the recycle bin and start it
+// @Receiver p
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (p *Manager) RecoverCluster(ctx context.Context, req cluster.RecoverClusterReq) (resp cluster.RecoverClusterResp, err error) {
+	clusterMeta, err := meta.Get(ctx, req.ClusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("load cluster %s meta from db error: %s", req.ClusterID, err.Error())
+		return
+	}
+	if err = checkClusterPermission(ctx, clusterMeta, constants.RbacActionUpdate); err != nil {
+		return
+	}
+
+	rw := models.GetClusterReaderWriter()
+	recycled, err := getRecycledCluster(ctx, clusterMeta)
+	if err != nil {
+		return
+	}
+
+	if err = clusterMeta.EndMaintenance(ctx, constants.ClusterMaintenanceRecycled); err != nil {
+		framework.LogWithContext(ctx).Errorf("take cluster %s out of the recycle bin failed, %s", req.ClusterID, err.Error())
+		return
+	}
+	restartResp, err := p.RestartCluster(ctx, cluster.RestartClusterReq{
+		ClusterID:            req.ClusterID,
+		MaintainWindowOption: structs.MaintainWindowOption{IgnoreMaintainWindow: true},
+	})
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("start recovered cluster %s failed, %s", req.ClusterID, err.Error())
+		// put the cluster back, so that it is still purged when expired
+		if recycleErr := clusterMeta.StartMaintenance(ctx, constants.ClusterMaintenanceRecycled); recycleErr != nil {
+			framework.LogWithContext(ctx).Errorf("put cluster %s back to the recycle bin failed, %s", req.ClusterID, recycleErr.Error())
+		}
+		return
+	}
+	if err = rw.UpdateRecycledCluster(ctx, recycled.ID, constants.RecycledClusterRecovered, "", restartResp.WorkFlowID,
+		fmt.Sprintf("recovered by %s", framework.GetUserIDFromContext(ctx))); err != nil {
+		framework.LogWithContext(ctx).Errorf("update recycled cluster %s failed, %s", recycled.ID, err.Error())
+		return
+	}
+
+	resp.ClusterID = req.ClusterID
+	resp.WorkFlowID = restartResp.WorkFlowID
+	return
+}
+
+// recycleCluster
+// @Description: stop the cluster and keep it with its resources in the recycle bin until the retention expires
+// @Receiver p
+// @Parameter ctx
+// @Parameter clusterMeta
+// @Parameter req
+// @return resp
+// @return err
+func (p *Manager) recycleCluster(ctx context.Context, clusterMeta *meta.ClusterMeta, req cluster.DeleteClusterReq) (resp cluster.DeleteClusterResp, err error) {
+	resp.ClusterID = clusterMeta.Cluster.ID
+	retention := req.RetentionHours
+	if retention <= 0 {
+		retention = getClusterRetentionHours(ctx)
+	}
+	request, err := json.Marshal(req)
+	if err != nil {
+		err = errors.WrapError(errors.TIUNIMANAGER_MARSHAL_ERROR, "marshal delete cluster request failed", err)
+		return
+	}
+
+	rw := models.GetClusterReaderWriter()
+	recycled, err := rw.CreateRecycledCluster(ctx, &management.RecycledCluster{
+		Entity:      dbCommon.Entity{TenantId: clusterMeta.Cluster.TenantId},
+		ClusterID:   clusterMeta.Cluster.ID,
+		ClusterName: clusterMeta.Cluster.Name,
+		OperatorID:  framework.GetUserIDFromContext(ctx),
+		Request:     string(request),
+		ExpireTime:  time.Now().Add(time.Duration(retention) * time.Hour),
+	})
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("put cluster %s into the recycle bin failed, %s", clusterMeta.Cluster.ID, err.Error())
+		return
+	}
+
+	data := map[string]interface{}{
+		ContextClusterMeta:       clusterMeta,
+		ContextDeleteRequest:     req,
+		ContextRecycledClusterID: recycled.ID,
+	}
+	flowID, err := asyncMaintenance(ctx, clusterMeta, constants.ClusterMaintenanceRecycled, recycleClusterFlow.FlowName, data)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("cluster %s async maintenance error: %s", clusterMeta.Cluster.ID, err.Error())
+		if updateErr := rw.UpdateRecycledCluster(ctx, recycled.ID, constants.RecycledClusterFailed, "", "", err.Error()); updateErr != nil {
+			framework.LogWithContext(ctx).Errorf("update recycled cluster %s failed, %s", recycled.ID, updateErr.Error())
+		}
+		return
+	}
+	if err = rw.UpdateRecycledCluster(ctx, recycled.ID, constants.RecycledClusterRecycling, "", flowID, ""); err != nil {
+		framework.LogWithContext(ctx).Errorf("update recycled cluster %s failed, %s", recycled.ID, err.Error())
+		return
+	}
+	framework.LogWithContext(ctx).Infof("cluster %s is put into the recycle bin, it will be purged at %s",
+		clusterMeta.Cluster.ID, recycled.ExpireTime.Format(time.RFC3339))
+
+	resp.WorkFlowID = flowID
+	return
+}
+
+// purgeRecycledCluster
+// @Description: destroy the cluster in the recycle bin and free its resources, backup before recycling is kept
+// @Receiver p
+// @Parameter ctx
+// @Parameter clusterMeta
+// @return resp
+// @return err
+func (p *Manager) purgeRecycledCluster(ctx context.Context, clusterMeta *meta.ClusterMeta) (resp cluster.DeleteClusterResp, err error) {
+	resp.ClusterID = clusterMeta.Cluster.ID
+	if clusterMeta.Cluster.DeletionProtection {
+		err = errors.NewErrorf(errors.TIUNIMANAGER_CLUSTER_DELETION_PROTECTED,
+			"cluster %s is protected from deletion, disable the protection first", clusterMeta.Cluster.ID)
+		return
+	}
+	rw := models.GetClusterReaderWriter()
+	recycled, err := getRecycledCluster(ctx, clusterMeta)
+	if err != nil {
+		return
+	}
+
+	req := cluster.DeleteClusterReq{}
+	if err = json.Unmarshal([]byte(recycled.Request), &req); err != nil {
+		err = errors.WrapError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, "unmarshal delete cluster request failed", err)
+		return
+	}
+	// the cluster has been backed up before recycling
+	req.AutoBackup = false
+	req.SoftDelete = false
+
+	// every replica runs the purge scheduler, the cluster is purged by the one claims it
+	claimed, err := rw.ClaimRecycledCluster(ctx, recycled.ID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("claim recycled cluster %s failed, %s", recycled.ID, err.Error())
+		return
+	}
+	if !claimed {
+		err = errors.NewErrorf(errors.TIUNIMANAGER_CLUSTER_MAINTENANCE_CONFLICT,
+			"cluster %s is being purged by another request", clusterMeta.Cluster.ID)
+		return
+	}
+
+	data := map[string]interface{}{
+		ContextClusterMeta:   clusterMeta,
+		ContextDeleteRequest: req,
+		ContextBackupID:      recycled.BackupID,
+	}
+	flowID, err := asyncMaintenance(ctx, clusterMeta, constants.ClusterMaintenanceDeleting, deleteClusterFlow.FlowName, data)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("cluster %s async maintenance error: %s", clusterMeta.Cluster.ID, err.Error())
+		// keep it in the recycle bin, so that it is purged next time
+		if updateErr := rw.UpdateRecycledCluster(ctx, recycled.ID, constants.RecycledClusterRecycled, "", "", err.Error()); updateErr != nil {
+			framework.LogWithContext(ctx).Errorf("update recycled cluster %s failed, %s", recycled.ID, updateErr.Error())
+		}
+		return
+	}
+	if err = rw.UpdateRecycledCluster(ctx, recycled.ID, constants.RecycledClusterPurged, "", flowID, ""); err != nil {
+		framework.LogWithContext(ctx).Errorf("update recycled cluster %s failed, %s", recycled.ID, err.Error())
+		return
+	}
+	framework.LogWithContext(ctx).Infof("cluster %s is purged from the recycle bin by workflow %s", clusterMeta.Cluster.ID, flowID)
+
+	resp.WorkFlowID = flowID
+	return
+}
+
+// getRecycledCluster get record of the cluster in the recycle bin, the cluster must have been recycled
+func getRecycledCluster(ctx context.Context, clusterMeta *meta.ClusterMeta) (*management.RecycledCluster, error) {
+	if clusterMeta.Cluster.MaintenanceStatus != constants.ClusterMaintenanceRecycled {
+		return nil, errors.NewErrorf(errors.TIUNIMANAGER_RECYCLED_CLUSTER_NOT_FOUND,
+			"cluster %s is not in the recycle bin", clusterMeta.Cluster.ID)
+	}
+	recycled, err := models.GetClusterReaderWriter().GetRecycledCluster(ctx, clusterMeta.Cluster.ID)
+	if err != nil {
+		return nil, err
+	}
+	if recycled.Status != string(constants.RecycledClusterRecycled) {
+		return nil, errors.NewErrorf(errors.TIUNIMANAGER_CLUSTER_MAINTENANCE_CONFLICT,
+			"cluster %s is %s in the recycle bin", clusterMeta.Cluster.ID, recycled.Status)
+	}
+	return recycled, nil
+}
+
+// getClusterRetentionHours hours to keep a soft deleted cluster, it is configured by system config
+func getClusterRetentionHours(ctx context.Context) int {
+	config, err := models.GetConfigReaderWriter().GetConfig(ctx, constants.ConfigKeyClusterRetentionHours)
+	if err != nil || config.ConfigValue == "" {
+		framework.LogWithContext(ctx).Warnf("get config %s failed, use default %d hours", constants.ConfigKeyClusterRetentionHours, constants.DefaultClusterRetentionHours)
+		return constants.DefaultClusterRetentionHours
+	}
+	hours, err := strconv.Atoi(config.ConfigValue)
+	if err != nil || hours <= 0 {
+		framework.LogWithContext(ctx).Warnf("invalid config %s = %s, use default %d hours", constants.ConfigKeyClusterRetentionHours, config.ConfigValue, constants.DefaultClusterRetentionHours)
+		return constants.DefaultClusterRetentionHours
+	}
+	return hours
+}
+
+// recycleClusterDone
+// @Description: the cluster is stopped and kept in the recycle bin, maintenance status Recycled is not cleared
+func recycleClusterDone(node *workflowModel.WorkFlowNode, context *workflow.FlowContext) error {
+	var recycledID, backupID string
+	if err := context.GetData(ContextRecycledClusterID, &recycledID); err != nil {
+		return err
+	}
+	if err := context.GetData(ContextBackupID, &backupID); err != nil {
+		return err
+	}
+	err := models.GetClusterReaderWriter().UpdateRecycledCluster(context, recycledID, constants.RecycledClusterRecycled, backupID, "", "")
+	if err != nil {
+		framework.LogWithContext(context).Errorf("update recycled cluster %s failed, %s", recycledID, err.Error())
+		return err
+	}
+	node.Record(fmt.Sprintf("keep cluster in the recycle bin, backup %s ", backupID))
+	return nil
+}
+
+// recycleClusterFailed
+// @Description: mark the record of recycle bin failed, the cluster is not recycled
+func recycleClusterFailed(node *workflowModel.WorkFlowNode, context *workflow.FlowContext) error {
+	var recycledID string
+	if err := context.GetData(ContextRecycledClusterID, &recycledID); err != nil {
+		return err
+	}
+	err := models.GetClusterReaderWriter().UpdateRecycledCluster(context, recycledID, constants.RecycledClusterFailed, "", "", "recycle cluster workflow failed")
+	if err != nil {
+		framework.LogWithContext(context).Errorf("update recycled cluster %s failed, %s", recycledID, err.Error())
+		return err
+	}
+	node.Record(fmt.Sprintf("recycle cluster failed, record %s ", recycledID))
+	return nil
+}
@@ -0,0 +1,190 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package management
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	"github.com/pingcap/tiunimanager/models/common"
+	"github.com/pingcap/tiunimanager/models/platform/config"
+	"github.com/pingcap/tiunimanager/test/mockmodels/mockclustermanagement"
+	"github.com/pingcap/tiunimanager/test/mockmodels/mockconfig"
+	mock_workflow_service "github.com/pingcap/tiunimanager/test/mockworkflow"
+	workflow "github.com/pingcap/tiunimanager/workflow2"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockRecycleCluster(status constants.ClusterMaintenanceStatus, protected bool) *management.Cluster {
+	return &management.Cluster{
+		Entity:             common.Entity{ID: "111", TenantId: "tenant"},
+		MaintenanceStatus:  status,
+		DeletionProtection: protected,
+	}
+}
+
+func Test_getClusterRetentionHours(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	configRW := mockconfig.NewMockReaderWriter(ctrl)
+	models.SetConfigReaderWriter(configRW)
+
+	configRW.EXPECT().GetConfig(gomock.Any(), constants.ConfigKeyClusterRetentionHours).Return(&config.SystemConfig{ConfigValue: "24"}, nil)
+	assert.Equal(t, 24, getClusterRetentionHours(context.TODO()))
+
+	configRW.EXPECT().GetConfig(gomock.Any(), constants.ConfigKeyClusterRetentionHours).Return(&config.SystemConfig{ConfigValue: "-1"}, nil)
+	assert.Equal(t, constants.DefaultClusterRetentionHours, getClusterRetentionHours(context.TODO()))
+
+	configRW.EXPECT().GetConfig(gomock.Any(), constants.ConfigKeyClusterRetentionHours).Return(nil, errors.Error(errors.TIUNIMANAGER_SYSTEM_MISSING_CONFIG))
+	assert.Equal(t, constants.DefaultClusterRetentionHours, getClusterRetentionHours(context.TODO()))
+}
+
+func TestManager_DeleteCluster_Recycle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	workflow.GetWorkFlowService().RegisterWorkFlow(context.TODO(), constants.FlowDeleteCluster, getEmptyFlow(constants.FlowDeleteCluster))
+	workflow.GetWorkFlowService().RegisterWorkFlow(context.TODO(), constants.FlowRecycleCluster, getEmptyFlow(constants.FlowRecycleCluster))
+	manager := Manager{}
+
+	t.Run("protected", func(t *testing.T) {
+		clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+		models.SetClusterReaderWriter(clusterRW)
+		clusterRW.EXPECT().GetMeta(gomock.Any(), "111").Return(mockRecycleCluster(constants.ClusterMaintenanceNone, true),
+			[]*management.ClusterInstance{}, make([]*management.DBUser, 0), nil)
+
+		_, err := manager.DeleteCluster(context.TODO(), cluster.DeleteClusterReq{ClusterID: "111", Force: true})
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_CLUSTER_DELETION_PROTECTED, err.(errors.EMError).GetCode())
+	})
+	t.Run("soft delete", func(t *testing.T) {
+		clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+		models.SetClusterReaderWriter(clusterRW)
+		clusterRW.EXPECT().GetMeta(gomock.Any(), "111").Return(mockRecycleCluster(constants.ClusterMaintenanceNone, false),
+			[]*management.ClusterInstance{}, make([]*management.DBUser, 0), nil)
+		clusterRW.EXPECT().CreateRecycledCluster(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, recycled *management.RecycledCluster) (*management.RecycledCluster, error) {
+				assert.Equal(t, "111", recycled.ClusterID)
+				recycled.ID = "recycled01"
+				return recycled, nil
+			})
+		clusterRW.EXPECT().SetMaintenanceStatus(gomock.Any(), "111", constants.ClusterMaintenanceRecycled).Return(nil)
+		clusterRW.EXPECT().UpdateRecycledCluster(gomock.Any(), "recycled01", constants.RecycledClusterRecycling, "", "flow01", "").Return(nil)
+
+		workflowService := mock_workflow_service.NewMockWorkFlowService(ctrl)
+		workflow.MockWorkFlowService(workflowService)
+		defer workflow.MockWorkFlowService(workflow.NewWorkFlowManager())
+		workflowService.EXPECT().CreateWorkFlow(gomock.Any(), gomock.Any(), gomock.Any(), constants.FlowRecycleCluster).Return("flow01", nil)
+		workflowService.EXPECT().InitContext(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+		workflowService.EXPECT().Start(gomock.Any(), gomock.Any()).Return(nil)
+
+		resp, err := manager.DeleteCluster(context.TODO(), cluster.DeleteClusterReq{ClusterID: "111", SoftDelete: true, RetentionHours: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, "flow01", resp.WorkFlowID)
+	})
+	t.Run("purge", func(t *testing.T) {
+		clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+		models.SetClusterReaderWriter(clusterRW)
+		clusterRW.EXPECT().GetMeta(gomock.Any(), "111").Return(mockRecycleCluster(constants.ClusterMaintenanceRecycled, false),
+			[]*management.ClusterInstance{}, make([]*management.DBUser, 0), nil)
+		recycled := &management.RecycledCluster{ClusterID: "111", Request: `{"clusterID":"111","autoBackup":true}`, BackupID: "backup01"}
+		recycled.ID = "recycled01"
+		recycled.Status = string(constants.RecycledClusterRecycled)
+		clusterRW.EXPECT().GetRecycledCluster(gomock.Any(), "111").Return(recycled, nil)
+		clusterRW.EXPECT().ClaimRecycledCluster(gomock.Any(), "recycled01").Return(true, nil)
+		clusterRW.EXPECT().SetMaintenanceStatus(gomock.Any(), "111", constants.ClusterMaintenanceDeleting).Return(nil)
+		clusterRW.EXPECT().UpdateRecycledCluster(gomock.Any(), "recycled01", constants.RecycledClusterPurged, "", "flow02", "").Return(nil)
+
+		workflowService := mock_workflow_service.NewMockWorkFlowService(ctrl)
+		workflow.MockWorkFlowService(workflowService)
+		defer workflow.MockWorkFlowService(workflow.NewWorkFlowManager())
+		workflowService.EXPECT().CreateWorkFlow(gomock.Any(), gomock.Any(), gomock.Any(), constants.FlowDeleteCluster).Return("flow02", nil)
+		workflowService.EXPECT().InitContext(gomock.Any(), "flow02", ContextBackupID, "backup01").Return(nil)
+		workflowService.EXPECT().InitContext(gomock.Any(), "flow02", ContextDeleteRequest, cluster.DeleteClusterReq{ClusterID: "111"}).Return(nil)
+		workflowService.EXPECT().InitContext(gomock.Any(), "flow02", ContextClusterMeta, gomock.Any()).Return(nil)
+		workflowService.EXPECT().Start(gomock.Any(), gomock.Any()).Return(nil)
+
+		resp, err := manager.DeleteCluster(context.TODO(), cluster.DeleteClusterReq{ClusterID: "111"})
+		assert.NoError(t, err)
+		assert.Equal(t, "flow02", resp.WorkFlowID)
+	})
+	t.Run("purged by another replica", func(t *testing.T) {
+		clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+		models.SetClusterReaderWriter(clusterRW)
+		clusterRW.EXPECT().GetMeta(gomock.Any(), "111").Return(mockRecycleCluster(constants.ClusterMaintenanceRecycled, false),
+			[]*management.ClusterInstance{}, make([]*management.DBUser, 0), nil)
+		recycled := &management.RecycledCluster{ClusterID: "111", Request: `{"clusterID":"111"}`}
+		recycled.ID = "recycled01"
+		recycled.Status = string(constants.RecycledClusterRecycled)
+		clusterRW.EXPECT().GetRecycledCluster(gomock.Any(), "111").Return(recycled, nil)
+		clusterRW.EXPECT().ClaimRecycledCluster(gomock.Any(), "recycled01").Return(false, nil)
+
+		_, err := manager.DeleteCluster(context.TODO(), cluster.DeleteClusterReq{ClusterID: "111"})
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_CLUSTER_MAINTENANCE_CONFLICT, err.(errors.EMError).GetCode())
+	})
+	t.Run("still recycling", func(t *testing.T) {
+		clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+		models.SetClusterReaderWriter(clusterRW)
+		clusterRW.EXPECT().GetMeta(gomock.Any(), "111").Return(mockRecycleCluster(constants.ClusterMaintenanceRecycled, false),
+			[]*management.ClusterInstance{}, make([]*management.DBUser, 0), nil)
+		recycled := &management.RecycledCluster{ClusterID: "111"}
+		recycled.Status = string(constants.RecycledClusterRecycling)
+		clusterRW.EXPECT().GetRecycledCluster(gomock.Any(), "111").Return(recycled, nil)
+
+		_, err := manager.DeleteCluster(context.TODO(), cluster.DeleteClusterReq{ClusterID: "111"})
+		assert.Error(t, err)
+	})
+}
+
+func TestManager_RecoverCluster(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	manager := Manager{}
+	t.Run("not recycled", func(t *testing.T) {
+		clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+		models.SetClusterReaderWriter(clusterRW)
+		clusterRW.EXPECT().GetMeta(gomock.Any(), "111").Return(mockRecycleCluster(constants.ClusterMaintenanceNone, false),
+			[]*management.ClusterInstance{}, make([]*management.DBUser, 0), nil)
+
+		_, err := manager.RecoverCluster(context.TODO(), cluster.RecoverClusterReq{ClusterID: "111"})
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_RECYCLED_CLUSTER_NOT_FOUND, err.(errors.EMError).GetCode())
+	})
+	t.Run("start failed", func(t *testing.T) {
+		clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+		models.SetClusterReaderWriter(clusterRW)
+		clusterRW.EXPECT().GetMeta(gomock.Any(), "111").Return(mockRecycleCluster(constants.ClusterMaintenanceRecycled, false),
+			[]*management.ClusterInstance{}, make([]*management.DBUser, 0), nil)
+		recycled := &management.RecycledCluster{ClusterID: "111"}
+		recycled.Status = string(constants.RecycledClusterRecycled)
+		clusterRW.EXPECT().GetRecycledCluster(gomock.Any(), "111").Return(recycled, nil)
+		clusterRW.EXPECT().ClearMaintenanceStatus(gomock.Any(), "111", constants.ClusterMaintenanceRecycled).Return(nil)
+		clusterRW.EXPECT().GetMeta(gomock.Any(), "111").Return(nil, nil, nil, errors.Error(errors.TIUNIMANAGER_CLUSTER_NOT_FOUND))
+		clusterRW.EXPECT().SetMaintenanceStatus(gomock.Any(), "111", constants.ClusterMaintenanceRecycled).Return(nil)
+
+		_, err := manager.RecoverCluster(context.TODO(), cluster.RecoverClusterReq{ClusterID: "111"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,525 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package management
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/deployment"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/models"
+	workflowModel "github.com/pingcap/tiunimanager/models/workflow"
+	util "github.com/pingcap/tiunimanager/util/http"
+	workflow "github.com/pingcap/tiunimanager/workflow2"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	"gopkg.in/yaml.v2"
+)
+
+// rollbackUpgradeClusterFlow reinstall packages of the version before upgrade on instances which have been upgraded,
+// components are rolled back in the reverse order of upgrade
+var rollbackUpgradeClusterFlow = workflow.WorkFlowDefine{
+	FlowName: constants.FlowRollbackUpgradeCluster,
+	TaskNodes: map[string]*workflow.NodeDefine{
+		"start":               {"rollbackCDC", "rollbackCDCDone", "fail", workflow.PollingNode, rollbackComponent(constants.ComponentIDCDC), nil},
+		"rollbackCDCDone":     {"rollbackTiDB", "rollbackTiDBDone", "fail", workflow.PollingNode, rollbackComponent(constants.ComponentIDTiDB), nil},
+		"rollbackTiDBDone":    {"rollbackTiFlash", "rollbackTiFlashDone", "fail", workflow.PollingNode, rollbackComponent(constants.ComponentIDTiFlash), nil},
+		"rollbackTiFlashDone": {"rollbackTiKV", "rollbackTiKVDone", "fail", workflow.PollingNode, rollbackComponent(constants.ComponentIDTiKV), nil},
+		"rollbackTiKVDone":    {"rollbackPD", "rollbackPDDone", "fail", workflow.PollingNode, rollbackComponent(constants.ComponentIDPD), nil},
+		"rollbackPDDone":      {"revertTiUPVersion", "revertTiUPDone", "fail", workflow.SyncFuncNode, revertTiUPVersion, nil},
+		"revertTiUPDone":      {"checkRollback", "checkRollbackDone", "fail", workflow.SyncFuncNode, checkRollbackVersions, nil},
+		"checkRollbackDone":   {"setClusterOnline", "onlineDone", "fail", workflow.SyncFuncNode, workflow.CompositeExecutor(revertUpgradeVersions, setClusterOnline), nil},
+		"onlineDone":          {"syncTopology", "success", "fail", workflow.SyncFuncNode, syncTopology, nil},
+		"success":             {"end", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(persistCluster, endMaintenance), nil},
+		"fail":                {"fail", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(setClusterFailure, endMaintenance), nil},
+	},
+}
+
+// upgradeSnapshot topology and versions of cluster before upgrade, it is saved in ClusterTopologySnapshot
+type upgradeSnapshot struct {
+	WorkFlowID    string            `json:"workFlowId"`
+	Version       string            `json:"version"`
+	TargetVersion string            `json:"targetVersion"`
+	Topology      string            `json:"topology"` // meta.yaml of tiup
+	Versions      map[string]string `json:"versions"` // key is instance id
+	CreateTime    time.Time         `json:"createTime"`
+}
+
+var versionPattern = regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
+
+// normalizeVersion
+// @Description: extract version like v5.4.0 from version string of components, eg. 5.7.25-TiDB-v5.4.0
+func normalizeVersion(version string) string {
+	matches := versionPattern.FindAllStringSubmatch(version, -1)
+	if len(matches) == 0 {
+		return ""
+	}
+	// the version of TiDB follows the MySQL compatible version
+	return "v" + matches[len(matches)-1][1]
+}
+
+// snapshotUpgradeTopology
+// @Description: save topology and versions of instances before upgrade, they are used to roll back the upgrade
+func snapshotUpgradeTopology(node *workflowModel.WorkFlowNode, context *workflow.FlowContext) error {
+	var clusterMeta meta.ClusterMeta
+	err := context.GetData(ContextClusterMeta, &clusterMeta)
+	if err != nil {
+		return err
+	}
+	var version string
+	err = context.GetData(ContextUpgradeVersion, &version)
+	if err != nil {
+		return err
+	}
+
+	topology, err := readTiUPFile(context, getClusterSpaceInTiUP(context, clusterMeta.Cluster.ID), "meta.yaml")
+	if err != nil {
+		framework.LogWithContext(context).Errorf("read meta.yaml of cluster %s failed, %s", clusterMeta.Cluster.ID, err.Error())
+		return err
+	}
+	snapshot := upgradeSnapshot{
+		WorkFlowID:    node.ParentID,
+		Version:       clusterMeta.Cluster.Version,
+		TargetVersion: version,
+		Topology:      topology,
+		Versions:      make(map[string]string),
+		CreateTime:    time.Now(),
+	}
+	for _, instances := range clusterMeta.Instances {
+		for _, instance := range instances {
+			snapshot.Versions[instance.ID] = instance.Version
+			if instance.Version == "" {
+				snapshot.Versions[instance.ID] = clusterMeta.Cluster.Version
+			}
+		}
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return errors.WrapError(errors.TIUNIMANAGER_MARSHAL_ERROR, "marshal upgrade snapshot failed", err)
+	}
+	if err = models.GetClusterReaderWriter().UpdateTopologySnapshotUpgrade(context, clusterMeta.Cluster.ID, string(data)); err != nil {
+		framework.LogWithContext(context).Errorf("save upgrade snapshot of cluster %s failed, %s", clusterMeta.Cluster.ID, err.Error())
+		return err
+	}
+	node.Record(fmt.Sprintf("snapshot topology and versions of cluster %s before upgrading from %s to %s",
+		clusterMeta.Cluster.ID, clusterMeta.Cluster.Version, version))
+	return nil
+}
+
+// RollbackUpgrade
+// @Description: roll back instances which have been upgraded by the latest upgrade to their versions before upgrade
+// @Receiver p
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (p *Manager) RollbackUpgrade(ctx context.Context, req cluster.RollbackUpgradeReq) (resp cluster.RollbackUpgradeResp, err error) {
+	clusterMeta, err := meta.Get(ctx, req.ClusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf(
+			"load cluster %s meta from db error: %s", req.ClusterID, err.Error())
+		return
+	}
+	if err = checkClusterPermission(ctx, clusterMeta, constants.RbacActionUpdate); err != nil {
+		return
+	}
+
+	topologySnapshot, err := models.GetClusterReaderWriter().GetCurrentClusterTopologySnapshot(ctx, req.ClusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("get topology snapshot of cluster %s failed, %s", req.ClusterID, err.Error())
+		return
+	}
+	if topologySnapshot.Upgrade == "" {
+		err = errors.NewErrorf(errors.TIUNIMANAGER_UPGRADE_ROLLBACK_REFUSED, "cluster %s has not been upgraded", req.ClusterID)
+		return
+	}
+	snapshot := upgradeSnapshot{}
+	if err = json.Unmarshal([]byte(topologySnapshot.Upgrade), &snapshot); err != nil {
+		err = errors.WrapError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, "parse upgrade snapshot failed", err)
+		return
+	}
+
+	items, err := planUpgradeRollback(clusterMeta, snapshot, queryRunningVersions(ctx, clusterMeta))
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("roll back upgrade of cluster %s is refused, %s", req.ClusterID, err.Error())
+		return
+	}
+	if err = checkRollbackPackages(framework.GetTiupHomePathForTidb(), clusterMeta.Cluster.CpuArchitecture, items); err != nil {
+		framework.LogWithContext(ctx).Errorf("roll back upgrade of cluster %s is refused, %s", req.ClusterID, err.Error())
+		return
+	}
+
+	data := map[string]interface{}{
+		ContextClusterMeta:     clusterMeta,
+		ContextOriginalVersion: snapshot.Version,
+		ContextRollbackItems:   items,
+	}
+	flowID, err := asyncMaintenance(ctx, clusterMeta, constants.ClusterMaintenanceRollingBack, rollbackUpgradeClusterFlow.FlowName, data)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf(
+			"cluster %s async maintenance error: %s", clusterMeta.Cluster.ID, err.Error())
+		return
+	}
+
+	resp.ClusterID = clusterMeta.Cluster.ID
+	resp.WorkFlowID = flowID
+	resp.Version = snapshot.Version
+	resp.Instances = items
+	return
+}
+
+// planUpgradeRollback
+// @Description: find instances which are not running the version before upgrade, the rollback is refused if any of them can not be rolled back
+// @Parameter clusterMeta
+// @Parameter snapshot
+// @Parameter running running versions of instances, instance which is not found is regarded as upgraded
+// @return []structs.UpgradeRollbackItem
+// @return error
+func planUpgradeRollback(clusterMeta *meta.ClusterMeta, snapshot upgradeSnapshot, running map[string]string) ([]structs.UpgradeRollbackItem, error) {
+	items := make([]structs.UpgradeRollbackItem, 0)
+	refused := make([]string, 0)
+	for _, instanceType := range sortedInstanceTypes(clusterMeta) {
+		if _, ok := upgradeComponents[instanceType]; !ok {
+			continue
+		}
+		for _, instance := range clusterMeta.Instances[instanceType] {
+			toVersion, ok := snapshot.Versions[instance.ID]
+			if !ok {
+				// scaled out after upgrade
+				toVersion = snapshot.Version
+			}
+			fromVersion, ok := running[instance.ID]
+			if !ok {
+				fromVersion = snapshot.TargetVersion
+			}
+			if fromVersion == toVersion {
+				continue
+			}
+			if err := checkRollbackCompatible(instanceType, fromVersion, toVersion); err != nil {
+				refused = append(refused, fmt.Sprintf("%s %s: %s", instanceType, instance.ID, err.Error()))
+				continue
+			}
+			items = append(items, structs.UpgradeRollbackItem{
+				InstanceID:   instance.ID,
+				InstanceType: instanceType,
+				Address:      strings.Join([]string{instance.HostIP[0], strconv.Itoa(int(instance.Ports[0]))}, ":"),
+				FromVersion:  fromVersion,
+				ToVersion:    toVersion,
+			})
+		}
+	}
+	if len(refused) > 0 {
+		return nil, errors.NewErrorf(errors.TIUNIMANAGER_UPGRADE_ROLLBACK_REFUSED, "upgrade of cluster %s from %s to %s can not be rolled back, %s",
+			clusterMeta.Cluster.ID, snapshot.Version, snapshot.TargetVersion, strings.Join(refused, "; "))
+	}
+	if len(items) == 0 {
+		return nil, errors.NewErrorf(errors.TIUNIMANAGER_UPGRADE_ROLLBACK_REFUSED, "all instances of cluster %s are running the versions before upgrade to %s",
+			clusterMeta.Cluster.ID, snapshot.TargetVersion)
+	}
+	return items, nil
+}
+
+// checkRollbackCompatible
+// @Description: all components can be rolled back to a version of the same minor version.
+// TiDB is stateless and can be rolled back across minor versions,
+// the others may have persisted data in the format of the new version, so they are not allowed
+func checkRollbackCompatible(instanceType string, fromVersion string, toVersion string) error {
+	if getMinorVersion(fromVersion) == getMinorVersion(toVersion) {
+		return nil
+	}
+	if instanceType == string(constants.ComponentIDTiDB) {
+		return nil
+	}
+	return fmt.Errorf("rolling back from %s to %s is not supported, data may have been persisted in the format of %s",
+		fromVersion, toVersion, getMinorVersion(fromVersion))
+}
+
+func rollbackPackagePath(tiupHome string, component string, version string, arch constants.ArchType) string {
+	return filepath.Join(tiupHome, "storage", "cluster", "packages",
+		fmt.Sprintf("%s-%s-linux-%s.tar.gz", component, version, constants.GetArchAlias(arch)))
+}
+
+// checkRollbackPackages
+// @Description: packages of the versions before upgrade are required by tiup patch, they have been downloaded when deploying or upgrading
+func checkRollbackPackages(tiupHome string, arch constants.ArchType, items []structs.UpgradeRollbackItem) error {
+	for _, item := range items {
+		path := rollbackPackagePath(tiupHome, upgradeComponents[item.InstanceType], item.ToVersion, arch)
+		if _, err := os.Stat(path); err != nil {
+			return errors.NewErrorf(errors.TIUNIMANAGER_UPGRADE_ROLLBACK_REFUSED, "package %s of %s %s is not found, %s",
+				path, item.InstanceType, item.ToVersion, err.Error())
+		}
+	}
+	return nil
+}
+
+// queryRunningVersions
+// @Description: query versions of running instances, TiKV and TiFlash from stores in PD, PD from members,
+// TiDB and CDC from their status api. Instances of which the version is unknown are not included
+func queryRunningVersions(ctx context.Context, clusterMeta *meta.ClusterMeta) map[string]string {
+	versions := make(map[string]string)
+	if stores, err := queryStores(ctx, clusterMeta); err == nil {
+		storeVersions := make(map[string]string)
+		for _, store := range stores.Stores {
+			storeVersions[store.Store.Address] = normalizeVersion(store.Store.Version)
+		}
+		for _, instanceType := range []constants.EMProductComponentIDType{constants.ComponentIDTiKV, constants.ComponentIDTiFlash} {
+			for _, instance := range clusterMeta.Instances[string(instanceType)] {
+				if version := storeVersions[storeAddress(instance)]; version != "" {
+					versions[instance.ID] = version
+				}
+			}
+		}
+	} else {
+		framework.LogWithContext(ctx).Warnf("query stores of cluster %s failed, %s", clusterMeta.Cluster.ID, err.Error())
+	}
+
+	if output, err := pdCtl(ctx, clusterMeta, "member"); err == nil {
+		members := &pdMembers{}
+		if err = json.Unmarshal([]byte(output), members); err == nil {
+			memberVersions := make(map[string]string)
+			for _, member := range members.Members {
+				for _, url := range member.ClientURLs {
+					memberVersions[strings.TrimPrefix(strings.TrimPrefix(url, "http://"), "https://")] = normalizeVersion(member.BinaryVersion)
+				}
+			}
+			for _, instance := range clusterMeta.Instances[string(constants.ComponentIDPD)] {
+				address := strings.Join([]string{instance.HostIP[0], strconv.Itoa(int(instance.Ports[0]))}, ":")
+				if version := memberVersions[address]; version != "" {
+					versions[instance.ID] = version
+				}
+			}
+		}
+	} else {
+		framework.LogWithContext(ctx).Warnf("query PD members of cluster %s failed, %s", clusterMeta.Cluster.ID, err.Error())
+	}
+
+	for _, instance := range clusterMeta.Instances[string(constants.ComponentIDTiDB)] {
+		if version := queryStatusVersion(ctx, instance.HostIP[0], int(instance.Ports[1])); version != "" {
+			versions[instance.ID] = version
+		}
+	}
+	for _, instance := range clusterMeta.Instances[string(constants.ComponentIDCDC)] {
+		if version := queryStatusVersion(ctx, instance.HostIP[0], int(instance.Ports[0])); version != "" {
+			versions[instance.ID] = version
+		}
+	}
+	return versions
+}
+
+type pdMembers struct {
+	Members []struct {
+		Name          string   `json:"name"`
+		ClientURLs    []string `json:"client_urls"`
+		BinaryVersion string   `json:"binary_version"`
+	} `json:"members"`
+}
+
+// queryStatusVersion
+// @Description: query version by the status api of TiDB or CDC, return empty string if it is unavailable
+func queryStatusVersion(ctx context.Context, host string, port int) string {
+	resp, err := util.Get(fmt.Sprintf("http://%s:%d/status", host, port), nil, nil)
+	if err != nil {
+		framework.LogWithContext(ctx).Warnf("query status of %s:%d failed, %s", host, port, err.Error())
+		return ""
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return ""
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return ""
+	}
+	status := struct {
+		Version string `json:"version"`
+	}{}
+	if err = json.Unmarshal(body, &status); err != nil {
+		return ""
+	}
+	return normalizeVersion(status.Version)
+}
+
+// rollbackComponent
+// @Description: patch instances of the component with packages of the versions before upgrade
+func rollbackComponent(instanceType constants.EMProductComponentIDType) workflow.NodeExecutor {
+	return func(node *workflowModel.WorkFlowNode, context *workflow.FlowContext) error {
+		var clusterMeta meta.ClusterMeta
+		err := context.GetData(ContextClusterMeta, &clusterMeta)
+		if err != nil {
+			return err
+		}
+		var items []structs.UpgradeRollbackItem
+		err = context.GetData(ContextRollbackItems, &items)
+		if err != nil {
+			return err
+		}
+
+		nodes := make([]string, 0)
+		version := ""
+		for _, item := range items {
+			if item.InstanceType != string(instanceType) {
+				continue
+			}
+			if version != "" && version != item.ToVersion {
+				return errors.NewErrorf(errors.TIUNIMANAGER_UPGRADE_ROLLBACK_FAILED, "instances of %s are rolled back to different versions %s and %s",
+					instanceType, version, item.ToVersion)
+			}
+			version = item.ToVersion
+			nodes = append(nodes, item.Address)
+		}
+		if len(nodes) == 0 {
+			node.Success(fmt.Sprintf("no instance of %s needs to be rolled back", instanceType))
+			return nil
+		}
+
+		tiupHome := framework.GetTiupHomePathForTidb()
+		packagePath := rollbackPackagePath(tiupHome, upgradeComponents[string(instanceType)], version, clusterMeta.Cluster.CpuArchitecture)
+		// overwrite the package in topology, then instances scaled out later are deployed by the package too
+		args := []string{"-N", strings.Join(nodes, ","), "--overwrite"}
+		framework.LogWithContext(context).Infof("roll back %s of cluster %s to %s, args %v", instanceType, clusterMeta.Cluster.ID, version, args)
+		operationID, err := deployment.M.Patch(context.Context, deployment.TiUPComponentTypeCluster, clusterMeta.Cluster.ID,
+			packagePath, tiupHome, node.ParentID, args, meta.DefaultTiupTimeOut)
+		if err != nil {
+			framework.LogWithContext(context).Errorf("roll back %s of cluster %s error: %s", instanceType, clusterMeta.Cluster.ID, err.Error())
+			return err
+		}
+		node.Record(fmt.Sprintf("roll back %s %s of cluster %s to %s", instanceType, strings.Join(nodes, ","), clusterMeta.Cluster.ID, version))
+		node.OperationID = operationID
+		return nil
+	}
+}
+
+// revertTiUPVersion
+// @Description: tiup patch keeps the target version of upgrade in meta.yaml,
+// set it to the version before upgrade, which is used when tiup scales out, reloads or renders config of the cluster later
+func revertTiUPVersion(node *workflowModel.WorkFlowNode, context *workflow.FlowContext) error {
+	var clusterMeta meta.ClusterMeta
+	err := context.GetData(ContextClusterMeta, &clusterMeta)
+	if err != nil {
+		return err
+	}
+	var originalVersion string
+	err = context.GetData(ContextOriginalVersion, &originalVersion)
+	if err != nil {
+		return err
+	}
+
+	if err = setTiUPClusterVersion(getClusterSpaceInTiUP(context, clusterMeta.Cluster.ID), originalVersion); err != nil {
+		framework.LogWithContext(context).Errorf("revert version in meta.yaml of cluster %s failed, %s", clusterMeta.Cluster.ID, err.Error())
+		return err
+	}
+	node.Record(fmt.Sprintf("revert version in meta.yaml of cluster %s to %s", clusterMeta.Cluster.ID, originalVersion))
+	return nil
+}
+
+// setTiUPClusterVersion
+// @Description: rewrite meta.yaml in the tiup space of cluster with the version, the topology is kept
+func setTiUPClusterVersion(clusterHome string, version string) error {
+	fileName := filepath.Join(clusterHome, "meta.yaml")
+	info, err := os.Stat(fileName)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+	metadata := &spec.ClusterMeta{}
+	if err = yaml.Unmarshal(data, metadata); err != nil {
+		return errors.WrapError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, "parse meta.yaml failed", err)
+	}
+	metadata.Version = version
+	data, err = yaml.Marshal(metadata)
+	if err != nil {
+		return errors.WrapError(errors.TIUNIMANAGER_MARSHAL_ERROR, "marshal meta.yaml failed", err)
+	}
+	return ioutil.WriteFile(fileName, data, info.Mode())
+}
+
+// checkRollbackVersions
+// @Description: check instances are running the versions before upgrade after rolling back
+func checkRollbackVersions(node *workflowModel.WorkFlowNode, context *workflow.FlowContext) error {
+	var clusterMeta meta.ClusterMeta
+	err := context.GetData(ContextClusterMeta, &clusterMeta)
+	if err != nil {
+		return err
+	}
+	var items []structs.UpgradeRollbackItem
+	err = context.GetData(ContextRollbackItems, &items)
+	if err != nil {
+		return err
+	}
+
+	running := queryRunningVersions(context, &clusterMeta)
+	for _, item := range items {
+		if version, ok := running[item.InstanceID]; ok && version != item.ToVersion {
+			return errors.NewErrorf(errors.TIUNIMANAGER_UPGRADE_ROLLBACK_FAILED, "%s %s is running %s after rolling back, expect %s",
+				item.InstanceType, item.Address, version, item.ToVersion)
+		}
+	}
+	node.Record(fmt.Sprintf("check versions of %d instances as expected", len(items)))
+	return nil
+}
+
+// revertUpgradeVersions
+// @Description: set versions of cluster and instances to the versions before upgrade
+func revertUpgradeVersions(node *workflowModel.WorkFlowNode, context *workflow.FlowContext) error {
+	var clusterMeta meta.ClusterMeta
+	err := context.GetData(ContextClusterMeta, &clusterMeta)
+	if err != nil {
+		return err
+	}
+	var originalVersion string
+	err = context.GetData(ContextOriginalVersion, &originalVersion)
+	if err != nil {
+		return err
+	}
+	var items []structs.UpgradeRollbackItem
+	err = context.GetData(ContextRollbackItems, &items)
+	if err != nil {
+		return err
+	}
+
+	rolledBack := make(map[string]string)
+	for _, item := range items {
+		rolledBack[item.InstanceID] = item.ToVersion
+	}
+	clusterMeta.Cluster.Version = originalVersion
+	for _, instances := range clusterMeta.Instances {
+		for _, instance := range instances {
+			if version, ok := rolledBack[instance.ID]; ok {
+				instance.Version = version
+			}
+		}
+	}
+	context.SetData(ContextClusterMeta, &clusterMeta)
+	node.Record(fmt.Sprintf("revert version of cluster %s to %s", clusterMeta.Cluster.ID, originalVersion))
+	return nil
+}
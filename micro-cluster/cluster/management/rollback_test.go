@@ -0,0 +1,196 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package management
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	"github.com/pingcap/tiunimanager/models/common"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func Test_normalizeVersion(t *testing.T) {
+	assert.Equal(t, "v5.4.0", normalizeVersion("5.7.25-TiDB-v5.4.0"))
+	assert.Equal(t, "v5.4.0", normalizeVersion("5.4.0"))
+	assert.Equal(t, "v5.3.1", normalizeVersion("v5.3.1"))
+	assert.Equal(t, "", normalizeVersion(""))
+}
+
+func Test_checkRollbackCompatible(t *testing.T) {
+	assert.NoError(t, checkRollbackCompatible("TiKV", "v5.4.1", "v5.4.0"))
+	assert.NoError(t, checkRollbackCompatible("TiDB", "v5.4.0", "v5.3.0"))
+	assert.Error(t, checkRollbackCompatible("TiKV", "v5.4.0", "v5.3.0"))
+	assert.Error(t, checkRollbackCompatible("PD", "v5.4.0", "v5.3.0"))
+}
+
+func mockRollbackClusterMeta() *meta.ClusterMeta {
+	return &meta.ClusterMeta{
+		Cluster: &management.Cluster{
+			Entity:  common.Entity{ID: "testCluster"},
+			Version: "v5.4.0",
+		},
+		Instances: map[string][]*management.ClusterInstance{
+			"TiDB": {
+				{Entity: common.Entity{ID: "tidb01"}, Type: "TiDB", HostIP: []string{"127.0.0.1"}, Ports: []int32{4000, 10080}},
+			},
+			"TiKV": {
+				{Entity: common.Entity{ID: "tikv01"}, Type: "TiKV", HostIP: []string{"127.0.0.1"}, Ports: []int32{20160, 20180}},
+				{Entity: common.Entity{ID: "tikv02"}, Type: "TiKV", HostIP: []string{"127.0.0.2"}, Ports: []int32{20160, 20180}},
+			},
+			"PD": {
+				{Entity: common.Entity{ID: "pd01"}, Type: "PD", HostIP: []string{"127.0.0.1"}, Ports: []int32{2379, 2380}},
+			},
+			"Grafana": {
+				{Entity: common.Entity{ID: "grafana01"}, Type: "Grafana", HostIP: []string{"127.0.0.1"}, Ports: []int32{3000}},
+			},
+		},
+	}
+}
+
+func Test_planUpgradeRollback(t *testing.T) {
+	t.Run("patch upgrade", func(t *testing.T) {
+		snapshot := upgradeSnapshot{
+			Version:       "v5.4.0",
+			TargetVersion: "v5.4.1",
+			Versions:      map[string]string{"tidb01": "v5.4.0", "tikv01": "v5.4.0", "pd01": "v5.4.0", "grafana01": "v5.4.0"},
+		}
+		running := map[string]string{"tidb01": "v5.4.1", "tikv01": "v5.4.1", "tikv02": "v5.4.0", "pd01": "v5.4.0"}
+		items, err := planUpgradeRollback(mockRollbackClusterMeta(), snapshot, running)
+		assert.NoError(t, err)
+		assert.Equal(t, []structs.UpgradeRollbackItem{
+			{InstanceID: "tikv01", InstanceType: "TiKV", Address: "127.0.0.1:20160", FromVersion: "v5.4.1", ToVersion: "v5.4.0"},
+			{InstanceID: "tidb01", InstanceType: "TiDB", Address: "127.0.0.1:4000", FromVersion: "v5.4.1", ToVersion: "v5.4.0"},
+		}, sortRollbackItems(items))
+	})
+	t.Run("unknown version", func(t *testing.T) {
+		snapshot := upgradeSnapshot{
+			Version:       "v5.4.0",
+			TargetVersion: "v5.4.1",
+			Versions:      map[string]string{"tidb01": "v5.4.0", "tikv01": "v5.4.0", "tikv02": "v5.4.0", "pd01": "v5.4.0"},
+		}
+		items, err := planUpgradeRollback(mockRollbackClusterMeta(), snapshot, map[string]string{})
+		assert.NoError(t, err)
+		assert.Equal(t, 4, len(items))
+		for _, item := range items {
+			assert.Equal(t, "v5.4.1", item.FromVersion)
+		}
+	})
+	t.Run("refused", func(t *testing.T) {
+		snapshot := upgradeSnapshot{
+			Version:       "v5.3.0",
+			TargetVersion: "v5.4.0",
+			Versions:      map[string]string{"tidb01": "v5.3.0", "tikv01": "v5.3.0", "tikv02": "v5.3.0", "pd01": "v5.3.0"},
+		}
+		running := map[string]string{"tidb01": "v5.4.0", "tikv01": "v5.4.0", "tikv02": "v5.3.0", "pd01": "v5.3.0"}
+		_, err := planUpgradeRollback(mockRollbackClusterMeta(), snapshot, running)
+		assert.Error(t, err)
+	})
+	t.Run("nothing to roll back", func(t *testing.T) {
+		snapshot := upgradeSnapshot{
+			Version:       "v5.3.0",
+			TargetVersion: "v5.4.0",
+			Versions:      map[string]string{"tidb01": "v5.3.0", "tikv01": "v5.3.0", "tikv02": "v5.3.0", "pd01": "v5.3.0"},
+		}
+		running := map[string]string{"tidb01": "v5.3.0", "tikv01": "v5.3.0", "tikv02": "v5.3.0", "pd01": "v5.3.0"}
+		_, err := planUpgradeRollback(mockRollbackClusterMeta(), snapshot, running)
+		assert.Error(t, err)
+	})
+}
+
+func sortRollbackItems(items []structs.UpgradeRollbackItem) []structs.UpgradeRollbackItem {
+	sorted := make([]structs.UpgradeRollbackItem, 0, len(items))
+	for _, instanceType := range []string{"TiKV", "TiDB", "PD"} {
+		for _, item := range items {
+			if item.InstanceType == instanceType {
+				sorted = append(sorted, item)
+			}
+		}
+	}
+	return sorted
+}
+
+func Test_checkRollbackPackages(t *testing.T) {
+	tiupHome, err := ioutil.TempDir("", "upgrade-rollback")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tiupHome)
+
+	items := []structs.UpgradeRollbackItem{
+		{InstanceID: "tikv01", InstanceType: "TiKV", ToVersion: "v5.4.0"},
+	}
+	t.Run("not found", func(t *testing.T) {
+		err := checkRollbackPackages(tiupHome, constants.ArchX8664, items)
+		assert.Error(t, err)
+	})
+	t.Run("normal", func(t *testing.T) {
+		packageDir := filepath.Join(tiupHome, "storage", "cluster", "packages")
+		assert.NoError(t, os.MkdirAll(packageDir, 0755))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(packageDir, "tikv-v5.4.0-linux-amd64.tar.gz"), []byte("tikv package"), 0644))
+		err := checkRollbackPackages(tiupHome, constants.ArchX8664, items)
+		assert.NoError(t, err)
+	})
+}
+
+func Test_setTiUPClusterVersion(t *testing.T) {
+	clusterHome, err := ioutil.TempDir("", "upgrade-rollback")
+	assert.NoError(t, err)
+	defer os.RemoveAll(clusterHome)
+
+	t.Run("not found", func(t *testing.T) {
+		err := setTiUPClusterVersion(clusterHome, "v5.3.0")
+		assert.Error(t, err)
+	})
+	t.Run("normal", func(t *testing.T) {
+		data, err := yaml.Marshal(&spec.ClusterMeta{
+			User:    "tidb",
+			Version: "v5.4.0",
+			Topology: &spec.Specification{
+				TiDBServers: []*spec.TiDBSpec{
+					{Host: "127.0.0.1", Port: 4000, StatusPort: 10080},
+				},
+				TiKVServers: []*spec.TiKVSpec{
+					{Host: "127.0.0.2", Port: 20160, StatusPort: 20180},
+				},
+				PDServers: []*spec.PDSpec{
+					{Host: "127.0.0.3", ClientPort: 2379, PeerPort: 2380},
+				},
+			},
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(clusterHome, "meta.yaml"), data, 0644))
+
+		err = setTiUPClusterVersion(clusterHome, "v5.3.0")
+		assert.NoError(t, err)
+
+		data, err = ioutil.ReadFile(filepath.Join(clusterHome, "meta.yaml"))
+		assert.NoError(t, err)
+		metadata := &spec.ClusterMeta{}
+		assert.NoError(t, yaml.Unmarshal(data, metadata))
+		assert.Equal(t, "v5.3.0", metadata.Version)
+		assert.Equal(t, "tidb", metadata.User)
+		assert.Equal(t, 1, len(metadata.Topology.TiDBServers))
+		assert.Equal(t, 1, len(metadata.Topology.TiKVServers))
+		assert.Equal(t, "127.0.0.3", metadata.Topology.PDServers[0].Host)
+	})
+}
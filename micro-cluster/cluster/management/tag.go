@@ -0,0 +1,114 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package management
+
+import (
+	"context"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/models"
+)
+
+// AddClusterTags
+// @Description: add tags to cluster, value of an existing key is overwritten
+// @Receiver p
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (p *Manager) AddClusterTags(ctx context.Context, req cluster.AddClusterTagsReq) (resp cluster.AddClusterTagsResp, err error) {
+	tags, err := parseClusterTags(req.Tags)
+	if err != nil {
+		return
+	}
+	for _, tag := range tags {
+		if err = validateClusterTagKey(tag.Key); err != nil {
+			return
+		}
+	}
+
+	return p.updateClusterTags(ctx, req.ClusterID, tags, nil)
+}
+
+// RemoveClusterTags
+// @Description: remove tags of cluster by key, keys not found are ignored
+// @Receiver p
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (p *Manager) RemoveClusterTags(ctx context.Context, req cluster.RemoveClusterTagsReq) (resp cluster.RemoveClusterTagsResp, err error) {
+	for _, key := range req.Keys {
+		if err = validateClusterTagKey(key); err != nil {
+			return
+		}
+	}
+
+	updated, err := p.updateClusterTags(ctx, req.ClusterID, nil, req.Keys)
+	if err != nil {
+		return
+	}
+	resp.ClusterID = updated.ClusterID
+	resp.Tags = updated.Tags
+	return
+}
+
+func (p *Manager) updateClusterTags(ctx context.Context, clusterID string, added []structs.ClusterTag, removedKeys []string) (resp cluster.AddClusterTagsResp, err error) {
+	clusterMeta, err := meta.Get(ctx, clusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("load cluster %s meta from db error: %s", clusterID, err.Error())
+		return
+	}
+	if err = checkClusterPermission(ctx, clusterMeta, constants.RbacActionUpdate); err != nil {
+		return
+	}
+
+	tags, err := models.GetClusterReaderWriter().UpdateClusterTags(ctx, clusterID, added, removedKeys)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("update tags of cluster %s failed, %s", clusterID, err.Error())
+		return
+	}
+	framework.LogWithContext(ctx).Infof("tags of cluster %s are updated to %v", clusterID, tags)
+	resp.ClusterID = clusterID
+	resp.Tags = tags
+	return
+}
+
+// validateClusterTagKey tag of takeover is maintained by the system
+func validateClusterTagKey(key string) error {
+	if key == meta.TagTakeover {
+		return errors.NewErrorf(errors.TIUNIMANAGER_PARAMETER_INVALID, "tag %s is reserved", key)
+	}
+	return nil
+}
+
+// parseClusterTags parse and validate tags in the format of key=value or key
+func parseClusterTags(tags []string) ([]structs.ClusterTag, error) {
+	result := make([]structs.ClusterTag, 0, len(tags))
+	for _, tag := range tags {
+		clusterTag := structs.ParseClusterTag(tag)
+		if err := clusterTag.Validate(); err != nil {
+			return nil, errors.WrapError(errors.TIUNIMANAGER_PARAMETER_INVALID, err.Error(), err)
+		}
+		result = append(result, clusterTag)
+	}
+	return result, nil
+}
@@ -0,0 +1,47 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package management
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_AddClusterTags_invalid(t *testing.T) {
+	manager := &Manager{}
+	for _, tag := range []string{meta.TagTakeover, "team name=dba", "=dba", "team=dba,ops"} {
+		_, err := manager.AddClusterTags(context.TODO(), cluster.AddClusterTagsReq{
+			ClusterID: "testCluster",
+			Tags:      []string{"env=prod", tag},
+		})
+		assert.Error(t, err, tag)
+		assert.Equal(t, errors.TIUNIMANAGER_PARAMETER_INVALID, err.(errors.EMError).GetCode())
+	}
+}
+
+func TestManager_RemoveClusterTags_reserved(t *testing.T) {
+	manager := &Manager{}
+	_, err := manager.RemoveClusterTags(context.TODO(), cluster.RemoveClusterTagsReq{
+		ClusterID: "testCluster",
+		Keys:      []string{meta.TagTakeover},
+	})
+	assert.Error(t, err)
+}
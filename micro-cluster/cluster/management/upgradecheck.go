@@ -0,0 +1,454 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package management
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/parameter"
+	workflowModel "github.com/pingcap/tiunimanager/models/workflow"
+	workflow "github.com/pingcap/tiunimanager/workflow2"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+)
+
+const (
+	upgradeRestartSeconds      = 30   // restarting an instance with the new binary
+	upgradeEvictLeaderRate     = 100  // leaders evicted per second before restarting a TiKV store
+	upgradeTransferTimeout     = 600  // tiup gives up evicting leaders of a store after the transfer timeout
+	upgradeRegionLoadRate      = 1000 // regions loaded per second when a store starts
+	regionHealthyCheckItemName = "regions"
+)
+
+// upgradeComponents components upgraded by tiup, the key is instance type of cluster
+var upgradeComponents = map[string]string{
+	string(constants.ComponentIDTiDB):    spec.ComponentTiDB,
+	string(constants.ComponentIDTiKV):    spec.ComponentTiKV,
+	string(constants.ComponentIDPD):      spec.ComponentPD,
+	string(constants.ComponentIDTiFlash): spec.ComponentTiFlash,
+	string(constants.ComponentIDCDC):     spec.ComponentCDC,
+}
+
+// upgradeChecker check whether the cluster is ready to upgrade to the target version by the way
+type upgradeChecker func(ctx context.Context, clusterMeta *meta.ClusterMeta, version string, way string) []structs.UpgradeCheckItem
+
+// tiupComponentManifest component manifest of tiup mirror, which is cached in tiup home
+type tiupComponentManifest struct {
+	Signed struct {
+		Platforms map[string]map[string]struct {
+			Yanked bool              `json:"yanked"`
+			URL    string            `json:"url"`
+			Hashes map[string]string `json:"hashes"`
+		} `json:"platforms"`
+	} `json:"signed"`
+}
+
+func upgradeCheckPassed(items []structs.UpgradeCheckItem) bool {
+	for _, item := range items {
+		if item.Blocking && !item.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// QueryUpgradeCheckReport
+// @Description: run all pre-upgrade checks without upgrading the cluster
+// @Receiver p
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (p *Manager) QueryUpgradeCheckReport(ctx context.Context, req cluster.QueryUpgradeCheckReportReq) (resp cluster.QueryUpgradeCheckReportResp, err error) {
+	clusterMeta, err := meta.Get(ctx, req.ClusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf(
+			"load cluster %s meta from db error: %s", req.ClusterID, err.Error())
+		return
+	}
+	if err = checkClusterPermission(ctx, clusterMeta, constants.RbacActionRead); err != nil {
+		return
+	}
+	way := req.UpgradeWay
+	if way == "" {
+		way = string(constants.UpgradeWayOffline)
+	}
+
+	items := checkUpgradeBinaries(ctx, clusterMeta, req.TargetVersion, way)
+	duration, durationItem := estimateUpgrade(ctx, clusterMeta, way)
+	items = append(items, durationItem)
+	items = append(items, checkUpgradeConfigs(ctx, clusterMeta, req.TargetVersion, way)...)
+	items = append(items, checkUpgradeHealth(ctx, clusterMeta, req.TargetVersion, way)...)
+
+	resp = cluster.QueryUpgradeCheckReportResp{
+		ClusterID:         clusterMeta.Cluster.ID,
+		TargetVersion:     req.TargetVersion,
+		Passed:            upgradeCheckPassed(items),
+		EstimatedDuration: int64(duration.Seconds()),
+		Items:             items,
+	}
+	return
+}
+
+// checkUpgradeBinaries
+// @Description: check packages of target version are available in tiup mirror, and verify checksum of the cached packages
+func checkUpgradeBinaries(ctx context.Context, clusterMeta *meta.ClusterMeta, version string, way string) []structs.UpgradeCheckItem {
+	platform := fmt.Sprintf("linux/%s", constants.GetArchAlias(clusterMeta.Cluster.CpuArchitecture))
+	tiupHome := framework.GetTiupHomePathForTidb()
+
+	items := make([]structs.UpgradeCheckItem, 0)
+	for _, instanceType := range sortedInstanceTypes(clusterMeta) {
+		component, ok := upgradeComponents[instanceType]
+		if !ok {
+			continue
+		}
+		item := checkComponentPackage(tiupHome, component, version, platform)
+		framework.LogWithContext(ctx).Infof("check package of cluster %s: %s", clusterMeta.Cluster.ID, item.Message)
+		items = append(items, item)
+	}
+	return items
+}
+
+func sortedInstanceTypes(clusterMeta *meta.ClusterMeta) []string {
+	instanceTypes := getRunningInstanceTypes(clusterMeta)
+	sort.Strings(instanceTypes)
+	return instanceTypes
+}
+
+// checkComponentPackage
+// @Description: check the package of component is released for the platform and not yanked in the manifest cached in tiup home,
+// checksum of the package is verified if it has been downloaded
+// @Parameter tiupHome
+// @Parameter component
+// @Parameter version
+// @Parameter platform like linux/amd64
+// @return structs.UpgradeCheckItem
+func checkComponentPackage(tiupHome string, component string, version string, platform string) structs.UpgradeCheckItem {
+	item := structs.UpgradeCheckItem{
+		Category: string(constants.UpgradeCheckBinary),
+		Name:     component,
+		Blocking: true,
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(tiupHome, "manifests", component+".json"))
+	if err != nil {
+		// tiup fetches the manifest from mirror when upgrading, the package can not be verified here
+		item.Blocking = false
+		item.Message = fmt.Sprintf("manifest of %s is not found in %s, package will be checked by tiup when upgrading", component, tiupHome)
+		return item
+	}
+	manifest := &tiupComponentManifest{}
+	if err = json.Unmarshal(data, manifest); err != nil {
+		item.Message = fmt.Sprintf("manifest of %s is invalid, %s", component, err.Error())
+		return item
+	}
+	release, ok := manifest.Signed.Platforms[platform][version]
+	if !ok {
+		item.Message = fmt.Sprintf("%s %s for %s is not found in tiup mirror", component, version, platform)
+		return item
+	}
+	if release.Yanked {
+		item.Message = fmt.Sprintf("%s %s for %s has been yanked from tiup mirror", component, version, platform)
+		return item
+	}
+	checksum := release.Hashes["sha256"]
+	if checksum == "" {
+		item.Message = fmt.Sprintf("%s %s for %s has no sha256 checksum in tiup mirror", component, version, platform)
+		return item
+	}
+
+	packagePath := filepath.Join(tiupHome, "storage", "cluster", "packages", strings.TrimPrefix(release.URL, "/"))
+	actual, err := fileChecksum(packagePath)
+	if os.IsNotExist(err) {
+		item.Passed = true
+		item.Message = fmt.Sprintf("%s %s for %s is available in tiup mirror, it will be downloaded when upgrading", component, version, platform)
+		return item
+	}
+	if err != nil {
+		item.Message = fmt.Sprintf("read package %s failed, %s", packagePath, err.Error())
+		return item
+	}
+	if actual != checksum {
+		item.Message = fmt.Sprintf("checksum of package %s mismatched, expected %s, got %s", packagePath, checksum, actual)
+		return item
+	}
+	item.Passed = true
+	item.Message = fmt.Sprintf("%s %s for %s is available, checksum verified", component, version, platform)
+	return item
+}
+
+func fileChecksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func checkUpgradeDuration(ctx context.Context, clusterMeta *meta.ClusterMeta, version string, way string) []structs.UpgradeCheckItem {
+	_, item := estimateUpgrade(ctx, clusterMeta, way)
+	return []structs.UpgradeCheckItem{item}
+}
+
+// estimateUpgrade
+// @Description: estimate duration of upgrade by region and leader count of stores in PD
+func estimateUpgrade(ctx context.Context, clusterMeta *meta.ClusterMeta, way string) (time.Duration, structs.UpgradeCheckItem) {
+	item := structs.UpgradeCheckItem{
+		Category: string(constants.UpgradeCheckDuration),
+		Name:     "estimatedDuration",
+	}
+	stores, err := queryStores(ctx, clusterMeta)
+	if err != nil {
+		framework.LogWithContext(ctx).Warnf("query stores of cluster %s failed, %s", clusterMeta.Cluster.ID, err.Error())
+		stores = &meta.StoreInfos{}
+	}
+	duration := estimateUpgradeDuration(clusterMeta, stores, way)
+
+	regions, leaders := 0, 0
+	for _, store := range stores.Stores {
+		regions += store.Status.RegionCount
+		leaders += store.Status.LeaderCount
+	}
+	item.Passed = err == nil
+	item.Message = fmt.Sprintf("%s upgrade is estimated to take %s, %d stores with %d regions and %d leaders",
+		way, duration.String(), len(stores.Stores), regions, leaders)
+	if err != nil {
+		item.Message = fmt.Sprintf("%s, regions are not counted because querying stores failed: %s", item.Message, err.Error())
+	}
+	return duration, item
+}
+
+// estimateUpgradeDuration
+// @Description: instances are restarted one by one in online upgrade, and leaders of TiKV are evicted before restarting;
+// offline upgrade restarts all instances of a component at once, and stores have to load their regions when starting
+// @Parameter clusterMeta
+// @Parameter stores
+// @Parameter way
+// @return time.Duration
+func estimateUpgradeDuration(clusterMeta *meta.ClusterMeta, stores *meta.StoreInfos, way string) time.Duration {
+	seconds := 0
+	if way == string(constants.UpgradeWayOnline) {
+		for instanceType, instances := range clusterMeta.Instances {
+			if _, ok := upgradeComponents[instanceType]; ok {
+				seconds += len(instances) * upgradeRestartSeconds
+			}
+		}
+		for _, store := range stores.Stores {
+			evictSeconds := store.Status.LeaderCount / upgradeEvictLeaderRate
+			if evictSeconds > upgradeTransferTimeout {
+				evictSeconds = upgradeTransferTimeout
+			}
+			seconds += evictSeconds
+		}
+	} else {
+		for instanceType := range clusterMeta.Instances {
+			if _, ok := upgradeComponents[instanceType]; ok {
+				// stop and start
+				seconds += 2 * upgradeRestartSeconds
+			}
+		}
+		maxRegions := 0
+		for _, store := range stores.Stores {
+			if store.Status.RegionCount > maxRegions {
+				maxRegions = store.Status.RegionCount
+			}
+		}
+		seconds += maxRegions / upgradeRegionLoadRate
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// checkUpgradeConfigs
+// @Description: find configs of which the value will be changed or which are deprecated in target version
+func checkUpgradeConfigs(ctx context.Context, clusterMeta *meta.ClusterMeta, version string, way string) []structs.UpgradeCheckItem {
+	clusterParams, groupParams, err := queryUpgradeParameters(ctx, clusterMeta, version)
+	if err != nil {
+		return []structs.UpgradeCheckItem{{
+			Category: string(constants.UpgradeCheckConfig),
+			Name:     "parameters",
+			Message:  fmt.Sprintf("query parameters of version %s failed, %s", version, err.Error()),
+		}}
+	}
+	runningInstanceTypes := getRunningInstanceTypes(clusterMeta)
+
+	items := make([]structs.UpgradeCheckItem, 0)
+	changed := compareConfigDifference(ctx, clusterParams, groupParams, runningInstanceTypes)
+	sort.Slice(changed, func(i, j int) bool {
+		return changed[i].InstanceType+changed[i].Name < changed[j].InstanceType+changed[j].Name
+	})
+	for _, diff := range changed {
+		items = append(items, structs.UpgradeCheckItem{
+			Category: string(constants.UpgradeCheckConfig),
+			Name:     fmt.Sprintf("%s.%s", diff.InstanceType, diff.Name),
+			Message: fmt.Sprintf("default value in %s is %s, current value is %s",
+				version, diff.SuggestValue, diff.CurrentValue),
+		})
+	}
+	for _, deprecated := range findDeprecatedConfigs(clusterParams, groupParams, runningInstanceTypes) {
+		items = append(items, structs.UpgradeCheckItem{
+			Category: string(constants.UpgradeCheckConfig),
+			Name:     fmt.Sprintf("%s.%s", deprecated.InstanceType, deprecated.Name),
+			Message: fmt.Sprintf("deprecated in %s, current value %s will be dropped",
+				version, deprecated.RealValue.ClusterValue),
+		})
+	}
+	if len(items) == 0 {
+		items = append(items, structs.UpgradeCheckItem{
+			Category: string(constants.UpgradeCheckConfig),
+			Name:     "parameters",
+			Passed:   true,
+			Message:  fmt.Sprintf("no config is changed or deprecated in %s", version),
+		})
+	}
+	return items
+}
+
+// findDeprecatedConfigs
+// @Description: configs of running components which are not in the default parameter group of target version
+func findDeprecatedConfigs(clusterParams []structs.ClusterParameterInfo, groupParams []structs.ParameterGroupParameterInfo,
+	runningInstanceTypes []string) []structs.ClusterParameterInfo {
+	targetParams := make(map[string]bool)
+	for _, param := range groupParams {
+		targetParams[param.ID] = true
+	}
+
+	deprecated := make([]structs.ClusterParameterInfo, 0)
+	for _, param := range clusterParams {
+		if !meta.Contain(runningInstanceTypes, param.InstanceType) || param.HasApply == int(parameter.ModifyApply) {
+			continue
+		}
+		if !targetParams[param.ParamId] {
+			deprecated = append(deprecated, param)
+		}
+	}
+	sort.Slice(deprecated, func(i, j int) bool {
+		return deprecated[i].InstanceType+deprecated[i].Name < deprecated[j].InstanceType+deprecated[j].Name
+	})
+	return deprecated
+}
+
+// checkUpgradeHealth
+// @Description: all instances are running, all stores are up and all regions are healthy
+func checkUpgradeHealth(ctx context.Context, clusterMeta *meta.ClusterMeta, version string, way string) []structs.UpgradeCheckItem {
+	items := make([]structs.UpgradeCheckItem, 0)
+	for _, instanceType := range sortedInstanceTypes(clusterMeta) {
+		for _, instance := range clusterMeta.Instances[instanceType] {
+			if instance.Status != string(constants.ClusterInstanceRunning) {
+				items = append(items, structs.UpgradeCheckItem{
+					Category: string(constants.UpgradeCheckHealth),
+					Name:     fmt.Sprintf("%s %s", instance.Type, instance.ID),
+					Blocking: true,
+					Message:  fmt.Sprintf("instance status is %s", instance.Status),
+				})
+			}
+		}
+	}
+
+	stores, err := queryStores(ctx, clusterMeta)
+	if err != nil {
+		items = append(items, structs.UpgradeCheckItem{
+			Category: string(constants.UpgradeCheckHealth),
+			Name:     "stores",
+			Blocking: true,
+			Message:  fmt.Sprintf("query stores failed, %s", err.Error()),
+		})
+	} else {
+		for _, store := range stores.Stores {
+			state := meta.StoreStatus(store.Store.StateName)
+			if state != meta.StoreUp && state != meta.StoreTombstone {
+				items = append(items, structs.UpgradeCheckItem{
+					Category: string(constants.UpgradeCheckHealth),
+					Name:     fmt.Sprintf("store %d", store.Store.ID),
+					Blocking: true,
+					Message:  fmt.Sprintf("store %s is %s", store.Store.Address, store.Store.StateName),
+				})
+			}
+		}
+	}
+
+	regionItem := structs.UpgradeCheckItem{
+		Category: string(constants.UpgradeCheckHealth),
+		Name:     regionHealthyCheckItemName,
+		Blocking: true,
+		Passed:   true,
+		Message:  "all regions are healthy",
+	}
+	if err = checkRegionsHealthy(ctx, clusterMeta.Cluster.ID); err != nil {
+		regionItem.Passed = false
+		regionItem.Message = err.Error()
+	}
+	items = append(items, regionItem)
+	return items
+}
+
+// executeUpgradeCheck
+// @Description: run the checker in upgrade workflow, record all items and fail the node if any blocking item is not passed
+// @Parameter node
+// @Parameter context
+// @Parameter checker
+// @return error
+func executeUpgradeCheck(node *workflowModel.WorkFlowNode, context *workflow.FlowContext, checker upgradeChecker) error {
+	var clusterMeta meta.ClusterMeta
+	err := context.GetData(ContextClusterMeta, &clusterMeta)
+	if err != nil {
+		return err
+	}
+	var version string
+	err = context.GetData(ContextUpgradeVersion, &version)
+	if err != nil {
+		return err
+	}
+	var way string
+	err = context.GetData(ContextUpgradeWay, &way)
+	if err != nil {
+		return err
+	}
+
+	items := checker(context.Context, &clusterMeta, version, way)
+	failed := make([]string, 0)
+	for _, item := range items {
+		node.Record(fmt.Sprintf("%s %s passed: %t, %s", item.Category, item.Name, item.Passed, item.Message))
+		if item.Blocking && !item.Passed {
+			failed = append(failed, fmt.Sprintf("%s: %s", item.Name, item.Message))
+		}
+	}
+	if len(failed) > 0 {
+		return errors.NewErrorf(errors.TIUNIMANAGER_UPGRADE_PRECHECK_FAILED, "cluster %s is not ready to upgrade to %s, %s",
+			clusterMeta.Cluster.ID, version, strings.Join(failed, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package management
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/parameter"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeComponentManifest(t *testing.T, tiupHome string, component string, content string) {
+	assert.NoError(t, os.MkdirAll(filepath.Join(tiupHome, "manifests"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(tiupHome, "manifests", component+".json"), []byte(content), 0644))
+}
+
+func Test_checkComponentPackage(t *testing.T) {
+	tiupHome, err := ioutil.TempDir("", "upgrade-check")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tiupHome)
+
+	packageContent := []byte("tikv package")
+	sum := sha256.Sum256(packageContent)
+	writeComponentManifest(t, tiupHome, "tikv", fmt.Sprintf(`{"signed":{"platforms":{"linux/amd64":{
+		"v5.4.0":{"yanked":false,"url":"/tikv-v5.4.0-linux-amd64.tar.gz","hashes":{"sha256":"%s"}},
+		"v5.3.0":{"yanked":true,"url":"/tikv-v5.3.0-linux-amd64.tar.gz","hashes":{"sha256":"%s"}}}}}}`,
+		hex.EncodeToString(sum[:]), hex.EncodeToString(sum[:])))
+
+	t.Run("no manifest", func(t *testing.T) {
+		item := checkComponentPackage(tiupHome, "tidb", "v5.4.0", "linux/amd64")
+		assert.False(t, item.Passed)
+		assert.False(t, item.Blocking)
+	})
+	t.Run("version not found", func(t *testing.T) {
+		item := checkComponentPackage(tiupHome, "tikv", "v5.5.0", "linux/amd64")
+		assert.False(t, item.Passed)
+		assert.True(t, item.Blocking)
+	})
+	t.Run("platform not found", func(t *testing.T) {
+		item := checkComponentPackage(tiupHome, "tikv", "v5.4.0", "linux/arm64")
+		assert.False(t, item.Passed)
+	})
+	t.Run("yanked", func(t *testing.T) {
+		item := checkComponentPackage(tiupHome, "tikv", "v5.3.0", "linux/amd64")
+		assert.False(t, item.Passed)
+		assert.True(t, item.Blocking)
+	})
+	t.Run("not downloaded", func(t *testing.T) {
+		item := checkComponentPackage(tiupHome, "tikv", "v5.4.0", "linux/amd64")
+		assert.True(t, item.Passed)
+	})
+
+	packageDir := filepath.Join(tiupHome, "storage", "cluster", "packages")
+	assert.NoError(t, os.MkdirAll(packageDir, 0755))
+	t.Run("checksum verified", func(t *testing.T) {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(packageDir, "tikv-v5.4.0-linux-amd64.tar.gz"), packageContent, 0644))
+		item := checkComponentPackage(tiupHome, "tikv", "v5.4.0", "linux/amd64")
+		assert.True(t, item.Passed)
+	})
+	t.Run("checksum mismatched", func(t *testing.T) {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(packageDir, "tikv-v5.4.0-linux-amd64.tar.gz"), []byte("broken"), 0644))
+		item := checkComponentPackage(tiupHome, "tikv", "v5.4.0", "linux/amd64")
+		assert.False(t, item.Passed)
+		assert.True(t, item.Blocking)
+	})
+}
+
+func Test_estimateUpgradeDuration(t *testing.T) {
+	clusterMeta := &meta.ClusterMeta{
+		Instances: map[string][]*management.ClusterInstance{
+			string(constants.ComponentIDTiDB):    {{}, {}},
+			string(constants.ComponentIDTiKV):    {{}, {}, {}},
+			string(constants.ComponentIDPD):      {{}},
+			string(constants.ComponentIDGrafana): {{}},
+		},
+	}
+	stores := &meta.StoreInfos{Stores: make([]meta.StoreInfo, 3)}
+	for i := range stores.Stores {
+		stores.Stores[i].Status.LeaderCount = 1000
+		stores.Stores[i].Status.RegionCount = 3000
+	}
+	// leaders of the last store can not be evicted in transfer timeout
+	stores.Stores[2].Status.LeaderCount = 100000
+
+	t.Run("online", func(t *testing.T) {
+		duration := estimateUpgradeDuration(clusterMeta, stores, string(constants.UpgradeWayOnline))
+		assert.Equal(t, time.Duration(6*upgradeRestartSeconds+10+10+upgradeTransferTimeout)*time.Second, duration)
+	})
+	t.Run("offline", func(t *testing.T) {
+		duration := estimateUpgradeDuration(clusterMeta, stores, string(constants.UpgradeWayOffline))
+		assert.Equal(t, time.Duration(3*2*upgradeRestartSeconds+3)*time.Second, duration)
+	})
+	t.Run("no stores", func(t *testing.T) {
+		duration := estimateUpgradeDuration(clusterMeta, &meta.StoreInfos{}, string(constants.UpgradeWayOnline))
+		assert.Equal(t, time.Duration(6*upgradeRestartSeconds)*time.Second, duration)
+	})
+}
+
+func Test_findDeprecatedConfigs(t *testing.T) {
+	clusterParams := []structs.ClusterParameterInfo{
+		{ParamId: "1", Name: "kept", InstanceType: "TiKV"},
+		{ParamId: "2", Name: "removed", InstanceType: "TiKV"},
+		{ParamId: "3", Name: "not running", InstanceType: "TiFlash"},
+		{ParamId: "4", Name: "modify apply", InstanceType: "TiDB", HasApply: int(parameter.ModifyApply)},
+		{ParamId: "5", Name: "removed", InstanceType: "PD"},
+	}
+	groupParams := []structs.ParameterGroupParameterInfo{
+		{ID: "1", Name: "kept", InstanceType: "TiKV"},
+	}
+
+	deprecated := findDeprecatedConfigs(clusterParams, groupParams, []string{"TiDB", "TiKV", "PD"})
+	assert.Equal(t, 2, len(deprecated))
+	assert.Equal(t, "5", deprecated[0].ParamId)
+	assert.Equal(t, "2", deprecated[1].ParamId)
+}
+
+func Test_upgradeCheckPassed(t *testing.T) {
+	assert.True(t, upgradeCheckPassed([]structs.UpgradeCheckItem{
+		{Passed: true, Blocking: true},
+		{Passed: false, Blocking: false},
+	}))
+	assert.False(t, upgradeCheckPassed([]structs.UpgradeCheckItem{
+		{Passed: true, Blocking: true},
+		{Passed: false, Blocking: true},
+	}))
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/tiunimanager/common/errors"
 	"github.com/pingcap/tiunimanager/library/framework"
 	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
 	"github.com/pingcap/tiunimanager/models"
 )
 
@@ -72,6 +73,9 @@ func validateCreating(ctx context.Context, req *cluster.CreateClusterReq) error
 			}
 		}
 		return nil
+	}).BreakIf(func() error {
+		_, err := meta.ParseMaintainWindow(req.MaintainWindow)
+		return err
 	}).If(func(err error) {
 		framework.LogWithContext(ctx).Error(err.Error())
 	}).Else(func() {
@@ -0,0 +1,74 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package monitor
+
+import (
+	"github.com/pingcap/tiunimanager/common/constants"
+)
+
+// rangeWindow placeholder of range window in PromQL of named metrics, it is replaced according to the step
+const rangeWindow = "$window"
+
+// namedMetric PromQL and unit of a named metric
+type namedMetric struct {
+	Expr string
+	Unit string
+	// Labels labels kept in result and their normalized names, other labels are dropped
+	Labels map[string]string
+}
+
+var namedMetrics = map[constants.ClusterMetricName]namedMetric{
+	constants.ClusterMetricQPS: {
+		Expr:   `sum(rate(tidb_executor_statement_total[$window])) by (type)`,
+		Unit:   "ops",
+		Labels: map[string]string{"type": "type"},
+	},
+	constants.ClusterMetricLatencyP999: {
+		Expr:   `histogram_quantile(0.999, sum(rate(tidb_server_handle_query_duration_seconds_bucket[$window])) by (le))`,
+		Unit:   "seconds",
+		Labels: map[string]string{},
+	},
+	constants.ClusterMetricLatencyP99: {
+		Expr:   `histogram_quantile(0.99, sum(rate(tidb_server_handle_query_duration_seconds_bucket[$window])) by (le))`,
+		Unit:   "seconds",
+		Labels: map[string]string{},
+	},
+	constants.ClusterMetricLatencyP95: {
+		Expr:   `histogram_quantile(0.95, sum(rate(tidb_server_handle_query_duration_seconds_bucket[$window])) by (le))`,
+		Unit:   "seconds",
+		Labels: map[string]string{},
+	},
+	constants.ClusterMetricLatencyP80: {
+		Expr:   `histogram_quantile(0.80, sum(rate(tidb_server_handle_query_duration_seconds_bucket[$window])) by (le))`,
+		Unit:   "seconds",
+		Labels: map[string]string{},
+	},
+	constants.ClusterMetricStorageUsed: {
+		Expr:   `sum(tikv_store_size_bytes{type="used"}) by (instance)`,
+		Unit:   "bytes",
+		Labels: map[string]string{"instance": "instance"},
+	},
+	constants.ClusterMetricStorageCapacity: {
+		Expr:   `sum(tikv_store_size_bytes{type="capacity"}) by (instance)`,
+		Unit:   "bytes",
+		Labels: map[string]string{"instance": "instance"},
+	},
+	constants.ClusterMetricCPUByComponent: {
+		Expr:   `sum(rate(process_cpu_seconds_total[$window])) by (job)`,
+		Unit:   "cores",
+		Labels: map[string]string{"job": "component"},
+	},
+}
@@ -0,0 +1,189 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/micro-cluster/user/rbac"
+	"github.com/pingcap/tiunimanager/util/api/prometheus"
+)
+
+// minRangeWindow range window of rate should cover at least 4 scrape intervals
+const minRangeWindow = 4 * constants.MetricsMinStep
+
+type Manager struct{}
+
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// QueryClusterMetrics
+// @Description: run a named metric or a raw PromQL over a time range against prometheus of cluster, and return normalized series
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (m *Manager) QueryClusterMetrics(ctx context.Context, req cluster.QueryClusterMetricsReq) (resp cluster.QueryClusterMetricsResp, err error) {
+	if (len(req.Name) == 0) == (len(req.Query) == 0) {
+		err = errors.NewError(errors.TIUNIMANAGER_METRICS_QUERY_INVALID, "either name or query is required")
+		return
+	}
+	var metric *namedMetric
+	if len(req.Name) > 0 {
+		named, ok := namedMetrics[constants.ClusterMetricName(req.Name)]
+		if !ok {
+			err = errors.NewErrorf(errors.TIUNIMANAGER_METRICS_QUERY_INVALID, "metric %s is not supported", req.Name)
+			return
+		}
+		metric = &named
+	}
+	start, end, step, err := getTimeRange(req.StartTime, req.EndTime, req.Step, time.Now().Unix())
+	if err != nil {
+		return
+	}
+
+	clusterMeta, err := meta.Get(ctx, req.ClusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("load cluster %s meta from db error: %s", req.ClusterID, err.Error())
+		return
+	}
+	if err = rbac.CheckClusterPermission(ctx, clusterMeta.Cluster.ID, clusterMeta.Cluster.Tags, clusterMeta.Cluster.TenantId, constants.RbacActionRead); err != nil {
+		return
+	}
+	address, err := getPrometheusAddress(clusterMeta)
+	if err != nil {
+		return
+	}
+
+	query := req.Query
+	if metric != nil {
+		window := step
+		if window < minRangeWindow {
+			window = minRangeWindow
+		}
+		query = strings.ReplaceAll(metric.Expr, rangeWindow, fmt.Sprintf("%ds", window))
+	}
+	series, err := prometheus.PrometheusService.QueryRange(ctx, address, query,
+		time.Unix(start, 0), time.Unix(end, 0), time.Duration(step)*time.Second)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query metrics of cluster %s failed, query = %s, err = %s", req.ClusterID, query, err.Error())
+		return
+	}
+
+	resp = cluster.QueryClusterMetricsResp{
+		ClusterID: req.ClusterID,
+		Name:      req.Name,
+		Query:     query,
+		StartTime: start,
+		EndTime:   end,
+		Step:      step,
+		Series:    make([]structs.MetricSeries, 0, len(series)),
+	}
+	if metric != nil {
+		resp.Unit = metric.Unit
+	}
+	for _, s := range series {
+		resp.Series = append(resp.Series, normalizeSeries(s, metric))
+	}
+	return
+}
+
+// getTimeRange
+// @Description: end time is now and start time is an hour before end time by default,
+// default step keeps about MetricsDefaultPoints points, and points are limited by MetricsMaxPoints
+// @Parameter startTime
+// @Parameter endTime
+// @Parameter step
+// @Parameter now
+// @return start
+// @return end
+// @return finalStep
+// @return err
+func getTimeRange(startTime, endTime, step, now int64) (start, end, finalStep int64, err error) {
+	end = endTime
+	if end == 0 {
+		end = now
+	}
+	start = startTime
+	if start == 0 {
+		start = end - constants.MetricsDefaultRange
+	}
+	if start < 0 || start >= end {
+		err = errors.NewErrorf(errors.TIUNIMANAGER_METRICS_QUERY_INVALID, "start time %d should be before end time %d", start, end)
+		return
+	}
+
+	finalStep = step
+	if finalStep == 0 {
+		finalStep = (end - start) / constants.MetricsDefaultPoints
+		if finalStep < constants.MetricsMinStep {
+			finalStep = constants.MetricsMinStep
+		}
+	}
+	if finalStep < 0 {
+		err = errors.NewErrorf(errors.TIUNIMANAGER_METRICS_QUERY_INVALID, "step %d is invalid", step)
+		return
+	}
+	if (end-start)/finalStep > constants.MetricsMaxPoints {
+		err = errors.NewErrorf(errors.TIUNIMANAGER_METRICS_QUERY_INVALID, "step %d is too small, points of each series should not exceed %d", finalStep, constants.MetricsMaxPoints)
+		return
+	}
+	return
+}
+
+// getPrometheusAddress
+// @Description: get address of the running prometheus of cluster
+func getPrometheusAddress(clusterMeta *meta.ClusterMeta) (string, error) {
+	addresses := clusterMeta.GetMonitorAddresses()
+	if len(addresses) == 0 {
+		return "", errors.NewErrorf(errors.TIUNIMANAGER_PROMETHEUS_UNAVAILABLE, "no running prometheus in cluster %s", clusterMeta.Cluster.ID)
+	}
+	return fmt.Sprintf("%s:%d", addresses[0].IP, addresses[0].Port), nil
+}
+
+// normalizeSeries
+// @Description: labels of named metric are renamed and others are dropped, labels of raw query are kept
+func normalizeSeries(series prometheus.Series, metric *namedMetric) structs.MetricSeries {
+	result := structs.MetricSeries{
+		Labels: make(map[string]string),
+		Points: make([]structs.MetricPoint, 0, len(series.Samples)),
+	}
+	for name, value := range series.Labels {
+		if metric == nil {
+			result.Labels[name] = value
+		} else if normalized, ok := metric.Labels[name]; ok {
+			result.Labels[normalized] = value
+		}
+	}
+	for _, sample := range series.Samples {
+		result.Points = append(result.Points, structs.MetricPoint{
+			Timestamp: sample.Timestamp,
+			Value:     sample.Value,
+		})
+	}
+	return result
+}
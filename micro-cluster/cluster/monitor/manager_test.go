@@ -0,0 +1,165 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-cluster/user/rbac"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/test/mockmodels/mockclustermanagement"
+	"github.com/pingcap/tiunimanager/test/mockutilprometheus"
+	"github.com/pingcap/tiunimanager/util/api/prometheus"
+)
+
+func TestManager_QueryClusterMetrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+	models.SetClusterReaderWriter(clusterRW)
+	mockGetMeta(clusterRW, true)
+	service := mockutilprometheus.NewMockQueryService(ctrl)
+	prometheus.PrometheusService = service
+
+	t.Run("named metric", func(t *testing.T) {
+		service.EXPECT().QueryRange(gomock.Any(), "127.0.0.1:9090", `sum(rate(process_cpu_seconds_total[60s])) by (job)`,
+			time.Unix(1638331200, 0), time.Unix(1638334800, 0), 15*time.Second).
+			Return([]prometheus.Series{
+				{
+					Labels:  map[string]string{"job": "tidb", "cluster": "metrics-cluster"},
+					Samples: []prometheus.Sample{{Timestamp: 1638331200, Value: 0.5}},
+				},
+			}, nil)
+
+		resp, err := mockManager.QueryClusterMetrics(context.TODO(), cluster.QueryClusterMetricsReq{
+			ClusterID: "metrics-cluster",
+			Name:      string(constants.ClusterMetricCPUByComponent),
+			StartTime: 1638331200,
+			EndTime:   1638334800,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "cores", resp.Unit)
+		assert.Equal(t, int64(15), resp.Step)
+		assert.Equal(t, 1, len(resp.Series))
+		assert.Equal(t, map[string]string{"component": "tidb"}, resp.Series[0].Labels)
+		assert.Equal(t, []structs.MetricPoint{{Timestamp: 1638331200, Value: 0.5}}, resp.Series[0].Points)
+	})
+	t.Run("raw query", func(t *testing.T) {
+		service.EXPECT().QueryRange(gomock.Any(), "127.0.0.1:9090", "up", time.Unix(1638248400, 0), time.Unix(1638334800, 0), 360*time.Second).
+			Return([]prometheus.Series{
+				{Labels: map[string]string{"job": "tikv", "instance": "127.0.0.1:20180"}, Samples: []prometheus.Sample{}},
+			}, nil)
+
+		resp, err := mockManager.QueryClusterMetrics(context.TODO(), cluster.QueryClusterMetricsReq{
+			ClusterID: "metrics-cluster",
+			Query:     "up",
+			StartTime: 1638248400,
+			EndTime:   1638334800,
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, resp.Unit)
+		assert.Equal(t, int64(360), resp.Step)
+		assert.Equal(t, "127.0.0.1:20180", resp.Series[0].Labels["instance"])
+	})
+	t.Run("query failed", func(t *testing.T) {
+		service.EXPECT().QueryRange(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, errors.Error(errors.TIUNIMANAGER_METRICS_QUERY_INVALID))
+
+		_, err := mockManager.QueryClusterMetrics(context.TODO(), cluster.QueryClusterMetricsReq{
+			ClusterID: "metrics-cluster",
+			Query:     "sum(",
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestManager_QueryClusterMetricsInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+	models.SetClusterReaderWriter(clusterRW)
+	mockGetMeta(clusterRW, false)
+
+	for _, req := range []cluster.QueryClusterMetricsReq{
+		{ClusterID: "metrics-cluster"},
+		{ClusterID: "metrics-cluster", Name: "qps", Query: "up"},
+		{ClusterID: "metrics-cluster", Name: "unknown"},
+		{ClusterID: "metrics-cluster", Name: "qps", StartTime: 1638334800, EndTime: 1638331200},
+		{ClusterID: "metrics-cluster", Name: "qps", Step: -1},
+		{ClusterID: "metrics-cluster", Name: "qps", StartTime: 1638331200, EndTime: 1638334800 + 86400, Step: 1},
+	} {
+		_, err := mockManager.QueryClusterMetrics(context.TODO(), req)
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_METRICS_QUERY_INVALID, err.(errors.EMError).GetCode())
+	}
+
+	_, err := mockManager.QueryClusterMetrics(context.TODO(), cluster.QueryClusterMetricsReq{ClusterID: "metrics-cluster", Name: "qps"})
+	assert.Error(t, err)
+	assert.Equal(t, errors.TIUNIMANAGER_PROMETHEUS_UNAVAILABLE, err.(errors.EMError).GetCode())
+}
+
+// denyRBACService denies all permissions
+type denyRBACService struct {
+	rbac.RBACService
+}
+
+func (d *denyRBACService) CheckPermissionForInstances(ctx context.Context, request message.CheckPermissionForInstancesReq) (message.CheckPermissionForInstancesResp, error) {
+	return message.CheckPermissionForInstancesResp{Result: false}, nil
+}
+
+func TestManager_QueryClusterMetricsDenied(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+	models.SetClusterReaderWriter(clusterRW)
+	mockGetMeta(clusterRW, true)
+	service := mockutilprometheus.NewMockQueryService(ctrl)
+	prometheus.PrometheusService = service
+	rbac.MockRBACService(&denyRBACService{})
+	defer rbac.MockRBACService(nil)
+
+	ginCtx := &gin.Context{}
+	ginCtx.Set(framework.TiUniManager_X_USER_ID_KEY, "guest")
+	_, err := mockManager.QueryClusterMetrics(framework.NewMicroCtxFromGinCtx(ginCtx), cluster.QueryClusterMetricsReq{ClusterID: "metrics-cluster", Name: "qps"})
+	assert.Error(t, err)
+	assert.Equal(t, errors.TIUNIMANAGER_RBAC_PERMISSION_DENIED, err.(errors.EMError).GetCode())
+}
+
+func TestManager_getTimeRange(t *testing.T) {
+	start, end, step, err := getTimeRange(0, 0, 0, 1638334800)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1638334800-constants.MetricsDefaultRange), start)
+	assert.Equal(t, int64(1638334800), end)
+	assert.Equal(t, int64(constants.MetricsMinStep), step)
+
+	_, _, step, err = getTimeRange(1638331200, 1638334800, 60, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(60), step)
+}
@@ -49,6 +49,7 @@ import (
 
 	"github.com/pingcap/tiunimanager/message"
 
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/maintainwindow"
 	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
 
 	"github.com/pingcap/tiunimanager/common/constants"
@@ -74,21 +75,47 @@ func NewManager() *Manager {
 			workflowManager.RegisterWorkFlow(context.TODO(), constants.FlowModifyParameters, &modifyParametersDefine)
 
 			manager = &Manager{}
+			manager.registerPendingOperations()
 		}
 	})
 	return manager
 }
 
+// registerPendingOperations
+// @Description: register executors of parameter modifications which may be queued until the maintain window opens
+// @Receiver m
+func (m *Manager) registerPendingOperations() {
+	maintainwindow.RegisterOperation(constants.PendingOperationUpdateParameters, func(ctx context.Context, request string) (string, error) {
+		req := cluster.UpdateClusterParametersReq{}
+		if err := json.Unmarshal([]byte(request), &req); err != nil {
+			return "", errors.WrapError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, "unmarshal update cluster parameters request failed", err)
+		}
+		// the window has been checked by the scheduler
+		req.IgnoreMaintainWindow = true
+		resp, err := m.UpdateClusterParameters(ctx, req, true)
+		return resp.WorkFlowID, err
+	})
+	maintainwindow.RegisterOperation(constants.PendingOperationApplyParameterGroup, func(ctx context.Context, request string) (string, error) {
+		req := message.ApplyParameterGroupReq{}
+		if err := json.Unmarshal([]byte(request), &req); err != nil {
+			return "", errors.WrapError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, "unmarshal apply parameter group request failed", err)
+		}
+		req.IgnoreMaintainWindow = true
+		resp, err := m.ApplyParameterGroup(ctx, req, true)
+		return resp.WorkFlowID, err
+	})
+}
+
 var modifyParametersDefine = workflow.WorkFlowDefine{
 	FlowName: constants.FlowModifyParameters,
 	TaskNodes: map[string]*workflow.NodeDefine{
-		"start":          {"validationParameter", "validationDone", "fail", workflow.SyncFuncNode, validationParameter},
-		"validationDone": {"modifyParameter", "modifyDone", "failParameter", workflow.PollingNode, modifyParameters},
-		"modifyDone":     {"refreshParameter", "refreshDone", "failParameter", workflow.PollingNode, refreshParameter},
-		"refreshDone":    {"persistParameter", "persistDone", "fail", workflow.SyncFuncNode, persistParameter},
-		"persistDone":    {"end", "", "", workflow.SyncFuncNode, defaultEnd},
-		"fail":           {"fail", "", "", workflow.SyncFuncNode, defaultEnd},
-		"failParameter":  {"failParameter", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(parameterFail, defaultEnd)},
+		"start":          {"validationParameter", "validationDone", "fail", workflow.SyncFuncNode, validationParameter, nil},
+		"validationDone": {"modifyParameter", "modifyDone", "failParameter", workflow.PollingNode, modifyParameters, nil},
+		"modifyDone":     {"refreshParameter", "refreshDone", "failParameter", workflow.PollingNode, refreshParameter, nil},
+		"refreshDone":    {"persistParameter", "persistDone", "fail", workflow.SyncFuncNode, persistParameter, nil},
+		"persistDone":    {"end", "", "", workflow.SyncFuncNode, defaultEnd, nil},
+		"fail":           {"fail", "", "", workflow.SyncFuncNode, defaultEnd, nil},
+		"failParameter":  {"failParameter", "", "", workflow.SyncFuncNode, workflow.CompositeExecutor(parameterFail, defaultEnd), nil},
 	},
 }
 
@@ -174,6 +201,15 @@ func (m *Manager) UpdateClusterParameters(ctx context.Context, req cluster.Updat
 		framework.LogWithContext(ctx).Errorf("load cluser%s meta from db error: %s", req.ClusterID, err.Error())
 		return
 	}
+	// modifying parameters with reboot of a running cluster is restricted by the maintain window
+	if maintenanceStatusChange && req.Reboot {
+		pendingOperationID, admitErr := maintainwindow.Admit(ctx, clusterMeta, constants.PendingOperationUpdateParameters, req.MaintainWindowOption, req)
+		if admitErr != nil || pendingOperationID != "" {
+			resp.ClusterID = req.ClusterID
+			resp.PendingOperationID = pendingOperationID
+			return resp, admitErr
+		}
+	}
 
 	params := make([]*ModifyClusterParameterInfo, 0)
 
@@ -239,6 +275,15 @@ func (m *Manager) ApplyParameterGroup(ctx context.Context, req message.ApplyPara
 		framework.LogWithContext(ctx).Errorf("load cluser%s meta from db error: %s", req.ClusterID, err.Error())
 		return
 	}
+	// modifying parameters with reboot of a running cluster is restricted by the maintain window
+	if maintenanceStatusChange && req.Reboot {
+		pendingOperationID, admitErr := maintainwindow.Admit(ctx, clusterMeta, constants.PendingOperationApplyParameterGroup, req.MaintainWindowOption, req)
+		if admitErr != nil || pendingOperationID != "" {
+			resp.ClusterID = req.ClusterID
+			resp.PendingOperationID = pendingOperationID
+			return resp, admitErr
+		}
+	}
 
 	// Detail parameter group by id
 	_, pgm, err := models.GetParameterGroupReaderWriter().GetParameterGroup(ctx, req.ParamGroupId, "", "")
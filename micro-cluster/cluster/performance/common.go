@@ -0,0 +1,194 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package performance
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/models/cluster/performance"
+	"github.com/pingcap/tiunimanager/util/api/tidb/sql"
+)
+
+const snapshotJobSpec = "0 0 */1 * * *" // every hour
+
+// getTimeRange
+// @Description: end time is now and start time is TopSQLDefaultRange before end time by default
+// @Parameter startTime
+// @Parameter endTime
+// @Parameter now
+// @return start
+// @return end
+// @return err
+func getTimeRange(startTime, endTime, now int64) (start, end int64, err error) {
+	end = endTime
+	if end == 0 {
+		end = now
+	}
+	start = startTime
+	if start == 0 {
+		start = end - constants.TopSQLDefaultRange
+	}
+	if start < 0 || start >= end {
+		err = errors.NewErrorf(errors.TIUNIMANAGER_PERFORMANCE_QUERY_INVALID, "start time %d should be before end time %d", start, end)
+	}
+	return
+}
+
+// getLimit
+// @Description: limit is TopSQLDefaultLimit by default and should not exceed TopSQLMaxLimit
+func getLimit(limit int) (int, error) {
+	if limit == 0 {
+		return constants.TopSQLDefaultLimit, nil
+	}
+	if limit < 0 || limit > constants.TopSQLMaxLimit {
+		return 0, errors.NewErrorf(errors.TIUNIMANAGER_PERFORMANCE_QUERY_INVALID, "limit %d should be between 1 and %d", limit, constants.TopSQLMaxLimit)
+	}
+	return limit, nil
+}
+
+// getOrderBy
+// @Description: statements are ordered by latency by default
+func getOrderBy(orderBy string, supported ...constants.TopSQLOrderBy) (constants.TopSQLOrderBy, error) {
+	if len(orderBy) == 0 {
+		return constants.TopSQLOrderByLatency, nil
+	}
+	for _, s := range supported {
+		if constants.TopSQLOrderBy(orderBy) == s {
+			return s, nil
+		}
+	}
+	return "", errors.NewErrorf(errors.TIUNIMANAGER_PERFORMANCE_QUERY_INVALID, "order by %s is not supported", orderBy)
+}
+
+// getConnParam
+// @Description: connect a running TiDB of cluster with the parameter management user
+func getConnParam(ctx context.Context, clusterMeta *meta.ClusterMeta) (sql.DbConnParam, error) {
+	tidbServers := clusterMeta.GetClusterConnectAddresses()
+	if len(tidbServers) == 0 {
+		return sql.DbConnParam{}, errors.NewErrorf(errors.TIUNIMANAGER_CONNECT_TIDB_ERROR, "no running tidb in cluster %s", clusterMeta.Cluster.ID)
+	}
+	tidbServer := tidbServers[rand.Intn(len(tidbServers))]
+
+	tidbUserInfo, err := clusterMeta.GetDBUserNamePassword(ctx, constants.DBUserParameterManagement)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("get cluster %s user info from meta failed, %s", clusterMeta.Cluster.ID, err.Error())
+		return sql.DbConnParam{}, err
+	}
+	if tidbUserInfo == nil {
+		return sql.DbConnParam{}, errors.NewErrorf(errors.TIUNIMANAGER_USER_NOT_FOUND, "get user of cluster %s failed, empty user", clusterMeta.Cluster.ID)
+	}
+	return sql.DbConnParam{
+		Username: tidbUserInfo.Name,
+		Password: tidbUserInfo.Password.Val,
+		IP:       tidbServer.IP,
+		Port:     strconv.Itoa(tidbServer.Port),
+	}, nil
+}
+
+// toMilliseconds convert latency in nanoseconds to milliseconds
+func toMilliseconds(nanoseconds int64) float64 {
+	return float64(nanoseconds) / float64(time.Millisecond)
+}
+
+func average(sum int64, count int64) int64 {
+	if count == 0 {
+		return 0
+	}
+	return sum / count
+}
+
+func convertStatementInfo(statement sql.StatementStatistics) structs.StatementInfo {
+	return structs.StatementInfo{
+		Digest:           statement.Digest,
+		DigestText:       statement.DigestText,
+		SchemaName:       statement.SchemaName,
+		ExecCount:        statement.ExecCount,
+		SumLatency:       toMilliseconds(statement.SumLatency),
+		AvgLatency:       toMilliseconds(average(statement.SumLatency, statement.ExecCount)),
+		MaxLatency:       toMilliseconds(statement.MaxLatency),
+		SumProcessedKeys: statement.SumProcessedKeys,
+		SumTotalKeys:     statement.SumTotalKeys,
+		AvgTotalKeys:     average(statement.SumTotalKeys, statement.ExecCount),
+		FirstSeen:        statement.FirstSeen,
+		LastSeen:         statement.LastSeen,
+	}
+}
+
+func convertSnapshotStatementInfo(statement *performance.SnapshotStatement) structs.StatementInfo {
+	return convertStatementInfo(sql.StatementStatistics{
+		Digest:           statement.Digest,
+		DigestText:       statement.DigestText,
+		SchemaName:       statement.SchemaName,
+		ExecCount:        statement.ExecCount,
+		SumLatency:       statement.SumLatency,
+		MaxLatency:       statement.MaxLatency,
+		SumProcessedKeys: statement.SumProcessedKeys,
+		SumTotalKeys:     statement.SumTotalKeys,
+		FirstSeen:        statement.FirstSeen,
+		LastSeen:         statement.LastSeen,
+	})
+}
+
+func convertPlanInfo(plan sql.StatementPlan) structs.StatementPlanInfo {
+	return structs.StatementPlanInfo{
+		PlanDigest:   plan.PlanDigest,
+		Plan:         plan.Plan,
+		SampleText:   plan.SampleText,
+		ExecCount:    plan.ExecCount,
+		SumLatency:   toMilliseconds(plan.SumLatency),
+		AvgLatency:   toMilliseconds(average(plan.SumLatency, plan.ExecCount)),
+		MaxLatency:   toMilliseconds(plan.MaxLatency),
+		AvgTotalKeys: average(plan.SumTotalKeys, plan.ExecCount),
+		FirstSeen:    plan.FirstSeen,
+		LastSeen:     plan.LastSeen,
+	}
+}
+
+func convertSlowQueryInfo(query sql.SlowQuery) structs.SlowQueryInfo {
+	return structs.SlowQueryInfo{
+		Time:        query.Time,
+		Instance:    query.Instance,
+		Digest:      query.Digest,
+		Query:       query.Query,
+		DB:          query.DB,
+		User:        query.User,
+		QueryTime:   query.QueryTime * float64(time.Second/time.Millisecond),
+		ProcessKeys: query.ProcessKeys,
+		TotalKeys:   query.TotalKeys,
+		Success:     query.Success,
+	}
+}
+
+func convertSnapshotInfo(snapshot *performance.PerformanceSnapshot) structs.PerformanceSnapshotInfo {
+	return structs.PerformanceSnapshotInfo{
+		ID:             snapshot.ID,
+		ClusterID:      snapshot.ClusterID,
+		Name:           snapshot.Name,
+		Type:           snapshot.Type,
+		StartTime:      snapshot.StartTime,
+		EndTime:        snapshot.EndTime,
+		StatementCount: snapshot.StatementCount,
+		CreateTime:     snapshot.CreatedAt,
+	}
+}
@@ -0,0 +1,78 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package performance
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	"github.com/pingcap/tiunimanager/models/common"
+	"github.com/pingcap/tiunimanager/test/mockmodels/mockclustermanagement"
+)
+
+var mockManager = NewManager()
+
+func TestMain(m *testing.M) {
+	var testFilePath string
+	framework.InitBaseFrameworkForUt(framework.ClusterService,
+		func(d *framework.BaseFramework) error {
+			testFilePath = d.GetDataDir()
+			os.MkdirAll(testFilePath, 0755)
+			models.MockDB()
+			return models.Open(d)
+		},
+	)
+	code := m.Run()
+	os.RemoveAll(testFilePath)
+
+	os.Exit(code)
+}
+
+func mockGetMeta(clusterRW *mockclustermanagement.MockReaderWriter, tidbStatus constants.ClusterInstanceRunningStatus) {
+	clusterRW.EXPECT().GetMeta(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, clusterID string) (*management.Cluster, []*management.ClusterInstance, []*management.DBUser, error) {
+			return &management.Cluster{
+				Entity:  common.Entity{ID: clusterID, TenantId: "1", Status: string(constants.ClusterRunning)},
+				Name:    "testCluster",
+				Type:    "TiDB",
+				Version: "v5.2.2",
+			}, []*management.ClusterInstance{
+				{
+					Entity:    common.Entity{ID: clusterID + "-tidb", TenantId: "1", Status: string(tidbStatus)},
+					Type:      string(constants.ComponentIDTiDB),
+					Version:   "v5.2.2",
+					ClusterID: clusterID,
+					HostID:    "host1",
+					HostIP:    []string{"127.0.0.1"},
+					Ports:     []int32{4000, 10080},
+					DiskPath:  "/mnt/sda",
+				},
+			}, []*management.DBUser{
+				{
+					ClusterID: clusterID,
+					Name:      constants.DBUserName[constants.DBUserParameterManagement],
+					Password:  common.PasswordInExpired{Val: "123455678"},
+					RoleType:  string(constants.DBUserParameterManagement),
+				},
+			}, nil
+		}).AnyTimes()
+}
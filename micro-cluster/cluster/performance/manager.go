@@ -0,0 +1,369 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package performance
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/micro-cluster/user/rbac"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/performance"
+	"github.com/pingcap/tiunimanager/util/api/tidb/sql"
+)
+
+type Manager struct{}
+
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// QueryTopSQL
+// @Description: query top SQL of cluster in order of latency, executions or scanned keys from statements summary history
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (m *Manager) QueryTopSQL(ctx context.Context, req cluster.QueryTopSQLReq) (resp cluster.QueryTopSQLResp, err error) {
+	orderBy, err := getOrderBy(req.OrderBy, constants.TopSQLOrderByLatency, constants.TopSQLOrderByExecutions, constants.TopSQLOrderByScannedKeys)
+	if err != nil {
+		return
+	}
+	limit, err := getLimit(req.Limit)
+	if err != nil {
+		return
+	}
+	start, end, err := getTimeRange(req.StartTime, req.EndTime, time.Now().Unix())
+	if err != nil {
+		return
+	}
+	connParam, err := m.getClusterConnParam(ctx, req.ClusterID)
+	if err != nil {
+		return
+	}
+
+	statements, err := sql.PerformanceService.QueryTopStatements(ctx, sql.TopStatementsReq{
+		DbConnParameter: connParam,
+		StartTime:       time.Unix(start, 0),
+		EndTime:         time.Unix(end, 0),
+		OrderBy:         orderBy,
+		Limit:           limit,
+	})
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query top sql of cluster %s failed, err = %s", req.ClusterID, err.Error())
+		return
+	}
+	resp = cluster.QueryTopSQLResp{
+		ClusterID:  req.ClusterID,
+		OrderBy:    string(orderBy),
+		StartTime:  start,
+		EndTime:    end,
+		Statements: make([]structs.StatementInfo, 0, len(statements)),
+	}
+	for _, statement := range statements {
+		resp.Statements = append(resp.Statements, convertStatementInfo(statement))
+	}
+	return
+}
+
+// QueryStatementPlans
+// @Description: query execution plans of a statement digest in order of latency from statements summary history
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (m *Manager) QueryStatementPlans(ctx context.Context, req cluster.QueryStatementPlansReq) (resp cluster.QueryStatementPlansResp, err error) {
+	start, end, err := getTimeRange(req.StartTime, req.EndTime, time.Now().Unix())
+	if err != nil {
+		return
+	}
+	connParam, err := m.getClusterConnParam(ctx, req.ClusterID)
+	if err != nil {
+		return
+	}
+
+	plans, err := sql.PerformanceService.QueryStatementPlans(ctx, sql.StatementPlansReq{
+		DbConnParameter: connParam,
+		StartTime:       time.Unix(start, 0),
+		EndTime:         time.Unix(end, 0),
+		Digest:          req.Digest,
+	})
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query plans of statement %s in cluster %s failed, err = %s", req.Digest, req.ClusterID, err.Error())
+		return
+	}
+	resp = cluster.QueryStatementPlansResp{
+		ClusterID: req.ClusterID,
+		Digest:    req.Digest,
+		StartTime: start,
+		EndTime:   end,
+		Plans:     make([]structs.StatementPlanInfo, 0, len(plans)),
+	}
+	for _, plan := range plans {
+		resp.Plans = append(resp.Plans, convertPlanInfo(plan))
+	}
+	return
+}
+
+// QuerySlowQueries
+// @Description: query slow query log of cluster in order of query time or scanned keys
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (m *Manager) QuerySlowQueries(ctx context.Context, req cluster.QuerySlowQueriesReq) (resp cluster.QuerySlowQueriesResp, err error) {
+	orderBy, err := getOrderBy(req.OrderBy, constants.TopSQLOrderByLatency, constants.TopSQLOrderByScannedKeys)
+	if err != nil {
+		return
+	}
+	limit, err := getLimit(req.Limit)
+	if err != nil {
+		return
+	}
+	start, end, err := getTimeRange(req.StartTime, req.EndTime, time.Now().Unix())
+	if err != nil {
+		return
+	}
+	connParam, err := m.getClusterConnParam(ctx, req.ClusterID)
+	if err != nil {
+		return
+	}
+
+	queries, err := sql.PerformanceService.QuerySlowQueries(ctx, sql.SlowQueriesReq{
+		DbConnParameter: connParam,
+		StartTime:       time.Unix(start, 0),
+		EndTime:         time.Unix(end, 0),
+		Digest:          req.Digest,
+		OrderBy:         orderBy,
+		Limit:           limit,
+	})
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query slow queries of cluster %s failed, err = %s", req.ClusterID, err.Error())
+		return
+	}
+	resp = cluster.QuerySlowQueriesResp{
+		ClusterID:   req.ClusterID,
+		StartTime:   start,
+		EndTime:     end,
+		SlowQueries: make([]structs.SlowQueryInfo, 0, len(queries)),
+	}
+	for _, query := range queries {
+		resp.SlowQueries = append(resp.SlowQueries, convertSlowQueryInfo(query))
+	}
+	return
+}
+
+// CreatePerformanceSnapshot
+// @Description: capture top SQL of cluster within a time range as a manual snapshot
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (m *Manager) CreatePerformanceSnapshot(ctx context.Context, req cluster.CreatePerformanceSnapshotReq) (resp cluster.CreatePerformanceSnapshotResp, err error) {
+	start, end, err := getTimeRange(req.StartTime, req.EndTime, time.Now().Unix())
+	if err != nil {
+		return
+	}
+	clusterMeta, err := getClusterMeta(ctx, req.ClusterID, constants.RbacActionUpdate)
+	if err != nil {
+		return
+	}
+
+	snapshot, err := captureSnapshot(ctx, clusterMeta, constants.PerformanceSnapshotManual, req.Name, time.Unix(start, 0), time.Unix(end, 0))
+	if err != nil {
+		return
+	}
+	resp.Snapshot = convertSnapshotInfo(snapshot)
+	return
+}
+
+// QueryPerformanceSnapshots
+// @Description: query performance snapshots of cluster in order of end time desc
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return total
+// @return err
+func (m *Manager) QueryPerformanceSnapshots(ctx context.Context, req cluster.QueryPerformanceSnapshotsReq) (resp cluster.QueryPerformanceSnapshotsResp, total int, err error) {
+	if _, err = getClusterMeta(ctx, req.ClusterID, constants.RbacActionRead); err != nil {
+		return
+	}
+	snapshots, count, err := models.GetPerformanceReaderWriter().QuerySnapshots(ctx, req.ClusterID,
+		constants.PerformanceSnapshotType(req.Type), req.GetOffset(), req.PageSize)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query performance snapshots of cluster %s failed, err = %s", req.ClusterID, err.Error())
+		return
+	}
+	resp.Snapshots = make([]structs.PerformanceSnapshotInfo, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		resp.Snapshots = append(resp.Snapshots, convertSnapshotInfo(snapshot))
+	}
+	total = int(count)
+	return
+}
+
+// GetPerformanceSnapshot
+// @Description: get performance snapshot of cluster with its statements in order of latency desc
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (m *Manager) GetPerformanceSnapshot(ctx context.Context, req cluster.GetPerformanceSnapshotReq) (resp cluster.GetPerformanceSnapshotResp, err error) {
+	if _, err = getClusterMeta(ctx, req.ClusterID, constants.RbacActionRead); err != nil {
+		return
+	}
+	snapshot, statements, err := models.GetPerformanceReaderWriter().GetSnapshot(ctx, req.ClusterID, req.SnapshotID)
+	if err != nil {
+		return
+	}
+	resp.Snapshot = convertSnapshotInfo(snapshot)
+	resp.Statements = make([]structs.StatementInfo, 0, len(statements))
+	for _, statement := range statements {
+		resp.Statements = append(resp.Statements, convertSnapshotStatementInfo(statement))
+	}
+	return
+}
+
+// DeletePerformanceSnapshot
+// @Description: delete performance snapshot of cluster
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (m *Manager) DeletePerformanceSnapshot(ctx context.Context, req cluster.DeletePerformanceSnapshotReq) (resp cluster.DeletePerformanceSnapshotResp, err error) {
+	if _, err = getClusterMeta(ctx, req.ClusterID, constants.RbacActionUpdate); err != nil {
+		return
+	}
+	if err = models.GetPerformanceReaderWriter().DeleteSnapshot(ctx, req.ClusterID, req.SnapshotID); err != nil {
+		return
+	}
+	resp.SnapshotID = req.SnapshotID
+	return
+}
+
+// ComparePerformanceSnapshots
+// @Description: compare statements of target snapshot with base snapshot, for example snapshots before and after a change
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (m *Manager) ComparePerformanceSnapshots(ctx context.Context, req cluster.ComparePerformanceSnapshotsReq) (resp cluster.ComparePerformanceSnapshotsResp, err error) {
+	if _, err = getClusterMeta(ctx, req.ClusterID, constants.RbacActionRead); err != nil {
+		return
+	}
+	rw := models.GetPerformanceReaderWriter()
+	base, baseStatements, err := rw.GetSnapshot(ctx, req.ClusterID, req.BaseSnapshotID)
+	if err != nil {
+		return
+	}
+	target, targetStatements, err := rw.GetSnapshot(ctx, req.ClusterID, req.TargetSnapshotID)
+	if err != nil {
+		return
+	}
+	resp = cluster.ComparePerformanceSnapshotsResp{
+		Base:       convertSnapshotInfo(base),
+		Target:     convertSnapshotInfo(target),
+		Statements: compareStatements(baseStatements, targetStatements),
+	}
+	return
+}
+
+// getClusterConnParam
+// @Description: connection to cluster is opened only if current user has read permission of the cluster
+func (m *Manager) getClusterConnParam(ctx context.Context, clusterID string) (sql.DbConnParam, error) {
+	clusterMeta, err := getClusterMeta(ctx, clusterID, constants.RbacActionRead)
+	if err != nil {
+		return sql.DbConnParam{}, err
+	}
+	return getConnParam(ctx, clusterMeta)
+}
+
+// getClusterMeta
+// @Description: load cluster meta and check permission of action of current user on the cluster
+func getClusterMeta(ctx context.Context, clusterID string, action constants.RbacAction) (*meta.ClusterMeta, error) {
+	clusterMeta, err := meta.Get(ctx, clusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("load cluster %s meta from db error: %s", clusterID, err.Error())
+		return nil, err
+	}
+	cluster := clusterMeta.Cluster
+	if err = rbac.CheckClusterPermission(ctx, cluster.ID, cluster.Tags, cluster.TenantId, action); err != nil {
+		return nil, err
+	}
+	return clusterMeta, nil
+}
+
+// compareStatements
+// @Description: match statements of snapshots by schema and digest, in order of absolute diff of total latency desc
+func compareStatements(baseStatements []*performance.SnapshotStatement, targetStatements []*performance.SnapshotStatement) []structs.StatementComparison {
+	comparisons := make([]structs.StatementComparison, 0)
+	index := make(map[string]int)
+	for _, statement := range baseStatements {
+		info := convertSnapshotStatementInfo(statement)
+		index[statement.SchemaName+"."+statement.Digest] = len(comparisons)
+		comparisons = append(comparisons, structs.StatementComparison{
+			Digest:     statement.Digest,
+			DigestText: statement.DigestText,
+			SchemaName: statement.SchemaName,
+			Base:       &info,
+		})
+	}
+	for _, statement := range targetStatements {
+		info := convertSnapshotStatementInfo(statement)
+		if i, ok := index[statement.SchemaName+"."+statement.Digest]; ok {
+			comparisons[i].Target = &info
+			continue
+		}
+		comparisons = append(comparisons, structs.StatementComparison{
+			Digest:     statement.Digest,
+			DigestText: statement.DigestText,
+			SchemaName: statement.SchemaName,
+			Target:     &info,
+		})
+	}
+
+	for i := range comparisons {
+		base, target := structs.StatementInfo{}, structs.StatementInfo{}
+		if comparisons[i].Base != nil {
+			base = *comparisons[i].Base
+		}
+		if comparisons[i].Target != nil {
+			target = *comparisons[i].Target
+		}
+		comparisons[i].ExecCountDiff = target.ExecCount - base.ExecCount
+		comparisons[i].SumLatencyDiff = target.SumLatency - base.SumLatency
+		comparisons[i].AvgLatencyDiff = target.AvgLatency - base.AvgLatency
+	}
+	sort.SliceStable(comparisons, func(i, j int) bool {
+		return math.Abs(comparisons[i].SumLatencyDiff) > math.Abs(comparisons[j].SumLatencyDiff)
+	})
+	return comparisons
+}
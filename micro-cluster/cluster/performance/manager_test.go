@@ -0,0 +1,364 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package performance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message"
+	"github.com/pingcap/tiunimanager/message/cluster"
+	"github.com/pingcap/tiunimanager/micro-cluster/user/rbac"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/performance"
+	"github.com/pingcap/tiunimanager/models/common"
+	"github.com/pingcap/tiunimanager/test/mockmodels/mockclustermanagement"
+	"github.com/pingcap/tiunimanager/test/mockmodels/mockperformance"
+	mockutiltidbsqlperformance "github.com/pingcap/tiunimanager/test/mockutiltidbsql_performance"
+	"github.com/pingcap/tiunimanager/util/api/tidb/sql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_QueryTopSQL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+	models.SetClusterReaderWriter(clusterRW)
+	mockGetMeta(clusterRW, constants.ClusterInstanceRunning)
+	service := mockutiltidbsqlperformance.NewMockClusterPerformanceService(ctrl)
+	sql.PerformanceService = service
+
+	t.Run("normal", func(t *testing.T) {
+		service.EXPECT().QueryTopStatements(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, req sql.TopStatementsReq) ([]sql.StatementStatistics, error) {
+				assert.Equal(t, constants.DBUserName[constants.DBUserParameterManagement], req.DbConnParameter.Username)
+				assert.Equal(t, "127.0.0.1", req.DbConnParameter.IP)
+				assert.Equal(t, "4000", req.DbConnParameter.Port)
+				assert.Equal(t, constants.TopSQLOrderByExecutions, req.OrderBy)
+				assert.Equal(t, constants.TopSQLDefaultLimit, req.Limit)
+				assert.Equal(t, int64(1638331200), req.StartTime.Unix())
+				return []sql.StatementStatistics{
+					{Digest: "digest1", DigestText: "select * from t where id = ?", ExecCount: 4, SumLatency: 8000000, MaxLatency: 5000000, SumTotalKeys: 10},
+				}, nil
+			})
+
+		resp, err := mockManager.QueryTopSQL(context.TODO(), cluster.QueryTopSQLReq{
+			ClusterID: "performanceCluster",
+			OrderBy:   string(constants.TopSQLOrderByExecutions),
+			StartTime: 1638331200,
+			EndTime:   1638333000,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1638333000), resp.EndTime)
+		assert.Equal(t, 1, len(resp.Statements))
+		assert.Equal(t, float64(8), resp.Statements[0].SumLatency)
+		assert.Equal(t, float64(2), resp.Statements[0].AvgLatency)
+		assert.Equal(t, float64(5), resp.Statements[0].MaxLatency)
+		assert.Equal(t, int64(2), resp.Statements[0].AvgTotalKeys)
+	})
+	t.Run("query failed", func(t *testing.T) {
+		service.EXPECT().QueryTopStatements(gomock.Any(), gomock.Any()).
+			Return(nil, errors.Error(errors.TIUNIMANAGER_PERFORMANCE_QUERY_FAILED))
+		_, err := mockManager.QueryTopSQL(context.TODO(), cluster.QueryTopSQLReq{ClusterID: "performanceCluster"})
+		assert.Error(t, err)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for _, req := range []cluster.QueryTopSQLReq{
+			{ClusterID: "performanceCluster", OrderBy: "unknown"},
+			{ClusterID: "performanceCluster", Limit: constants.TopSQLMaxLimit + 1},
+			{ClusterID: "performanceCluster", StartTime: 1638333000, EndTime: 1638331200},
+		} {
+			_, err := mockManager.QueryTopSQL(context.TODO(), req)
+			assert.Error(t, err)
+			assert.Equal(t, errors.TIUNIMANAGER_PERFORMANCE_QUERY_INVALID, err.(errors.EMError).GetCode())
+		}
+	})
+}
+
+func TestManager_QueryTopSQL_NoTiDB(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+	models.SetClusterReaderWriter(clusterRW)
+	mockGetMeta(clusterRW, constants.ClusterInstanceStopped)
+
+	_, err := mockManager.QueryTopSQL(context.TODO(), cluster.QueryTopSQLReq{ClusterID: "performanceCluster"})
+	assert.Error(t, err)
+	assert.Equal(t, errors.TIUNIMANAGER_CONNECT_TIDB_ERROR, err.(errors.EMError).GetCode())
+}
+
+func TestManager_QueryStatementPlans(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+	models.SetClusterReaderWriter(clusterRW)
+	mockGetMeta(clusterRW, constants.ClusterInstanceRunning)
+	service := mockutiltidbsqlperformance.NewMockClusterPerformanceService(ctrl)
+	sql.PerformanceService = service
+
+	service.EXPECT().QueryStatementPlans(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, req sql.StatementPlansReq) ([]sql.StatementPlan, error) {
+			assert.Equal(t, "digest1", req.Digest)
+			assert.Equal(t, int64(constants.TopSQLDefaultRange), req.EndTime.Unix()-req.StartTime.Unix())
+			return []sql.StatementPlan{
+				{PlanDigest: "plan1", Plan: "Point_Get_1", ExecCount: 10, SumLatency: 10000000, SumTotalKeys: 10},
+			}, nil
+		})
+
+	resp, err := mockManager.QueryStatementPlans(context.TODO(), cluster.QueryStatementPlansReq{
+		ClusterID: "performanceCluster",
+		Digest:    "digest1",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "digest1", resp.Digest)
+	assert.Equal(t, 1, len(resp.Plans))
+	assert.Equal(t, float64(1), resp.Plans[0].AvgLatency)
+	assert.Equal(t, int64(1), resp.Plans[0].AvgTotalKeys)
+}
+
+func TestManager_QuerySlowQueries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+	models.SetClusterReaderWriter(clusterRW)
+	mockGetMeta(clusterRW, constants.ClusterInstanceRunning)
+	service := mockutiltidbsqlperformance.NewMockClusterPerformanceService(ctrl)
+	sql.PerformanceService = service
+
+	service.EXPECT().QuerySlowQueries(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, req sql.SlowQueriesReq) ([]sql.SlowQuery, error) {
+			assert.Equal(t, constants.TopSQLOrderByScannedKeys, req.OrderBy)
+			assert.Equal(t, "digest1", req.Digest)
+			assert.Equal(t, 5, req.Limit)
+			return []sql.SlowQuery{
+				{Digest: "digest1", Query: "select * from t where id = 1", QueryTime: 1.5, TotalKeys: 100, Success: true},
+			}, nil
+		})
+
+	resp, err := mockManager.QuerySlowQueries(context.TODO(), cluster.QuerySlowQueriesReq{
+		ClusterID: "performanceCluster",
+		Digest:    "digest1",
+		OrderBy:   string(constants.TopSQLOrderByScannedKeys),
+		Limit:     5,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(resp.SlowQueries))
+	assert.Equal(t, float64(1500), resp.SlowQueries[0].QueryTime)
+
+	_, err = mockManager.QuerySlowQueries(context.TODO(), cluster.QuerySlowQueriesReq{
+		ClusterID: "performanceCluster",
+		OrderBy:   string(constants.TopSQLOrderByExecutions),
+	})
+	assert.Error(t, err)
+}
+
+func TestManager_PerformanceSnapshot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+	models.SetClusterReaderWriter(clusterRW)
+	mockGetMeta(clusterRW, constants.ClusterInstanceRunning)
+	service := mockutiltidbsqlperformance.NewMockClusterPerformanceService(ctrl)
+	sql.PerformanceService = service
+	performanceRW := mockperformance.NewMockReaderWriter(ctrl)
+	models.SetPerformanceReaderWriter(performanceRW)
+
+	t.Run("create", func(t *testing.T) {
+		service.EXPECT().QueryTopStatements(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, req sql.TopStatementsReq) ([]sql.StatementStatistics, error) {
+				assert.Equal(t, constants.TopSQLOrderByLatency, req.OrderBy)
+				assert.Equal(t, constants.TopSQLMaxLimit, req.Limit)
+				return []sql.StatementStatistics{{Digest: "digest1", ExecCount: 1}}, nil
+			})
+		performanceRW.EXPECT().CreateSnapshot(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, snapshot *performance.PerformanceSnapshot, statements []*performance.SnapshotStatement) (*performance.PerformanceSnapshot, error) {
+				assert.Equal(t, "1", snapshot.TenantId)
+				assert.Equal(t, string(constants.PerformanceSnapshotManual), snapshot.Type)
+				assert.Equal(t, 1, len(statements))
+				snapshot.ID = "snapshot1"
+				snapshot.StatementCount = len(statements)
+				return snapshot, nil
+			})
+
+		resp, err := mockManager.CreatePerformanceSnapshot(context.TODO(), cluster.CreatePerformanceSnapshotReq{
+			ClusterID: "performanceCluster",
+			Name:      "before upgrade",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "snapshot1", resp.Snapshot.ID)
+		assert.Equal(t, "before upgrade", resp.Snapshot.Name)
+		assert.Equal(t, 1, resp.Snapshot.StatementCount)
+	})
+	t.Run("query", func(t *testing.T) {
+		performanceRW.EXPECT().QuerySnapshots(gomock.Any(), "performanceCluster", constants.PerformanceSnapshotPeriodic, 0, 10).
+			Return([]*performance.PerformanceSnapshot{{Entity: common.Entity{ID: "snapshot2"}, ClusterID: "performanceCluster"}}, int64(1), nil)
+
+		resp, total, err := mockManager.QueryPerformanceSnapshots(context.TODO(), cluster.QueryPerformanceSnapshotsReq{
+			ClusterID: "performanceCluster",
+			Type:      string(constants.PerformanceSnapshotPeriodic),
+			PageRequest: structs.PageRequest{
+				Page:     1,
+				PageSize: 10,
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.Equal(t, "snapshot2", resp.Snapshots[0].ID)
+	})
+	t.Run("get", func(t *testing.T) {
+		performanceRW.EXPECT().GetSnapshot(gomock.Any(), "performanceCluster", "snapshot1").
+			Return(&performance.PerformanceSnapshot{Entity: common.Entity{ID: "snapshot1"}}, []*performance.SnapshotStatement{{Digest: "digest1", ExecCount: 2, SumLatency: 4000000}}, nil)
+
+		resp, err := mockManager.GetPerformanceSnapshot(context.TODO(), cluster.GetPerformanceSnapshotReq{
+			ClusterID:  "performanceCluster",
+			SnapshotID: "snapshot1",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "snapshot1", resp.Snapshot.ID)
+		assert.Equal(t, float64(2), resp.Statements[0].AvgLatency)
+	})
+	t.Run("delete", func(t *testing.T) {
+		performanceRW.EXPECT().DeleteSnapshot(gomock.Any(), "performanceCluster", "snapshot1").Return(nil)
+		resp, err := mockManager.DeletePerformanceSnapshot(context.TODO(), cluster.DeletePerformanceSnapshotReq{
+			ClusterID:  "performanceCluster",
+			SnapshotID: "snapshot1",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "snapshot1", resp.SnapshotID)
+	})
+	t.Run("compare", func(t *testing.T) {
+		performanceRW.EXPECT().GetSnapshot(gomock.Any(), "performanceCluster", "base").
+			Return(&performance.PerformanceSnapshot{Entity: common.Entity{ID: "base"}}, []*performance.SnapshotStatement{
+				{Digest: "digest1", SchemaName: "test", ExecCount: 10, SumLatency: 10000000},
+				{Digest: "digest2", SchemaName: "test", ExecCount: 10, SumLatency: 1000000},
+			}, nil)
+		performanceRW.EXPECT().GetSnapshot(gomock.Any(), "performanceCluster", "target").
+			Return(&performance.PerformanceSnapshot{Entity: common.Entity{ID: "target"}}, []*performance.SnapshotStatement{
+				{Digest: "digest1", SchemaName: "test", ExecCount: 10, SumLatency: 50000000},
+				{Digest: "digest3", SchemaName: "test", ExecCount: 1, SumLatency: 2000000},
+			}, nil)
+
+		resp, err := mockManager.ComparePerformanceSnapshots(context.TODO(), cluster.ComparePerformanceSnapshotsReq{
+			ClusterID:        "performanceCluster",
+			BaseSnapshotID:   "base",
+			TargetSnapshotID: "target",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "base", resp.Base.ID)
+		assert.Equal(t, "target", resp.Target.ID)
+		assert.Equal(t, 3, len(resp.Statements))
+
+		assert.Equal(t, "digest1", resp.Statements[0].Digest)
+		assert.Equal(t, float64(40), resp.Statements[0].SumLatencyDiff)
+		assert.Equal(t, float64(4), resp.Statements[0].AvgLatencyDiff)
+		assert.Equal(t, int64(0), resp.Statements[0].ExecCountDiff)
+
+		assert.Equal(t, "digest3", resp.Statements[1].Digest)
+		assert.Nil(t, resp.Statements[1].Base)
+		assert.Equal(t, int64(1), resp.Statements[1].ExecCountDiff)
+
+		assert.Equal(t, "digest2", resp.Statements[2].Digest)
+		assert.Nil(t, resp.Statements[2].Target)
+		assert.Equal(t, float64(-1), resp.Statements[2].SumLatencyDiff)
+	})
+	t.Run("not found", func(t *testing.T) {
+		performanceRW.EXPECT().GetSnapshot(gomock.Any(), "performanceCluster", "unknown").
+			Return(nil, nil, errors.Error(errors.TIUNIMANAGER_PERFORMANCE_SNAPSHOT_NOT_FOUND))
+		_, err := mockManager.ComparePerformanceSnapshots(context.TODO(), cluster.ComparePerformanceSnapshotsReq{
+			ClusterID:        "performanceCluster",
+			BaseSnapshotID:   "unknown",
+			TargetSnapshotID: "target",
+		})
+		assert.Error(t, err)
+	})
+}
+
+// denyRBACService denies all permissions
+type denyRBACService struct {
+	rbac.RBACService
+}
+
+func (d *denyRBACService) CheckPermissionForInstances(ctx context.Context, request message.CheckPermissionForInstancesReq) (message.CheckPermissionForInstancesResp, error) {
+	return message.CheckPermissionForInstancesResp{Result: false}, nil
+}
+
+func TestManager_PermissionDenied(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+	models.SetClusterReaderWriter(clusterRW)
+	mockGetMeta(clusterRW, constants.ClusterInstanceRunning)
+	// no connection is opened and no snapshot is read
+	sql.PerformanceService = mockutiltidbsqlperformance.NewMockClusterPerformanceService(ctrl)
+	models.SetPerformanceReaderWriter(mockperformance.NewMockReaderWriter(ctrl))
+	rbac.MockRBACService(&denyRBACService{})
+	defer rbac.MockRBACService(nil)
+
+	ginCtx := &gin.Context{}
+	ginCtx.Set(framework.TiUniManager_X_USER_ID_KEY, "guest")
+	ctx := framework.NewMicroCtxFromGinCtx(ginCtx)
+
+	_, err := mockManager.QueryTopSQL(ctx, cluster.QueryTopSQLReq{ClusterID: "performanceCluster"})
+	assert.Equal(t, errors.TIUNIMANAGER_RBAC_PERMISSION_DENIED, err.(errors.EMError).GetCode())
+	_, err = mockManager.QueryStatementPlans(ctx, cluster.QueryStatementPlansReq{ClusterID: "performanceCluster", Digest: "digest1"})
+	assert.Equal(t, errors.TIUNIMANAGER_RBAC_PERMISSION_DENIED, err.(errors.EMError).GetCode())
+	_, err = mockManager.QuerySlowQueries(ctx, cluster.QuerySlowQueriesReq{ClusterID: "performanceCluster"})
+	assert.Equal(t, errors.TIUNIMANAGER_RBAC_PERMISSION_DENIED, err.(errors.EMError).GetCode())
+	_, err = mockManager.CreatePerformanceSnapshot(ctx, cluster.CreatePerformanceSnapshotReq{ClusterID: "performanceCluster"})
+	assert.Equal(t, errors.TIUNIMANAGER_RBAC_PERMISSION_DENIED, err.(errors.EMError).GetCode())
+	_, _, err = mockManager.QueryPerformanceSnapshots(ctx, cluster.QueryPerformanceSnapshotsReq{ClusterID: "performanceCluster"})
+	assert.Equal(t, errors.TIUNIMANAGER_RBAC_PERMISSION_DENIED, err.(errors.EMError).GetCode())
+	_, err = mockManager.GetPerformanceSnapshot(ctx, cluster.GetPerformanceSnapshotReq{ClusterID: "performanceCluster", SnapshotID: "snapshot1"})
+	assert.Equal(t, errors.TIUNIMANAGER_RBAC_PERMISSION_DENIED, err.(errors.EMError).GetCode())
+	_, err = mockManager.DeletePerformanceSnapshot(ctx, cluster.DeletePerformanceSnapshotReq{ClusterID: "performanceCluster", SnapshotID: "snapshot1"})
+	assert.Equal(t, errors.TIUNIMANAGER_RBAC_PERMISSION_DENIED, err.(errors.EMError).GetCode())
+	_, err = mockManager.ComparePerformanceSnapshots(ctx, cluster.ComparePerformanceSnapshotsReq{ClusterID: "performanceCluster", BaseSnapshotID: "base", TargetSnapshotID: "target"})
+	assert.Equal(t, errors.TIUNIMANAGER_RBAC_PERMISSION_DENIED, err.(errors.EMError).GetCode())
+}
+
+func Test_getTimeRange(t *testing.T) {
+	start, end, err := getTimeRange(0, 0, 1638333000)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1638333000), end)
+	assert.Equal(t, int64(1638333000-constants.TopSQLDefaultRange), start)
+
+	_, _, err = getTimeRange(-1, 0, 1638333000)
+	assert.Error(t, err)
+
+	limit, err := getLimit(0)
+	assert.NoError(t, err)
+	assert.Equal(t, constants.TopSQLDefaultLimit, limit)
+	_, err = getLimit(-1)
+	assert.Error(t, err)
+}
+
+func Test_compareStatements_Empty(t *testing.T) {
+	comparisons := compareStatements([]*performance.SnapshotStatement{}, []*performance.SnapshotStatement{})
+	assert.Empty(t, comparisons)
+	assert.NotNil(t, comparisons)
+}
@@ -0,0 +1,172 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package performance
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/management/meta"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	"github.com/pingcap/tiunimanager/models/cluster/performance"
+	dbCommon "github.com/pingcap/tiunimanager/models/common"
+	"github.com/pingcap/tiunimanager/util/api/tidb/sql"
+	"github.com/robfig/cron"
+)
+
+var snapshotSchedulerOnce sync.Once
+
+type snapshotHandler struct{}
+
+// StartPerformanceSnapshotScheduler
+// @Description: start the scheduler which captures top SQL of running clusters every hour and removes expired periodic snapshots
+func StartPerformanceSnapshotScheduler() {
+	snapshotSchedulerOnce.Do(func() {
+		jobCron := cron.New()
+		if err := jobCron.AddJob(snapshotJobSpec, &snapshotHandler{}); err != nil {
+			framework.Log().Fatalf("add performance snapshot cron job failed, %s", err.Error())
+			return
+		}
+		go func() {
+			time.Sleep(5 * time.Second) //wait db client ready
+			jobCron.Start()
+		}()
+	})
+}
+
+func (h *snapshotHandler) Run() {
+	ctx := context.TODO()
+	end := time.Now().Truncate(time.Hour)
+	start := end.Add(-constants.PerformanceSnapshotInterval * time.Second)
+
+	results, _, err := models.GetClusterReaderWriter().QueryMetas(ctx, management.Filters{
+		StatusFilters: []constants.ClusterRunningStatus{constants.ClusterRunning},
+	}, structs.PageRequest{Page: 1, PageSize: math.MaxInt32})
+	if err != nil {
+		framework.Log().Errorf("query running clusters failed, %s", err.Error())
+		return
+	}
+	for _, result := range results {
+		clusterMeta, err := meta.Get(ctx, result.Cluster.ID)
+		if err != nil {
+			framework.Log().Errorf("load cluster %s meta from db error: %s", result.Cluster.ID, err.Error())
+			continue
+		}
+		if _, err = captureSnapshot(ctx, clusterMeta, constants.PerformanceSnapshotPeriodic, "", start, end); err != nil {
+			if isSnapshotExisted(err) {
+				// every replica runs the scheduler, the snapshot has been captured by another one
+				continue
+			}
+			framework.Log().Errorf("capture performance snapshot of cluster %s failed, %s", result.Cluster.ID, err.Error())
+		}
+	}
+
+	retention := getSnapshotRetentionDays(ctx)
+	deleted, err := models.GetPerformanceReaderWriter().DeleteSnapshotsBefore(ctx, constants.PerformanceSnapshotPeriodic,
+		end.AddDate(0, 0, -retention))
+	if err != nil {
+		framework.Log().Errorf("delete expired performance snapshots failed, %s", err.Error())
+		return
+	}
+	framework.Log().Infof("%d expired performance snapshots are deleted", deleted)
+}
+
+// captureSnapshot
+// @Description: save top SQL of cluster in order of latency within the time range as a snapshot
+// @Parameter ctx
+// @Parameter clusterMeta
+// @Parameter snapshotType
+// @Parameter name
+// @Parameter start
+// @Parameter end
+// @return *performance.PerformanceSnapshot
+// @return error
+func captureSnapshot(ctx context.Context, clusterMeta *meta.ClusterMeta, snapshotType constants.PerformanceSnapshotType, name string, start time.Time, end time.Time) (*performance.PerformanceSnapshot, error) {
+	connParam, err := getConnParam(ctx, clusterMeta)
+	if err != nil {
+		return nil, err
+	}
+	statements, err := sql.PerformanceService.QueryTopStatements(ctx, sql.TopStatementsReq{
+		DbConnParameter: connParam,
+		StartTime:       start,
+		EndTime:         end,
+		OrderBy:         constants.TopSQLOrderByLatency,
+		Limit:           constants.TopSQLMaxLimit,
+	})
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query top sql of cluster %s failed, err = %s", clusterMeta.Cluster.ID, err.Error())
+		return nil, err
+	}
+
+	rows := make([]*performance.SnapshotStatement, 0, len(statements))
+	for _, statement := range statements {
+		rows = append(rows, &performance.SnapshotStatement{
+			Digest:           statement.Digest,
+			DigestText:       statement.DigestText,
+			SchemaName:       statement.SchemaName,
+			ExecCount:        statement.ExecCount,
+			SumLatency:       statement.SumLatency,
+			MaxLatency:       statement.MaxLatency,
+			SumProcessedKeys: statement.SumProcessedKeys,
+			SumTotalKeys:     statement.SumTotalKeys,
+			FirstSeen:        statement.FirstSeen,
+			LastSeen:         statement.LastSeen,
+		})
+	}
+	snapshot, err := models.GetPerformanceReaderWriter().CreateSnapshot(ctx, &performance.PerformanceSnapshot{
+		Entity:    dbCommon.Entity{TenantId: clusterMeta.Cluster.TenantId},
+		ClusterID: clusterMeta.Cluster.ID,
+		Name:      name,
+		Type:      string(snapshotType),
+		StartTime: start,
+		EndTime:   end,
+	}, rows)
+	if err != nil {
+		if !isSnapshotExisted(err) {
+			framework.LogWithContext(ctx).Errorf("save performance snapshot of cluster %s failed, err = %s", clusterMeta.Cluster.ID, err.Error())
+		}
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+func isSnapshotExisted(err error) bool {
+	emErr, ok := err.(errors.EMError)
+	return ok && emErr.GetCode() == errors.TIUNIMANAGER_PERFORMANCE_SNAPSHOT_EXISTED
+}
+
+// getSnapshotRetentionDays days to keep periodic performance snapshots, it is configured by system config
+func getSnapshotRetentionDays(ctx context.Context) int {
+	config, err := models.GetConfigReaderWriter().GetConfig(ctx, constants.ConfigKeyPerformanceSnapshotRetentionDays)
+	if err != nil || config.ConfigValue == "" {
+		framework.LogWithContext(ctx).Warnf("get config %s failed, use default %d days", constants.ConfigKeyPerformanceSnapshotRetentionDays, constants.DefaultPerformanceSnapshotRetentionDays)
+		return constants.DefaultPerformanceSnapshotRetentionDays
+	}
+	days, err := strconv.Atoi(config.ConfigValue)
+	if err != nil || days <= 0 {
+		framework.LogWithContext(ctx).Warnf("invalid config %s = %s, use default %d days", constants.ConfigKeyPerformanceSnapshotRetentionDays, config.ConfigValue, constants.DefaultPerformanceSnapshotRetentionDays)
+		return constants.DefaultPerformanceSnapshotRetentionDays
+	}
+	return days
+}
@@ -0,0 +1,99 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package performance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	"github.com/pingcap/tiunimanager/models/cluster/performance"
+	"github.com/pingcap/tiunimanager/models/common"
+	"github.com/pingcap/tiunimanager/models/platform/config"
+	"github.com/pingcap/tiunimanager/test/mockmodels/mockclustermanagement"
+	"github.com/pingcap/tiunimanager/test/mockmodels/mockconfig"
+	"github.com/pingcap/tiunimanager/test/mockmodels/mockperformance"
+	mockutiltidbsqlperformance "github.com/pingcap/tiunimanager/test/mockutiltidbsql_performance"
+	"github.com/pingcap/tiunimanager/util/api/tidb/sql"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_getSnapshotRetentionDays(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	configRW := mockconfig.NewMockReaderWriter(ctrl)
+	models.SetConfigReaderWriter(configRW)
+
+	configRW.EXPECT().GetConfig(gomock.Any(), constants.ConfigKeyPerformanceSnapshotRetentionDays).Return(&config.SystemConfig{ConfigValue: "30"}, nil)
+	assert.Equal(t, 30, getSnapshotRetentionDays(context.TODO()))
+
+	configRW.EXPECT().GetConfig(gomock.Any(), constants.ConfigKeyPerformanceSnapshotRetentionDays).Return(&config.SystemConfig{ConfigValue: "0"}, nil)
+	assert.Equal(t, constants.DefaultPerformanceSnapshotRetentionDays, getSnapshotRetentionDays(context.TODO()))
+
+	configRW.EXPECT().GetConfig(gomock.Any(), constants.ConfigKeyPerformanceSnapshotRetentionDays).Return(nil, errors.Error(errors.TIUNIMANAGER_SYSTEM_MISSING_CONFIG))
+	assert.Equal(t, constants.DefaultPerformanceSnapshotRetentionDays, getSnapshotRetentionDays(context.TODO()))
+}
+
+func TestSnapshotHandler_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+	models.SetClusterReaderWriter(clusterRW)
+	mockGetMeta(clusterRW, constants.ClusterInstanceRunning)
+	clusterRW.EXPECT().QueryMetas(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]*management.Result{
+			{Cluster: &management.Cluster{Entity: common.Entity{ID: "periodicCluster"}}},
+		}, structs.Page{}, nil)
+	service := mockutiltidbsqlperformance.NewMockClusterPerformanceService(ctrl)
+	sql.PerformanceService = service
+	performanceRW := mockperformance.NewMockReaderWriter(ctrl)
+	models.SetPerformanceReaderWriter(performanceRW)
+	configRW := mockconfig.NewMockReaderWriter(ctrl)
+	models.SetConfigReaderWriter(configRW)
+
+	end := time.Now().Truncate(time.Hour)
+	service.EXPECT().QueryTopStatements(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, req sql.TopStatementsReq) ([]sql.StatementStatistics, error) {
+			assert.Equal(t, end.Unix(), req.EndTime.Unix())
+			assert.Equal(t, int64(constants.PerformanceSnapshotInterval), req.EndTime.Unix()-req.StartTime.Unix())
+			return []sql.StatementStatistics{}, nil
+		})
+	performanceRW.EXPECT().CreateSnapshot(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, snapshot *performance.PerformanceSnapshot, statements []*performance.SnapshotStatement) (*performance.PerformanceSnapshot, error) {
+			assert.Equal(t, "periodicCluster", snapshot.ClusterID)
+			assert.Equal(t, string(constants.PerformanceSnapshotPeriodic), snapshot.Type)
+			return snapshot, nil
+		})
+	configRW.EXPECT().GetConfig(gomock.Any(), constants.ConfigKeyPerformanceSnapshotRetentionDays).Return(&config.SystemConfig{ConfigValue: "3"}, nil)
+	performanceRW.EXPECT().DeleteSnapshotsBefore(gomock.Any(), constants.PerformanceSnapshotPeriodic, end.AddDate(0, 0, -3)).Return(int64(1), nil)
+
+	handler := &snapshotHandler{}
+	handler.Run()
+}
+
+func Test_isSnapshotExisted(t *testing.T) {
+	assert.True(t, isSnapshotExisted(errors.Error(errors.TIUNIMANAGER_PERFORMANCE_SNAPSHOT_EXISTED)))
+	assert.False(t, isSnapshotExisted(errors.Error(errors.TIUNIMANAGER_SQL_ERROR)))
+	assert.False(t, isSnapshotExisted(context.Canceled))
+}
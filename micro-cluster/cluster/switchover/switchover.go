@@ -75,56 +75,56 @@ func GetManager() *Manager {
 			FlowName: constants.FlowMasterSlaveSwitchoverNormal,
 			TaskNodes: map[string]*workflow.NodeDefine{
 				"start": {
-					"marshalSwitchoverMasterSlavesState", "checkHealthStatus", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepMarshalSwitchoverMasterSlavesState, wfStepFail)},
+					"marshalSwitchoverMasterSlavesState", "checkHealthStatus", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepMarshalSwitchoverMasterSlavesState, wfStepFail), nil},
 				"checkHealthStatus": {
-					"checkHealthStatus", "checkSyncChangeFeedTaskMaxLagTime", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepCheckOldSyncChangeFeedTaskHealth, wfStepFail)},
+					"checkHealthStatus", "checkSyncChangeFeedTaskMaxLagTime", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepCheckOldSyncChangeFeedTaskHealth, wfStepFail), nil},
 				"checkSyncChangeFeedTaskMaxLagTime": {
-					"checkSyncChangeFeedTaskMaxLagTime", "setOldMasterReadOnly", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepCheckSyncChangeFeedTaskMaxLagTime, wfStepFail)},
+					"checkSyncChangeFeedTaskMaxLagTime", "setOldMasterReadOnly", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepCheckSyncChangeFeedTaskMaxLagTime, wfStepFail), nil},
 				"setOldMasterReadOnly": {
-					"setOldMasterReadOnly", "waitOldMasterCDCsCaughtUp", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepSetOldMasterReadOnly, wfStepFail)},
+					"setOldMasterReadOnly", "waitOldMasterCDCsCaughtUp", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepSetOldMasterReadOnly, wfStepFail), nil},
 				"waitOldMasterCDCsCaughtUp": {
-					"waitOldMasterCDCsCaughtUp", "pauseOldSyncChangeFeedTask", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepWaitOldMasterCDCsCaughtUp, wfStepFail)},
+					"waitOldMasterCDCsCaughtUp", "pauseOldSyncChangeFeedTask", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepWaitOldMasterCDCsCaughtUp, wfStepFail), nil},
 				"pauseOldSyncChangeFeedTask": {
-					"pauseOldSyncChangeFeedTask", "createReverseSyncChangeFeedTask", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepPauseOldSyncChangeFeedTask, wfStepFail)},
+					"pauseOldSyncChangeFeedTask", "createReverseSyncChangeFeedTask", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepPauseOldSyncChangeFeedTask, wfStepFail), nil},
 				"createReverseSyncChangeFeedTask": {
-					"createReverseSyncChangeFeedTask", "checkNewSyncChangeFeedTaskHealth", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepCreateReverseSyncChangeFeedTask, wfStepFail)},
+					"createReverseSyncChangeFeedTask", "checkNewSyncChangeFeedTaskHealth", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepCreateReverseSyncChangeFeedTask, wfStepFail), nil},
 				"checkNewSyncChangeFeedTaskHealth": {
-					"checkNewSyncChangeFeedTaskHealth", "migrateAllDownStreamSyncChangeFeedTasksToNewMaster", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepCheckNewSyncChangeFeedTaskHealth, wfStepFail)},
+					"checkNewSyncChangeFeedTaskHealth", "migrateAllDownStreamSyncChangeFeedTasksToNewMaster", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepCheckNewSyncChangeFeedTaskHealth, wfStepFail), nil},
 				"migrateAllDownStreamSyncChangeFeedTasksToNewMaster": {
-					"migrateAllDownStreamSyncChangeFeedTasksToNewMaster", "setNewMasterReadWrite", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepMigrateAllDownStreamSyncChangeFeedTasksToNewMaster, wfStepFail)},
+					"migrateAllDownStreamSyncChangeFeedTasksToNewMaster", "setNewMasterReadWrite", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepMigrateAllDownStreamSyncChangeFeedTasksToNewMaster, wfStepFail), nil},
 				"setNewMasterReadWrite": {
-					"setNewMasterReadWrite", "swapMasterSlaveRelationInDB", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepSetNewMasterReadWrite, wfStepFail)},
+					"setNewMasterReadWrite", "swapMasterSlaveRelationInDB", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepSetNewMasterReadWrite, wfStepFail), nil},
 				"swapMasterSlaveRelationInDB": {
-					"swapMasterSlaveRelationInDB", "end", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepSwapMasterSlaveRelationInDB, wfStepFail)},
+					"swapMasterSlaveRelationInDB", "end", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepSwapMasterSlaveRelationInDB, wfStepFail), nil},
 				"end": {
-					"end", "", "", workflow.SyncFuncNode, wfStepFinish},
+					"end", "", "", workflow.SyncFuncNode, wfStepFinish, nil},
 				"fail": {
-					"fail", "", "", workflow.SyncFuncNode, wfStepNOP},
+					"fail", "", "", workflow.SyncFuncNode, wfStepNOP, nil},
 			},
 		})
 		flowManager.RegisterWorkFlow(context.TODO(), constants.FlowMasterSlaveSwitchoverForce, &workflow.WorkFlowDefine{
 			FlowName: constants.FlowMasterSlaveSwitchoverForce,
 			TaskNodes: map[string]*workflow.NodeDefine{
 				"start": {
-					"marshalSwitchoverMasterSlavesState", "setOldMasterReadOnly", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepMarshalSwitchoverMasterSlavesState, wfStepFail)},
+					"marshalSwitchoverMasterSlavesState", "setOldMasterReadOnly", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepMarshalSwitchoverMasterSlavesState, wfStepFail), nil},
 				"setOldMasterReadOnly": {
-					"setOldMasterReadOnly", "pauseOldSyncChangeFeedTask", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepSetOldMasterReadOnly, wfStepFail)},
+					"setOldMasterReadOnly", "pauseOldSyncChangeFeedTask", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepSetOldMasterReadOnly, wfStepFail), nil},
 				"pauseOldSyncChangeFeedTask": {
-					"pauseOldSyncChangeFeedTask", "createReverseSyncChangeFeedTask", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepPauseOldSyncChangeFeedTask, wfStepFail)},
+					"pauseOldSyncChangeFeedTask", "createReverseSyncChangeFeedTask", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepPauseOldSyncChangeFeedTask, wfStepFail), nil},
 				"createReverseSyncChangeFeedTask": {
-					"createReverseSyncChangeFeedTask", "checkNewSyncChangeFeedTaskHealth", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepCreateReverseSyncChangeFeedTask, wfStepFail)},
+					"createReverseSyncChangeFeedTask", "checkNewSyncChangeFeedTaskHealth", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepCreateReverseSyncChangeFeedTask, wfStepFail), nil},
 				"checkNewSyncChangeFeedTaskHealth": {
-					"checkNewSyncChangeFeedTaskHealth", "migrateAllDownStreamSyncChangeFeedTasksToNewMaster", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepCheckNewSyncChangeFeedTaskHealth, wfStepFail)},
+					"checkNewSyncChangeFeedTaskHealth", "migrateAllDownStreamSyncChangeFeedTasksToNewMaster", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepCheckNewSyncChangeFeedTaskHealth, wfStepFail), nil},
 				"migrateAllDownStreamSyncChangeFeedTasksToNewMaster": {
-					"migrateAllDownStreamSyncChangeFeedTasksToNewMaster", "setNewMasterReadWrite", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepMigrateAllDownStreamSyncChangeFeedTasksToNewMaster, wfStepFail)},
+					"migrateAllDownStreamSyncChangeFeedTasksToNewMaster", "setNewMasterReadWrite", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepMigrateAllDownStreamSyncChangeFeedTasksToNewMaster, wfStepFail), nil},
 				"setNewMasterReadWrite": {
-					"setNewMasterReadWrite", "swapMasterSlaveRelationInDB", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepSetNewMasterReadWrite, wfStepFail)},
+					"setNewMasterReadWrite", "swapMasterSlaveRelationInDB", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepSetNewMasterReadWrite, wfStepFail), nil},
 				"swapMasterSlaveRelationInDB": {
-					"swapMasterSlaveRelationInDB", "end", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepSwapMasterSlaveRelationInDB, wfStepFail)},
+					"swapMasterSlaveRelationInDB", "end", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepSwapMasterSlaveRelationInDB, wfStepFail), nil},
 				"end": {
-					"end", "", "", workflow.SyncFuncNode, wfStepFinish},
+					"end", "", "", workflow.SyncFuncNode, wfStepFinish, nil},
 				"fail": {
-					"fail", "", "", workflow.SyncFuncNode, wfStepNOP},
+					"fail", "", "", workflow.SyncFuncNode, wfStepNOP, nil},
 			},
 		})
 		flowManager.RegisterWorkFlow(context.TODO(), constants.FlowMasterSlaveSwitchoverForceWithMasterUnavailable,
@@ -132,17 +132,17 @@ func GetManager() *Manager {
 				FlowName: constants.FlowMasterSlaveSwitchoverForceWithMasterUnavailable,
 				TaskNodes: map[string]*workflow.NodeDefine{
 					"start": {
-						"marshalSwitchoverMasterSlavesState", "setOldMasterReadOnly", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepMarshalSwitchoverMasterSlavesState, wfStepFail)},
+						"marshalSwitchoverMasterSlavesState", "setOldMasterReadOnly", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepMarshalSwitchoverMasterSlavesState, wfStepFail), nil},
 					"setOldMasterReadOnly": {
-						"setOldMasterReadOnly", "setNewMasterReadWrite", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepSetOldMasterReadOnly, wfStepFail)},
+						"setOldMasterReadOnly", "setNewMasterReadWrite", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepSetOldMasterReadOnly, wfStepFail), nil},
 					"setNewMasterReadWrite": {
-						"setNewMasterReadWrite", "swapMasterSlaveRelationInDB", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepSetNewMasterReadWrite, wfStepFail)},
+						"setNewMasterReadWrite", "swapMasterSlaveRelationInDB", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepSetNewMasterReadWrite, wfStepFail), nil},
 					"swapMasterSlaveRelationInDB": {
-						"swapMasterSlaveRelationInDB", "end", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepSwapMasterSlaveRelationInDB, wfStepFail)},
+						"swapMasterSlaveRelationInDB", "end", "fail", workflow.SyncFuncNode, wfGenStepWithRollbackCB(wfStepSwapMasterSlaveRelationInDB, wfStepFail), nil},
 					"end": {
-						"end", "", "", workflow.SyncFuncNode, wfStepFinish},
+						"end", "", "", workflow.SyncFuncNode, wfStepFinish, nil},
 					"fail": {
-						"fail", "", "", workflow.SyncFuncNode, wfStepNOP},
+						"fail", "", "", workflow.SyncFuncNode, wfStepNOP, nil},
 				},
 			})
 		flowManager.RegisterWorkFlow(context.TODO(), constants.FlowMasterSlaveSwitchoverRollback,
@@ -150,11 +150,11 @@ func GetManager() *Manager {
 				FlowName: constants.FlowMasterSlaveSwitchoverRollback,
 				TaskNodes: map[string]*workflow.NodeDefine{
 					"start": {
-						"rollback", "end", "fail", workflow.SyncFuncNode, wfStepRollback},
+						"rollback", "end", "fail", workflow.SyncFuncNode, wfStepRollback, nil},
 					"end": {
-						"end", "", "", workflow.SyncFuncNode, wfStepNOP},
+						"end", "", "", workflow.SyncFuncNode, wfStepNOP, nil},
 					"fail": {
-						"fail", "", "", workflow.SyncFuncNode, wfStepNOP},
+						"fail", "", "", workflow.SyncFuncNode, wfStepNOP, nil},
 				},
 			})
 	})
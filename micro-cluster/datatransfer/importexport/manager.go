@@ -59,20 +59,20 @@ func NewImportExportManager() *ImportExportManager {
 	flowManager.RegisterWorkFlow(context.TODO(), constants.FlowExportData, &workflow.WorkFlowDefine{
 		FlowName: constants.FlowExportData,
 		TaskNodes: map[string]*workflow.NodeDefine{
-			"start":            {"exportDataFromCluster", "exportDataDone", "fail", workflow.PollingNode, exportDataFromCluster},
-			"exportDataDone":   {"updateDataExportRecord", "updateRecordDone", "fail", workflow.SyncFuncNode, updateDataExportRecord},
-			"updateRecordDone": {"end", "", "", workflow.SyncFuncNode, defaultEnd},
-			"fail":             {"fail", "", "", workflow.SyncFuncNode, exportDataFailed},
+			"start":            {"exportDataFromCluster", "exportDataDone", "fail", workflow.PollingNode, exportDataFromCluster, nil},
+			"exportDataDone":   {"updateDataExportRecord", "updateRecordDone", "fail", workflow.SyncFuncNode, updateDataExportRecord, nil},
+			"updateRecordDone": {"end", "", "", workflow.SyncFuncNode, defaultEnd, nil},
+			"fail":             {"fail", "", "", workflow.SyncFuncNode, exportDataFailed, nil},
 		},
 	})
 	flowManager.RegisterWorkFlow(context.TODO(), constants.FlowImportData, &workflow.WorkFlowDefine{
 		FlowName: constants.FlowImportData,
 		TaskNodes: map[string]*workflow.NodeDefine{
-			"start":            {"buildDataImportConfig", "buildConfigDone", "fail", workflow.SyncFuncNode, buildDataImportConfig},
-			"buildConfigDone":  {"importDataToCluster", "importDataDone", "fail", workflow.PollingNode, importDataToCluster},
-			"importDataDone":   {"updateDataImportRecord", "updateRecordDone", "fail", workflow.SyncFuncNode, updateDataImportRecord},
-			"updateRecordDone": {"end", "", "", workflow.SyncFuncNode, defaultEnd},
-			"fail":             {"fail", "", "", workflow.SyncFuncNode, importDataFailed},
+			"start":            {"buildDataImportConfig", "buildConfigDone", "fail", workflow.SyncFuncNode, buildDataImportConfig, nil},
+			"buildConfigDone":  {"importDataToCluster", "importDataDone", "fail", workflow.PollingNode, importDataToCluster, nil},
+			"importDataDone":   {"updateDataImportRecord", "updateRecordDone", "fail", workflow.SyncFuncNode, updateDataImportRecord, nil},
+			"updateRecordDone": {"end", "", "", workflow.SyncFuncNode, defaultEnd, nil},
+			"fail":             {"fail", "", "", workflow.SyncFuncNode, importDataFailed, nil},
 		},
 	})
 
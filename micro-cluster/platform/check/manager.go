@@ -64,10 +64,10 @@ func NewCheckManager() *CheckManager {
 var checkDefine = workflow.WorkFlowDefine{
 	FlowName: constants.FlowCheckPlatform,
 	TaskNodes: map[string]*workflow.NodeDefine{
-		"start":            {"checkTenants", "checkTenantsDone", "fail", workflow.SyncFuncNode, checkTenants},
-		"checkTenantsDone": {"checkHosts", "checkHostsDone", "fail", workflow.SyncFuncNode, checkHosts},
-		"checkHostsDone":   {"end", "", "", workflow.SyncFuncNode, endCheck},
-		"fail":             {"fail", "", "", workflow.SyncFuncNode, handleFail},
+		"start":            {"checkTenants", "checkTenantsDone", "fail", workflow.SyncFuncNode, checkTenants, nil},
+		"checkTenantsDone": {"checkHosts", "checkHostsDone", "fail", workflow.SyncFuncNode, checkHosts, nil},
+		"checkHostsDone":   {"end", "", "", workflow.SyncFuncNode, endCheck, nil},
+		"fail":             {"fail", "", "", workflow.SyncFuncNode, handleFail, nil},
 	},
 }
 
@@ -120,9 +120,9 @@ func (manager *CheckManager) Check(ctx context.Context, request message.CheckPla
 var checkClusterDefine = workflow.WorkFlowDefine{
 	FlowName: constants.FlowCheckCluster,
 	TaskNodes: map[string]*workflow.NodeDefine{
-		"start":            {"checkCluster", "checkClusterDone", "fail", workflow.SyncFuncNode, checkCluster},
-		"checkClusterDone": {"end", "", "", workflow.SyncFuncNode, endCheck},
-		"fail":             {"fail", "", "", workflow.SyncFuncNode, handleFail},
+		"start":            {"checkCluster", "checkClusterDone", "fail", workflow.SyncFuncNode, checkCluster, nil},
+		"checkClusterDone": {"end", "", "", workflow.SyncFuncNode, endCheck, nil},
+		"fail":             {"fail", "", "", workflow.SyncFuncNode, handleFail, nil},
 	},
 }
 
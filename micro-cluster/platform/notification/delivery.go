@@ -0,0 +1,168 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	"github.com/pingcap/tiunimanager/models/common"
+	"github.com/pingcap/tiunimanager/models/platform/notification"
+	"github.com/robfig/cron"
+)
+
+const (
+	deliveryJobSpec      = "*/30 * * * * *" // every 30 seconds
+	deliveryBatchSize    = 100
+	deliverySendingLimit = 10 * time.Minute
+)
+
+var deliverySchedulerOnce sync.Once
+
+// queuedNotification content of queued delivery
+type queuedNotification struct {
+	Subject string        `json:"subject"`
+	Text    string        `json:"text"`
+	Payload alertsPayload `json:"payload"`
+}
+
+// dispatchDeliveries send queued deliveries in background, replaced in unit test
+var dispatchDeliveries = func(ctx context.Context, deliveries []*notification.NotificationDelivery) {
+	framework.StartBackgroundTask(ctx, "send notifications", func(ctx context.Context) error {
+		sendDeliveries(ctx, deliveries)
+		return nil
+	})
+}
+
+type deliveryHandler struct{}
+
+// StartDeliveryScheduler
+// @Description: start the scheduler which sends pending deliveries left by failed dispatching or restarted service
+func StartDeliveryScheduler() {
+	deliverySchedulerOnce.Do(func() {
+		jobCron := cron.New()
+		if err := jobCron.AddJob(deliveryJobSpec, &deliveryHandler{}); err != nil {
+			framework.Log().Fatalf("add notification delivery cron job failed, %s", err.Error())
+			return
+		}
+		go func() {
+			time.Sleep(5 * time.Second) //wait db client ready
+			jobCron.Start()
+		}()
+	})
+}
+
+func (h *deliveryHandler) Run() {
+	ctx := context.TODO()
+	rw := models.GetNotificationReaderWriter()
+	count, err := rw.FailStaleDeliveries(ctx, time.Now().Add(-deliverySendingLimit), "sending notification is interrupted")
+	if err != nil {
+		framework.Log().Errorf("fail stale notification deliveries failed, %s", err.Error())
+	} else if count > 0 {
+		framework.Log().Warnf("%d notification deliveries are interrupted", count)
+	}
+
+	deliveries, err := rw.QueryPendingDeliveries(ctx, deliveryBatchSize)
+	if err != nil {
+		framework.Log().Errorf("query pending notification deliveries failed, %s", err.Error())
+		return
+	}
+	sendDeliveries(ctx, deliveries)
+}
+
+// queueDelivery
+// @Description: record a pending delivery of alerts notification, which is sent later
+// @Parameter ctx
+// @Parameter channel
+// @Parameter content
+// @Parameter clusterInfo
+// @return *notification.NotificationDelivery
+// @return error
+func queueDelivery(ctx context.Context, channel *notification.NotificationChannel, content Notification, clusterInfo *management.Cluster) (*notification.NotificationDelivery, error) {
+	payload, ok := content.Payload.(alertsPayload)
+	if !ok {
+		return nil, errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "only alerts notification can be queued")
+	}
+	bytes, err := json.Marshal(queuedNotification{
+		Subject: content.Subject,
+		Text:    content.Text,
+		Payload: payload,
+	})
+	if err != nil {
+		return nil, errors.WrapError(errors.TIUNIMANAGER_MARSHAL_ERROR, "", err)
+	}
+	delivery, err := models.GetNotificationReaderWriter().CreateDelivery(ctx, &notification.NotificationDelivery{
+		Entity:      common.Entity{TenantId: clusterInfo.TenantId, Status: string(constants.NotificationDeliveryPending)},
+		ChannelID:   channel.ID,
+		ChannelName: channel.Name,
+		ClusterID:   clusterInfo.ID,
+		Subject:     content.Subject,
+		Content:     string(bytes),
+	})
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("queue notification delivery of channel %s failed, err = %s", channel.Name, err.Error())
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// sendDeliveries
+// @Description: send pending deliveries, each delivery is claimed before sending so that it is sent only once
+// @Parameter ctx
+// @Parameter deliveries
+func sendDeliveries(ctx context.Context, deliveries []*notification.NotificationDelivery) {
+	rw := models.GetNotificationReaderWriter()
+	for _, delivery := range deliveries {
+		claimed, err := rw.ClaimDelivery(ctx, delivery.ID)
+		if err != nil {
+			framework.LogWithContext(ctx).Errorf("claim notification delivery %s failed, err = %s", delivery.ID, err.Error())
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		status, message := constants.NotificationDeliverySuccess, ""
+		if err = sendDelivery(ctx, delivery); err != nil {
+			status, message = constants.NotificationDeliveryFailed, err.Error()
+		}
+		if err = rw.UpdateDeliveryStatus(ctx, delivery.ID, status, message); err != nil {
+			framework.LogWithContext(ctx).Errorf("update notification delivery %s failed, err = %s", delivery.ID, err.Error())
+		}
+	}
+}
+
+func sendDelivery(ctx context.Context, delivery *notification.NotificationDelivery) error {
+	channel, err := models.GetNotificationReaderWriter().GetChannel(ctx, delivery.ChannelID)
+	if err != nil {
+		return err
+	}
+	content := queuedNotification{}
+	if err = json.Unmarshal([]byte(delivery.Content), &content); err != nil {
+		return errors.WrapError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, "", err)
+	}
+	return send(ctx, channel, Notification{
+		Subject: content.Subject,
+		Text:    content.Text,
+		Payload: content.Payload,
+	})
+}
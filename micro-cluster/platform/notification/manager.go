@@ -0,0 +1,645 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package notification
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	"github.com/pingcap/tiunimanager/models/common"
+	"github.com/pingcap/tiunimanager/models/platform/notification"
+)
+
+const defaultSMTPPort = 25
+
+// alertsPayload body posted to webhook channels
+type alertsPayload struct {
+	ClusterID   string                      `json:"clusterId"`
+	ClusterName string                      `json:"clusterName"`
+	TenantID    string                      `json:"tenantId"`
+	Status      string                      `json:"status"`
+	Alerts      []structs.NotificationAlert `json:"alerts"`
+}
+
+type Manager struct{}
+
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// CreateNotificationChannel
+// @Description: create a notification channel, SMTP password is saved encrypted
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (m *Manager) CreateNotificationChannel(ctx context.Context, req message.CreateNotificationChannelReq) (resp message.CreateNotificationChannelResp, err error) {
+	channelType, ok := constants.NotificationChannelTypeMap[req.Type]
+	if !ok {
+		err = errors.NewErrorf(errors.TIUNIMANAGER_NOTIFICATION_CHANNEL_INVALID, "channel type %s is invalid", req.Type)
+		return
+	}
+	if err = validateChannelConfig(channelType, &req.Config); err != nil {
+		return
+	}
+	channel := &notification.NotificationChannel{
+		Entity: common.Entity{TenantId: framework.GetTenantIDFromContext(ctx)},
+		Name:   req.Name,
+		Type:   string(channelType),
+	}
+	if err = setChannelConfig(channel, req.Config); err != nil {
+		return
+	}
+	channel, err = models.GetNotificationReaderWriter().CreateChannel(ctx, channel)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("create notification channel %s failed, err = %s", req.Name, err.Error())
+		return
+	}
+	resp.Channel, err = convertChannelInfo(channel)
+	return
+}
+
+// UpdateNotificationChannel
+// @Description: update name and config of notification channel, SMTP password is kept if it is empty in request
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (m *Manager) UpdateNotificationChannel(ctx context.Context, req message.UpdateNotificationChannelReq) (resp message.UpdateNotificationChannelResp, err error) {
+	rw := models.GetNotificationReaderWriter()
+	channel, err := rw.GetChannel(ctx, req.ChannelID)
+	if err != nil {
+		return
+	}
+	if len(req.Config.SMTPPassword) == 0 {
+		req.Config.SMTPPassword = structs.SensitiveText(channel.Password)
+	}
+	if err = validateChannelConfig(constants.NotificationChannelType(channel.Type), &req.Config); err != nil {
+		return
+	}
+	channel.Name = req.Name
+	if err = setChannelConfig(channel, req.Config); err != nil {
+		return
+	}
+	if err = rw.UpdateChannel(ctx, channel); err != nil {
+		framework.LogWithContext(ctx).Errorf("update notification channel %s failed, err = %s", req.ChannelID, err.Error())
+		return
+	}
+	resp.Channel, err = convertChannelInfo(channel)
+	return
+}
+
+// DeleteNotificationChannel
+// @Description: delete notification channel which is not used by any route
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (m *Manager) DeleteNotificationChannel(ctx context.Context, req message.DeleteNotificationChannelReq) (resp message.DeleteNotificationChannelResp, err error) {
+	rw := models.GetNotificationReaderWriter()
+	if _, err = rw.GetChannel(ctx, req.ChannelID); err != nil {
+		return
+	}
+	routes, _, err := rw.QueryRoutes(ctx, 0, -1)
+	if err != nil {
+		return
+	}
+	for _, route := range routes {
+		channelIDs, convertErr := getRouteChannelIDs(route)
+		if convertErr != nil {
+			err = convertErr
+			return
+		}
+		for _, id := range channelIDs {
+			if id == req.ChannelID {
+				err = errors.NewErrorf(errors.TIUNIMANAGER_NOTIFICATION_CHANNEL_IN_USE, "notification channel %s is used by route %s", req.ChannelID, route.Name)
+				return
+			}
+		}
+	}
+	if err = rw.DeleteChannel(ctx, req.ChannelID); err != nil {
+		framework.LogWithContext(ctx).Errorf("delete notification channel %s failed, err = %s", req.ChannelID, err.Error())
+		return
+	}
+	resp.ChannelID = req.ChannelID
+	return
+}
+
+// QueryNotificationChannels
+// @Description: query notification channels, SMTP passwords are not returned
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return total
+// @return err
+func (m *Manager) QueryNotificationChannels(ctx context.Context, req message.QueryNotificationChannelsReq) (resp message.QueryNotificationChannelsResp, total int, err error) {
+	if len(req.Type) > 0 {
+		if _, ok := constants.NotificationChannelTypeMap[req.Type]; !ok {
+			err = errors.NewErrorf(errors.TIUNIMANAGER_PARAMETER_INVALID, "channel type %s is invalid", req.Type)
+			return
+		}
+	}
+	channels, count, err := models.GetNotificationReaderWriter().QueryChannels(ctx, constants.NotificationChannelType(req.Type), req.GetOffset(), req.PageSize)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query notification channels failed, err = %s", err.Error())
+		return
+	}
+	resp.Channels = make([]structs.NotificationChannelInfo, 0, len(channels))
+	for _, channel := range channels {
+		info, convertErr := convertChannelInfo(channel)
+		if convertErr != nil {
+			err = convertErr
+			return
+		}
+		resp.Channels = append(resp.Channels, info)
+	}
+	return resp, int(count), nil
+}
+
+// TestNotificationChannel
+// @Description: send a test notification to channel, the result is recorded as a delivery
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (m *Manager) TestNotificationChannel(ctx context.Context, req message.TestNotificationChannelReq) (resp message.TestNotificationChannelResp, err error) {
+	channel, err := models.GetNotificationReaderWriter().GetChannel(ctx, req.ChannelID)
+	if err != nil {
+		return
+	}
+	content := Notification{
+		Subject: fmt.Sprintf("[TEST] notification channel %s", channel.Name),
+		Text:    "This is a test notification sent by TiUniManager.",
+		Payload: alertsPayload{Status: "test", Alerts: make([]structs.NotificationAlert, 0)},
+	}
+	delivery := &notification.NotificationDelivery{
+		Entity: common.Entity{TenantId: framework.GetTenantIDFromContext(ctx)},
+		Test:   true,
+	}
+	resp.Delivery, err = deliver(ctx, channel, content, delivery)
+	return
+}
+
+// CreateNotificationRoute
+// @Description: create a route from severities, cluster tags and tenant to channels
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (m *Manager) CreateNotificationRoute(ctx context.Context, req message.CreateNotificationRouteReq) (resp message.CreateNotificationRouteResp, err error) {
+	if err = validateRoute(ctx, req.Severities, req.TagSelector, req.ChannelIDs); err != nil {
+		return
+	}
+	route := &notification.NotificationRoute{
+		Entity: common.Entity{TenantId: framework.GetTenantIDFromContext(ctx)},
+		Name:   req.Name,
+	}
+	if err = setRouteCondition(route, req.Severities, req.TagSelector, req.TenantID, req.ChannelIDs); err != nil {
+		return
+	}
+	route, err = models.GetNotificationReaderWriter().CreateRoute(ctx, route)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("create notification route %s failed, err = %s", req.Name, err.Error())
+		return
+	}
+	resp.Route, err = convertRouteInfo(route)
+	return
+}
+
+// UpdateNotificationRoute
+// @Description: update conditions and channels of notification route
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (m *Manager) UpdateNotificationRoute(ctx context.Context, req message.UpdateNotificationRouteReq) (resp message.UpdateNotificationRouteResp, err error) {
+	rw := models.GetNotificationReaderWriter()
+	route, err := rw.GetRoute(ctx, req.RouteID)
+	if err != nil {
+		return
+	}
+	if err = validateRoute(ctx, req.Severities, req.TagSelector, req.ChannelIDs); err != nil {
+		return
+	}
+	route.Name = req.Name
+	if err = setRouteCondition(route, req.Severities, req.TagSelector, req.TenantID, req.ChannelIDs); err != nil {
+		return
+	}
+	if err = rw.UpdateRoute(ctx, route); err != nil {
+		framework.LogWithContext(ctx).Errorf("update notification route %s failed, err = %s", req.RouteID, err.Error())
+		return
+	}
+	resp.Route, err = convertRouteInfo(route)
+	return
+}
+
+// DeleteNotificationRoute
+// @Description: delete notification route
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (m *Manager) DeleteNotificationRoute(ctx context.Context, req message.DeleteNotificationRouteReq) (resp message.DeleteNotificationRouteResp, err error) {
+	if err = models.GetNotificationReaderWriter().DeleteRoute(ctx, req.RouteID); err != nil {
+		framework.LogWithContext(ctx).Errorf("delete notification route %s failed, err = %s", req.RouteID, err.Error())
+		return
+	}
+	resp.RouteID = req.RouteID
+	return
+}
+
+// QueryNotificationRoutes
+// @Description: query notification routes
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return total
+// @return err
+func (m *Manager) QueryNotificationRoutes(ctx context.Context, req message.QueryNotificationRoutesReq) (resp message.QueryNotificationRoutesResp, total int, err error) {
+	routes, count, err := models.GetNotificationReaderWriter().QueryRoutes(ctx, req.GetOffset(), req.PageSize)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query notification routes failed, err = %s", err.Error())
+		return
+	}
+	resp.Routes = make([]structs.NotificationRouteInfo, 0, len(routes))
+	for _, route := range routes {
+		info, convertErr := convertRouteInfo(route)
+		if convertErr != nil {
+			err = convertErr
+			return
+		}
+		resp.Routes = append(resp.Routes, info)
+	}
+	return resp, int(count), nil
+}
+
+// QueryNotificationDeliveries
+// @Description: query delivery logs of notifications
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return total
+// @return err
+func (m *Manager) QueryNotificationDeliveries(ctx context.Context, req message.QueryNotificationDeliveriesReq) (resp message.QueryNotificationDeliveriesResp, total int, err error) {
+	deliveries, count, err := models.GetNotificationReaderWriter().QueryDeliveries(ctx, req.ChannelID, req.ClusterID,
+		constants.NotificationDeliveryStatus(req.Status), req.GetOffset(), req.PageSize)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query notification deliveries failed, err = %s", err.Error())
+		return
+	}
+	resp.Deliveries = make([]structs.NotificationDeliveryInfo, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		resp.Deliveries = append(resp.Deliveries, convertDeliveryInfo(delivery))
+	}
+	return resp, int(count), nil
+}
+
+// ReceiveAlerts
+// @Description: receive alerts from alertmanager of cluster, and queue deliveries to channels of matched routes, which are sent asynchronously
+// @Receiver m
+// @Parameter ctx
+// @Parameter req
+// @return resp
+// @return err
+func (m *Manager) ReceiveAlerts(ctx context.Context, req message.ReceiveAlertsReq) (resp message.ReceiveAlertsResp, err error) {
+	if err = verifyReceiverToken(ctx, req.ClusterID, string(req.Token)); err != nil {
+		return
+	}
+	clusterInfo, err := models.GetClusterReaderWriter().Get(ctx, req.ClusterID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("get cluster %s failed, err = %s", req.ClusterID, err.Error())
+		return
+	}
+	rw := models.GetNotificationReaderWriter()
+	routes, _, err := rw.QueryRoutes(ctx, 0, -1)
+	if err != nil {
+		return
+	}
+
+	// alerts of each channel, channels are kept in order of matching
+	channelIDs := make([]string, 0)
+	channelAlerts := make(map[string][]structs.NotificationAlert)
+	for _, a := range req.Alerts {
+		matched := make(map[string]bool)
+		for _, route := range routes {
+			ok, matchErr := matchRoute(route, clusterInfo, a.Labels[constants.AlertLabelSeverity])
+			if matchErr != nil {
+				framework.LogWithContext(ctx).Warnf("match notification route %s failed, err = %s", route.Name, matchErr.Error())
+				continue
+			}
+			if !ok {
+				continue
+			}
+			ids, convertErr := getRouteChannelIDs(route)
+			if convertErr != nil {
+				framework.LogWithContext(ctx).Warnf("get channels of route %s failed, err = %s", route.Name, convertErr.Error())
+				continue
+			}
+			for _, id := range ids {
+				if matched[id] {
+					continue
+				}
+				matched[id] = true
+				if _, exist := channelAlerts[id]; !exist {
+					channelIDs = append(channelIDs, id)
+				}
+				channelAlerts[id] = append(channelAlerts[id], a)
+			}
+		}
+	}
+
+	resp.Deliveries = make([]structs.NotificationDeliveryInfo, 0, len(channelIDs))
+	queued := make([]*notification.NotificationDelivery, 0, len(channelIDs))
+	for _, id := range channelIDs {
+		channel, getErr := rw.GetChannel(ctx, id)
+		if getErr != nil {
+			framework.LogWithContext(ctx).Warnf("channel %s of notification route is unavailable, err = %s", id, getErr.Error())
+			continue
+		}
+		delivery, queueErr := queueDelivery(ctx, channel, buildAlertsNotification(clusterInfo, req.Status, channelAlerts[id]), clusterInfo)
+		if queueErr != nil {
+			err = queueErr
+			return
+		}
+		queued = append(queued, delivery)
+		resp.Deliveries = append(resp.Deliveries, convertDeliveryInfo(delivery))
+	}
+	if len(queued) > 0 {
+		dispatchDeliveries(ctx, queued)
+	}
+	return
+}
+
+// deliver
+// @Description: send notification to channel and record the delivery, failure of sending is recorded but not returned
+// @Parameter ctx
+// @Parameter channel
+// @Parameter content
+// @Parameter delivery
+// @return structs.NotificationDeliveryInfo
+// @return error
+func deliver(ctx context.Context, channel *notification.NotificationChannel, content Notification, delivery *notification.NotificationDelivery) (structs.NotificationDeliveryInfo, error) {
+	delivery.ChannelID = channel.ID
+	delivery.ChannelName = channel.Name
+	delivery.Subject = content.Subject
+	delivery.Status = string(constants.NotificationDeliverySuccess)
+
+	if err := send(ctx, channel, content); err != nil {
+		delivery.Status = string(constants.NotificationDeliveryFailed)
+		delivery.Message = err.Error()
+	}
+
+	delivery, err := models.GetNotificationReaderWriter().CreateDelivery(ctx, delivery)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("record notification delivery of channel %s failed, err = %s", channel.Name, err.Error())
+		return structs.NotificationDeliveryInfo{}, err
+	}
+	return convertDeliveryInfo(delivery), nil
+}
+
+// send
+// @Description: send notification to channel by the sender of channel type
+func send(ctx context.Context, channel *notification.NotificationChannel, content Notification) error {
+	config, err := getChannelConfig(channel)
+	if err == nil {
+		sender, ok := senders[constants.NotificationChannelType(channel.Type)]
+		if !ok {
+			err = errors.NewErrorf(errors.TIUNIMANAGER_NOTIFICATION_CHANNEL_INVALID, "channel type %s is invalid", channel.Type)
+		} else {
+			framework.LogWithContext(ctx).Infof("send notification to %s channel %s, id = %s", channel.Type, channel.Name, channel.ID)
+			err = sender.Send(ctx, config, content)
+		}
+	}
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("send notification to channel %s failed, err = %s", channel.Name, err.Error())
+	}
+	return err
+}
+
+// verifyReceiverToken
+// @Description: alerts are accepted only if the token equals to the receiver token of the cluster
+func verifyReceiverToken(ctx context.Context, clusterID string, token string) error {
+	receiver, err := models.GetAlertReaderWriter().GetReceiver(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+	if len(token) == 0 || subtle.ConstantTimeCompare([]byte(token), []byte(receiver.Token)) != 1 {
+		return errors.NewError(errors.TIUNIMANAGER_ALERT_RECEIVER_UNAUTHORIZED, "token of alert receiver is invalid")
+	}
+	return nil
+}
+
+// matchRoute
+// @Description: empty severities, tag selector or tenant of route matches all
+func matchRoute(route *notification.NotificationRoute, clusterInfo *management.Cluster, severity string) (bool, error) {
+	if len(route.Severities) > 0 {
+		matched := false
+		for _, s := range strings.Split(route.Severities, ",") {
+			if s == severity {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	if len(route.MatchTenantID) > 0 && route.MatchTenantID != clusterInfo.TenantId {
+		return false, nil
+	}
+	selector, err := structs.ParseTagSelector(route.TagSelector)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(clusterInfo.Tags), nil
+}
+
+func buildAlertsNotification(clusterInfo *management.Cluster, status string, alerts []structs.NotificationAlert) Notification {
+	lines := make([]string, 0, len(alerts))
+	for _, a := range alerts {
+		lines = append(lines, fmt.Sprintf("[%s] %s %s: %s", a.Labels[constants.AlertLabelSeverity],
+			a.Labels[constants.AlertLabelName], a.Status, a.Annotations["summary"]))
+	}
+	return Notification{
+		Subject: fmt.Sprintf("[%s:%d] alerts of cluster %s(%s)", strings.ToUpper(status), len(alerts), clusterInfo.Name, clusterInfo.ID),
+		Text:    strings.Join(lines, "\n"),
+		Payload: alertsPayload{
+			ClusterID:   clusterInfo.ID,
+			ClusterName: clusterInfo.Name,
+			TenantID:    clusterInfo.TenantId,
+			Status:      status,
+			Alerts:      alerts,
+		},
+	}
+}
+
+// validateChannelConfig
+// @Description: webhook and slack channels require url, email channel requires SMTP host, sender and receivers
+func validateChannelConfig(channelType constants.NotificationChannelType, config *structs.NotificationChannelConfig) error {
+	switch channelType {
+	case constants.NotificationChannelWebhook, constants.NotificationChannelSlack:
+		if !strings.HasPrefix(config.URL, "http://") && !strings.HasPrefix(config.URL, "https://") {
+			return errors.NewErrorf(errors.TIUNIMANAGER_NOTIFICATION_CHANNEL_INVALID, "url %s of %s channel is invalid", config.URL, channelType)
+		}
+	case constants.NotificationChannelEmail:
+		if len(config.SMTPHost) == 0 || len(config.From) == 0 || len(config.To) == 0 {
+			return errors.NewError(errors.TIUNIMANAGER_NOTIFICATION_CHANNEL_INVALID, "smtp host, from and to are required by email channel")
+		}
+		if config.SMTPPort == 0 {
+			config.SMTPPort = defaultSMTPPort
+		}
+	}
+	return nil
+}
+
+// validateRoute
+// @Description: severities should be valid, tag selector should be parsed and channels should exist
+func validateRoute(ctx context.Context, severities []string, tagSelector string, channelIDs []string) error {
+	for _, s := range severities {
+		if _, ok := constants.AlertSeverityMap[s]; !ok {
+			return errors.NewErrorf(errors.TIUNIMANAGER_NOTIFICATION_ROUTE_INVALID, "severity %s is invalid", s)
+		}
+	}
+	if _, err := structs.ParseTagSelector(tagSelector); err != nil {
+		return errors.WrapError(errors.TIUNIMANAGER_NOTIFICATION_ROUTE_INVALID, "tag selector is invalid", err)
+	}
+	if len(channelIDs) == 0 {
+		return errors.NewError(errors.TIUNIMANAGER_NOTIFICATION_ROUTE_INVALID, "channels are required")
+	}
+	for _, id := range channelIDs {
+		if _, err := models.GetNotificationReaderWriter().GetChannel(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setChannelConfig
+// @Description: password is saved in encrypted column, other config is saved as json
+func setChannelConfig(channel *notification.NotificationChannel, config structs.NotificationChannelConfig) error {
+	channel.Password = common.Password(config.SMTPPassword)
+	config.SMTPPassword = ""
+	bytes, err := json.Marshal(config)
+	if err != nil {
+		return errors.WrapError(errors.TIUNIMANAGER_MARSHAL_ERROR, "", err)
+	}
+	channel.Config = string(bytes)
+	return nil
+}
+
+func getChannelConfig(channel *notification.NotificationChannel) (structs.NotificationChannelConfig, error) {
+	config := structs.NotificationChannelConfig{}
+	if len(channel.Config) > 0 {
+		if err := json.Unmarshal([]byte(channel.Config), &config); err != nil {
+			return config, errors.WrapError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, "", err)
+		}
+	}
+	config.SMTPPassword = structs.SensitiveText(channel.Password)
+	return config, nil
+}
+
+func setRouteCondition(route *notification.NotificationRoute, severities []string, tagSelector string, tenantID string, channelIDs []string) error {
+	bytes, err := json.Marshal(channelIDs)
+	if err != nil {
+		return errors.WrapError(errors.TIUNIMANAGER_MARSHAL_ERROR, "", err)
+	}
+	route.Severities = strings.Join(severities, ",")
+	route.TagSelector = tagSelector
+	route.MatchTenantID = tenantID
+	route.ChannelIDs = string(bytes)
+	return nil
+}
+
+func getRouteChannelIDs(route *notification.NotificationRoute) ([]string, error) {
+	channelIDs := make([]string, 0)
+	if len(route.ChannelIDs) > 0 {
+		if err := json.Unmarshal([]byte(route.ChannelIDs), &channelIDs); err != nil {
+			return nil, errors.WrapError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, "", err)
+		}
+	}
+	return channelIDs, nil
+}
+
+func convertChannelInfo(channel *notification.NotificationChannel) (structs.NotificationChannelInfo, error) {
+	config, err := getChannelConfig(channel)
+	if err != nil {
+		return structs.NotificationChannelInfo{}, err
+	}
+	config.SMTPPassword = ""
+	return structs.NotificationChannelInfo{
+		ID:         channel.ID,
+		Name:       channel.Name,
+		Type:       channel.Type,
+		Config:     config,
+		CreateTime: channel.CreatedAt,
+		UpdateTime: channel.UpdatedAt,
+	}, nil
+}
+
+func convertRouteInfo(route *notification.NotificationRoute) (structs.NotificationRouteInfo, error) {
+	channelIDs, err := getRouteChannelIDs(route)
+	if err != nil {
+		return structs.NotificationRouteInfo{}, err
+	}
+	severities := make([]string, 0)
+	if len(route.Severities) > 0 {
+		severities = strings.Split(route.Severities, ",")
+	}
+	return structs.NotificationRouteInfo{
+		ID:          route.ID,
+		Name:        route.Name,
+		Severities:  severities,
+		TagSelector: route.TagSelector,
+		TenantID:    route.MatchTenantID,
+		ChannelIDs:  channelIDs,
+		CreateTime:  route.CreatedAt,
+		UpdateTime:  route.UpdatedAt,
+	}, nil
+}
+
+func convertDeliveryInfo(delivery *notification.NotificationDelivery) structs.NotificationDeliveryInfo {
+	return structs.NotificationDeliveryInfo{
+		ID:          delivery.ID,
+		ChannelID:   delivery.ChannelID,
+		ChannelName: delivery.ChannelName,
+		ClusterID:   delivery.ClusterID,
+		Subject:     delivery.Subject,
+		Test:        delivery.Test,
+		Status:      delivery.Status,
+		Message:     delivery.Message,
+		CreateTime:  delivery.CreatedAt,
+	}
+}
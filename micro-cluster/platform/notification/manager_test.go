@@ -0,0 +1,327 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package notification
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/message"
+	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/alert"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	"github.com/pingcap/tiunimanager/models/common"
+	"github.com/pingcap/tiunimanager/models/platform/notification"
+	"github.com/pingcap/tiunimanager/test/mockmodels/mockclustermanagement"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordSender records notifications instead of sending them
+type recordSender struct {
+	notifications []Notification
+	err           error
+}
+
+func (s *recordSender) Send(ctx context.Context, config structs.NotificationChannelConfig, notification Notification) error {
+	s.notifications = append(s.notifications, notification)
+	return s.err
+}
+
+func mockSenders() (webhook *recordSender, email *recordSender, recover func()) {
+	origin := senders
+	webhook = &recordSender{}
+	email = &recordSender{}
+	senders = map[constants.NotificationChannelType]Sender{
+		constants.NotificationChannelWebhook: webhook,
+		constants.NotificationChannelSlack:   webhook,
+		constants.NotificationChannelEmail:   email,
+	}
+	return webhook, email, func() {
+		senders = origin
+	}
+}
+
+func createChannel(t *testing.T, name string, channelType constants.NotificationChannelType) structs.NotificationChannelInfo {
+	req := message.CreateNotificationChannelReq{Name: name, Type: string(channelType)}
+	if channelType == constants.NotificationChannelEmail {
+		req.Config = structs.NotificationChannelConfig{SMTPHost: "127.0.0.1", From: "em@example.com", To: []string{"dba@example.com"}}
+	} else {
+		req.Config = structs.NotificationChannelConfig{URL: "http://127.0.0.1:8080/" + name}
+	}
+	resp, err := mockManager.CreateNotificationChannel(context.TODO(), req)
+	assert.NoError(t, err)
+	return resp.Channel
+}
+
+func TestManager_NotificationChannel(t *testing.T) {
+	t.Run("invalid", func(t *testing.T) {
+		_, err := mockManager.CreateNotificationChannel(context.TODO(), message.CreateNotificationChannelReq{
+			Name: "invalid-type", Type: "Phone",
+		})
+		assert.Equal(t, errors.TIUNIMANAGER_NOTIFICATION_CHANNEL_INVALID, err.(errors.EMError).GetCode())
+		_, err = mockManager.CreateNotificationChannel(context.TODO(), message.CreateNotificationChannelReq{
+			Name: "invalid-url", Type: string(constants.NotificationChannelWebhook), Config: structs.NotificationChannelConfig{URL: "127.0.0.1"},
+		})
+		assert.Equal(t, errors.TIUNIMANAGER_NOTIFICATION_CHANNEL_INVALID, err.(errors.EMError).GetCode())
+		_, err = mockManager.CreateNotificationChannel(context.TODO(), message.CreateNotificationChannelReq{
+			Name: "invalid-email", Type: string(constants.NotificationChannelEmail), Config: structs.NotificationChannelConfig{SMTPHost: "127.0.0.1"},
+		})
+		assert.Equal(t, errors.TIUNIMANAGER_NOTIFICATION_CHANNEL_INVALID, err.(errors.EMError).GetCode())
+	})
+
+	resp, err := mockManager.CreateNotificationChannel(context.TODO(), message.CreateNotificationChannelReq{
+		Name: "channel-email",
+		Type: string(constants.NotificationChannelEmail),
+		Config: structs.NotificationChannelConfig{
+			SMTPHost:     "127.0.0.1",
+			SMTPUser:     "em",
+			SMTPPassword: "secret",
+			From:         "em@example.com",
+			To:           []string{"dba@example.com"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, defaultSMTPPort, resp.Channel.Config.SMTPPort)
+	assert.Empty(t, resp.Channel.Config.SMTPPassword)
+
+	t.Run("update keeps password", func(t *testing.T) {
+		updated, err := mockManager.UpdateNotificationChannel(context.TODO(), message.UpdateNotificationChannelReq{
+			ChannelID: resp.Channel.ID,
+			Name:      "channel-email-updated",
+			Config: structs.NotificationChannelConfig{
+				SMTPHost: "127.0.0.1",
+				SMTPPort: 2525,
+				SMTPUser: "em",
+				From:     "em@example.com",
+				To:       []string{"dba@example.com", "ops@example.com"},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2525, updated.Channel.Config.SMTPPort)
+
+		channel, err := models.GetNotificationReaderWriter().GetChannel(context.TODO(), resp.Channel.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, common.Password("secret"), channel.Password)
+		assert.Equal(t, "channel-email-updated", channel.Name)
+	})
+	t.Run("query", func(t *testing.T) {
+		channels, total, err := mockManager.QueryNotificationChannels(context.TODO(), message.QueryNotificationChannelsReq{
+			Type:        string(constants.NotificationChannelEmail),
+			PageRequest: structs.PageRequest{Page: 1, PageSize: 100},
+		})
+		assert.NoError(t, err)
+		assert.True(t, total >= 1)
+		for _, channel := range channels.Channels {
+			assert.Equal(t, string(constants.NotificationChannelEmail), channel.Type)
+			assert.Empty(t, channel.Config.SMTPPassword)
+		}
+	})
+	t.Run("test", func(t *testing.T) {
+		_, email, recover := mockSenders()
+		defer recover()
+
+		testResp, err := mockManager.TestNotificationChannel(context.TODO(), message.TestNotificationChannelReq{ChannelID: resp.Channel.ID})
+		assert.NoError(t, err)
+		assert.True(t, testResp.Delivery.Test)
+		assert.Equal(t, string(constants.NotificationDeliverySuccess), testResp.Delivery.Status)
+		assert.Len(t, email.notifications, 1)
+
+		email.err = errors.NewError(errors.TIUNIMANAGER_NOTIFICATION_SEND_FAILED, "connection refused")
+		testResp, err = mockManager.TestNotificationChannel(context.TODO(), message.TestNotificationChannelReq{ChannelID: resp.Channel.ID})
+		assert.NoError(t, err)
+		assert.Equal(t, string(constants.NotificationDeliveryFailed), testResp.Delivery.Status)
+		assert.Contains(t, testResp.Delivery.Message, "connection refused")
+
+		deliveries, total, err := mockManager.QueryNotificationDeliveries(context.TODO(), message.QueryNotificationDeliveriesReq{
+			ChannelID:   resp.Channel.ID,
+			Status:      string(constants.NotificationDeliveryFailed),
+			PageRequest: structs.PageRequest{Page: 1, PageSize: 10},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.Equal(t, testResp.Delivery.ID, deliveries.Deliveries[0].ID)
+	})
+	t.Run("delete", func(t *testing.T) {
+		_, err := mockManager.DeleteNotificationChannel(context.TODO(), message.DeleteNotificationChannelReq{ChannelID: resp.Channel.ID})
+		assert.NoError(t, err)
+		_, err = mockManager.DeleteNotificationChannel(context.TODO(), message.DeleteNotificationChannelReq{ChannelID: resp.Channel.ID})
+		assert.Equal(t, errors.TIUNIMANAGER_NOTIFICATION_CHANNEL_NOT_FOUND, err.(errors.EMError).GetCode())
+	})
+}
+
+func TestManager_NotificationRoute(t *testing.T) {
+	channel := createChannel(t, "route-webhook", constants.NotificationChannelWebhook)
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := mockManager.CreateNotificationRoute(context.TODO(), message.CreateNotificationRouteReq{
+			Name: "invalid-severity", Severities: []string{"fatal"}, ChannelIDs: []string{channel.ID},
+		})
+		assert.Equal(t, errors.TIUNIMANAGER_NOTIFICATION_ROUTE_INVALID, err.(errors.EMError).GetCode())
+		_, err = mockManager.CreateNotificationRoute(context.TODO(), message.CreateNotificationRouteReq{
+			Name: "invalid-selector", TagSelector: "env in (prod", ChannelIDs: []string{channel.ID},
+		})
+		assert.Equal(t, errors.TIUNIMANAGER_NOTIFICATION_ROUTE_INVALID, err.(errors.EMError).GetCode())
+		_, err = mockManager.CreateNotificationRoute(context.TODO(), message.CreateNotificationRouteReq{
+			Name: "invalid-channel", ChannelIDs: []string{"unknown"},
+		})
+		assert.Equal(t, errors.TIUNIMANAGER_NOTIFICATION_CHANNEL_NOT_FOUND, err.(errors.EMError).GetCode())
+	})
+
+	resp, err := mockManager.CreateNotificationRoute(context.TODO(), message.CreateNotificationRouteReq{
+		Name:        "route-prod",
+		Severities:  []string{string(constants.AlertSeverityCritical)},
+		TagSelector: "env=prod",
+		ChannelIDs:  []string{channel.ID},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{string(constants.AlertSeverityCritical)}, resp.Route.Severities)
+
+	t.Run("channel in use", func(t *testing.T) {
+		_, err := mockManager.DeleteNotificationChannel(context.TODO(), message.DeleteNotificationChannelReq{ChannelID: channel.ID})
+		assert.Equal(t, errors.TIUNIMANAGER_NOTIFICATION_CHANNEL_IN_USE, err.(errors.EMError).GetCode())
+	})
+	t.Run("update", func(t *testing.T) {
+		updated, err := mockManager.UpdateNotificationRoute(context.TODO(), message.UpdateNotificationRouteReq{
+			RouteID:    resp.Route.ID,
+			Name:       "route-all",
+			TenantID:   "tenant01",
+			ChannelIDs: []string{channel.ID},
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, updated.Route.Severities)
+		assert.Equal(t, "tenant01", updated.Route.TenantID)
+	})
+	t.Run("delete", func(t *testing.T) {
+		_, err := mockManager.DeleteNotificationRoute(context.TODO(), message.DeleteNotificationRouteReq{RouteID: resp.Route.ID})
+		assert.NoError(t, err)
+		_, err = mockManager.DeleteNotificationChannel(context.TODO(), message.DeleteNotificationChannelReq{ChannelID: channel.ID})
+		assert.NoError(t, err)
+	})
+}
+
+func TestManager_ReceiveAlerts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	err := models.GetAlertReaderWriter().SaveReceiver(context.TODO(), &alert.AlertReceiver{ClusterID: "receive-cluster", Token: "token"})
+	assert.NoError(t, err)
+	clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+	clusterRW.EXPECT().Get(gomock.Any(), "receive-cluster").Return(&management.Cluster{
+		Entity: common.Entity{ID: "receive-cluster", TenantId: "tenant01"},
+		Name:   "prod01",
+		Tags:   []string{"env=prod", "team=dba"},
+	}, nil).AnyTimes()
+	models.SetClusterReaderWriter(clusterRW)
+
+	webhook, email, recover := mockSenders()
+	defer recover()
+	dispatched := make([]*notification.NotificationDelivery, 0)
+	origin := dispatchDeliveries
+	dispatchDeliveries = func(ctx context.Context, deliveries []*notification.NotificationDelivery) {
+		dispatched = append(dispatched, deliveries...)
+	}
+	defer func() {
+		dispatchDeliveries = origin
+	}()
+
+	webhookChannel := createChannel(t, "receive-webhook", constants.NotificationChannelWebhook)
+	emailChannel := createChannel(t, "receive-email", constants.NotificationChannelEmail)
+	_, err = mockManager.CreateNotificationRoute(context.TODO(), message.CreateNotificationRouteReq{
+		Name:        "receive-critical",
+		Severities:  []string{string(constants.AlertSeverityEmergency), string(constants.AlertSeverityCritical)},
+		TagSelector: "env=prod",
+		ChannelIDs:  []string{webhookChannel.ID, emailChannel.ID},
+	})
+	assert.NoError(t, err)
+	_, err = mockManager.CreateNotificationRoute(context.TODO(), message.CreateNotificationRouteReq{
+		Name:       "receive-tenant",
+		TenantID:   "tenant01",
+		ChannelIDs: []string{webhookChannel.ID},
+	})
+	assert.NoError(t, err)
+	_, err = mockManager.CreateNotificationRoute(context.TODO(), message.CreateNotificationRouteReq{
+		Name:        "receive-other",
+		TagSelector: "env=test",
+		ChannelIDs:  []string{emailChannel.ID},
+	})
+	assert.NoError(t, err)
+
+	req := message.ReceiveAlertsReq{
+		ClusterID: "receive-cluster",
+		Token:     "token",
+		Status:    "firing",
+		Alerts: []structs.NotificationAlert{
+			{Status: "firing", Labels: map[string]string{constants.AlertLabelName: "TiDB_server_is_down", constants.AlertLabelSeverity: "emergency"}},
+			{Status: "firing", Labels: map[string]string{constants.AlertLabelName: "TiKV_space_used_more_than_80%", constants.AlertLabelSeverity: "warning"}},
+		},
+	}
+
+	t.Run("unauthorized", func(t *testing.T) {
+		invalid := req
+		invalid.Token = "invalid"
+		_, err := mockManager.ReceiveAlerts(context.TODO(), invalid)
+		assert.Equal(t, errors.TIUNIMANAGER_ALERT_RECEIVER_UNAUTHORIZED, err.(errors.EMError).GetCode())
+	})
+	t.Run("token of other cluster", func(t *testing.T) {
+		err := models.GetAlertReaderWriter().SaveReceiver(context.TODO(), &alert.AlertReceiver{ClusterID: "other-cluster", Token: "other"})
+		assert.NoError(t, err)
+		invalid := req
+		invalid.Token = "other"
+		_, err = mockManager.ReceiveAlerts(context.TODO(), invalid)
+		assert.Equal(t, errors.TIUNIMANAGER_ALERT_RECEIVER_UNAUTHORIZED, err.(errors.EMError).GetCode())
+	})
+
+	// deliveries are queued and sent asynchronously
+	resp, err := mockManager.ReceiveAlerts(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Deliveries, 2)
+	for _, delivery := range resp.Deliveries {
+		assert.Equal(t, "receive-cluster", delivery.ClusterID)
+		assert.Equal(t, string(constants.NotificationDeliveryPending), delivery.Status)
+	}
+	assert.Empty(t, webhook.notifications)
+	assert.Len(t, dispatched, 2)
+
+	sendDeliveries(context.TODO(), dispatched)
+	// deliveries which have been sent are not sent again by scheduler
+	(&deliveryHandler{}).Run()
+	// webhook channel is matched by both routes, but alerts are sent once
+	assert.Len(t, webhook.notifications, 1)
+	assert.Len(t, webhook.notifications[0].Payload.(alertsPayload).Alerts, 2)
+	assert.Len(t, email.notifications, 1)
+	assert.Len(t, email.notifications[0].Payload.(alertsPayload).Alerts, 1)
+	assert.Contains(t, email.notifications[0].Text, "TiDB_server_is_down")
+
+	deliveries, total, err := mockManager.QueryNotificationDeliveries(context.TODO(), message.QueryNotificationDeliveriesReq{
+		ClusterID:   "receive-cluster",
+		PageRequest: structs.PageRequest{Page: 1, PageSize: 10},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Len(t, deliveries.Deliveries, 2)
+	for _, delivery := range deliveries.Deliveries {
+		assert.Equal(t, string(constants.NotificationDeliverySuccess), delivery.Status)
+	}
+}
+
+func TestManager_ReceiveAlertsNotConfigured(t *testing.T) {
+	_, err := mockManager.ReceiveAlerts(context.TODO(), message.ReceiveAlertsReq{ClusterID: "unconfigured-cluster", Token: "token"})
+	assert.Equal(t, errors.TIUNIMANAGER_ALERT_RECEIVER_NOT_CONFIGURED, err.(errors.EMError).GetCode())
+}
@@ -0,0 +1,131 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/library/framework"
+)
+
+// webhookTimeout timeout of posting to a webhook, a delivery is sent by one request within deliverySendingLimit
+const webhookTimeout = 30 * time.Second
+
+var webhookClient = &http.Client{Timeout: webhookTimeout}
+
+// headerReplacer remove line breaks from values of mail headers, otherwise extra headers could be injected
+var headerReplacer = strings.NewReplacer("\r", "", "\n", "")
+
+// Notification content sent to channels, Payload is posted by webhook channel as it is
+type Notification struct {
+	Subject string
+	Text    string
+	Payload interface{}
+}
+
+// Sender send notification to a type of channel
+type Sender interface {
+	Send(ctx context.Context, config structs.NotificationChannelConfig, notification Notification) error
+}
+
+// senders senders of each type of channel, replaced in unit test
+var senders = map[constants.NotificationChannelType]Sender{
+	constants.NotificationChannelWebhook: &webhookSender{},
+	constants.NotificationChannelSlack:   &slackSender{},
+	constants.NotificationChannelEmail:   &emailSender{},
+}
+
+type webhookSender struct{}
+
+func (s *webhookSender) Send(ctx context.Context, config structs.NotificationChannelConfig, notification Notification) error {
+	bytes, err := json.Marshal(notification.Payload)
+	if err != nil {
+		return errors.WrapError(errors.TIUNIMANAGER_MARSHAL_ERROR, "", err)
+	}
+	data := make(map[string]interface{})
+	if err = json.Unmarshal(bytes, &data); err != nil {
+		return errors.WrapError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, "", err)
+	}
+	return postJSON(ctx, config.URL, data)
+}
+
+type slackSender struct{}
+
+func (s *slackSender) Send(ctx context.Context, config structs.NotificationChannelConfig, notification Notification) error {
+	return postJSON(ctx, config.URL, map[string]interface{}{
+		"text": fmt.Sprintf("*%s*\n%s", notification.Subject, notification.Text),
+	})
+}
+
+// postJSON url of webhook is a credential, it is neither logged nor returned in error.
+// The request is canceled with ctx, or after webhookTimeout
+func postJSON(ctx context.Context, webhookURL string, data map[string]interface{}) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return errors.WrapError(errors.TIUNIMANAGER_MARSHAL_ERROR, "", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return errors.WrapError(errors.TIUNIMANAGER_NOTIFICATION_SEND_FAILED, "build notification request failed", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpResp, err := webhookClient.Do(httpReq)
+	if err != nil {
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return errors.WrapError(errors.TIUNIMANAGER_NOTIFICATION_SEND_FAILED, "post notification failed", err)
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		respBody, _ := ioutil.ReadAll(httpResp.Body)
+		return errors.NewErrorf(errors.TIUNIMANAGER_NOTIFICATION_SEND_FAILED, "response status %d, %s", httpResp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+type emailSender struct{}
+
+func (s *emailSender) Send(ctx context.Context, config structs.NotificationChannelConfig, notification Notification) error {
+	address := fmt.Sprintf("%s:%d", config.SMTPHost, config.SMTPPort)
+	var auth smtp.Auth
+	if len(config.SMTPUser) > 0 {
+		auth = smtp.PlainAuth("", config.SMTPUser, string(config.SMTPPassword), config.SMTPHost)
+	}
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		headerReplacer.Replace(config.From), headerReplacer.Replace(strings.Join(config.To, ",")), headerReplacer.Replace(notification.Subject), notification.Text)
+
+	framework.LogWithContext(ctx).Infof("send notification, smtp = %s, to = %v", address, config.To)
+	if err := smtp.SendMail(address, auth, config.From, config.To, []byte(msg)); err != nil {
+		return errors.WrapError(errors.TIUNIMANAGER_NOTIFICATION_SEND_FAILED, "send mail failed", err)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package notification
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/stretchr/testify/assert"
+)
+
+// startSMTPServer a local SMTP stand-in which accepts one mail and sends its data to the channel
+func startSMTPServer(t *testing.T) (host string, port int, mails chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	mails = make(chan string, 1)
+	go func() {
+		defer listener.Close()
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		write := func(line string) {
+			conn.Write([]byte(line + "\r\n"))
+		}
+		write("220 localhost ESMTP")
+		data := make([]string, 0)
+		inData := false
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			if inData {
+				if line == "." {
+					inData = false
+					mails <- strings.Join(data, "\n")
+					write("250 OK")
+				} else {
+					data = append(data, line)
+				}
+				continue
+			}
+			switch {
+			case strings.HasPrefix(line, "EHLO"), strings.HasPrefix(line, "HELO"):
+				write("250 localhost")
+			case strings.HasPrefix(line, "DATA"):
+				inData = true
+				write("354 start mail input")
+			case strings.HasPrefix(line, "QUIT"):
+				write("221 bye")
+				return
+			default:
+				write("250 OK")
+			}
+		}
+	}()
+	address := listener.Addr().(*net.TCPAddr)
+	return address.IP.String(), address.Port, mails
+}
+
+func TestWebhookSender_Send(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		payload := alertsPayload{}
+		assert.NoError(t, json.Unmarshal(body, &payload))
+		assert.Equal(t, "cluster01", payload.ClusterID)
+		assert.Equal(t, "firing", payload.Status)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	err := senders[constants.NotificationChannelWebhook].Send(context.TODO(), structs.NotificationChannelConfig{URL: server.URL}, Notification{
+		Subject: "subject",
+		Payload: alertsPayload{ClusterID: "cluster01", Status: "firing"},
+	})
+	assert.NoError(t, err)
+
+	t.Run("error status", func(t *testing.T) {
+		failed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer failed.Close()
+		err := senders[constants.NotificationChannelWebhook].Send(context.TODO(), structs.NotificationChannelConfig{URL: failed.URL}, Notification{Payload: alertsPayload{}})
+		assert.Error(t, err)
+	})
+	t.Run("canceled", func(t *testing.T) {
+		blocked := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+		defer blocked.Close()
+		ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+		defer cancel()
+		err := senders[constants.NotificationChannelWebhook].Send(ctx, structs.NotificationChannelConfig{URL: blocked.URL}, Notification{Payload: alertsPayload{}})
+		assert.Error(t, err)
+	})
+	t.Run("unreachable", func(t *testing.T) {
+		unreachable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		unreachable.Close()
+		err := senders[constants.NotificationChannelWebhook].Send(context.TODO(), structs.NotificationChannelConfig{URL: unreachable.URL + "/hooks/secret-token"}, Notification{Payload: alertsPayload{}})
+		assert.Error(t, err)
+		assert.NotContains(t, err.Error(), "secret-token")
+	})
+}
+
+func TestSlackSender_Send(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		message := make(map[string]string)
+		assert.NoError(t, json.Unmarshal(body, &message))
+		assert.Contains(t, message["text"], "subject")
+		assert.Contains(t, message["text"], "TiDB server is down")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	err := senders[constants.NotificationChannelSlack].Send(context.TODO(), structs.NotificationChannelConfig{URL: server.URL}, Notification{
+		Subject: "subject",
+		Text:    "TiDB server is down",
+	})
+	assert.NoError(t, err)
+}
+
+func TestEmailSender_Send(t *testing.T) {
+	host, port, mails := startSMTPServer(t)
+
+	err := senders[constants.NotificationChannelEmail].Send(context.TODO(), structs.NotificationChannelConfig{
+		SMTPHost: host,
+		SMTPPort: port,
+		From:     "tiunimanager@example.com",
+		To:       []string{"dba@example.com"},
+	}, Notification{
+		Subject: "subject",
+		Text:    "TiDB server is down",
+	})
+	assert.NoError(t, err)
+	mail := <-mails
+	assert.Contains(t, mail, "Subject: subject")
+	assert.Contains(t, mail, "TiDB server is down")
+
+	t.Run("header injection", func(t *testing.T) {
+		host, port, mails := startSMTPServer(t)
+		err := senders[constants.NotificationChannelEmail].Send(context.TODO(), structs.NotificationChannelConfig{
+			SMTPHost: host,
+			SMTPPort: port,
+			From:     "tiunimanager@example.com",
+			To:       []string{"dba@example.com"},
+		}, Notification{
+			Subject: "subject\r\nBcc: attacker@example.com",
+			Text:    "TiDB server is down",
+		})
+		assert.NoError(t, err)
+		mail := <-mails
+		assert.Contains(t, mail, "Subject: subjectBcc: attacker@example.com")
+		assert.NotContains(t, mail, "\nBcc:")
+	})
+	t.Run("unreachable", func(t *testing.T) {
+		err := senders[constants.NotificationChannelEmail].Send(context.TODO(), structs.NotificationChannelConfig{
+			SMTPHost: "127.0.0.1",
+			SMTPPort: 1,
+			From:     "tiunimanager@example.com",
+			To:       []string{"dba@example.com"},
+		}, Notification{Subject: "subject"})
+		assert.Error(t, err)
+	})
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/tiunimanager/common/errors"
 	"github.com/pingcap/tiunimanager/common/structs"
 	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message"
 	"github.com/pingcap/tiunimanager/micro-cluster/resourcemanager/management"
 	resource_structs "github.com/pingcap/tiunimanager/micro-cluster/resourcemanager/management/structs"
 	"github.com/pingcap/tiunimanager/micro-cluster/resourcemanager/resourcepool"
@@ -130,6 +131,41 @@ func (m *ResourceManager) UpdateHostStatus(ctx context.Context, hostIds []string
 	return
 }
 
+func (m *ResourceManager) SetInstanceEvacuator(evacuator resourcepool.InstanceEvacuator) {
+	m.resourcePool.SetInstanceEvacuator(evacuator)
+}
+
+func (m *ResourceManager) DrainHost(ctx context.Context, hostId string) (flowId string, err error) {
+	flowId, err = m.resourcePool.DrainHost(ctx, hostId)
+	if err != nil {
+		framework.LogWithContext(ctx).Warnf("drain host %s failed: %v", hostId, err)
+	} else {
+		framework.LogWithContext(ctx).Infof("drain host %s in workflow %s", hostId, flowId)
+	}
+
+	return
+}
+
+func (m *ResourceManager) QueryDrainHost(ctx context.Context, hostId string) (resp message.QueryDrainHostResp, err error) {
+	resp, err = m.resourcePool.QueryDrainHost(ctx, hostId)
+	if err != nil {
+		framework.LogWithContext(ctx).Warnf("query drain progress of host %s failed: %v", hostId, err)
+	}
+
+	return
+}
+
+func (m *ResourceManager) ReinstateHost(ctx context.Context, hostId string) (err error) {
+	err = m.resourcePool.ReinstateHost(ctx, hostId)
+	if err != nil {
+		framework.LogWithContext(ctx).Warnf("reinstate host %s failed: %v", hostId, err)
+	} else {
+		framework.LogWithContext(ctx).Infof("reinstate host %s succeed.", hostId)
+	}
+
+	return
+}
+
 func (m *ResourceManager) GetHierarchy(ctx context.Context, filter *structs.HostFilter, level int, depth int) (root *structs.HierarchyTreeNode, err error) {
 	root, err = m.resourcePool.GetHierarchy(ctx, filter, level, depth)
 	if err != nil {
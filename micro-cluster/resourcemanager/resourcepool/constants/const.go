@@ -21,6 +21,7 @@ const (
 	FlowTakeOverHosts          string = "TakeOverHosts"          // A flow to take over hosts
 	FlowDeleteHosts            string = "DeleteHosts"            // A normal flow to delete hosts
 	FlowDeleteHostsByForce     string = "DeleteHostsByForce"     // delete hosts by force - without uninstall filebeat .etc.
+	FlowDrainHost              string = "DrainHost"              // evacuate instances from a host and put it into maintenance
 )
 
 const (
@@ -28,6 +29,8 @@ const (
 	ContextHostIDArrayKey   string = "hostIDArray"
 	ContextWorkFlowIDKey    string = "resourceWorkFlowID"
 	ContextIgnoreWarnings   string = "checkHostIgnoreWarns"
+	ContextDrainProgressKey string = "drainProgress"
+	ContextEvictedStoresKey string = "evictedStores"
 )
 
 const (
@@ -42,4 +45,5 @@ const (
 	DefaultCopySshIDTimeOut     = 10
 	BackGroundTaskCheckInterval = 5 // unit: Second
 	BackGroundTaskMaxTries      = 1000
+	DrainHostRelocateTimeout    = 24 * 3600 // unit: Second, time to relocate all instances on the draining host
 )
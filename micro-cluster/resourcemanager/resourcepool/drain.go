@@ -0,0 +1,467 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package resourcepool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/deployment"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message"
+	rp_consts "github.com/pingcap/tiunimanager/micro-cluster/resourcemanager/resourcepool/constants"
+	"github.com/pingcap/tiunimanager/models"
+	workflowModel "github.com/pingcap/tiunimanager/models/workflow"
+	workflow "github.com/pingcap/tiunimanager/workflow2"
+)
+
+// InstanceEvacuator evacuates cluster instances from a host being drained.
+// It is implemented by cluster management, which depends on resource manager
+type InstanceEvacuator interface {
+	// EvictLeaders evict region leaders from TiKV instances of the cluster on the host through PD,
+	// return ids of the stores whose leaders are evicted, including those evicted before failing
+	EvictLeaders(ctx context.Context, clusterID string, hostID string) (storeIDs []int, err error)
+	// RemoveLeaderEviction remove the evict leader scheduler of the stores
+	RemoveLeaderEviction(ctx context.Context, clusterID string, storeIDs []int) error
+	// EvacuateInstance relocate the instance to another eligible host, return id of the migration workflow
+	EvacuateInstance(ctx context.Context, clusterID string, instanceID string) (workflowID string, err error)
+}
+
+// relocateOperationType type of the deployment operation tracking relocation of instances
+const relocateOperationType = "relocate"
+
+func (p *ResourcePool) SetInstanceEvacuator(evacuator InstanceEvacuator) {
+	p.instanceEvacuator = evacuator
+}
+
+func (p *ResourcePool) registerDrainHostWorkFlow(ctx context.Context, flowManager workflow.WorkFlowService) {
+	log := framework.LogWithContext(ctx)
+
+	log.Infoln("register drain host workflow")
+	flowManager.RegisterWorkFlow(ctx, rp_consts.FlowDrainHost, &workflow.WorkFlowDefine{
+		FlowName: rp_consts.FlowDrainHost,
+		TaskNodes: map[string]*workflow.NodeDefine{
+			"start":             {Name: "start", SuccessEvent: "evictLeaders", FailEvent: "fail", ReturnType: workflow.SyncFuncNode, Executor: collectDrainInstances},
+			"evictLeaders":      {Name: "evictLeaders", SuccessEvent: "relocateInstances", FailEvent: "fail", ReturnType: workflow.SyncFuncNode, Executor: evictLeaders},
+			"relocateInstances": {Name: "relocateInstances", SuccessEvent: "relocated", FailEvent: "fail", ReturnType: workflow.PollingNode, Executor: relocateInstances, Policy: &workflow.NodePolicy{Timeout: rp_consts.DrainHostRelocateTimeout * time.Second}},
+			"relocated":         {Name: "removeLeaderEviction", SuccessEvent: "succeed", FailEvent: "fail", ReturnType: workflow.SyncFuncNode, Executor: removeLeaderEviction},
+			"succeed":           {Name: "succeed", SuccessEvent: "", FailEvent: "", ReturnType: workflow.SyncFuncNode, Executor: setHostsMaintenance},
+			"fail":              {Name: "fail", SuccessEvent: "", FailEvent: "", ReturnType: workflow.SyncFuncNode, Executor: drainFail},
+		},
+	})
+}
+
+func (p *ResourcePool) getHost(ctx context.Context, hostId string) (*structs.HostInfo, error) {
+	hosts, count, err := p.QueryHosts(ctx, &structs.Location{}, &structs.HostFilter{HostID: hostId}, &structs.PageRequest{})
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, errors.NewErrorf(errors.TIUNIMANAGER_RESOURCE_HOST_NOT_FOUND, "host %s is not found", hostId)
+	}
+	return &hosts[0], nil
+}
+
+// DrainHost
+// @Description: stop allocating resources on the host, evacuate all cluster instances from it,
+// then put it into maintenance
+// @Receiver p
+// @Parameter ctx
+// @Parameter hostId
+// @return flowId
+// @return err
+func (p *ResourcePool) DrainHost(ctx context.Context, hostId string) (flowId string, err error) {
+	host, err := p.getHost(ctx, hostId)
+	if err != nil {
+		return "", err
+	}
+	if host.Status != string(constants.HostOnline) && host.Status != string(constants.HostOffline) {
+		return "", errors.NewErrorf(errors.TIUNIMANAGER_RESOURCE_HOST_STATUS_CONFLICT, "host %s is %s, only online or offline host can be drained", hostId, host.Status)
+	}
+	if p.instanceEvacuator == nil {
+		return "", errors.NewErrorf(errors.TIUNIMANAGER_RESOURCE_DRAIN_HOST_ERROR, "no instance evacuator to drain host %s", hostId)
+	}
+
+	flowManager := workflow.GetWorkFlowService()
+	flowId, err = flowManager.CreateWorkFlow(ctx, hostId, workflow.BizTypeHost, rp_consts.FlowDrainHost)
+	if err != nil {
+		errMsg := fmt.Sprintf("create %s workflow failed for host %s, %s", rp_consts.FlowDrainHost, hostId, err.Error())
+		framework.LogWithContext(ctx).Errorln(errMsg)
+		return "", errors.WrapError(errors.TIUNIMANAGER_WORKFLOW_CREATE_FAILED, errMsg, err)
+	}
+	flowManager.InitContext(ctx, flowId, rp_consts.ContextHostIDArrayKey, []string{hostId})
+	flowManager.InitContext(ctx, flowId, rp_consts.ContextHostInfoArrayKey, []structs.HostInfo{*host})
+
+	// allocator only picks online hosts, so nothing is allocated on the host since now
+	if err = p.UpdateHostStatus(ctx, []string{hostId}, string(constants.HostDraining)); err != nil {
+		return "", err
+	}
+	if err = flowManager.Start(ctx, flowId); err != nil {
+		errMsg := fmt.Sprintf("start %s workflow %s failed for host %s, %s", rp_consts.FlowDrainHost, flowId, hostId, err.Error())
+		framework.LogWithContext(ctx).Errorln(errMsg)
+		if recoverErr := p.UpdateHostStatus(ctx, []string{hostId}, host.Status); recoverErr != nil {
+			framework.LogWithContext(ctx).Errorf("recover status of host %s to %s failed, %v", hostId, host.Status, recoverErr)
+		}
+		return "", errors.WrapError(errors.TIUNIMANAGER_WORKFLOW_START_FAILED, errMsg, err)
+	}
+	return flowId, nil
+}
+
+// QueryDrainHost
+// @Description: query progress of the latest drain of the host
+// @Receiver p
+// @Parameter ctx
+// @Parameter hostId
+// @return resp
+// @return err
+func (p *ResourcePool) QueryDrainHost(ctx context.Context, hostId string) (resp message.QueryDrainHostResp, err error) {
+	host, err := p.getHost(ctx, hostId)
+	if err != nil {
+		return
+	}
+	flows, _, err := models.GetWorkFlowReaderWriter().QueryWorkFlows(ctx, hostId, workflow.BizTypeHost, rp_consts.FlowDrainHost, "", 1, 1)
+	if err != nil {
+		return
+	}
+	if len(flows) == 0 {
+		err = errors.NewErrorf(errors.TIUNIMANAGER_WORKFLOW_QUERY_FAILED, "host %s has never been drained", hostId)
+		return
+	}
+
+	resp.HostID = hostId
+	resp.HostStatus = host.Status
+	resp.WorkFlowID = flows[0].ID
+	resp.Status = flows[0].Status
+	resp.Instances = make([]structs.DrainInstanceProgress, 0)
+	if flows[0].Context == "" {
+		return
+	}
+	flowData := make(map[string]string)
+	if err = json.Unmarshal([]byte(flows[0].Context), &flowData); err != nil {
+		err = errors.WrapError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, fmt.Sprintf("parse context of workflow %s failed", flows[0].ID), err)
+		return
+	}
+	if progress, ok := flowData[rp_consts.ContextDrainProgressKey]; ok {
+		if err = json.Unmarshal([]byte(progress), &resp.Instances); err != nil {
+			err = errors.WrapError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, fmt.Sprintf("parse drain progress of workflow %s failed", flows[0].ID), err)
+			return
+		}
+	}
+	return
+}
+
+// ReinstateHost
+// @Description: bring a host in maintenance online again, the host must have no instances on it
+// @Receiver p
+// @Parameter ctx
+// @Parameter hostId
+// @return err
+func (p *ResourcePool) ReinstateHost(ctx context.Context, hostId string) (err error) {
+	host, err := p.getHost(ctx, hostId)
+	if err != nil {
+		return err
+	}
+	if host.Status != string(constants.HostMaintenance) {
+		return errors.NewErrorf(errors.TIUNIMANAGER_RESOURCE_HOST_STATUS_CONFLICT, "host %s is %s, only host in maintenance can be reinstated", hostId, host.Status)
+	}
+	if len(host.Instances) > 0 {
+		return errors.NewErrorf(errors.TIUNIMANAGER_RESOURCE_HOST_STILL_INUSED, "host %s still has instances of clusters %v", hostId, host.Instances)
+	}
+	return p.UpdateHostStatus(ctx, []string{hostId}, string(constants.HostOnline))
+}
+
+func getDrainProgressFromFlowContext(ctx *workflow.FlowContext) (progress []structs.DrainInstanceProgress, err error) {
+	err = ctx.GetData(rp_consts.ContextDrainProgressKey, &progress)
+	if err != nil {
+		return nil, errors.NewErrorf(errors.TIUNIMANAGER_RESOURCE_EXTRACT_FLOW_CTX_ERROR, "get key %s from flow context failed, %v", rp_consts.ContextDrainProgressKey, err)
+	}
+	return progress, nil
+}
+
+// saveDrainProgress save progress into flow context, and persist it at once, so that it can be queried while the node is running
+func saveDrainProgress(ctx *workflow.FlowContext, flowID string, progress []structs.DrainInstanceProgress) error {
+	if err := ctx.SetData(rp_consts.ContextDrainProgressKey, progress); err != nil {
+		return err
+	}
+	return workflow.GetWorkFlowService().InitContext(ctx, flowID, rp_consts.ContextDrainProgressKey, progress)
+}
+
+func collectDrainInstances(node *workflowModel.WorkFlowNode, ctx *workflow.FlowContext) (err error) {
+	log := framework.LogWithContext(ctx)
+	log.Infoln("begin collect instances on draining host")
+
+	hostIds, err := getHostIDArrayFromFlowContext(ctx)
+	if err != nil {
+		log.Errorf("collect instances failed for get flow context, %v", err)
+		return err
+	}
+	instances, err := models.GetClusterReaderWriter().QueryInstancesByHost(ctx, hostIds[0], nil, nil)
+	if err != nil {
+		log.Errorf("query instances on host %s failed, %v", hostIds[0], err)
+		return err
+	}
+
+	progress := make([]structs.DrainInstanceProgress, 0, len(instances))
+	for _, instance := range instances {
+		item := structs.DrainInstanceProgress{
+			ClusterID:  instance.ClusterID,
+			InstanceID: instance.ID,
+			Type:       instance.Type,
+			Status:     string(constants.DrainInstanceWaiting),
+		}
+		// monitoring components can not be migrated, they are left on the host
+		if isParasiteComponent(instance.Type) {
+			item.Status, item.Message = string(constants.DrainInstanceSkipped), fmt.Sprintf("%s can not be migrated, scale it in and out manually", instance.Type)
+		}
+		progress = append(progress, item)
+	}
+	if err = ctx.SetData(rp_consts.ContextDrainProgressKey, progress); err != nil {
+		return err
+	}
+	log.Infof("collect %d instances on host %s", len(progress), hostIds[0])
+	node.Record(fmt.Sprintf("%d instances to evacuate from host %s ", len(progress), hostIds[0]))
+	return nil
+}
+
+func evictLeaders(node *workflowModel.WorkFlowNode, ctx *workflow.FlowContext) (err error) {
+	log := framework.LogWithContext(ctx)
+	log.Infoln("begin evict leaders on draining host")
+
+	hostIds, err := getHostIDArrayFromFlowContext(ctx)
+	if err != nil {
+		log.Errorf("evict leaders failed for get flow context, %v", err)
+		return err
+	}
+	progress, err := getDrainProgressFromFlowContext(ctx)
+	if err != nil {
+		log.Errorf("evict leaders failed for get flow context, %v", err)
+		return err
+	}
+
+	evicted := make(map[string][]int)
+	for _, item := range progress {
+		if item.Type != string(constants.ComponentIDTiKV) {
+			continue
+		}
+		if _, ok := evicted[item.ClusterID]; ok {
+			continue
+		}
+		storeIDs, err := GetResourcePool().instanceEvacuator.EvictLeaders(ctx, item.ClusterID, hostIds[0])
+		// stores evicted before failing are recorded too, so that the eviction can be removed by the fail node
+		evicted[item.ClusterID] = storeIDs
+		if setErr := ctx.SetData(rp_consts.ContextEvictedStoresKey, evicted); setErr != nil {
+			log.Errorf("save evicted stores failed, %v", setErr)
+			return setErr
+		}
+		if err != nil {
+			log.Errorf("evict leaders of cluster %s on host %s failed, %v", item.ClusterID, hostIds[0], err)
+			return err
+		}
+		node.Record(fmt.Sprintf("evict leaders of stores %v of cluster %s on host %s ", storeIDs, item.ClusterID, hostIds[0]))
+	}
+	return nil
+}
+
+// removeLeaderEviction remove evict leader schedulers added by evictLeaders, they must not be left in PD after the drain ends
+func removeLeaderEviction(node *workflowModel.WorkFlowNode, ctx *workflow.FlowContext) (err error) {
+	log := framework.LogWithContext(ctx)
+	log.Infoln("begin remove leader eviction of draining host")
+
+	evicted := make(map[string][]int)
+	if err = ctx.GetData(rp_consts.ContextEvictedStoresKey, &evicted); err != nil {
+		return errors.NewErrorf(errors.TIUNIMANAGER_RESOURCE_EXTRACT_FLOW_CTX_ERROR, "get key %s from flow context failed, %v", rp_consts.ContextEvictedStoresKey, err)
+	}
+	var lastErr error
+	for clusterID, storeIDs := range evicted {
+		if len(storeIDs) == 0 {
+			continue
+		}
+		if err = GetResourcePool().instanceEvacuator.RemoveLeaderEviction(ctx, clusterID, storeIDs); err != nil {
+			log.Errorf("remove leader eviction of stores %v of cluster %s failed, %v", storeIDs, clusterID, err)
+			lastErr = err
+			continue
+		}
+		delete(evicted, clusterID)
+		node.Record(fmt.Sprintf("remove leader eviction of stores %v of cluster %s ", storeIDs, clusterID))
+	}
+	if err = ctx.SetData(rp_consts.ContextEvictedStoresKey, evicted); err != nil {
+		log.Warnf("save evicted stores failed, %v", err)
+	}
+	return lastErr
+}
+
+// drainFail remove leader eviction, and keep the host unallocatable because it is not drained completely
+func drainFail(node *workflowModel.WorkFlowNode, ctx *workflow.FlowContext) (err error) {
+	if err = removeLeaderEviction(node, ctx); err != nil {
+		framework.LogWithContext(ctx).Warnf("remove leader eviction after drain failed, %v", err)
+		node.Record(fmt.Sprintf("remove leader eviction failed, remove evict-leader-scheduler by pd-ctl manually: %s ", err.Error()))
+	}
+	return setHostsOffline(node, ctx)
+}
+
+func isParasiteComponent(componentType string) bool {
+	for _, id := range constants.ParasiteComponentIDs {
+		if string(id) == componentType {
+			return true
+		}
+	}
+	return false
+}
+
+// relocateInstances
+// @Description: start relocating instances in background, it is a polling node.
+// The relocation is tracked as a deployment operation, so that the node waits for it like a tiup command,
+// and it is stopped by the deadline or cancellation of the node
+// @Parameter node
+// @Parameter ctx
+// @return err
+func relocateInstances(node *workflowModel.WorkFlowNode, ctx *workflow.FlowContext) (err error) {
+	log := framework.LogWithContext(ctx)
+	log.Infoln("begin relocate instances on draining host")
+
+	progress, err := getDrainProgressFromFlowContext(ctx)
+	if err != nil {
+		log.Errorf("relocate instances failed for get flow context, %v", err)
+		return err
+	}
+	operationID, err := deployment.Create(framework.GetTiupHomePathForTidb(), deployment.Operation{
+		Type:       relocateOperationType,
+		Operation:  fmt.Sprintf("relocate %d instances", len(progress)),
+		WorkFlowID: node.ParentID,
+		Status:     deployment.Processing,
+	})
+	if err != nil {
+		log.Errorf("create relocate operation failed, %v", err)
+		return err
+	}
+	node.OperationID = operationID
+
+	go func() {
+		op := deployment.Operation{
+			Type:       relocateOperationType,
+			Operation:  fmt.Sprintf("relocate %d instances", len(progress)),
+			WorkFlowID: node.ParentID,
+			Status:     deployment.Finished,
+		}
+		if result, relocateErr := relocateInstancesOneByOne(ctx, node.ParentID, progress); relocateErr != nil {
+			op.Status, op.ErrorStr = deployment.Error, relocateErr.Error()
+		} else {
+			op.Result = result
+		}
+		if updateErr := deployment.Update(operationID, op); updateErr != nil {
+			log.Errorf("update relocate operation %s failed, %v", operationID, updateErr)
+		}
+	}()
+	return nil
+}
+
+// relocateInstancesOneByOne migrate instances one by one, instances of the same cluster can not be migrated concurrently
+func relocateInstancesOneByOne(ctx *workflow.FlowContext, flowID string, progress []structs.DrainInstanceProgress) (string, error) {
+	log := framework.LogWithContext(ctx)
+
+	var err error
+	failed, migrated := 0, 0
+	for i := range progress {
+		item := &progress[i]
+		if item.Status == string(constants.DrainInstanceMigrated) || item.Status == string(constants.DrainInstanceSkipped) {
+			continue
+		}
+		if ctx.Err() != nil {
+			return "", errors.NewErrorf(errors.TIUNIMANAGER_TASK_CANCELED, "relocation is canceled, %d instances are migrated", migrated)
+		}
+		item.Status, item.Message = string(constants.DrainInstanceMigrating), ""
+		item.WorkFlowID, err = GetResourcePool().instanceEvacuator.EvacuateInstance(ctx, item.ClusterID, item.InstanceID)
+		if err == nil {
+			if err = saveDrainProgress(ctx, flowID, progress); err != nil {
+				log.Warnf("save drain progress failed, %v", err)
+			}
+			err = waitWorkFlow(ctx, item.WorkFlowID)
+		}
+		if err != nil {
+			log.Errorf("relocate instance %s of cluster %s failed, %v", item.InstanceID, item.ClusterID, err)
+			item.Status, item.Message = string(constants.DrainInstanceFailed), err.Error()
+			failed++
+		} else {
+			item.Status = string(constants.DrainInstanceMigrated)
+			migrated++
+		}
+		if err = saveDrainProgress(ctx, flowID, progress); err != nil {
+			log.Warnf("save drain progress failed, %v", err)
+		}
+	}
+
+	if failed > 0 {
+		return "", errors.NewErrorf(errors.TIUNIMANAGER_RESOURCE_DRAIN_HOST_ERROR, "%d of %d instances are not relocated", failed, len(progress))
+	}
+	return fmt.Sprintf("%d instances are relocated, %d are skipped ", migrated, len(progress)-migrated), nil
+}
+
+// waitWorkFlow wait until the workflow ends, return error if it is not finished successfully or ctx is done.
+// There is no limit of a single migration, the whole relocation is bounded by the timeout of relocateInstances node
+func waitWorkFlow(ctx context.Context, flowId string) error {
+	ticker := time.NewTicker(rp_consts.BackGroundTaskCheckInterval * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return errors.NewErrorf(errors.TIUNIMANAGER_TASK_CANCELED, "stop waiting workflow %s, %v", flowId, ctx.Err())
+		}
+		resp, err := workflow.GetWorkFlowService().DetailWorkFlow(ctx, message.QueryWorkFlowDetailReq{WorkFlowID: flowId})
+		if err != nil {
+			return err
+		}
+		switch resp.Info.Status {
+		case constants.WorkFlowStatusFinished:
+			return nil
+		case constants.WorkFlowStatusError, constants.WorkFlowStatusCanceled:
+			return errors.NewErrorf(errors.TIUNIMANAGER_RESOURCE_DRAIN_HOST_ERROR, "workflow %s ends with status %s", flowId, resp.Info.Status)
+		}
+	}
+}
+
+func setHostsMaintenance(node *workflowModel.WorkFlowNode, ctx *workflow.FlowContext) (err error) {
+	return setHostsStatus(node, ctx, constants.HostMaintenance)
+}
+
+// setHostsOffline keep the host unallocatable if it is not drained completely
+func setHostsOffline(node *workflowModel.WorkFlowNode, ctx *workflow.FlowContext) (err error) {
+	return setHostsStatus(node, ctx, constants.HostOffline)
+}
+
+func setHostsStatus(node *workflowModel.WorkFlowNode, ctx *workflow.FlowContext, status constants.HostStatus) (err error) {
+	log := framework.LogWithContext(ctx)
+	log.Infof("begin set host %s", status)
+
+	hostIds, err := getHostIDArrayFromFlowContext(ctx)
+	if err != nil {
+		log.Errorf("set host %s failed for get flow context, %v", status, err)
+		return err
+	}
+	err = GetResourcePool().UpdateHostStatus(ctx, hostIds, string(status))
+	if err != nil {
+		log.Errorf("set host %v %s failed, %v", hostIds, status, err)
+		return err
+	}
+	log.Infof("set host %v %s succeed", hostIds, status)
+	node.Record(fmt.Sprintf("set status of hosts %v to %v ", hostIds, status))
+	return nil
+}
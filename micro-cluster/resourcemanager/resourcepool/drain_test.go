@@ -0,0 +1,223 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package resourcepool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message"
+	rp_consts "github.com/pingcap/tiunimanager/micro-cluster/resourcemanager/resourcepool/constants"
+	"github.com/pingcap/tiunimanager/models"
+	workflowModel "github.com/pingcap/tiunimanager/models/workflow"
+	mock_provider "github.com/pingcap/tiunimanager/test/mockresource/mockprovider"
+	mock_workflow "github.com/pingcap/tiunimanager/test/mockworkflow"
+	workflow "github.com/pingcap/tiunimanager/workflow2"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeEvacuator struct {
+	evicted  []string
+	removed  map[string][]int
+	evictErr error
+}
+
+func (e *fakeEvacuator) EvictLeaders(ctx context.Context, clusterID string, hostID string) ([]int, error) {
+	e.evicted = append(e.evicted, clusterID)
+	return []int{len(e.evicted)}, e.evictErr
+}
+
+func (e *fakeEvacuator) RemoveLeaderEviction(ctx context.Context, clusterID string, storeIDs []int) error {
+	if e.removed == nil {
+		e.removed = make(map[string][]int)
+	}
+	e.removed[clusterID] = storeIDs
+	return nil
+}
+
+func (e *fakeEvacuator) EvacuateInstance(ctx context.Context, clusterID string, instanceID string) (string, error) {
+	return "", errors.NewError(errors.TIUNIMANAGER_MIGRATE_INSTANCE_INVALID, "can not be migrated")
+}
+
+func mockHostWithStatus(ctrl *gomock.Controller, status constants.HostStatus, instances map[string][]string) *mock_provider.MockHostProvider {
+	mockProvider := mock_provider.NewMockHostProvider(ctrl)
+	mockProvider.EXPECT().QueryHosts(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(
+		[]structs.HostInfo{{ID: "host01", Status: string(status), Instances: instances}}, int64(1), nil).AnyTimes()
+	return mockProvider
+}
+
+func Test_DrainHost_StatusConflict(t *testing.T) {
+	models.MockDB()
+	framework.InitBaseFrameworkForUt(framework.ClusterService)
+	resourcePool := GetResourcePool()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	resourcePool.SetHostProvider(mockHostWithStatus(ctrl, constants.HostMaintenance, nil))
+	resourcePool.SetInstanceEvacuator(&fakeEvacuator{})
+
+	_, err := resourcePool.DrainHost(context.TODO(), "host01")
+	assert.Error(t, err)
+	assert.Equal(t, errors.TIUNIMANAGER_RESOURCE_HOST_STATUS_CONFLICT, err.(errors.EMError).GetCode())
+}
+
+func Test_ReinstateHost(t *testing.T) {
+	models.MockDB()
+	framework.InitBaseFrameworkForUt(framework.ClusterService)
+	resourcePool := GetResourcePool()
+
+	t.Run("normal", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockProvider := mockHostWithStatus(ctrl, constants.HostMaintenance, map[string][]string{})
+		mockProvider.EXPECT().UpdateHostStatus(gomock.Any(), []string{"host01"}, string(constants.HostOnline)).Return(nil)
+		resourcePool.SetHostProvider(mockProvider)
+
+		assert.NoError(t, resourcePool.ReinstateHost(context.TODO(), "host01"))
+	})
+	t.Run("not in maintenance", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		resourcePool.SetHostProvider(mockHostWithStatus(ctrl, constants.HostDraining, map[string][]string{}))
+
+		assert.Error(t, resourcePool.ReinstateHost(context.TODO(), "host01"))
+	})
+	t.Run("instances remained", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		resourcePool.SetHostProvider(mockHostWithStatus(ctrl, constants.HostMaintenance, map[string][]string{"cluster01": {"TiKV"}}))
+
+		err := resourcePool.ReinstateHost(context.TODO(), "host01")
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_RESOURCE_HOST_STILL_INUSED, err.(errors.EMError).GetCode())
+	})
+}
+
+func Test_EvictLeaders(t *testing.T) {
+	models.MockDB()
+	framework.InitBaseFrameworkForUt(framework.ClusterService)
+	resourcePool := GetResourcePool()
+	evacuator := &fakeEvacuator{}
+	resourcePool.SetInstanceEvacuator(evacuator)
+
+	flowContext := workflow.NewFlowContext(context.TODO(), make(map[string]string))
+	flowContext.SetData(rp_consts.ContextHostIDArrayKey, []string{"host01"})
+	flowContext.SetData(rp_consts.ContextDrainProgressKey, []structs.DrainInstanceProgress{
+		{ClusterID: "cluster01", InstanceID: "instance01", Type: "TiKV"},
+		{ClusterID: "cluster01", InstanceID: "instance02", Type: "TiKV"},
+		{ClusterID: "cluster01", InstanceID: "instance03", Type: "TiDB"},
+		{ClusterID: "cluster02", InstanceID: "instance04", Type: "PD"},
+	})
+
+	var node workflowModel.WorkFlowNode
+	assert.NoError(t, evictLeaders(&node, flowContext))
+	assert.Equal(t, []string{"cluster01"}, evacuator.evicted)
+
+	evicted := make(map[string][]int)
+	assert.NoError(t, flowContext.GetData(rp_consts.ContextEvictedStoresKey, &evicted))
+	assert.Equal(t, map[string][]int{"cluster01": {1}}, evicted)
+
+	assert.NoError(t, removeLeaderEviction(&node, flowContext))
+	assert.Equal(t, map[string][]int{"cluster01": {1}}, evacuator.removed)
+}
+
+func Test_DrainFail_RemoveLeaderEviction(t *testing.T) {
+	models.MockDB()
+	framework.InitBaseFrameworkForUt(framework.ClusterService)
+	resourcePool := GetResourcePool()
+	evacuator := &fakeEvacuator{evictErr: errors.Error(errors.TIUNIMANAGER_PD_NOT_FOUND_ERROR)}
+	resourcePool.SetInstanceEvacuator(evacuator)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockProvider := mock_provider.NewMockHostProvider(ctrl)
+	mockProvider.EXPECT().UpdateHostStatus(gomock.Any(), []string{"host01"}, string(constants.HostOffline)).Return(nil)
+	resourcePool.SetHostProvider(mockProvider)
+
+	flowContext := workflow.NewFlowContext(context.TODO(), make(map[string]string))
+	flowContext.SetData(rp_consts.ContextHostIDArrayKey, []string{"host01"})
+	flowContext.SetData(rp_consts.ContextDrainProgressKey, []structs.DrainInstanceProgress{
+		{ClusterID: "cluster01", InstanceID: "instance01", Type: "TiKV"},
+	})
+
+	var node workflowModel.WorkFlowNode
+	assert.Error(t, evictLeaders(&node, flowContext))
+	// the store evicted before failing is restored
+	assert.NoError(t, drainFail(&node, flowContext))
+	assert.Equal(t, map[string][]int{"cluster01": {1}}, evacuator.removed)
+}
+
+func Test_RelocateInstances_SkipMonitor(t *testing.T) {
+	assert.True(t, isParasiteComponent(string(constants.ComponentIDPrometheus)))
+	assert.True(t, isParasiteComponent(string(constants.ComponentIDGrafana)))
+	assert.True(t, isParasiteComponent(string(constants.ComponentIDAlertManger)))
+	assert.False(t, isParasiteComponent(string(constants.ComponentIDTiKV)))
+
+	progress := []structs.DrainInstanceProgress{
+		{ClusterID: "cluster01", InstanceID: "instance01", Type: "Prometheus", Status: string(constants.DrainInstanceSkipped)},
+	}
+	result, err := relocateInstancesOneByOne(workflow.NewFlowContext(context.TODO(), make(map[string]string)), "flow01", progress)
+	assert.NoError(t, err)
+	assert.Equal(t, "0 instances are relocated, 1 are skipped ", result)
+}
+
+func Test_waitWorkFlow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	workflowService := mock_workflow.NewMockWorkFlowService(ctrl)
+	workflow.MockWorkFlowService(workflowService)
+	defer workflow.MockWorkFlowService(workflow.NewWorkFlowManager())
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+		err := waitWorkFlow(ctx, "flow01")
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_TASK_CANCELED, err.(errors.EMError).GetCode())
+	})
+	t.Run("failed", func(t *testing.T) {
+		workflowService.EXPECT().DetailWorkFlow(gomock.Any(), message.QueryWorkFlowDetailReq{WorkFlowID: "flow01"}).
+			Return(message.QueryWorkFlowDetailResp{Info: &structs.WorkFlowInfo{Status: constants.WorkFlowStatusProcessing}}, nil)
+		workflowService.EXPECT().DetailWorkFlow(gomock.Any(), message.QueryWorkFlowDetailReq{WorkFlowID: "flow01"}).
+			Return(message.QueryWorkFlowDetailResp{Info: &structs.WorkFlowInfo{Status: constants.WorkFlowStatusError}}, nil)
+		err := waitWorkFlow(context.TODO(), "flow01")
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_RESOURCE_DRAIN_HOST_ERROR, err.(errors.EMError).GetCode())
+	})
+}
+
+func Test_SetHostsMaintenance(t *testing.T) {
+	models.MockDB()
+	framework.InitBaseFrameworkForUt(framework.ClusterService)
+	resourcePool := GetResourcePool()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockProvider := mock_provider.NewMockHostProvider(ctrl)
+	mockProvider.EXPECT().UpdateHostStatus(gomock.Any(), []string{"host01"}, string(constants.HostMaintenance)).Return(nil)
+	resourcePool.SetHostProvider(mockProvider)
+
+	flowContext := workflow.NewFlowContext(context.TODO(), make(map[string]string))
+	flowContext.SetData(rp_consts.ContextHostIDArrayKey, []string{"host01"})
+
+	var node workflowModel.WorkFlowNode
+	assert.NoError(t, setHostsMaintenance(&node, flowContext))
+}
@@ -39,6 +39,7 @@ type ResourcePool struct {
 	hostProvider  hostprovider.HostProvider
 	hostInitiator hostinitiator.HostInitiator
 	// cloudHostProvider hostprovider.HostProvider
+	instanceEvacuator InstanceEvacuator
 }
 
 var globalResourcePool *ResourcePool
@@ -61,6 +62,7 @@ func (p *ResourcePool) InitResourcePool() {
 	flowManager := workflow.GetWorkFlowService()
 	p.registerImportHostsWorkFlow(context.TODO(), flowManager)
 	p.registerDeleteHostsWorkFlow(context.TODO(), flowManager)
+	p.registerDrainHostWorkFlow(context.TODO(), flowManager)
 }
 
 func (p *ResourcePool) registerImportHostsWorkFlow(ctx context.Context, flowManager workflow.WorkFlowService) {
@@ -47,11 +47,17 @@ import (
 	"github.com/pingcap/tiunimanager/micro-cluster/cluster/backuprestore"
 
 	"github.com/pingcap/tiunimanager/micro-cluster/platform/config"
+	"github.com/pingcap/tiunimanager/micro-cluster/platform/notification"
 
+	clusterAlert "github.com/pingcap/tiunimanager/micro-cluster/cluster/alert"
 	"github.com/pingcap/tiunimanager/micro-cluster/cluster/changefeed"
+	clusterHealth "github.com/pingcap/tiunimanager/micro-cluster/cluster/health"
 	clusterLog "github.com/pingcap/tiunimanager/micro-cluster/cluster/log"
+	"github.com/pingcap/tiunimanager/micro-cluster/cluster/maintainwindow"
 	clusterManager "github.com/pingcap/tiunimanager/micro-cluster/cluster/management"
+	clusterMonitor "github.com/pingcap/tiunimanager/micro-cluster/cluster/monitor"
 	clusterParameter "github.com/pingcap/tiunimanager/micro-cluster/cluster/parameter"
+	clusterPerformance "github.com/pingcap/tiunimanager/micro-cluster/cluster/performance"
 	switchoverManager "github.com/pingcap/tiunimanager/micro-cluster/cluster/switchover"
 	"github.com/pingcap/tiunimanager/micro-cluster/datatransfer/importexport"
 	"github.com/pingcap/tiunimanager/micro-cluster/parametergroup"
@@ -75,6 +81,11 @@ type ClusterServiceHandler struct {
 	brManager               backuprestore.BRService
 	importexportManager     importexport.ImportExportService
 	clusterLogManager       *clusterLog.Manager
+	alertManager            *clusterAlert.Manager
+	notificationManager     *notification.Manager
+	monitorManager          *clusterMonitor.Manager
+	performanceManager      *clusterPerformance.Manager
+	healthManager           *clusterHealth.Manager
 	accountManager          *account.Manager
 	authManager             *identification.Manager
 	productManager          *product.Manager
@@ -83,9 +94,47 @@ type ClusterServiceHandler struct {
 	platformLogManager      *platformLog.Manager
 }
 
+// clusterIDKeys keys of cluster ids in requests, the request must be permitted on all of the clusters
+var clusterIDKeys = []string{
+	"clusterId", "clusterID",
+	"sourceClusterId", "sourceClusterID",
+	"targetClusterId", "targetClusterID",
+}
+
+// getRequestClusterIDs
+// @Description: resolve clusters operated by the request from its json body
+// @Parameter request
+// @return []string
+func getRequestClusterIDs(request string) []string {
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal([]byte(request), &fields); err != nil {
+		return nil
+	}
+	clusterIDs := make([]string, 0)
+	for _, key := range clusterIDKeys {
+		var clusterID string
+		if raw, ok := fields[key]; ok && json.Unmarshal(raw, &clusterID) == nil && clusterID != "" {
+			clusterIDs = append(clusterIDs, clusterID)
+		}
+	}
+	return clusterIDs
+}
+
 func handleRequest(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse, requestBody interface{}, permissions []structs.RbacPermission) bool {
+	err := json.Unmarshal([]byte(req.GetRequest()), requestBody)
+	if err != nil {
+		errMsg := fmt.Sprintf("unmarshal request failed, err = %s", err.Error())
+		handleResponse(ctx, resp, errors.NewError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, errMsg), nil, nil)
+		return false
+	}
+
 	if len(permissions) > 0 {
-		result, err := rbac.GetRBACService().CheckPermissionForUser(ctx, message.CheckPermissionForUserReq{UserID: framework.GetUserIDFromContext(ctx), Permissions: permissions})
+		// permission granted on some clusters is accepted only if the request operates on them
+		result, err := rbac.GetRBACService().CheckPermissionForUser(ctx, message.CheckPermissionForUserReq{
+			UserID:      framework.GetUserIDFromContext(ctx),
+			Permissions: permissions,
+			ClusterIDs:  getRequestClusterIDs(req.GetRequest()),
+		})
 		if err != nil {
 			errMsg := fmt.Sprintf("check permission for user %s error, permission %+v, err = %s", framework.GetUserIDFromContext(ctx), permissions, err.Error())
 			handleResponse(ctx, resp, errors.NewError(errors.TIUNIMANAGER_RBAC_PERMISSION_CHECK_FAILED, errMsg), nil, nil)
@@ -98,18 +147,10 @@ func handleRequest(ctx context.Context, req *clusterservices.RpcRequest, resp *c
 		}
 	}
 
-	err := json.Unmarshal([]byte(req.GetRequest()), requestBody)
-
-	if err != nil {
-		errMsg := fmt.Sprintf("unmarshal request failed, err = %s", err.Error())
-		handleResponse(ctx, resp, errors.NewError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, errMsg), nil, nil)
-		return false
-	} else {
-		if pc, _, _, ok := runtime.Caller(1); ok {
-			desensitizeLog(ctx, runtime.FuncForPC(pc).Name(), "start", requestBody)
-		}
-		return true
+	if pc, _, _, ok := runtime.Caller(1); ok {
+		desensitizeLog(ctx, runtime.FuncForPC(pc).Name(), "start", requestBody)
 	}
+	return true
 }
 
 func desensitizeLog(ctx context.Context, methodName, event string, data interface{}) string {
@@ -177,18 +218,31 @@ func NewClusterServiceHandler(fw *framework.BaseFramework) *ClusterServiceHandle
 	handler.parameterGroupManager = parametergroup.NewManager()
 	handler.clusterParameterManager = clusterParameter.NewManager()
 	handler.clusterManager = clusterManager.NewClusterManager()
+	handler.resourceManager.SetInstanceEvacuator(handler.clusterManager)
 	handler.switchoverManager = switchoverManager.GetManager()
 	handler.systemConfigManager = config.NewSystemConfigManager()
 	handler.systemManager = system.GetSystemManager()
 	handler.brManager = backuprestore.GetBRService()
 	handler.importexportManager = importexport.GetImportExportService()
 	handler.clusterLogManager = clusterLog.NewManager()
+	handler.alertManager = clusterAlert.NewManager()
+	handler.notificationManager = notification.NewManager()
+	handler.monitorManager = clusterMonitor.NewManager()
+	handler.performanceManager = clusterPerformance.NewManager()
+	handler.healthManager = clusterHealth.NewManager()
 	handler.accountManager = account.NewAccountManager()
 	handler.authManager = identification.NewIdentificationManager()
 	handler.productManager = product.NewManager()
 	handler.rbacManager = rbac.GetRBACService()
 	handler.checkManager = check.GetCheckService()
 	handler.platformLogManager = platformLog.NewManager()
+	maintainwindow.StartScheduler()
+	handler.clusterManager.StartUpgradeCampaignScheduler()
+	handler.clusterManager.StartClusterRecycleScheduler()
+	clusterAlert.StartAlertHistoryScheduler()
+	notification.StartDeliveryScheduler()
+	clusterPerformance.StartPerformanceSnapshotScheduler()
+	clusterHealth.StartHealthMonitor()
 	return handler
 }
 
@@ -472,218 +526,915 @@ func (handler *ClusterServiceHandler) QueryClusterLog(ctx context.Context, req *
 	return nil
 }
 
-func (handler *ClusterServiceHandler) QueryPlatformLog(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+func (handler *ClusterServiceHandler) QueryFiringAlerts(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
 	start := time.Now()
-	defer metrics.HandleClusterMetrics(start, "QueryPlatformLog", int(resp.GetCode()))
-	defer handlePanic(ctx, "QueryPlatformLog", resp)
+	defer metrics.HandleClusterMetrics(start, "QueryFiringAlerts", int(resp.GetCode()))
+	defer handlePanic(ctx, "QueryFiringAlerts", resp)
 
-	request := &message.QueryPlatformLogReq{}
+	request := cluster.QueryFiringAlertsReq{}
 
-	if handleRequest(ctx, req, resp, request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead)}}) {
-		result, page, err := handler.platformLogManager.QueryPlatformLog(framework.NewBackgroundMicroCtx(ctx, false), *request)
-		handleResponse(ctx, resp, err, result, page)
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead)}}) {
+		result, err := handler.alertManager.QueryFiringAlerts(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
 	}
+
 	return nil
 }
 
-func (c ClusterServiceHandler) CreateCluster(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
+func (handler *ClusterServiceHandler) QueryAlertHistory(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
 	start := time.Now()
-	defer metrics.HandleClusterMetrics(start, "CreateCluster", int(resp.GetCode()))
-	defer handlePanic(ctx, "CreateCluster", resp)
+	defer metrics.HandleClusterMetrics(start, "QueryAlertHistory", int(resp.GetCode()))
+	defer handlePanic(ctx, "QueryAlertHistory", resp)
 
-	request := cluster.CreateClusterReq{}
+	request := cluster.QueryAlertHistoryReq{}
 
-	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionCreate)}}) {
-		result, err := c.clusterManager.CreateCluster(framework.NewBackgroundMicroCtx(ctx, false), request)
-		handleResponse(ctx, resp, err, result, nil)
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead)}}) {
+		result, total, err := handler.alertManager.QueryAlertHistory(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, &clusterservices.RpcPage{
+			Page:     int32(request.Page),
+			PageSize: int32(request.PageSize),
+			Total:    int32(total),
+		})
 	}
 
 	return nil
 }
 
-func (c ClusterServiceHandler) PreviewCluster(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
+func (handler *ClusterServiceHandler) QueryAlertRules(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
 	start := time.Now()
-	defer metrics.HandleClusterMetrics(start, "PreviewCluster", int(resp.GetCode()))
-	defer handlePanic(ctx, "PreviewCluster", resp)
+	defer metrics.HandleClusterMetrics(start, "QueryAlertRules", int(resp.GetCode()))
+	defer handlePanic(ctx, "QueryAlertRules", resp)
 
-	request := cluster.CreateClusterReq{}
+	request := cluster.QueryAlertRulesReq{}
 
 	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead)}}) {
-		result, err := c.clusterManager.PreviewCluster(framework.NewBackgroundMicroCtx(ctx, false), request)
+		result, total, err := handler.alertManager.QueryAlertRules(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, &clusterservices.RpcPage{
+			Page:     int32(request.Page),
+			PageSize: int32(request.PageSize),
+			Total:    int32(total),
+		})
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) CreateAlertRule(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "CreateAlertRule", int(resp.GetCode()))
+	defer handlePanic(ctx, "CreateAlertRule", resp)
+
+	request := cluster.CreateAlertRuleReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := handler.alertManager.CreateAlertRule(framework.NewBackgroundMicroCtx(ctx, false), request)
 		handleResponse(ctx, resp, err, result, nil)
 	}
 
 	return nil
 }
 
-func (c ClusterServiceHandler) PreviewScaleOutCluster(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
+func (handler *ClusterServiceHandler) UpdateAlertRule(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
 	start := time.Now()
-	defer metrics.HandleClusterMetrics(start, "PreviewScaleOutCluster", int(resp.GetCode()))
-	defer handlePanic(ctx, "PreviewScaleOutCluster", resp)
+	defer metrics.HandleClusterMetrics(start, "UpdateAlertRule", int(resp.GetCode()))
+	defer handlePanic(ctx, "UpdateAlertRule", resp)
 
-	request := cluster.ScaleOutClusterReq{}
+	request := cluster.UpdateAlertRuleReq{}
 
-	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead)}}) {
-		result, err := c.clusterManager.PreviewScaleOutCluster(framework.NewBackgroundMicroCtx(ctx, false), request)
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := handler.alertManager.UpdateAlertRule(framework.NewBackgroundMicroCtx(ctx, false), request)
 		handleResponse(ctx, resp, err, result, nil)
 	}
 
 	return nil
 }
 
-func (c ClusterServiceHandler) RestoreNewCluster(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
+func (handler *ClusterServiceHandler) SetAlertRuleEnabled(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
 	start := time.Now()
-	defer metrics.HandleClusterMetrics(start, "RestoreNewCluster", int(resp.GetCode()))
-	defer handlePanic(ctx, "RestoreNewCluster", resp)
+	defer metrics.HandleClusterMetrics(start, "SetAlertRuleEnabled", int(resp.GetCode()))
+	defer handlePanic(ctx, "SetAlertRuleEnabled", resp)
 
-	request := cluster.RestoreNewClusterReq{}
+	request := cluster.SetAlertRuleEnabledReq{}
 
 	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
-		result, err := c.clusterManager.RestoreNewCluster(framework.NewBackgroundMicroCtx(ctx, false), request)
+		result, err := handler.alertManager.SetAlertRuleEnabled(framework.NewBackgroundMicroCtx(ctx, false), request)
 		handleResponse(ctx, resp, err, result, nil)
 	}
 
 	return nil
 }
 
-func (handler *ClusterServiceHandler) ScaleOutCluster(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+func (handler *ClusterServiceHandler) DeleteAlertRule(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
 	start := time.Now()
-	defer metrics.HandleClusterMetrics(start, "ScaleOutCluster", int(resp.GetCode()))
-	defer handlePanic(ctx, "ScaleOutCluster", resp)
+	defer metrics.HandleClusterMetrics(start, "DeleteAlertRule", int(resp.GetCode()))
+	defer handlePanic(ctx, "DeleteAlertRule", resp)
 
-	request := cluster.ScaleOutClusterReq{}
+	request := cluster.DeleteAlertRuleReq{}
 
 	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
-		result, err := handler.clusterManager.ScaleOut(framework.NewBackgroundMicroCtx(ctx, false), request)
+		result, err := handler.alertManager.DeleteAlertRule(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) CreateAlertSilence(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "CreateAlertSilence", int(resp.GetCode()))
+	defer handlePanic(ctx, "CreateAlertSilence", resp)
 
+	request := cluster.CreateAlertSilenceReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := handler.alertManager.CreateAlertSilence(framework.NewBackgroundMicroCtx(ctx, false), request)
 		handleResponse(ctx, resp, err, result, nil)
 	}
 
 	return nil
 }
 
-func (handler *ClusterServiceHandler) ScaleInCluster(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+func (handler *ClusterServiceHandler) QueryAlertSilences(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
 	start := time.Now()
-	defer metrics.HandleClusterMetrics(start, "ScaleInCluster", int(resp.GetCode()))
-	defer handlePanic(ctx, "ScaleInCluster", resp)
+	defer metrics.HandleClusterMetrics(start, "QueryAlertSilences", int(resp.GetCode()))
+	defer handlePanic(ctx, "QueryAlertSilences", resp)
+
+	request := cluster.QueryAlertSilencesReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead)}}) {
+		result, total, err := handler.alertManager.QueryAlertSilences(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, &clusterservices.RpcPage{
+			Page:     int32(request.Page),
+			PageSize: int32(request.PageSize),
+			Total:    int32(total),
+		})
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) DeleteAlertSilence(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "DeleteAlertSilence", int(resp.GetCode()))
+	defer handlePanic(ctx, "DeleteAlertSilence", resp)
+
+	request := cluster.DeleteAlertSilenceReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := handler.alertManager.DeleteAlertSilence(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) ConfigureAlertReceiver(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "ConfigureAlertReceiver", int(resp.GetCode()))
+	defer handlePanic(ctx, "ConfigureAlertReceiver", resp)
+
+	request := cluster.ConfigureAlertReceiverReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := handler.alertManager.ConfigureAlertReceiver(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) CreateNotificationChannel(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "CreateNotificationChannel", int(resp.GetCode()))
+	defer handlePanic(ctx, "CreateNotificationChannel", resp)
+
+	request := message.CreateNotificationChannelReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceSystem), Action: string(constants.RbacActionCreate)}}) {
+		result, err := handler.notificationManager.CreateNotificationChannel(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) UpdateNotificationChannel(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "UpdateNotificationChannel", int(resp.GetCode()))
+	defer handlePanic(ctx, "UpdateNotificationChannel", resp)
+
+	request := message.UpdateNotificationChannelReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceSystem), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := handler.notificationManager.UpdateNotificationChannel(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) DeleteNotificationChannel(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "DeleteNotificationChannel", int(resp.GetCode()))
+	defer handlePanic(ctx, "DeleteNotificationChannel", resp)
+
+	request := message.DeleteNotificationChannelReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceSystem), Action: string(constants.RbacActionDelete)}}) {
+		result, err := handler.notificationManager.DeleteNotificationChannel(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) QueryNotificationChannels(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "QueryNotificationChannels", int(resp.GetCode()))
+	defer handlePanic(ctx, "QueryNotificationChannels", resp)
+
+	request := message.QueryNotificationChannelsReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceSystem), Action: string(constants.RbacActionRead)}}) {
+		result, total, err := handler.notificationManager.QueryNotificationChannels(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, &clusterservices.RpcPage{
+			Page:     int32(request.Page),
+			PageSize: int32(request.PageSize),
+			Total:    int32(total),
+		})
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) TestNotificationChannel(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "TestNotificationChannel", int(resp.GetCode()))
+	defer handlePanic(ctx, "TestNotificationChannel", resp)
+
+	request := message.TestNotificationChannelReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceSystem), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := handler.notificationManager.TestNotificationChannel(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) CreateNotificationRoute(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "CreateNotificationRoute", int(resp.GetCode()))
+	defer handlePanic(ctx, "CreateNotificationRoute", resp)
+
+	request := message.CreateNotificationRouteReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceSystem), Action: string(constants.RbacActionCreate)}}) {
+		result, err := handler.notificationManager.CreateNotificationRoute(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) UpdateNotificationRoute(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "UpdateNotificationRoute", int(resp.GetCode()))
+	defer handlePanic(ctx, "UpdateNotificationRoute", resp)
+
+	request := message.UpdateNotificationRouteReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceSystem), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := handler.notificationManager.UpdateNotificationRoute(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) DeleteNotificationRoute(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "DeleteNotificationRoute", int(resp.GetCode()))
+	defer handlePanic(ctx, "DeleteNotificationRoute", resp)
+
+	request := message.DeleteNotificationRouteReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceSystem), Action: string(constants.RbacActionDelete)}}) {
+		result, err := handler.notificationManager.DeleteNotificationRoute(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) QueryNotificationRoutes(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "QueryNotificationRoutes", int(resp.GetCode()))
+	defer handlePanic(ctx, "QueryNotificationRoutes", resp)
+
+	request := message.QueryNotificationRoutesReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceSystem), Action: string(constants.RbacActionRead)}}) {
+		result, total, err := handler.notificationManager.QueryNotificationRoutes(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, &clusterservices.RpcPage{
+			Page:     int32(request.Page),
+			PageSize: int32(request.PageSize),
+			Total:    int32(total),
+		})
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) QueryNotificationDeliveries(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "QueryNotificationDeliveries", int(resp.GetCode()))
+	defer handlePanic(ctx, "QueryNotificationDeliveries", resp)
+
+	request := message.QueryNotificationDeliveriesReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceSystem), Action: string(constants.RbacActionRead)}}) {
+		result, total, err := handler.notificationManager.QueryNotificationDeliveries(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, &clusterservices.RpcPage{
+			Page:     int32(request.Page),
+			PageSize: int32(request.PageSize),
+			Total:    int32(total),
+		})
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) ReceiveAlerts(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "ReceiveAlerts", int(resp.GetCode()))
+	defer handlePanic(ctx, "ReceiveAlerts", resp)
+
+	request := message.ReceiveAlertsReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{}) {
+		result, err := handler.notificationManager.ReceiveAlerts(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) QueryClusterMetrics(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "QueryClusterMetrics", int(resp.GetCode()))
+	defer handlePanic(ctx, "QueryClusterMetrics", resp)
+
+	request := cluster.QueryClusterMetricsReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead)}}) {
+		result, err := handler.monitorManager.QueryClusterMetrics(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) QueryTopSQL(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "QueryTopSQL", int(resp.GetCode()))
+	defer handlePanic(ctx, "QueryTopSQL", resp)
+
+	request := cluster.QueryTopSQLReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead)}}) {
+		result, err := handler.performanceManager.QueryTopSQL(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) QueryStatementPlans(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "QueryStatementPlans", int(resp.GetCode()))
+	defer handlePanic(ctx, "QueryStatementPlans", resp)
+
+	request := cluster.QueryStatementPlansReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead)}}) {
+		result, err := handler.performanceManager.QueryStatementPlans(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) QuerySlowQueries(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "QuerySlowQueries", int(resp.GetCode()))
+	defer handlePanic(ctx, "QuerySlowQueries", resp)
+
+	request := cluster.QuerySlowQueriesReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead)}}) {
+		result, err := handler.performanceManager.QuerySlowQueries(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) CreatePerformanceSnapshot(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "CreatePerformanceSnapshot", int(resp.GetCode()))
+	defer handlePanic(ctx, "CreatePerformanceSnapshot", resp)
+
+	request := cluster.CreatePerformanceSnapshotReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := handler.performanceManager.CreatePerformanceSnapshot(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) QueryPerformanceSnapshots(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "QueryPerformanceSnapshots", int(resp.GetCode()))
+	defer handlePanic(ctx, "QueryPerformanceSnapshots", resp)
+
+	request := cluster.QueryPerformanceSnapshotsReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead)}}) {
+		result, total, err := handler.performanceManager.QueryPerformanceSnapshots(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, &clusterservices.RpcPage{
+			Page:     int32(request.Page),
+			PageSize: int32(request.PageSize),
+			Total:    int32(total),
+		})
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) GetPerformanceSnapshot(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "GetPerformanceSnapshot", int(resp.GetCode()))
+	defer handlePanic(ctx, "GetPerformanceSnapshot", resp)
+
+	request := cluster.GetPerformanceSnapshotReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead)}}) {
+		result, err := handler.performanceManager.GetPerformanceSnapshot(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) DeletePerformanceSnapshot(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "DeletePerformanceSnapshot", int(resp.GetCode()))
+	defer handlePanic(ctx, "DeletePerformanceSnapshot", resp)
+
+	request := cluster.DeletePerformanceSnapshotReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := handler.performanceManager.DeletePerformanceSnapshot(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) ComparePerformanceSnapshots(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "ComparePerformanceSnapshots", int(resp.GetCode()))
+	defer handlePanic(ctx, "ComparePerformanceSnapshots", resp)
+
+	request := cluster.ComparePerformanceSnapshotsReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead)}}) {
+		result, err := handler.performanceManager.ComparePerformanceSnapshots(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) QueryClusterHealthEvents(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "QueryClusterHealthEvents", int(resp.GetCode()))
+	defer handlePanic(ctx, "QueryClusterHealthEvents", resp)
+
+	request := cluster.QueryClusterHealthEventsReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead)}}) {
+		result, total, err := handler.healthManager.QueryClusterHealthEvents(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, &clusterservices.RpcPage{
+			Page:     int32(request.Page),
+			PageSize: int32(request.PageSize),
+			Total:    int32(total),
+		})
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) QueryPlatformLog(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "QueryPlatformLog", int(resp.GetCode()))
+	defer handlePanic(ctx, "QueryPlatformLog", resp)
+
+	request := &message.QueryPlatformLogReq{}
+
+	if handleRequest(ctx, req, resp, request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead)}}) {
+		result, page, err := handler.platformLogManager.QueryPlatformLog(framework.NewBackgroundMicroCtx(ctx, false), *request)
+		handleResponse(ctx, resp, err, result, page)
+	}
+	return nil
+}
+
+func (c ClusterServiceHandler) CreateCluster(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "CreateCluster", int(resp.GetCode()))
+	defer handlePanic(ctx, "CreateCluster", resp)
+
+	request := cluster.CreateClusterReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionCreate)}}) {
+		result, err := c.clusterManager.CreateCluster(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (c ClusterServiceHandler) PreviewCluster(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "PreviewCluster", int(resp.GetCode()))
+	defer handlePanic(ctx, "PreviewCluster", resp)
+
+	request := cluster.CreateClusterReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead)}}) {
+		result, err := c.clusterManager.PreviewCluster(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (c ClusterServiceHandler) PreviewScaleOutCluster(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "PreviewScaleOutCluster", int(resp.GetCode()))
+	defer handlePanic(ctx, "PreviewScaleOutCluster", resp)
+
+	request := cluster.ScaleOutClusterReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead)}}) {
+		result, err := c.clusterManager.PreviewScaleOutCluster(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (c ClusterServiceHandler) RestoreNewCluster(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "RestoreNewCluster", int(resp.GetCode()))
+	defer handlePanic(ctx, "RestoreNewCluster", resp)
+
+	request := cluster.RestoreNewClusterReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := c.clusterManager.RestoreNewCluster(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) ScaleOutCluster(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "ScaleOutCluster", int(resp.GetCode()))
+	defer handlePanic(ctx, "ScaleOutCluster", resp)
+
+	request := cluster.ScaleOutClusterReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := handler.clusterManager.ScaleOut(framework.NewBackgroundMicroCtx(ctx, false), request)
+
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) ScaleInCluster(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "ScaleInCluster", int(resp.GetCode()))
+	defer handlePanic(ctx, "ScaleInCluster", resp)
+
+	request := cluster.ScaleInClusterReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := handler.clusterManager.ScaleIn(framework.NewBackgroundMicroCtx(ctx, false), request)
+
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) CloneCluster(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "CloneCluster", int(resp.GetCode()))
+	defer handlePanic(ctx, "CloneCluster", resp)
+
+	request := cluster.CloneClusterReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionCreate)}}) {
+		result, err := handler.clusterManager.Clone(framework.NewBackgroundMicroCtx(ctx, false), request)
+
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler ClusterServiceHandler) TakeoverClusters(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "TakeoverClusters", int(resp.GetCode()))
+	defer handlePanic(ctx, "TakeoverClusters", resp)
+
+	request := cluster.TakeoverClusterReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionCreate)}}) {
+		result, err := handler.clusterManager.Takeover(framework.NewBackgroundMicroCtx(ctx, false), request)
+
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler ClusterServiceHandler) DeleteMetadataPhysically(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "DeleteMetadataPhysically", int(resp.GetCode()))
+	defer handlePanic(ctx, "DeleteMetadataPhysically", resp)
+
+	request := cluster.DeleteMetadataPhysicallyReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceSystem), Action: string(constants.RbacActionDelete)}}) {
+		result, err := handler.clusterManager.DeleteMetadataPhysically(framework.NewBackgroundMicroCtx(ctx, false), request)
+
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (c ClusterServiceHandler) QueryCluster(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "QueryCluster", int(resp.GetCode()))
+	defer handlePanic(ctx, "QueryCluster", resp)
+
+	request := cluster.QueryClustersReq{}
+
+	// clusters are filtered by permission of current user in QueryCluster
+	if handleRequest(ctx, req, resp, &request, nil) {
+		result, total, err := c.clusterManager.QueryCluster(ctx, request)
+		handleResponse(ctx, resp, err, result, &clusterservices.RpcPage{
+			Page:     int32(request.Page),
+			PageSize: int32(request.PageSize),
+			Total:    int32(total),
+		})
+	}
+
+	return nil
+}
+
+func (c ClusterServiceHandler) DeleteCluster(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "DeleteCluster", int(resp.GetCode()))
+	defer handlePanic(ctx, "DeleteCluster", resp)
+
+	request := cluster.DeleteClusterReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionDelete)}}) {
+		result, err := c.clusterManager.DeleteCluster(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (c ClusterServiceHandler) RestartCluster(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "RestartCluster", int(resp.GetCode()))
+	defer handlePanic(ctx, "RestartCluster", resp)
+
+	request := cluster.RestartClusterReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := c.clusterManager.RestartCluster(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (c ClusterServiceHandler) StopCluster(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "StopCluster", int(resp.GetCode()))
+	defer handlePanic(ctx, "StopCluster", resp)
+
+	request := cluster.StopClusterReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := c.clusterManager.StopCluster(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (c ClusterServiceHandler) StartInstance(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "StartInstance", int(resp.GetCode()))
+	defer handlePanic(ctx, "StartInstance", resp)
+
+	request := cluster.StartInstanceReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := c.clusterManager.StartInstance(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (c ClusterServiceHandler) StopInstance(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "StopInstance", int(resp.GetCode()))
+	defer handlePanic(ctx, "StopInstance", resp)
+
+	request := cluster.StopInstanceReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := c.clusterManager.StopInstance(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (c ClusterServiceHandler) RestartInstance(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "RestartInstance", int(resp.GetCode()))
+	defer handlePanic(ctx, "RestartInstance", resp)
+
+	request := cluster.RestartInstanceReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := c.clusterManager.RestartInstance(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (c ClusterServiceHandler) ReloadInstance(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "ReloadInstance", int(resp.GetCode()))
+	defer handlePanic(ctx, "ReloadInstance", resp)
+
+	request := cluster.ReloadInstanceReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := c.clusterManager.ReloadInstance(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (c ClusterServiceHandler) MigrateInstance(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "MigrateInstance", int(resp.GetCode()))
+	defer handlePanic(ctx, "MigrateInstance", resp)
 
-	request := cluster.ScaleInClusterReq{}
+	request := cluster.MigrateInstanceReq{}
 
 	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
-		result, err := handler.clusterManager.ScaleIn(framework.NewBackgroundMicroCtx(ctx, false), request)
-
+		result, err := c.clusterManager.MigrateInstance(framework.NewBackgroundMicroCtx(ctx, false), request)
 		handleResponse(ctx, resp, err, result, nil)
 	}
 
 	return nil
 }
 
-func (handler *ClusterServiceHandler) CloneCluster(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+func (c ClusterServiceHandler) QueryPendingOperations(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
 	start := time.Now()
-	defer metrics.HandleClusterMetrics(start, "CloneCluster", int(resp.GetCode()))
-	defer handlePanic(ctx, "CloneCluster", resp)
-
-	request := cluster.CloneClusterReq{}
+	defer metrics.HandleClusterMetrics(start, "QueryPendingOperations", int(resp.GetCode()))
+	defer handlePanic(ctx, "QueryPendingOperations", resp)
 
-	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionCreate)}}) {
-		result, err := handler.clusterManager.Clone(framework.NewBackgroundMicroCtx(ctx, false), request)
+	request := cluster.QueryPendingOperationsReq{}
 
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead)}}) {
+		result, err := c.clusterManager.QueryPendingOperations(framework.NewBackgroundMicroCtx(ctx, false), request)
 		handleResponse(ctx, resp, err, result, nil)
 	}
 
 	return nil
 }
 
-func (handler ClusterServiceHandler) TakeoverClusters(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
+func (c ClusterServiceHandler) CancelPendingOperation(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
 	start := time.Now()
-	defer metrics.HandleClusterMetrics(start, "TakeoverClusters", int(resp.GetCode()))
-	defer handlePanic(ctx, "TakeoverClusters", resp)
-
-	request := cluster.TakeoverClusterReq{}
+	defer metrics.HandleClusterMetrics(start, "CancelPendingOperation", int(resp.GetCode()))
+	defer handlePanic(ctx, "CancelPendingOperation", resp)
 
-	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionCreate)}}) {
-		result, err := handler.clusterManager.Takeover(framework.NewBackgroundMicroCtx(ctx, false), request)
+	request := cluster.CancelPendingOperationReq{}
 
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := c.clusterManager.CancelPendingOperation(framework.NewBackgroundMicroCtx(ctx, false), request)
 		handleResponse(ctx, resp, err, result, nil)
 	}
 
 	return nil
 }
 
-func (handler ClusterServiceHandler) DeleteMetadataPhysically(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
+func (c ClusterServiceHandler) AddClusterTags(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
 	start := time.Now()
-	defer metrics.HandleClusterMetrics(start, "DeleteMetadataPhysically", int(resp.GetCode()))
-	defer handlePanic(ctx, "DeleteMetadataPhysically", resp)
-
-	request := cluster.DeleteMetadataPhysicallyReq{}
+	defer metrics.HandleClusterMetrics(start, "AddClusterTags", int(resp.GetCode()))
+	defer handlePanic(ctx, "AddClusterTags", resp)
 
-	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceSystem), Action: string(constants.RbacActionDelete)}}) {
-		result, err := handler.clusterManager.DeleteMetadataPhysically(framework.NewBackgroundMicroCtx(ctx, false), request)
+	request := cluster.AddClusterTagsReq{}
 
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := c.clusterManager.AddClusterTags(framework.NewBackgroundMicroCtx(ctx, false), request)
 		handleResponse(ctx, resp, err, result, nil)
 	}
 
 	return nil
 }
 
-func (c ClusterServiceHandler) QueryCluster(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
+func (c ClusterServiceHandler) RemoveClusterTags(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
 	start := time.Now()
-	defer metrics.HandleClusterMetrics(start, "QueryCluster", int(resp.GetCode()))
-	defer handlePanic(ctx, "QueryCluster", resp)
+	defer metrics.HandleClusterMetrics(start, "RemoveClusterTags", int(resp.GetCode()))
+	defer handlePanic(ctx, "RemoveClusterTags", resp)
 
-	request := cluster.QueryClustersReq{}
+	request := cluster.RemoveClusterTagsReq{}
 
-	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead)}}) {
-		result, total, err := c.clusterManager.QueryCluster(ctx, request)
-		handleResponse(ctx, resp, err, result, &clusterservices.RpcPage{
-			Page:     int32(request.Page),
-			PageSize: int32(request.PageSize),
-			Total:    int32(total),
-		})
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := c.clusterManager.RemoveClusterTags(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
 	}
 
 	return nil
 }
 
-func (c ClusterServiceHandler) DeleteCluster(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+func (c ClusterServiceHandler) SetDeletionProtection(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
 	start := time.Now()
-	defer metrics.HandleClusterMetrics(start, "DeleteCluster", int(resp.GetCode()))
-	defer handlePanic(ctx, "DeleteCluster", resp)
+	defer metrics.HandleClusterMetrics(start, "SetDeletionProtection", int(resp.GetCode()))
+	defer handlePanic(ctx, "SetDeletionProtection", resp)
 
-	request := cluster.DeleteClusterReq{}
+	request := cluster.SetDeletionProtectionReq{}
 
-	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionDelete)}}) {
-		result, err := c.clusterManager.DeleteCluster(framework.NewBackgroundMicroCtx(ctx, false), request)
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := c.clusterManager.SetDeletionProtection(framework.NewBackgroundMicroCtx(ctx, false), request)
 		handleResponse(ctx, resp, err, result, nil)
 	}
 
 	return nil
 }
 
-func (c ClusterServiceHandler) RestartCluster(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
+func (c ClusterServiceHandler) QueryRecycledClusters(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
 	start := time.Now()
-	defer metrics.HandleClusterMetrics(start, "RestartCluster", int(resp.GetCode()))
-	defer handlePanic(ctx, "RestartCluster", resp)
+	defer metrics.HandleClusterMetrics(start, "QueryRecycledClusters", int(resp.GetCode()))
+	defer handlePanic(ctx, "QueryRecycledClusters", resp)
 
-	request := cluster.RestartClusterReq{}
+	request := cluster.QueryRecycledClustersReq{}
 
-	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
-		result, err := c.clusterManager.RestartCluster(framework.NewBackgroundMicroCtx(ctx, false), request)
-		handleResponse(ctx, resp, err, result, nil)
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead)}}) {
+		result, total, err := c.clusterManager.QueryRecycledClusters(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, &clusterservices.RpcPage{
+			Page:     int32(request.Page),
+			PageSize: int32(request.PageSize),
+			Total:    int32(total),
+		})
 	}
 
 	return nil
 }
 
-func (c ClusterServiceHandler) StopCluster(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
+func (c ClusterServiceHandler) RecoverCluster(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
 	start := time.Now()
-	defer metrics.HandleClusterMetrics(start, "StopCluster", int(resp.GetCode()))
-	defer handlePanic(ctx, "StopCluster", resp)
+	defer metrics.HandleClusterMetrics(start, "RecoverCluster", int(resp.GetCode()))
+	defer handlePanic(ctx, "RecoverCluster", resp)
 
-	request := cluster.StopClusterReq{}
+	request := cluster.RecoverClusterReq{}
 
 	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
-		result, err := c.clusterManager.StopCluster(framework.NewBackgroundMicroCtx(ctx, false), request)
+		result, err := c.clusterManager.RecoverCluster(framework.NewBackgroundMicroCtx(ctx, false), request)
 		handleResponse(ctx, resp, err, result, nil)
 	}
 
@@ -705,6 +1456,21 @@ func (c ClusterServiceHandler) DetailCluster(ctx context.Context, req *clusterse
 	return nil
 }
 
+func (c ClusterServiceHandler) UpdateClusterProfile(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "UpdateClusterProfile", int(resp.GetCode()))
+	defer handlePanic(ctx, "UpdateClusterProfile", resp)
+
+	request := cluster.UpdateClusterProfileReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := c.clusterManager.UpdateClusterProfile(framework.NewBackgroundMicroCtx(ctx, false), request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
 func (c ClusterServiceHandler) ExportData(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
 	start := time.Now()
 	defer metrics.HandleClusterMetrics(start, "ExportData", int(resp.GetCode()))
@@ -889,6 +1655,96 @@ func (c ClusterServiceHandler) GetBackupStrategy(ctx context.Context, req *clust
 	return nil
 }
 
+func (c ClusterServiceHandler) VerifyBackup(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "VerifyBackup", int(resp.GetCode()))
+	defer handlePanic(ctx, "VerifyBackup", resp)
+
+	verifyReq := cluster.VerifyBackupReq{}
+
+	if handleRequest(ctx, req, resp, &verifyReq, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := c.brManager.VerifyBackup(framework.NewBackgroundMicroCtx(ctx, false), verifyReq)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (c ClusterServiceHandler) PruneBackupRecords(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "PruneBackupRecords", int(resp.GetCode()))
+	defer handlePanic(ctx, "PruneBackupRecords", resp)
+
+	pruneReq := cluster.PruneBackupRecordsReq{}
+
+	if handleRequest(ctx, req, resp, &pruneReq, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionDelete)}}) {
+		result, err := c.brManager.PruneBackupRecords(framework.NewBackgroundMicroCtx(ctx, false), pruneReq)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (c ClusterServiceHandler) StartLogBackup(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "StartLogBackup", int(resp.GetCode()))
+	defer handlePanic(ctx, "StartLogBackup", resp)
+
+	startReq := cluster.StartLogBackupReq{}
+
+	if handleRequest(ctx, req, resp, &startReq, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := c.brManager.StartLogBackup(framework.NewBackgroundMicroCtx(ctx, false), startReq)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (c ClusterServiceHandler) StopLogBackup(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "StopLogBackup", int(resp.GetCode()))
+	defer handlePanic(ctx, "StopLogBackup", resp)
+
+	stopReq := cluster.StopLogBackupReq{}
+
+	if handleRequest(ctx, req, resp, &stopReq, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := c.brManager.StopLogBackup(framework.NewBackgroundMicroCtx(ctx, false), stopReq)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (c ClusterServiceHandler) QueryLogBackup(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "QueryLogBackup", int(resp.GetCode()))
+	defer handlePanic(ctx, "QueryLogBackup", resp)
+
+	queryReq := cluster.QueryLogBackupReq{}
+
+	if handleRequest(ctx, req, resp, &queryReq, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead)}}) {
+		result, err := c.brManager.QueryLogBackup(framework.NewBackgroundMicroCtx(ctx, false), queryReq)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (c ClusterServiceHandler) TruncateLogBackup(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "TruncateLogBackup", int(resp.GetCode()))
+	defer handlePanic(ctx, "TruncateLogBackup", resp)
+
+	truncateReq := cluster.TruncateLogBackupReq{}
+
+	if handleRequest(ctx, req, resp, &truncateReq, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionDelete)}}) {
+		result, err := c.brManager.TruncateLogBackup(framework.NewBackgroundMicroCtx(ctx, false), truncateReq)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
 func (c ClusterServiceHandler) QueryBackupRecords(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) (err error) {
 	start := time.Now()
 	defer metrics.HandleClusterMetrics(start, "QueryBackupRecords", int(resp.GetCode()))
@@ -1001,6 +1857,51 @@ func (c *ClusterServiceHandler) StopFlow(ctx context.Context, request *clusterse
 	return nil
 }
 
+func (c *ClusterServiceHandler) RetryFlow(ctx context.Context, request *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "RetryFlow", int(resp.GetCode()))
+	defer handlePanic(ctx, "RetryFlow", resp)
+
+	retryReq := message.RetryWorkFlowReq{}
+	if handleRequest(ctx, request, resp, &retryReq, []structs.RbacPermission{{Resource: string(constants.RbacResourceWorkflow), Action: string(constants.RbacActionUpdate)}}) {
+		manager := workflow.GetWorkFlowService()
+		err := manager.Retry(framework.NewBackgroundMicroCtx(ctx, false), retryReq.WorkFlowID)
+		handleResponse(ctx, resp, err, message.RetryWorkFlowResp{}, nil)
+	}
+
+	return nil
+}
+
+func (c *ClusterServiceHandler) ResumeFlow(ctx context.Context, request *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "ResumeFlow", int(resp.GetCode()))
+	defer handlePanic(ctx, "ResumeFlow", resp)
+
+	resumeReq := message.ResumeWorkFlowReq{}
+	if handleRequest(ctx, request, resp, &resumeReq, []structs.RbacPermission{{Resource: string(constants.RbacResourceWorkflow), Action: string(constants.RbacActionUpdate)}}) {
+		manager := workflow.GetWorkFlowService()
+		err := manager.ResumeFromNode(framework.NewBackgroundMicroCtx(ctx, false), resumeReq.WorkFlowID, resumeReq.NodeName)
+		handleResponse(ctx, resp, err, message.ResumeWorkFlowResp{}, nil)
+	}
+
+	return nil
+}
+
+func (c *ClusterServiceHandler) AbandonFlow(ctx context.Context, request *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "AbandonFlow", int(resp.GetCode()))
+	defer handlePanic(ctx, "AbandonFlow", resp)
+
+	abandonReq := message.AbandonWorkFlowReq{}
+	if handleRequest(ctx, request, resp, &abandonReq, []structs.RbacPermission{{Resource: string(constants.RbacResourceWorkflow), Action: string(constants.RbacActionUpdate)}}) {
+		manager := workflow.GetWorkFlowService()
+		err := manager.Abandon(framework.NewBackgroundMicroCtx(ctx, false), abandonReq.WorkFlowID)
+		handleResponse(ctx, resp, err, message.AbandonWorkFlowResp{}, nil)
+	}
+
+	return nil
+}
+
 func (c *ClusterServiceHandler) Login(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
 	start := time.Now()
 	defer metrics.HandleClusterMetrics(start, "Login", int(resp.GetCode()))
@@ -1276,6 +2177,59 @@ func (handler *ClusterServiceHandler) UpdateHostStatus(ctx context.Context, req
 	return nil
 }
 
+func (handler *ClusterServiceHandler) DrainHost(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	metricsFuncName := "DrainHost"
+	defer metrics.HandleClusterMetrics(start, metricsFuncName, int(resp.GetCode()))
+	defer handlePanic(ctx, metricsFuncName, resp)
+
+	reqStruct := message.DrainHostReq{}
+
+	if handleRequest(ctx, req, resp, &reqStruct, []structs.RbacPermission{{Resource: string(constants.RbacResourceResource), Action: string(constants.RbacActionUpdate)}}) {
+		flowId, err := handler.resourceManager.DrainHost(framework.NewBackgroundMicroCtx(ctx, false), reqStruct.HostID)
+		var rsp message.DrainHostResp
+		if err == nil {
+			rsp.WorkFlowID = flowId
+		}
+		handleResponse(ctx, resp, err, rsp, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) QueryDrainHost(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	metricsFuncName := "QueryDrainHost"
+	defer metrics.HandleClusterMetrics(start, metricsFuncName, int(resp.GetCode()))
+	defer handlePanic(ctx, metricsFuncName, resp)
+
+	reqStruct := message.QueryDrainHostReq{}
+
+	if handleRequest(ctx, req, resp, &reqStruct, []structs.RbacPermission{{Resource: string(constants.RbacResourceResource), Action: string(constants.RbacActionRead)}}) {
+		rsp, err := handler.resourceManager.QueryDrainHost(framework.NewBackgroundMicroCtx(ctx, false), reqStruct.HostID)
+		handleResponse(ctx, resp, err, rsp, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) ReinstateHost(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	metricsFuncName := "ReinstateHost"
+	defer metrics.HandleClusterMetrics(start, metricsFuncName, int(resp.GetCode()))
+	defer handlePanic(ctx, metricsFuncName, resp)
+
+	reqStruct := message.ReinstateHostReq{}
+
+	if handleRequest(ctx, req, resp, &reqStruct, []structs.RbacPermission{{Resource: string(constants.RbacResourceResource), Action: string(constants.RbacActionUpdate)}}) {
+		err := handler.resourceManager.ReinstateHost(framework.NewBackgroundMicroCtx(ctx, false), reqStruct.HostID)
+		var rsp message.ReinstateHostResp
+		handleResponse(ctx, resp, err, rsp, nil)
+	}
+
+	return nil
+}
+
 func (handler *ClusterServiceHandler) UpdateHostInfo(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
 	start := time.Now()
 	metricsFuncName := "UpdateHostInfo"
@@ -1697,6 +2651,36 @@ func (handler *ClusterServiceHandler) QueryUpgradeVersionDiffInfo(ctx context.Co
 	return nil
 }
 
+func (handler *ClusterServiceHandler) RollbackUpgrade(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "RollbackUpgrade", int(resp.GetCode()))
+	defer handlePanic(ctx, "RollbackUpgrade", resp)
+
+	request := cluster.RollbackUpgradeReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := handler.clusterManager.RollbackUpgrade(ctx, request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) QueryUpgradeCheckReport(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "QueryUpgradeCheckReport", int(resp.GetCode()))
+	defer handlePanic(ctx, "QueryUpgradeCheckReport", resp)
+
+	request := cluster.QueryUpgradeCheckReportReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead)}}) {
+		result, err := handler.clusterManager.QueryUpgradeCheckReport(ctx, request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
 func (handler *ClusterServiceHandler) GetCheckReport(ctx context.Context, request *clusterservices.RpcRequest, response *clusterservices.RpcResponse) error {
 	start := time.Now()
 	defer metrics.HandleClusterMetrics(start, "GetCheckReport", int(response.GetCode()))
@@ -1723,3 +2707,67 @@ func (handler *ClusterServiceHandler) UpgradeCluster(ctx context.Context, req *c
 
 	return nil
 }
+
+func (handler *ClusterServiceHandler) CreateUpgradeCampaign(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "CreateUpgradeCampaign", int(resp.GetCode()))
+	defer handlePanic(ctx, "CreateUpgradeCampaign", resp)
+
+	request := cluster.CreateUpgradeCampaignReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := handler.clusterManager.CreateUpgradeCampaign(ctx, request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) QueryUpgradeCampaigns(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "QueryUpgradeCampaigns", int(resp.GetCode()))
+	defer handlePanic(ctx, "QueryUpgradeCampaigns", resp)
+
+	request := cluster.QueryUpgradeCampaignsReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead)}}) {
+		result, total, err := handler.clusterManager.QueryUpgradeCampaigns(ctx, request)
+		handleResponse(ctx, resp, err, result, &clusterservices.RpcPage{
+			Page:     int32(request.Page),
+			PageSize: int32(request.PageSize),
+			Total:    int32(total),
+		})
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) GetUpgradeCampaign(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "GetUpgradeCampaign", int(resp.GetCode()))
+	defer handlePanic(ctx, "GetUpgradeCampaign", resp)
+
+	request := cluster.GetUpgradeCampaignReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead)}}) {
+		result, err := handler.clusterManager.GetUpgradeCampaign(ctx, request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
+
+func (handler *ClusterServiceHandler) CancelUpgradeCampaign(ctx context.Context, req *clusterservices.RpcRequest, resp *clusterservices.RpcResponse) error {
+	start := time.Now()
+	defer metrics.HandleClusterMetrics(start, "CancelUpgradeCampaign", int(resp.GetCode()))
+	defer handlePanic(ctx, "CancelUpgradeCampaign", resp)
+
+	request := cluster.CancelUpgradeCampaignReq{}
+
+	if handleRequest(ctx, req, resp, &request, []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}) {
+		result, err := handler.clusterManager.CancelUpgradeCampaign(ctx, request)
+		handleResponse(ctx, resp, err, result, nil)
+	}
+
+	return nil
+}
@@ -60,6 +60,14 @@ func Test_handleRequest(t *testing.T) {
 	})
 }
 
+func Test_getRequestClusterIDs(t *testing.T) {
+	assert.Equal(t, []string{"cluster01"}, getRequestClusterIDs(`{"clusterId": "cluster01", "name": "aaa"}`))
+	assert.Equal(t, []string{"cluster01", "cluster02"}, getRequestClusterIDs(`{"sourceClusterID": "cluster01", "targetClusterID": "cluster02"}`))
+	assert.Empty(t, getRequestClusterIDs(`{"clusterId": "", "clusterIds": ["cluster01"]}`))
+	assert.Empty(t, getRequestClusterIDs(`{"clusterId": 1}`))
+	assert.Empty(t, getRequestClusterIDs(`[]`))
+}
+
 func Test_handleResponse(t *testing.T) {
 	t.Run("normal", func(t *testing.T) {
 		resp := &clusterservices.RpcResponse{}
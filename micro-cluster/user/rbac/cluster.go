@@ -0,0 +1,71 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ *  Unless required by applicable law or agreed to in writing, software       *
+ *  distributed under the License is distributed on an "AS IS" BASIS,         *
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  *
+ *  See the License for the specific language governing permissions and       *
+ *  limitations under the License.                                            *
+ ******************************************************************************/
+
+package rbac
+
+import (
+	"context"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/message"
+)
+
+// HasClusterPermission
+// @Description: check whether current user has permission of action on the cluster,
+// requests without user, such as internal tasks, are always allowed
+// @Parameter ctx
+// @Parameter action
+// @Parameter instances rbac instances of the cluster, nil means all clusters
+// @return bool
+// @return error
+func HasClusterPermission(ctx context.Context, action constants.RbacAction, instances []string) (bool, error) {
+	userID := framework.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return true, nil
+	}
+	resp, err := GetRBACService().CheckPermissionForInstances(ctx, message.CheckPermissionForInstancesReq{
+		UserID:    userID,
+		Resource:  string(constants.RbacResourceCluster),
+		Action:    string(action),
+		Instances: instances,
+	})
+	if err != nil {
+		return false, err
+	}
+	return resp.Result, nil
+}
+
+// CheckClusterPermission
+// @Description: deny the request if current user has no permission of action on the cluster
+// @Parameter ctx
+// @Parameter clusterID
+// @Parameter tags
+// @Parameter tenantID
+// @Parameter action
+// @return error
+func CheckClusterPermission(ctx context.Context, clusterID string, tags []string, tenantID string, action constants.RbacAction) error {
+	allowed, err := HasClusterPermission(ctx, action, ClusterPermissionInstances(clusterID, tags, tenantID))
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("check permission %s of cluster %s failed, %s", action, clusterID, err.Error())
+		return err
+	}
+	if !allowed {
+		return errors.NewErrorf(errors.TIUNIMANAGER_RBAC_PERMISSION_DENIED,
+			"user %s has no permission %s of cluster %s", framework.GetUserIDFromContext(ctx), action, clusterID)
+	}
+	return nil
+}
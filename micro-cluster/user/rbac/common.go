@@ -15,8 +15,125 @@
 
 package rbac
 
+import (
+	"fmt"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/structs"
+)
+
 const (
 	ObjectIndex   int = 0
 	ResourceIndex int = 1
 	ActionIndex   int = 2
 )
+
+// instanceMatch
+// @Description: whether the object matches the object pattern of a policy, eg. CLUSTER/tag/team=dba matches CLUSTER/tag/team=*.
+// Unlike path glob, "*" matches any characters including the separator, so CLUSTER/* grants clusters of any instance scope,
+// and a tag containing "/" is matched by CLUSTER/tag/*. "?" matches any single character
+// @Parameter object
+// @Parameter pattern
+// @return bool
+func instanceMatch(object string, pattern string) bool {
+	o, p := []rune(object), []rune(pattern)
+	i, j := 0, 0
+	// position of the last "*" in pattern and the position in object it starts to match from
+	star, from := -1, 0
+	for i < len(o) {
+		if j < len(p) && (p[j] == '?' || p[j] == o[i]) {
+			i++
+			j++
+		} else if j < len(p) && p[j] == '*' {
+			star, from = j, i
+			j++
+		} else if star >= 0 {
+			// let the last "*" match one more character
+			from++
+			i, j = from, star+1
+		} else {
+			return false
+		}
+	}
+	for j < len(p) && p[j] == '*' {
+		j++
+	}
+	return j == len(p)
+}
+
+// instanceMatchFunc instanceMatch as a function of casbin matcher
+func instanceMatchFunc(args ...interface{}) (interface{}, error) {
+	if len(args) != 2 {
+		return false, fmt.Errorf("instanceMatch expects 2 arguments, got %d", len(args))
+	}
+	object, ok := args[0].(string)
+	if !ok {
+		return false, fmt.Errorf("object %v of instanceMatch is not a string", args[0])
+	}
+	pattern, ok := args[1].(string)
+	if !ok {
+		return false, fmt.Errorf("pattern %v of instanceMatch is not a string", args[1])
+	}
+	return instanceMatch(object, pattern), nil
+}
+
+// ClusterPermissionInstances
+// @Description: rbac instances of a cluster, cluster permission can be granted by cluster id, tag or tenant
+// @Parameter clusterID
+// @Parameter tags
+// @Parameter tenantID
+// @return []string
+func ClusterPermissionInstances(clusterID string, tags []string, tenantID string) []string {
+	instances := []string{
+		string(constants.RbacInstanceScopeID) + constants.RbacInstanceSeparator + clusterID,
+	}
+	for _, tag := range tags {
+		instances = append(instances, string(constants.RbacInstanceScopeTag)+constants.RbacInstanceSeparator+tag)
+	}
+	if tenantID != "" {
+		instances = append(instances, string(constants.RbacInstanceScopeTenant)+constants.RbacInstanceSeparator+tenantID)
+	}
+	return instances
+}
+
+// defaultRolePermissions permissions of built-in roles,
+// admin can do anything, read-only can read all resources except users,
+// operator can also create and update clusters, cdc tasks, parameters and workflows, but never delete them
+var defaultRolePermissions = map[constants.RbacRole][]structs.RbacPermission{
+	constants.RbacRoleAdmin: {
+		{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionAll)},
+		{Resource: string(constants.RbacResourceResource), Action: string(constants.RbacActionAll)},
+		{Resource: string(constants.RbacResourceParameter), Action: string(constants.RbacActionAll)},
+		{Resource: string(constants.RbacResourceUser), Action: string(constants.RbacActionAll)},
+		{Resource: string(constants.RbacResourceCDC), Action: string(constants.RbacActionAll)},
+		{Resource: string(constants.RbacResourceProduct), Action: string(constants.RbacActionAll)},
+		{Resource: string(constants.RbacResourceSystem), Action: string(constants.RbacActionAll)},
+		{Resource: string(constants.RbacResourceWorkflow), Action: string(constants.RbacActionAll)},
+	},
+	constants.RbacRoleOperator: {
+		{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead)},
+		{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionCreate)},
+		{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)},
+		{Resource: string(constants.RbacResourceCDC), Action: string(constants.RbacActionRead)},
+		{Resource: string(constants.RbacResourceCDC), Action: string(constants.RbacActionCreate)},
+		{Resource: string(constants.RbacResourceCDC), Action: string(constants.RbacActionUpdate)},
+		{Resource: string(constants.RbacResourceParameter), Action: string(constants.RbacActionRead)},
+		{Resource: string(constants.RbacResourceParameter), Action: string(constants.RbacActionCreate)},
+		{Resource: string(constants.RbacResourceParameter), Action: string(constants.RbacActionUpdate)},
+		{Resource: string(constants.RbacResourceWorkflow), Action: string(constants.RbacActionRead)},
+		{Resource: string(constants.RbacResourceWorkflow), Action: string(constants.RbacActionCreate)},
+		{Resource: string(constants.RbacResourceWorkflow), Action: string(constants.RbacActionUpdate)},
+		{Resource: string(constants.RbacResourceResource), Action: string(constants.RbacActionRead)},
+		{Resource: string(constants.RbacResourceProduct), Action: string(constants.RbacActionRead)},
+		{Resource: string(constants.RbacResourceSystem), Action: string(constants.RbacActionRead)},
+	},
+	constants.RbacRoleReadOnly: {
+		{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead)},
+		{Resource: string(constants.RbacResourceResource), Action: string(constants.RbacActionRead)},
+		{Resource: string(constants.RbacResourceParameter), Action: string(constants.RbacActionRead)},
+		{Resource: string(constants.RbacResourceCDC), Action: string(constants.RbacActionRead)},
+		{Resource: string(constants.RbacResourceProduct), Action: string(constants.RbacActionRead)},
+		{Resource: string(constants.RbacResourceSystem), Action: string(constants.RbacActionRead)},
+		{Resource: string(constants.RbacResourceWorkflow), Action: string(constants.RbacActionRead)},
+	},
+}
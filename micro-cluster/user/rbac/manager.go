@@ -52,7 +52,8 @@ func NewRBACManager() *RBACManager {
 	m.AddDef("p", "p", "sub, obj, act")
 	m.AddDef("g", "g", "_, _")
 	m.AddDef("e", "e", "some(where (p.eft == allow))")
-	m.AddDef("m", "m", "g(r.sub, p.sub) && r.obj == p.obj && (r.act == p.act || p.act == \"*\")")
+	// object of policy is resource or resource with instance pattern, eg. CLUSTER/id/cluster*, see instanceMatch
+	m.AddDef("m", "m", "g(r.sub, p.sub) && instanceMatch(r.obj, p.obj) && (r.act == p.act || p.act == \"*\")")
 	adapter, err := models.GetRBACReaderWriter().GetRBACAdapter(context.Background())
 	if err != nil {
 		framework.LogWithContext(context.Background()).Fatalf("get casbin gorm adapter failed, %s", err.Error())
@@ -63,6 +64,7 @@ func NewRBACManager() *RBACManager {
 		framework.LogWithContext(context.Background()).Fatalf("new casbin enforcer failed, %s", err.Error())
 		return nil
 	}
+	e.AddFunction("instanceMatch", instanceMatchFunc)
 	if err := e.LoadPolicy(); err != nil {
 		framework.LogWithContext(context.Background()).Fatalf("load rbac policy failed, %s", err.Error())
 		return nil
@@ -75,63 +77,17 @@ func NewRBACManager() *RBACManager {
 }
 
 /*
-	1. init role and permission
+	1. init role and permission of admin, operator and read-only role
 	2. bind admin role for admin user
 */
 func (mgr *RBACManager) initDefaultRBAC(ctx context.Context) {
 	framework.LogWithContext(ctx).Infof("begin init default rbac...")
 	// 1. init role and permission
-	// init admin role
-	framework.LogWithContext(ctx).Infof("begin init default rbac role %s ...", constants.RbacRoleAdmin)
-	mgr.CreateRole(ctx, message.CreateRoleReq{Role: string(constants.RbacRoleAdmin)}, true)
-	mgr.AddPermissionsForRole(ctx, message.AddPermissionsForRoleReq{Role: string(constants.RbacRoleAdmin), Permissions: []structs.RbacPermission{
-		{
-			Resource: string(constants.RbacResourceCluster),
-			Action:   string(constants.RbacActionAll),
-		},
-	}}, true)
-	mgr.AddPermissionsForRole(ctx, message.AddPermissionsForRoleReq{Role: string(constants.RbacRoleAdmin), Permissions: []structs.RbacPermission{
-		{
-			Resource: string(constants.RbacResourceResource),
-			Action:   string(constants.RbacActionAll),
-		},
-	}}, true)
-	mgr.AddPermissionsForRole(ctx, message.AddPermissionsForRoleReq{Role: string(constants.RbacRoleAdmin), Permissions: []structs.RbacPermission{
-		{
-			Resource: string(constants.RbacResourceParameter),
-			Action:   string(constants.RbacActionAll),
-		},
-	}}, true)
-	mgr.AddPermissionsForRole(ctx, message.AddPermissionsForRoleReq{Role: string(constants.RbacRoleAdmin), Permissions: []structs.RbacPermission{
-		{
-			Resource: string(constants.RbacResourceUser),
-			Action:   string(constants.RbacActionAll),
-		},
-	}}, true)
-	mgr.AddPermissionsForRole(ctx, message.AddPermissionsForRoleReq{Role: string(constants.RbacRoleAdmin), Permissions: []structs.RbacPermission{
-		{
-			Resource: string(constants.RbacResourceCDC),
-			Action:   string(constants.RbacActionAll),
-		},
-	}}, true)
-	mgr.AddPermissionsForRole(ctx, message.AddPermissionsForRoleReq{Role: string(constants.RbacRoleAdmin), Permissions: []structs.RbacPermission{
-		{
-			Resource: string(constants.RbacResourceProduct),
-			Action:   string(constants.RbacActionAll),
-		},
-	}}, true)
-	mgr.AddPermissionsForRole(ctx, message.AddPermissionsForRoleReq{Role: string(constants.RbacRoleAdmin), Permissions: []structs.RbacPermission{
-		{
-			Resource: string(constants.RbacResourceSystem),
-			Action:   string(constants.RbacActionAll),
-		},
-	}}, true)
-	mgr.AddPermissionsForRole(ctx, message.AddPermissionsForRoleReq{Role: string(constants.RbacRoleAdmin), Permissions: []structs.RbacPermission{
-		{
-			Resource: string(constants.RbacResourceWorkflow),
-			Action:   string(constants.RbacActionAll),
-		},
-	}}, true)
+	for _, role := range []constants.RbacRole{constants.RbacRoleAdmin, constants.RbacRoleOperator, constants.RbacRoleReadOnly} {
+		framework.LogWithContext(ctx).Infof("begin init default rbac role %s ...", role)
+		mgr.CreateRole(ctx, message.CreateRoleReq{Role: string(role)}, true)
+		mgr.AddPermissionsForRole(ctx, message.AddPermissionsForRoleReq{Role: string(role), Permissions: defaultRolePermissions[role]}, true)
+	}
 
 	// 2. bind admin role for admin user
 	// todo: replace new account
@@ -149,11 +105,24 @@ func (mgr *RBACManager) CheckPermissionForUser(ctx context.Context, request mess
 	framework.LogWithContext(ctx).Info("end CheckPermissionForUser")
 
 	for _, permission := range request.Permissions {
-		result, err := mgr.enforcer.Enforce(request.UserID, permission.Resource, permission.Action)
+		result, err := mgr.enforcer.Enforce(request.UserID, permission.GetObject(), permission.Action)
 		if err != nil {
 			framework.LogWithContext(ctx).Errorf("user %s check permission error: %s", request.UserID, err.Error())
 			return message.CheckPermissionForUserResp{Result: false}, errors.WrapError(errors.TIUNIMANAGER_RBAC_PERMISSION_CHECK_FAILED, fmt.Sprintf("user %s check permission failed", request.UserID), err)
 		}
+		if !result && permission.Instance == "" && permission.Resource == string(constants.RbacResourceCluster) {
+			// permission granted on some clusters never satisfies the resource-level check,
+			// unless the request is scoped to those clusters, or it filters clusters by itself
+			if len(request.ClusterIDs) > 0 {
+				result, err = mgr.hasClustersPermission(ctx, request.UserID, permission.Action, request.ClusterIDs)
+			} else if request.AnyInstance {
+				result, err = mgr.hasInstancePermission(request.UserID, permission.Resource, permission.Action)
+			}
+			if err != nil {
+				framework.LogWithContext(ctx).Errorf("user %s check instance permission error: %s", request.UserID, err.Error())
+				return message.CheckPermissionForUserResp{Result: false}, errors.WrapError(errors.TIUNIMANAGER_RBAC_PERMISSION_CHECK_FAILED, fmt.Sprintf("user %s check permission failed", request.UserID), err)
+			}
+		}
 		if !result {
 			framework.LogWithContext(ctx).Infof("user %s check permission %+v failed", request.UserID, permission)
 			return message.CheckPermissionForUserResp{Result: false}, nil
@@ -162,6 +131,82 @@ func (mgr *RBACManager) CheckPermissionForUser(ctx context.Context, request mess
 	return message.CheckPermissionForUserResp{Result: true}, nil
 }
 
+// hasClustersPermission
+// @Description: whether user has permission of action on all the clusters, granted by cluster id, tag or tenant
+// @Receiver mgr
+// @Parameter ctx
+// @Parameter userID
+// @Parameter action
+// @Parameter clusterIDs
+// @return bool
+// @return error
+func (mgr *RBACManager) hasClustersPermission(ctx context.Context, userID string, action string, clusterIDs []string) (bool, error) {
+	for _, clusterID := range clusterIDs {
+		cluster, err := models.GetClusterReaderWriter().Get(ctx, clusterID)
+		if err != nil {
+			// cluster does not exist, it can not be granted
+			framework.LogWithContext(ctx).Warnf("get cluster %s for permission check failed, %s", clusterID, err.Error())
+			return false, nil
+		}
+		resp, err := mgr.CheckPermissionForInstances(ctx, message.CheckPermissionForInstancesReq{
+			UserID:    userID,
+			Resource:  string(constants.RbacResourceCluster),
+			Action:    action,
+			Instances: ClusterPermissionInstances(cluster.ID, cluster.Tags, cluster.TenantId),
+		})
+		if err != nil || !resp.Result {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// hasInstancePermission
+// @Description: whether user has permission of any instance of the resource
+// @Receiver mgr
+// @Parameter userID
+// @Parameter resource
+// @Parameter action
+// @return bool
+// @return error
+func (mgr *RBACManager) hasInstancePermission(userID string, resource string, action string) (bool, error) {
+	policies, err := mgr.enforcer.GetImplicitPermissionsForUser(userID)
+	if err != nil {
+		return false, err
+	}
+	for _, policy := range policies {
+		permission := structs.NewRbacPermissionFromObject(policy[ResourceIndex], policy[ActionIndex])
+		if permission.Resource == resource && permission.Instance != "" &&
+			(permission.Action == action || permission.Action == string(constants.RbacActionAll)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (mgr *RBACManager) CheckPermissionForInstances(ctx context.Context, request message.CheckPermissionForInstancesReq) (resp message.CheckPermissionForInstancesResp, err error) {
+	framework.LogWithContext(ctx).Infof("begin CheckPermissionForInstances, request: %+v", request)
+	framework.LogWithContext(ctx).Info("end CheckPermissionForInstances")
+
+	objects := make([]string, 0, len(request.Instances)+1)
+	objects = append(objects, request.Resource)
+	for _, instance := range request.Instances {
+		objects = append(objects, request.Resource+constants.RbacInstanceSeparator+instance)
+	}
+	for _, object := range objects {
+		result, err := mgr.enforcer.Enforce(request.UserID, object, request.Action)
+		if err != nil {
+			framework.LogWithContext(ctx).Errorf("user %s check permission of %s error: %s", request.UserID, object, err.Error())
+			return resp, errors.WrapError(errors.TIUNIMANAGER_RBAC_PERMISSION_CHECK_FAILED, fmt.Sprintf("user %s check permission failed", request.UserID), err)
+		}
+		if result {
+			return message.CheckPermissionForInstancesResp{Result: true}, nil
+		}
+	}
+	framework.LogWithContext(ctx).Infof("user %s has no permission %s of %s instances %v", request.UserID, request.Action, request.Resource, request.Instances)
+	return message.CheckPermissionForInstancesResp{Result: false}, nil
+}
+
 func (mgr *RBACManager) QueryRoles(ctx context.Context, request message.QueryRolesReq) (resp message.QueryRolesResp, err error) {
 	framework.LogWithContext(ctx).Infof("begin QueryRoles, request: %+v", request)
 	framework.LogWithContext(ctx).Info("end QueryRoles")
@@ -291,7 +336,7 @@ func (mgr *RBACManager) AddPermissionsForRole(ctx context.Context, request messa
 			framework.LogWithContext(ctx).Errorf(err.Error())
 			return resp, errors.NewErrorf(errors.TIUNIMANAGER_PARAMETER_INVALID, err.Error())
 		}
-		permissionList = append(permissionList, []string{permission.Resource, permission.Action})
+		permissionList = append(permissionList, []string{permission.GetObject(), permission.Action})
 	}
 
 	if _, err = mgr.enforcer.AddPermissionsForUser(request.Role, permissionList...); err != nil {
@@ -321,7 +366,7 @@ func (mgr *RBACManager) DeletePermissionsForRole(ctx context.Context, request me
 		}
 	}
 	for _, permission := range request.Permissions {
-		if _, err = mgr.enforcer.DeletePermissionForUser(request.Role, permission.Resource, permission.Action); err != nil {
+		if _, err = mgr.enforcer.DeletePermissionForUser(request.Role, permission.GetObject(), permission.Action); err != nil {
 			framework.LogWithContext(ctx).Errorf("call enforcer DeletePermissionForUser failed %s", err.Error())
 			return resp, errors.WrapError(errors.TIUNIMANAGER_RBAC_PERMISSION_DELETE_FAILED, fmt.Sprintf("delete permissions of role %s failed", request.Role), err)
 		}
@@ -346,10 +391,7 @@ func (mgr *RBACManager) QueryPermissionsForUser(ctx context.Context, request mes
 
 		resp.UserID = request.UserID
 		for index := 0; index < len(rbacPermissions); index++ {
-			permission := structs.RbacPermission{
-				Resource: rbacPermissions[index][ResourceIndex],
-				Action:   rbacPermissions[index][ActionIndex],
-			}
+			permission := structs.NewRbacPermissionFromObject(rbacPermissions[index][ResourceIndex], rbacPermissions[index][ActionIndex])
 			resp.Permissions = append(resp.Permissions, permission)
 		}
 	}
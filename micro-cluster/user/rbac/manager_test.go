@@ -17,12 +17,16 @@ package rbac
 
 import (
 	"context"
+	"errors"
 	"github.com/golang/mock/gomock"
 	"github.com/pingcap/tiunimanager/common/constants"
 	"github.com/pingcap/tiunimanager/common/structs"
 	"github.com/pingcap/tiunimanager/message"
 	"github.com/pingcap/tiunimanager/models"
+	"github.com/pingcap/tiunimanager/models/cluster/management"
+	"github.com/pingcap/tiunimanager/models/common"
 	mock_account "github.com/pingcap/tiunimanager/test/mockmodels/mockaccount"
+	"github.com/pingcap/tiunimanager/test/mockmodels/mockclustermanagement"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -191,6 +195,126 @@ func TestRBACManager_CheckPermissionForUser_case2(t *testing.T) {
 	assert.Nil(t, errDelRole)
 }
 
+func TestRBACManager_DefaultRoles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	accountRW := mock_account.NewMockReaderWriter(ctrl)
+	models.SetAccountReaderWriter(accountRW)
+	accountRW.EXPECT().GetUserByName(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+	rbacService := GetRBACService()
+	_, errBind := rbacService.BindRolesForUser(context.TODO(), message.BindRolesForUserReq{UserID: "operator", Roles: []string{string(constants.RbacRoleOperator)}})
+	assert.Nil(t, errBind)
+	_, errBind = rbacService.BindRolesForUser(context.TODO(), message.BindRolesForUserReq{UserID: "viewer", Roles: []string{string(constants.RbacRoleReadOnly)}})
+	assert.Nil(t, errBind)
+
+	check := func(userID string, resource constants.RbacResource, action constants.RbacAction) bool {
+		resp, err := rbacService.CheckPermissionForUser(context.TODO(), message.CheckPermissionForUserReq{UserID: userID, Permissions: []structs.RbacPermission{{Resource: string(resource), Action: string(action)}}})
+		assert.Nil(t, err)
+		return resp.Result
+	}
+	assert.True(t, check("operator", constants.RbacResourceCluster, constants.RbacActionUpdate))
+	assert.False(t, check("operator", constants.RbacResourceCluster, constants.RbacActionDelete))
+	assert.False(t, check("operator", constants.RbacResourceUser, constants.RbacActionRead))
+	assert.True(t, check("viewer", constants.RbacResourceResource, constants.RbacActionRead))
+	assert.False(t, check("viewer", constants.RbacResourceCluster, constants.RbacActionCreate))
+
+	_, errDelete := rbacService.DeleteRole(context.TODO(), message.DeleteRoleReq{Role: string(constants.RbacRoleReadOnly)}, false)
+	assert.NotNil(t, errDelete)
+}
+
+func TestRBACManager_CheckPermissionForInstances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	accountRW := mock_account.NewMockReaderWriter(ctrl)
+	models.SetAccountReaderWriter(accountRW)
+	accountRW.EXPECT().GetUserByName(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+	rbacService := GetRBACService()
+	_, errCreate := rbacService.CreateRole(context.TODO(), message.CreateRoleReq{Role: "dbarole"}, false)
+	assert.Nil(t, errCreate)
+	_, errAdd := rbacService.AddPermissionsForRole(context.TODO(), message.AddPermissionsForRoleReq{Role: "dbarole", Permissions: []structs.RbacPermission{
+		{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionAll), Instance: "tag/team=dba*"},
+		{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead), Instance: "id/shared-*"},
+	}}, false)
+	assert.Nil(t, errAdd)
+	_, errBind := rbacService.BindRolesForUser(context.TODO(), message.BindRolesForUserReq{UserID: "dba", Roles: []string{"dbarole"}})
+	assert.Nil(t, errBind)
+
+	check := func(action constants.RbacAction, instances ...string) bool {
+		resp, err := rbacService.CheckPermissionForInstances(context.TODO(), message.CheckPermissionForInstancesReq{
+			UserID: "dba", Resource: string(constants.RbacResourceCluster), Action: string(action), Instances: instances,
+		})
+		assert.Nil(t, err)
+		return resp.Result
+	}
+	assert.True(t, check(constants.RbacActionDelete, "id/cluster01", "tag/team=dba01"))
+	assert.True(t, check(constants.RbacActionRead, "id/shared-cluster"))
+	assert.False(t, check(constants.RbacActionUpdate, "id/shared-cluster"))
+	assert.False(t, check(constants.RbacActionRead, "id/cluster02", "tag/team=dev"))
+
+	t.Run("wildcard grant", func(t *testing.T) {
+		_, errCreate := rbacService.CreateRole(context.TODO(), message.CreateRoleReq{Role: "auditrole"}, false)
+		assert.Nil(t, errCreate)
+		_, errAdd := rbacService.AddPermissionsForRole(context.TODO(), message.AddPermissionsForRoleReq{Role: "auditrole", Permissions: []structs.RbacPermission{
+			{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead), Instance: "*"},
+			{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate), Instance: "tag/*"},
+		}}, false)
+		assert.Nil(t, errAdd)
+		_, errBind := rbacService.BindRolesForUser(context.TODO(), message.BindRolesForUserReq{UserID: "auditor", Roles: []string{"auditrole"}})
+		assert.Nil(t, errBind)
+
+		checkAuditor := func(action constants.RbacAction, instances ...string) bool {
+			resp, err := rbacService.CheckPermissionForInstances(context.TODO(), message.CheckPermissionForInstancesReq{
+				UserID: "auditor", Resource: string(constants.RbacResourceCluster), Action: string(action), Instances: instances,
+			})
+			assert.Nil(t, err)
+			return resp.Result
+		}
+		// "*" matches across "/", the grant covers clusters of any scope
+		assert.True(t, checkAuditor(constants.RbacActionRead, "tag/k=v"))
+		assert.True(t, checkAuditor(constants.RbacActionRead, "id/cluster03"))
+		assert.True(t, checkAuditor(constants.RbacActionUpdate, "id/cluster03", "tag/app/name=x"))
+		assert.False(t, checkAuditor(constants.RbacActionUpdate, "id/cluster03", "tenant/tenant01"))
+		assert.False(t, checkAuditor(constants.RbacActionDelete, "tag/k=v"))
+	})
+
+	clusterRW := mockclustermanagement.NewMockReaderWriter(ctrl)
+	models.SetClusterReaderWriter(clusterRW)
+	clusterRW.EXPECT().Get(gomock.Any(), "cluster01").Return(&management.Cluster{
+		Entity: common.Entity{ID: "cluster01", TenantId: "tenant01"},
+		Tags:   []string{"team=dba01"},
+	}, nil).AnyTimes()
+	clusterRW.EXPECT().Get(gomock.Any(), "cluster02").Return(&management.Cluster{
+		Entity: common.Entity{ID: "cluster02", TenantId: "tenant01"},
+		Tags:   []string{"team=dev"},
+	}, nil).AnyTimes()
+	clusterRW.EXPECT().Get(gomock.Any(), "cluster03").Return(nil, errors.New("not found")).AnyTimes()
+
+	checkUser := func(request message.CheckPermissionForUserReq) bool {
+		request.UserID = "dba"
+		request.Permissions = []structs.RbacPermission{{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionUpdate)}}
+		resp, err := rbacService.CheckPermissionForUser(context.TODO(), request)
+		assert.Nil(t, err)
+		return resp.Result
+	}
+	// permission of some clusters never passes resource-level check
+	assert.False(t, checkUser(message.CheckPermissionForUserReq{}))
+	// but passes if all the clusters operated by request are permitted
+	assert.True(t, checkUser(message.CheckPermissionForUserReq{ClusterIDs: []string{"cluster01"}}))
+	assert.False(t, checkUser(message.CheckPermissionForUserReq{ClusterIDs: []string{"cluster02"}}))
+	assert.False(t, checkUser(message.CheckPermissionForUserReq{ClusterIDs: []string{"cluster01", "cluster02"}}))
+	assert.False(t, checkUser(message.CheckPermissionForUserReq{ClusterIDs: []string{"cluster03"}}))
+	// or request filters clusters by itself
+	assert.True(t, checkUser(message.CheckPermissionForUserReq{AnyInstance: true}))
+
+	query, errQuery := rbacService.QueryPermissionsForUser(context.TODO(), message.QueryPermissionsForUserReq{UserID: "dba"})
+	assert.Nil(t, errQuery)
+	assert.Contains(t, query.Permissions, structs.RbacPermission{Resource: string(constants.RbacResourceCluster), Action: string(constants.RbacActionRead), Instance: "id/shared-*"})
+}
+
 func TestRBACManager_QueryPermissionsForUser(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -216,6 +340,11 @@ func TestRBACManager_QueryPermissionsForUser(t *testing.T) {
 	assert.Equal(t, string(constants.RbacActionAll), resp.Permissions[0].Action)
 }
 
+func TestClusterPermissionInstances(t *testing.T) {
+	assert.Equal(t, []string{"id/cluster01", "tag/team=dba", "tenant/tenant01"}, ClusterPermissionInstances("cluster01", []string{"team=dba"}, "tenant01"))
+	assert.Equal(t, []string{"id/cluster01"}, ClusterPermissionInstances("cluster01", nil, ""))
+}
+
 func checkContainRole(role string, roles []string) bool {
 	for _, r := range roles {
 		if r == role {
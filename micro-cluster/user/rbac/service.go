@@ -31,6 +31,15 @@ type RBACService interface {
 	// @Return error
 	CheckPermissionForUser(ctx context.Context, request message.CheckPermissionForUserReq) (resp message.CheckPermissionForUserResp, err error)
 
+	// CheckPermissionForInstances
+	// @Description: check permission for user on instances of resource, permission of the whole resource or any one of the instances is enough
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter request
+	// @Return message.CheckPermissionForInstancesResp
+	// @Return error
+	CheckPermissionForInstances(ctx context.Context, request message.CheckPermissionForInstancesReq) (resp message.CheckPermissionForInstancesResp, err error)
+
 	// DeleteRole
 	// @Description: delete rbac role
 	// @Receiver m
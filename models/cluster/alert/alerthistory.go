@@ -0,0 +1,35 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package alert
+
+import (
+	"time"
+
+	"github.com/pingcap/tiunimanager/models/common"
+)
+
+// AlertHistory alert which has been firing in cluster, status is firing or resolved
+type AlertHistory struct {
+	common.Entity
+	ClusterID   string    `gorm:"not null;size:32;index;comment:'cluster id'"`
+	Fingerprint string    `gorm:"not null;size:64;index;comment:'fingerprint of alert in alertmanager'"`
+	Name        string    `gorm:"not null;size:64;comment:'alert name'"`
+	Severity    string    `gorm:"size:32"`
+	Summary     string    `gorm:"type:text"`
+	Labels      string    `gorm:"type:text;comment:'json of alert labels'"`
+	StartsAt    time.Time `gorm:"default:null"`
+	EndsAt      time.Time `gorm:"default:null"`
+}
@@ -0,0 +1,31 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package alert
+
+import (
+	"time"
+
+	"github.com/pingcap/tiunimanager/models/common"
+)
+
+// AlertReceiver token which authenticates alertmanager of a cluster to the receiver of the platform, kept encrypted
+type AlertReceiver struct {
+	ClusterID string          `gorm:"primaryKey;size:32;comment:'cluster id'"`
+	TenantId  string          `gorm:"size:32"`
+	Token     common.Password `gorm:"size:256;comment:'bearer token of alertmanager'"`
+	CreatedAt time.Time       `gorm:"autoCreateTime;<-:create;->;"`
+	UpdatedAt time.Time       `gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,33 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package alert
+
+import (
+	"github.com/pingcap/tiunimanager/models/common"
+)
+
+// AlertRule alert rule of cluster, status is Enabled or Disabled, enabled rules are deployed to prometheus of the cluster
+type AlertRule struct {
+	common.Entity
+	ClusterID string `gorm:"not null;size:32;index;comment:'cluster id'"`
+	Name      string `gorm:"not null;size:64;comment:'alert name, unique in cluster'"`
+	Type      string `gorm:"not null;size:32;comment:'BuiltIn/Custom'"`
+	Component string `gorm:"size:32;comment:'component which the rule is about'"`
+	Expr      string `gorm:"not null;type:text;comment:'PromQL expression'"`
+	Duration  string `gorm:"size:32;comment:'duration of expression being true before firing'"`
+	Severity  string `gorm:"not null;size:32;comment:'emergency/critical/warning'"`
+	Summary   string `gorm:"type:text"`
+}
@@ -14,3 +14,163 @@
  ******************************************************************************/
 
 package alert
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+)
+
+type ReaderWriter interface {
+	// CreateRules
+	// @Description: create alert rules of the same cluster in one transaction, names of rules are unique in cluster
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter rules
+	// @return error if any name is duplicated
+	CreateRules(ctx context.Context, rules []*AlertRule) error
+
+	// GetRule
+	// @Description: get alert rule of cluster
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter clusterID
+	// @Parameter ruleID
+	// @return *AlertRule
+	// @return error if rule non-existent
+	GetRule(ctx context.Context, clusterID string, ruleID string) (*AlertRule, error)
+
+	// QueryRules
+	// @Description: query alert rules of cluster in order of name, empty ruleType or status means all
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter clusterID
+	// @Parameter ruleType
+	// @Parameter status
+	// @Parameter offset
+	// @Parameter length
+	// @return rules
+	// @return total
+	// @return err
+	QueryRules(ctx context.Context, clusterID string, ruleType constants.AlertRuleType, status constants.AlertRuleStatus, offset int, length int) (rules []*AlertRule, total int64, err error)
+
+	// UpdateRule
+	// @Description: update expression, duration, severity, summary and status of alert rule
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter rule
+	// @return error if rule non-existent
+	UpdateRule(ctx context.Context, rule *AlertRule) error
+
+	// DeleteRule
+	// @Description: delete alert rule of cluster
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter clusterID
+	// @Parameter ruleID
+	// @return error if rule non-existent
+	DeleteRule(ctx context.Context, clusterID string, ruleID string) error
+
+	// CreateSilence
+	// @Description: create alert silence with status Active
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter silence
+	// @return *AlertSilence
+	// @return error
+	CreateSilence(ctx context.Context, silence *AlertSilence) (*AlertSilence, error)
+
+	// GetSilence
+	// @Description: get alert silence of cluster
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter clusterID
+	// @Parameter id
+	// @return *AlertSilence
+	// @return error if silence non-existent
+	GetSilence(ctx context.Context, clusterID string, id string) (*AlertSilence, error)
+
+	// QuerySilences
+	// @Description: query alert silences of cluster in order of creation desc, empty status means all
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter clusterID
+	// @Parameter status
+	// @Parameter offset
+	// @Parameter length
+	// @return silences
+	// @return total
+	// @return err
+	QuerySilences(ctx context.Context, clusterID string, status constants.AlertSilenceStatus, offset int, length int) (silences []*AlertSilence, total int64, err error)
+
+	// UpdateSilenceStatus
+	// @Description: update status of alert silence
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter id
+	// @Parameter status
+	// @return error
+	UpdateSilenceStatus(ctx context.Context, id string, status constants.AlertSilenceStatus) error
+
+	// CreateHistory
+	// @Description: record an alert which begins firing
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter history
+	// @return *AlertHistory
+	// @return error
+	CreateHistory(ctx context.Context, history *AlertHistory) (*AlertHistory, error)
+
+	// QueryFiringHistory
+	// @Description: query alerts of cluster which are not resolved yet
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter clusterID
+	// @return []*AlertHistory
+	// @return error
+	QueryFiringHistory(ctx context.Context, clusterID string) ([]*AlertHistory, error)
+
+	// ResolveHistory
+	// @Description: mark alert as resolved
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter id
+	// @Parameter endsAt
+	// @return error
+	ResolveHistory(ctx context.Context, id string, endsAt time.Time) error
+
+	// QueryHistory
+	// @Description: query alert history of cluster in order of starting desc, empty conditions and zero time mean all
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter clusterID
+	// @Parameter name
+	// @Parameter severity
+	// @Parameter state
+	// @Parameter startTime alerts which start after it
+	// @Parameter endTime alerts which start before it
+	// @Parameter offset
+	// @Parameter length
+	// @return histories
+	// @return total
+	// @return err
+	QueryHistory(ctx context.Context, clusterID string, name string, severity string, state constants.AlertState, startTime time.Time, endTime time.Time, offset int, length int) (histories []*AlertHistory, total int64, err error)
+
+	// SaveReceiver
+	// @Description: create or replace the receiver token of cluster
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter receiver
+	// @return error
+	SaveReceiver(ctx context.Context, receiver *AlertReceiver) error
+
+	// GetReceiver
+	// @Description: get the receiver token of cluster
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter clusterID
+	// @return *AlertReceiver
+	// @return error if the receiver of cluster is not configured
+	GetReceiver(ctx context.Context, clusterID string) (*AlertReceiver, error)
+}
@@ -0,0 +1,214 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package alert
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	dbCommon "github.com/pingcap/tiunimanager/models/common"
+	"gorm.io/gorm"
+)
+
+type GormAlertReadWrite struct {
+	dbCommon.GormDB
+}
+
+func NewGormAlertReadWrite(db *gorm.DB) *GormAlertReadWrite {
+	m := &GormAlertReadWrite{
+		dbCommon.WrapDB(db),
+	}
+	return m
+}
+
+func (m *GormAlertReadWrite) CreateRules(ctx context.Context, rules []*AlertRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	err := m.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, rule := range rules {
+			var count int64
+			if err := tx.Model(&AlertRule{}).Where("cluster_id = ? AND name = ?", rule.ClusterID, rule.Name).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return errors.NewErrorf(errors.TIUNIMANAGER_ALERT_RULE_DUPLICATED, "alert rule %s of cluster %s already exists", rule.Name, rule.ClusterID)
+			}
+			if rule.Status == "" {
+				rule.Status = string(constants.AlertRuleEnabled)
+			}
+			if err := tx.Create(rule).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return dbCommon.WrapDBError(err)
+}
+
+func (m *GormAlertReadWrite) GetRule(ctx context.Context, clusterID string, ruleID string) (*AlertRule, error) {
+	if "" == clusterID || "" == ruleID {
+		return nil, errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "cluster id and rule id are required")
+	}
+
+	rule := &AlertRule{}
+	err := m.DB(ctx).First(rule, "id = ? AND cluster_id = ?", ruleID, clusterID).Error
+	if err != nil {
+		return nil, errors.NewErrorf(errors.TIUNIMANAGER_ALERT_RULE_NOT_FOUND, "alert rule %s of cluster %s not found, %s", ruleID, clusterID, err.Error())
+	}
+	return rule, nil
+}
+
+func (m *GormAlertReadWrite) QueryRules(ctx context.Context, clusterID string, ruleType constants.AlertRuleType, status constants.AlertRuleStatus, offset int, length int) (rules []*AlertRule, total int64, err error) {
+	rules = make([]*AlertRule, 0)
+	query := m.DB(ctx).Model(&AlertRule{}).Where("cluster_id = ?", clusterID)
+	if ruleType != "" {
+		query = query.Where("type = ?", string(ruleType))
+	}
+	if status != "" {
+		query = query.Where("status = ?", string(status))
+	}
+	err = query.Count(&total).Order("name").Offset(offset).Limit(length).Find(&rules).Error
+	return rules, total, dbCommon.WrapDBError(err)
+}
+
+func (m *GormAlertReadWrite) UpdateRule(ctx context.Context, rule *AlertRule) error {
+	if _, err := m.GetRule(ctx, rule.ClusterID, rule.ID); err != nil {
+		return err
+	}
+	err := m.DB(ctx).Model(&AlertRule{}).Where("id = ?", rule.ID).Updates(map[string]interface{}{
+		"expr":     rule.Expr,
+		"duration": rule.Duration,
+		"severity": rule.Severity,
+		"summary":  rule.Summary,
+		"status":   rule.Status,
+	}).Error
+	return dbCommon.WrapDBError(err)
+}
+
+func (m *GormAlertReadWrite) DeleteRule(ctx context.Context, clusterID string, ruleID string) error {
+	rule, err := m.GetRule(ctx, clusterID, ruleID)
+	if err != nil {
+		return err
+	}
+	return dbCommon.WrapDBError(m.DB(ctx).Delete(rule).Error)
+}
+
+func (m *GormAlertReadWrite) CreateSilence(ctx context.Context, silence *AlertSilence) (*AlertSilence, error) {
+	silence.Status = string(constants.AlertSilenceActive)
+	err := m.DB(ctx).Create(silence).Error
+	return silence, dbCommon.WrapDBError(err)
+}
+
+func (m *GormAlertReadWrite) GetSilence(ctx context.Context, clusterID string, id string) (*AlertSilence, error) {
+	if "" == clusterID || "" == id {
+		return nil, errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "cluster id and silence id are required")
+	}
+
+	silence := &AlertSilence{}
+	err := m.DB(ctx).First(silence, "id = ? AND cluster_id = ?", id, clusterID).Error
+	if err != nil {
+		return nil, errors.NewErrorf(errors.TIUNIMANAGER_ALERT_SILENCE_NOT_FOUND, "alert silence %s of cluster %s not found, %s", id, clusterID, err.Error())
+	}
+	return silence, nil
+}
+
+func (m *GormAlertReadWrite) QuerySilences(ctx context.Context, clusterID string, status constants.AlertSilenceStatus, offset int, length int) (silences []*AlertSilence, total int64, err error) {
+	silences = make([]*AlertSilence, 0)
+	query := m.DB(ctx).Model(&AlertSilence{}).Where("cluster_id = ?", clusterID)
+	if status != "" {
+		query = query.Where("status = ?", string(status))
+	}
+	err = query.Count(&total).Order("created_at desc").Offset(offset).Limit(length).Find(&silences).Error
+	return silences, total, dbCommon.WrapDBError(err)
+}
+
+func (m *GormAlertReadWrite) UpdateSilenceStatus(ctx context.Context, id string, status constants.AlertSilenceStatus) error {
+	if "" == id {
+		return errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "silence id is required")
+	}
+	err := m.DB(ctx).Model(&AlertSilence{}).Where("id = ?", id).Update("status", string(status)).Error
+	return dbCommon.WrapDBError(err)
+}
+
+func (m *GormAlertReadWrite) CreateHistory(ctx context.Context, history *AlertHistory) (*AlertHistory, error) {
+	history.Status = string(constants.AlertFiring)
+	err := m.DB(ctx).Create(history).Error
+	return history, dbCommon.WrapDBError(err)
+}
+
+func (m *GormAlertReadWrite) QueryFiringHistory(ctx context.Context, clusterID string) ([]*AlertHistory, error) {
+	histories := make([]*AlertHistory, 0)
+	err := m.DB(ctx).Model(&AlertHistory{}).
+		Where("cluster_id = ? AND status = ?", clusterID, string(constants.AlertFiring)).
+		Find(&histories).Error
+	return histories, dbCommon.WrapDBError(err)
+}
+
+func (m *GormAlertReadWrite) ResolveHistory(ctx context.Context, id string, endsAt time.Time) error {
+	if "" == id {
+		return errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "history id is required")
+	}
+	err := m.DB(ctx).Model(&AlertHistory{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":  string(constants.AlertResolved),
+		"ends_at": endsAt,
+	}).Error
+	return dbCommon.WrapDBError(err)
+}
+
+func (m *GormAlertReadWrite) QueryHistory(ctx context.Context, clusterID string, name string, severity string, state constants.AlertState, startTime time.Time, endTime time.Time, offset int, length int) (histories []*AlertHistory, total int64, err error) {
+	histories = make([]*AlertHistory, 0)
+	query := m.DB(ctx).Model(&AlertHistory{}).Where("cluster_id = ?", clusterID)
+	if name != "" {
+		query = query.Where("name = ?", name)
+	}
+	if severity != "" {
+		query = query.Where("severity = ?", severity)
+	}
+	if state != "" {
+		query = query.Where("status = ?", string(state))
+	}
+	if !startTime.IsZero() {
+		query = query.Where("starts_at >= ?", startTime)
+	}
+	if !endTime.IsZero() {
+		query = query.Where("starts_at <= ?", endTime)
+	}
+	err = query.Count(&total).Order("starts_at desc").Offset(offset).Limit(length).Find(&histories).Error
+	return histories, total, dbCommon.WrapDBError(err)
+}
+
+func (m *GormAlertReadWrite) SaveReceiver(ctx context.Context, receiver *AlertReceiver) error {
+	if "" == receiver.ClusterID {
+		return errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "cluster id is required")
+	}
+	return dbCommon.WrapDBError(m.DB(ctx).Save(receiver).Error)
+}
+
+func (m *GormAlertReadWrite) GetReceiver(ctx context.Context, clusterID string) (*AlertReceiver, error) {
+	if "" == clusterID {
+		return nil, errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "cluster id is required")
+	}
+
+	receiver := &AlertReceiver{}
+	err := m.DB(ctx).First(receiver, "cluster_id = ?", clusterID).Error
+	if err != nil {
+		return nil, errors.NewErrorf(errors.TIUNIMANAGER_ALERT_RECEIVER_NOT_CONFIGURED, "alert receiver of cluster %s is not configured, %s", clusterID, err.Error())
+	}
+	return receiver, nil
+}
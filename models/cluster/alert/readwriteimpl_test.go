@@ -0,0 +1,213 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package alert
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/models/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGormAlertReadWrite_Rule(t *testing.T) {
+	err := testRW.CreateRules(context.TODO(), []*AlertRule{
+		{
+			Entity:    common.Entity{TenantId: "tenant"},
+			ClusterID: "ruleCluster",
+			Name:      "TiDB_server_is_down",
+			Type:      string(constants.AlertRuleBuiltIn),
+			Expr:      `probe_success{group="tidb"} == 0`,
+			Duration:  "1m",
+			Severity:  string(constants.AlertSeverityEmergency),
+		},
+		{
+			Entity:    common.Entity{TenantId: "tenant", Status: string(constants.AlertRuleDisabled)},
+			ClusterID: "ruleCluster",
+			Name:      "custom",
+			Type:      string(constants.AlertRuleCustom),
+			Expr:      "sum(tidb_server_connections) > 1000",
+			Severity:  string(constants.AlertSeverityWarning),
+		},
+	})
+	assert.NoError(t, err)
+
+	t.Run("duplicated", func(t *testing.T) {
+		err := testRW.CreateRules(context.TODO(), []*AlertRule{
+			{
+				Entity:    common.Entity{TenantId: "tenant"},
+				ClusterID: "ruleCluster",
+				Name:      "custom",
+				Type:      string(constants.AlertRuleCustom),
+				Expr:      "up == 0",
+				Severity:  string(constants.AlertSeverityWarning),
+			},
+		})
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_ALERT_RULE_DUPLICATED, err.(errors.EMError).GetCode())
+	})
+	t.Run("query", func(t *testing.T) {
+		rules, total, err := testRW.QueryRules(context.TODO(), "ruleCluster", "", "", 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, "TiDB_server_is_down", rules[0].Name)
+		assert.Equal(t, string(constants.AlertRuleEnabled), rules[0].Status)
+
+		rules, total, err = testRW.QueryRules(context.TODO(), "ruleCluster", constants.AlertRuleCustom, constants.AlertRuleDisabled, 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, "custom", rules[0].Name)
+
+		_, total, err = testRW.QueryRules(context.TODO(), "otherCluster", "", "", 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+	})
+	t.Run("update and delete", func(t *testing.T) {
+		rules, _, err := testRW.QueryRules(context.TODO(), "ruleCluster", constants.AlertRuleCustom, "", 0, 10)
+		assert.NoError(t, err)
+		rule := rules[0]
+		rule.Expr = "sum(tidb_server_connections) > 2000"
+		rule.Duration = "5m"
+		rule.Status = string(constants.AlertRuleEnabled)
+		assert.NoError(t, testRW.UpdateRule(context.TODO(), rule))
+
+		got, err := testRW.GetRule(context.TODO(), "ruleCluster", rule.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "sum(tidb_server_connections) > 2000", got.Expr)
+		assert.Equal(t, "5m", got.Duration)
+		assert.Equal(t, string(constants.AlertRuleEnabled), got.Status)
+
+		_, err = testRW.GetRule(context.TODO(), "otherCluster", rule.ID)
+		assert.Equal(t, errors.TIUNIMANAGER_ALERT_RULE_NOT_FOUND, err.(errors.EMError).GetCode())
+		_, err = testRW.GetRule(context.TODO(), "", "")
+		assert.Error(t, err)
+
+		assert.NoError(t, testRW.DeleteRule(context.TODO(), "ruleCluster", rule.ID))
+		_, err = testRW.GetRule(context.TODO(), "ruleCluster", rule.ID)
+		assert.Error(t, err)
+		assert.Error(t, testRW.DeleteRule(context.TODO(), "ruleCluster", rule.ID))
+	})
+}
+
+func TestGormAlertReadWrite_Silence(t *testing.T) {
+	silence, err := testRW.CreateSilence(context.TODO(), &AlertSilence{
+		Entity:    common.Entity{TenantId: "tenant"},
+		ClusterID: "silenceCluster",
+		SilenceID: "am-silence",
+		Matchers:  `[{"name":"alertname","value":"TiDB_server_is_down"}]`,
+		StartsAt:  time.Now(),
+		EndsAt:    time.Now().Add(time.Hour),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, string(constants.AlertSilenceActive), silence.Status)
+
+	got, err := testRW.GetSilence(context.TODO(), "silenceCluster", silence.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "am-silence", got.SilenceID)
+
+	_, err = testRW.GetSilence(context.TODO(), "otherCluster", silence.ID)
+	assert.Equal(t, errors.TIUNIMANAGER_ALERT_SILENCE_NOT_FOUND, err.(errors.EMError).GetCode())
+
+	assert.NoError(t, testRW.UpdateSilenceStatus(context.TODO(), silence.ID, constants.AlertSilenceExpired))
+	assert.Error(t, testRW.UpdateSilenceStatus(context.TODO(), "", constants.AlertSilenceExpired))
+
+	silences, total, err := testRW.QuerySilences(context.TODO(), "silenceCluster", constants.AlertSilenceExpired, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, silence.ID, silences[0].ID)
+
+	_, total, err = testRW.QuerySilences(context.TODO(), "silenceCluster", constants.AlertSilenceActive, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+}
+
+func TestGormAlertReadWrite_History(t *testing.T) {
+	now := time.Now()
+	first, err := testRW.CreateHistory(context.TODO(), &AlertHistory{
+		Entity:      common.Entity{TenantId: "tenant"},
+		ClusterID:   "historyCluster",
+		Fingerprint: "f1",
+		Name:        "TiDB_server_is_down",
+		Severity:    string(constants.AlertSeverityEmergency),
+		StartsAt:    now.Add(-time.Hour),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, string(constants.AlertFiring), first.Status)
+
+	_, err = testRW.CreateHistory(context.TODO(), &AlertHistory{
+		Entity:      common.Entity{TenantId: "tenant"},
+		ClusterID:   "historyCluster",
+		Fingerprint: "f2",
+		Name:        "TiKV_server_is_down",
+		Severity:    string(constants.AlertSeverityEmergency),
+		StartsAt:    now,
+	})
+	assert.NoError(t, err)
+
+	firing, err := testRW.QueryFiringHistory(context.TODO(), "historyCluster")
+	assert.NoError(t, err)
+	assert.Len(t, firing, 2)
+
+	assert.NoError(t, testRW.ResolveHistory(context.TODO(), first.ID, now))
+	assert.Error(t, testRW.ResolveHistory(context.TODO(), "", now))
+
+	firing, err = testRW.QueryFiringHistory(context.TODO(), "historyCluster")
+	assert.NoError(t, err)
+	assert.Len(t, firing, 1)
+	assert.Equal(t, "f2", firing[0].Fingerprint)
+
+	t.Run("query", func(t *testing.T) {
+		histories, total, err := testRW.QueryHistory(context.TODO(), "historyCluster", "", "", "", time.Time{}, time.Time{}, 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, "f2", histories[0].Fingerprint)
+
+		histories, total, err = testRW.QueryHistory(context.TODO(), "historyCluster", "", "", constants.AlertResolved, time.Time{}, time.Time{}, 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, first.ID, histories[0].ID)
+
+		_, total, err = testRW.QueryHistory(context.TODO(), "historyCluster", "TiKV_server_is_down", string(constants.AlertSeverityEmergency), "", now.Add(-time.Minute), now.Add(time.Minute), 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+
+		_, total, err = testRW.QueryHistory(context.TODO(), "historyCluster", "", "", "", now.Add(time.Minute), time.Time{}, 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+	})
+}
+
+func TestGormAlertReadWrite_Receiver(t *testing.T) {
+	_, err := testRW.GetReceiver(context.TODO(), "receiverCluster")
+	assert.Error(t, err)
+	assert.Equal(t, errors.TIUNIMANAGER_ALERT_RECEIVER_NOT_CONFIGURED, err.(errors.EMError).GetCode())
+
+	assert.NoError(t, testRW.SaveReceiver(context.TODO(), &AlertReceiver{ClusterID: "receiverCluster", TenantId: "tenant", Token: "token01"}))
+	receiver, err := testRW.GetReceiver(context.TODO(), "receiverCluster")
+	assert.NoError(t, err)
+	assert.Equal(t, common.Password("token01"), receiver.Token)
+
+	// token is replaced when the receiver is configured again
+	assert.NoError(t, testRW.SaveReceiver(context.TODO(), &AlertReceiver{ClusterID: "receiverCluster", TenantId: "tenant", Token: "token02"}))
+	receiver, err = testRW.GetReceiver(context.TODO(), "receiverCluster")
+	assert.NoError(t, err)
+	assert.Equal(t, common.Password("token02"), receiver.Token)
+
+	assert.Error(t, testRW.SaveReceiver(context.TODO(), &AlertReceiver{}))
+}
@@ -33,4 +33,8 @@ type BackupRecord struct {
 	BackupTso    uint64
 	StartTime    time.Time
 	EndTime      time.Time
+	// result of the latest integrity verification, empty status means never verified
+	VerifyStatus  string `gorm:"default:null"`
+	VerifyMessage string `gorm:"type:text"`
+	VerifyTime    time.Time
 }
@@ -66,6 +66,17 @@ func (m *BRReadWrite) UpdateBackupRecord(ctx context.Context, backupId string, s
 	return db.Error
 }
 
+func (m *BRReadWrite) UpdateBackupVerification(ctx context.Context, backupId string, status string, message string, verifyTime time.Time) (err error) {
+	if "" == backupId {
+		return errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "backup id cannot be empty")
+	}
+	columnMap := make(map[string]interface{})
+	columnMap["verify_status"] = status
+	columnMap["verify_message"] = message
+	columnMap["verify_time"] = verifyTime
+	return m.DB(ctx).Model(&BackupRecord{}).Where("id = ?", backupId).Updates(columnMap).Error
+}
+
 func (m *BRReadWrite) GetBackupRecord(ctx context.Context, backupId string) (record *BackupRecord, err error) {
 	if "" == backupId {
 		return nil, errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "backup id cannot be empty")
@@ -125,6 +136,11 @@ func (m *BRReadWrite) UpdateBackupStrategy(ctx context.Context, strategy *Backup
 	columnMap["backup_date"] = strategy.BackupDate
 	columnMap["start_hour"] = strategy.StartHour
 	columnMap["end_hour"] = strategy.EndHour
+	columnMap["keep_last"] = strategy.KeepLast
+	columnMap["keep_days"] = strategy.KeepDays
+	columnMap["keep_weekly"] = strategy.KeepWeekly
+	columnMap["keep_monthly"] = strategy.KeepMonthly
+	columnMap["auto_verify"] = strategy.AutoVerify
 	return m.DB(ctx).Model(strategy).Where("cluster_id = ?", strategy.ClusterID).Updates(columnMap).Error
 }
 
@@ -166,6 +182,28 @@ func (m *BRReadWrite) QueryBackupStrategy(ctx context.Context, weekDay string, s
 	}
 }
 
+func (m *BRReadWrite) ClaimAutoPrune(ctx context.Context, clusterId string, runTime time.Time) (bool, error) {
+	return m.claimStrategyRun(ctx, clusterId, "prune_run_time", runTime)
+}
+
+func (m *BRReadWrite) ClaimAutoVerify(ctx context.Context, clusterId string, runTime time.Time) (bool, error) {
+	return m.claimStrategyRun(ctx, clusterId, "verify_run_time", runTime)
+}
+
+// claimStrategyRun set the run time column of the strategy only if it is earlier than the scheduled time
+func (m *BRReadWrite) claimStrategyRun(ctx context.Context, clusterId string, column string, runTime time.Time) (bool, error) {
+	if "" == clusterId {
+		return false, errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "cluster id cannot be empty")
+	}
+	result := m.DB(ctx).Model(&BackupStrategy{}).
+		Where("cluster_id = ? AND ("+column+" IS NULL OR "+column+" < ?)", clusterId, runTime).
+		Update(column, runTime)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 func (m *BRReadWrite) DeleteBackupStrategy(ctx context.Context, clusterId string) (err error) {
 	if "" == clusterId {
 		return errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "cluster id cannot be empty")
@@ -180,3 +218,32 @@ func (m *BRReadWrite) DeleteBackupStrategy(ctx context.Context, clusterId string
 
 	return m.DB(ctx).First(strategy, "cluster_id = ?", clusterId).Unscoped().Delete(strategy).Error
 }
+
+func (m *BRReadWrite) CreateLogBackupTask(ctx context.Context, task *LogBackupTask) (*LogBackupTask, error) {
+	return task, m.DB(ctx).Create(task).Error
+}
+
+func (m *BRReadWrite) UpdateLogBackupTask(ctx context.Context, task *LogBackupTask) (err error) {
+	if "" == task.ID {
+		return errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "log backup task id cannot be empty")
+	}
+	columnMap := make(map[string]interface{})
+	columnMap["status"] = task.Status
+	columnMap["checkpoint_ts"] = task.CheckpointTS
+	columnMap["truncated_ts"] = task.TruncatedTS
+	columnMap["stop_time"] = task.StopTime
+	return m.DB(ctx).Model(&LogBackupTask{}).Where("id = ?", task.ID).Updates(columnMap).Error
+}
+
+func (m *BRReadWrite) GetLogBackupTask(ctx context.Context, clusterId string) (task *LogBackupTask, err error) {
+	if "" == clusterId {
+		return nil, errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "cluster id cannot be empty")
+	}
+
+	task = &LogBackupTask{}
+	err = m.DB(ctx).Order("created_at desc").First(task, "cluster_id = ?", clusterId).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+	return task, nil
+}
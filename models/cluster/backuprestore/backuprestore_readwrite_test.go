@@ -105,6 +105,39 @@ func TestBRReadWrite_UpdateBackupRecord(t *testing.T) {
 	assert.Equal(t, true, recordGet.EndTime.Equal(recordCreate.EndTime))
 }
 
+func TestBRReadWrite_UpdateBackupVerification(t *testing.T) {
+	record := &BackupRecord{
+		Entity: common.Entity{
+			TenantId: "tenantId",
+			Status:   "Finished",
+		},
+		ClusterID:    "clusterId",
+		FilePath:     "/tmp/test",
+		StorageType:  "nfs",
+		BackupType:   "full",
+		BackupMethod: "physics",
+		BackupMode:   "auto",
+		StartTime:    time.Now(),
+	}
+	recordCreate, errCreate := rw.CreateBackupRecord(context.TODO(), record)
+	assert.NoError(t, errCreate)
+	assert.Equal(t, "", recordCreate.VerifyStatus)
+
+	verifyTime := time.Now()
+	errUpdate := rw.UpdateBackupVerification(context.TODO(), recordCreate.ID, "Failed", "checksum mismatch", verifyTime)
+	assert.NoError(t, errUpdate)
+
+	recordGet, errGet := rw.GetBackupRecord(context.TODO(), recordCreate.ID)
+	assert.NoError(t, errGet)
+	assert.Equal(t, "Failed", recordGet.VerifyStatus)
+	assert.Equal(t, "checksum mismatch", recordGet.VerifyMessage)
+	assert.Equal(t, true, recordGet.VerifyTime.Equal(verifyTime))
+	assert.Equal(t, "Finished", recordGet.Status)
+
+	errUpdate = rw.UpdateBackupVerification(context.TODO(), "", "Failed", "", verifyTime)
+	assert.Error(t, errUpdate)
+}
+
 func TestBRReadWrite_QueryBackupRecords(t *testing.T) {
 	record := &BackupRecord{
 		Entity: common.Entity{
@@ -301,3 +334,69 @@ func TestBRReadWrite_DeleteBackupStrategy(t *testing.T) {
 	assert.Nil(t, errGet)
 	assert.Equal(t, "", strategyGet.ID)
 }
+
+func TestBRReadWrite_ClaimAutoRun(t *testing.T) {
+	_, err := rw.CreateBackupStrategy(context.TODO(), &BackupStrategy{
+		Entity: common.Entity{
+			TenantId: "tenantId",
+			Status:   "BackupInitStatus",
+		},
+		ClusterID:  "clusterIdClaim",
+		BackupDate: "Monday,Friday",
+		StartHour:  11,
+		EndHour:    12,
+	})
+	assert.NoError(t, err)
+
+	runTime := time.Now().Truncate(time.Hour)
+	claimed, err := rw.ClaimAutoPrune(context.TODO(), "clusterIdClaim", runTime)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	claimed, err = rw.ClaimAutoPrune(context.TODO(), "clusterIdClaim", runTime)
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+
+	// runs of prune and verify are claimed separately
+	claimed, err = rw.ClaimAutoVerify(context.TODO(), "clusterIdClaim", runTime)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+
+	claimed, err = rw.ClaimAutoPrune(context.TODO(), "clusterIdClaim", runTime.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+
+	_, err = rw.ClaimAutoPrune(context.TODO(), "", runTime)
+	assert.Error(t, err)
+}
+
+func TestBRReadWrite_LogBackupTask(t *testing.T) {
+	task := &LogBackupTask{
+		Entity: common.Entity{
+			TenantId: "tenantId",
+			Status:   "Running",
+		},
+		ClusterID:   "clusterIdLog",
+		StorageType: "s3",
+		FilePath:    "/tmp/log",
+		StartTS:     100,
+		StartTime:   time.Now(),
+	}
+	taskCreate, errCreate := rw.CreateLogBackupTask(context.TODO(), task)
+	assert.NoError(t, errCreate)
+
+	taskCreate.Status = "Stopped"
+	taskCreate.CheckpointTS = 300
+	taskCreate.TruncatedTS = 200
+	taskCreate.StopTime = time.Now()
+	assert.NoError(t, rw.UpdateLogBackupTask(context.TODO(), taskCreate))
+
+	taskGet, errGet := rw.GetLogBackupTask(context.TODO(), "clusterIdLog")
+	assert.NoError(t, errGet)
+	assert.Equal(t, "Stopped", taskGet.Status)
+	assert.Equal(t, uint64(300), taskGet.CheckpointTS)
+	assert.Equal(t, uint64(200), taskGet.CoveredFrom())
+
+	taskGet, errGet = rw.GetLogBackupTask(context.TODO(), "clusterIdNotExist")
+	assert.NoError(t, errGet)
+	assert.Equal(t, "", taskGet.ID)
+}
@@ -15,7 +15,10 @@
 
 package backuprestore
 
-import "github.com/pingcap/tiunimanager/models/common"
+import (
+	"github.com/pingcap/tiunimanager/models/common"
+	"time"
+)
 
 // BackupStrategy backup strategy information
 type BackupStrategy struct {
@@ -24,4 +27,19 @@ type BackupStrategy struct {
 	BackupDate string `gorm:"default:null"`
 	StartHour  uint32
 	EndHour    uint32
+	// retention rules of auto backups, 0 means the rule is disabled, backups kept by any rule will not be pruned
+	KeepLast    int `gorm:"default:0;comment:'keep the latest N backups'"`
+	KeepDays    int `gorm:"default:0;comment:'keep backups created in recent D days'"`
+	KeepWeekly  int `gorm:"default:0;comment:'keep the latest backup of each recent W weeks'"`
+	KeepMonthly int `gorm:"default:0;comment:'keep the latest backup of each recent M months'"`
+	// verify the latest auto backup periodically
+	AutoVerify bool `gorm:"default:false"`
+	// scheduled time of the latest auto prune and auto verify run, each run is claimed by only one replica
+	PruneRunTime  time.Time
+	VerifyRunTime time.Time
+}
+
+// RetentionEnabled whether any retention rule is set
+func (s *BackupStrategy) RetentionEnabled() bool {
+	return s.KeepLast > 0 || s.KeepDays > 0 || s.KeepWeekly > 0 || s.KeepMonthly > 0
 }
@@ -0,0 +1,42 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ *  Unless required by applicable law or agreed to in writing, software       *
+ *  distributed under the License is distributed on an "AS IS" BASIS,         *
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  *
+ *  See the License for the specific language governing permissions and       *
+ *  limitations under the License.                                            *
+ ******************************************************************************/
+
+package backuprestore
+
+import (
+	"github.com/pingcap/tiunimanager/models/common"
+	"time"
+)
+
+// LogBackupTask continuous log backup task of a cluster, logs in [TruncatedTS, CheckpointTS] can be used for point-in-time recovery
+type LogBackupTask struct {
+	common.Entity
+	ClusterID    string `gorm:"not null;type:varchar(22);default:null"`
+	StorageType  string `gorm:"not null"`
+	FilePath     string
+	StartTS      uint64 `gorm:"comment:'ts from which logs are backed up'"`
+	CheckpointTS uint64 `gorm:"comment:'logs before this ts have been backed up'"`
+	TruncatedTS  uint64 `gorm:"comment:'logs before this ts have been truncated'"`
+	StartTime    time.Time
+	StopTime     time.Time
+}
+
+// CoveredFrom the earliest ts whose logs are still kept
+func (t *LogBackupTask) CoveredFrom() uint64 {
+	if t.TruncatedTS > t.StartTS {
+		return t.TruncatedTS
+	}
+	return t.StartTS
+}
@@ -48,6 +48,7 @@ func TestMain(m *testing.M) {
 			}
 			db.Migrator().CreateTable(BackupRecord{})
 			db.Migrator().CreateTable(BackupStrategy{})
+			db.Migrator().CreateTable(LogBackupTask{})
 
 			rw = NewBRReadWrite(db)
 			return nil
@@ -42,6 +42,17 @@ type ReaderWriter interface {
 	// @Return error
 	UpdateBackupRecord(ctx context.Context, backupId string, status string, size uint64, backupTso uint64, endTime time.Time) (err error)
 
+	// UpdateBackupVerification
+	// @Description: update verification result of backup record
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter backupId
+	// @Parameter status
+	// @Parameter message
+	// @Parameter verifyTime
+	// @Return error
+	UpdateBackupVerification(ctx context.Context, backupId string, status string, message string, verifyTime time.Time) (err error)
+
 	// GetBackupRecord
 	// @Description: get backup record by Id
 	// @Receiver m
@@ -128,6 +139,26 @@ type ReaderWriter interface {
 	// @Return error
 	QueryBackupStrategy(ctx context.Context, weekDay string, startHour uint32) (strategies []*BackupStrategy, err error)
 
+	// ClaimAutoPrune
+	// @Description: claim the auto prune run of the cluster, only one replica succeeds for a scheduled time
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter clusterId
+	// @Parameter runTime scheduled time of the run
+	// @Return claimed
+	// @Return error
+	ClaimAutoPrune(ctx context.Context, clusterId string, runTime time.Time) (claimed bool, err error)
+
+	// ClaimAutoVerify
+	// @Description: claim the auto verify run of the cluster, only one replica succeeds for a scheduled time
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter clusterId
+	// @Parameter runTime scheduled time of the run
+	// @Return claimed
+	// @Return error
+	ClaimAutoVerify(ctx context.Context, clusterId string, runTime time.Time) (claimed bool, err error)
+
 	// DeleteBackupStrategy
 	// @Description: delete backup strategy by clusterId
 	// @Receiver m
@@ -135,4 +166,30 @@ type ReaderWriter interface {
 	// @Parameter clusterId
 	// @Return error
 	DeleteBackupStrategy(ctx context.Context, clusterId string) (err error)
+
+	// CreateLogBackupTask
+	// @Description: create new log backup task
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter task
+	// @Return *LogBackupTask
+	// @Return error
+	CreateLogBackupTask(ctx context.Context, task *LogBackupTask) (*LogBackupTask, error)
+
+	// UpdateLogBackupTask
+	// @Description: update status, checkpoint ts, truncated ts and stop time of log backup task
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter task
+	// @Return error
+	UpdateLogBackupTask(ctx context.Context, task *LogBackupTask) (err error)
+
+	// GetLogBackupTask
+	// @Description: get the latest log backup task of cluster, return empty task if not found
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter clusterId
+	// @Return *LogBackupTask
+	// @Return error
+	GetLogBackupTask(ctx context.Context, clusterId string) (task *LogBackupTask, err error)
 }
@@ -0,0 +1,31 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package health
+
+import (
+	"github.com/pingcap/tiunimanager/models/common"
+)
+
+// HealthEvent status change of cluster or its instance found by health monitor,
+// status is the new status and instance id is empty for a change of cluster status
+type HealthEvent struct {
+	common.Entity
+	ClusterID      string `gorm:"not null;size:32;index;comment:'cluster id'"`
+	InstanceID     string `gorm:"size:32;index;comment:'instance id, empty for cluster'"`
+	InstanceType   string `gorm:"size:32;comment:'component type of instance'"`
+	PreviousStatus string `gorm:"size:32"`
+	Reason         string `gorm:"type:text;comment:'result of probes which causes the change'"`
+}
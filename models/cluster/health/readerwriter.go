@@ -0,0 +1,55 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package health
+
+import (
+	"context"
+	"time"
+)
+
+type ReaderWriter interface {
+	// CreateEvents
+	// @Description: create health events in one transaction
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter events
+	// @return error
+	CreateEvents(ctx context.Context, events []*HealthEvent) error
+
+	// QueryEvents
+	// @Description: query health events of cluster in order of created time desc, empty instance id means all
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter clusterID
+	// @Parameter instanceID
+	// @Parameter startTime zero means unlimited
+	// @Parameter endTime zero means unlimited
+	// @Parameter offset
+	// @Parameter length
+	// @return events
+	// @return total
+	// @return err
+	QueryEvents(ctx context.Context, clusterID string, instanceID string, startTime time.Time, endTime time.Time, offset int, length int) (events []*HealthEvent, total int64, err error)
+
+	// DeleteEventsBefore
+	// @Description: delete health events created before the time permanently
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter before
+	// @return int64 count of deleted events
+	// @return error
+	DeleteEventsBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,68 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package health
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/errors"
+	dbCommon "github.com/pingcap/tiunimanager/models/common"
+	"gorm.io/gorm"
+)
+
+type GormHealthReadWrite struct {
+	dbCommon.GormDB
+}
+
+func NewGormHealthReadWrite(db *gorm.DB) *GormHealthReadWrite {
+	m := &GormHealthReadWrite{
+		dbCommon.WrapDB(db),
+	}
+	return m
+}
+
+func (m *GormHealthReadWrite) CreateEvents(ctx context.Context, events []*HealthEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	err := m.DB(ctx).Create(events).Error
+	return dbCommon.WrapDBError(err)
+}
+
+func (m *GormHealthReadWrite) QueryEvents(ctx context.Context, clusterID string, instanceID string, startTime time.Time, endTime time.Time, offset int, length int) (events []*HealthEvent, total int64, err error) {
+	if "" == clusterID {
+		return nil, 0, errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "cluster id is required")
+	}
+	events = make([]*HealthEvent, 0)
+	query := m.DB(ctx).Model(&HealthEvent{}).Where("cluster_id = ?", clusterID)
+	if instanceID != "" {
+		query = query.Where("instance_id = ?", instanceID)
+	}
+	if !startTime.IsZero() {
+		query = query.Where("created_at >= ?", startTime)
+	}
+	if !endTime.IsZero() {
+		query = query.Where("created_at <= ?", endTime)
+	}
+	err = query.Count(&total).Order("created_at desc").Offset(offset).Limit(length).Find(&events).Error
+	return events, total, dbCommon.WrapDBError(err)
+}
+
+func (m *GormHealthReadWrite) DeleteEventsBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := m.DB(ctx).Unscoped().Where("created_at < ?", before).Delete(&HealthEvent{})
+	return result.RowsAffected, dbCommon.WrapDBError(result.Error)
+}
@@ -0,0 +1,85 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/models/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGormHealthReadWrite_Event(t *testing.T) {
+	now := time.Now()
+	err := testRW.CreateEvents(context.TODO(), []*HealthEvent{
+		{
+			Entity:         common.Entity{TenantId: "tenant", Status: string(constants.ClusterFailure), CreatedAt: now.Add(-48 * time.Hour)},
+			ClusterID:      "eventCluster",
+			PreviousStatus: string(constants.ClusterRunning),
+			Reason:         "pd is unavailable",
+		},
+		{
+			Entity:         common.Entity{TenantId: "tenant", Status: string(constants.ClusterInstanceFailure), CreatedAt: now.Add(-time.Hour)},
+			ClusterID:      "eventCluster",
+			InstanceID:     "instance1",
+			InstanceType:   string(constants.ComponentIDTiKV),
+			PreviousStatus: string(constants.ClusterInstanceRunning),
+			Reason:         "store is Down",
+		},
+		{
+			Entity:         common.Entity{TenantId: "tenant", Status: string(constants.ClusterRunning)},
+			ClusterID:      "eventCluster",
+			PreviousStatus: string(constants.ClusterFailure),
+		},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, testRW.CreateEvents(context.TODO(), []*HealthEvent{}))
+
+	t.Run("query", func(t *testing.T) {
+		events, total, err := testRW.QueryEvents(context.TODO(), "eventCluster", "", time.Time{}, time.Time{}, 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Equal(t, string(constants.ClusterRunning), events[0].Status)
+
+		events, total, err = testRW.QueryEvents(context.TODO(), "eventCluster", "instance1", time.Time{}, time.Time{}, 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, "store is Down", events[0].Reason)
+
+		_, total, err = testRW.QueryEvents(context.TODO(), "eventCluster", "", now.Add(-2*time.Hour), time.Time{}, 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+
+		_, total, err = testRW.QueryEvents(context.TODO(), "eventCluster", "", time.Time{}, now.Add(-24*time.Hour), 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+
+		_, _, err = testRW.QueryEvents(context.TODO(), "", "", time.Time{}, time.Time{}, 0, 10)
+		assert.Error(t, err)
+	})
+	t.Run("delete before", func(t *testing.T) {
+		deleted, err := testRW.DeleteEventsBefore(context.TODO(), now.Add(-24*time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		_, total, err := testRW.QueryEvents(context.TODO(), "eventCluster", "", time.Time{}, time.Time{}, 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+	})
+}
@@ -27,21 +27,22 @@ import (
 
 type Cluster struct {
 	common.Entity
-	Name              string                             `gorm:"not null;size:64;uniqueIndex:uniqueName;comment:'user name of the cluster''"`
-	Type              string                             `gorm:"not null;size:16;comment:'type of the cluster, eg. TiDB、TiDB Migration';"`
-	Version           string                             `gorm:"not null;size:64;comment:'version of the cluster'"`
-	TLS               bool                               `gorm:"default:false;comment:'whether to enable TLS, value: true or false'"`
-	Tags              []string                           `gorm:"-"`
-	TagInfo           string                             `gorm:"comment:'cluster tag information'"`
-	OwnerId           string                             `gorm:"not null;size:32;<-:create;->"`
-	ParameterGroupID  string                             `gorm:"comment: parameter group id"`
-	Copies            int                                `gorm:"comment: copies"`
-	Exclusive         bool                               `gorm:"comment: exclusive"`
-	Vendor            string                             `gorm:"comment: vendorID"`
-	Region            string                             `gorm:"comment: region location"`
-	CpuArchitecture   constants.ArchType                 `gorm:"not null;type:varchar(64);comment:'user name of the cluster''"`
-	MaintenanceStatus constants.ClusterMaintenanceStatus `gorm:"not null;type:varchar(64);comment:'user name of the cluster''"`
-	MaintainWindow    string                             `gorm:"not null;type:varchar(64);comment:'maintain window''"`
+	Name               string                             `gorm:"not null;size:64;uniqueIndex:uniqueName;comment:'user name of the cluster''"`
+	Type               string                             `gorm:"not null;size:16;comment:'type of the cluster, eg. TiDB、TiDB Migration';"`
+	Version            string                             `gorm:"not null;size:64;comment:'version of the cluster'"`
+	TLS                bool                               `gorm:"default:false;comment:'whether to enable TLS, value: true or false'"`
+	Tags               []string                           `gorm:"-"`
+	TagInfo            string                             `gorm:"comment:'cluster tag information'"`
+	OwnerId            string                             `gorm:"not null;size:32;<-:create;->"`
+	ParameterGroupID   string                             `gorm:"comment: parameter group id"`
+	Copies             int                                `gorm:"comment: copies"`
+	Exclusive          bool                               `gorm:"comment: exclusive"`
+	Vendor             string                             `gorm:"comment: vendorID"`
+	Region             string                             `gorm:"comment: region location"`
+	CpuArchitecture    constants.ArchType                 `gorm:"not null;type:varchar(64);comment:'user name of the cluster''"`
+	MaintenanceStatus  constants.ClusterMaintenanceStatus `gorm:"not null;type:varchar(64);comment:'user name of the cluster''"`
+	MaintainWindow     string                             `gorm:"not null;type:varchar(256);comment:'maintain window''"`
+	DeletionProtection bool                               `gorm:"default:false;comment:'cluster can not be deleted until the protection is cleared'"`
 	// only for database
 	DeleteTime int64 `gorm:"uniqueIndex:uniqueName"`
 }
@@ -0,0 +1,105 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package management
+
+import (
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/structs"
+	"gorm.io/gorm"
+)
+
+// ClusterTag normalized tag of cluster, Cluster.TagInfo is kept in sync for compatibility
+type ClusterTag struct {
+	ID        uint      `gorm:"primaryKey"`
+	ClusterID string    `gorm:"not null;size:32;uniqueIndex:idx_cluster_tag_key;comment:'cluster id'"`
+	TagKey    string    `gorm:"not null;size:64;uniqueIndex:idx_cluster_tag_key;index:idx_tag_key_value"`
+	TagValue  string    `gorm:"not null;size:64;index:idx_tag_key_value;comment:'empty if the tag has no value'"`
+	CreatedAt time.Time `gorm:"autoCreateTime;<-:create;->;"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// buildClusterTags convert tags of cluster to rows, the latter wins if a key is duplicated
+func buildClusterTags(clusterID string, tags []string) []*ClusterTag {
+	rows := make([]*ClusterTag, 0)
+	index := make(map[string]int)
+	for _, tag := range tags {
+		clusterTag := structs.ParseClusterTag(tag)
+		if clusterTag.Key == "" {
+			continue
+		}
+		if i, ok := index[clusterTag.Key]; ok {
+			rows[i].TagValue = clusterTag.Value
+			continue
+		}
+		index[clusterTag.Key] = len(rows)
+		rows = append(rows, &ClusterTag{
+			ClusterID: clusterID,
+			TagKey:    clusterTag.Key,
+			TagValue:  clusterTag.Value,
+		})
+	}
+	return rows
+}
+
+// tagStrings format rows as the tags of cluster
+func tagStrings(rows []*ClusterTag) []string {
+	tags := make([]string, 0, len(rows))
+	for _, row := range rows {
+		tags = append(tags, structs.ClusterTag{Key: row.TagKey, Value: row.TagValue}.String())
+	}
+	return tags
+}
+
+// tagSelectorQuery narrow the query of clusters by requirements of tag selector
+func tagSelectorQuery(db *gorm.DB, query *gorm.DB, selector structs.TagSelector) *gorm.DB {
+	for _, requirement := range selector {
+		subQuery := db.Session(&gorm.Session{NewDB: true}).Model(&ClusterTag{}).Select("cluster_id").Where("tag_key = ?", requirement.Key)
+		switch requirement.Operator {
+		case constants.TagOperatorExists:
+			query = query.Where("id in (?)", subQuery)
+		case constants.TagOperatorDoesNotExist:
+			query = query.Where("id not in (?)", subQuery)
+		case constants.TagOperatorEquals, constants.TagOperatorIn:
+			query = query.Where("id in (?)", subQuery.Where("tag_value in ?", requirement.Values))
+		case constants.TagOperatorNotEquals, constants.TagOperatorNotIn:
+			query = query.Where("id not in (?)", subQuery.Where("tag_value in ?", requirement.Values))
+		}
+	}
+	return query
+}
+
+// MigrateClusterTags
+// @Description: fill table of cluster tags with TagInfo of existing clusters, used when the table is created
+// @Parameter db
+// @return error
+func MigrateClusterTags(db *gorm.DB) error {
+	clusters := make([]*Cluster, 0)
+	if err := db.Unscoped().Find(&clusters).Error; err != nil {
+		return err
+	}
+	for _, cluster := range clusters {
+		rows := buildClusterTags(cluster.ID, cluster.Tags)
+		if len(rows) == 0 {
+			continue
+		}
+		if err := db.Create(rows).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -21,4 +21,5 @@ type ClusterTopologySnapshot struct {
 	Config     string `gorm:"type:text;comment:'yaml content of cluster topology';'"`
 	PublicKey  string `gorm:"not null;default:null;<-:create;type:text;comment:'connection public key';"`
 	PrivateKey string `gorm:"not null;default:null;<-:create;type:text;comment:'Connection private key';"`
+	Upgrade    string `gorm:"type:text;comment:'json of topology and versions before the latest upgrade';"`
 }
@@ -48,6 +48,9 @@ func TestMain(m *testing.M) {
 			db.Migrator().CreateTable(ClusterInstance{})
 			db.Migrator().CreateTable(ClusterTopologySnapshot{})
 			db.Migrator().CreateTable(DBUser{})
+			db.Migrator().CreateTable(PendingOperation{})
+			db.Migrator().CreateTable(ClusterTag{})
+			db.Migrator().CreateTable(RecycledCluster{})
 
 			testRW = NewClusterReadWrite(db)
 			return nil
@@ -0,0 +1,35 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package management
+
+import (
+	"time"
+
+	"github.com/pingcap/tiunimanager/models/common"
+)
+
+// PendingOperation disruptive operation submitted out of the maintain window of cluster,
+// it will be launched when the maintain window opens
+type PendingOperation struct {
+	common.Entity
+	ClusterID     string    `gorm:"not null;size:32;index;comment:'cluster id'"`
+	OperationType string    `gorm:"not null;size:32;comment:'type of the operation, eg. RestartCluster'"`
+	OperatorID    string    `gorm:"size:32;comment:'user who submits the operation'"`
+	Request       string    `gorm:"type:text;comment:'request of the operation in json'"`
+	WorkFlowID    string    `gorm:"size:32;default:null;comment:'workflow id after launched'"`
+	Message       string    `gorm:"type:text;comment:'reason of launch failure'"`
+	LaunchTime    time.Time `gorm:"default:null"`
+}
@@ -15,7 +15,10 @@
 
 package management
 
-import "github.com/pingcap/tiunimanager/common/constants"
+import (
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/structs"
+)
 
 type Filters struct {
 	ClusterIDs    []string
@@ -24,6 +27,7 @@ type Filters struct {
 	Type          string
 	StatusFilters []constants.ClusterRunningStatus
 	Tag           string
+	TagSelector   structs.TagSelector
 }
 
 type Result struct {
@@ -80,6 +80,19 @@ type ReaderWriter interface {
 	//
 	UpdateInstance(ctx context.Context, instances ...*ClusterInstance) error
 
+	//
+	// CompareAndUpdateInstanceStatus
+	//  @Description: update running status of cluster instance only if it is still expectedStatus
+	//  and its cluster is not under maintenance
+	//  @param ctx
+	//  @param instanceID
+	//  @param expectedStatus
+	//  @param status
+	//  @return bool false if status is changed by others or cluster is under maintenance
+	//  @return error
+	//
+	CompareAndUpdateInstanceStatus(ctx context.Context, instanceID string, expectedStatus string, status constants.ClusterInstanceRunningStatus) (bool, error)
+
 	//
 	// QueryInstancesByHost
 	// @Description: query all instances located at host
@@ -122,6 +135,18 @@ type ReaderWriter interface {
 	//
 	UpdateStatus(ctx context.Context, clusterID string, status constants.ClusterRunningStatus) error
 
+	//
+	// CompareAndUpdateStatus
+	//  @Description: update cluster status only if it is still expectedStatus and the cluster is not under maintenance
+	//  @param ctx
+	//  @param clusterID
+	//  @param expectedStatus
+	//  @param status
+	//  @return bool false if status is changed by others or cluster is under maintenance
+	//  @return error
+	//
+	CompareAndUpdateStatus(ctx context.Context, clusterID string, expectedStatus string, status constants.ClusterRunningStatus) (bool, error)
+
 	//
 	// SetMaintenanceStatus
 	//  @Description: set maintenance status to targetStatus,
@@ -151,6 +176,7 @@ type ReaderWriter interface {
 	CreateClusterTopologySnapshot(ctx context.Context, snapshot ClusterTopologySnapshot) error
 	GetCurrentClusterTopologySnapshot(ctx context.Context, clusterID string) (ClusterTopologySnapshot, error)
 	UpdateTopologySnapshotConfig(ctx context.Context, clusterID string, config string) error
+	UpdateTopologySnapshotUpgrade(ctx context.Context, clusterID string, upgrade string) error
 
 	//
 	// ClearClusterPhysically
@@ -194,4 +220,135 @@ type ReaderWriter interface {
 	// @return error
 	//
 	DeleteDBUser(ctx context.Context, ID uint) error
+	//
+	// CreatePendingOperation
+	// @Description: queue an operation until the maintain window of cluster opens
+	// @param ctx
+	// @param operation
+	// @return *PendingOperation
+	// @return error
+	//
+	CreatePendingOperation(ctx context.Context, operation *PendingOperation) (*PendingOperation, error)
+	//
+	// GetPendingOperation
+	// @Description: get pending operation by id
+	// @param ctx
+	// @param operationID
+	// @return *PendingOperation
+	// @return error
+	//
+	GetPendingOperation(ctx context.Context, operationID string) (*PendingOperation, error)
+	//
+	// QueryPendingOperations
+	// @Description: query pending operations in order of creation, empty clusterID or status means all
+	// @param ctx
+	// @param clusterID
+	// @param status
+	// @return []*PendingOperation
+	// @return error
+	//
+	QueryPendingOperations(ctx context.Context, clusterID string, status constants.PendingOperationStatus) ([]*PendingOperation, error)
+	//
+	// UpdatePendingOperation
+	// @Description: update status, workflow id and message of pending operation
+	// @param ctx
+	// @param operationID
+	// @param status
+	// @param workflowID
+	// @param message
+	// @return error
+	//
+	UpdatePendingOperation(ctx context.Context, operationID string, status constants.PendingOperationStatus, workflowID string, message string) error
+	//
+	// ClaimPendingOperation
+	// @Description: change status of a waiting operation to launching, only one caller can claim the operation
+	// @param ctx
+	// @param operationID
+	// @return bool false if the operation is not waiting any more
+	// @return error
+	//
+	ClaimPendingOperation(ctx context.Context, operationID string) (bool, error)
+	//
+	// UpdateClusterTags
+	// @Description: add or overwrite tags by key, then remove tags of removedKeys, TagInfo of cluster is updated too
+	// @param ctx
+	// @param clusterID
+	// @param added
+	// @param removedKeys
+	// @return []string tags of cluster after updated
+	// @return error
+	//
+	UpdateClusterTags(ctx context.Context, clusterID string, added []structs.ClusterTag, removedKeys []string) ([]string, error)
+	//
+	// UpdateClusterProfile
+	// @Description: update name, exclusive flag and maintain window of cluster, name must be unique among clusters not deleted
+	// @param ctx
+	// @param clusterID
+	// @param name
+	// @param exclusive
+	// @param maintainWindow
+	// @return error
+	//
+	UpdateClusterProfile(ctx context.Context, clusterID string, name string, exclusive bool, maintainWindow string) error
+	//
+	// SetDeletionProtection
+	// @Description: enable or disable deletion protection of cluster
+	// @param ctx
+	// @param clusterID
+	// @param enabled
+	// @return error
+	//
+	SetDeletionProtection(ctx context.Context, clusterID string, enabled bool) error
+	//
+	// CreateRecycledCluster
+	// @Description: put a soft deleted cluster into the recycle bin
+	// @param ctx
+	// @param recycled
+	// @return *RecycledCluster
+	// @return error
+	//
+	CreateRecycledCluster(ctx context.Context, recycled *RecycledCluster) (*RecycledCluster, error)
+	//
+	// GetRecycledCluster
+	// @Description: get the latest record of cluster in the recycle bin
+	// @param ctx
+	// @param clusterID
+	// @return *RecycledCluster
+	// @return error
+	//
+	GetRecycledCluster(ctx context.Context, clusterID string) (*RecycledCluster, error)
+	//
+	// QueryRecycledClusters
+	// @Description: query records of the recycle bin in order of creation desc, empty tenantID or status means all
+	// @param ctx
+	// @param tenantID
+	// @param status
+	// @param offset
+	// @param length
+	// @return []*RecycledCluster
+	// @return int64 total
+	// @return error
+	//
+	QueryRecycledClusters(ctx context.Context, tenantID string, status constants.RecycledClusterStatus, offset int, length int) ([]*RecycledCluster, int64, error)
+	//
+	// UpdateRecycledCluster
+	// @Description: update status of record in the recycle bin, empty backupID or workflowID is not updated
+	// @param ctx
+	// @param id
+	// @param status
+	// @param backupID
+	// @param workflowID
+	// @param message
+	// @return error
+	//
+	UpdateRecycledCluster(ctx context.Context, id string, status constants.RecycledClusterStatus, backupID string, workflowID string, message string) error
+	//
+	// ClaimRecycledCluster
+	// @Description: change status of a recycled record to purging, only one caller can claim the record
+	// @param ctx
+	// @param id
+	// @return bool false if the record is not recycled any more
+	// @return error
+	//
+	ClaimRecycledCluster(ctx context.Context, id string) (bool, error)
 }
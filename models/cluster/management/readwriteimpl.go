@@ -17,7 +17,9 @@ package management
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/pingcap/tiunimanager/common/constants"
 	"github.com/pingcap/tiunimanager/common/errors"
@@ -43,6 +45,11 @@ func (g *ClusterReadWrite) Create(ctx context.Context, cluster *Cluster) (*Clust
 			err = dbCommon.WrapDBError(err)
 		}
 	}
+	if err == nil {
+		if tags := buildClusterTags(cluster.ID, cluster.Tags); len(tags) > 0 {
+			err = dbCommon.WrapDBError(g.DB(ctx).Create(tags).Error)
+		}
+	}
 	return cluster, err
 }
 
@@ -267,7 +274,16 @@ func (g *ClusterReadWrite) QueryMetas(ctx context.Context, filters Filters, page
 	}
 
 	if len(filters.Tag) > 0 {
-		query = query.Where("tag_info like '%\"" + filters.Tag + "\"%'")
+		clusterTag := structs.ParseClusterTag(filters.Tag)
+		query = tagSelectorQuery(g.DB(ctx), query, structs.TagSelector{{
+			Key:      clusterTag.Key,
+			Operator: constants.TagOperatorEquals,
+			Values:   []string{clusterTag.Value},
+		}})
+	}
+
+	if len(filters.TagSelector) > 0 {
+		query = tagSelectorQuery(g.DB(ctx), query, filters.TagSelector)
 	}
 
 	err := query.Count(&total).Order("updated_at desc").Offset(pageReq.GetOffset()).Limit(pageReq.PageSize).Find(&clusters).Error
@@ -395,6 +411,21 @@ func (g *ClusterReadWrite) UpdateClusterInfo(ctx context.Context, template *Clus
 	return dbCommon.WrapDBError(err)
 }
 
+func (g *ClusterReadWrite) CompareAndUpdateInstanceStatus(ctx context.Context, instanceID string, expectedStatus string, status constants.ClusterInstanceRunningStatus) (bool, error) {
+	if "" == instanceID {
+		return false, errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "instance id is required")
+	}
+	idleClusters := g.DB(ctx).Model(&Cluster{}).Select("id").
+		Where("maintenance_status = ?", string(constants.ClusterMaintenanceNone))
+	result := g.DB(ctx).Model(&ClusterInstance{}).
+		Where("id = ? AND status = ? AND cluster_id IN (?)", instanceID, expectedStatus, idleClusters).
+		Update("status", string(status))
+	if result.Error != nil {
+		return false, dbCommon.WrapDBError(result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
 func (g *ClusterReadWrite) UpdateStatus(ctx context.Context, clusterID string, status constants.ClusterRunningStatus) error {
 	cluster, err := g.Get(ctx, clusterID)
 
@@ -408,6 +439,19 @@ func (g *ClusterReadWrite) UpdateStatus(ctx context.Context, clusterID string, s
 	return dbCommon.WrapDBError(err)
 }
 
+func (g *ClusterReadWrite) CompareAndUpdateStatus(ctx context.Context, clusterID string, expectedStatus string, status constants.ClusterRunningStatus) (bool, error) {
+	if "" == clusterID {
+		return false, errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "cluster id is required")
+	}
+	result := g.DB(ctx).Model(&Cluster{}).
+		Where("id = ? AND status = ? AND maintenance_status = ?", clusterID, expectedStatus, string(constants.ClusterMaintenanceNone)).
+		Update("status", string(status))
+	if result.Error != nil {
+		return false, dbCommon.WrapDBError(result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
 func (g *ClusterReadWrite) SetMaintenanceStatus(ctx context.Context, clusterID string, targetStatus constants.ClusterMaintenanceStatus) error {
 	cluster, err := g.Get(ctx, clusterID)
 
@@ -580,6 +624,20 @@ func (g *ClusterReadWrite) UpdateTopologySnapshotConfig(ctx context.Context, clu
 	return dbCommon.WrapDBError(g.DB(ctx).Save(snapshot).Error)
 }
 
+func (g *ClusterReadWrite) UpdateTopologySnapshotUpgrade(ctx context.Context, clusterID string, upgrade string) error {
+	snapshot := &ClusterTopologySnapshot{}
+	err := g.DB(ctx).Model(&ClusterTopologySnapshot{}).Where("cluster_id = ?", clusterID).First(snapshot).Error
+	if err != nil {
+		errInfo := "update cluster topology snapshot failed : record not found"
+		framework.LogWithContext(ctx).Error(errInfo)
+		err = errors.NewError(errors.TIUNIMANAGER_CLUSTER_NOT_FOUND, errInfo)
+		return err
+	}
+	snapshot.Upgrade = upgrade
+
+	return dbCommon.WrapDBError(g.DB(ctx).Save(snapshot).Error)
+}
+
 func (g *ClusterReadWrite) ClearClusterPhysically(ctx context.Context, clusterID string, reason string) error {
 	if len(clusterID) == 0 {
 		return errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "clusterId is empty")
@@ -600,6 +658,8 @@ func (g *ClusterReadWrite) ClearClusterPhysically(ctx context.Context, clusterID
 			return g.DB(ctx).Unscoped().Where("subject_cluster_id = ?", clusterID).Delete(&ClusterRelation{}).Error
 		}).BreakIf(func() error {
 			return g.DB(ctx).Unscoped().Where("object_cluster_id = ?", clusterID).Delete(&ClusterRelation{}).Error
+		}).BreakIf(func() error {
+			return g.DB(ctx).Where("cluster_id = ?", clusterID).Delete(&ClusterTag{}).Error
 		}).If(func(err error) {
 			framework.LogWithContext(ctx).Errorf("clear cluster data physically failed, clusterId = %s, err = %s", clusterID, err.Error())
 		}).Else(func() {
@@ -634,8 +694,220 @@ func (g *ClusterReadWrite) UpdateDBUser(ctx context.Context, user *DBUser) error
 	return nil
 }
 
+func (g *ClusterReadWrite) CreatePendingOperation(ctx context.Context, operation *PendingOperation) (*PendingOperation, error) {
+	if operation.Status == "" {
+		operation.Status = string(constants.PendingOperationWaiting)
+	}
+	err := g.DB(ctx).Create(operation).Error
+	return operation, dbCommon.WrapDBError(err)
+}
+
+func (g *ClusterReadWrite) GetPendingOperation(ctx context.Context, operationID string) (*PendingOperation, error) {
+	if "" == operationID {
+		return nil, errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "operation id is required")
+	}
+	operation := &PendingOperation{}
+	err := g.DB(ctx).First(operation, "id = ?", operationID).Error
+	if err != nil {
+		return nil, errors.NewErrorf(errors.TIUNIMANAGER_PENDING_OPERATION_NOT_FOUND, "pending operation %s not found, %s", operationID, err.Error())
+	}
+	return operation, nil
+}
+
+func (g *ClusterReadWrite) QueryPendingOperations(ctx context.Context, clusterID string, status constants.PendingOperationStatus) ([]*PendingOperation, error) {
+	operations := make([]*PendingOperation, 0)
+	query := g.DB(ctx).Model(&PendingOperation{})
+	if clusterID != "" {
+		query = query.Where("cluster_id = ?", clusterID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", string(status))
+	}
+	err := query.Order("created_at").Find(&operations).Error
+	return operations, dbCommon.WrapDBError(err)
+}
+
+func (g *ClusterReadWrite) UpdatePendingOperation(ctx context.Context, operationID string, status constants.PendingOperationStatus, workflowID string, message string) error {
+	if "" == operationID {
+		return errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "operation id is required")
+	}
+	columns := map[string]interface{}{
+		"status":  string(status),
+		"message": message,
+	}
+	if workflowID != "" {
+		columns["work_flow_id"] = workflowID
+	}
+	if status == constants.PendingOperationLaunched || status == constants.PendingOperationFailed {
+		columns["launch_time"] = time.Now()
+	}
+	err := g.DB(ctx).Model(&PendingOperation{}).Where("id = ?", operationID).Updates(columns).Error
+	return dbCommon.WrapDBError(err)
+}
+
+func (g *ClusterReadWrite) ClaimPendingOperation(ctx context.Context, operationID string) (bool, error) {
+	if "" == operationID {
+		return false, errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "operation id is required")
+	}
+	result := g.DB(ctx).Model(&PendingOperation{}).
+		Where("id = ? AND status = ?", operationID, string(constants.PendingOperationWaiting)).
+		Update("status", string(constants.PendingOperationLaunching))
+	if result.Error != nil {
+		return false, dbCommon.WrapDBError(result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
 func NewClusterReadWrite(db *gorm.DB) *ClusterReadWrite {
 	return &ClusterReadWrite{
 		dbCommon.WrapDB(db),
 	}
 }
+
+func (g *ClusterReadWrite) UpdateClusterTags(ctx context.Context, clusterID string, added []structs.ClusterTag, removedKeys []string) ([]string, error) {
+	cluster, err := g.Get(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]string, 0)
+	err = g.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, tag := range added {
+			err := tx.Where(&ClusterTag{ClusterID: clusterID, TagKey: tag.Key}).
+				Assign(map[string]interface{}{"tag_value": tag.Value}).
+				FirstOrCreate(&ClusterTag{}).Error
+			if err != nil {
+				return err
+			}
+		}
+		if len(removedKeys) > 0 {
+			err := tx.Where("cluster_id = ? AND tag_key in ?", clusterID, removedKeys).Delete(&ClusterTag{}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		rows := make([]*ClusterTag, 0)
+		err := tx.Where("cluster_id = ?", clusterID).Order("id").Find(&rows).Error
+		if err != nil {
+			return err
+		}
+		tags = tagStrings(rows)
+		tagInfo, err := json.Marshal(tags)
+		if err != nil {
+			return err
+		}
+		// skip hooks of cluster, which rebuild TagInfo from Tags
+		return tx.Model(cluster).UpdateColumn("tag_info", string(tagInfo)).Error
+	})
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("update tags of cluster %s failed, err = %s", clusterID, err.Error())
+		return nil, dbCommon.WrapDBError(err)
+	}
+	return tags, nil
+}
+
+func (g *ClusterReadWrite) UpdateClusterProfile(ctx context.Context, clusterID string, name string, exclusive bool, maintainWindow string) error {
+	cluster, err := g.Get(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+
+	err = g.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		// names of deleted clusters are released, see uniqueName index with DeleteTime
+		existOrError := tx.Model(&Cluster{}).Where("name = ? AND id <> ?", name, clusterID).First(&Cluster{}).Error
+		if existOrError == nil {
+			return errors.NewErrorf(errors.TIUNIMANAGER_DUPLICATED_NAME, "%s:%s", errors.TIUNIMANAGER_DUPLICATED_NAME.Explain(), name)
+		}
+		// skip hooks of cluster, which rebuild TagInfo from Tags
+		return tx.Model(cluster).UpdateColumns(map[string]interface{}{
+			"name":            name,
+			"exclusive":       exclusive,
+			"maintain_window": maintainWindow,
+			"updated_at":      time.Now(),
+		}).Error
+	})
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("update profile of cluster %s failed, err = %s", clusterID, err.Error())
+	}
+	return dbCommon.WrapDBError(err)
+}
+
+func (g *ClusterReadWrite) SetDeletionProtection(ctx context.Context, clusterID string, enabled bool) error {
+	cluster, err := g.Get(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+	// skip hooks of cluster, which rebuild TagInfo from Tags
+	err = g.DB(ctx).Model(cluster).UpdateColumns(map[string]interface{}{
+		"deletion_protection": enabled,
+		"updated_at":          time.Now(),
+	}).Error
+	return dbCommon.WrapDBError(err)
+}
+
+func (g *ClusterReadWrite) CreateRecycledCluster(ctx context.Context, recycled *RecycledCluster) (*RecycledCluster, error) {
+	if recycled.Status == "" {
+		recycled.Status = string(constants.RecycledClusterRecycling)
+	}
+	err := g.DB(ctx).Create(recycled).Error
+	return recycled, dbCommon.WrapDBError(err)
+}
+
+func (g *ClusterReadWrite) GetRecycledCluster(ctx context.Context, clusterID string) (*RecycledCluster, error) {
+	if "" == clusterID {
+		return nil, errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "cluster id is required")
+	}
+	recycled := &RecycledCluster{}
+	// a cluster may be recycled and recovered several times
+	err := g.DB(ctx).Where("cluster_id = ?", clusterID).Order("created_at desc").First(recycled).Error
+	if err != nil {
+		return nil, errors.NewErrorf(errors.TIUNIMANAGER_RECYCLED_CLUSTER_NOT_FOUND, "cluster %s not found in the recycle bin, %s", clusterID, err.Error())
+	}
+	return recycled, nil
+}
+
+func (g *ClusterReadWrite) QueryRecycledClusters(ctx context.Context, tenantID string, status constants.RecycledClusterStatus, offset int, length int) ([]*RecycledCluster, int64, error) {
+	recycled := make([]*RecycledCluster, 0)
+	query := g.DB(ctx).Model(&RecycledCluster{})
+	if tenantID != "" {
+		query = query.Where("tenant_id = ?", tenantID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", string(status))
+	}
+	var total int64
+	err := query.Count(&total).Order("created_at desc").Offset(offset).Limit(length).Find(&recycled).Error
+	return recycled, total, dbCommon.WrapDBError(err)
+}
+
+func (g *ClusterReadWrite) UpdateRecycledCluster(ctx context.Context, id string, status constants.RecycledClusterStatus, backupID string, workflowID string, message string) error {
+	if "" == id {
+		return errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "id is required")
+	}
+	columns := map[string]interface{}{
+		"status":  string(status),
+		"message": message,
+	}
+	if backupID != "" {
+		columns["backup_id"] = backupID
+	}
+	if workflowID != "" {
+		columns["work_flow_id"] = workflowID
+	}
+	err := g.DB(ctx).Model(&RecycledCluster{}).Where("id = ?", id).Updates(columns).Error
+	return dbCommon.WrapDBError(err)
+}
+
+func (g *ClusterReadWrite) ClaimRecycledCluster(ctx context.Context, id string) (bool, error) {
+	if "" == id {
+		return false, errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "id is required")
+	}
+	result := g.DB(ctx).Model(&RecycledCluster{}).
+		Where("id = ? AND status = ?", id, string(constants.RecycledClusterRecycled)).
+		Update("status", string(constants.RecycledClusterPurging))
+	if result.Error != nil {
+		return false, dbCommon.WrapDBError(result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
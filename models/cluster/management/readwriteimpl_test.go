@@ -19,6 +19,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/pingcap/tiunimanager/common/constants"
 	"github.com/pingcap/tiunimanager/common/errors"
@@ -508,6 +509,78 @@ func TestGormClusterReadWrite_UpdateStatus(t *testing.T) {
 	})
 }
 
+func TestGormClusterReadWrite_CompareAndUpdateStatus(t *testing.T) {
+	got, err := testRW.Create(context.TODO(), &Cluster{
+		Name: "testCompareAndUpdate",
+		Entity: common.Entity{
+			TenantId: "abc",
+			Status:   string(constants.ClusterRunning),
+		},
+	})
+	assert.NoError(t, err)
+	defer testRW.Delete(context.TODO(), got.ID)
+
+	instance := &ClusterInstance{
+		Entity: common.Entity{
+			TenantId: "abc",
+			Status:   string(constants.ClusterInstanceRunning),
+		},
+		Type:      "TiKV",
+		Version:   "v5.0.0",
+		ClusterID: got.ID,
+		HostIP:    []string{"127.0.0.1"},
+		Ports:     []int32{20160, 20180},
+	}
+	err = testRW.DB(context.TODO()).Create(instance).Error
+	assert.NoError(t, err)
+
+	t.Run("normal", func(t *testing.T) {
+		updated, err := testRW.CompareAndUpdateInstanceStatus(context.TODO(), instance.ID, string(constants.ClusterInstanceRunning), constants.ClusterInstanceFailure)
+		assert.NoError(t, err)
+		assert.True(t, updated)
+		updated, err = testRW.CompareAndUpdateStatus(context.TODO(), got.ID, string(constants.ClusterRunning), constants.ClusterFailure)
+		assert.NoError(t, err)
+		assert.True(t, updated)
+
+		check, err := testRW.GetInstance(context.TODO(), instance.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, string(constants.ClusterInstanceFailure), check.Status)
+		assert.Equal(t, []int32{20160, 20180}, check.Ports)
+		cluster, err := testRW.Get(context.TODO(), got.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, string(constants.ClusterFailure), cluster.Status)
+	})
+	t.Run("status changed", func(t *testing.T) {
+		// status has been changed by another replica
+		updated, err := testRW.CompareAndUpdateInstanceStatus(context.TODO(), instance.ID, string(constants.ClusterInstanceRunning), constants.ClusterInstanceFailure)
+		assert.NoError(t, err)
+		assert.False(t, updated)
+		updated, err = testRW.CompareAndUpdateStatus(context.TODO(), got.ID, string(constants.ClusterRunning), constants.ClusterFailure)
+		assert.NoError(t, err)
+		assert.False(t, updated)
+	})
+	t.Run("maintenance", func(t *testing.T) {
+		err := testRW.SetMaintenanceStatus(context.TODO(), got.ID, constants.ClusterMaintenanceUpgrading)
+		assert.NoError(t, err)
+		defer testRW.ClearMaintenanceStatus(context.TODO(), got.ID, constants.ClusterMaintenanceUpgrading)
+
+		updated, err := testRW.CompareAndUpdateInstanceStatus(context.TODO(), instance.ID, string(constants.ClusterInstanceFailure), constants.ClusterInstanceRunning)
+		assert.NoError(t, err)
+		assert.False(t, updated)
+		updated, err = testRW.CompareAndUpdateStatus(context.TODO(), got.ID, string(constants.ClusterFailure), constants.ClusterRunning)
+		assert.NoError(t, err)
+		assert.False(t, updated)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := testRW.CompareAndUpdateInstanceStatus(context.TODO(), "", string(constants.ClusterInstanceRunning), constants.ClusterInstanceFailure)
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_PARAMETER_INVALID, err.(errors.EMError).GetCode())
+		_, err = testRW.CompareAndUpdateStatus(context.TODO(), "", string(constants.ClusterRunning), constants.ClusterFailure)
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_PARAMETER_INVALID, err.(errors.EMError).GetCode())
+	})
+}
+
 func TestGormClusterReadWrite_ClusterTopologySnapshot(t *testing.T) {
 	t.Run("create", func(t *testing.T) {
 		err := testRW.CreateClusterTopologySnapshot(context.TODO(), ClusterTopologySnapshot{
@@ -579,6 +652,16 @@ func TestGormClusterReadWrite_ClusterTopologySnapshot(t *testing.T) {
 		err = testRW.UpdateTopologySnapshotConfig(context.TODO(), "cluster111", "changed")
 		assert.NoError(t, err)
 
+		err = testRW.UpdateTopologySnapshotUpgrade(context.TODO(), "whatever", "upgrade")
+		assert.Error(t, err)
+
+		err = testRW.UpdateTopologySnapshotUpgrade(context.TODO(), "cluster111", "upgrade")
+		assert.NoError(t, err)
+		s, err = testRW.GetCurrentClusterTopologySnapshot(context.TODO(), "cluster111")
+		assert.NoError(t, err)
+		assert.Equal(t, "upgrade", s.Upgrade)
+		assert.Equal(t, "changed", s.Config)
+
 		s, err = testRW.GetCurrentClusterTopologySnapshot(context.TODO(), "cluster111")
 		assert.NoError(t, err)
 		assert.Equal(t, "changed", s.Config)
@@ -1058,3 +1141,273 @@ func TestClusterReadWrite_UpdateDBUser(t *testing.T) {
 		})
 	}
 }
+
+func TestClusterReadWrite_PendingOperation(t *testing.T) {
+	first, err := testRW.CreatePendingOperation(context.TODO(), &PendingOperation{
+		Entity:        common.Entity{TenantId: "tenant"},
+		ClusterID:     "pendingCluster",
+		OperationType: string(constants.PendingOperationRestartCluster),
+		OperatorID:    "user",
+		Request:       `{"clusterId":"pendingCluster"}`,
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, first.ID)
+	assert.Equal(t, string(constants.PendingOperationWaiting), first.Status)
+	second, err := testRW.CreatePendingOperation(context.TODO(), &PendingOperation{
+		Entity:        common.Entity{TenantId: "tenant"},
+		ClusterID:     "pendingCluster",
+		OperationType: string(constants.PendingOperationBackupCluster),
+		Request:       `{"clusterId":"pendingCluster"}`,
+	})
+	assert.NoError(t, err)
+
+	t.Run("get", func(t *testing.T) {
+		got, err := testRW.GetPendingOperation(context.TODO(), first.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "user", got.OperatorID)
+
+		_, err = testRW.GetPendingOperation(context.TODO(), "")
+		assert.Error(t, err)
+		_, err = testRW.GetPendingOperation(context.TODO(), "unknown")
+		assert.Equal(t, errors.TIUNIMANAGER_PENDING_OPERATION_NOT_FOUND, err.(errors.EMError).GetCode())
+	})
+	t.Run("update", func(t *testing.T) {
+		err := testRW.UpdatePendingOperation(context.TODO(), first.ID, constants.PendingOperationLaunched, "flow", "")
+		assert.NoError(t, err)
+		got, err := testRW.GetPendingOperation(context.TODO(), first.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, string(constants.PendingOperationLaunched), got.Status)
+		assert.Equal(t, "flow", got.WorkFlowID)
+		assert.False(t, got.LaunchTime.IsZero())
+
+		assert.Error(t, testRW.UpdatePendingOperation(context.TODO(), "", constants.PendingOperationCanceled, "", ""))
+	})
+	t.Run("query", func(t *testing.T) {
+		operations, err := testRW.QueryPendingOperations(context.TODO(), "pendingCluster", "")
+		assert.NoError(t, err)
+		assert.Len(t, operations, 2)
+
+		operations, err = testRW.QueryPendingOperations(context.TODO(), "pendingCluster", constants.PendingOperationWaiting)
+		assert.NoError(t, err)
+		assert.Len(t, operations, 1)
+		assert.Equal(t, second.ID, operations[0].ID)
+
+		operations, err = testRW.QueryPendingOperations(context.TODO(), "", constants.PendingOperationWaiting)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, operations)
+	})
+	t.Run("claim", func(t *testing.T) {
+		claimed, err := testRW.ClaimPendingOperation(context.TODO(), second.ID)
+		assert.NoError(t, err)
+		assert.True(t, claimed)
+		got, err := testRW.GetPendingOperation(context.TODO(), second.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, string(constants.PendingOperationLaunching), got.Status)
+
+		// claimed by another replica
+		claimed, err = testRW.ClaimPendingOperation(context.TODO(), second.ID)
+		assert.NoError(t, err)
+		assert.False(t, claimed)
+		// launched already
+		claimed, err = testRW.ClaimPendingOperation(context.TODO(), first.ID)
+		assert.NoError(t, err)
+		assert.False(t, claimed)
+
+		_, err = testRW.ClaimPendingOperation(context.TODO(), "")
+		assert.Error(t, err)
+	})
+}
+
+func TestClusterReadWrite_UpdateClusterTags(t *testing.T) {
+	cluster1 := mockCluster("UpdateClusterTags_test1", "TiDB", constants.ClusterRunning, []string{"env=prod", "backup"})
+	cluster2 := mockCluster("UpdateClusterTags_test2", "TiDB", constants.ClusterRunning, []string{"env=test", "team=dba"})
+	defer testRW.Delete(context.TODO(), cluster1)
+	defer testRW.Delete(context.TODO(), cluster2)
+
+	t.Run("update", func(t *testing.T) {
+		tags, err := testRW.UpdateClusterTags(context.TODO(), cluster1, []structs.ClusterTag{
+			{Key: "env", Value: "staging"},
+			{Key: "team", Value: "ops"},
+		}, []string{"backup"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"env=staging", "team=ops"}, tags)
+
+		got, err := testRW.Get(context.TODO(), cluster1)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"env=staging", "team=ops"}, got.Tags)
+	})
+	t.Run("not found", func(t *testing.T) {
+		_, err := testRW.UpdateClusterTags(context.TODO(), "unknown", []structs.ClusterTag{{Key: "env"}}, nil)
+		assert.Error(t, err)
+	})
+	t.Run("selector", func(t *testing.T) {
+		query := func(s string) []string {
+			selector, err := structs.ParseTagSelector(s)
+			assert.NoError(t, err)
+			results, _, err := testRW.QueryMetas(context.TODO(), Filters{
+				TenantId:    "1919",
+				NameLike:    "UpdateClusterTags",
+				TagSelector: selector,
+			}, structs.PageRequest{Page: 1, PageSize: 10})
+			assert.NoError(t, err)
+			ids := make([]string, 0)
+			for _, result := range results {
+				ids = append(ids, result.Cluster.ID)
+			}
+			return ids
+		}
+		assert.ElementsMatch(t, []string{cluster1, cluster2}, query("env in (staging,test)"))
+		assert.ElementsMatch(t, []string{cluster2}, query("env!=staging"))
+		assert.ElementsMatch(t, []string{cluster1}, query("team=ops,env"))
+		assert.ElementsMatch(t, []string{cluster1, cluster2}, query("!backup"))
+		assert.Empty(t, query("team notin (ops,dba)"))
+	})
+}
+
+func TestClusterReadWrite_UpdateClusterProfile(t *testing.T) {
+	cluster1 := mockCluster("UpdateClusterProfile_test1", "TiDB", constants.ClusterRunning, []string{"env=prod"})
+	cluster2 := mockCluster("UpdateClusterProfile_test2", "TiDB", constants.ClusterRunning, []string{})
+	defer testRW.Delete(context.TODO(), cluster1)
+	defer testRW.Delete(context.TODO(), cluster2)
+
+	t.Run("normal", func(t *testing.T) {
+		err := testRW.UpdateClusterProfile(context.TODO(), cluster1, "UpdateClusterProfile_renamed", true, "Saturday 02:00-06:00")
+		assert.NoError(t, err)
+		got, err := testRW.Get(context.TODO(), cluster1)
+		assert.NoError(t, err)
+		assert.Equal(t, "UpdateClusterProfile_renamed", got.Name)
+		assert.True(t, got.Exclusive)
+		assert.Equal(t, "Saturday 02:00-06:00", got.MaintainWindow)
+		assert.Equal(t, []string{"env=prod"}, got.Tags)
+
+		err = testRW.UpdateClusterProfile(context.TODO(), cluster1, "UpdateClusterProfile_renamed", false, "")
+		assert.NoError(t, err)
+		got, err = testRW.Get(context.TODO(), cluster1)
+		assert.NoError(t, err)
+		assert.False(t, got.Exclusive)
+		assert.Empty(t, got.MaintainWindow)
+	})
+	t.Run("duplicated name", func(t *testing.T) {
+		err := testRW.UpdateClusterProfile(context.TODO(), cluster2, "UpdateClusterProfile_renamed", false, "")
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_DUPLICATED_NAME, err.(errors.EMError).GetCode())
+	})
+	t.Run("name of deleted cluster", func(t *testing.T) {
+		deleted := mockCluster("UpdateClusterProfile_deleted", "TiDB", constants.ClusterRunning, []string{})
+		assert.NoError(t, testRW.Delete(context.TODO(), deleted))
+		err := testRW.UpdateClusterProfile(context.TODO(), cluster2, "UpdateClusterProfile_deleted", false, "")
+		assert.NoError(t, err)
+	})
+	t.Run("not found", func(t *testing.T) {
+		err := testRW.UpdateClusterProfile(context.TODO(), "unknown", "UpdateClusterProfile_unknown", false, "")
+		assert.Error(t, err)
+	})
+}
+
+func TestClusterReadWrite_SetDeletionProtection(t *testing.T) {
+	clusterID := mockCluster("SetDeletionProtection_test", "TiDB", constants.ClusterRunning, []string{"env=prod"})
+	defer testRW.Delete(context.TODO(), clusterID)
+
+	assert.NoError(t, testRW.SetDeletionProtection(context.TODO(), clusterID, true))
+	got, err := testRW.Get(context.TODO(), clusterID)
+	assert.NoError(t, err)
+	assert.True(t, got.DeletionProtection)
+	assert.Equal(t, []string{"env=prod"}, got.Tags)
+
+	assert.NoError(t, testRW.SetDeletionProtection(context.TODO(), clusterID, false))
+	got, err = testRW.Get(context.TODO(), clusterID)
+	assert.NoError(t, err)
+	assert.False(t, got.DeletionProtection)
+
+	assert.Error(t, testRW.SetDeletionProtection(context.TODO(), "unknown", true))
+}
+
+func TestClusterReadWrite_RecycledCluster(t *testing.T) {
+	first, err := testRW.CreateRecycledCluster(context.TODO(), &RecycledCluster{
+		Entity:      common.Entity{TenantId: "recycleTenant"},
+		ClusterID:   "recycledCluster",
+		ClusterName: "recycled",
+		OperatorID:  "user",
+		Request:     `{"clusterID":"recycledCluster"}`,
+		ExpireTime:  time.Now().Add(time.Hour),
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, first.ID)
+	assert.Equal(t, string(constants.RecycledClusterRecycling), first.Status)
+
+	t.Run("update", func(t *testing.T) {
+		err := testRW.UpdateRecycledCluster(context.TODO(), first.ID, constants.RecycledClusterRecycled, "backup", "flow", "")
+		assert.NoError(t, err)
+		got, err := testRW.GetRecycledCluster(context.TODO(), "recycledCluster")
+		assert.NoError(t, err)
+		assert.Equal(t, string(constants.RecycledClusterRecycled), got.Status)
+		assert.Equal(t, "backup", got.BackupID)
+		assert.Equal(t, "flow", got.WorkFlowID)
+
+		err = testRW.UpdateRecycledCluster(context.TODO(), first.ID, constants.RecycledClusterRecovered, "", "", "recovered")
+		assert.NoError(t, err)
+		got, err = testRW.GetRecycledCluster(context.TODO(), "recycledCluster")
+		assert.NoError(t, err)
+		assert.Equal(t, "backup", got.BackupID)
+		assert.Equal(t, "recovered", got.Message)
+
+		assert.Error(t, testRW.UpdateRecycledCluster(context.TODO(), "", constants.RecycledClusterFailed, "", "", ""))
+	})
+	t.Run("get latest", func(t *testing.T) {
+		time.Sleep(10 * time.Millisecond)
+		second, err := testRW.CreateRecycledCluster(context.TODO(), &RecycledCluster{
+			Entity:     common.Entity{TenantId: "recycleTenant"},
+			ClusterID:  "recycledCluster",
+			ExpireTime: time.Now().Add(time.Hour),
+		})
+		assert.NoError(t, err)
+		got, err := testRW.GetRecycledCluster(context.TODO(), "recycledCluster")
+		assert.NoError(t, err)
+		assert.Equal(t, second.ID, got.ID)
+
+		_, err = testRW.GetRecycledCluster(context.TODO(), "")
+		assert.Error(t, err)
+		_, err = testRW.GetRecycledCluster(context.TODO(), "unknown")
+		assert.Equal(t, errors.TIUNIMANAGER_RECYCLED_CLUSTER_NOT_FOUND, err.(errors.EMError).GetCode())
+	})
+	t.Run("query", func(t *testing.T) {
+		recycled, total, err := testRW.QueryRecycledClusters(context.TODO(), "recycleTenant", "", 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Len(t, recycled, 2)
+
+		recycled, total, err = testRW.QueryRecycledClusters(context.TODO(), "recycleTenant", constants.RecycledClusterRecovered, 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, first.ID, recycled[0].ID)
+
+		recycled, _, err = testRW.QueryRecycledClusters(context.TODO(), "otherTenant", "", 0, 10)
+		assert.NoError(t, err)
+		assert.Empty(t, recycled)
+	})
+	t.Run("claim", func(t *testing.T) {
+		got, err := testRW.GetRecycledCluster(context.TODO(), "recycledCluster")
+		assert.NoError(t, err)
+		// still recycling
+		claimed, err := testRW.ClaimRecycledCluster(context.TODO(), got.ID)
+		assert.NoError(t, err)
+		assert.False(t, claimed)
+
+		err = testRW.UpdateRecycledCluster(context.TODO(), got.ID, constants.RecycledClusterRecycled, "", "", "")
+		assert.NoError(t, err)
+		claimed, err = testRW.ClaimRecycledCluster(context.TODO(), got.ID)
+		assert.NoError(t, err)
+		assert.True(t, claimed)
+		got, err = testRW.GetRecycledCluster(context.TODO(), "recycledCluster")
+		assert.NoError(t, err)
+		assert.Equal(t, string(constants.RecycledClusterPurging), got.Status)
+
+		// claimed by another replica
+		claimed, err = testRW.ClaimRecycledCluster(context.TODO(), got.ID)
+		assert.NoError(t, err)
+		assert.False(t, claimed)
+
+		_, err = testRW.ClaimRecycledCluster(context.TODO(), "")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,36 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package management
+
+import (
+	"time"
+
+	"github.com/pingcap/tiunimanager/models/common"
+)
+
+// RecycledCluster cluster in the recycle bin after soft deleted,
+// it is stopped and its resources are held until ExpireTime, then it is purged
+type RecycledCluster struct {
+	common.Entity
+	ClusterID   string    `gorm:"not null;size:32;index;comment:'cluster id'"`
+	ClusterName string    `gorm:"size:64;comment:'name of the cluster when it is deleted'"`
+	OperatorID  string    `gorm:"size:32;comment:'user who deletes the cluster'"`
+	Request     string    `gorm:"type:text;comment:'delete request in json, it is used when the cluster is purged'"`
+	BackupID    string    `gorm:"size:32;comment:'backup before deleting'"`
+	ExpireTime  time.Time `gorm:"index;comment:'the cluster is purged after the time'"`
+	WorkFlowID  string    `gorm:"size:32;default:null;comment:'workflow id of recycling or purging'"`
+	Message     string    `gorm:"type:text;comment:'reason of failure'"`
+}
@@ -14,3 +14,66 @@
  ******************************************************************************/
 
 package performance
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+)
+
+type ReaderWriter interface {
+	// CreateSnapshot
+	// @Description: create performance snapshot with its statements in one transaction,
+	// TIUNIMANAGER_PERFORMANCE_SNAPSHOT_EXISTED is returned if the cluster has a snapshot of the same type and end time
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter snapshot
+	// @Parameter statements
+	// @return *PerformanceSnapshot
+	// @return error
+	CreateSnapshot(ctx context.Context, snapshot *PerformanceSnapshot, statements []*SnapshotStatement) (*PerformanceSnapshot, error)
+
+	// GetSnapshot
+	// @Description: get performance snapshot of cluster with its statements in order of latency desc
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter clusterID
+	// @Parameter snapshotID
+	// @return *PerformanceSnapshot
+	// @return []*SnapshotStatement
+	// @return error if snapshot non-existent
+	GetSnapshot(ctx context.Context, clusterID string, snapshotID string) (*PerformanceSnapshot, []*SnapshotStatement, error)
+
+	// QuerySnapshots
+	// @Description: query performance snapshots of cluster in order of end time desc, empty type means all
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter clusterID
+	// @Parameter snapshotType
+	// @Parameter offset
+	// @Parameter length
+	// @return snapshots
+	// @return total
+	// @return err
+	QuerySnapshots(ctx context.Context, clusterID string, snapshotType constants.PerformanceSnapshotType, offset int, length int) (snapshots []*PerformanceSnapshot, total int64, err error)
+
+	// DeleteSnapshot
+	// @Description: delete performance snapshot of cluster and its statements permanently
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter clusterID
+	// @Parameter snapshotID
+	// @return error if snapshot non-existent
+	DeleteSnapshot(ctx context.Context, clusterID string, snapshotID string) error
+
+	// DeleteSnapshotsBefore
+	// @Description: delete performance snapshots of the type whose end time is before the time and their statements permanently
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter snapshotType
+	// @Parameter before
+	// @return int64 count of deleted snapshots
+	// @return error
+	DeleteSnapshotsBefore(ctx context.Context, snapshotType constants.PerformanceSnapshotType, before time.Time) (int64, error)
+}
@@ -0,0 +1,118 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package performance
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	dbCommon "github.com/pingcap/tiunimanager/models/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormPerformanceReadWrite struct {
+	dbCommon.GormDB
+}
+
+func NewGormPerformanceReadWrite(db *gorm.DB) *GormPerformanceReadWrite {
+	m := &GormPerformanceReadWrite{
+		dbCommon.WrapDB(db),
+	}
+	return m
+}
+
+func (m *GormPerformanceReadWrite) CreateSnapshot(ctx context.Context, snapshot *PerformanceSnapshot, statements []*SnapshotStatement) (*PerformanceSnapshot, error) {
+	snapshot.StatementCount = len(statements)
+	err := m.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(snapshot)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.NewErrorf(errors.TIUNIMANAGER_PERFORMANCE_SNAPSHOT_EXISTED, "%s performance snapshot of cluster %s ending at %s already exists",
+				snapshot.Type, snapshot.ClusterID, snapshot.EndTime.Format(time.RFC3339))
+		}
+		if len(statements) == 0 {
+			return nil
+		}
+		for _, statement := range statements {
+			statement.SnapshotID = snapshot.ID
+		}
+		return tx.Create(statements).Error
+	})
+	return snapshot, dbCommon.WrapDBError(err)
+}
+
+func (m *GormPerformanceReadWrite) GetSnapshot(ctx context.Context, clusterID string, snapshotID string) (*PerformanceSnapshot, []*SnapshotStatement, error) {
+	if "" == clusterID || "" == snapshotID {
+		return nil, nil, errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "cluster id and snapshot id are required")
+	}
+
+	snapshot := &PerformanceSnapshot{}
+	err := m.DB(ctx).First(snapshot, "id = ? AND cluster_id = ?", snapshotID, clusterID).Error
+	if err != nil {
+		return nil, nil, errors.NewErrorf(errors.TIUNIMANAGER_PERFORMANCE_SNAPSHOT_NOT_FOUND, "performance snapshot %s of cluster %s not found, %s", snapshotID, clusterID, err.Error())
+	}
+	statements := make([]*SnapshotStatement, 0)
+	err = m.DB(ctx).Model(&SnapshotStatement{}).Where("snapshot_id = ?", snapshotID).
+		Order("sum_latency desc").Find(&statements).Error
+	if err != nil {
+		return nil, nil, dbCommon.WrapDBError(err)
+	}
+	return snapshot, statements, nil
+}
+
+func (m *GormPerformanceReadWrite) QuerySnapshots(ctx context.Context, clusterID string, snapshotType constants.PerformanceSnapshotType, offset int, length int) (snapshots []*PerformanceSnapshot, total int64, err error) {
+	snapshots = make([]*PerformanceSnapshot, 0)
+	query := m.DB(ctx).Model(&PerformanceSnapshot{}).Where("cluster_id = ?", clusterID)
+	if snapshotType != "" {
+		query = query.Where("type = ?", string(snapshotType))
+	}
+	err = query.Count(&total).Order("end_time desc").Offset(offset).Limit(length).Find(&snapshots).Error
+	return snapshots, total, dbCommon.WrapDBError(err)
+}
+
+func (m *GormPerformanceReadWrite) DeleteSnapshot(ctx context.Context, clusterID string, snapshotID string) error {
+	snapshot, _, err := m.GetSnapshot(ctx, clusterID, snapshotID)
+	if err != nil {
+		return err
+	}
+	err = m.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("snapshot_id = ?", snapshot.ID).Delete(&SnapshotStatement{}).Error; err != nil {
+			return err
+		}
+		// deleted permanently, so that the time range can be captured again
+		return tx.Unscoped().Delete(snapshot).Error
+	})
+	return dbCommon.WrapDBError(err)
+}
+
+func (m *GormPerformanceReadWrite) DeleteSnapshotsBefore(ctx context.Context, snapshotType constants.PerformanceSnapshotType, before time.Time) (int64, error) {
+	var deleted int64
+	err := m.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		expired := tx.Unscoped().Model(&PerformanceSnapshot{}).Select("id").Where("type = ? AND end_time < ?", string(snapshotType), before)
+		if err := tx.Where("snapshot_id in (?)", expired).Delete(&SnapshotStatement{}).Error; err != nil {
+			return err
+		}
+		result := tx.Unscoped().Where("type = ? AND end_time < ?", string(snapshotType), before).Delete(&PerformanceSnapshot{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	return deleted, dbCommon.WrapDBError(err)
+}
@@ -0,0 +1,129 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package performance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/models/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGormPerformanceReadWrite_Snapshot(t *testing.T) {
+	end := time.Now()
+	snapshot, err := testRW.CreateSnapshot(context.TODO(), &PerformanceSnapshot{
+		Entity:    common.Entity{TenantId: "tenant"},
+		ClusterID: "snapshotCluster",
+		Name:      "before upgrade",
+		Type:      string(constants.PerformanceSnapshotManual),
+		StartTime: end.Add(-time.Hour),
+		EndTime:   end,
+	}, []*SnapshotStatement{
+		{Digest: "digest1", DigestText: "select * from t where id = ?", SchemaName: "test", ExecCount: 100, SumLatency: 1000},
+		{Digest: "digest2", DigestText: "update t set a = ? where id = ?", SchemaName: "test", ExecCount: 10, SumLatency: 5000},
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, snapshot.ID)
+	assert.Equal(t, 2, snapshot.StatementCount)
+
+	_, err = testRW.CreateSnapshot(context.TODO(), &PerformanceSnapshot{
+		Entity:    common.Entity{TenantId: "tenant"},
+		ClusterID: "snapshotCluster",
+		Type:      string(constants.PerformanceSnapshotPeriodic),
+		StartTime: end.Add(-2 * time.Hour),
+		EndTime:   end.Add(-time.Hour),
+	}, []*SnapshotStatement{})
+	assert.NoError(t, err)
+
+	t.Run("existed", func(t *testing.T) {
+		// captured by another replica
+		_, err := testRW.CreateSnapshot(context.TODO(), &PerformanceSnapshot{
+			Entity:    common.Entity{TenantId: "tenant"},
+			ClusterID: "snapshotCluster",
+			Type:      string(constants.PerformanceSnapshotPeriodic),
+			StartTime: end.Add(-2 * time.Hour),
+			EndTime:   end.Add(-time.Hour),
+		}, []*SnapshotStatement{
+			{Digest: "digest1", DigestText: "select * from t where id = ?", SchemaName: "test", ExecCount: 100, SumLatency: 1000},
+		})
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_PERFORMANCE_SNAPSHOT_EXISTED, err.(errors.EMError).GetCode())
+		var count int64
+		testRW.DB(context.TODO()).Model(&SnapshotStatement{}).Where("digest = ?", "digest1").Count(&count)
+		assert.Equal(t, int64(1), count)
+	})
+	t.Run("get", func(t *testing.T) {
+		got, statements, err := testRW.GetSnapshot(context.TODO(), "snapshotCluster", snapshot.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "before upgrade", got.Name)
+		assert.Equal(t, 2, len(statements))
+		assert.Equal(t, "digest2", statements[0].Digest)
+
+		_, _, err = testRW.GetSnapshot(context.TODO(), "otherCluster", snapshot.ID)
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_PERFORMANCE_SNAPSHOT_NOT_FOUND, err.(errors.EMError).GetCode())
+
+		_, _, err = testRW.GetSnapshot(context.TODO(), "", "")
+		assert.Error(t, err)
+	})
+	t.Run("query", func(t *testing.T) {
+		snapshots, total, err := testRW.QuerySnapshots(context.TODO(), "snapshotCluster", "", 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, snapshot.ID, snapshots[0].ID)
+
+		snapshots, total, err = testRW.QuerySnapshots(context.TODO(), "snapshotCluster", constants.PerformanceSnapshotPeriodic, 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, string(constants.PerformanceSnapshotPeriodic), snapshots[0].Type)
+	})
+	t.Run("delete before", func(t *testing.T) {
+		deleted, err := testRW.DeleteSnapshotsBefore(context.TODO(), constants.PerformanceSnapshotPeriodic, end.Add(-time.Minute))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		_, total, err := testRW.QuerySnapshots(context.TODO(), "snapshotCluster", "", 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+	})
+	t.Run("delete", func(t *testing.T) {
+		err := testRW.DeleteSnapshot(context.TODO(), "snapshotCluster", snapshot.ID)
+		assert.NoError(t, err)
+
+		_, _, err = testRW.GetSnapshot(context.TODO(), "snapshotCluster", snapshot.ID)
+		assert.Error(t, err)
+		var count int64
+		testRW.DB(context.TODO()).Model(&SnapshotStatement{}).Where("snapshot_id = ?", snapshot.ID).Count(&count)
+		assert.Equal(t, int64(0), count)
+
+		err = testRW.DeleteSnapshot(context.TODO(), "snapshotCluster", snapshot.ID)
+		assert.Error(t, err)
+
+		// the time range can be captured again
+		_, err = testRW.CreateSnapshot(context.TODO(), &PerformanceSnapshot{
+			Entity:    common.Entity{TenantId: "tenant"},
+			ClusterID: "snapshotCluster",
+			Type:      string(constants.PerformanceSnapshotManual),
+			StartTime: end.Add(-time.Hour),
+			EndTime:   end,
+		}, []*SnapshotStatement{})
+		assert.NoError(t, err)
+	})
+}
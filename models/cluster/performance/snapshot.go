@@ -0,0 +1,50 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package performance
+
+import (
+	"time"
+
+	"github.com/pingcap/tiunimanager/models/common"
+)
+
+// PerformanceSnapshot top statements of cluster captured within a time range, type is Periodic or Manual,
+// there is only one snapshot of the same type ending at the same time for a cluster
+type PerformanceSnapshot struct {
+	common.Entity
+	ClusterID      string    `gorm:"not null;size:32;index;uniqueIndex:cluster_type_end_time;comment:'cluster id'"`
+	Name           string    `gorm:"size:64"`
+	Type           string    `gorm:"not null;size:16;index;uniqueIndex:cluster_type_end_time"`
+	StartTime      time.Time `gorm:"default:null"`
+	EndTime        time.Time `gorm:"default:null;uniqueIndex:cluster_type_end_time"`
+	StatementCount int       `gorm:"default:0"`
+}
+
+// SnapshotStatement statistics of a statement digest in performance snapshot, latency is in nanoseconds
+type SnapshotStatement struct {
+	ID               uint      `gorm:"primaryKey"`
+	SnapshotID       string    `gorm:"not null;size:32;index;comment:'performance snapshot id'"`
+	Digest           string    `gorm:"not null;size:64"`
+	DigestText       string    `gorm:"type:text"`
+	SchemaName       string    `gorm:"size:64"`
+	ExecCount        int64     `gorm:"default:0"`
+	SumLatency       int64     `gorm:"default:0"`
+	MaxLatency       int64     `gorm:"default:0"`
+	SumProcessedKeys int64     `gorm:"default:0"`
+	SumTotalKeys     int64     `gorm:"default:0"`
+	FirstSeen        time.Time `gorm:"default:null"`
+	LastSeen         time.Time `gorm:"default:null"`
+}
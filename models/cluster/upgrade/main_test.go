@@ -58,6 +58,8 @@ func TestMain(m *testing.M) {
 				logins.Infof("open database successful, filepath: %s", dbFile)
 			}
 			db.Migrator().CreateTable(ProductUpgradePath{})
+			db.Migrator().CreateTable(UpgradeCampaign{})
+			db.Migrator().CreateTable(UpgradeCampaignCluster{})
 
 			testRW = NewGormProductUpgradePath(db)
 			return nil
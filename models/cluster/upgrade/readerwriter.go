@@ -65,4 +65,66 @@ type ReaderWriter interface {
 	// @Parameter id
 	// @return err if task non-existent
 	Delete(ctx context.Context, id string) (err error)
+
+	// CreateCampaign
+	// @Description: create an upgrade campaign with its clusters
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter campaign
+	// @Parameter clusters
+	// @return *UpgradeCampaign
+	// @return error
+	CreateCampaign(ctx context.Context, campaign *UpgradeCampaign, clusters []*UpgradeCampaignCluster) (*UpgradeCampaign, error)
+
+	// GetCampaign
+	// @Description: get upgrade campaign and its clusters in order of wave and sequence
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter id
+	// @return *UpgradeCampaign
+	// @return []*UpgradeCampaignCluster
+	// @return error if campaign non-existent
+	GetCampaign(ctx context.Context, id string) (*UpgradeCampaign, []*UpgradeCampaignCluster, error)
+
+	// QueryCampaigns
+	// @Description: query upgrade campaigns in order of creation desc, empty tenantID or status means all
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter tenantID
+	// @Parameter status
+	// @Parameter offset
+	// @Parameter length
+	// @return campaigns
+	// @return total
+	// @return err
+	QueryCampaigns(ctx context.Context, tenantID string, status constants.UpgradeCampaignStatus, offset int, length int) (campaigns []*UpgradeCampaign, total int64, err error)
+
+	// UpdateCampaign
+	// @Description: update status and progress of upgrade campaign only if its status is still the expected one
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter campaign
+	// @Parameter expectedStatus status of campaign when it was read
+	// @return bool false if the campaign has been changed by others
+	// @return error
+	UpdateCampaign(ctx context.Context, campaign *UpgradeCampaign, expectedStatus string) (bool, error)
+
+	// UpdateCampaignCluster
+	// @Description: update a cluster of upgrade campaign only if its status is still the expected one
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter cluster
+	// @Parameter expectedStatus status of cluster when it was read
+	// @return bool false if the cluster has been changed by others
+	// @return error
+	UpdateCampaignCluster(ctx context.Context, cluster *UpgradeCampaignCluster, expectedStatus string) (bool, error)
+
+	// ClaimCampaignCluster
+	// @Description: atomically change a waiting cluster of upgrade campaign to upgrading, only one caller is able to claim it
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter id id of the campaign cluster
+	// @return bool true if the cluster is claimed by the caller
+	// @return error
+	ClaimCampaignCluster(ctx context.Context, id string) (bool, error)
 }
@@ -117,3 +117,86 @@ func (m *GormProductUpgradePathReadWrite) Delete(ctx context.Context, id string)
 
 	return m.DB(ctx).First(path, "id = ?", id).Delete(path).Error
 }
+
+func (m *GormProductUpgradePathReadWrite) CreateCampaign(ctx context.Context, campaign *UpgradeCampaign, clusters []*UpgradeCampaignCluster) (*UpgradeCampaign, error) {
+	err := m.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(campaign).Error; err != nil {
+			return err
+		}
+		for _, cluster := range clusters {
+			cluster.CampaignID = campaign.ID
+		}
+		if len(clusters) > 0 {
+			return tx.Create(clusters).Error
+		}
+		return nil
+	})
+	return campaign, dbCommon.WrapDBError(err)
+}
+
+func (m *GormProductUpgradePathReadWrite) GetCampaign(ctx context.Context, id string) (*UpgradeCampaign, []*UpgradeCampaignCluster, error) {
+	if "" == id {
+		return nil, nil, errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "campaign id is required")
+	}
+
+	campaign := &UpgradeCampaign{}
+	err := m.DB(ctx).First(campaign, "id = ?", id).Error
+	if err != nil {
+		return nil, nil, errors.NewErrorf(errors.TIUNIMANAGER_UPGRADE_CAMPAIGN_NOT_FOUND, "upgrade campaign %s not found, %s", id, err.Error())
+	}
+
+	clusters := make([]*UpgradeCampaignCluster, 0)
+	err = m.DB(ctx).Model(&UpgradeCampaignCluster{}).Where("campaign_id = ?", id).
+		Order("wave").Order("sequence").Find(&clusters).Error
+	return campaign, clusters, dbCommon.WrapDBError(err)
+}
+
+func (m *GormProductUpgradePathReadWrite) QueryCampaigns(ctx context.Context, tenantID string, status constants.UpgradeCampaignStatus, offset int, length int) (campaigns []*UpgradeCampaign, total int64, err error) {
+	campaigns = make([]*UpgradeCampaign, 0)
+	query := m.DB(ctx).Model(&UpgradeCampaign{})
+	if tenantID != "" {
+		query = query.Where("tenant_id = ?", tenantID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", string(status))
+	}
+	err = query.Count(&total).Order("created_at desc").Offset(offset).Limit(length).Find(&campaigns).Error
+	return campaigns, total, dbCommon.WrapDBError(err)
+}
+
+func (m *GormProductUpgradePathReadWrite) UpdateCampaign(ctx context.Context, campaign *UpgradeCampaign, expectedStatus string) (bool, error) {
+	if "" == campaign.ID {
+		return false, errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "campaign id is required")
+	}
+	result := m.DB(ctx).Model(campaign).Where("status = ?", expectedStatus).
+		Select("*").Omit("id", "tenant_id", "created_at", "deleted_at").Updates(campaign)
+	if result.Error != nil {
+		return false, dbCommon.WrapDBError(result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (m *GormProductUpgradePathReadWrite) UpdateCampaignCluster(ctx context.Context, cluster *UpgradeCampaignCluster, expectedStatus string) (bool, error) {
+	if "" == cluster.ID {
+		return false, errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "campaign cluster id is required")
+	}
+	result := m.DB(ctx).Model(cluster).Where("status = ?", expectedStatus).
+		Select("*").Omit("id", "campaign_id", "created_at").Updates(cluster)
+	if result.Error != nil {
+		return false, dbCommon.WrapDBError(result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (m *GormProductUpgradePathReadWrite) ClaimCampaignCluster(ctx context.Context, id string) (bool, error) {
+	if "" == id {
+		return false, errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "campaign cluster id is required")
+	}
+	result := m.DB(ctx).Model(&UpgradeCampaignCluster{}).
+		Where("id = ? AND status = ?", id, string(constants.CampaignClusterWaiting)).
+		Update("status", string(constants.CampaignClusterUpgrading))
+	if result.Error != nil {
+		return false, dbCommon.WrapDBError(result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
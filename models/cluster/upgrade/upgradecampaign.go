@@ -0,0 +1,72 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package upgrade
+
+import (
+	"time"
+
+	"github.com/pingcap/tiunimanager/models/common"
+	"github.com/pingcap/tiunimanager/util/uuidutil"
+	"gorm.io/gorm"
+)
+
+// UpgradeCampaign upgrade a batch of clusters to the same version in ordered waves,
+// aggregate progress of clusters is kept in the campaign
+type UpgradeCampaign struct {
+	common.Entity
+	Name             string    `gorm:"size:64;comment:'name of the campaign'"`
+	TargetVersion    string    `gorm:"not null;size:64;comment:'version which clusters are upgraded to'"`
+	UpgradeWay       string    `gorm:"size:32;comment:'offline/online'"`
+	Selector         string    `gorm:"type:text;comment:'json of the cluster selector'"`
+	WaveSizes        string    `gorm:"size:256;comment:'json of cluster count of each wave, the last one is used by the rest waves'"`
+	Concurrency      int       `gorm:"comment:'max count of clusters upgrading at the same time'"`
+	FailureThreshold float64   `gorm:"comment:'campaign is halted if the failure rate exceeds it'"`
+	OperatorID       string    `gorm:"size:32;comment:'user who creates the campaign'"`
+	Waves            int       `gorm:"comment:'count of waves'"`
+	CurrentWave      int       `gorm:"comment:'index of the wave being upgraded, starts from 0'"`
+	Upgrading        int       `gorm:"comment:'count of clusters being upgraded'"`
+	Total            int       `gorm:"comment:'count of selected clusters'"`
+	Succeeded        int       `gorm:"comment:'count of clusters upgraded successfully'"`
+	Failed           int       `gorm:"comment:'count of clusters failed to upgrade'"`
+	Skipped          int       `gorm:"comment:'count of clusters skipped'"`
+	Message          string    `gorm:"type:text;comment:'reason of halting or canceling'"`
+	EndTime          time.Time `gorm:"default:null"`
+}
+
+// UpgradeCampaignCluster a cluster selected by upgrade campaign
+type UpgradeCampaignCluster struct {
+	ID              string    `gorm:"primaryKey;"`
+	CampaignID      string    `gorm:"not null;size:32;index;comment:'upgrade campaign id'"`
+	ClusterID       string    `gorm:"not null;size:32;comment:'cluster id'"`
+	ClusterName     string    `gorm:"size:64"`
+	OriginalVersion string    `gorm:"size:64;comment:'version of cluster when the campaign is created'"`
+	Wave            int       `gorm:"comment:'index of wave the cluster belongs to'"`
+	Sequence        int       `gorm:"comment:'clusters in the same wave are upgraded in order of sequence'"`
+	Status          string    `gorm:"not null;size:32"`
+	WorkFlowID      string    `gorm:"size:32;default:null;comment:'workflow id of upgrading'"`
+	Message         string    `gorm:"type:text"`
+	StartTime       time.Time `gorm:"default:null"`
+	EndTime         time.Time `gorm:"default:null"`
+	CreatedAt       time.Time `gorm:"autoCreateTime;<-:create;->;"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime"`
+}
+
+func (s *UpgradeCampaignCluster) BeforeCreate(tx *gorm.DB) (err error) {
+	if len(s.ID) == 0 {
+		s.ID = uuidutil.GenerateID()
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package upgrade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/models/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGormProductUpgradePathReadWrite_Campaign(t *testing.T) {
+	campaign, err := testRW.CreateCampaign(context.TODO(), &UpgradeCampaign{
+		Entity:        common.Entity{TenantId: "tenant01", Status: string(constants.UpgradeCampaignRunning)},
+		Name:          "campaign01",
+		TargetVersion: "v5.4.0",
+		Concurrency:   1,
+		Waves:         2,
+		Total:         2,
+	}, []*UpgradeCampaignCluster{
+		{ClusterID: "cluster02", Wave: 1, Status: string(constants.CampaignClusterWaiting)},
+		{ClusterID: "cluster01", Wave: 0, Status: string(constants.CampaignClusterWaiting)},
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, campaign.ID)
+
+	t.Run("get", func(t *testing.T) {
+		got, clusters, err := testRW.GetCampaign(context.TODO(), campaign.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "campaign01", got.Name)
+		assert.Equal(t, 2, len(clusters))
+		assert.Equal(t, "cluster01", clusters[0].ClusterID)
+		assert.Equal(t, campaign.ID, clusters[0].CampaignID)
+	})
+	t.Run("not found", func(t *testing.T) {
+		_, _, err := testRW.GetCampaign(context.TODO(), "unknown")
+		assert.Error(t, err)
+		_, _, err = testRW.GetCampaign(context.TODO(), "")
+		assert.Error(t, err)
+	})
+	t.Run("update", func(t *testing.T) {
+		got, clusters, err := testRW.GetCampaign(context.TODO(), campaign.ID)
+		assert.NoError(t, err)
+		got.Succeeded = 1
+		clusters[0].Status = string(constants.CampaignClusterSucceeded)
+		updated, err := testRW.UpdateCampaign(context.TODO(), got, string(constants.UpgradeCampaignRunning))
+		assert.NoError(t, err)
+		assert.True(t, updated)
+		updated, err = testRW.UpdateCampaignCluster(context.TODO(), clusters[0], string(constants.CampaignClusterWaiting))
+		assert.NoError(t, err)
+		assert.True(t, updated)
+
+		got, clusters, err = testRW.GetCampaign(context.TODO(), campaign.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, got.Succeeded)
+		assert.Equal(t, "tenant01", got.TenantId)
+		assert.Equal(t, string(constants.CampaignClusterSucceeded), clusters[0].Status)
+		assert.Equal(t, string(constants.CampaignClusterWaiting), clusters[1].Status)
+	})
+	t.Run("update conflict", func(t *testing.T) {
+		got, clusters, err := testRW.GetCampaign(context.TODO(), campaign.ID)
+		assert.NoError(t, err)
+		got.Succeeded = 2
+		updated, err := testRW.UpdateCampaign(context.TODO(), got, string(constants.UpgradeCampaignCanceled))
+		assert.NoError(t, err)
+		assert.False(t, updated)
+		clusters[1].Status = string(constants.CampaignClusterSkipped)
+		updated, err = testRW.UpdateCampaignCluster(context.TODO(), clusters[1], string(constants.CampaignClusterUpgrading))
+		assert.NoError(t, err)
+		assert.False(t, updated)
+
+		got, clusters, err = testRW.GetCampaign(context.TODO(), campaign.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, got.Succeeded)
+		assert.Equal(t, string(constants.CampaignClusterWaiting), clusters[1].Status)
+	})
+	t.Run("claim", func(t *testing.T) {
+		_, clusters, err := testRW.GetCampaign(context.TODO(), campaign.ID)
+		assert.NoError(t, err)
+		claimed, err := testRW.ClaimCampaignCluster(context.TODO(), clusters[1].ID)
+		assert.NoError(t, err)
+		assert.True(t, claimed)
+		claimed, err = testRW.ClaimCampaignCluster(context.TODO(), clusters[1].ID)
+		assert.NoError(t, err)
+		assert.False(t, claimed)
+		_, err = testRW.ClaimCampaignCluster(context.TODO(), "")
+		assert.Error(t, err)
+
+		_, clusters, err = testRW.GetCampaign(context.TODO(), campaign.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, string(constants.CampaignClusterUpgrading), clusters[1].Status)
+	})
+	t.Run("query", func(t *testing.T) {
+		campaigns, total, err := testRW.QueryCampaigns(context.TODO(), "tenant01", constants.UpgradeCampaignRunning, 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, campaign.ID, campaigns[0].ID)
+
+		_, total, err = testRW.QueryCampaigns(context.TODO(), "tenant01", constants.UpgradeCampaignCompleted, 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+	})
+}
@@ -21,16 +21,20 @@ import (
 	"github.com/pingcap/tiunimanager/common/constants"
 	"github.com/pingcap/tiunimanager/common/errors"
 	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/models/cluster/alert"
 	"github.com/pingcap/tiunimanager/models/cluster/backuprestore"
 	"github.com/pingcap/tiunimanager/models/cluster/changefeed"
+	"github.com/pingcap/tiunimanager/models/cluster/health"
 	"github.com/pingcap/tiunimanager/models/cluster/management"
 	"github.com/pingcap/tiunimanager/models/cluster/parameter"
+	"github.com/pingcap/tiunimanager/models/cluster/performance"
 	"github.com/pingcap/tiunimanager/models/cluster/upgrade"
 	"github.com/pingcap/tiunimanager/models/common"
 	"github.com/pingcap/tiunimanager/models/datatransfer/importexport"
 	"github.com/pingcap/tiunimanager/models/parametergroup"
 	"github.com/pingcap/tiunimanager/models/platform/check"
 	"github.com/pingcap/tiunimanager/models/platform/config"
+	"github.com/pingcap/tiunimanager/models/platform/notification"
 	"github.com/pingcap/tiunimanager/models/platform/product"
 	"github.com/pingcap/tiunimanager/models/platform/system"
 	"github.com/pingcap/tiunimanager/models/resource"
@@ -71,6 +75,10 @@ type database struct {
 	tiUPConfigReaderWriter           tiup.ReaderWriter
 	reportReaderWriter               check.ReaderWriter
 	systemReaderWriter               system.ReaderWriter
+	alertReaderWriter                alert.ReaderWriter
+	notificationReaderWriter         notification.ReaderWriter
+	performanceReaderWriter          performance.ReaderWriter
+	healthReaderWriter               health.ReaderWriter
 }
 
 func Open(fw *framework.BaseFramework) error {
@@ -107,11 +115,21 @@ func Open(fw *framework.BaseFramework) error {
 
 	defaultDb.initReaderWriters()
 
+	clusterTagsExisted := db.Migrator().HasTable(&management.ClusterTag{})
+
 	err = defaultDb.migrateTables()
 	if err != nil {
 		return err
 	}
 
+	// tags of clusters were kept only in TagInfo before the table of cluster tags was created
+	if dbFileExisted && !clusterTagsExisted {
+		if err = management.MigrateClusterTags(db); err != nil {
+			logins.Errorf("migrate cluster tags failed, err = %s", err.Error())
+			return err
+		}
+	}
+
 	// init data for empty database
 	if !dbFileExisted {
 		logins.Infof("init default data for new database")
@@ -191,14 +209,30 @@ func (p *database) migrateTables() (err error) {
 		new(workflow.WorkFlow),
 		new(workflow.WorkFlowNode),
 		new(upgrade.ProductUpgradePath),
+		new(upgrade.UpgradeCampaign),
+		new(upgrade.UpgradeCampaignCluster),
 		new(management.Cluster),
 		new(management.ClusterInstance),
 		new(management.ClusterRelation),
 		new(management.ClusterTopologySnapshot),
 		new(management.DBUser),
+		new(management.PendingOperation),
+		new(management.ClusterTag),
+		new(management.RecycledCluster),
+		new(alert.AlertRule),
+		new(alert.AlertSilence),
+		new(alert.AlertHistory),
+		new(alert.AlertReceiver),
+		new(notification.NotificationChannel),
+		new(notification.NotificationRoute),
+		new(notification.NotificationDelivery),
+		new(performance.PerformanceSnapshot),
+		new(performance.SnapshotStatement),
+		new(health.HealthEvent),
 		new(importexport.DataTransportRecord),
 		new(backuprestore.BackupRecord),
 		new(backuprestore.BackupStrategy),
+		new(backuprestore.LogBackupTask),
 		new(config.SystemConfig),
 		new(secondparty.SecondPartyOperation),
 		new(parametergroup.Parameter),
@@ -246,6 +280,10 @@ func (p *database) initReaderWriters() {
 	defaultDb.tiUPConfigReaderWriter = tiup.NewGormTiupConfigReadWrite(defaultDb.base)
 	defaultDb.reportReaderWriter = check.NewReportReadWrite(defaultDb.base)
 	defaultDb.systemReaderWriter = system.NewSystemReadWrite(defaultDb.base)
+	defaultDb.alertReaderWriter = alert.NewGormAlertReadWrite(defaultDb.base)
+	defaultDb.notificationReaderWriter = notification.NewGormNotificationReadWrite(defaultDb.base)
+	defaultDb.performanceReaderWriter = performance.NewGormPerformanceReadWrite(defaultDb.base)
+	defaultDb.healthReaderWriter = health.NewGormHealthReadWrite(defaultDb.base)
 }
 
 func GetChangeFeedReaderWriter() changefeed.ReaderWriter {
@@ -392,6 +430,38 @@ func SetSystemReaderWriter(rw system.ReaderWriter) {
 	defaultDb.systemReaderWriter = rw
 }
 
+func GetAlertReaderWriter() alert.ReaderWriter {
+	return defaultDb.alertReaderWriter
+}
+
+func SetAlertReaderWriter(rw alert.ReaderWriter) {
+	defaultDb.alertReaderWriter = rw
+}
+
+func GetNotificationReaderWriter() notification.ReaderWriter {
+	return defaultDb.notificationReaderWriter
+}
+
+func SetNotificationReaderWriter(rw notification.ReaderWriter) {
+	defaultDb.notificationReaderWriter = rw
+}
+
+func GetPerformanceReaderWriter() performance.ReaderWriter {
+	return defaultDb.performanceReaderWriter
+}
+
+func SetPerformanceReaderWriter(rw performance.ReaderWriter) {
+	defaultDb.performanceReaderWriter = rw
+}
+
+func GetHealthReaderWriter() health.ReaderWriter {
+	return defaultDb.healthReaderWriter
+}
+
+func SetHealthReaderWriter(rw health.ReaderWriter) {
+	defaultDb.healthReaderWriter = rw
+}
+
 // Transaction
 // @Description: Transaction for service
 // @Parameter ctx
@@ -0,0 +1,51 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package notification
+
+import (
+	"github.com/pingcap/tiunimanager/models/common"
+)
+
+// NotificationChannel channel which notifications are sent to, password of SMTP is kept encrypted out of config
+type NotificationChannel struct {
+	common.Entity
+	Name     string          `gorm:"not null;size:64;comment:'channel name, unique in platform'"`
+	Type     string          `gorm:"not null;size:32;comment:'Webhook/Email/Slack'"`
+	Config   string          `gorm:"type:text;comment:'json of channel config without secret'"`
+	Password common.Password `gorm:"comment:'password of SMTP'"`
+}
+
+// NotificationRoute route alerts which match severities, tag selector of cluster and tenant of cluster to channels
+type NotificationRoute struct {
+	common.Entity
+	Name          string `gorm:"not null;size:64;comment:'route name'"`
+	Severities    string `gorm:"size:128;comment:'severities separated by comma, empty matches all'"`
+	TagSelector   string `gorm:"type:text;comment:'tag selector of cluster, empty matches all'"`
+	MatchTenantID string `gorm:"size:32;comment:'tenant of cluster, empty matches all'"`
+	ChannelIDs    string `gorm:"type:text;comment:'json of channel ids'"`
+}
+
+// NotificationDelivery log of sending a notification, status is Pending, Sending, Success or Failed
+type NotificationDelivery struct {
+	common.Entity
+	ChannelID   string `gorm:"not null;size:32;index;comment:'notification channel id'"`
+	ChannelName string `gorm:"size:64"`
+	ClusterID   string `gorm:"size:32;index;comment:'empty for test notification'"`
+	Subject     string `gorm:"type:text"`
+	Test        bool   `gorm:"default:false;comment:'whether it is a test notification'"`
+	Content     string `gorm:"type:text;comment:'json of queued notification'"`
+	Message     string `gorm:"type:text;comment:'error message of delivery'"`
+}
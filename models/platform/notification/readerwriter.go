@@ -0,0 +1,177 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+)
+
+type ReaderWriter interface {
+	// CreateChannel
+	// @Description: create notification channel, names of channels are unique
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter channel
+	// @return *NotificationChannel
+	// @return error
+	CreateChannel(ctx context.Context, channel *NotificationChannel) (*NotificationChannel, error)
+
+	// GetChannel
+	// @Description: get notification channel
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter id
+	// @return *NotificationChannel
+	// @return error if channel non-existent
+	GetChannel(ctx context.Context, id string) (*NotificationChannel, error)
+
+	// QueryChannels
+	// @Description: query notification channels in order of name, empty channelType means all
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter channelType
+	// @Parameter offset
+	// @Parameter length
+	// @return channels
+	// @return total
+	// @return err
+	QueryChannels(ctx context.Context, channelType constants.NotificationChannelType, offset int, length int) (channels []*NotificationChannel, total int64, err error)
+
+	// UpdateChannel
+	// @Description: update name, config and password of notification channel
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter channel
+	// @return error
+	UpdateChannel(ctx context.Context, channel *NotificationChannel) error
+
+	// DeleteChannel
+	// @Description: delete notification channel
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter id
+	// @return error
+	DeleteChannel(ctx context.Context, id string) error
+
+	// CreateRoute
+	// @Description: create notification route
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter route
+	// @return *NotificationRoute
+	// @return error
+	CreateRoute(ctx context.Context, route *NotificationRoute) (*NotificationRoute, error)
+
+	// GetRoute
+	// @Description: get notification route
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter id
+	// @return *NotificationRoute
+	// @return error if route non-existent
+	GetRoute(ctx context.Context, id string) (*NotificationRoute, error)
+
+	// QueryRoutes
+	// @Description: query notification routes in order of name
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter offset
+	// @Parameter length
+	// @return routes
+	// @return total
+	// @return err
+	QueryRoutes(ctx context.Context, offset int, length int) (routes []*NotificationRoute, total int64, err error)
+
+	// UpdateRoute
+	// @Description: update notification route
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter route
+	// @return error
+	UpdateRoute(ctx context.Context, route *NotificationRoute) error
+
+	// DeleteRoute
+	// @Description: delete notification route
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter id
+	// @return error
+	DeleteRoute(ctx context.Context, id string) error
+
+	// CreateDelivery
+	// @Description: record a delivery of notification
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter delivery
+	// @return *NotificationDelivery
+	// @return error
+	CreateDelivery(ctx context.Context, delivery *NotificationDelivery) (*NotificationDelivery, error)
+
+	// QueryDeliveries
+	// @Description: query deliveries in order of creation desc, empty conditions mean all
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter channelID
+	// @Parameter clusterID
+	// @Parameter status
+	// @Parameter offset
+	// @Parameter length
+	// @return deliveries
+	// @return total
+	// @return err
+	QueryDeliveries(ctx context.Context, channelID string, clusterID string, status constants.NotificationDeliveryStatus, offset int, length int) (deliveries []*NotificationDelivery, total int64, err error)
+
+	// QueryPendingDeliveries
+	// @Description: query pending deliveries in order of creation
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter length
+	// @return []*NotificationDelivery
+	// @return error
+	QueryPendingDeliveries(ctx context.Context, length int) ([]*NotificationDelivery, error)
+
+	// ClaimDelivery
+	// @Description: change status of delivery from Pending to Sending, only one of concurrent claims succeeds
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter id
+	// @return bool false if delivery is not pending
+	// @return error
+	ClaimDelivery(ctx context.Context, id string) (bool, error)
+
+	// UpdateDeliveryStatus
+	// @Description: update status and error message of delivery
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter id
+	// @Parameter status
+	// @Parameter message
+	// @return error
+	UpdateDeliveryStatus(ctx context.Context, id string, status constants.NotificationDeliveryStatus, message string) error
+
+	// FailStaleDeliveries
+	// @Description: mark deliveries which are still sending since the time as failed, e.g. the sender is restarted
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter before
+	// @Parameter message
+	// @return int64 count of failed deliveries
+	// @return error
+	FailStaleDeliveries(ctx context.Context, before time.Time, message string) (int64, error)
+}
@@ -0,0 +1,210 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	dbCommon "github.com/pingcap/tiunimanager/models/common"
+	"gorm.io/gorm"
+)
+
+type GormNotificationReadWrite struct {
+	dbCommon.GormDB
+}
+
+func NewGormNotificationReadWrite(db *gorm.DB) *GormNotificationReadWrite {
+	m := &GormNotificationReadWrite{
+		dbCommon.WrapDB(db),
+	}
+	return m
+}
+
+func (m *GormNotificationReadWrite) CreateChannel(ctx context.Context, channel *NotificationChannel) (*NotificationChannel, error) {
+	err := m.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&NotificationChannel{}).Where("name = ?", channel.Name).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return errors.NewErrorf(errors.TIUNIMANAGER_NOTIFICATION_CHANNEL_INVALID, "notification channel %s already exists", channel.Name)
+		}
+		return tx.Create(channel).Error
+	})
+	return channel, dbCommon.WrapDBError(err)
+}
+
+func (m *GormNotificationReadWrite) GetChannel(ctx context.Context, id string) (*NotificationChannel, error) {
+	if "" == id {
+		return nil, errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "channel id is required")
+	}
+
+	channel := &NotificationChannel{}
+	err := m.DB(ctx).First(channel, "id = ?", id).Error
+	if err != nil {
+		return nil, errors.NewErrorf(errors.TIUNIMANAGER_NOTIFICATION_CHANNEL_NOT_FOUND, "notification channel %s not found, %s", id, err.Error())
+	}
+	return channel, nil
+}
+
+func (m *GormNotificationReadWrite) QueryChannels(ctx context.Context, channelType constants.NotificationChannelType, offset int, length int) (channels []*NotificationChannel, total int64, err error) {
+	channels = make([]*NotificationChannel, 0)
+	query := m.DB(ctx).Model(&NotificationChannel{})
+	if channelType != "" {
+		query = query.Where("type = ?", string(channelType))
+	}
+	err = query.Count(&total).Order("name").Offset(offset).Limit(length).Find(&channels).Error
+	return channels, total, dbCommon.WrapDBError(err)
+}
+
+func (m *GormNotificationReadWrite) UpdateChannel(ctx context.Context, channel *NotificationChannel) error {
+	if _, err := m.GetChannel(ctx, channel.ID); err != nil {
+		return err
+	}
+	var count int64
+	err := m.DB(ctx).Model(&NotificationChannel{}).Where("name = ? AND id <> ?", channel.Name, channel.ID).Count(&count).Error
+	if err != nil {
+		return dbCommon.WrapDBError(err)
+	}
+	if count > 0 {
+		return errors.NewErrorf(errors.TIUNIMANAGER_NOTIFICATION_CHANNEL_INVALID, "notification channel %s already exists", channel.Name)
+	}
+	err = m.DB(ctx).Model(&NotificationChannel{}).Where("id = ?", channel.ID).Updates(map[string]interface{}{
+		"name":     channel.Name,
+		"config":   channel.Config,
+		"password": channel.Password,
+	}).Error
+	return dbCommon.WrapDBError(err)
+}
+
+func (m *GormNotificationReadWrite) DeleteChannel(ctx context.Context, id string) error {
+	channel, err := m.GetChannel(ctx, id)
+	if err != nil {
+		return err
+	}
+	return dbCommon.WrapDBError(m.DB(ctx).Delete(channel).Error)
+}
+
+func (m *GormNotificationReadWrite) CreateRoute(ctx context.Context, route *NotificationRoute) (*NotificationRoute, error) {
+	err := m.DB(ctx).Create(route).Error
+	return route, dbCommon.WrapDBError(err)
+}
+
+func (m *GormNotificationReadWrite) GetRoute(ctx context.Context, id string) (*NotificationRoute, error) {
+	if "" == id {
+		return nil, errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "route id is required")
+	}
+
+	route := &NotificationRoute{}
+	err := m.DB(ctx).First(route, "id = ?", id).Error
+	if err != nil {
+		return nil, errors.NewErrorf(errors.TIUNIMANAGER_NOTIFICATION_ROUTE_NOT_FOUND, "notification route %s not found, %s", id, err.Error())
+	}
+	return route, nil
+}
+
+func (m *GormNotificationReadWrite) QueryRoutes(ctx context.Context, offset int, length int) (routes []*NotificationRoute, total int64, err error) {
+	routes = make([]*NotificationRoute, 0)
+	err = m.DB(ctx).Model(&NotificationRoute{}).Count(&total).Order("name").Offset(offset).Limit(length).Find(&routes).Error
+	return routes, total, dbCommon.WrapDBError(err)
+}
+
+func (m *GormNotificationReadWrite) UpdateRoute(ctx context.Context, route *NotificationRoute) error {
+	if _, err := m.GetRoute(ctx, route.ID); err != nil {
+		return err
+	}
+	err := m.DB(ctx).Model(&NotificationRoute{}).Where("id = ?", route.ID).Updates(map[string]interface{}{
+		"name":            route.Name,
+		"severities":      route.Severities,
+		"tag_selector":    route.TagSelector,
+		"match_tenant_id": route.MatchTenantID,
+		"channel_ids":     route.ChannelIDs,
+	}).Error
+	return dbCommon.WrapDBError(err)
+}
+
+func (m *GormNotificationReadWrite) DeleteRoute(ctx context.Context, id string) error {
+	route, err := m.GetRoute(ctx, id)
+	if err != nil {
+		return err
+	}
+	return dbCommon.WrapDBError(m.DB(ctx).Delete(route).Error)
+}
+
+func (m *GormNotificationReadWrite) CreateDelivery(ctx context.Context, delivery *NotificationDelivery) (*NotificationDelivery, error) {
+	err := m.DB(ctx).Create(delivery).Error
+	return delivery, dbCommon.WrapDBError(err)
+}
+
+func (m *GormNotificationReadWrite) QueryDeliveries(ctx context.Context, channelID string, clusterID string, status constants.NotificationDeliveryStatus, offset int, length int) (deliveries []*NotificationDelivery, total int64, err error) {
+	deliveries = make([]*NotificationDelivery, 0)
+	query := m.DB(ctx).Model(&NotificationDelivery{})
+	if channelID != "" {
+		query = query.Where("channel_id = ?", channelID)
+	}
+	if clusterID != "" {
+		query = query.Where("cluster_id = ?", clusterID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", string(status))
+	}
+	err = query.Count(&total).Order("created_at desc").Offset(offset).Limit(length).Find(&deliveries).Error
+	return deliveries, total, dbCommon.WrapDBError(err)
+}
+
+func (m *GormNotificationReadWrite) QueryPendingDeliveries(ctx context.Context, length int) ([]*NotificationDelivery, error) {
+	deliveries := make([]*NotificationDelivery, 0)
+	err := m.DB(ctx).Where("status = ?", string(constants.NotificationDeliveryPending)).
+		Order("created_at").Limit(length).Find(&deliveries).Error
+	return deliveries, dbCommon.WrapDBError(err)
+}
+
+func (m *GormNotificationReadWrite) ClaimDelivery(ctx context.Context, id string) (bool, error) {
+	if "" == id {
+		return false, errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "delivery id is required")
+	}
+	result := m.DB(ctx).Model(&NotificationDelivery{}).
+		Where("id = ? AND status = ?", id, string(constants.NotificationDeliveryPending)).
+		Update("status", string(constants.NotificationDeliverySending))
+	if result.Error != nil {
+		return false, dbCommon.WrapDBError(result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (m *GormNotificationReadWrite) UpdateDeliveryStatus(ctx context.Context, id string, status constants.NotificationDeliveryStatus, message string) error {
+	if "" == id {
+		return errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "delivery id is required")
+	}
+	err := m.DB(ctx).Model(&NotificationDelivery{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":  string(status),
+		"message": message,
+	}).Error
+	return dbCommon.WrapDBError(err)
+}
+
+func (m *GormNotificationReadWrite) FailStaleDeliveries(ctx context.Context, before time.Time, message string) (int64, error) {
+	result := m.DB(ctx).Model(&NotificationDelivery{}).
+		Where("status = ? AND updated_at < ?", string(constants.NotificationDeliverySending), before).
+		Updates(map[string]interface{}{
+			"status":  string(constants.NotificationDeliveryFailed),
+			"message": message,
+		})
+	return result.RowsAffected, dbCommon.WrapDBError(result.Error)
+}
@@ -0,0 +1,216 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package notification
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/models/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGormNotificationReadWrite_Channel(t *testing.T) {
+	webhook, err := testRW.CreateChannel(context.TODO(), &NotificationChannel{
+		Entity: common.Entity{TenantId: "admin"},
+		Name:   "webhook",
+		Type:   string(constants.NotificationChannelWebhook),
+		Config: `{"url":"http://127.0.0.1:8080/hook"}`,
+	})
+	assert.NoError(t, err)
+	email, err := testRW.CreateChannel(context.TODO(), &NotificationChannel{
+		Entity:   common.Entity{TenantId: "admin"},
+		Name:     "email",
+		Type:     string(constants.NotificationChannelEmail),
+		Config:   `{"smtpHost":"127.0.0.1","smtpPort":25}`,
+		Password: "secret",
+	})
+	assert.NoError(t, err)
+
+	t.Run("duplicated", func(t *testing.T) {
+		_, err := testRW.CreateChannel(context.TODO(), &NotificationChannel{
+			Entity: common.Entity{TenantId: "admin"},
+			Name:   "webhook",
+			Type:   string(constants.NotificationChannelSlack),
+		})
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_NOTIFICATION_CHANNEL_INVALID, err.(errors.EMError).GetCode())
+	})
+	t.Run("get", func(t *testing.T) {
+		got, err := testRW.GetChannel(context.TODO(), email.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, common.Password("secret"), got.Password)
+
+		_, err = testRW.GetChannel(context.TODO(), "unknown")
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_NOTIFICATION_CHANNEL_NOT_FOUND, err.(errors.EMError).GetCode())
+	})
+	t.Run("query", func(t *testing.T) {
+		channels, total, err := testRW.QueryChannels(context.TODO(), "", 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, "email", channels[0].Name)
+
+		channels, total, err = testRW.QueryChannels(context.TODO(), constants.NotificationChannelWebhook, 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, webhook.ID, channels[0].ID)
+	})
+	t.Run("update", func(t *testing.T) {
+		email.Name = "webhook"
+		err := testRW.UpdateChannel(context.TODO(), email)
+		assert.Error(t, err)
+
+		email.Name = "mail"
+		email.Password = "changed"
+		err = testRW.UpdateChannel(context.TODO(), email)
+		assert.NoError(t, err)
+		got, err := testRW.GetChannel(context.TODO(), email.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "mail", got.Name)
+		assert.Equal(t, common.Password("changed"), got.Password)
+	})
+	t.Run("delete", func(t *testing.T) {
+		err := testRW.DeleteChannel(context.TODO(), webhook.ID)
+		assert.NoError(t, err)
+		_, err = testRW.GetChannel(context.TODO(), webhook.ID)
+		assert.Error(t, err)
+
+		err = testRW.DeleteChannel(context.TODO(), webhook.ID)
+		assert.Error(t, err)
+	})
+}
+
+func TestGormNotificationReadWrite_Route(t *testing.T) {
+	route, err := testRW.CreateRoute(context.TODO(), &NotificationRoute{
+		Entity:      common.Entity{TenantId: "admin"},
+		Name:        "critical",
+		Severities:  "emergency,critical",
+		TagSelector: "env=prod",
+		ChannelIDs:  `["channel01"]`,
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, route.ID)
+
+	t.Run("get", func(t *testing.T) {
+		got, err := testRW.GetRoute(context.TODO(), route.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "env=prod", got.TagSelector)
+
+		_, err = testRW.GetRoute(context.TODO(), "unknown")
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_NOTIFICATION_ROUTE_NOT_FOUND, err.(errors.EMError).GetCode())
+	})
+	t.Run("update", func(t *testing.T) {
+		route.MatchTenantID = "tenant01"
+		route.ChannelIDs = `["channel01","channel02"]`
+		err := testRW.UpdateRoute(context.TODO(), route)
+		assert.NoError(t, err)
+
+		routes, total, err := testRW.QueryRoutes(context.TODO(), 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, "tenant01", routes[0].MatchTenantID)
+		assert.Equal(t, `["channel01","channel02"]`, routes[0].ChannelIDs)
+	})
+	t.Run("delete", func(t *testing.T) {
+		err := testRW.DeleteRoute(context.TODO(), route.ID)
+		assert.NoError(t, err)
+		_, total, err := testRW.QueryRoutes(context.TODO(), 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+	})
+}
+
+func TestGormNotificationReadWrite_Delivery(t *testing.T) {
+	for _, d := range []*NotificationDelivery{
+		{Entity: common.Entity{TenantId: "admin", Status: string(constants.NotificationDeliverySuccess)}, ChannelID: "deliveryChannel", Test: true, Subject: "test"},
+		{Entity: common.Entity{TenantId: "tenant01", Status: string(constants.NotificationDeliverySuccess)}, ChannelID: "deliveryChannel", ClusterID: "cluster01", Subject: "firing"},
+		{Entity: common.Entity{TenantId: "tenant01", Status: string(constants.NotificationDeliveryFailed)}, ChannelID: "deliveryChannel", ClusterID: "cluster01", Subject: "resolved", Message: "timeout"},
+	} {
+		_, err := testRW.CreateDelivery(context.TODO(), d)
+		assert.NoError(t, err)
+	}
+
+	deliveries, total, err := testRW.QueryDeliveries(context.TODO(), "deliveryChannel", "", "", 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Len(t, deliveries, 3)
+
+	deliveries, total, err = testRW.QueryDeliveries(context.TODO(), "", "cluster01", constants.NotificationDeliveryFailed, 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "timeout", deliveries[0].Message)
+}
+
+func TestGormNotificationReadWrite_PendingDelivery(t *testing.T) {
+	delivery, err := testRW.CreateDelivery(context.TODO(), &NotificationDelivery{
+		Entity:    common.Entity{TenantId: "tenant01", Status: string(constants.NotificationDeliveryPending)},
+		ChannelID: "pendingChannel",
+		ClusterID: "cluster02",
+		Subject:   "firing",
+		Content:   "{}",
+	})
+	assert.NoError(t, err)
+
+	pending, err := testRW.QueryPendingDeliveries(context.TODO(), 10)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, delivery.ID, pending[0].ID)
+
+	t.Run("claim", func(t *testing.T) {
+		claimed, err := testRW.ClaimDelivery(context.TODO(), delivery.ID)
+		assert.NoError(t, err)
+		assert.True(t, claimed)
+		// delivery is claimed only once
+		claimed, err = testRW.ClaimDelivery(context.TODO(), delivery.ID)
+		assert.NoError(t, err)
+		assert.False(t, claimed)
+
+		pending, err := testRW.QueryPendingDeliveries(context.TODO(), 10)
+		assert.NoError(t, err)
+		assert.Empty(t, pending)
+
+		_, err = testRW.ClaimDelivery(context.TODO(), "")
+		assert.Error(t, err)
+	})
+	t.Run("stale", func(t *testing.T) {
+		count, err := testRW.FailStaleDeliveries(context.TODO(), time.Now().Add(-time.Hour), "interrupted")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+		count, err = testRW.FailStaleDeliveries(context.TODO(), time.Now().Add(time.Hour), "interrupted")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		deliveries, _, err := testRW.QueryDeliveries(context.TODO(), "pendingChannel", "", constants.NotificationDeliveryFailed, 0, 10)
+		assert.NoError(t, err)
+		assert.Len(t, deliveries, 1)
+		assert.Equal(t, "interrupted", deliveries[0].Message)
+	})
+	t.Run("update", func(t *testing.T) {
+		err := testRW.UpdateDeliveryStatus(context.TODO(), delivery.ID, constants.NotificationDeliverySuccess, "")
+		assert.NoError(t, err)
+		deliveries, _, err := testRW.QueryDeliveries(context.TODO(), "pendingChannel", "", constants.NotificationDeliverySuccess, 0, 10)
+		assert.NoError(t, err)
+		assert.Len(t, deliveries, 1)
+		assert.Empty(t, deliveries[0].Message)
+
+		assert.Error(t, testRW.UpdateDeliveryStatus(context.TODO(), "", constants.NotificationDeliverySuccess, ""))
+	})
+}
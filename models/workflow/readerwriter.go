@@ -89,7 +89,7 @@ type ReaderWriter interface {
 	GetWorkFlowNode(ctx context.Context, nodeId string) (node *WorkFlowNode, err error)
 
 	// UpdateWorkFlowDetail
-	// @Description: update workflow detail nodes
+	// @Description: update workflow detail nodes, rejected if the workflow has been updated with a greater fencing token
 	// @Receiver m
 	// @Parameter ctx
 	// @Parameter flow
@@ -97,6 +97,15 @@ type ReaderWriter interface {
 	// @Return error
 	UpdateWorkFlowDetail(ctx context.Context, flow *WorkFlow, nodes []*WorkFlowNode) (err error)
 
+	// ResumeWorkFlow
+	// @Description: set the failed workflow to processing and create the node to resume from, in one transaction
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter flowId
+	// @Parameter node
+	// @Return error TIUNIMANAGER_WORKFLOW_RESUME_FAILED if the workflow is not failed any more
+	ResumeWorkFlow(ctx context.Context, flowId string, node *WorkFlowNode) (err error)
+
 	// QueryDetailWorkFlow
 	// @Description: detail workflow with nodes
 	// @Receiver m
@@ -27,6 +27,8 @@ type WorkFlow struct {
 	BizID   string `gorm:"default:null;<-:create"`
 	BizType string `gorm:"default:null"`
 	Context string `gorm:"default:null"`
+	// FencingToken token of the replica which updated the workflow last, a stale owner with smaller token is rejected
+	FencingToken int64 `gorm:"default:0;comment:'fencing token of the replica owning the workflow'"`
 }
 
 func (flow *WorkFlow) Finished() bool {
@@ -17,6 +17,7 @@ package workflow
 
 import (
 	"context"
+	"github.com/pingcap/tiunimanager/common/constants"
 	"github.com/pingcap/tiunimanager/common/errors"
 	"github.com/pingcap/tiunimanager/library/framework"
 	dbCommon "github.com/pingcap/tiunimanager/models/common"
@@ -105,9 +106,47 @@ func (m *WorkFlowReadWrite) GetWorkFlowNode(ctx context.Context, nodeId string)
 	return node, m.DB(ctx).Model(node).Where("id = ?", nodeId).First(node).Error
 }
 
+// updateWorkFlowWithFencing
+// @Description: update status and context of the workflow only if no replica with greater fencing token has updated it
+// @Receiver m
+// @Parameter ctx
+// @Parameter flow
+// @return err TIUNIMANAGER_WORKFLOW_OWNERSHIP_LOST if the workflow has been taken over by another replica
+func (m *WorkFlowReadWrite) updateWorkFlowWithFencing(ctx context.Context, flow *WorkFlow) (err error) {
+	if "" == flow.ID || "" == flow.Status {
+		return errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "")
+	}
+
+	updates := map[string]interface{}{
+		"status":        flow.Status,
+		"fencing_token": flow.FencingToken,
+	}
+	if "" != flow.Context {
+		updates["context"] = flow.Context
+	}
+	result := m.DB(ctx).Model(&WorkFlow{}).
+		Where("id = ? AND fencing_token <= ?", flow.ID, flow.FencingToken).
+		Updates(updates)
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.Error
+	}
+
+	// no row is affected, the workflow is not found, taken over, or just unchanged
+	current := &WorkFlow{}
+	err = m.DB(ctx).First(current, "id = ?", flow.ID).Error
+	if err != nil {
+		return errors.NewErrorf(errors.TIUNIMANAGER_FLOW_NOT_FOUND, "flow %s not found", flow.ID)
+	}
+	if current.FencingToken > flow.FencingToken {
+		return errors.NewErrorf(errors.TIUNIMANAGER_WORKFLOW_OWNERSHIP_LOST,
+			"workflow %s has been taken over with token %d, current token %d", flow.ID, current.FencingToken, flow.FencingToken)
+	}
+	return nil
+}
+
 func (m *WorkFlowReadWrite) UpdateWorkFlowDetail(ctx context.Context, flow *WorkFlow, nodes []*WorkFlowNode) (err error) {
 	return m.DB(ctx).Transaction(func(tx *gorm.DB) error {
-		err = m.UpdateWorkFlow(dbCommon.CtxWithTransaction(ctx, tx), flow.ID, flow.Status, flow.Context)
+		err = m.updateWorkFlowWithFencing(dbCommon.CtxWithTransaction(ctx, tx), flow)
 		if err != nil {
 			framework.LogWithContext(ctx).Errorf("update workflow %+v failed %s", flow, err.Error())
 			tx.Rollback()
@@ -125,6 +164,25 @@ func (m *WorkFlowReadWrite) UpdateWorkFlowDetail(ctx context.Context, flow *Work
 	})
 }
 
+func (m *WorkFlowReadWrite) ResumeWorkFlow(ctx context.Context, flowId string, node *WorkFlowNode) (err error) {
+	if "" == flowId || node == nil {
+		return errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "")
+	}
+	return m.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&WorkFlow{}).
+			Where("id = ? AND status = ?", flowId, constants.WorkFlowStatusError).
+			Update("status", constants.WorkFlowStatusProcessing)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			// resumed or abandoned concurrently
+			return errors.NewErrorf(errors.TIUNIMANAGER_WORKFLOW_RESUME_FAILED, "workflow %s is not failed", flowId)
+		}
+		return tx.Create(node).Error
+	})
+}
+
 func (m *WorkFlowReadWrite) QueryDetailWorkFlow(ctx context.Context, flowId string) (flow *WorkFlow, nodes []*WorkFlowNode, err error) {
 	if "" == flowId {
 		return nil, nil, errors.NewError(errors.TIUNIMANAGER_PARAMETER_INVALID, "empty flow id")
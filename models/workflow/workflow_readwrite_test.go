@@ -20,6 +20,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
 	"github.com/pingcap/tiunimanager/models/common"
 	"github.com/stretchr/testify/assert"
 )
@@ -250,3 +252,87 @@ func TestFlowReadWrite_UpdateWorkFlowDetail(t *testing.T) {
 	err := rw.UpdateWorkFlowDetail(context.TODO(), &WorkFlow{Entity: common.Entity{ID: "999"}}, nodes)
 	assert.Error(t, err)
 }
+
+func TestFlowReadWrite_UpdateWorkFlowDetail_fencing(t *testing.T) {
+	flowCreate, err := rw.CreateWorkFlow(context.TODO(), &WorkFlow{
+		Entity: common.Entity{
+			TenantId: "tenantId",
+			Status:   "FlowInitStatus",
+		},
+		Name:    "flowName",
+		BizID:   "clusterId",
+		Context: "flowContext",
+	})
+	assert.NoError(t, err)
+
+	t.Run("new owner", func(t *testing.T) {
+		flowCreate.FencingToken = 5
+		flowCreate.Status = "Processing"
+		err := rw.UpdateWorkFlowDetail(context.TODO(), flowCreate, nil)
+		assert.NoError(t, err)
+
+		flowQuery, err := rw.GetWorkFlow(context.TODO(), flowCreate.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), flowQuery.FencingToken)
+		assert.Equal(t, "Processing", flowQuery.Status)
+	})
+	t.Run("same owner", func(t *testing.T) {
+		err := rw.UpdateWorkFlowDetail(context.TODO(), flowCreate, nil)
+		assert.NoError(t, err)
+	})
+	t.Run("stale owner", func(t *testing.T) {
+		stale := *flowCreate
+		stale.FencingToken = 3
+		stale.Status = "Finished"
+		err := rw.UpdateWorkFlowDetail(context.TODO(), &stale, nil)
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_WORKFLOW_OWNERSHIP_LOST, err.(errors.EMError).GetCode())
+
+		flowQuery, err := rw.GetWorkFlow(context.TODO(), flowCreate.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), flowQuery.FencingToken)
+		assert.Equal(t, "Processing", flowQuery.Status)
+	})
+}
+
+func TestFlowReadWrite_ResumeWorkFlow(t *testing.T) {
+	flowCreate, err := rw.CreateWorkFlow(context.TODO(), &WorkFlow{
+		Entity: common.Entity{
+			TenantId: "tenantId",
+			Status:   constants.WorkFlowStatusError,
+		},
+		Name:  "flowName",
+		BizID: "clusterId",
+	})
+	assert.NoError(t, err)
+
+	t.Run("normal", func(t *testing.T) {
+		err := rw.ResumeWorkFlow(context.TODO(), flowCreate.ID, &WorkFlowNode{
+			Entity:   common.Entity{TenantId: "tenantId", Status: constants.WorkFlowStatusInitializing},
+			Name:     "nodeName",
+			ParentID: flowCreate.ID,
+			Resumed:  true,
+		})
+		assert.NoError(t, err)
+
+		flowQuery, nodes, err := rw.QueryDetailWorkFlow(context.TODO(), flowCreate.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, constants.WorkFlowStatusProcessing, flowQuery.Status)
+		assert.Len(t, nodes, 1)
+		assert.True(t, nodes[0].Resumed)
+	})
+	t.Run("not failed", func(t *testing.T) {
+		err := rw.ResumeWorkFlow(context.TODO(), flowCreate.ID, &WorkFlowNode{
+			Entity:   common.Entity{TenantId: "tenantId", Status: constants.WorkFlowStatusInitializing},
+			Name:     "nodeName",
+			ParentID: flowCreate.ID,
+			Resumed:  true,
+		})
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_WORKFLOW_RESUME_FAILED, err.(errors.EMError).GetCode())
+
+		_, nodes, err := rw.QueryDetailWorkFlow(context.TODO(), flowCreate.ID)
+		assert.NoError(t, err)
+		assert.Len(t, nodes, 1)
+	})
+}
@@ -28,10 +28,13 @@ type WorkFlowNode struct {
 	BizID       string `gorm:"default:null;<-:create"`
 	ParentID    string `gorm:"default:null;index;comment:'ID of the workflow parent node'"`
 	Name        string `gorm:"default:null;comment:'name of the workflow node'"`
+	Branch      string `gorm:"default:null;comment:'name of the parallel branch which the node belongs to'"`
 	OperationID string `gorm:"default:null;comment:'ID of the operation'"`
 	ReturnType  string `gorm:"default:null"`
 	Parameters  string `gorm:"default:null"`
 	Result      string `gorm:"default:null"`
+	Attempts    int    `gorm:"default:0;comment:'execution times of the workflow node'"`
+	Resumed     bool   `gorm:"default:false;comment:'whether the node is resumed from a failed workflow'"`
 	StartTime   time.Time
 	EndTime     time.Time
 }
@@ -0,0 +1,196 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package alertmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/library/framework"
+	util "github.com/pingcap/tiunimanager/util/http"
+)
+
+const (
+	AlertsApiUrl   = "/api/v2/alerts"
+	SilencesApiUrl = "/api/v2/silences"
+	SilenceApiUrl  = "/api/v2/silence"
+	ReloadApiUrl   = "/-/reload"
+)
+
+type Matcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+}
+
+type AlertStatus struct {
+	State       string   `json:"state"`
+	SilencedBy  []string `json:"silencedBy"`
+	InhibitedBy []string `json:"inhibitedBy"`
+}
+
+type Alert struct {
+	Fingerprint string            `json:"fingerprint"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+	Status      AlertStatus       `json:"status"`
+}
+
+type Silence struct {
+	Matchers  []Matcher `json:"matchers"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+	CreatedBy string    `json:"createdBy"`
+	Comment   string    `json:"comment"`
+}
+
+type createSilenceResp struct {
+	SilenceID string `json:"silenceID"`
+}
+
+var AlertManagerService AlertService
+
+// AlertService wrapper of alertmanager api v2
+type AlertService interface {
+	// QueryAlerts
+	// @Description: query alerts which are active, silenced or inhibited
+	// @Parameter ctx
+	// @Parameter address ip:port of alertmanager
+	// @return []Alert
+	// @return error
+	QueryAlerts(ctx context.Context, address string) ([]Alert, error)
+	// CreateSilence
+	// @Description: create a silence
+	// @Parameter ctx
+	// @Parameter address
+	// @Parameter silence
+	// @return silenceID
+	// @return err
+	CreateSilence(ctx context.Context, address string, silence Silence) (silenceID string, err error)
+	// DeleteSilence
+	// @Description: expire a silence
+	// @Parameter ctx
+	// @Parameter address
+	// @Parameter silenceID
+	// @return error
+	DeleteSilence(ctx context.Context, address string, silenceID string) error
+	// Reload
+	// @Description: reload config file of alertmanager
+	// @Parameter ctx
+	// @Parameter address
+	// @return error
+	Reload(ctx context.Context, address string) error
+}
+
+type AlertServiceImpl struct{}
+
+func init() {
+	AlertManagerService = new(AlertServiceImpl)
+}
+
+func (service *AlertServiceImpl) QueryAlerts(ctx context.Context, address string) ([]Alert, error) {
+	url := fmt.Sprintf("http://%s%s", address, AlertsApiUrl)
+	httpResp, err := util.Get(url, map[string]string{}, map[string]string{})
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query alerts from %s failed, %s", address, err.Error())
+		return nil, errors.WrapError(errors.TIUNIMANAGER_ALERT_MANAGER_UNAVAILABLE, "query alerts failed", err)
+	}
+
+	respBody, err := readResponse(httpResp)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query alerts from %s failed, %s", address, err.Error())
+		return nil, err
+	}
+	alerts := make([]Alert, 0)
+	if err = json.Unmarshal(respBody, &alerts); err != nil {
+		return nil, errors.WrapError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, "unmarshal alerts failed", err)
+	}
+	return alerts, nil
+}
+
+func (service *AlertServiceImpl) CreateSilence(ctx context.Context, address string, silence Silence) (silenceID string, err error) {
+	url := fmt.Sprintf("http://%s%s", address, SilencesApiUrl)
+
+	bytes, err := json.Marshal(&silence)
+	if err != nil {
+		err = errors.WrapError(errors.TIUNIMANAGER_MARSHAL_ERROR, "", err)
+		return
+	}
+	data := make(map[string]interface{})
+	if err = json.Unmarshal(bytes, &data); err != nil {
+		err = errors.WrapError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, "", err)
+		return
+	}
+
+	framework.LogWithContext(ctx).Infof("create silence, url = %s, data = %s", url, data)
+	httpResp, err := util.PostJSON(url, data, map[string]string{})
+	if err != nil {
+		err = errors.WrapError(errors.TIUNIMANAGER_ALERT_MANAGER_UNAVAILABLE, "create silence failed", err)
+		return
+	}
+
+	respBody, err := readResponse(httpResp)
+	if err != nil {
+		return
+	}
+	resp := &createSilenceResp{}
+	if err = json.Unmarshal(respBody, resp); err != nil {
+		err = errors.WrapError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, "unmarshal silence failed", err)
+		return
+	}
+	return resp.SilenceID, nil
+}
+
+func (service *AlertServiceImpl) DeleteSilence(ctx context.Context, address string, silenceID string) error {
+	url := fmt.Sprintf("http://%s%s/%s", address, SilenceApiUrl, silenceID)
+	framework.LogWithContext(ctx).Infof("delete silence, url = %s", url)
+	httpResp, err := util.Delete(url)
+	if err != nil {
+		return errors.WrapError(errors.TIUNIMANAGER_ALERT_MANAGER_UNAVAILABLE, "delete silence failed", err)
+	}
+	_, err = readResponse(httpResp)
+	return err
+}
+
+func (service *AlertServiceImpl) Reload(ctx context.Context, address string) error {
+	url := fmt.Sprintf("http://%s%s", address, ReloadApiUrl)
+	framework.LogWithContext(ctx).Infof("reload alertmanager, url = %s", url)
+	httpResp, err := util.PostJSON(url, map[string]interface{}{}, map[string]string{})
+	if err != nil {
+		return errors.WrapError(errors.TIUNIMANAGER_ALERT_MANAGER_UNAVAILABLE, "reload alertmanager failed", err)
+	}
+	_, err = readResponse(httpResp)
+	return err
+}
+
+func readResponse(httpResp *http.Response) ([]byte, error) {
+	defer httpResp.Body.Close()
+	respBody, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, errors.WrapError(errors.TIUNIMANAGER_ALERT_MANAGER_UNAVAILABLE, "read response failed", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, errors.NewErrorf(errors.TIUNIMANAGER_ALERT_MANAGER_UNAVAILABLE, "alertmanager response status %d, %s", httpResp.StatusCode, string(respBody))
+	}
+	return respBody, nil
+}
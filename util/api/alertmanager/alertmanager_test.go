@@ -0,0 +1,100 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package alertmanager
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlertServiceImpl_QueryAlerts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, AlertsApiUrl, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[{"fingerprint":"f1","labels":{"alertname":"TiDB_server_is_down","level":"emergency"},"annotations":{"summary":"TiDB server is down"},"status":{"state":"active"}}]`))
+	}))
+	defer server.Close()
+
+	alerts, err := AlertManagerService.QueryAlerts(context.TODO(), strings.TrimPrefix(server.URL, "http://"))
+	assert.NoError(t, err)
+	assert.Len(t, alerts, 1)
+	assert.Equal(t, "f1", alerts[0].Fingerprint)
+	assert.Equal(t, "TiDB_server_is_down", alerts[0].Labels["alertname"])
+	assert.Equal(t, "active", alerts[0].Status.State)
+
+	t.Run("error status", func(t *testing.T) {
+		failed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer failed.Close()
+		_, err := AlertManagerService.QueryAlerts(context.TODO(), strings.TrimPrefix(failed.URL, "http://"))
+		assert.Error(t, err)
+	})
+	t.Run("unreachable", func(t *testing.T) {
+		_, err := AlertManagerService.QueryAlerts(context.TODO(), "127.0.0.1:1")
+		assert.Error(t, err)
+	})
+}
+
+func TestAlertServiceImpl_Silence(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			assert.Equal(t, SilencesApiUrl, r.URL.Path)
+			body, _ := ioutil.ReadAll(r.Body)
+			silence := Silence{}
+			assert.NoError(t, json.Unmarshal(body, &silence))
+			assert.Equal(t, "alertname", silence.Matchers[0].Name)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"silenceID":"s1"}`))
+		} else if r.Method == "DELETE" {
+			assert.Equal(t, SilenceApiUrl+"/s1", r.URL.Path)
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+	address := strings.TrimPrefix(server.URL, "http://")
+
+	id, err := AlertManagerService.CreateSilence(context.TODO(), address, Silence{
+		Matchers: []Matcher{{Name: "alertname", Value: "TiDB_server_is_down"}},
+		StartsAt: time.Now(),
+		EndsAt:   time.Now().Add(time.Hour),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "s1", id)
+
+	assert.NoError(t, AlertManagerService.DeleteSilence(context.TODO(), address, "s1"))
+	assert.Error(t, AlertManagerService.DeleteSilence(context.TODO(), "127.0.0.1:1", "s1"))
+}
+
+func TestAlertServiceImpl_Reload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, ReloadApiUrl, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	assert.NoError(t, AlertManagerService.Reload(context.TODO(), strings.TrimPrefix(server.URL, "http://")))
+	assert.Error(t, AlertManagerService.Reload(context.TODO(), "127.0.0.1:1"))
+}
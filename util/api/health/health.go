@@ -0,0 +1,186 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/library/framework"
+)
+
+const (
+	PDHealthApiUrl      = "/pd/api/v1/health"
+	PDStoresApiUrl      = "/pd/api/v1/stores"
+	PDRegionCheckApiUrl = "/pd/api/v1/regions/check"
+	TiDBStatusApiUrl    = "/status"
+)
+
+// states of regions which are not fully replicated
+const (
+	RegionStateMissPeer    = "miss-peer"
+	RegionStatePendingPeer = "pending-peer"
+)
+
+// states of stores reported by pd
+const (
+	StoreStateUp           = "Up"
+	StoreStateOffline      = "Offline"
+	StoreStateDisconnected = "Disconnected"
+	StoreStateDown         = "Down"
+	StoreStateTombstone    = "Tombstone"
+)
+
+// probeTimeout a dead host should not block the probe of other clusters
+const probeTimeout = 5 * time.Second
+
+type PDMemberHealth struct {
+	Name       string   `json:"name"`
+	MemberID   uint64   `json:"member_id"`
+	ClientUrls []string `json:"client_urls"`
+	Health     bool     `json:"health"`
+}
+
+type Store struct {
+	ID            uint64 `json:"id"`
+	Address       string `json:"address"`
+	StatusAddress string `json:"status_address"`
+	StateName     string `json:"state_name"`
+}
+
+type storesResp struct {
+	Count  int `json:"count"`
+	Stores []struct {
+		Store Store `json:"store"`
+	} `json:"stores"`
+}
+
+type regionsResp struct {
+	Count int `json:"count"`
+}
+
+type TiDBStatus struct {
+	Connections int    `json:"connections"`
+	Version     string `json:"version"`
+	GitHash     string `json:"git_hash"`
+}
+
+var ProbeService HealthService
+
+// HealthService probe health of cluster components by their http api
+type HealthService interface {
+	// QueryPDHealth
+	// @Description: query health of all pd members
+	// @Parameter ctx
+	// @Parameter address ip:port of pd
+	// @return []PDMemberHealth
+	// @return error
+	QueryPDHealth(ctx context.Context, address string) ([]PDMemberHealth, error)
+	// QueryStores
+	// @Description: query tikv and tiflash stores registered in pd
+	// @Parameter ctx
+	// @Parameter address ip:port of pd
+	// @return []Store
+	// @return error
+	QueryStores(ctx context.Context, address string) ([]Store, error)
+	// QueryRegionCount
+	// @Description: query count of regions in the state, such as miss-peer or pending-peer
+	// @Parameter ctx
+	// @Parameter address ip:port of pd
+	// @Parameter state
+	// @return int
+	// @return error
+	QueryRegionCount(ctx context.Context, address string, state string) (int, error)
+	// QueryTiDBStatus
+	// @Description: query status of tidb server
+	// @Parameter ctx
+	// @Parameter address ip:status_port of tidb
+	// @return *TiDBStatus
+	// @return error
+	QueryTiDBStatus(ctx context.Context, address string) (*TiDBStatus, error)
+}
+
+type HealthServiceImpl struct {
+	client *http.Client
+}
+
+func init() {
+	ProbeService = &HealthServiceImpl{
+		client: &http.Client{Timeout: probeTimeout},
+	}
+}
+
+func (service *HealthServiceImpl) QueryPDHealth(ctx context.Context, address string) ([]PDMemberHealth, error) {
+	members := make([]PDMemberHealth, 0)
+	if err := service.get(ctx, fmt.Sprintf("http://%s%s", address, PDHealthApiUrl), &members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (service *HealthServiceImpl) QueryStores(ctx context.Context, address string) ([]Store, error) {
+	resp := &storesResp{}
+	if err := service.get(ctx, fmt.Sprintf("http://%s%s", address, PDStoresApiUrl), resp); err != nil {
+		return nil, err
+	}
+	stores := make([]Store, 0, len(resp.Stores))
+	for _, item := range resp.Stores {
+		stores = append(stores, item.Store)
+	}
+	return stores, nil
+}
+
+func (service *HealthServiceImpl) QueryRegionCount(ctx context.Context, address string, state string) (int, error) {
+	resp := &regionsResp{}
+	if err := service.get(ctx, fmt.Sprintf("http://%s%s/%s", address, PDRegionCheckApiUrl, state), resp); err != nil {
+		return 0, err
+	}
+	return resp.Count, nil
+}
+
+func (service *HealthServiceImpl) QueryTiDBStatus(ctx context.Context, address string) (*TiDBStatus, error) {
+	status := &TiDBStatus{}
+	if err := service.get(ctx, fmt.Sprintf("http://%s%s", address, TiDBStatusApiUrl), status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func (service *HealthServiceImpl) get(ctx context.Context, url string, result interface{}) error {
+	httpResp, err := service.client.Get(url)
+	if err != nil {
+		framework.LogWithContext(ctx).Warnf("probe %s failed, %s", url, err.Error())
+		return errors.WrapError(errors.TIUNIMANAGER_HEALTH_PROBE_FAILED, fmt.Sprintf("probe %s failed", url), err)
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return errors.WrapError(errors.TIUNIMANAGER_HEALTH_PROBE_FAILED, "read response failed", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return errors.NewErrorf(errors.TIUNIMANAGER_HEALTH_PROBE_FAILED, "probe %s response status %d, %s", url, httpResp.StatusCode, string(respBody))
+	}
+	if err = json.Unmarshal(respBody, result); err != nil {
+		return errors.WrapError(errors.TIUNIMANAGER_UNMARSHAL_ERROR, fmt.Sprintf("unmarshal response of %s failed", url), err)
+	}
+	return nil
+}
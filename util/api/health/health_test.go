@@ -0,0 +1,93 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package health
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthServiceImpl_PD(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case PDHealthApiUrl:
+			w.Write([]byte(`[{"name":"pd-1","member_id":1,"client_urls":["http://127.0.0.1:2379"],"health":true},{"name":"pd-2","member_id":2,"client_urls":["http://127.0.0.2:2379"],"health":false}]`))
+		case PDStoresApiUrl:
+			w.Write([]byte(`{"count":2,"stores":[{"store":{"id":1,"address":"127.0.0.1:20160","status_address":"127.0.0.1:20180","state_name":"Up"}},{"store":{"id":2,"address":"127.0.0.2:20160","state_name":"Down"}}]}`))
+		case PDRegionCheckApiUrl + "/" + RegionStateMissPeer:
+			w.Write([]byte(`{"count":3,"regions":[]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	address := strings.TrimPrefix(server.URL, "http://")
+
+	t.Run("health", func(t *testing.T) {
+		members, err := ProbeService.QueryPDHealth(context.TODO(), address)
+		assert.NoError(t, err)
+		assert.Len(t, members, 2)
+		assert.Equal(t, "http://127.0.0.1:2379", members[0].ClientUrls[0])
+		assert.True(t, members[0].Health)
+		assert.False(t, members[1].Health)
+	})
+	t.Run("stores", func(t *testing.T) {
+		stores, err := ProbeService.QueryStores(context.TODO(), address)
+		assert.NoError(t, err)
+		assert.Len(t, stores, 2)
+		assert.Equal(t, "127.0.0.1:20160", stores[0].Address)
+		assert.Equal(t, StoreStateDown, stores[1].StateName)
+	})
+	t.Run("regions", func(t *testing.T) {
+		count, err := ProbeService.QueryRegionCount(context.TODO(), address, RegionStateMissPeer)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, count)
+
+		_, err = ProbeService.QueryRegionCount(context.TODO(), address, RegionStatePendingPeer)
+		assert.Error(t, err)
+	})
+	t.Run("unreachable", func(t *testing.T) {
+		_, err := ProbeService.QueryPDHealth(context.TODO(), "127.0.0.1:1")
+		assert.Error(t, err)
+	})
+}
+
+func TestHealthServiceImpl_QueryTiDBStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, TiDBStatusApiUrl, r.URL.Path)
+		w.Write([]byte(`{"connections":2,"version":"5.7.25-TiDB-v5.2.2","git_hash":"da1c21fd45a4ea5900ac16d2f4a248143f378d18"}`))
+	}))
+	defer server.Close()
+
+	status, err := ProbeService.QueryTiDBStatus(context.TODO(), strings.TrimPrefix(server.URL, "http://"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, status.Connections)
+	assert.Equal(t, "5.7.25-TiDB-v5.2.2", status.Version)
+
+	t.Run("invalid response", func(t *testing.T) {
+		invalid := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`not json`))
+		}))
+		defer invalid.Close()
+		_, err := ProbeService.QueryTiDBStatus(context.TODO(), strings.TrimPrefix(invalid.URL, "http://"))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,181 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/library/framework"
+	util "github.com/pingcap/tiunimanager/util/http"
+	"github.com/prometheus/client_golang/api"
+	client "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+const ReloadApiUrl = "/-/reload"
+
+// Sample value of a series at a timestamp in seconds
+type Sample struct {
+	Timestamp int64   `json:"timestamp"`
+	Value     float64 `json:"value"`
+}
+
+// Series labels and samples of a series in result of range query
+type Series struct {
+	Labels  map[string]string `json:"labels"`
+	Samples []Sample          `json:"samples"`
+}
+
+// RuleGroup rule group loaded by prometheus, only names of alerting rules are kept
+type RuleGroup struct {
+	Name   string   `json:"name"`
+	File   string   `json:"file"`
+	Alerts []string `json:"alerts"`
+}
+
+var PrometheusService QueryService
+
+// QueryService wrapper of prometheus api
+type QueryService interface {
+	// QueryRange
+	// @Description: evaluate an expression query over a range of time, samples of NaN or Inf are dropped
+	// @Parameter ctx
+	// @Parameter address ip:port of prometheus
+	// @Parameter query PromQL
+	// @Parameter start
+	// @Parameter end
+	// @Parameter step
+	// @return []Series
+	// @return error
+	QueryRange(ctx context.Context, address string, query string, start, end time.Time, step time.Duration) ([]Series, error)
+
+	// QueryRuleGroups
+	// @Description: query rule groups which are loaded by prometheus
+	// @Parameter ctx
+	// @Parameter address ip:port of prometheus
+	// @return []RuleGroup
+	// @return error
+	QueryRuleGroups(ctx context.Context, address string) ([]RuleGroup, error)
+
+	// Reload
+	// @Description: reload config and rule files of prometheus, it requires prometheus to be started with --web.enable-lifecycle
+	// @Parameter ctx
+	// @Parameter address ip:port of prometheus
+	// @return error
+	Reload(ctx context.Context, address string) error
+}
+
+type QueryServiceImpl struct{}
+
+func init() {
+	PrometheusService = new(QueryServiceImpl)
+}
+
+func (service *QueryServiceImpl) QueryRange(ctx context.Context, address string, query string, start, end time.Time, step time.Duration) ([]Series, error) {
+	promClient, err := api.NewClient(api.Config{Address: fmt.Sprintf("http://%s", address)})
+	if err != nil {
+		return nil, errors.WrapError(errors.TIUNIMANAGER_PROMETHEUS_UNAVAILABLE, "create prometheus client failed", err)
+	}
+
+	framework.LogWithContext(ctx).Infof("query range, address = %s, query = %s, start = %s, end = %s, step = %s", address, query, start, end, step)
+	value, warnings, err := client.NewAPI(promClient).QueryRange(ctx, query, client.Range{
+		Start: start,
+		End:   end,
+		Step:  step,
+	})
+	if err != nil {
+		if apiErr, ok := err.(*client.Error); ok && apiErr.Type == client.ErrBadData {
+			return nil, errors.WrapError(errors.TIUNIMANAGER_METRICS_QUERY_INVALID, fmt.Sprintf("query %s is invalid", query), err)
+		}
+		return nil, errors.WrapError(errors.TIUNIMANAGER_METRICS_QUERY_FAILED, fmt.Sprintf("query %s failed", query), err)
+	}
+	if len(warnings) > 0 {
+		framework.LogWithContext(ctx).Warnf("query %s has warnings: %v", query, warnings)
+	}
+
+	matrix, ok := value.(model.Matrix)
+	if !ok {
+		return nil, errors.NewErrorf(errors.TIUNIMANAGER_METRICS_QUERY_FAILED, "unexpected result type %s of range query", value.Type())
+	}
+	result := make([]Series, 0, len(matrix))
+	for _, stream := range matrix {
+		series := Series{
+			Labels:  make(map[string]string),
+			Samples: make([]Sample, 0, len(stream.Values)),
+		}
+		for name, v := range stream.Metric {
+			series.Labels[string(name)] = string(v)
+		}
+		for _, pair := range stream.Values {
+			v := float64(pair.Value)
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				continue
+			}
+			series.Samples = append(series.Samples, Sample{Timestamp: pair.Timestamp.Unix(), Value: v})
+		}
+		result = append(result, series)
+	}
+	return result, nil
+}
+
+func (service *QueryServiceImpl) QueryRuleGroups(ctx context.Context, address string) ([]RuleGroup, error) {
+	promClient, err := api.NewClient(api.Config{Address: fmt.Sprintf("http://%s", address)})
+	if err != nil {
+		return nil, errors.WrapError(errors.TIUNIMANAGER_PROMETHEUS_UNAVAILABLE, "create prometheus client failed", err)
+	}
+
+	rules, err := client.NewAPI(promClient).Rules(ctx)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query rules from %s failed, %s", address, err.Error())
+		return nil, errors.WrapError(errors.TIUNIMANAGER_PROMETHEUS_UNAVAILABLE, "query rules failed", err)
+	}
+	result := make([]RuleGroup, 0, len(rules.Groups))
+	for _, group := range rules.Groups {
+		ruleGroup := RuleGroup{
+			Name:   group.Name,
+			File:   group.File,
+			Alerts: make([]string, 0, len(group.Rules)),
+		}
+		for _, rule := range group.Rules {
+			if alertingRule, ok := rule.(client.AlertingRule); ok {
+				ruleGroup.Alerts = append(ruleGroup.Alerts, alertingRule.Name)
+			}
+		}
+		result = append(result, ruleGroup)
+	}
+	return result, nil
+}
+
+func (service *QueryServiceImpl) Reload(ctx context.Context, address string) error {
+	url := fmt.Sprintf("http://%s%s", address, ReloadApiUrl)
+	framework.LogWithContext(ctx).Infof("reload prometheus, url = %s", url)
+	httpResp, err := util.PostJSON(url, map[string]interface{}{}, map[string]string{})
+	if err != nil {
+		return errors.WrapError(errors.TIUNIMANAGER_PROMETHEUS_UNAVAILABLE, "reload prometheus failed", err)
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK {
+		respBody, _ := ioutil.ReadAll(httpResp.Body)
+		return errors.NewErrorf(errors.TIUNIMANAGER_PROMETHEUS_UNAVAILABLE, "prometheus response status %d, %s", httpResp.StatusCode, string(respBody))
+	}
+	return nil
+}
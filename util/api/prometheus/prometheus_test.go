@@ -0,0 +1,117 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryServiceImpl_QueryRange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/query_range", r.URL.Path)
+		assert.NoError(t, r.ParseForm())
+		w.Header().Set("Content-Type", "application/json")
+		if r.Form.Get("query") == "invalid(" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"parse error"}`))
+			return
+		}
+		assert.Equal(t, "15", r.Form.Get("step"))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[` +
+			`{"metric":{"job":"tidb"},"values":[[1638331200,"1.5"],[1638331215,"NaN"],[1638331230,"2"]]},` +
+			`{"metric":{"job":"tikv"},"values":[[1638331200,"3"]]}]}}`))
+	}))
+	defer server.Close()
+	address := strings.TrimPrefix(server.URL, "http://")
+	start := time.Unix(1638331200, 0)
+	end := time.Unix(1638331230, 0)
+
+	series, err := PrometheusService.QueryRange(context.TODO(), address, "sum(rate(process_cpu_seconds_total[1m])) by (job)", start, end, 15*time.Second)
+	assert.NoError(t, err)
+	assert.Len(t, series, 2)
+	assert.Equal(t, "tidb", series[0].Labels["job"])
+	assert.Equal(t, []Sample{{Timestamp: 1638331200, Value: 1.5}, {Timestamp: 1638331230, Value: 2}}, series[0].Samples)
+
+	t.Run("invalid query", func(t *testing.T) {
+		_, err := PrometheusService.QueryRange(context.TODO(), address, "invalid(", start, end, 15*time.Second)
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_METRICS_QUERY_INVALID, err.(errors.EMError).GetCode())
+	})
+	t.Run("unreachable", func(t *testing.T) {
+		_, err := PrometheusService.QueryRange(context.TODO(), "127.0.0.1:1", "up", start, end, 15*time.Second)
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_METRICS_QUERY_FAILED, err.(errors.EMError).GetCode())
+	})
+}
+
+func TestQueryServiceImpl_QueryRuleGroups(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/rules", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status":"success","data":{"groups":[` +
+			`{"name":"tiunimanager-alert-rules","file":"/deploy/conf/tiunimanager.rules.yml","interval":15,"rules":[` +
+			`{"name":"TiDB_server_is_down","query":"probe_success == 0","duration":60,"labels":{},"annotations":{},"alerts":[],"health":"ok","type":"alerting"},` +
+			`{"name":"tidb:qps","query":"sum(rate(tidb_server_query_total[1m]))","labels":{},"health":"ok","type":"recording"}]}]}}`))
+	}))
+	defer server.Close()
+
+	groups, err := PrometheusService.QueryRuleGroups(context.TODO(), strings.TrimPrefix(server.URL, "http://"))
+	assert.NoError(t, err)
+	assert.Equal(t, []RuleGroup{
+		{Name: "tiunimanager-alert-rules", File: "/deploy/conf/tiunimanager.rules.yml", Alerts: []string{"TiDB_server_is_down"}},
+	}, groups)
+
+	t.Run("unreachable", func(t *testing.T) {
+		_, err := PrometheusService.QueryRuleGroups(context.TODO(), "127.0.0.1:1")
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_PROMETHEUS_UNAVAILABLE, err.(errors.EMError).GetCode())
+	})
+}
+
+func TestQueryServiceImpl_Reload(t *testing.T) {
+	lifecycle := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, ReloadApiUrl, r.URL.Path)
+		assert.Equal(t, http.MethodPost, r.Method)
+		if !lifecycle {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("Lifecycle API is not enabled."))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	address := strings.TrimPrefix(server.URL, "http://")
+
+	assert.NoError(t, PrometheusService.Reload(context.TODO(), address))
+
+	t.Run("lifecycle disabled", func(t *testing.T) {
+		lifecycle = false
+		err := PrometheusService.Reload(context.TODO(), address)
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_PROMETHEUS_UNAVAILABLE, err.(errors.EMError).GetCode())
+	})
+}
@@ -0,0 +1,281 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/library/framework"
+)
+
+// TopStatementsReq query statements of cluster from statements summary history, whose summary windows overlap the time range
+type TopStatementsReq struct {
+	DbConnParameter DbConnParam
+	StartTime       time.Time
+	EndTime         time.Time
+	OrderBy         constants.TopSQLOrderBy
+	Limit           int
+}
+
+// StatementStatistics statistics of a statement digest in the time range, latency is in nanoseconds
+type StatementStatistics struct {
+	Digest           string
+	DigestText       string
+	SchemaName       string
+	ExecCount        int64
+	SumLatency       int64
+	MaxLatency       int64
+	SumProcessedKeys int64
+	SumTotalKeys     int64
+	FirstSeen        time.Time
+	LastSeen         time.Time
+}
+
+// SlowQueriesReq query slow queries of cluster from slow query log, empty digest means all
+type SlowQueriesReq struct {
+	DbConnParameter DbConnParam
+	StartTime       time.Time
+	EndTime         time.Time
+	Digest          string
+	OrderBy         constants.TopSQLOrderBy
+	Limit           int
+}
+
+// SlowQuery an entry of slow query log, query time is in seconds
+type SlowQuery struct {
+	Time        time.Time
+	Instance    string
+	Digest      string
+	Query       string
+	DB          string
+	User        string
+	QueryTime   float64
+	ProcessKeys int64
+	TotalKeys   int64
+	Success     bool
+}
+
+// StatementPlansReq query execution plans of a statement digest from statements summary history
+type StatementPlansReq struct {
+	DbConnParameter DbConnParam
+	StartTime       time.Time
+	EndTime         time.Time
+	Digest          string
+}
+
+// StatementPlan statistics of a execution plan of statement digest, latency is in nanoseconds
+type StatementPlan struct {
+	PlanDigest   string
+	Plan         string
+	SampleText   string
+	ExecCount    int64
+	SumLatency   int64
+	MaxLatency   int64
+	SumTotalKeys int64
+	FirstSeen    time.Time
+	LastSeen     time.Time
+}
+
+var PerformanceService ClusterPerformanceService
+
+type ClusterPerformanceService interface {
+	// QueryTopStatements
+	// @Description: query statements in order of latency, executions or scanned keys desc
+	QueryTopStatements(ctx context.Context, req TopStatementsReq) ([]StatementStatistics, error)
+
+	// QuerySlowQueries
+	// @Description: query slow queries in order of query time or scanned keys desc
+	QuerySlowQueries(ctx context.Context, req SlowQueriesReq) ([]SlowQuery, error)
+
+	// QueryStatementPlans
+	// @Description: query execution plans of statement digest in order of latency desc
+	QueryStatementPlans(ctx context.Context, req StatementPlansReq) ([]StatementPlan, error)
+}
+
+type ClusterPerformanceServiceImpl struct{}
+
+func init() {
+	PerformanceService = new(ClusterPerformanceServiceImpl)
+}
+
+var statementOrderColumns = map[constants.TopSQLOrderBy]string{
+	constants.TopSQLOrderByLatency:     "sum_latency",
+	constants.TopSQLOrderByExecutions:  "exec_count",
+	constants.TopSQLOrderByScannedKeys: "sum_total_keys",
+}
+
+var slowQueryOrderColumns = map[constants.TopSQLOrderBy]string{
+	constants.TopSQLOrderByLatency:     "Query_time",
+	constants.TopSQLOrderByScannedKeys: "Total_keys",
+}
+
+const topStatementsSQL = "SELECT DIGEST, ANY_VALUE(DIGEST_TEXT), IFNULL(SCHEMA_NAME, ''), " +
+	"SUM(EXEC_COUNT) AS exec_count, SUM(SUM_LATENCY) AS sum_latency, MAX(MAX_LATENCY), " +
+	"CAST(SUM(AVG_PROCESSED_KEYS * EXEC_COUNT) AS SIGNED), CAST(SUM(AVG_TOTAL_KEYS * EXEC_COUNT) AS SIGNED) AS sum_total_keys, " +
+	"UNIX_TIMESTAMP(MIN(FIRST_SEEN)), UNIX_TIMESTAMP(MAX(LAST_SEEN)) " +
+	"FROM INFORMATION_SCHEMA.CLUSTER_STATEMENTS_SUMMARY_HISTORY " +
+	"WHERE SUMMARY_END_TIME > FROM_UNIXTIME(?) AND SUMMARY_BEGIN_TIME < FROM_UNIXTIME(?) AND DIGEST != '' " +
+	"GROUP BY SCHEMA_NAME, DIGEST ORDER BY %s DESC LIMIT ?"
+
+const slowQueriesSQL = "SELECT UNIX_TIMESTAMP(Time), INSTANCE, IFNULL(Digest, ''), Query, IFNULL(DB, ''), IFNULL(User, ''), " +
+	"Query_time, Process_keys, Total_keys, Succ " +
+	"FROM INFORMATION_SCHEMA.CLUSTER_SLOW_QUERY " +
+	"WHERE Time BETWEEN FROM_UNIXTIME(?) AND FROM_UNIXTIME(?)%s ORDER BY %s DESC LIMIT ?"
+
+const statementPlansSQL = "SELECT IFNULL(PLAN_DIGEST, ''), ANY_VALUE(PLAN), ANY_VALUE(QUERY_SAMPLE_TEXT), " +
+	"SUM(EXEC_COUNT), SUM(SUM_LATENCY) AS sum_latency, MAX(MAX_LATENCY), CAST(SUM(AVG_TOTAL_KEYS * EXEC_COUNT) AS SIGNED), " +
+	"UNIX_TIMESTAMP(MIN(FIRST_SEEN)), UNIX_TIMESTAMP(MAX(LAST_SEEN)) " +
+	"FROM INFORMATION_SCHEMA.CLUSTER_STATEMENTS_SUMMARY_HISTORY " +
+	"WHERE DIGEST = ? AND SUMMARY_END_TIME > FROM_UNIXTIME(?) AND SUMMARY_BEGIN_TIME < FROM_UNIXTIME(?) " +
+	"GROUP BY PLAN_DIGEST ORDER BY sum_latency DESC"
+
+func openTiDB(ctx context.Context, connec DbConnParam) (*sql.DB, error) {
+	db, err := sql.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s:%s)/mysql", connec.Username, connec.Password, connec.IP, connec.Port))
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("open tidb connection failed %s", err.Error())
+		return nil, errors.WrapError(errors.TIUNIMANAGER_CONNECT_TIDB_ERROR, "open tidb connection failed", err)
+	}
+	return db, nil
+}
+
+func (service *ClusterPerformanceServiceImpl) QueryTopStatements(ctx context.Context, req TopStatementsReq) ([]StatementStatistics, error) {
+	db, err := openTiDB(ctx, req.DbConnParameter)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return queryTopStatements(ctx, db, req)
+}
+
+func (service *ClusterPerformanceServiceImpl) QuerySlowQueries(ctx context.Context, req SlowQueriesReq) ([]SlowQuery, error) {
+	db, err := openTiDB(ctx, req.DbConnParameter)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return querySlowQueries(ctx, db, req)
+}
+
+func (service *ClusterPerformanceServiceImpl) QueryStatementPlans(ctx context.Context, req StatementPlansReq) ([]StatementPlan, error) {
+	db, err := openTiDB(ctx, req.DbConnParameter)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return queryStatementPlans(ctx, db, req)
+}
+
+func queryTopStatements(ctx context.Context, db *sql.DB, req TopStatementsReq) ([]StatementStatistics, error) {
+	column, ok := statementOrderColumns[req.OrderBy]
+	if !ok {
+		return nil, errors.NewErrorf(errors.TIUNIMANAGER_PERFORMANCE_QUERY_INVALID, "statements can not be ordered by %s", req.OrderBy)
+	}
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(topStatementsSQL, column), req.StartTime.Unix(), req.EndTime.Unix(), req.Limit)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query statements summary history failed %s", err.Error())
+		return nil, errors.WrapError(errors.TIUNIMANAGER_PERFORMANCE_QUERY_FAILED, "query statements summary history failed", err)
+	}
+	defer rows.Close()
+
+	statements := make([]StatementStatistics, 0)
+	for rows.Next() {
+		var statement StatementStatistics
+		var firstSeen, lastSeen int64
+		err = rows.Scan(&statement.Digest, &statement.DigestText, &statement.SchemaName,
+			&statement.ExecCount, &statement.SumLatency, &statement.MaxLatency,
+			&statement.SumProcessedKeys, &statement.SumTotalKeys, &firstSeen, &lastSeen)
+		if err != nil {
+			return nil, errors.WrapError(errors.TIUNIMANAGER_PERFORMANCE_QUERY_FAILED, "scan statements summary history failed", err)
+		}
+		statement.FirstSeen = time.Unix(firstSeen, 0)
+		statement.LastSeen = time.Unix(lastSeen, 0)
+		statements = append(statements, statement)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(errors.TIUNIMANAGER_PERFORMANCE_QUERY_FAILED, "query statements summary history failed", err)
+	}
+	return statements, nil
+}
+
+func querySlowQueries(ctx context.Context, db *sql.DB, req SlowQueriesReq) ([]SlowQuery, error) {
+	column, ok := slowQueryOrderColumns[req.OrderBy]
+	if !ok {
+		return nil, errors.NewErrorf(errors.TIUNIMANAGER_PERFORMANCE_QUERY_INVALID, "slow queries can not be ordered by %s", req.OrderBy)
+	}
+	args := []interface{}{req.StartTime.Unix(), req.EndTime.Unix()}
+	digestCondition := ""
+	if len(req.Digest) > 0 {
+		digestCondition = " AND Digest = ?"
+		args = append(args, req.Digest)
+	}
+	args = append(args, req.Limit)
+
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(slowQueriesSQL, digestCondition, column), args...)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query slow queries failed %s", err.Error())
+		return nil, errors.WrapError(errors.TIUNIMANAGER_PERFORMANCE_QUERY_FAILED, "query slow queries failed", err)
+	}
+	defer rows.Close()
+
+	queries := make([]SlowQuery, 0)
+	for rows.Next() {
+		var query SlowQuery
+		var timestamp float64
+		err = rows.Scan(&timestamp, &query.Instance, &query.Digest, &query.Query, &query.DB, &query.User,
+			&query.QueryTime, &query.ProcessKeys, &query.TotalKeys, &query.Success)
+		if err != nil {
+			return nil, errors.WrapError(errors.TIUNIMANAGER_PERFORMANCE_QUERY_FAILED, "scan slow queries failed", err)
+		}
+		query.Time = time.Unix(0, int64(timestamp*float64(time.Second)))
+		queries = append(queries, query)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(errors.TIUNIMANAGER_PERFORMANCE_QUERY_FAILED, "query slow queries failed", err)
+	}
+	return queries, nil
+}
+
+func queryStatementPlans(ctx context.Context, db *sql.DB, req StatementPlansReq) ([]StatementPlan, error) {
+	rows, err := db.QueryContext(ctx, statementPlansSQL, req.Digest, req.StartTime.Unix(), req.EndTime.Unix())
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("query plans of statement %s failed %s", req.Digest, err.Error())
+		return nil, errors.WrapError(errors.TIUNIMANAGER_PERFORMANCE_QUERY_FAILED, "query plans of statement failed", err)
+	}
+	defer rows.Close()
+
+	plans := make([]StatementPlan, 0)
+	for rows.Next() {
+		var plan StatementPlan
+		var firstSeen, lastSeen int64
+		err = rows.Scan(&plan.PlanDigest, &plan.Plan, &plan.SampleText,
+			&plan.ExecCount, &plan.SumLatency, &plan.MaxLatency, &plan.SumTotalKeys, &firstSeen, &lastSeen)
+		if err != nil {
+			return nil, errors.WrapError(errors.TIUNIMANAGER_PERFORMANCE_QUERY_FAILED, "scan plans of statement failed", err)
+		}
+		plan.FirstSeen = time.Unix(firstSeen, 0)
+		plan.LastSeen = time.Unix(lastSeen, 0)
+		plans = append(plans, plan)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapError(errors.TIUNIMANAGER_PERFORMANCE_QUERY_FAILED, "query plans of statement failed", err)
+	}
+	return plans, nil
+}
@@ -0,0 +1,142 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ * Unless required by applicable law or agreed to in writing, software        *
+ * distributed under the License is distributed on an "AS IS" BASIS,          *
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.   *
+ * See the License for the specific language governing permissions and        *
+ * limitations under the License.                                             *
+ ******************************************************************************/
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pingcap/tiunimanager/common/constants"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryTopStatements(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	defer db.Close()
+
+	start := time.Unix(1638331200, 0)
+	end := time.Unix(1638334800, 0)
+	t.Run("normal", func(t *testing.T) {
+		mock.ExpectQuery(fmt.Sprintf(topStatementsSQL, "exec_count")).
+			WithArgs(start.Unix(), end.Unix(), 10).
+			WillReturnRows(sqlmock.NewRows([]string{"DIGEST", "DIGEST_TEXT", "SCHEMA_NAME", "exec_count", "sum_latency", "max_latency",
+				"sum_processed_keys", "sum_total_keys", "first_seen", "last_seen"}).
+				AddRow("digest1", "select * from t where id = ?", "test", 100, 5000000000, 200000000, 1000, 2000, 1638331260, 1638334740))
+
+		statements, err := queryTopStatements(context.TODO(), db, TopStatementsReq{
+			StartTime: start,
+			EndTime:   end,
+			OrderBy:   constants.TopSQLOrderByExecutions,
+			Limit:     10,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(statements))
+		assert.Equal(t, "digest1", statements[0].Digest)
+		assert.Equal(t, int64(100), statements[0].ExecCount)
+		assert.Equal(t, int64(5000000000), statements[0].SumLatency)
+		assert.Equal(t, int64(2000), statements[0].SumTotalKeys)
+		assert.Equal(t, int64(1638334740), statements[0].LastSeen.Unix())
+	})
+	t.Run("invalid order", func(t *testing.T) {
+		_, err := queryTopStatements(context.TODO(), db, TopStatementsReq{OrderBy: "unknown"})
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_PERFORMANCE_QUERY_INVALID, err.(errors.EMError).GetCode())
+	})
+	t.Run("failed", func(t *testing.T) {
+		mock.ExpectQuery(fmt.Sprintf(topStatementsSQL, "sum_latency")).
+			WillReturnError(fmt.Errorf("some error"))
+		_, err := queryTopStatements(context.TODO(), db, TopStatementsReq{OrderBy: constants.TopSQLOrderByLatency})
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_PERFORMANCE_QUERY_FAILED, err.(errors.EMError).GetCode())
+	})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQuerySlowQueries(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	defer db.Close()
+
+	start := time.Unix(1638331200, 0)
+	end := time.Unix(1638334800, 0)
+	t.Run("normal", func(t *testing.T) {
+		mock.ExpectQuery(fmt.Sprintf(slowQueriesSQL, " AND Digest = ?", "Total_keys")).
+			WithArgs(start.Unix(), end.Unix(), "digest1", 5).
+			WillReturnRows(sqlmock.NewRows([]string{"Time", "INSTANCE", "Digest", "Query", "DB", "User",
+				"Query_time", "Process_keys", "Total_keys", "Succ"}).
+				AddRow(1638331260.5, "127.0.0.1:10080", "digest1", "select * from t where id = 1", "test", "root", 1.5, 1000, 2000, 1))
+
+		queries, err := querySlowQueries(context.TODO(), db, SlowQueriesReq{
+			StartTime: start,
+			EndTime:   end,
+			Digest:    "digest1",
+			OrderBy:   constants.TopSQLOrderByScannedKeys,
+			Limit:     5,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(queries))
+		assert.Equal(t, int64(1638331260500), queries[0].Time.UnixNano()/int64(time.Millisecond))
+		assert.Equal(t, 1.5, queries[0].QueryTime)
+		assert.Equal(t, int64(2000), queries[0].TotalKeys)
+		assert.True(t, queries[0].Success)
+	})
+	t.Run("invalid order", func(t *testing.T) {
+		_, err := querySlowQueries(context.TODO(), db, SlowQueriesReq{OrderBy: constants.TopSQLOrderByExecutions})
+		assert.Error(t, err)
+		assert.Equal(t, errors.TIUNIMANAGER_PERFORMANCE_QUERY_INVALID, err.(errors.EMError).GetCode())
+	})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQueryStatementPlans(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	defer db.Close()
+
+	start := time.Unix(1638331200, 0)
+	end := time.Unix(1638334800, 0)
+	mock.ExpectQuery(statementPlansSQL).
+		WithArgs("digest1", start.Unix(), end.Unix()).
+		WillReturnRows(sqlmock.NewRows([]string{"PLAN_DIGEST", "PLAN", "QUERY_SAMPLE_TEXT", "exec_count", "sum_latency", "max_latency",
+			"sum_total_keys", "first_seen", "last_seen"}).
+			AddRow("plan1", "Point_Get_1", "select * from t where id = 1", 90, 900000000, 20000000, 90, 1638331260, 1638334740).
+			AddRow("plan2", "TableReader_5", "select * from t where id = 2", 10, 4100000000, 500000000, 1910, 1638332000, 1638333000))
+
+	plans, err := queryStatementPlans(context.TODO(), db, StatementPlansReq{
+		StartTime: start,
+		EndTime:   end,
+		Digest:    "digest1",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(plans))
+	assert.Equal(t, "Point_Get_1", plans[0].Plan)
+	assert.Equal(t, int64(1910), plans[1].SumTotalKeys)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClusterPerformanceServiceImpl_QueryTopStatements(t *testing.T) {
+	_, err := PerformanceService.QueryTopStatements(context.TODO(), TopStatementsReq{
+		DbConnParameter: DbConnParam{Username: "root", IP: "127.0.0.1", Port: "4000"},
+		OrderBy:         constants.TopSQLOrderByLatency,
+		Limit:           10,
+	})
+	assert.Error(t, err)
+}
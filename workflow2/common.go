@@ -20,6 +20,7 @@ type NodeReturnType string
 const (
 	SyncFuncNode NodeReturnType = "SyncFuncNode"
 	PollingNode  NodeReturnType = "PollingNode"
+	ParallelNode NodeReturnType = "ParallelNode"
 )
 
 const (
@@ -28,6 +29,9 @@ const (
 	BizTypePlatform string = "platform"
 )
 
+// ContextDeferFailure key of flow context data, true if failure handling of the workflow is deferred, see WorkFlowDefine.DeferFailure
+const ContextDeferFailure = "DeferFailure"
+
 const (
 	maxPollingSequence int32 = 10 * 24 * 3600
 	defaultPageSize    int   = 10
@@ -17,13 +17,28 @@
 package workflow2
 
 import (
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/common/structs"
 	"github.com/pingcap/tiunimanager/models/workflow"
+	"time"
 )
 
 type WorkFlowDefine struct {
 	FlowName      string
 	TaskNodes     map[string]*NodeDefine
 	ContextParser func(string) *FlowContext
+	Branches      map[string][]*BranchDefine //key: name of ParallelNode, value: branches executed concurrently
+	// DeferFailure the workflow could defer failure handling if it is requested by ContextDeferFailure,
+	// then a failed node of the main path does not go to its FailEvent immediately,
+	// the workflow is failed and waits to be resumed from the failed node, or abandoned to execute the FailEvent
+	DeferFailure bool
+}
+
+// BranchDefine branch of a ParallelNode, task nodes of the branch are linked from "start" like WorkFlowDefine,
+// a failed node goes to its FailEvent inside the branch, and the ParallelNode fails after all branches end
+type BranchDefine struct {
+	Name      string
+	TaskNodes map[string]*NodeDefine
 }
 
 type NodeExecutor func(task *workflow.WorkFlowNode, context *FlowContext) error
@@ -34,6 +49,119 @@ type NodeDefine struct {
 	FailEvent    string
 	ReturnType   NodeReturnType
 	Executor     NodeExecutor
+	Policy       *NodePolicy //nil if the node is executed only once without deadline
+}
+
+// NodePolicy execution policy of a task node, a node without policy will be executed only once.
+// Executor of a polling node is not retried once its deployment operation is submitted, only the polling is
+type NodePolicy struct {
+	// MaxAttempts max execution times of the node, including the first execution
+	MaxAttempts int
+	// Backoff wait time before the first retry, doubled before every next retry
+	Backoff time.Duration
+	// MaxBackoff upper limit of the wait time between two retries, no limit if zero
+	MaxBackoff time.Duration
+	// Retryable decide whether the node can be retried after failing with err, all errors can be retried if nil
+	Retryable func(err error) bool
+	// Timeout deadline of the node since it starts, including all attempts, no deadline if zero.
+	// Node past its deadline is failed by watchLoop and goes to FailEvent
+	Timeout time.Duration
+}
+
+// RetryableErrorCodes
+// @Description: build a Retryable func which only retries EMError with the given codes
+// @Parameter codes
+// @return func(err error) bool
+func RetryableErrorCodes(codes ...errors.EM_ERROR_CODE) func(err error) bool {
+	return func(err error) bool {
+		emErr, ok := err.(errors.EMError)
+		if !ok {
+			return false
+		}
+		for _, code := range codes {
+			if emErr.GetCode() == code {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (policy *NodePolicy) canRetry(attempts int, err error) bool {
+	if policy == nil || attempts >= policy.MaxAttempts {
+		return false
+	}
+	return policy.Retryable == nil || policy.Retryable(err)
+}
+
+func (policy *NodePolicy) getBackoff(attempts int) time.Duration {
+	backoff := policy.Backoff
+	for i := 1; i < attempts; i++ {
+		backoff = backoff * 2
+		if policy.MaxBackoff > 0 && backoff >= policy.MaxBackoff {
+			return policy.MaxBackoff
+		}
+	}
+	if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+		return policy.MaxBackoff
+	}
+	return backoff
+}
+
+func (policy *NodePolicy) hasTimeout() bool {
+	return policy != nil && policy.Timeout > 0
+}
+
+func (define *WorkFlowDefine) getBranches(nodeName string) []*BranchDefine {
+	if define == nil || define.Branches == nil {
+		return nil
+	}
+	return define.Branches[nodeName]
+}
+
+func (branch *BranchDefine) getNodeDefineByName(nodeName string) *NodeDefine {
+	for _, value := range branch.TaskNodes {
+		if nodeName == value.Name {
+			return value
+		}
+	}
+	return nil
+}
+
+func (branch *BranchDefine) getNodeNameList() []string {
+	var nodeNames []string
+	node := branch.TaskNodes["start"]
+
+	for node != nil {
+		nodeNames = append(nodeNames, node.Name)
+		node = branch.TaskNodes[node.SuccessEvent]
+	}
+	return nodeNames
+}
+
+// getBranchInfoList
+// @Description: get branches of parallel nodes in the main path of workflow, in the order of execution
+// @Receiver define
+// @return []*structs.WorkFlowBranchInfo
+func (define *WorkFlowDefine) getBranchInfoList() []*structs.WorkFlowBranchInfo {
+	branchInfos := make([]*structs.WorkFlowBranchInfo, 0)
+	var collect func(parallelNodeNames []string)
+	collect = func(parallelNodeNames []string) {
+		for _, nodeName := range parallelNodeNames {
+			for _, branch := range define.getBranches(nodeName) {
+				nodeNames := branch.getNodeNameList()
+				branchInfos = append(branchInfos, &structs.WorkFlowBranchInfo{
+					ParallelNode: nodeName,
+					Name:         branch.Name,
+					NodeNames:    nodeNames,
+				})
+				//parallel node nested in branch
+				collect(nodeNames)
+			}
+		}
+	}
+	collect(define.getNodeNameList())
+	return branchInfos
 }
 
 func (define *WorkFlowDefine) getNodeNameList() []string {
@@ -65,6 +193,42 @@ func (define *WorkFlowDefine) isFailNode(nodeName string) bool {
 	return false
 }
 
+// getFailNodeDefine
+// @Description: get define of the node executed when the node fails
+// @Receiver define
+// @Parameter nodeName
+// @return *NodeDefine nil if the node has no FailEvent
+func (define *WorkFlowDefine) getFailNodeDefine(nodeName string) *NodeDefine {
+	nodeDefine, ok := define.TaskNodes[define.getNodeDefineKeyByName(nodeName)]
+	if !ok {
+		return nil
+	}
+	return define.TaskNodes[nodeDefine.FailEvent]
+}
+
+// hasExecutedFailNode
+// @Description: whether any fail node of the main path has been executed since the workflow was resumed last time
+// @Receiver define
+// @Parameter nodes
+// @return bool
+func (define *WorkFlowDefine) hasExecutedFailNode(nodes []*workflow.WorkFlowNode) bool {
+	var resumed *workflow.WorkFlowNode
+	for _, node := range nodes {
+		if node.Resumed && node.Branch == "" && (resumed == nil || resumed.CreatedAt.Before(node.CreatedAt)) {
+			resumed = node
+		}
+	}
+	for _, node := range nodes {
+		if node.Branch != "" || !define.isFailNode(node.Name) {
+			continue
+		}
+		if resumed == nil || resumed.CreatedAt.Before(node.CreatedAt) {
+			return true
+		}
+	}
+	return false
+}
+
 func CompositeExecutor(executors ...NodeExecutor) NodeExecutor {
 	return func(node *workflow.WorkFlowNode, context *FlowContext) error {
 		for _, executor := range executors {
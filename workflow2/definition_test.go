@@ -0,0 +1,85 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ *  Unless required by applicable law or agreed to in writing, software       *
+ *  distributed under the License is distributed on an "AS IS" BASIS,         *
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  *
+ *  See the License for the specific language governing permissions and       *
+ *  limitations under the License.                                            *
+ ******************************************************************************/
+
+package workflow2
+
+import (
+	"fmt"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNodePolicy_canRetry(t *testing.T) {
+	var nilPolicy *NodePolicy
+	assert.False(t, nilPolicy.canRetry(1, fmt.Errorf("error")))
+
+	policy := &NodePolicy{MaxAttempts: 3}
+	assert.True(t, policy.canRetry(1, fmt.Errorf("error")))
+	assert.True(t, policy.canRetry(2, fmt.Errorf("error")))
+	assert.False(t, policy.canRetry(3, fmt.Errorf("error")))
+
+	policy.Retryable = RetryableErrorCodes(errors.TIUNIMANAGER_TASK_FAILED)
+	assert.True(t, policy.canRetry(1, errors.Error(errors.TIUNIMANAGER_TASK_FAILED)))
+	assert.False(t, policy.canRetry(1, errors.Error(errors.TIUNIMANAGER_PARAMETER_INVALID)))
+	assert.False(t, policy.canRetry(1, fmt.Errorf("error")))
+}
+
+func TestNodePolicy_getBackoff(t *testing.T) {
+	policy := &NodePolicy{Backoff: time.Second}
+	assert.Equal(t, time.Second, policy.getBackoff(1))
+	assert.Equal(t, 2*time.Second, policy.getBackoff(2))
+	assert.Equal(t, 4*time.Second, policy.getBackoff(3))
+
+	policy.MaxBackoff = 3 * time.Second
+	assert.Equal(t, 2*time.Second, policy.getBackoff(2))
+	assert.Equal(t, 3*time.Second, policy.getBackoff(3))
+	assert.Equal(t, 3*time.Second, policy.getBackoff(10))
+}
+
+func TestWorkFlowDefine_getBranchInfoList(t *testing.T) {
+	define := &WorkFlowDefine{
+		FlowName: "flowName",
+		TaskNodes: map[string]*NodeDefine{
+			"start":        {"prepare", "prepareDone", "fail", SyncFuncNode, doNode, nil},
+			"prepareDone":  {"initAccounts", "accountsDone", "fail", ParallelNode, nil, nil},
+			"accountsDone": {"end", "", "", SyncFuncNode, doNode, nil},
+			"fail":         {"fail", "", "", SyncFuncNode, doNode, nil},
+		},
+		Branches: map[string][]*BranchDefine{
+			"initAccounts": {
+				{Name: "database", TaskNodes: map[string]*NodeDefine{
+					"start": {"initDatabaseAccount", "", "fail", SyncFuncNode, doNode, nil},
+					"fail":  {"revertDatabaseAccount", "", "", SyncFuncNode, doNode, nil},
+				}},
+				{Name: "grafana", TaskNodes: map[string]*NodeDefine{
+					"start": {"initGrafanaAccount", "", "", SyncFuncNode, doNode, nil},
+				}},
+			},
+		},
+	}
+	assert.Equal(t, []string{"prepare", "initAccounts", "end"}, define.getNodeNameList())
+
+	branches := define.getBranchInfoList()
+	assert.Equal(t, 2, len(branches))
+	assert.Equal(t, "initAccounts", branches[0].ParallelNode)
+	assert.Equal(t, "database", branches[0].Name)
+	assert.Equal(t, []string{"initDatabaseAccount"}, branches[0].NodeNames)
+	assert.Equal(t, "grafana", branches[1].Name)
+	assert.Equal(t, []string{"initGrafanaAccount"}, branches[1].NodeNames)
+
+	assert.Empty(t, (&WorkFlowDefine{TaskNodes: define.TaskNodes}).getBranchInfoList())
+}
@@ -28,6 +28,7 @@ import (
 	dbModel "github.com/pingcap/tiunimanager/models/common"
 	"github.com/pingcap/tiunimanager/models/workflow"
 	"runtime/debug"
+	"strings"
 	"sync"
 	"time"
 )
@@ -41,26 +42,59 @@ type WorkFlowMeta struct {
 	Nodes             []*workflow.WorkFlowNode
 	Context           *FlowContext
 	IsFailNode        bool
+	Ownership         FlowOwnership //nil if the workflow is not claimed, such as in test
+	OwnerToken        int64
+	branchNodes       []*workflow.WorkFlowNode                 //nodes of parallel branches, each of them is modified by its own branch only
+	deadlines         map[*workflow.WorkFlowNode]*nodeDeadline //executing nodes with timeout, checked by watchLoop
+	mutex             sync.Mutex                               //protect Nodes, branchNodes, deadlines and Flow when branches of parallel node are executing
+}
+
+// nodeDeadline deadline of an executing node, the node is canceled when it is expired
+type nodeDeadline struct {
+	deadline time.Time
+	cancel   context.CancelFunc
+	expired  chan struct{}
 }
 
 type FlowContext struct {
 	context.Context
 	FlowData map[string]string
 	mutex    sync.Mutex
+	parent   *FlowContext //flow data is shared with parent, nil if it is the context of the workflow
 }
 
 func NewFlowContext(ctx context.Context, data map[string]string) *FlowContext {
 	flowCtx := &FlowContext{
-		ctx,
-		data,
-		sync.Mutex{},
+		Context:  ctx,
+		FlowData: data,
 	}
 	return flowCtx.InitFlowContext()
 }
 
+// withCancel
+// @Description: derive a cancelable context for a node execution, flow data is shared with c
+// @Receiver c
+// @return *FlowContext
+// @return context.CancelFunc
+func (c *FlowContext) withCancel() (*FlowContext, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(c.Context)
+	return &FlowContext{
+		Context:  ctx,
+		FlowData: c.FlowData,
+		parent:   c,
+	}, cancel
+}
+
+func (c *FlowContext) getMutex() *sync.Mutex {
+	if c.parent != nil {
+		return c.parent.getMutex()
+	}
+	return &c.mutex
+}
+
 func (c *FlowContext) GetData(key string, data interface{}) error {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	c.getMutex().Lock()
+	defer c.getMutex().Unlock()
 	if _, ok := c.FlowData[key]; !ok {
 		return nil
 	} else {
@@ -73,8 +107,8 @@ func (c *FlowContext) SetData(key string, value interface{}) error {
 	if err != nil {
 		return err
 	}
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	c.getMutex().Lock()
+	defer c.getMutex().Unlock()
 	c.FlowData[key] = string(data)
 	return nil
 }
@@ -140,7 +174,15 @@ func NewWorkFlowMeta(ctx context.Context, flowId string) (*WorkFlowMeta, error)
 
 	var latest *workflow.WorkFlowNode
 	var latestNodeDefineKey string
+	mainNodes := make([]*workflow.WorkFlowNode, 0)
+	branchNodes := make([]*workflow.WorkFlowNode, 0)
 	for _, node := range nodes {
+		if node.Branch != "" {
+			//nodes of parallel branches are handled by the parallel node
+			branchNodes = append(branchNodes, node)
+			continue
+		}
+		mainNodes = append(mainNodes, node)
 		if latest == nil || latest.CreatedAt.Before(node.CreatedAt) {
 			latest = node
 		}
@@ -169,7 +211,11 @@ func NewWorkFlowMeta(ctx context.Context, flowId string) (*WorkFlowMeta, error)
 	}
 
 	var current *workflow.WorkFlowNode
-	if currentNodeDefine != nil {
+	if currentNodeDefine != nil && latest != nil && latest.Status == constants.WorkFlowStatusInitializing {
+		//node is persisted but not executed, such as node resumed from a failed workflow
+		isNodeFail = define.isFailNode(currentNodeDefine.Name)
+		current = latest
+	} else if currentNodeDefine != nil {
 		isNodeFail = define.isFailNode(currentNodeDefine.Name)
 		current = &workflow.WorkFlowNode{
 			Entity: dbModel.Entity{
@@ -188,7 +234,8 @@ func NewWorkFlowMeta(ctx context.Context, flowId string) (*WorkFlowMeta, error)
 
 	meta := &WorkFlowMeta{
 		Flow:              flow,
-		Nodes:             nodes,
+		Nodes:             mainNodes,
+		branchNodes:       branchNodes,
 		Define:            define,
 		CurrentNode:       current,
 		CurrentNodeDefine: currentNodeDefine,
@@ -199,17 +246,49 @@ func NewWorkFlowMeta(ctx context.Context, flowId string) (*WorkFlowMeta, error)
 	return meta, nil
 }
 
-func (flow *WorkFlowMeta) Restore() {
+func (flow *WorkFlowMeta) Restore() error {
+	flow.mutex.Lock()
+	defer flow.mutex.Unlock()
+	return flow.restore(flow.Nodes)
+}
+
+// persistNode
+// @Description: persist the workflow with the executing node, node of a branch is persisted without other nodes,
+// which may be modified by other branches concurrently
+// @Receiver flow
+// @Parameter node
+// @return error
+func (flow *WorkFlowMeta) persistNode(node *workflow.WorkFlowNode) error {
+	if node.Branch == "" {
+		return flow.Restore()
+	}
+	flow.mutex.Lock()
+	defer flow.mutex.Unlock()
+	return flow.restore([]*workflow.WorkFlowNode{node})
+}
+
+// restore
+// @Description: persist the workflow and the given nodes, flow.mutex must be held.
+// The update is fenced by the owner token, it is rejected if the workflow has been taken over by another replica
+// @Receiver flow
+// @Parameter nodes
+// @return error TIUNIMANAGER_WORKFLOW_OWNERSHIP_LOST if the workflow is owned by another replica
+func (flow *WorkFlowMeta) restore(nodes []*workflow.WorkFlowNode) error {
+	flow.Context.mutex.Lock()
 	data, err := json.Marshal(flow.Context.FlowData)
+	flow.Context.mutex.Unlock()
 	if err != nil {
 		framework.LogWithContext(flow.Context).Warnf("json marshal flow context data failed %s", err.Error())
-		return
+		return err
 	}
 	flow.Flow.Context = string(data)
+	if flow.Ownership != nil {
+		flow.Flow.FencingToken = flow.OwnerToken
+	}
 	current, err := models.GetWorkFlowReaderWriter().GetWorkFlow(flow.Context, flow.Flow.ID)
 	if err != nil {
 		framework.LogWithContext(flow.Context).Warnf("get workflow by id %s failed %s", flow.Flow.ID, err.Error())
-		return
+		return err
 	}
 	if (constants.WorkFlowStatusStopped == current.Status ||
 		constants.WorkFlowStatusCanceling == current.Status ||
@@ -218,11 +297,12 @@ func (flow *WorkFlowMeta) Restore() {
 		flow.Flow.Status = current.Status
 		handleWorkFlowMetrics(flow.Flow)
 	}
-	err = models.GetWorkFlowReaderWriter().UpdateWorkFlowDetail(flow.Context, flow.Flow, flow.Nodes)
+	err = models.GetWorkFlowReaderWriter().UpdateWorkFlowDetail(flow.Context, flow.Flow, nodes)
 	if err != nil {
 		framework.LogWithContext(flow.Context).Warnf("update workflow detail %+v failed %s", flow, err.Error())
 	}
 	//framework.LogWithContext(flow.Context).Infof("restore workflow %+v success", flow.Flow)
+	return err
 }
 
 func (flow *WorkFlowMeta) CheckNeedPause() {
@@ -233,6 +313,33 @@ func (flow *WorkFlowMeta) CheckNeedPause() {
 	}
 }
 
+// CheckNeedDeferFailure
+// @Description: the workflow deferring failure is failed after a node of the main path fails, without executing the FailEvent.
+// It could be resumed from the failed node, or abandoned to execute the FailEvent.
+// Failure is deferred only if it is requested when the workflow is created, and node past its deadline always goes to FailEvent
+// @Receiver flow
+// @Parameter err error of the failed node
+func (flow *WorkFlowMeta) CheckNeedDeferFailure(err error) {
+	if flow.Define == nil || !flow.Define.DeferFailure || flow.IsFailNode || isNodeTimeout(err) {
+		return
+	}
+	if flow.Flow.Status != constants.WorkFlowStatusProcessing || flow.Define.TaskNodes[flow.CurrentNodeDefine.FailEvent] == nil {
+		return
+	}
+	deferFailure := false
+	if dataErr := flow.Context.GetData(ContextDeferFailure, &deferFailure); dataErr != nil || !deferFailure {
+		return
+	}
+	framework.LogWithContext(flow.Context).Infof("workflow %s of bizId %s defers handling failure of node %s", flow.Flow.ID, flow.Flow.BizID, flow.CurrentNode.Name)
+	flow.Flow.Status = constants.WorkFlowStatusError
+	handleWorkFlowMetrics(flow.Flow)
+}
+
+func isNodeTimeout(err error) bool {
+	emErr, ok := err.(errors.EMError)
+	return ok && emErr.GetCode() == errors.TIUNIMANAGER_WORKFLOW_NODE_TIMEOUT
+}
+
 func (flow *WorkFlowMeta) Execute() {
 	defer func() {
 		if r := recover(); r != nil {
@@ -269,80 +376,428 @@ func (flow *WorkFlowMeta) Execute() {
 	nodeDefine := flow.CurrentNodeDefine
 
 	handleWorkFlowNodeMetrics(flow, node)
-	_, err := models.GetWorkFlowReaderWriter().CreateWorkFlowNode(flow.Context, node)
-	if err != nil {
-		framework.LogWithContext(flow.Context).Warnf("create workflow node, node %s failed %s", node.Name, err.Error())
-		return
+	if !flow.hasNode(node) {
+		_, err := models.GetWorkFlowReaderWriter().CreateWorkFlowNode(flow.Context, node)
+		if err != nil {
+			framework.LogWithContext(flow.Context).Warnf("create workflow node, node %s failed %s", node.Name, err.Error())
+			return
+		}
+		flow.appendNode(node)
 	}
-
-	flow.Nodes = append(flow.Nodes, node)
 	node.Processing()
 	handleWorkFlowNodeMetrics(flow, node)
-	flow.Restore()
+	if err := flow.Restore(); isOwnershipLost(err) {
+		//workflow has been taken over by another replica, do not execute the node twice
+		return
+	}
 
-	err = nodeDefine.Executor(node, flow.Context)
+	err := flow.runNode(flow.Context, node, nodeDefine)
 	if err != nil {
 		framework.LogWithContext(flow.Context).Infof("workflow %s of bizId %s do node %s failed, %s", flow.Flow.ID, flow.Flow.BizID, node.Name, err.Error())
 		node.Fail(err)
 		handleWorkFlowNodeMetrics(flow, node)
 		flow.CheckNeedPause()
+		flow.CheckNeedDeferFailure(err)
 		flow.Restore()
 		return
 	}
+	handleWorkFlowNodeMetrics(flow, node)
+	flow.Restore()
+}
+
+func (flow *WorkFlowMeta) hasNode(node *workflow.WorkFlowNode) bool {
+	flow.mutex.Lock()
+	defer flow.mutex.Unlock()
+	for _, n := range flow.Nodes {
+		if n == node {
+			return true
+		}
+	}
+	return false
+}
+
+func (flow *WorkFlowMeta) appendNode(node *workflow.WorkFlowNode) {
+	flow.mutex.Lock()
+	defer flow.mutex.Unlock()
+	flow.Nodes = append(flow.Nodes, node)
+}
+
+func (flow *WorkFlowMeta) appendBranchNode(node *workflow.WorkFlowNode) {
+	flow.mutex.Lock()
+	defer flow.mutex.Unlock()
+	flow.branchNodes = append(flow.branchNodes, node)
+}
+
+// getBranchNodes
+// @Description: get nodes of the branch created since the parallel node started
+// @Receiver flow
+// @Parameter branchName
+// @Parameter since
+// @return []*workflow.WorkFlowNode
+func (flow *WorkFlowMeta) getBranchNodes(branchName string, since time.Time) []*workflow.WorkFlowNode {
+	flow.mutex.Lock()
+	defer flow.mutex.Unlock()
+	nodes := make([]*workflow.WorkFlowNode, 0)
+	for _, node := range flow.branchNodes {
+		if node.Branch == branchName && !node.CreatedAt.Before(since) {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+func (flow *WorkFlowMeta) getFlowStatus() string {
+	flow.mutex.Lock()
+	defer flow.mutex.Unlock()
+	return flow.Flow.Status
+}
+
+// watchDeadline
+// @Description: register the executing node with its deadline, it will be canceled by watchLoop when it is expired
+// @Receiver flow
+// @Parameter node
+// @Parameter deadline
+// @Parameter cancel
+// @return channel closed when the node is expired
+func (flow *WorkFlowMeta) watchDeadline(node *workflow.WorkFlowNode, deadline time.Time, cancel context.CancelFunc) chan struct{} {
+	flow.mutex.Lock()
+	defer flow.mutex.Unlock()
+	if flow.deadlines == nil {
+		flow.deadlines = make(map[*workflow.WorkFlowNode]*nodeDeadline)
+	}
+	expired := make(chan struct{})
+	flow.deadlines[node] = &nodeDeadline{
+		deadline: deadline,
+		cancel:   cancel,
+		expired:  expired,
+	}
+	return expired
+}
+
+func (flow *WorkFlowMeta) unwatchDeadline(node *workflow.WorkFlowNode) {
+	flow.mutex.Lock()
+	defer flow.mutex.Unlock()
+	delete(flow.deadlines, node)
+}
+
+// ExpireNodes
+// @Description: cancel executing nodes past their deadlines, including nodes of parallel branches,
+// each of them is failed and goes to its FailEvent after its executor returns
+// @Receiver flow
+// @Parameter now
+// @return names of nodes expired this time
+func (flow *WorkFlowMeta) ExpireNodes(now time.Time) []string {
+	flow.mutex.Lock()
+	defer flow.mutex.Unlock()
+	expiredNodes := make([]string, 0)
+	for node, d := range flow.deadlines {
+		if !now.After(d.deadline) || isClosed(d.expired) {
+			continue
+		}
+		close(d.expired)
+		d.cancel()
+		expiredNodes = append(expiredNodes, node.Name)
+	}
+	return expiredNodes
+}
+
+func isClosed(ch chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// runNode
+// @Description: execute the node, retry it according to the node policy if failed.
+// Node with timeout is executed with a cancelable context, which is canceled by watchLoop after the deadline,
+// the deployment operation of a polling node is killed when it is canceled
+// @Receiver flow
+// @Parameter ctx
+// @Parameter node
+// @Parameter nodeDefine
+// @return error
+func (flow *WorkFlowMeta) runNode(ctx *FlowContext, node *workflow.WorkFlowNode, nodeDefine *NodeDefine) error {
+	policy := nodeDefine.Policy
+	if !policy.hasTimeout() {
+		return flow.runNodeAttempts(ctx, node, nodeDefine)
+	}
+
+	nodeCtx, cancel := ctx.withCancel()
+	defer cancel()
+	expired := flow.watchDeadline(node, node.StartTime.Add(policy.Timeout), cancel)
+	defer flow.unwatchDeadline(node)
+
+	//executor has returned and the operation of polling node is killed here, so FailEvent will not run concurrently with them
+	err := flow.runNodeAttempts(nodeCtx, node, nodeDefine)
+	if isClosed(expired) {
+		framework.LogWithContext(flow.Context).Errorf("workflow %s of bizId %s do node %s past its deadline", flow.Flow.ID, flow.Flow.BizID, node.Name)
+		return errors.NewErrorf(errors.TIUNIMANAGER_WORKFLOW_NODE_TIMEOUT, "node %s is not finished before deadline", node.Name)
+	}
+	return err
+}
+
+// runNodeAttempts
+// @Description: execute the node until it succeeds or no more attempt is allowed by the node policy.
+// Once the deployment operation of a polling node is submitted, retries only poll it again,
+// an operation is never submitted twice and a failed operation is not retried
+// @Receiver flow
+// @Parameter ctx
+// @Parameter node
+// @Parameter nodeDefine
+// @return error
+func (flow *WorkFlowMeta) runNodeAttempts(ctx *FlowContext, node *workflow.WorkFlowNode, nodeDefine *NodeDefine) error {
+	policy := nodeDefine.Policy
+	for attempts := 1; ; attempts++ {
+		node.Attempts = attempts
+		var operationFailed bool
+		var err error
+		if attempts > 1 && nodeDefine.ReturnType == PollingNode && node.OperationID != "" {
+			//operation has been submitted by the executor, wait for it again instead of submitting another one
+			operationFailed, err = flow.pollOperation(ctx, node)
+		} else {
+			operationFailed, err = flow.executeNode(ctx, node, nodeDefine)
+		}
+		if err == nil || ctx.Err() != nil || operationFailed || !policy.canRetry(attempts, err) {
+			return err
+		}
+		backoff := policy.getBackoff(attempts)
+		framework.LogWithContext(flow.Context).Warnf("workflow %s of bizId %s do node %s failed in attempt %d, retry after %s, %s", flow.Flow.ID, flow.Flow.BizID, node.Name, attempts, backoff, err.Error())
+		node.Record(fmt.Sprintf("attempt %d failed, retry after %s: %s", attempts, backoff, err.Error()))
+		if persistErr := flow.persistNode(node); isOwnershipLost(persistErr) {
+			return persistErr
+		}
+		if flow.getFlowStatus() != constants.WorkFlowStatusProcessing {
+			//workflow is stopped or canceled by api, give up retrying
+			return err
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// executeNode
+// @Description: call executor of the node once, and wait for the deployment operation if it is a polling node
+// @Receiver flow
+// @Parameter ctx
+// @Parameter node
+// @Parameter nodeDefine
+// @return operationFailed true if the deployment operation of the polling node is finished with error
+// @return error
+func (flow *WorkFlowMeta) executeNode(ctx *FlowContext, node *workflow.WorkFlowNode, nodeDefine *NodeDefine) (bool, error) {
+	if nodeDefine.Executor != nil {
+		if err := nodeDefine.Executor(node, ctx); err != nil {
+			return false, err
+		}
+	}
 
 	switch nodeDefine.ReturnType {
 	case SyncFuncNode:
 		node.Success()
-		handleWorkFlowNodeMetrics(flow, node)
-		flow.Restore()
-		return
+		return false, nil
+	case ParallelNode:
+		return false, flow.executeBranches(ctx, node)
 	case PollingNode:
 		if node.Status == constants.WorkFlowStatusFinished {
-			flow.Restore()
-			handleWorkFlowNodeMetrics(flow, node)
-			return
+			return false, nil
 		}
-		ticker := time.NewTicker(3 * time.Second)
-		sequence := int32(0)
-		for range ticker.C {
-			sequence++
-			if sequence > maxPollingSequence {
-				node.Fail(errors.Error(errors.TIUNIMANAGER_WORKFLOW_NODE_POLLING_TIME_OUT))
-				handleWorkFlowNodeMetrics(flow, node)
-				flow.CheckNeedPause()
-				flow.Restore()
-				return
-			}
-			framework.LogWithContext(flow.Context).Debugf("polling node waiting, sequence %d, nodeId %s, nodeName %s", sequence, node.ID, node.Name)
-
-			op, err := deployment.M.GetStatus(flow.Context, node.OperationID)
-			if err != nil {
-				framework.LogWithContext(flow.Context).Errorf("call deployment GetStatus %s, failed %s", node.OperationID, err.Error())
-				node.Fail(errors.NewError(errors.TIUNIMANAGER_TASK_FAILED, err.Error()))
-				handleWorkFlowNodeMetrics(flow, node)
-				flow.CheckNeedPause()
-				flow.Restore()
-				return
+		return flow.pollOperation(ctx, node)
+	}
+	return false, nil
+}
+
+// pollOperation
+// @Description: wait for the deployment operation of the polling node
+// @Receiver flow
+// @Parameter ctx
+// @Parameter node
+// @return operationFailed true if the operation is finished with error, false if its status is not got
+// @return error
+func (flow *WorkFlowMeta) pollOperation(ctx *FlowContext, node *workflow.WorkFlowNode) (bool, error) {
+	ticker := time.NewTicker(3 * time.Second)
+	defer ticker.Stop()
+	sequence := int32(0)
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			//kill the operation, otherwise it is still running when the node goes to its FailEvent
+			if err := deployment.M.Cancel(flow.Context, node.OperationID); err != nil {
+				framework.LogWithContext(flow.Context).Errorf("cancel operation %s of node %s failed, %s", node.OperationID, node.Name, err.Error())
 			}
-			if op.Status == deployment.Error {
-				framework.LogWithContext(flow.Context).Errorf("call deployment GetStatus %s, response error %s", node.OperationID, op.ErrorStr)
-				node.Fail(errors.NewError(errors.TIUNIMANAGER_TASK_FAILED, op.ErrorStr))
-				handleWorkFlowNodeMetrics(flow, node)
-				flow.CheckNeedPause()
-				flow.Restore()
-				return
+			return false, errors.NewErrorf(errors.TIUNIMANAGER_TASK_CANCELED, "polling node %s is canceled", node.Name)
+		}
+		sequence++
+		if sequence > maxPollingSequence {
+			return false, errors.Error(errors.TIUNIMANAGER_WORKFLOW_NODE_POLLING_TIME_OUT)
+		}
+		framework.LogWithContext(ctx).Debugf("polling node waiting, sequence %d, nodeId %s, nodeName %s", sequence, node.ID, node.Name)
+
+		op, err := deployment.M.GetStatus(ctx, node.OperationID)
+		if err != nil {
+			framework.LogWithContext(ctx).Errorf("call deployment GetStatus %s, failed %s", node.OperationID, err.Error())
+			return false, errors.NewError(errors.TIUNIMANAGER_TASK_FAILED, err.Error())
+		}
+		if op.Status == deployment.Error {
+			framework.LogWithContext(ctx).Errorf("call deployment GetStatus %s, response error %s", node.OperationID, op.ErrorStr)
+			return true, errors.NewError(errors.TIUNIMANAGER_TASK_FAILED, op.ErrorStr)
+		}
+		if op.Status == deployment.Finished {
+			if op.Result != "" {
+				node.Success(op.Result)
+			} else {
+				node.Success(nil)
 			}
-			if op.Status == deployment.Finished {
-				if op.Result != "" {
-					node.Success(op.Result)
-				} else {
-					node.Success(nil)
+			return false, nil
+		}
+	}
+}
+
+// executeBranches
+// @Description: execute all branches of the parallel node concurrently, and wait for all of them
+// @Receiver flow
+// @Parameter ctx
+// @Parameter node
+// @return error
+func (flow *WorkFlowMeta) executeBranches(ctx *FlowContext, node *workflow.WorkFlowNode) error {
+	branches := flow.Define.getBranches(node.Name)
+	if len(branches) == 0 {
+		return errors.NewErrorf(errors.TIUNIMANAGER_WORKFLOW_DEFINE_NOT_FOUND, "no branch defined for parallel node %s", node.Name)
+	}
+
+	branchErrors := make([]error, len(branches))
+	wg := sync.WaitGroup{}
+	for i, branch := range branches {
+		wg.Add(1)
+		go func(i int, branch *BranchDefine) {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					framework.LogWithContext(flow.Context).Errorf("recover from workflow %s, branch %s, stacktrace %s", flow.Flow.Name, branch.Name, string(debug.Stack()))
+					branchErrors[i] = errors.NewErrorf(errors.TIUNIMANAGER_PANIC, "%v", r)
 				}
-				handleWorkFlowNodeMetrics(flow, node)
-				flow.Restore()
-				return
+			}()
+			branchErrors[i] = flow.executeBranch(ctx, node, branch)
+		}(i, branch)
+	}
+	wg.Wait()
+
+	failedBranches := make([]string, 0)
+	for i, branch := range branches {
+		if branchErrors[i] != nil {
+			failedBranches = append(failedBranches, fmt.Sprintf("%s: %s", branch.Name, branchErrors[i].Error()))
+		}
+	}
+	if len(failedBranches) > 0 {
+		return errors.NewErrorf(errors.TIUNIMANAGER_TASK_FAILED, "%d of %d branches failed, %s", len(failedBranches), len(branches), strings.Join(failedBranches, "; "))
+	}
+	node.Success(fmt.Sprintf("%d branches completed", len(branches)))
+	return nil
+}
+
+// executeBranch
+// @Description: execute nodes of the branch in order, a failed branch goes to its own FailEvent node.
+// Nodes finished before the workflow is taken over by another replica are not executed again
+// @Receiver flow
+// @Parameter ctx
+// @Parameter parallelNode
+// @Parameter branch
+// @return error of the first failed node
+func (flow *WorkFlowMeta) executeBranch(ctx *FlowContext, parallelNode *workflow.WorkFlowNode, branch *BranchDefine) error {
+	nodeDefine, node, branchErr := flow.resumeBranch(parallelNode, branch)
+	for nodeDefine != nil {
+		if flow.getFlowStatus() != constants.WorkFlowStatusProcessing || ctx.Err() != nil {
+			return errors.NewErrorf(errors.TIUNIMANAGER_TASK_CANCELED, "workflow %s is interrupted", flow.Flow.ID)
+		}
+		if node == nil {
+			node = &workflow.WorkFlowNode{
+				Entity: dbModel.Entity{
+					TenantId: flow.Flow.TenantId,
+					Status:   constants.WorkFlowStatusInitializing,
+				},
+				Name:       nodeDefine.Name,
+				BizID:      flow.Flow.BizID,
+				ParentID:   flow.Flow.ID,
+				Branch:     branch.Name,
+				ReturnType: string(nodeDefine.ReturnType),
+				StartTime:  time.Now(),
+			}
+			if _, err := models.GetWorkFlowReaderWriter().CreateWorkFlowNode(flow.Context, node); err != nil {
+				framework.LogWithContext(flow.Context).Warnf("create workflow node, node %s of branch %s failed %s", node.Name, branch.Name, err.Error())
+				return err
 			}
+			flow.appendBranchNode(node)
+		}
+		node.Processing()
+		handleWorkFlowNodeMetrics(flow, node)
+		if err := flow.persistNode(node); isOwnershipLost(err) {
+			return err
 		}
 
+		err := flow.runNode(ctx, node, nodeDefine)
+		if err != nil {
+			framework.LogWithContext(flow.Context).Infof("workflow %s of bizId %s do node %s of branch %s failed, %s", flow.Flow.ID, flow.Flow.BizID, node.Name, branch.Name, err.Error())
+			node.Fail(err)
+			if branchErr != nil {
+				//failure handling of the branch failed
+				nodeDefine = nil
+			} else {
+				branchErr = err
+				nodeDefine = branch.TaskNodes[nodeDefine.FailEvent]
+			}
+		} else {
+			nodeDefine = branch.TaskNodes[nodeDefine.SuccessEvent]
+		}
+		handleWorkFlowNodeMetrics(flow, node)
+		flow.persistNode(node)
+		node = nil
+	}
+	return branchErr
+}
+
+// resumeBranch
+// @Description: find where the branch continues from, according to its nodes executed before the workflow is taken over
+// @Receiver flow
+// @Parameter parallelNode
+// @Parameter branch
+// @return nodeDefine define of the node to execute next, nil if the branch is ended
+// @return node unfinished node to execute again, nil if a new node should be created
+// @return branchErr error of the failed node of the branch
+func (flow *WorkFlowMeta) resumeBranch(parallelNode *workflow.WorkFlowNode, branch *BranchDefine) (nodeDefine *NodeDefine, node *workflow.WorkFlowNode, branchErr error) {
+	var latest, failed *workflow.WorkFlowNode
+	for _, n := range flow.getBranchNodes(branch.Name, parallelNode.CreatedAt) {
+		if latest == nil || latest.CreatedAt.Before(n.CreatedAt) {
+			latest = n
+		}
+		if n.Status == constants.WorkFlowStatusError && (failed == nil || n.CreatedAt.Before(failed.CreatedAt)) {
+			failed = n
+		}
+	}
+	if latest == nil {
+		return branch.TaskNodes["start"], nil, nil
+	}
+	if failed != nil {
+		branchErr = errors.NewErrorf(errors.TIUNIMANAGER_TASK_FAILED, "node %s failed, %s", failed.Name, failed.Result)
+	}
+	latestDefine := branch.getNodeDefineByName(latest.Name)
+	if latestDefine == nil {
+		return nil, nil, errors.NewErrorf(errors.TIUNIMANAGER_WORKFLOW_DEFINE_NOT_FOUND, "node %s is not defined in branch %s", latest.Name, branch.Name)
+	}
+	switch latest.Status {
+	case constants.WorkFlowStatusFinished:
+		return branch.TaskNodes[latestDefine.SuccessEvent], nil, branchErr
+	case constants.WorkFlowStatusError:
+		if latest == failed {
+			return branch.TaskNodes[latestDefine.FailEvent], nil, branchErr
+		}
+		//failure handling of the branch failed
+		return nil, nil, branchErr
+	default:
+		return latestDefine, latest, branchErr
 	}
 }
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"github.com/golang/mock/gomock"
 	"github.com/pingcap/tiunimanager/common/constants"
+	emerr "github.com/pingcap/tiunimanager/common/errors"
 	"github.com/pingcap/tiunimanager/deployment"
 	"github.com/pingcap/tiunimanager/library/framework"
 	"github.com/pingcap/tiunimanager/models"
@@ -28,7 +29,9 @@ import (
 	mock_deployment "github.com/pingcap/tiunimanager/test/mockdeployment"
 	"github.com/pingcap/tiunimanager/test/mockmodels/mockworkflow"
 	"github.com/stretchr/testify/assert"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 var doNode = func(node *workflow.WorkFlowNode, context *FlowContext) error {
@@ -346,3 +349,599 @@ func TestWorkFlowMeta_Execute_case6(t *testing.T) {
 	}
 	meta.Execute()
 }
+
+func TestWorkFlowMeta_Execute_retry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFlowRW := mockworkflow.NewMockReaderWriter(ctrl)
+	mockFlowRW.EXPECT().CreateWorkFlowNode(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockFlowRW.EXPECT().UpdateWorkFlowDetail(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFlowRW.EXPECT().GetWorkFlow(gomock.Any(), gomock.Any()).Return(&workflow.WorkFlow{
+		Entity: common.Entity{
+			Status:   constants.WorkFlowStatusProcessing,
+			TenantId: framework.GetTenantIDFromContext(context.TODO()),
+			ID:       "testflowId",
+		},
+	}, nil).AnyTimes()
+	models.SetWorkFlowReaderWriter(mockFlowRW)
+
+	buildMeta := func(executor NodeExecutor, policy *NodePolicy) *WorkFlowMeta {
+		return &WorkFlowMeta{
+			Flow: &workflow.WorkFlow{
+				Entity: common.Entity{
+					ID:     "test",
+					Status: constants.WorkFlowStatusProcessing,
+				},
+				Name: "test",
+			},
+			Define: &WorkFlowDefine{
+				FlowName: "test",
+			},
+			CurrentNode: &workflow.WorkFlowNode{
+				Entity: common.Entity{
+					ID:     "test",
+					Status: constants.WorkFlowStatusInitializing,
+				},
+				Name: "flaky",
+			},
+			CurrentNodeDefine: &NodeDefine{
+				Name:       "flaky",
+				Executor:   executor,
+				ReturnType: SyncFuncNode,
+				Policy:     policy,
+			},
+			Context: NewFlowContext(context.Background(), make(map[string]string)),
+		}
+	}
+
+	t.Run("succeed after retry", func(t *testing.T) {
+		times := 0
+		meta := buildMeta(func(node *workflow.WorkFlowNode, context *FlowContext) error {
+			times++
+			if times < 3 {
+				return errors.New("flaky error")
+			}
+			return nil
+		}, &NodePolicy{MaxAttempts: 3, Backoff: time.Millisecond})
+		meta.Execute()
+		assert.Equal(t, 3, times)
+		assert.Equal(t, 3, meta.CurrentNode.Attempts)
+		assert.Equal(t, constants.WorkFlowStatusFinished, meta.CurrentNode.Status)
+		assert.Contains(t, meta.CurrentNode.Result, "attempt 2 failed")
+	})
+	t.Run("attempts exhausted", func(t *testing.T) {
+		times := 0
+		meta := buildMeta(func(node *workflow.WorkFlowNode, context *FlowContext) error {
+			times++
+			return errors.New("flaky error")
+		}, &NodePolicy{MaxAttempts: 2, Backoff: time.Millisecond})
+		meta.Execute()
+		assert.Equal(t, 2, times)
+		assert.Equal(t, constants.WorkFlowStatusError, meta.CurrentNode.Status)
+	})
+	t.Run("not retryable", func(t *testing.T) {
+		times := 0
+		meta := buildMeta(func(node *workflow.WorkFlowNode, context *FlowContext) error {
+			times++
+			return errors.New("fatal error")
+		}, &NodePolicy{MaxAttempts: 3, Backoff: time.Millisecond, Retryable: func(err error) bool {
+			return false
+		}})
+		meta.Execute()
+		assert.Equal(t, 1, times)
+		assert.Equal(t, constants.WorkFlowStatusError, meta.CurrentNode.Status)
+	})
+}
+
+func TestWorkFlowMeta_Execute_retryPolling(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFlowRW := mockworkflow.NewMockReaderWriter(ctrl)
+	mockFlowRW.EXPECT().CreateWorkFlowNode(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockFlowRW.EXPECT().UpdateWorkFlowDetail(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFlowRW.EXPECT().GetWorkFlow(gomock.Any(), gomock.Any()).Return(&workflow.WorkFlow{
+		Entity: common.Entity{
+			Status:   constants.WorkFlowStatusProcessing,
+			TenantId: framework.GetTenantIDFromContext(context.TODO()),
+			ID:       "testflowId",
+		},
+	}, nil).AnyTimes()
+	models.SetWorkFlowReaderWriter(mockFlowRW)
+
+	buildMeta := func(executor NodeExecutor) *WorkFlowMeta {
+		return &WorkFlowMeta{
+			Flow: &workflow.WorkFlow{
+				Entity: common.Entity{
+					ID:     "test",
+					Status: constants.WorkFlowStatusProcessing,
+				},
+				Name: "test",
+			},
+			Define: &WorkFlowDefine{
+				FlowName: "test",
+			},
+			CurrentNode: &workflow.WorkFlowNode{
+				Entity: common.Entity{
+					ID:     "test",
+					Status: constants.WorkFlowStatusInitializing,
+				},
+				Name: "start",
+			},
+			CurrentNodeDefine: &NodeDefine{
+				Name:       "start",
+				Executor:   executor,
+				ReturnType: PollingNode,
+				Policy: &NodePolicy{MaxAttempts: 3, Backoff: time.Millisecond,
+					Retryable: RetryableErrorCodes(emerr.TIUNIMANAGER_TASK_FAILED)},
+			},
+			Context: NewFlowContext(context.Background(), make(map[string]string)),
+		}
+	}
+	submit := func(times *int) NodeExecutor {
+		return func(node *workflow.WorkFlowNode, context *FlowContext) error {
+			*times++
+			node.OperationID = "operation01"
+			return nil
+		}
+	}
+
+	t.Run("status lost", func(t *testing.T) {
+		mockTiupManager := mock_deployment.NewMockInterface(ctrl)
+		gomock.InOrder(
+			mockTiupManager.EXPECT().GetStatus(gomock.Any(), "operation01").Return(deployment.Operation{}, errors.New("status lost")).Times(1),
+			mockTiupManager.EXPECT().GetStatus(gomock.Any(), "operation01").Return(deployment.Operation{Status: deployment.Finished}, nil).Times(1),
+		)
+		deployment.M = mockTiupManager
+
+		times := 0
+		meta := buildMeta(submit(&times))
+		meta.Execute()
+		assert.Equal(t, 1, times)
+		assert.Equal(t, 2, meta.CurrentNode.Attempts)
+		assert.Equal(t, constants.WorkFlowStatusFinished, meta.CurrentNode.Status)
+	})
+	t.Run("operation failed", func(t *testing.T) {
+		mockTiupManager := mock_deployment.NewMockInterface(ctrl)
+		mockTiupManager.EXPECT().GetStatus(gomock.Any(), "operation01").Return(deployment.Operation{Status: deployment.Error, ErrorStr: "ssh timeout"}, nil).Times(1)
+		deployment.M = mockTiupManager
+
+		times := 0
+		meta := buildMeta(submit(&times))
+		meta.Execute()
+		assert.Equal(t, 1, times)
+		assert.Equal(t, 1, meta.CurrentNode.Attempts)
+		assert.Equal(t, constants.WorkFlowStatusError, meta.CurrentNode.Status)
+	})
+	t.Run("submit failed", func(t *testing.T) {
+		mockTiupManager := mock_deployment.NewMockInterface(ctrl)
+		mockTiupManager.EXPECT().GetStatus(gomock.Any(), "operation01").Return(deployment.Operation{Status: deployment.Finished}, nil).Times(1)
+		deployment.M = mockTiupManager
+
+		times := 0
+		meta := buildMeta(func(node *workflow.WorkFlowNode, context *FlowContext) error {
+			times++
+			if times == 1 {
+				return emerr.NewError(emerr.TIUNIMANAGER_TASK_FAILED, "submit failed")
+			}
+			node.OperationID = "operation01"
+			return nil
+		})
+		meta.Execute()
+		assert.Equal(t, 2, times)
+		assert.Equal(t, constants.WorkFlowStatusFinished, meta.CurrentNode.Status)
+	})
+}
+
+func TestWorkFlowMeta_Execute_deferFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFlowRW := mockworkflow.NewMockReaderWriter(ctrl)
+	mockFlowRW.EXPECT().CreateWorkFlowNode(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockFlowRW.EXPECT().UpdateWorkFlowDetail(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFlowRW.EXPECT().GetWorkFlow(gomock.Any(), gomock.Any()).Return(&workflow.WorkFlow{
+		Entity: common.Entity{
+			Status:   constants.WorkFlowStatusProcessing,
+			TenantId: framework.GetTenantIDFromContext(context.TODO()),
+			ID:       "testflowId",
+		},
+	}, nil).AnyTimes()
+	models.SetWorkFlowReaderWriter(mockFlowRW)
+
+	buildMeta := func(deferFailure bool, nodeName string, deployErr error) *WorkFlowMeta {
+		define := &WorkFlowDefine{
+			FlowName:     "test",
+			DeferFailure: true,
+			TaskNodes: map[string]*NodeDefine{
+				"start": {"deploy", "", "fail", SyncFuncNode, func(node *workflow.WorkFlowNode, context *FlowContext) error {
+					return deployErr
+				}, nil},
+				"fail": {"fail", "", "", SyncFuncNode, func(node *workflow.WorkFlowNode, context *FlowContext) error {
+					return errors.New("revert error")
+				}, nil},
+			},
+		}
+		nodeDefine := define.TaskNodes[define.getNodeDefineKeyByName(nodeName)]
+		flowContext := NewFlowContext(context.Background(), make(map[string]string))
+		flowContext.SetData(ContextDeferFailure, deferFailure)
+		return &WorkFlowMeta{
+			Flow: &workflow.WorkFlow{
+				Entity: common.Entity{
+					ID:     "test",
+					Status: constants.WorkFlowStatusProcessing,
+				},
+				Name: "test",
+			},
+			Define: define,
+			CurrentNode: &workflow.WorkFlowNode{
+				Entity: common.Entity{
+					ID:     "test",
+					Status: constants.WorkFlowStatusInitializing,
+				},
+				Name: nodeName,
+			},
+			CurrentNodeDefine: nodeDefine,
+			IsFailNode:        define.isFailNode(nodeName),
+			Context:           flowContext,
+		}
+	}
+
+	t.Run("deferred", func(t *testing.T) {
+		meta := buildMeta(true, "deploy", errors.New("deploy error"))
+		meta.Execute()
+		assert.Equal(t, constants.WorkFlowStatusError, meta.CurrentNode.Status)
+		assert.Equal(t, constants.WorkFlowStatusError, meta.Flow.Status)
+	})
+	t.Run("not deferred", func(t *testing.T) {
+		meta := buildMeta(false, "deploy", errors.New("deploy error"))
+		meta.Execute()
+		assert.Equal(t, constants.WorkFlowStatusError, meta.CurrentNode.Status)
+		assert.Equal(t, constants.WorkFlowStatusProcessing, meta.Flow.Status)
+	})
+	t.Run("timeout", func(t *testing.T) {
+		meta := buildMeta(true, "deploy", emerr.NewErrorf(emerr.TIUNIMANAGER_WORKFLOW_NODE_TIMEOUT, "node deploy is not finished before deadline"))
+		meta.Execute()
+		assert.Equal(t, constants.WorkFlowStatusError, meta.CurrentNode.Status)
+		assert.Equal(t, constants.WorkFlowStatusProcessing, meta.Flow.Status)
+	})
+	t.Run("fail node", func(t *testing.T) {
+		meta := buildMeta(true, "fail", nil)
+		meta.Execute()
+		assert.Equal(t, constants.WorkFlowStatusError, meta.CurrentNode.Status)
+		assert.Equal(t, constants.WorkFlowStatusProcessing, meta.Flow.Status)
+	})
+}
+
+func TestWorkFlowMeta_Execute_parallel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFlowRW := mockworkflow.NewMockReaderWriter(ctrl)
+	mockFlowRW.EXPECT().CreateWorkFlowNode(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockFlowRW.EXPECT().UpdateWorkFlowDetail(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFlowRW.EXPECT().GetWorkFlow(gomock.Any(), gomock.Any()).Return(&workflow.WorkFlow{
+		Entity: common.Entity{
+			Status:   constants.WorkFlowStatusProcessing,
+			TenantId: framework.GetTenantIDFromContext(context.TODO()),
+			ID:       "testflowId",
+		},
+	}, nil).AnyTimes()
+	models.SetWorkFlowReaderWriter(mockFlowRW)
+
+	buildMeta := func(branches []*BranchDefine) *WorkFlowMeta {
+		return &WorkFlowMeta{
+			Flow: &workflow.WorkFlow{
+				Entity: common.Entity{
+					ID:     "test",
+					Status: constants.WorkFlowStatusProcessing,
+				},
+				Name: "test",
+			},
+			Define: &WorkFlowDefine{
+				FlowName: "test",
+				Branches: map[string][]*BranchDefine{"parallel": branches},
+			},
+			CurrentNode: &workflow.WorkFlowNode{
+				Entity: common.Entity{
+					ID:     "test",
+					Status: constants.WorkFlowStatusInitializing,
+				},
+				Name: "parallel",
+			},
+			CurrentNodeDefine: &NodeDefine{
+				Name:       "parallel",
+				ReturnType: ParallelNode,
+			},
+			Context: NewFlowContext(context.Background(), make(map[string]string)),
+		}
+	}
+
+	t.Run("all branches succeed", func(t *testing.T) {
+		meta := buildMeta([]*BranchDefine{
+			{Name: "branch1", TaskNodes: map[string]*NodeDefine{
+				"start":     {"branch1Node1", "node1Done", "fail", SyncFuncNode, doNode, nil},
+				"node1Done": {"branch1Node2", "", "fail", SyncFuncNode, doNode, nil},
+				"fail":      {"branch1Fail", "", "", SyncFuncNode, doNode, nil},
+			}},
+			{Name: "branch2", TaskNodes: map[string]*NodeDefine{
+				"start": {"branch2Node1", "", "fail", SyncFuncNode, doNode, nil},
+				"fail":  {"branch2Fail", "", "", SyncFuncNode, doNode, nil},
+			}},
+		})
+		meta.Execute()
+		assert.Equal(t, constants.WorkFlowStatusFinished, meta.CurrentNode.Status)
+		names := make([]string, 0)
+		for _, node := range meta.branchNodes {
+			names = append(names, node.Branch+"/"+node.Name)
+		}
+		assert.ElementsMatch(t, []string{"branch1/branch1Node1", "branch1/branch1Node2", "branch2/branch2Node1"}, names)
+	})
+	t.Run("one branch fails", func(t *testing.T) {
+		meta := buildMeta([]*BranchDefine{
+			{Name: "branch1", TaskNodes: map[string]*NodeDefine{
+				"start": {"branch1Node1", "", "fail", SyncFuncNode, doNode, nil},
+				"fail":  {"branch1Fail", "", "", SyncFuncNode, doNode, nil},
+			}},
+			{Name: "branch2", TaskNodes: map[string]*NodeDefine{
+				"start": {"branch2Node1", "", "fail", SyncFuncNode, failNode, nil},
+				"fail":  {"branch2Fail", "", "", SyncFuncNode, doNode, nil},
+			}},
+		})
+		meta.Execute()
+		assert.Equal(t, constants.WorkFlowStatusError, meta.CurrentNode.Status)
+		assert.Contains(t, meta.CurrentNode.Result, "branch2")
+		statuses := make(map[string]string)
+		for _, node := range meta.branchNodes {
+			statuses[node.Name] = node.Status
+		}
+		assert.Equal(t, constants.WorkFlowStatusFinished, statuses["branch1Node1"])
+		assert.Equal(t, constants.WorkFlowStatusError, statuses["branch2Node1"])
+		assert.Equal(t, constants.WorkFlowStatusFinished, statuses["branch2Fail"])
+	})
+	t.Run("no branch", func(t *testing.T) {
+		meta := buildMeta(nil)
+		meta.Execute()
+		assert.Equal(t, constants.WorkFlowStatusError, meta.CurrentNode.Status)
+	})
+	t.Run("taken over", func(t *testing.T) {
+		executed := make(chan string, 10)
+		recordNode := func(node *workflow.WorkFlowNode, context *FlowContext) error {
+			executed <- node.Name
+			return nil
+		}
+		meta := buildMeta([]*BranchDefine{
+			{Name: "branch1", TaskNodes: map[string]*NodeDefine{
+				"start":     {"branch1Node1", "node1Done", "fail", SyncFuncNode, recordNode, nil},
+				"node1Done": {"branch1Node2", "", "fail", SyncFuncNode, recordNode, nil},
+				"fail":      {"branch1Fail", "", "", SyncFuncNode, recordNode, nil},
+			}},
+			{Name: "branch2", TaskNodes: map[string]*NodeDefine{
+				"start": {"branch2Node1", "", "fail", SyncFuncNode, recordNode, nil},
+				"fail":  {"branch2Fail", "", "", SyncFuncNode, recordNode, nil},
+			}},
+			{Name: "branch3", TaskNodes: map[string]*NodeDefine{
+				"start": {"branch3Node1", "", "", SyncFuncNode, recordNode, nil},
+			}},
+		})
+		now := time.Now()
+		meta.CurrentNode.CreatedAt = now
+		interrupted := &workflow.WorkFlowNode{Entity: common.Entity{CreatedAt: now.Add(time.Second), Status: constants.WorkFlowStatusProcessing}, Name: "branch2Node1", Branch: "branch2"}
+		meta.branchNodes = []*workflow.WorkFlowNode{
+			{Entity: common.Entity{CreatedAt: now.Add(-time.Hour), Status: constants.WorkFlowStatusError}, Name: "branch1Node1", Branch: "branch1"},
+			{Entity: common.Entity{CreatedAt: now.Add(time.Second), Status: constants.WorkFlowStatusFinished}, Name: "branch1Node1", Branch: "branch1"},
+			interrupted,
+			{Entity: common.Entity{CreatedAt: now.Add(time.Second), Status: constants.WorkFlowStatusFinished}, Name: "branch3Node1", Branch: "branch3"},
+		}
+		meta.Execute()
+		close(executed)
+		names := make([]string, 0)
+		for name := range executed {
+			names = append(names, name)
+		}
+		assert.Equal(t, constants.WorkFlowStatusFinished, meta.CurrentNode.Status)
+		assert.ElementsMatch(t, []string{"branch1Node2", "branch2Node1"}, names)
+		assert.Equal(t, constants.WorkFlowStatusFinished, interrupted.Status)
+		assert.Equal(t, 5, len(meta.branchNodes))
+	})
+}
+
+func TestWorkFlowMeta_Execute_timeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFlowRW := mockworkflow.NewMockReaderWriter(ctrl)
+	mockFlowRW.EXPECT().CreateWorkFlowNode(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockFlowRW.EXPECT().UpdateWorkFlowDetail(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockFlowRW.EXPECT().GetWorkFlow(gomock.Any(), gomock.Any()).Return(&workflow.WorkFlow{
+		Entity: common.Entity{
+			Status:   constants.WorkFlowStatusProcessing,
+			TenantId: framework.GetTenantIDFromContext(context.TODO()),
+			ID:       "testflowId",
+		},
+	}, nil).AnyTimes()
+	models.SetWorkFlowReaderWriter(mockFlowRW)
+
+	buildMeta := func(nodeDefine *NodeDefine, branches []*BranchDefine) *WorkFlowMeta {
+		return &WorkFlowMeta{
+			Flow: &workflow.WorkFlow{
+				Entity: common.Entity{
+					ID:     "test",
+					Status: constants.WorkFlowStatusProcessing,
+				},
+				Name: "test",
+			},
+			Define: &WorkFlowDefine{
+				FlowName: "test",
+				Branches: map[string][]*BranchDefine{nodeDefine.Name: branches},
+			},
+			CurrentNode: &workflow.WorkFlowNode{
+				Entity: common.Entity{
+					ID:     "test",
+					Status: constants.WorkFlowStatusInitializing,
+				},
+				Name:      nodeDefine.Name,
+				StartTime: time.Now(),
+			},
+			CurrentNodeDefine: nodeDefine,
+			Context:           NewFlowContext(context.Background(), make(map[string]string)),
+		}
+	}
+	expireLater := func(meta *WorkFlowMeta) {
+		mgr := &WorkFlowManager{}
+		mgr.nodeGoroutineMap.Store(meta.Flow.ID, meta)
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			mgr.handleExpiredWorkFlowNode(context.TODO())
+		}()
+	}
+
+	t.Run("canceled and returned", func(t *testing.T) {
+		returned := int32(0)
+		meta := buildMeta(&NodeDefine{
+			Name: "slow",
+			Executor: func(node *workflow.WorkFlowNode, context *FlowContext) error {
+				<-context.Done()
+				//executor does not return immediately after canceled
+				time.Sleep(20 * time.Millisecond)
+				atomic.StoreInt32(&returned, 1)
+				return context.Err()
+			},
+			ReturnType: SyncFuncNode,
+			Policy:     &NodePolicy{MaxAttempts: 3, Timeout: 10 * time.Millisecond},
+		}, nil)
+		expireLater(meta)
+		meta.Execute()
+		assert.Equal(t, int32(1), atomic.LoadInt32(&returned))
+		assert.Equal(t, constants.WorkFlowStatusError, meta.CurrentNode.Status)
+		assert.Equal(t, 1, meta.CurrentNode.Attempts)
+		assert.Contains(t, meta.CurrentNode.Result, "deadline")
+		assert.Empty(t, meta.deadlines)
+	})
+	t.Run("branch node", func(t *testing.T) {
+		meta := buildMeta(&NodeDefine{
+			Name:       "parallel",
+			ReturnType: ParallelNode,
+		}, []*BranchDefine{
+			{Name: "branch1", TaskNodes: map[string]*NodeDefine{
+				"start": {"branch1Node1", "", "fail", SyncFuncNode, func(node *workflow.WorkFlowNode, context *FlowContext) error {
+					<-context.Done()
+					return context.Err()
+				}, &NodePolicy{Timeout: 10 * time.Millisecond}},
+				"fail": {"branch1Fail", "", "", SyncFuncNode, doNode, nil},
+			}},
+			{Name: "branch2", TaskNodes: map[string]*NodeDefine{
+				"start": {"branch2Node1", "", "fail", SyncFuncNode, doNode, nil},
+				"fail":  {"branch2Fail", "", "", SyncFuncNode, doNode, nil},
+			}},
+		})
+		expireLater(meta)
+		meta.Execute()
+		assert.Equal(t, constants.WorkFlowStatusError, meta.CurrentNode.Status)
+		statuses := make(map[string]string)
+		for _, node := range meta.branchNodes {
+			statuses[node.Name] = node.Status
+		}
+		assert.Equal(t, constants.WorkFlowStatusError, statuses["branch1Node1"])
+		assert.Equal(t, constants.WorkFlowStatusFinished, statuses["branch1Fail"])
+		assert.Equal(t, constants.WorkFlowStatusFinished, statuses["branch2Node1"])
+	})
+	t.Run("polling node", func(t *testing.T) {
+		mockTiupManager := mock_deployment.NewMockInterface(ctrl)
+		mockTiupManager.EXPECT().GetStatus(gomock.Any(), "operation01").Return(deployment.Operation{Status: deployment.Processing}, nil).AnyTimes()
+		mockTiupManager.EXPECT().Cancel(gomock.Any(), "operation01").Return(nil).Times(1)
+		deployment.M = mockTiupManager
+
+		meta := buildMeta(&NodeDefine{
+			Name: "deploy",
+			Executor: func(node *workflow.WorkFlowNode, context *FlowContext) error {
+				node.OperationID = "operation01"
+				return nil
+			},
+			ReturnType: PollingNode,
+			Policy:     &NodePolicy{Timeout: 10 * time.Millisecond},
+		}, nil)
+		expireLater(meta)
+		meta.Execute()
+		assert.Equal(t, constants.WorkFlowStatusError, meta.CurrentNode.Status)
+		assert.Contains(t, meta.CurrentNode.Result, "deadline")
+	})
+}
+
+func TestWorkFlowMeta_ExpireNodes(t *testing.T) {
+	meta := &WorkFlowMeta{
+		Flow: &workflow.WorkFlow{},
+	}
+	node := &workflow.WorkFlowNode{Name: "node"}
+	canceled := 0
+	expired := meta.watchDeadline(node, time.Now().Add(time.Minute), func() {
+		canceled++
+	})
+	assert.Empty(t, meta.ExpireNodes(time.Now()))
+	assert.False(t, isClosed(expired))
+
+	assert.Equal(t, []string{"node"}, meta.ExpireNodes(time.Now().Add(2*time.Minute)))
+	assert.True(t, isClosed(expired))
+	assert.Equal(t, 1, canceled)
+
+	//expired only once
+	assert.Empty(t, meta.ExpireNodes(time.Now().Add(2*time.Minute)))
+	assert.Equal(t, 1, canceled)
+
+	meta.unwatchDeadline(node)
+	assert.Empty(t, meta.deadlines)
+}
+
+func TestFlowContext_withCancel(t *testing.T) {
+	flowCtx := NewFlowContext(context.Background(), make(map[string]string))
+	nodeCtx, cancel := flowCtx.withCancel()
+	assert.NoError(t, nodeCtx.SetData("key", "value"))
+	var value string
+	assert.NoError(t, flowCtx.GetData("key", &value))
+	assert.Equal(t, "value", value)
+
+	cancel()
+	assert.Error(t, nodeCtx.Err())
+	assert.NoError(t, flowCtx.Err())
+}
+
+func TestWorkFlowMeta_Execute_ownershipLost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockFlowRW := mockworkflow.NewMockReaderWriter(ctrl)
+	models.SetWorkFlowReaderWriter(mockFlowRW)
+	mockFlowRW.EXPECT().CreateWorkFlowNode(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockFlowRW.EXPECT().GetWorkFlow(gomock.Any(), gomock.Any()).Return(&workflow.WorkFlow{
+		Entity: common.Entity{ID: "flowId", Status: constants.WorkFlowStatusProcessing},
+	}, nil).AnyTimes()
+	mockFlowRW.EXPECT().UpdateWorkFlowDetail(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, flow *workflow.WorkFlow, nodes []*workflow.WorkFlowNode) error {
+			assert.Equal(t, int64(1), flow.FencingToken)
+			return emerr.NewError(emerr.TIUNIMANAGER_WORKFLOW_OWNERSHIP_LOST, "taken over")
+		}).Times(1)
+
+	executed := false
+	meta := &WorkFlowMeta{
+		Flow: &workflow.WorkFlow{
+			Entity: common.Entity{ID: "flowId", Status: constants.WorkFlowStatusProcessing},
+		},
+		CurrentNode: &workflow.WorkFlowNode{
+			Entity: common.Entity{ID: "nodeId", Status: constants.WorkFlowStatusInitializing},
+			Name:   "test",
+		},
+		CurrentNodeDefine: &NodeDefine{
+			Name: "test",
+			Executor: func(node *workflow.WorkFlowNode, context *FlowContext) error {
+				executed = true
+				return nil
+			},
+			ReturnType: SyncFuncNode,
+		},
+		Context:    NewFlowContext(context.Background(), make(map[string]string)),
+		Ownership:  localFlowOwnership{},
+		OwnerToken: 1,
+	}
+	meta.Execute()
+	assert.False(t, executed)
+}
@@ -0,0 +1,152 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ *  Unless required by applicable law or agreed to in writing, software       *
+ *  distributed under the License is distributed on an "AS IS" BASIS,         *
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  *
+ *  See the License for the specific language governing permissions and       *
+ *  limitations under the License.                                            *
+ ******************************************************************************/
+
+package workflow2
+
+import (
+	"context"
+	"fmt"
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/pingcap/tiunimanager/util/uuidutil"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"sync"
+)
+
+const (
+	flowOwnerKeyPrefix = "/tiunimanager/workflow/owner/"
+	flowOwnerLeaseTTL  = int64(15)
+)
+
+// FlowOwnership claim exclusive execution of workflows among replicas of cluster-server.
+// Token of the ownership is persisted with the workflow, and updates with a stale token are rejected by storage,
+// see WorkFlowReadWrite.UpdateWorkFlowDetail
+type FlowOwnership interface {
+	// Claim
+	// @Description: try to own the workflow, it is idempotent for the owner
+	// @Parameter ctx
+	// @Parameter flowId
+	// @Return token fencing token of the ownership, a new owner always gets a greater token
+	// @Return owned whether the workflow is owned by current replica
+	// @Return err
+	Claim(ctx context.Context, flowId string) (token int64, owned bool, err error)
+
+	// Release
+	// @Description: give up the ownership of the workflow
+	// @Parameter ctx
+	// @Parameter flowId
+	// @Return error
+	Release(ctx context.Context, flowId string) error
+}
+
+// localFlowOwnership ownership for single replica, all workflows are owned by current process
+type localFlowOwnership struct{}
+
+func (l localFlowOwnership) Claim(ctx context.Context, flowId string) (int64, bool, error) {
+	return 0, true, nil
+}
+
+func (l localFlowOwnership) Release(ctx context.Context, flowId string) error {
+	return nil
+}
+
+// unavailableFlowOwnership ownership before etcd is ready, no workflow is owned by current replica,
+// running it without a fencing token would not be rejected after another replica takes it over
+type unavailableFlowOwnership struct{}
+
+func (u unavailableFlowOwnership) Claim(ctx context.Context, flowId string) (int64, bool, error) {
+	return 0, false, errors.NewErrorf(errors.TIUNIMANAGER_WORKFLOW_OWNERSHIP_UNAVAILABLE, "etcd client is not ready, workflow %s can not be claimed", flowId)
+}
+
+func (u unavailableFlowOwnership) Release(ctx context.Context, flowId string) error {
+	return nil
+}
+
+// EtcdFlowOwnership ownership based on etcd, owner key of a workflow is bound to the lease of current replica,
+// so workflows owned by a dead replica are released when its lease expires, and then taken over by others.
+// Create revision of the owner key is used as fencing token.
+type EtcdFlowOwnership struct {
+	client  *framework.EtcdClientV3
+	owner   string
+	leaseID clientv3.LeaseID
+	mutex   sync.Mutex
+}
+
+func NewEtcdFlowOwnership(client *framework.EtcdClientV3, replica string) *EtcdFlowOwnership {
+	return &EtcdFlowOwnership{
+		client:  client,
+		owner:   fmt.Sprintf("%s/%s", replica, uuidutil.GenerateID()),
+		leaseID: clientv3.NoLease,
+	}
+}
+
+func (e *EtcdFlowOwnership) getLease(ctx context.Context) (clientv3.LeaseID, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.leaseID != clientv3.NoLease {
+		return e.leaseID, nil
+	}
+
+	leaseID, err := e.client.GrantLease(flowOwnerLeaseTTL)
+	if err != nil {
+		return clientv3.NoLease, err
+	}
+	keepAlive, err := e.client.KeepAlive(context.Background(), leaseID)
+	if err != nil {
+		return clientv3.NoLease, err
+	}
+	e.leaseID = leaseID
+	go func() {
+		for range keepAlive {
+			//drain keep alive responses
+		}
+		// lease is lost, all workflows owned by it are released, grant a new one for next claim
+		framework.LogWithContext(ctx).Warnf("workflow owner lease %x of %s is lost", leaseID, e.owner)
+		e.mutex.Lock()
+		defer e.mutex.Unlock()
+		if e.leaseID == leaseID {
+			e.leaseID = clientv3.NoLease
+		}
+	}()
+	framework.LogWithContext(ctx).Infof("grant workflow owner lease %x for %s", leaseID, e.owner)
+	return leaseID, nil
+}
+
+func (e *EtcdFlowOwnership) Claim(ctx context.Context, flowId string) (int64, bool, error) {
+	leaseID, err := e.getLease(ctx)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("grant workflow owner lease failed, %s", err.Error())
+		return 0, false, err
+	}
+	owner, token, err := e.client.PutIfAbsent(flowOwnerKeyPrefix+flowId, e.owner, leaseID)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("claim ownership of workflow %s failed, %s", flowId, err.Error())
+		return 0, false, err
+	}
+	return token, owner == e.owner, nil
+}
+
+func (e *EtcdFlowOwnership) Release(ctx context.Context, flowId string) error {
+	return e.client.DeleteIfValue(flowOwnerKeyPrefix+flowId, e.owner)
+}
+
+// isOwnershipLost
+// @Description: whether the workflow update is rejected because the workflow has been taken over by another replica
+// @Parameter err
+// @return bool
+func isOwnershipLost(err error) bool {
+	emErr, ok := err.(errors.EMError)
+	return ok && emErr.GetCode() == errors.TIUNIMANAGER_WORKFLOW_OWNERSHIP_LOST
+}
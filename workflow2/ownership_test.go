@@ -0,0 +1,119 @@
+/******************************************************************************
+ * Copyright (c)  2022 PingCAP                                                *
+ * Licensed under the Apache License, Version 2.0 (the "License");            *
+ * you may not use this file except in compliance with the License.           *
+ * You may obtain a copy of the License at                                    *
+ *                                                                            *
+ * http://www.apache.org/licenses/LICENSE-2.0                                 *
+ *                                                                            *
+ *  Unless required by applicable law or agreed to in writing, software       *
+ *  distributed under the License is distributed on an "AS IS" BASIS,         *
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.  *
+ *  See the License for the specific language governing permissions and       *
+ *  limitations under the License.                                            *
+ ******************************************************************************/
+
+package workflow2
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiunimanager/common/errors"
+	"github.com/pingcap/tiunimanager/library/framework"
+	"github.com/stretchr/testify/assert"
+	etcd "go.etcd.io/etcd/server/v3/embed"
+)
+
+func startEmbedEtcd(t *testing.T) *framework.EtcdClientV3 {
+	clientUrl, _ := url.Parse("http://127.0.0.1:23791")
+	peerUrl, _ := url.Parse("http://127.0.0.1:23801")
+
+	cfg := etcd.NewConfig()
+	cfg.Name = "ownership"
+	cfg.Dir = t.TempDir()
+	cfg.LogLevel = "error"
+	cfg.LCUrls, cfg.ACUrls = []url.URL{*clientUrl}, []url.URL{*clientUrl}
+	cfg.LPUrls, cfg.APUrls = []url.URL{*peerUrl}, []url.URL{*peerUrl}
+	cfg.InitialCluster = cfg.InitialClusterFromName(cfg.Name)
+
+	e, err := etcd.StartEtcd(cfg)
+	if err != nil {
+		t.Fatalf("start embed etcd failed, %s", err.Error())
+	}
+	t.Cleanup(e.Close)
+	select {
+	case <-e.Server.ReadyNotify():
+	case <-time.After(30 * time.Second):
+		e.Server.Stop()
+		t.Fatal("embed etcd took too long to start")
+	}
+	return framework.InitEtcdClient([]string{clientUrl.String()})
+}
+
+func TestEtcdFlowOwnership(t *testing.T) {
+	client := startEmbedEtcd(t)
+	ctx := context.Background()
+	replica1 := NewEtcdFlowOwnership(client, "127.0.0.1:4100")
+	replica2 := NewEtcdFlowOwnership(client, "127.0.0.1:4101")
+
+	t.Run("claim", func(t *testing.T) {
+		token, owned, err := replica1.Claim(ctx, "flow1")
+		assert.NoError(t, err)
+		assert.True(t, owned)
+		assert.Greater(t, token, int64(0))
+
+		again, owned, err := replica1.Claim(ctx, "flow1")
+		assert.NoError(t, err)
+		assert.True(t, owned)
+		assert.Equal(t, token, again)
+	})
+	t.Run("owned by another", func(t *testing.T) {
+		_, owned, err := replica2.Claim(ctx, "flow1")
+		assert.NoError(t, err)
+		assert.False(t, owned)
+	})
+	t.Run("release and take over", func(t *testing.T) {
+		token, _, err := replica1.Claim(ctx, "flow2")
+		assert.NoError(t, err)
+
+		// release by non-owner takes no effect
+		assert.NoError(t, replica2.Release(ctx, "flow2"))
+		_, owned, err := replica2.Claim(ctx, "flow2")
+		assert.NoError(t, err)
+		assert.False(t, owned)
+
+		assert.NoError(t, replica1.Release(ctx, "flow2"))
+		newToken, owned, err := replica2.Claim(ctx, "flow2")
+		assert.NoError(t, err)
+		assert.True(t, owned)
+		assert.Greater(t, newToken, token)
+	})
+	t.Run("lease lost", func(t *testing.T) {
+		token, owned, err := replica1.Claim(ctx, "flow3")
+		assert.NoError(t, err)
+		assert.True(t, owned)
+
+		// lease of a dead replica expires, all of its workflows are released
+		_, err = client.Lease().Revoke(ctx, replica1.leaseID)
+		assert.NoError(t, err)
+
+		newToken, owned, err := replica2.Claim(ctx, "flow3")
+		assert.NoError(t, err)
+		assert.True(t, owned)
+		assert.Greater(t, newToken, token)
+
+		_, owned, err = replica2.Claim(ctx, "flow1")
+		assert.NoError(t, err)
+		assert.True(t, owned)
+	})
+}
+
+func TestUnavailableFlowOwnership(t *testing.T) {
+	_, owned, err := unavailableFlowOwnership{}.Claim(context.Background(), "flow1")
+	assert.Error(t, err)
+	assert.Equal(t, errors.TIUNIMANAGER_WORKFLOW_OWNERSHIP_UNAVAILABLE, err.(errors.EMError).GetCode())
+	assert.False(t, owned)
+}
@@ -104,4 +104,29 @@ type WorkFlowService interface {
 	// @Parameter reason
 	// @Return error
 	Cancel(ctx context.Context, flowId string, reason string) error
+
+	// Retry
+	// @Description: resume failed workflow from the failed node
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter flowId
+	// @Return error
+	Retry(ctx context.Context, flowId string) error
+
+	// ResumeFromNode
+	// @Description: resume failed workflow from the specified node, keep the history of executed nodes
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter flowId
+	// @Parameter nodeName
+	// @Return error
+	ResumeFromNode(ctx context.Context, flowId string, nodeName string) error
+
+	// Abandon
+	// @Description: give up resuming failed workflow, execute the failure handling deferred by the workflow
+	// @Receiver m
+	// @Parameter ctx
+	// @Parameter flowId
+	// @Return error
+	Abandon(ctx context.Context, flowId string) error
 }
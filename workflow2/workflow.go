@@ -25,15 +25,17 @@ import (
 	"github.com/pingcap/tiunimanager/models"
 	"github.com/pingcap/tiunimanager/models/common"
 	"github.com/pingcap/tiunimanager/models/workflow"
+	"sort"
 	"sync"
 	"time"
 )
 
 type WorkFlowManager struct {
 	flowDefineMap    sync.Map //key: flowName, value: flowDefine
-	nodeGoroutineMap sync.Map //key: flowId, value: stopChannel
+	nodeGoroutineMap sync.Map //key: flowId, value: *WorkFlowMeta in executing
 	watchInterval    time.Duration
-	retry            int //todo: node retry times
+	ownership        FlowOwnership
+	ownershipMutex   sync.Mutex
 }
 
 var workflowService WorkFlowService
@@ -51,7 +53,6 @@ func GetWorkFlowService() WorkFlowService {
 func NewWorkFlowManager() WorkFlowService {
 	mgr := &WorkFlowManager{
 		watchInterval: 5 * time.Second,
-		retry:         1,
 	}
 	go mgr.watchLoop(context.Background())
 	return mgr
@@ -61,14 +62,36 @@ func MockWorkFlowService(service WorkFlowService) {
 	workflowService = service
 }
 
+// getOwnership
+// @Description: ownership of workflows is claimed through etcd once etcd client of framework is ready,
+// claims fail until then, current process is the only owner only if it is not running as a service
+// @Receiver mgr
+// @return FlowOwnership
+func (mgr *WorkFlowManager) getOwnership() FlowOwnership {
+	mgr.ownershipMutex.Lock()
+	defer mgr.ownershipMutex.Unlock()
+	if mgr.ownership != nil {
+		return mgr.ownership
+	}
+	if framework.Current == nil {
+		return localFlowOwnership{}
+	}
+	if framework.Current.GetEtcdClient() == nil {
+		return unavailableFlowOwnership{}
+	}
+	mgr.ownership = NewEtcdFlowOwnership(framework.Current.GetEtcdClient(), framework.Current.GetServiceMeta().ServiceAddress)
+	return mgr.ownership
+}
+
 func (mgr *WorkFlowManager) watchLoop(ctx context.Context) {
 	ticker := time.NewTicker(mgr.watchInterval)
 	for range ticker.C {
 		framework.LogWithContext(ctx).Infof("begin workflow watchLoop every %+v", mgr.watchInterval)
 		mgr.nodeGoroutineMap.Range(func(key, value interface{}) bool {
-			framework.LogWithContext(ctx).Infof("key %s, value %s", key, value)
+			framework.LogWithContext(ctx).Infof("key %s, value %s", key, value.(*WorkFlowMeta).Flow.Name)
 			return true
 		})
+		mgr.handleExpiredWorkFlowNode(ctx)
 		//handle processing workflow last
 		mgr.handleUnFinishedWorkFlow(ctx, constants.WorkFlowStatusCanceling)
 		mgr.handleUnFinishedWorkFlow(ctx, constants.WorkFlowStatusStopped)
@@ -76,6 +99,21 @@ func (mgr *WorkFlowManager) watchLoop(ctx context.Context) {
 	}
 }
 
+// handleExpiredWorkFlowNode
+// @Description: watchdog of executing nodes, expire the node past its deadline, then it will be failed and go to FailEvent
+// @Receiver mgr
+// @Parameter ctx
+func (mgr *WorkFlowManager) handleExpiredWorkFlowNode(ctx context.Context) {
+	now := time.Now()
+	mgr.nodeGoroutineMap.Range(func(key, value interface{}) bool {
+		flowMeta := value.(*WorkFlowMeta)
+		for _, nodeName := range flowMeta.ExpireNodes(now) {
+			framework.LogWithContext(ctx).Warnf("workflow id %s, node %s is past its deadline, cancel it", key, nodeName)
+		}
+		return true
+	})
+}
+
 func (mgr *WorkFlowManager) handleUnFinishedWorkFlow(ctx context.Context, status string) {
 	//todo: recover
 	for page, pageSize := 1, defaultPageSize; ; page++ {
@@ -93,21 +131,38 @@ func (mgr *WorkFlowManager) handleUnFinishedWorkFlow(ctx context.Context, status
 				_, exist := mgr.nodeGoroutineMap.Load(flow.ID)
 				if !exist {
 					//workflow has no processing goroutine
+					ownership := mgr.getOwnership()
+					token, owned, err := ownership.Claim(ctx, flow.ID)
+					if err != nil {
+						framework.LogWithContext(ctx).Warnf("claim workflow %s failed %s", flow.ID, err.Error())
+						continue
+					}
+					if !owned {
+						//workflow is executing by another replica
+						continue
+					}
 					flowMeta, err := NewWorkFlowMeta(ctx, flow.ID)
 					if err != nil {
 						framework.LogWithContext(ctx).Errorf("build workflow meta by flow id %s failed %s", flow.ID, err.Error())
 						return
 					}
-					mgr.nodeGoroutineMap.Store(flow.ID, flow.ID)
+					flowMeta.Ownership = ownership
+					flowMeta.OwnerToken = token
+					mgr.nodeGoroutineMap.Store(flow.ID, flowMeta)
 					go func() {
 						//todo: recover
 						defer func() {
 							mgr.nodeGoroutineMap.Delete(flowMeta.Flow.ID) //clean node go routine map whether end or stop
-							framework.LogWithContext(context.Background()).Infof("delete flow id %s", flow.ID)
+							framework.LogWithContext(context.Background()).Infof("delete flow id %s", flowMeta.Flow.ID)
 						}()
 
 						//load workflow, call executor and handle polling, restore workflow
 						flowMeta.Execute()
+						if flowMeta.Flow.Finished() {
+							if err := ownership.Release(ctx, flowMeta.Flow.ID); err != nil {
+								framework.LogWithContext(ctx).Warnf("release ownership of workflow %s failed %s", flowMeta.Flow.ID, err.Error())
+							}
+						}
 					}()
 				} else {
 					//workflow has processing goroutine
@@ -129,12 +184,24 @@ func (mgr *WorkFlowManager) handleUnFinishedWorkFlow(ctx context.Context, status
 					//workflow has processing goroutine
 					mgr.nodeGoroutineMap.Delete(flow.ID)
 				}
+				ownership := mgr.getOwnership()
+				token, owned, err := ownership.Claim(ctx, flow.ID)
+				if err != nil {
+					framework.LogWithContext(ctx).Warnf("claim workflow %s failed %s", flow.ID, err.Error())
+					continue
+				}
+				if !owned {
+					//workflow is executing by another replica, which will cancel it
+					continue
+				}
 				//load workflow, cancel flow and node status, update workflow
 				flowMeta, err := NewWorkFlowMeta(ctx, flow.ID)
 				if err != nil {
 					framework.LogWithContext(ctx).Errorf("build workflow meta by flow id %s failed %s", flow.ID, err.Error())
 					return
 				}
+				flowMeta.Ownership = ownership
+				flowMeta.OwnerToken = token
 				if flowMeta.CurrentNode != nil {
 					flowMeta.CurrentNode.Status = constants.WorkFlowStatusCanceled
 					handleWorkFlowNodeMetrics(flowMeta, flowMeta.CurrentNode)
@@ -142,6 +209,9 @@ func (mgr *WorkFlowManager) handleUnFinishedWorkFlow(ctx context.Context, status
 				flowMeta.Flow.Status = constants.WorkFlowStatusCanceled
 				handleWorkFlowMetrics(flowMeta.Flow)
 				flowMeta.Restore()
+				if err = ownership.Release(ctx, flow.ID); err != nil {
+					framework.LogWithContext(ctx).Warnf("release ownership of workflow %s failed %s", flow.ID, err.Error())
+				}
 				framework.LogWithContext(ctx).Infof("cancel workflow id %s, name %s success", flow.ID, flow.Name)
 			}
 		}
@@ -243,25 +313,44 @@ func (mgr *WorkFlowManager) DetailWorkFlow(ctx context.Context, request message.
 		},
 		NodeInfo:  make([]*structs.WorkFlowNodeInfo, 0),
 		NodeNames: define.getNodeNameList(),
+		Branches:  define.getBranchInfoList(),
 	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].CreatedAt.Before(nodes[j].CreatedAt)
+	})
+	failed := false
 	for _, node := range nodes {
-		resp.NodeInfo = append(resp.NodeInfo, &structs.WorkFlowNodeInfo{
-			ID:         node.ID,
-			Name:       node.Name,
-			Parameters: node.Parameters,
-			Result:     node.Result,
-			Status:     node.Status,
-			StartTime:  node.StartTime,
-			EndTime:    node.EndTime,
-		})
-		if node.Status == constants.WorkFlowStatusError {
-			break
+		if node.Branch != "" {
+			//nodes of parallel branches are shown with their parallel node
+			resp.NodeInfo = append(resp.NodeInfo, buildWorkFlowNodeInfo(node))
+			continue
+		}
+		if failed && !node.Resumed {
+			//skip nodes handling the failure, until the workflow is resumed
+			continue
 		}
+		resp.NodeInfo = append(resp.NodeInfo, buildWorkFlowNodeInfo(node))
+		failed = node.Status == constants.WorkFlowStatusError
 	}
 
 	return resp, nil
 }
 
+func buildWorkFlowNodeInfo(node *workflow.WorkFlowNode) *structs.WorkFlowNodeInfo {
+	return &structs.WorkFlowNodeInfo{
+		ID:         node.ID,
+		Name:       node.Name,
+		Branch:     node.Branch,
+		Parameters: node.Parameters,
+		Result:     node.Result,
+		Status:     node.Status,
+		Attempts:   node.Attempts,
+		Resumed:    node.Resumed,
+		StartTime:  node.StartTime,
+		EndTime:    node.EndTime,
+	}
+}
+
 func (mgr *WorkFlowManager) InitContext(ctx context.Context, flowId string, key string, value interface{}) error {
 	meta, err := NewWorkFlowMeta(ctx, flowId)
 	if err != nil {
@@ -340,3 +429,118 @@ func (mgr *WorkFlowManager) Cancel(ctx context.Context, flowId string, reason st
 	handleWorkFlowMetrics(flow)
 	return err
 }
+
+func (mgr *WorkFlowManager) Retry(ctx context.Context, flowId string) error {
+	framework.LogWithContext(ctx).Infof("Begin retry workflow Id %s", flowId)
+	flow, nodes, err := models.GetWorkFlowReaderWriter().QueryDetailWorkFlow(ctx, flowId)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("get workflow by workflow Id %s, failed %s", flowId, err.Error())
+		return errors.WrapError(errors.TIUNIMANAGER_WORKFLOW_QUERY_FAILED, err.Error(), err)
+	}
+	define, err := mgr.GetWorkFlowDefine(ctx, flow.Name)
+	if err != nil {
+		return err
+	}
+
+	var failed *workflow.WorkFlowNode
+	for _, node := range nodes {
+		if node.Status != constants.WorkFlowStatusError || define.isFailNode(node.Name) {
+			continue
+		}
+		if failed == nil || failed.CreatedAt.Before(node.CreatedAt) {
+			failed = node
+		}
+	}
+	if failed == nil {
+		return errors.NewErrorf(errors.TIUNIMANAGER_WORKFLOW_RESUME_FAILED, "workflow Id %s has no failed node", flowId)
+	}
+	return mgr.ResumeFromNode(ctx, flowId, failed.Name)
+}
+
+func (mgr *WorkFlowManager) ResumeFromNode(ctx context.Context, flowId string, nodeName string) error {
+	framework.LogWithContext(ctx).Infof("Begin resume workflow Id %s from node %s", flowId, nodeName)
+	flow, nodes, err := models.GetWorkFlowReaderWriter().QueryDetailWorkFlow(ctx, flowId)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("get workflow by workflow Id %s, failed %s", flowId, err.Error())
+		return errors.WrapError(errors.TIUNIMANAGER_WORKFLOW_QUERY_FAILED, err.Error(), err)
+	}
+	if flow.Status != constants.WorkFlowStatusError {
+		return errors.NewErrorf(errors.TIUNIMANAGER_WORKFLOW_RESUME_FAILED, "workflow Id %s is %s, only failed workflow can be resumed", flowId, flow.Status)
+	}
+	if _, exist := mgr.nodeGoroutineMap.Load(flowId); exist {
+		return errors.NewErrorf(errors.TIUNIMANAGER_WORKFLOW_RESUME_FAILED, "workflow Id %s is still handling failure", flowId)
+	}
+
+	define, err := mgr.GetWorkFlowDefine(ctx, flow.Name)
+	if err != nil {
+		return err
+	}
+	nodeDefineKey := define.getNodeDefineKeyByName(nodeName)
+	if nodeDefineKey == "" || define.isFailNode(nodeName) {
+		return errors.NewErrorf(errors.TIUNIMANAGER_WORKFLOW_RESUME_FAILED, "workflow %s can not be resumed from node %s", flow.Name, nodeName)
+	}
+	if define.hasExecutedFailNode(nodes) {
+		//resources and maintenance status may have been released by the fail node, resuming would run without them
+		return errors.NewErrorf(errors.TIUNIMANAGER_WORKFLOW_RESUME_FAILED, "workflow Id %s has executed its failure handling, submit a new operation instead", flowId)
+	}
+
+	//persist the node to resume only if the workflow is still failed, watchLoop will rebuild flow context and execute it
+	err = models.GetWorkFlowReaderWriter().ResumeWorkFlow(ctx, flowId, &workflow.WorkFlowNode{
+		Entity: common.Entity{
+			TenantId: flow.TenantId,
+			Status:   constants.WorkFlowStatusInitializing,
+		},
+		Name:       nodeName,
+		BizID:      flow.BizID,
+		ParentID:   flow.ID,
+		ReturnType: string(define.TaskNodes[nodeDefineKey].ReturnType),
+		Resumed:    true,
+		StartTime:  time.Now(),
+	})
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("resume workflow Id %s from node %s, failed %s", flowId, nodeName, err.Error())
+		return errors.WrapError(errors.TIUNIMANAGER_WORKFLOW_RESUME_FAILED, err.Error(), err)
+	}
+	flow.Status = constants.WorkFlowStatusProcessing
+	handleWorkFlowMetrics(flow)
+	return nil
+}
+
+func (mgr *WorkFlowManager) Abandon(ctx context.Context, flowId string) error {
+	framework.LogWithContext(ctx).Infof("Begin abandon workflow Id %s", flowId)
+	flow, nodes, err := models.GetWorkFlowReaderWriter().QueryDetailWorkFlow(ctx, flowId)
+	if err != nil {
+		framework.LogWithContext(ctx).Errorf("get workflow by workflow Id %s, failed %s", flowId, err.Error())
+		return errors.WrapError(errors.TIUNIMANAGER_WORKFLOW_QUERY_FAILED, err.Error(), err)
+	}
+	if flow.Status != constants.WorkFlowStatusError {
+		return errors.NewErrorf(errors.TIUNIMANAGER_WORKFLOW_ABANDON_FAILED, "workflow Id %s is %s, only failed workflow can be abandoned", flowId, flow.Status)
+	}
+	if _, exist := mgr.nodeGoroutineMap.Load(flowId); exist {
+		return errors.NewErrorf(errors.TIUNIMANAGER_WORKFLOW_ABANDON_FAILED, "workflow Id %s is still handling failure", flowId)
+	}
+
+	define, err := mgr.GetWorkFlowDefine(ctx, flow.Name)
+	if err != nil {
+		return err
+	}
+	var latest *workflow.WorkFlowNode
+	for _, node := range nodes {
+		if node.Branch == "" && (latest == nil || latest.CreatedAt.Before(node.CreatedAt)) {
+			latest = node
+		}
+	}
+	if latest == nil || latest.Status != constants.WorkFlowStatusError ||
+		define.getFailNodeDefine(latest.Name) == nil || define.hasExecutedFailNode(nodes) {
+		return errors.NewErrorf(errors.TIUNIMANAGER_WORKFLOW_ABANDON_FAILED, "workflow Id %s has no deferred failure handling", flowId)
+	}
+
+	//watchLoop will execute the FailEvent of the failed node, then the workflow is failed again
+	err = models.GetWorkFlowReaderWriter().UpdateWorkFlow(ctx, flowId, constants.WorkFlowStatusProcessing, "")
+	if err != nil {
+		return err
+	}
+	flow.Status = constants.WorkFlowStatusProcessing
+	handleWorkFlowMetrics(flow)
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"github.com/golang/mock/gomock"
 	"github.com/pingcap/tiunimanager/common/constants"
+	emerr "github.com/pingcap/tiunimanager/common/errors"
 	"github.com/pingcap/tiunimanager/common/structs"
 	"github.com/pingcap/tiunimanager/library/framework"
 	"github.com/pingcap/tiunimanager/message"
@@ -63,10 +64,10 @@ func TestFlowManager_RegisterWorkFlow(t *testing.T) {
 		&WorkFlowDefine{
 			FlowName: "flowName",
 			TaskNodes: map[string]*NodeDefine{
-				"start":         {"nodeName1", "nodeName1Done", "fail", SyncFuncNode, doNodeName1},
-				"nodeName1Done": {"nodeName2", "nodeName2Done", "fail", SyncFuncNode, doNodeName2},
-				"nodeName2Done": {"end", "", "", SyncFuncNode, doSuccess},
-				"fail":          {"end", "", "", SyncFuncNode, doFail},
+				"start":         {"nodeName1", "nodeName1Done", "fail", SyncFuncNode, doNodeName1, nil},
+				"nodeName1Done": {"nodeName2", "nodeName2Done", "fail", SyncFuncNode, doNodeName2, nil},
+				"nodeName2Done": {"end", "", "", SyncFuncNode, doSuccess, nil},
+				"fail":          {"end", "", "", SyncFuncNode, doFail, nil},
 			},
 		})
 
@@ -108,10 +109,10 @@ func TestFlowManager_Start_case1(t *testing.T) {
 		&WorkFlowDefine{
 			FlowName: "flowName",
 			TaskNodes: map[string]*NodeDefine{
-				"start":         {"nodeName1", "nodeName1Done", "fail", SyncFuncNode, doNodeName1},
-				"nodeName1Done": {"nodeName2", "nodeName2Done", "fail", SyncFuncNode, doNodeName2},
-				"nodeName2Done": {"end", "", "", SyncFuncNode, CompositeExecutor(doFail, defaultSuccess)},
-				"fail":          {"end", "", "", SyncFuncNode, doFail},
+				"start":         {"nodeName1", "nodeName1Done", "fail", SyncFuncNode, doNodeName1, nil},
+				"nodeName1Done": {"nodeName2", "nodeName2Done", "fail", SyncFuncNode, doNodeName2, nil},
+				"nodeName2Done": {"end", "", "", SyncFuncNode, CompositeExecutor(doFail, defaultSuccess), nil},
+				"fail":          {"end", "", "", SyncFuncNode, doFail, nil},
 			},
 		})
 
@@ -153,10 +154,10 @@ func TestFlowManager_Start_case2(t *testing.T) {
 		&WorkFlowDefine{
 			FlowName: "flowName",
 			TaskNodes: map[string]*NodeDefine{
-				"start":         {"nodeName1", "nodeName1Done", "fail", SyncFuncNode, doNodeName1},
-				"nodeName1Done": {"nodeName2", "nodeName2Done", "fail", SyncFuncNode, doNodeName2},
-				"nodeName2Done": {"end", "", "", SyncFuncNode, CompositeExecutor(doFail, defaultSuccess)},
-				"fail":          {"end", "", "", SyncFuncNode, doFail},
+				"start":         {"nodeName1", "nodeName1Done", "fail", SyncFuncNode, doNodeName1, nil},
+				"nodeName1Done": {"nodeName2", "nodeName2Done", "fail", SyncFuncNode, doNodeName2, nil},
+				"nodeName2Done": {"end", "", "", SyncFuncNode, CompositeExecutor(doFail, defaultSuccess), nil},
+				"fail":          {"end", "", "", SyncFuncNode, doFail, nil},
 			},
 		})
 
@@ -198,10 +199,10 @@ func TestFlowManager_Start_case3(t *testing.T) {
 		&WorkFlowDefine{
 			FlowName: "flowName",
 			TaskNodes: map[string]*NodeDefine{
-				"start":         {"nodeName1", "nodeName1Done", "fail", SyncFuncNode, doNodeName1},
-				"nodeName1Done": {"nodeName2", "nodeName2Done", "fail", SyncFuncNode, doNodeName2},
-				"nodeName2Done": {"end", "", "", SyncFuncNode, CompositeExecutor(doFail, defaultSuccess)},
-				"fail":          {"end", "", "", SyncFuncNode, doFail},
+				"start":         {"nodeName1", "nodeName1Done", "fail", SyncFuncNode, doNodeName1, nil},
+				"nodeName1Done": {"nodeName2", "nodeName2Done", "fail", SyncFuncNode, doNodeName2, nil},
+				"nodeName2Done": {"end", "", "", SyncFuncNode, CompositeExecutor(doFail, defaultSuccess), nil},
+				"fail":          {"end", "", "", SyncFuncNode, doFail, nil},
 			},
 		})
 
@@ -242,10 +243,10 @@ func TestFlowManager_DetailWorkFlow(t *testing.T) {
 		&WorkFlowDefine{
 			FlowName: "flowName",
 			TaskNodes: map[string]*NodeDefine{
-				"start":         {"nodeName1", "nodeName1Done", "fail", SyncFuncNode, doNodeName1},
-				"nodeName1Done": {"nodeName2", "nodeName2Done", "fail", SyncFuncNode, doNodeName2},
-				"nodeName2Done": {"end", "", "", SyncFuncNode, doSuccess},
-				"fail":          {"end", "", "", SyncFuncNode, doFail},
+				"start":         {"nodeName1", "nodeName1Done", "fail", SyncFuncNode, doNodeName1, nil},
+				"nodeName1Done": {"nodeName2", "nodeName2Done", "fail", SyncFuncNode, doNodeName2, nil},
+				"nodeName2Done": {"end", "", "", SyncFuncNode, doSuccess, nil},
+				"fail":          {"end", "", "", SyncFuncNode, doFail, nil},
 			},
 		})
 
@@ -397,10 +398,10 @@ func TestFlowManager_InitContext(t *testing.T) {
 		&WorkFlowDefine{
 			FlowName: "flowName",
 			TaskNodes: map[string]*NodeDefine{
-				"start":         {"nodeName1", "nodeName1Done", "fail", SyncFuncNode, doNodeName1},
-				"nodeName1Done": {"nodeName2", "nodeName2Done", "fail", SyncFuncNode, doNodeName2},
-				"nodeName2Done": {"end", "", "", SyncFuncNode, doSuccess},
-				"fail":          {"end", "", "", SyncFuncNode, doFail},
+				"start":         {"nodeName1", "nodeName1Done", "fail", SyncFuncNode, doNodeName1, nil},
+				"nodeName1Done": {"nodeName2", "nodeName2Done", "fail", SyncFuncNode, doNodeName2, nil},
+				"nodeName2Done": {"end", "", "", SyncFuncNode, doSuccess, nil},
+				"fail":          {"end", "", "", SyncFuncNode, doFail, nil},
 			},
 		})
 	flowId, errCreate := manager.CreateWorkFlow(context.TODO(), "clusterId", BizTypeCluster, "flowName")
@@ -421,3 +422,282 @@ func TestFlowManager_loop(t *testing.T) {
 	assert.NotNil(t, manager)
 	time.Sleep(10 * time.Second)
 }
+
+func registerResumeFlow() WorkFlowService {
+	manager := GetWorkFlowService()
+	manager.RegisterWorkFlow(context.TODO(), "resumeFlow",
+		&WorkFlowDefine{
+			FlowName: "resumeFlow",
+			TaskNodes: map[string]*NodeDefine{
+				"start":         {"nodeName1", "nodeName1Done", "fail", SyncFuncNode, doNodeName1, nil},
+				"nodeName1Done": {"nodeName2", "nodeName2Done", "fail", SyncFuncNode, doNodeName2, nil},
+				"nodeName2Done": {"end", "", "", SyncFuncNode, doSuccess, nil},
+				"fail":          {"fail", "", "", SyncFuncNode, doFail, nil},
+			},
+		})
+	return manager
+}
+
+func TestFlowManager_ResumeFromNode(t *testing.T) {
+	manager := registerResumeFlow()
+	now := time.Now()
+
+	t.Run("normal", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockFlowRW := mockworkflow.NewMockReaderWriter(ctrl)
+		mockFlowRW.EXPECT().QueryDetailWorkFlow(gomock.Any(), "testflowId").Return(&wfModel.WorkFlow{
+			Entity: common.Entity{ID: "testflowId", Status: constants.WorkFlowStatusError},
+			Name:   "resumeFlow",
+		}, []*wfModel.WorkFlowNode{
+			{Entity: common.Entity{CreatedAt: now, Status: constants.WorkFlowStatusFinished}, Name: "nodeName1"},
+			{Entity: common.Entity{CreatedAt: now.Add(time.Second), Status: constants.WorkFlowStatusError}, Name: "nodeName2"},
+		}, nil).Times(1)
+		mockFlowRW.EXPECT().ResumeWorkFlow(gomock.Any(), "testflowId", gomock.Any()).DoAndReturn(func(ctx context.Context, flowId string, node *wfModel.WorkFlowNode) error {
+			assert.Equal(t, "nodeName2", node.Name)
+			assert.Equal(t, "testflowId", node.ParentID)
+			assert.Equal(t, constants.WorkFlowStatusInitializing, node.Status)
+			assert.True(t, node.Resumed)
+			return nil
+		}).Times(1)
+		models.SetWorkFlowReaderWriter(mockFlowRW)
+
+		err := manager.ResumeFromNode(context.TODO(), "testflowId", "nodeName2")
+		assert.NoError(t, err)
+	})
+	t.Run("resumed concurrently", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockFlowRW := mockworkflow.NewMockReaderWriter(ctrl)
+		mockFlowRW.EXPECT().QueryDetailWorkFlow(gomock.Any(), "testflowId").Return(&wfModel.WorkFlow{
+			Entity: common.Entity{ID: "testflowId", Status: constants.WorkFlowStatusError},
+			Name:   "resumeFlow",
+		}, []*wfModel.WorkFlowNode{
+			{Entity: common.Entity{CreatedAt: now, Status: constants.WorkFlowStatusError}, Name: "nodeName1"},
+		}, nil).Times(1)
+		mockFlowRW.EXPECT().ResumeWorkFlow(gomock.Any(), "testflowId", gomock.Any()).
+			Return(emerr.NewErrorf(emerr.TIUNIMANAGER_WORKFLOW_RESUME_FAILED, "workflow testflowId is not failed")).Times(1)
+		mockFlowRW.EXPECT().UpdateWorkFlow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		models.SetWorkFlowReaderWriter(mockFlowRW)
+
+		err := manager.ResumeFromNode(context.TODO(), "testflowId", "nodeName1")
+		assert.Error(t, err)
+	})
+	t.Run("not failed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockFlowRW := mockworkflow.NewMockReaderWriter(ctrl)
+		mockFlowRW.EXPECT().QueryDetailWorkFlow(gomock.Any(), "testflowId").Return(&wfModel.WorkFlow{
+			Entity: common.Entity{ID: "testflowId", Status: constants.WorkFlowStatusFinished},
+			Name:   "resumeFlow",
+		}, nil, nil).Times(1)
+		models.SetWorkFlowReaderWriter(mockFlowRW)
+
+		err := manager.ResumeFromNode(context.TODO(), "testflowId", "nodeName2")
+		assert.Error(t, err)
+	})
+	t.Run("invalid node", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockFlowRW := mockworkflow.NewMockReaderWriter(ctrl)
+		mockFlowRW.EXPECT().QueryDetailWorkFlow(gomock.Any(), "testflowId").Return(&wfModel.WorkFlow{
+			Entity: common.Entity{ID: "testflowId", Status: constants.WorkFlowStatusError},
+			Name:   "resumeFlow",
+		}, nil, nil).Times(2)
+		models.SetWorkFlowReaderWriter(mockFlowRW)
+
+		err := manager.ResumeFromNode(context.TODO(), "testflowId", "unknown")
+		assert.Error(t, err)
+		err = manager.ResumeFromNode(context.TODO(), "testflowId", "fail")
+		assert.Error(t, err)
+	})
+	t.Run("fail node executed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockFlowRW := mockworkflow.NewMockReaderWriter(ctrl)
+		mockFlowRW.EXPECT().QueryDetailWorkFlow(gomock.Any(), "testflowId").Return(&wfModel.WorkFlow{
+			Entity: common.Entity{ID: "testflowId", Status: constants.WorkFlowStatusError},
+			Name:   "resumeFlow",
+		}, []*wfModel.WorkFlowNode{
+			{Entity: common.Entity{CreatedAt: now, Status: constants.WorkFlowStatusFinished}, Name: "nodeName1"},
+			{Entity: common.Entity{CreatedAt: now.Add(time.Second), Status: constants.WorkFlowStatusError}, Name: "nodeName2"},
+			{Entity: common.Entity{CreatedAt: now.Add(2 * time.Second), Status: constants.WorkFlowStatusFinished}, Name: "fail"},
+		}, nil).Times(1)
+		models.SetWorkFlowReaderWriter(mockFlowRW)
+
+		err := manager.ResumeFromNode(context.TODO(), "testflowId", "nodeName2")
+		assert.Error(t, err)
+	})
+	t.Run("fail node executed before resumed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockFlowRW := mockworkflow.NewMockReaderWriter(ctrl)
+		mockFlowRW.EXPECT().QueryDetailWorkFlow(gomock.Any(), "testflowId").Return(&wfModel.WorkFlow{
+			Entity: common.Entity{ID: "testflowId", Status: constants.WorkFlowStatusError},
+			Name:   "resumeFlow",
+		}, []*wfModel.WorkFlowNode{
+			{Entity: common.Entity{CreatedAt: now, Status: constants.WorkFlowStatusError}, Name: "nodeName1"},
+			{Entity: common.Entity{CreatedAt: now.Add(time.Second), Status: constants.WorkFlowStatusFinished}, Name: "fail"},
+			{Entity: common.Entity{CreatedAt: now.Add(2 * time.Second), Status: constants.WorkFlowStatusError}, Name: "nodeName1", Resumed: true},
+		}, nil).Times(1)
+		mockFlowRW.EXPECT().ResumeWorkFlow(gomock.Any(), "testflowId", gomock.Any()).Return(nil).Times(1)
+		models.SetWorkFlowReaderWriter(mockFlowRW)
+
+		err := manager.ResumeFromNode(context.TODO(), "testflowId", "nodeName1")
+		assert.NoError(t, err)
+	})
+}
+
+func TestFlowManager_Retry(t *testing.T) {
+	manager := registerResumeFlow()
+	now := time.Now()
+
+	t.Run("normal", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		flow := &wfModel.WorkFlow{
+			Entity: common.Entity{ID: "testflowId", Status: constants.WorkFlowStatusError},
+			Name:   "resumeFlow",
+		}
+		mockFlowRW := mockworkflow.NewMockReaderWriter(ctrl)
+		mockFlowRW.EXPECT().QueryDetailWorkFlow(gomock.Any(), "testflowId").Return(flow, []*wfModel.WorkFlowNode{
+			{Entity: common.Entity{CreatedAt: now, Status: constants.WorkFlowStatusFinished}, Name: "nodeName1"},
+			{Entity: common.Entity{CreatedAt: now.Add(time.Second), Status: constants.WorkFlowStatusError}, Name: "nodeName2"},
+		}, nil).Times(2)
+		mockFlowRW.EXPECT().ResumeWorkFlow(gomock.Any(), "testflowId", gomock.Any()).DoAndReturn(func(ctx context.Context, flowId string, node *wfModel.WorkFlowNode) error {
+			assert.Equal(t, "nodeName2", node.Name)
+			return nil
+		}).Times(1)
+		models.SetWorkFlowReaderWriter(mockFlowRW)
+
+		err := manager.Retry(context.TODO(), "testflowId")
+		assert.NoError(t, err)
+	})
+	t.Run("fail node executed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		flow := &wfModel.WorkFlow{
+			Entity: common.Entity{ID: "testflowId", Status: constants.WorkFlowStatusError},
+			Name:   "resumeFlow",
+		}
+		mockFlowRW := mockworkflow.NewMockReaderWriter(ctrl)
+		mockFlowRW.EXPECT().QueryDetailWorkFlow(gomock.Any(), "testflowId").Return(flow, []*wfModel.WorkFlowNode{
+			{Entity: common.Entity{CreatedAt: now, Status: constants.WorkFlowStatusFinished}, Name: "nodeName1"},
+			{Entity: common.Entity{CreatedAt: now.Add(time.Second), Status: constants.WorkFlowStatusError}, Name: "nodeName2"},
+			{Entity: common.Entity{CreatedAt: now.Add(2 * time.Second), Status: constants.WorkFlowStatusFinished}, Name: "fail"},
+		}, nil).Times(2)
+		mockFlowRW.EXPECT().ResumeWorkFlow(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		models.SetWorkFlowReaderWriter(mockFlowRW)
+
+		err := manager.Retry(context.TODO(), "testflowId")
+		assert.Error(t, err)
+	})
+	t.Run("no failed node", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockFlowRW := mockworkflow.NewMockReaderWriter(ctrl)
+		mockFlowRW.EXPECT().QueryDetailWorkFlow(gomock.Any(), "testflowId").Return(&wfModel.WorkFlow{
+			Entity: common.Entity{ID: "testflowId", Status: constants.WorkFlowStatusFinished},
+			Name:   "resumeFlow",
+		}, []*wfModel.WorkFlowNode{
+			{Entity: common.Entity{CreatedAt: now, Status: constants.WorkFlowStatusFinished}, Name: "nodeName1"},
+		}, nil).Times(1)
+		models.SetWorkFlowReaderWriter(mockFlowRW)
+
+		err := manager.Retry(context.TODO(), "testflowId")
+		assert.Error(t, err)
+	})
+}
+
+func TestFlowManager_Abandon(t *testing.T) {
+	manager := registerResumeFlow()
+	now := time.Now()
+
+	t.Run("normal", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockFlowRW := mockworkflow.NewMockReaderWriter(ctrl)
+		mockFlowRW.EXPECT().QueryDetailWorkFlow(gomock.Any(), "testflowId").Return(&wfModel.WorkFlow{
+			Entity: common.Entity{ID: "testflowId", Status: constants.WorkFlowStatusError},
+			Name:   "resumeFlow",
+		}, []*wfModel.WorkFlowNode{
+			{Entity: common.Entity{CreatedAt: now, Status: constants.WorkFlowStatusFinished}, Name: "nodeName1"},
+			{Entity: common.Entity{CreatedAt: now.Add(time.Second), Status: constants.WorkFlowStatusError}, Name: "nodeName2"},
+		}, nil).Times(1)
+		mockFlowRW.EXPECT().UpdateWorkFlow(gomock.Any(), "testflowId", constants.WorkFlowStatusProcessing, "").Return(nil).Times(1)
+		models.SetWorkFlowReaderWriter(mockFlowRW)
+
+		err := manager.Abandon(context.TODO(), "testflowId")
+		assert.NoError(t, err)
+	})
+	t.Run("not failed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockFlowRW := mockworkflow.NewMockReaderWriter(ctrl)
+		mockFlowRW.EXPECT().QueryDetailWorkFlow(gomock.Any(), "testflowId").Return(&wfModel.WorkFlow{
+			Entity: common.Entity{ID: "testflowId", Status: constants.WorkFlowStatusProcessing},
+			Name:   "resumeFlow",
+		}, nil, nil).Times(1)
+		models.SetWorkFlowReaderWriter(mockFlowRW)
+
+		err := manager.Abandon(context.TODO(), "testflowId")
+		assert.Error(t, err)
+	})
+	t.Run("fail node executed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockFlowRW := mockworkflow.NewMockReaderWriter(ctrl)
+		mockFlowRW.EXPECT().QueryDetailWorkFlow(gomock.Any(), "testflowId").Return(&wfModel.WorkFlow{
+			Entity: common.Entity{ID: "testflowId", Status: constants.WorkFlowStatusError},
+			Name:   "resumeFlow",
+		}, []*wfModel.WorkFlowNode{
+			{Entity: common.Entity{CreatedAt: now, Status: constants.WorkFlowStatusError}, Name: "nodeName1"},
+			{Entity: common.Entity{CreatedAt: now.Add(time.Second), Status: constants.WorkFlowStatusError}, Name: "fail"},
+		}, nil).Times(1)
+		mockFlowRW.EXPECT().UpdateWorkFlow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		models.SetWorkFlowReaderWriter(mockFlowRW)
+
+		err := manager.Abandon(context.TODO(), "testflowId")
+		assert.Error(t, err)
+	})
+}
+
+func TestFlowManager_DetailWorkFlow_resumed(t *testing.T) {
+	manager := registerResumeFlow()
+	now := time.Now()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFlowRW := mockworkflow.NewMockReaderWriter(ctrl)
+	mockFlowRW.EXPECT().QueryDetailWorkFlow(gomock.Any(), "testflowId").Return(&wfModel.WorkFlow{
+		Entity: common.Entity{ID: "testflowId", Status: constants.WorkFlowStatusProcessing},
+		Name:   "resumeFlow",
+	}, []*wfModel.WorkFlowNode{
+		{Entity: common.Entity{ID: "4", CreatedAt: now.Add(3 * time.Second), Status: constants.WorkFlowStatusProcessing}, Name: "nodeName2", Resumed: true},
+		{Entity: common.Entity{ID: "1", CreatedAt: now, Status: constants.WorkFlowStatusFinished}, Name: "nodeName1"},
+		{Entity: common.Entity{ID: "2", CreatedAt: now.Add(time.Second), Status: constants.WorkFlowStatusError}, Name: "nodeName2"},
+		{Entity: common.Entity{ID: "3", CreatedAt: now.Add(2 * time.Second), Status: constants.WorkFlowStatusFinished}, Name: "fail"},
+	}, nil).Times(1)
+	models.SetWorkFlowReaderWriter(mockFlowRW)
+
+	resp, err := manager.DetailWorkFlow(context.TODO(), message.QueryWorkFlowDetailReq{WorkFlowID: "testflowId"})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(resp.NodeInfo))
+	assert.Equal(t, "1", resp.NodeInfo[0].ID)
+	assert.Equal(t, "2", resp.NodeInfo[1].ID)
+	assert.Equal(t, "4", resp.NodeInfo[2].ID)
+	assert.True(t, resp.NodeInfo[2].Resumed)
+}